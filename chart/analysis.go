@@ -0,0 +1,301 @@
+package chart
+
+import (
+	"errors"
+
+	"github.com/yaklabco/unioffice/v2"
+	"github.com/yaklabco/unioffice/v2/drawing"
+	"github.com/yaklabco/unioffice/v2/schema/soo/dml"
+	crt "github.com/yaklabco/unioffice/v2/schema/soo/dml/chart"
+)
+
+// Trendline is a trendline fitted to the values of a series.
+type Trendline struct{ x *crt.CT_Trendline }
+
+// MakeTrendline wraps a trendline.
+func MakeTrendline(x *crt.CT_Trendline) Trendline { return Trendline{x} }
+
+// X returns the inner wrapped XML type.
+func (t Trendline) X() *crt.CT_Trendline { return t.x }
+
+// AddTrendline adds a trendline of a type to the series. Polynomial
+// trendlines default to the second order and moving averages to a period of
+// two. Pie, doughnut, radar and surface series don't support trendlines.
+func (s Series) AddTrendline(typ crt.ST_TrendlineType) (Trendline, error) {
+	if s.f.trendline == nil {
+		return Trendline{}, errors.New("series doesn't support trendlines")
+	}
+	x := crt.NewCT_Trendline()
+	x.TrendlineType.ValAttr = typ
+	t := Trendline{x}
+	switch typ {
+	case crt.ST_TrendlineTypePoly:
+		t.SetOrder(2)
+	case crt.ST_TrendlineTypeMovingAvg:
+		t.SetPeriod(2)
+	}
+	*s.f.trendline = append(*s.f.trendline, x)
+	return t, nil
+}
+
+// Trendlines returns the trendlines of the series.
+func (s Series) Trendlines() []Trendline {
+	ret := []Trendline{}
+	if s.f.trendline == nil {
+		return ret
+	}
+	for _, x := range *s.f.trendline {
+		ret = append(ret, Trendline{x})
+	}
+	return ret
+}
+
+// SetName sets the name of the trendline displayed in the legend.
+func (t Trendline) SetName(name string) { t.x.Name = unioffice.String(name) }
+
+// SetOrder sets the order of a polynomial trendline, from 2 to 6.
+func (t Trendline) SetOrder(order uint8) {
+	t.x.Order = crt.NewCT_Order()
+	t.x.Order.ValAttr = &order
+}
+
+// SetPeriod sets the number of points averaged by a moving average
+// trendline.
+func (t Trendline) SetPeriod(period uint32) {
+	t.x.Period = crt.NewCT_Period()
+	t.x.Period.ValAttr = &period
+}
+
+// SetForward extends the trendline forward by a number of periods.
+func (t Trendline) SetForward(periods float64) { t.x.Forward = &crt.CT_Double{ValAttr: periods} }
+
+// SetBackward extends the trendline backward by a number of periods.
+func (t Trendline) SetBackward(periods float64) { t.x.Backward = &crt.CT_Double{ValAttr: periods} }
+
+// SetIntercept fixes the value where the trendline crosses the vertical axis.
+func (t Trendline) SetIntercept(v float64) { t.x.Intercept = &crt.CT_Double{ValAttr: v} }
+
+// SetDisplayEquation controls whether the equation of the trendline is
+// displayed on the chart.
+func (t Trendline) SetDisplayEquation(b bool) {
+	t.x.DispEq = crt.NewCT_Boolean()
+	t.x.DispEq.ValAttr = unioffice.Bool(b)
+	t.ensureLabel()
+}
+
+// SetDisplayRSquared controls whether the R-squared value of the trendline is
+// displayed on the chart.
+func (t Trendline) SetDisplayRSquared(b bool) {
+	t.x.DispRSqr = crt.NewCT_Boolean()
+	t.x.DispRSqr.ValAttr = unioffice.Bool(b)
+	t.ensureLabel()
+}
+
+// SetLabelNumberFormat sets the number format of the equation and R-squared
+// value displayed on the chart.
+func (t Trendline) SetLabelNumberFormat(code string) {
+	t.ensureLabel()
+	t.x.TrendlineLbl.NumFmt = newNumFmt(code)
+}
+
+func (t Trendline) ensureLabel() {
+	if t.x.TrendlineLbl == nil {
+		t.x.TrendlineLbl = crt.NewCT_TrendlineLbl()
+		t.x.TrendlineLbl.NumFmt = newNumFmt("General")
+	}
+}
+
+// Properties returns the line properties of the trendline.
+func (t Trendline) Properties() drawing.ShapeProperties {
+	if t.x.SpPr == nil {
+		t.x.SpPr = dml.NewCT_ShapeProperties()
+	}
+	return drawing.MakeShapeProperties(t.x.SpPr)
+}
+
+// ErrorBars are the error bars of a series.
+type ErrorBars struct{ x *crt.CT_ErrBars }
+
+// MakeErrorBars wraps error bars.
+func MakeErrorBars(x *crt.CT_ErrBars) ErrorBars { return ErrorBars{x} }
+
+// X returns the inner wrapped XML type.
+func (e ErrorBars) X() *crt.CT_ErrBars { return e.x }
+
+// AddErrorBars adds error bars in a direction to the series, showing the
+// standard error in both directions by default. Only scatter and bubble
+// series have x error bars, and bar and line series have a single set of
+// error bars which is replaced. Pie, doughnut, radar and surface series
+// don't support error bars.
+func (s Series) AddErrorBars(dir crt.ST_ErrDir) (ErrorBars, error) {
+	x := crt.NewCT_ErrBars()
+	x.ErrBarType.ValAttr = crt.ST_ErrBarTypeBoth
+	x.ErrValType.ValAttr = crt.ST_ErrValTypeStdErr
+	x.NoEndCap = crt.NewCT_Boolean()
+	x.NoEndCap.ValAttr = unioffice.Bool(false)
+	if dir != crt.ST_ErrDirUnset {
+		x.ErrDir = crt.NewCT_ErrDir()
+		x.ErrDir.ValAttr = dir
+	}
+	switch v := s.x.(type) {
+	case *crt.CT_ScatterSer:
+		v.ErrBars = append(v.ErrBars, x)
+		return ErrorBars{x}, nil
+	case *crt.CT_BubbleSer:
+		v.ErrBars = append(v.ErrBars, x)
+		return ErrorBars{x}, nil
+	}
+	if dir == crt.ST_ErrDirX {
+		return ErrorBars{}, errors.New("series doesn't support x error bars")
+	}
+	// the direction is implied by the chart type
+	x.ErrDir = nil
+	switch v := s.x.(type) {
+	case *crt.CT_AreaSer:
+		v.ErrBars = append(v.ErrBars, x)
+	case *crt.CT_BarSer:
+		v.ErrBars = x
+	case *crt.CT_LineSer:
+		v.ErrBars = x
+	default:
+		return ErrorBars{}, errors.New("series doesn't support error bars")
+	}
+	return ErrorBars{x}, nil
+}
+
+// ErrorBars returns the error bars of the series.
+func (s Series) ErrorBars() []ErrorBars {
+	ret := []ErrorBars{}
+	for _, x := range s.errBars() {
+		ret = append(ret, ErrorBars{x})
+	}
+	return ret
+}
+
+func (s Series) errBars() []*crt.CT_ErrBars {
+	switch v := s.x.(type) {
+	case *crt.CT_AreaSer:
+		return v.ErrBars
+	case *crt.CT_ScatterSer:
+		return v.ErrBars
+	case *crt.CT_BubbleSer:
+		return v.ErrBars
+	case *crt.CT_BarSer:
+		if v.ErrBars != nil {
+			return []*crt.CT_ErrBars{v.ErrBars}
+		}
+	case *crt.CT_LineSer:
+		if v.ErrBars != nil {
+			return []*crt.CT_ErrBars{v.ErrBars}
+		}
+	}
+	return nil
+}
+
+// SetType sets whether the error bars extend above, below or on both sides of
+// the values.
+func (e ErrorBars) SetType(t crt.ST_ErrBarType) { e.x.ErrBarType.ValAttr = t }
+
+// SetValue sets the amount of error as a fixed value, a percentage of the
+// values or a number of standard deviations.
+func (e ErrorBars) SetValue(typ crt.ST_ErrValType, v float64) {
+	e.x.ErrValType.ValAttr = typ
+	e.x.Val = &crt.CT_Double{ValAttr: v}
+	e.x.Plus, e.x.Minus = nil, nil
+}
+
+// SetStandardError sets the amount of error to the standard error of the
+// values.
+func (e ErrorBars) SetStandardError() {
+	e.x.ErrValType.ValAttr = crt.ST_ErrValTypeStdErr
+	e.x.Val, e.x.Plus, e.x.Minus = nil, nil, nil
+}
+
+// SetCustomReferences sets the references of the cells containing the amount
+// of error of each value, in the plus and minus directions. An empty
+// reference leaves the direction without error.
+func (e ErrorBars) SetCustomReferences(plus, minus string) {
+	e.x.ErrValType.ValAttr = crt.ST_ErrValTypeCust
+	e.x.Val, e.x.Plus, e.x.Minus = nil, nil, nil
+	if plus != "" {
+		e.x.Plus = crt.NewCT_NumDataSource()
+		MakeNumberDataSource(e.x.Plus).SetReference(plus)
+	}
+	if minus != "" {
+		e.x.Minus = crt.NewCT_NumDataSource()
+		MakeNumberDataSource(e.x.Minus).SetReference(minus)
+	}
+}
+
+// SetNoEndCap controls whether the error bars are drawn without end caps.
+func (e ErrorBars) SetNoEndCap(b bool) { e.x.NoEndCap.ValAttr = unioffice.Bool(b) }
+
+// Properties returns the line properties of the error bars.
+func (e ErrorBars) Properties() drawing.ShapeProperties {
+	if e.x.SpPr == nil {
+		e.x.SpPr = dml.NewCT_ShapeProperties()
+	}
+	return drawing.MakeShapeProperties(e.x.SpPr)
+}
+
+// AddTrendline adds a trendline to the series, see Series.AddTrendline.
+func (c AreaChartSeries) AddTrendline(typ crt.ST_TrendlineType) Trendline {
+	t, _ := makeSeries(c._ad).AddTrendline(typ)
+	return t
+}
+
+// AddTrendline adds a trendline to the series, see Series.AddTrendline.
+func (c BarChartSeries) AddTrendline(typ crt.ST_TrendlineType) Trendline {
+	t, _ := makeSeries(c._gc).AddTrendline(typ)
+	return t
+}
+
+// AddTrendline adds a trendline to the series, see Series.AddTrendline.
+func (c LineChartSeries) AddTrendline(typ crt.ST_TrendlineType) Trendline {
+	t, _ := makeSeries(c._gega).AddTrendline(typ)
+	return t
+}
+
+// AddTrendline adds a trendline to the series, see Series.AddTrendline.
+func (c ScatterChartSeries) AddTrendline(typ crt.ST_TrendlineType) Trendline {
+	t, _ := makeSeries(c._ffce).AddTrendline(typ)
+	return t
+}
+
+// AddTrendline adds a trendline to the series, see Series.AddTrendline.
+func (c BubbleChartSeries) AddTrendline(typ crt.ST_TrendlineType) Trendline {
+	t, _ := makeSeries(c._ffb).AddTrendline(typ)
+	return t
+}
+
+// AddErrorBars adds error bars to the series, see Series.AddErrorBars.
+func (c AreaChartSeries) AddErrorBars() ErrorBars {
+	e, _ := makeSeries(c._ad).AddErrorBars(crt.ST_ErrDirY)
+	return e
+}
+
+// AddErrorBars adds error bars to the series, see Series.AddErrorBars.
+func (c BarChartSeries) AddErrorBars() ErrorBars {
+	e, _ := makeSeries(c._gc).AddErrorBars(crt.ST_ErrDirY)
+	return e
+}
+
+// AddErrorBars adds error bars to the series, see Series.AddErrorBars.
+func (c LineChartSeries) AddErrorBars() ErrorBars {
+	e, _ := makeSeries(c._gega).AddErrorBars(crt.ST_ErrDirY)
+	return e
+}
+
+// AddErrorBars adds error bars in a direction to the series, see
+// Series.AddErrorBars.
+func (c ScatterChartSeries) AddErrorBars(dir crt.ST_ErrDir) ErrorBars {
+	e, _ := makeSeries(c._ffce).AddErrorBars(dir)
+	return e
+}
+
+// AddErrorBars adds error bars in a direction to the series, see
+// Series.AddErrorBars.
+func (c BubbleChartSeries) AddErrorBars(dir crt.ST_ErrDir) ErrorBars {
+	e, _ := makeSeries(c._ffb).AddErrorBars(dir)
+	return e
+}
@@ -0,0 +1,95 @@
+package chart
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	crt "github.com/yaklabco/unioffice/v2/schema/soo/dml/chart"
+)
+
+// marshalChart returns the XML of the chart after validating it.
+func marshalChart(t *testing.T, c Chart) string {
+	t.Helper()
+	if err := c.X().Validate(); err != nil {
+		t.Errorf("invalid chart: %s", err)
+	}
+	out, err := xml.Marshal(c.X())
+	if err != nil {
+		t.Fatalf("error marshaling chart: %s", err)
+	}
+	return string(out)
+}
+
+// expectXML checks the XML contains each of the fragments in order.
+func expectXML(t *testing.T, out string, frags ...string) {
+	t.Helper()
+	rest := out
+	for _, f := range frags {
+		i := strings.Index(rest, f)
+		if i < 0 {
+			t.Errorf("expected %s in order in %s", f, out)
+			return
+		}
+		rest = rest[i+len(f):]
+	}
+}
+
+func TestTrendlineXML(t *testing.T) {
+	c := MakeChart(crt.NewChartSpace())
+	s := c.AddScatterChart().AddSeries()
+	tl := s.AddTrendline(crt.ST_TrendlineTypePoly)
+	tl.SetName("Fit")
+	tl.SetForward(1.5)
+	tl.SetIntercept(0)
+	tl.SetDisplayEquation(true)
+	tl.SetDisplayRSquared(false)
+	tl.SetLabelNumberFormat("0.00")
+	s.AddTrendline(crt.ST_TrendlineTypeMovingAvg)
+	out := marshalChart(t, c)
+	expectXML(t, out,
+		`<c:trendline><c:name>Fit</c:name>`,
+		`<c:trendlineType val="poly"></c:trendlineType><c:order val="2"></c:order>`,
+		`<c:forward val="1.5"></c:forward><c:intercept val="0"></c:intercept>`,
+		`<c:dispRSqr val="0"></c:dispRSqr><c:dispEq val="1"></c:dispEq>`,
+		`<c:trendlineLbl><c:numFmt formatCode="0.00" sourceLinked="0"></c:numFmt></c:trendlineLbl></c:trendline>`,
+		`<c:trendline><c:trendlineType val="movingAvg"></c:trendlineType><c:period val="2"></c:period>`,
+	)
+
+	if _, err := MakeSeries(crt.NewCT_PieSer()).AddTrendline(crt.ST_TrendlineTypeLinear); err == nil {
+		t.Errorf("expected error adding a trendline to a pie series")
+	}
+}
+
+func TestErrorBarsXML(t *testing.T) {
+	c := MakeChart(crt.NewChartSpace())
+	s := c.AddScatterChart().AddSeries()
+	s.AddErrorBars(crt.ST_ErrDirX).SetValue(crt.ST_ErrValTypePercentage, 5)
+	eb := s.AddErrorBars(crt.ST_ErrDirY)
+	eb.SetType(crt.ST_ErrBarTypePlus)
+	eb.SetCustomReferences("Sheet1!$C$2:$C$4", "")
+	eb.SetNoEndCap(true)
+	out := marshalChart(t, c)
+	expectXML(t, out,
+		`<c:errBars><c:errDir val="x"></c:errDir><c:errBarType val="both"></c:errBarType><c:errValType val="percentage"></c:errValType><c:noEndCap val="0"></c:noEndCap><c:val val="5"></c:val></c:errBars>`,
+		`<c:errBars><c:errDir val="y"></c:errDir><c:errBarType val="plus"></c:errBarType><c:errValType val="cust"></c:errValType><c:noEndCap val="1"></c:noEndCap><c:plus><c:numRef><c:f>Sheet1!$C$2:$C$4</c:f></c:numRef></c:plus></c:errBars>`,
+	)
+
+	// bar series have a single set of error bars with an implied direction
+	c = MakeChart(crt.NewChartSpace())
+	bs := MakeSeries(c.AddBarChart().AddSeries().X())
+	if _, err := bs.AddErrorBars(crt.ST_ErrDirX); err == nil {
+		t.Errorf("expected error adding x error bars to a bar series")
+	}
+	bs.AddErrorBars(crt.ST_ErrDirY)
+	e, _ := bs.AddErrorBars(crt.ST_ErrDirY)
+	e.SetStandardError()
+	if len(bs.ErrorBars()) != 1 {
+		t.Errorf("expected the error bars of the bar series to be replaced")
+	}
+	out = marshalChart(t, c)
+	expectXML(t, out, `<c:errBars><c:errBarType val="both"></c:errBarType><c:errValType val="stdErr"></c:errValType>`)
+	if strings.Contains(out, "<c:errDir") {
+		t.Errorf("expected no direction on bar error bars")
+	}
+}
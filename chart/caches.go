@@ -0,0 +1,304 @@
+package chart
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/yaklabco/unioffice/v2/schema/schemas.microsoft.com/office/drawing/2014/chartex"
+	crt "github.com/yaklabco/unioffice/v2/schema/soo/dml/chart"
+)
+
+// CellValue is the value of a cell referenced by the data of a chart.
+type CellValue struct {
+	// Text is the value formatted as displayed in the cell.
+	Text string
+	// Number is the numeric value of the cell, set if IsNumber is true.
+	Number   float64
+	IsNumber bool
+	// FormatCode is the number format of the cell.
+	FormatCode string
+	// Empty is true for blank cells, which are left out of the caches.
+	Empty bool
+}
+
+// CellSource resolves the cell references of the data of a chart, e.g. a
+// spreadsheet workbook.
+type CellSource interface {
+	// ChartValues returns the values of the cells of a reference such as
+	// "Sheet1!$B$2:$B$10" by row.
+	ChartValues(ref string) ([][]CellValue, error)
+}
+
+// RefreshCaches fills the caches of the cell references of the chart, i.e.
+// the series names, categories, values and custom error amounts and the
+// title, from the cells of a source, so viewers that don't evaluate the
+// references display the current data. Sources given as literal values are
+// left alone.
+func (c Chart) RefreshCaches(src CellSource) error {
+	if ch := c._egg.Chart; ch != nil && ch.Title != nil && ch.Title.Tx != nil && ch.Title.Tx.TxChoice != nil {
+		if err := refreshStrRef(ch.Title.Tx.TxChoice.StrRef, src); err != nil {
+			return err
+		}
+	}
+	for _, p := range c.Plots() {
+		for _, s := range p.Series() {
+			if err := s.refreshCaches(src); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// refreshCaches fills the caches of the cell references of the series.
+func (s Series) refreshCaches(src CellSource) error {
+	if tx := *s.f.tx; tx != nil && tx.SerTxChoice != nil {
+		if err := refreshStrRef(tx.SerTxChoice.StrRef, src); err != nil {
+			return err
+		}
+	}
+	if cat := *s.f.cat; cat != nil && cat.AxDataSourceChoice != nil {
+		ch := cat.AxDataSourceChoice
+		if err := refreshStrRef(ch.StrRef, src); err != nil {
+			return err
+		}
+		if err := refreshNumRef(ch.NumRef, src); err != nil {
+			return err
+		}
+		if err := refreshMultiLvlStrRef(ch.MultiLvlStrRef, src); err != nil {
+			return err
+		}
+	}
+	sources := []*crt.CT_NumDataSource{*s.f.val}
+	if s.f.bubbleSize != nil {
+		sources = append(sources, *s.f.bubbleSize)
+	}
+	for _, eb := range s.errBars() {
+		sources = append(sources, eb.Plus, eb.Minus)
+	}
+	for _, ds := range sources {
+		if ds != nil && ds.NumDataSourceChoice != nil {
+			if err := refreshNumRef(ds.NumDataSourceChoice.NumRef, src); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// referenceValues returns the values of the cells of a reference as a list.
+func referenceValues(ref string, src CellSource) ([]CellValue, error) {
+	rows, err := src.ChartValues(ref)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", ref, err)
+	}
+	ret := []CellValue{}
+	for _, r := range rows {
+		ret = append(ret, r...)
+	}
+	return ret, nil
+}
+
+// referenceLevels returns the values of the cells of a reference as category
+// levels, the innermost level first. The points run along the longer side of
+// the range unless byRow is set.
+func referenceLevels(ref string, byRow bool, src CellSource) ([][]CellValue, error) {
+	rows, err := src.ChartValues(ref)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", ref, err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	cols := 0
+	for _, r := range rows {
+		if len(r) > cols {
+			cols = len(r)
+		}
+	}
+	cell := func(r, c int) CellValue {
+		if c < len(rows[r]) {
+			return rows[r][c]
+		}
+		return CellValue{Empty: true}
+	}
+	ret := [][]CellValue{}
+	if byRow || len(rows) == 1 || len(rows) < cols {
+		// points run along the rows, each row is a level
+		for r := len(rows) - 1; r >= 0; r-- {
+			lvl := make([]CellValue, cols)
+			for c := range lvl {
+				lvl[c] = cell(r, c)
+			}
+			ret = append(ret, lvl)
+		}
+		return ret, nil
+	}
+	for c := cols - 1; c >= 0; c-- {
+		lvl := make([]CellValue, len(rows))
+		for r := range lvl {
+			lvl[r] = cell(r, c)
+		}
+		ret = append(ret, lvl)
+	}
+	return ret, nil
+}
+
+// formatNumber formats a cached number.
+func formatNumber(v float64) string { return strconv.FormatFloat(v, 'g', -1, 64) }
+
+func refreshStrRef(r *crt.CT_StrRef, src CellSource) error {
+	if r == nil || r.F == "" {
+		return nil
+	}
+	vals, err := referenceValues(r.F, src)
+	if err != nil {
+		return err
+	}
+	r.StrCache = strData(vals)
+	return nil
+}
+
+func strData(vals []CellValue) *crt.CT_StrData {
+	d := crt.NewCT_StrData()
+	d.PtCount = crt.NewCT_UnsignedInt()
+	d.PtCount.ValAttr = uint32(len(vals))
+	for i, v := range vals {
+		if !v.Empty {
+			d.Pt = append(d.Pt, &crt.CT_StrVal{IdxAttr: uint32(i), V: v.Text})
+		}
+	}
+	return d
+}
+
+func refreshNumRef(r *crt.CT_NumRef, src CellSource) error {
+	if r == nil || r.F == "" {
+		return nil
+	}
+	vals, err := referenceValues(r.F, src)
+	if err != nil {
+		return err
+	}
+	d := crt.NewCT_NumData()
+	d.PtCount = crt.NewCT_UnsignedInt()
+	d.PtCount.ValAttr = uint32(len(vals))
+	for i, v := range vals {
+		if !v.IsNumber {
+			continue
+		}
+		pt := &crt.CT_NumVal{IdxAttr: uint32(i), V: formatNumber(v.Number)}
+		// the format of the first point is the one of the cache, the others
+		// are only written when different
+		if d.FormatCode == nil {
+			fc := v.FormatCode
+			if fc == "" {
+				fc = "General"
+			}
+			d.FormatCode = &fc
+		} else if v.FormatCode != "" && v.FormatCode != *d.FormatCode {
+			fc := v.FormatCode
+			pt.FormatCodeAttr = &fc
+		}
+		d.Pt = append(d.Pt, pt)
+	}
+	if d.FormatCode == nil {
+		fc := "General"
+		d.FormatCode = &fc
+	}
+	r.NumCache = d
+	return nil
+}
+
+func refreshMultiLvlStrRef(r *crt.CT_MultiLvlStrRef, src CellSource) error {
+	if r == nil || r.F == "" {
+		return nil
+	}
+	levels, err := referenceLevels(r.F, false, src)
+	if err != nil {
+		return err
+	}
+	d := crt.NewCT_MultiLvlStrData()
+	d.PtCount = crt.NewCT_UnsignedInt()
+	for _, lvl := range levels {
+		d.PtCount.ValAttr = uint32(len(lvl))
+		l := crt.NewCT_Lvl()
+		l.Pt = strData(lvl).Pt
+		d.Lvl = append(d.Lvl, l)
+	}
+	r.MultiLvlStrCache = d
+	return nil
+}
+
+// RefreshCaches fills the cached levels of the data dimensions and series
+// names of the chart given by cell references from the cells of a source, so
+// viewers that don't evaluate the references display the current data.
+func (c ChartEx) RefreshCaches(src CellSource) error {
+	if c.x.ChartData != nil {
+		for _, d := range c.x.ChartData.Data {
+			for _, dim := range d.StrDim {
+				if dim.F == nil || dim.F.Content == "" {
+					continue
+				}
+				levels, err := referenceLevels(dim.F.Content, dim.F.DirAttr == chartex.ST_FormulaDirectionRow, src)
+				if err != nil {
+					return err
+				}
+				dim.Lvl = nil
+				for _, lvl := range levels {
+					sl := &chartex.CT_StringLevel{PtCountAttr: uint32(len(lvl))}
+					for i, v := range lvl {
+						if !v.Empty {
+							sl.Pt = append(sl.Pt, &chartex.CT_StringValue{IdxAttr: uint32(i), Content: v.Text})
+						}
+					}
+					dim.Lvl = append(dim.Lvl, sl)
+				}
+			}
+			for _, dim := range d.NumDim {
+				if dim.F == nil || dim.F.Content == "" {
+					continue
+				}
+				vals, err := referenceValues(dim.F.Content, src)
+				if err != nil {
+					return err
+				}
+				nl := &chartex.CT_NumericLevel{PtCountAttr: uint32(len(vals)), FormatCodeAttr: "General"}
+				first := true
+				for i, v := range vals {
+					if !v.IsNumber {
+						continue
+					}
+					if first && v.FormatCode != "" {
+						nl.FormatCodeAttr = v.FormatCode
+					}
+					first = false
+					nl.Pt = append(nl.Pt, &chartex.CT_NumericValue{IdxAttr: uint32(i), Content: v.Number})
+				}
+				dim.Lvl = []*chartex.CT_NumericLevel{nl}
+			}
+		}
+	}
+	if c.x.Chart == nil || c.x.Chart.PlotArea == nil || c.x.Chart.PlotArea.PlotAreaRegion == nil {
+		return nil
+	}
+	for _, s := range c.x.Chart.PlotArea.PlotAreaRegion.Series {
+		if s.Tx == nil || s.Tx.TxData == nil || s.Tx.TxData.F == nil || s.Tx.TxData.F.Content == "" {
+			continue
+		}
+		vals, err := referenceValues(s.Tx.TxData.F.Content, src)
+		if err != nil {
+			return err
+		}
+		text := ""
+		for _, v := range vals {
+			if !v.Empty {
+				if text != "" {
+					text += " "
+				}
+				text += v.Text
+			}
+		}
+		s.Tx.TxData.V = &text
+	}
+	return nil
+}
@@ -0,0 +1,547 @@
+package chart
+
+import (
+	"strconv"
+
+	"github.com/yaklabco/unioffice/v2"
+	"github.com/yaklabco/unioffice/v2/drawing"
+	"github.com/yaklabco/unioffice/v2/schema/schemas.microsoft.com/office/drawing/2014/chartex"
+	"github.com/yaklabco/unioffice/v2/schema/soo/dml"
+)
+
+// ChartEx is a chart of one of the types introduced by Office 2016, e.g. a
+// waterfall or a treemap. Unlike a Chart it's stored in a chartex part.
+type ChartEx struct {
+	x *chartex.ChartSpace
+}
+
+// MakeChartEx constructs a chartex chart from its chart space.
+func MakeChartEx(x *chartex.ChartSpace) ChartEx {
+	if x.ChartData == nil {
+		x.ChartData = &chartex.CT_ChartData{}
+	}
+	if x.Chart == nil {
+		x.Chart = &chartex.CT_Chart{}
+	}
+	if x.Chart.PlotArea == nil {
+		x.Chart.PlotArea = &chartex.CT_PlotArea{}
+	}
+	if x.Chart.PlotArea.PlotAreaRegion == nil {
+		x.Chart.PlotArea.PlotAreaRegion = &chartex.CT_PlotAreaRegion{}
+	}
+	return ChartEx{x}
+}
+
+// X returns the inner wrapped XML type.
+func (c ChartEx) X() *chartex.ChartSpace { return c.x }
+
+// Properties returns the shape properties of the chart area.
+func (c ChartEx) Properties() drawing.ShapeProperties {
+	if c.x.SpPr == nil {
+		c.x.SpPr = dml.NewCT_ShapeProperties()
+	}
+	return drawing.MakeShapeProperties(c.x.SpPr)
+}
+
+// SetTitle sets the title of the chart, displayed centered above the plot
+// area.
+func (c ChartEx) SetTitle(s string) {
+	c.x.Chart.Title = &chartex.CT_Title{
+		PosAttr:     chartex.ST_SidePosT,
+		AlignAttr:   chartex.ST_PosAlignCtr,
+		OverlayAttr: unioffice.Bool(false),
+		Tx:          &chartex.CT_Text{TxData: &chartex.CT_TextData{V: &s}},
+	}
+}
+
+// RemoveTitle removes the title of the chart.
+func (c ChartEx) RemoveTitle() { c.x.Chart.Title = nil }
+
+// SetLegend displays the legend of the chart on a side of the plot area.
+func (c ChartEx) SetLegend(pos chartex.ST_SidePos) {
+	c.x.Chart.Legend = &chartex.CT_Legend{
+		PosAttr:     pos,
+		AlignAttr:   chartex.ST_PosAlignCtr,
+		OverlayAttr: unioffice.Bool(false),
+	}
+}
+
+// RemoveLegend removes the legend of the chart.
+func (c ChartEx) RemoveLegend() { c.x.Chart.Legend = nil }
+
+// addSeries adds a series of a layout with its own data.
+func (c ChartEx) addSeries(layout chartex.ST_SeriesLayout) ChartExSeries {
+	id := uint32(0)
+	for _, d := range c.x.ChartData.Data {
+		if d.IdAttr >= id {
+			id = d.IdAttr + 1
+		}
+	}
+	c.x.ChartData.Data = append(c.x.ChartData.Data, &chartex.CT_Data{IdAttr: id})
+	region := c.x.Chart.PlotArea.PlotAreaRegion
+	ser := &chartex.CT_Series{
+		LayoutIdAttr: layout,
+		DataId:       &chartex.CT_UnsignedIntValue{ValAttr: id},
+	}
+	if len(region.Series) > 0 {
+		ser.FormatIdxAttr = unioffice.Uint32(uint32(len(region.Series)))
+	}
+	region.Series = append(region.Series, ser)
+	return ChartExSeries{c.x, ser}
+}
+
+// axis returns the axis with an ID, adding it if needed.
+func (c ChartEx) axis(id uint32) *chartex.CT_Axis {
+	for _, ax := range c.x.Chart.PlotArea.Axis {
+		if ax.IdAttr == id {
+			return ax
+		}
+	}
+	ax := &chartex.CT_Axis{IdAttr: id, TickLabels: &chartex.CT_TickLabels{}}
+	c.x.Chart.PlotArea.Axis = append(c.x.Chart.PlotArea.Axis, ax)
+	return ax
+}
+
+// addCategoryAxes adds the category and value axes of the column like charts.
+func (c ChartEx) addCategoryAxes(gapWidth string) {
+	if cat := c.axis(0); cat.CatScaling == nil {
+		cat.CatScaling = &chartex.CT_CategoryAxisScaling{GapWidthAttr: gapWidth}
+	}
+	if val := c.axis(1); val.ValScaling == nil {
+		val.ValScaling = &chartex.CT_ValueAxisScaling{}
+		val.MajorGridlines = &chartex.CT_Gridlines{}
+	}
+}
+
+// AddWaterfallSeries adds a waterfall series, each value is drawn as a step
+// up or down from the running total unless it's marked as a subtotal.
+func (c ChartEx) AddWaterfallSeries() WaterfallSeries {
+	s := c.addSeries(chartex.ST_SeriesLayoutWaterfall)
+	s.setDataLabels(chartex.ST_DataLabelPosOutEnd, false, true)
+	s.layout().Subtotals = &chartex.CT_Subtotals{}
+	c.addCategoryAxes("0.5")
+	return WaterfallSeries{s}
+}
+
+// AddFunnelSeries adds a funnel series, the values are drawn as centered
+// bars of decreasing width.
+func (c ChartEx) AddFunnelSeries() FunnelSeries {
+	s := c.addSeries(chartex.ST_SeriesLayoutFunnel)
+	s.setDataLabels(chartex.ST_DataLabelPosInEnd, false, true)
+	if ax := c.axis(0); ax.CatScaling == nil {
+		ax.CatScaling = &chartex.CT_CategoryAxisScaling{GapWidthAttr: "0.06"}
+	}
+	return FunnelSeries{s}
+}
+
+// AddTreemapSeries adds a treemap series, the values of hierarchical
+// categories are drawn as nested rectangles.
+func (c ChartEx) AddTreemapSeries() TreemapSeries {
+	s := c.addSeries(chartex.ST_SeriesLayoutTreemap)
+	s.setDataLabels(chartex.ST_DataLabelPosInEnd, true, false)
+	s.layout().ParentLabelLayout = &chartex.CT_ParentLabelLayout{ValAttr: chartex.ST_ParentLabelLayoutOverlapping}
+	return TreemapSeries{s}
+}
+
+// AddSunburstSeries adds a sunburst series, the values of hierarchical
+// categories are drawn as concentric rings.
+func (c ChartEx) AddSunburstSeries() SunburstSeries {
+	s := c.addSeries(chartex.ST_SeriesLayoutSunburst)
+	s.setDataLabels(chartex.ST_DataLabelPosCtr, true, false)
+	return SunburstSeries{s}
+}
+
+// AddHistogramSeries adds a histogram series, the values are counted in bins
+// that are computed automatically by default.
+func (c ChartEx) AddHistogramSeries() HistogramSeries {
+	s := c.addSeries(chartex.ST_SeriesLayoutClusteredColumn)
+	s.layout().Binning = &chartex.CT_Binning{IntervalClosedAttr: chartex.ST_IntervalClosedSideR}
+	c.addCategoryAxes("0")
+	return HistogramSeries{s}
+}
+
+// AddParetoSeries adds a histogram series along with the line of its
+// cumulative percentage, plotted on a secondary axis.
+func (c ChartEx) AddParetoSeries() HistogramSeries {
+	h := c.AddHistogramSeries()
+	h.x.AxisId = []*chartex.CT_UnsignedIntValue{{ValAttr: 1}}
+	owner := uint32(len(c.x.Chart.PlotArea.PlotAreaRegion.Series) - 1)
+	region := c.x.Chart.PlotArea.PlotAreaRegion
+	region.Series = append(region.Series, &chartex.CT_Series{
+		LayoutIdAttr:  chartex.ST_SeriesLayoutParetoLine,
+		OwnerIdxAttr:  unioffice.Uint32(owner),
+		FormatIdxAttr: unioffice.Uint32(uint32(len(region.Series))),
+		AxisId:        []*chartex.CT_UnsignedIntValue{{ValAttr: 2}},
+	})
+	if ax := c.axis(2); ax.ValScaling == nil {
+		ax.ValScaling = &chartex.CT_ValueAxisScaling{MaxAttr: "1", MinAttr: "0"}
+		ax.Units = &chartex.CT_AxisUnits{UnitAttr: "percentage"}
+	}
+	return h
+}
+
+// AddBoxWhiskerSeries adds a box and whisker series, the distribution of the
+// values of each category is drawn as a box between the quartiles.
+func (c ChartEx) AddBoxWhiskerSeries() BoxWhiskerSeries {
+	s := c.addSeries(chartex.ST_SeriesLayoutBoxWhisker)
+	lp := s.layout()
+	lp.Visibility = &chartex.CT_SeriesElementVisibilities{
+		MeanLineAttr:    unioffice.Bool(false),
+		MeanMarkerAttr:  unioffice.Bool(true),
+		NonoutliersAttr: unioffice.Bool(false),
+		OutliersAttr:    unioffice.Bool(true),
+	}
+	lp.Statistics = &chartex.CT_Statistics{QuartileMethodAttr: chartex.ST_QuartileMethodExclusive}
+	c.addCategoryAxes("1")
+	return BoxWhiskerSeries{s}
+}
+
+// AddRegionMapSeries adds a region map series, the values of countries,
+// states or other regions named by the categories are drawn as colors on a
+// map. The applications displaying the chart look up the geography data of
+// the regions online.
+func (c ChartEx) AddRegionMapSeries() RegionMapSeries {
+	s := c.addSeries(chartex.ST_SeriesLayoutRegionMap)
+	lp := s.layout()
+	lp.RegionLabelLayout = &chartex.CT_RegionLabelLayout{ValAttr: chartex.ST_RegionLabelLayoutBestFitOnly}
+	lp.Geography = &chartex.CT_Geography{CultureLanguageAttr: "en-US", CultureRegionAttr: "US", AttributionAttr: "Powered by Bing"}
+	return RegionMapSeries{s}
+}
+
+// ChartExSeries is a series of a chartex chart.
+type ChartExSeries struct {
+	cs *chartex.ChartSpace
+	x  *chartex.CT_Series
+}
+
+// X returns the inner wrapped XML type.
+func (s ChartExSeries) X() *chartex.CT_Series { return s.x }
+
+// SetText sets the name of the series.
+func (s ChartExSeries) SetText(name string) {
+	s.x.Tx = &chartex.CT_Text{TxData: &chartex.CT_TextData{V: &name}}
+}
+
+// SetTextReference sets the cell holding the name of the series, e.g.
+// Sheet1!$B$1, keeping the name set by SetText as its cached value.
+func (s ChartExSeries) SetTextReference(ref string) {
+	if s.x.Tx == nil || s.x.Tx.TxData == nil {
+		s.x.Tx = &chartex.CT_Text{TxData: &chartex.CT_TextData{}}
+	}
+	s.x.Tx.TxData.F = &chartex.CT_Formula{Content: ref}
+}
+
+// Properties returns the shape properties of the series.
+func (s ChartExSeries) Properties() drawing.ShapeProperties {
+	if s.x.SpPr == nil {
+		s.x.SpPr = dml.NewCT_ShapeProperties()
+	}
+	return drawing.MakeShapeProperties(s.x.SpPr)
+}
+
+// SetShowValues displays or hides the values as data labels.
+func (s ChartExSeries) SetShowValues(b bool) {
+	if s.x.DataLabels == nil {
+		s.x.DataLabels = &chartex.CT_DataLabels{}
+	}
+	if s.x.DataLabels.Visibility == nil {
+		s.x.DataLabels.Visibility = &chartex.CT_DataLabelVisibilities{
+			SeriesNameAttr:   unioffice.Bool(false),
+			CategoryNameAttr: unioffice.Bool(false),
+		}
+	}
+	s.x.DataLabels.Visibility.ValueAttr = unioffice.Bool(b)
+}
+
+// SetShowCategoryNames displays or hides the category names as data labels.
+func (s ChartExSeries) SetShowCategoryNames(b bool) {
+	if s.x.DataLabels == nil {
+		s.x.DataLabels = &chartex.CT_DataLabels{}
+	}
+	if s.x.DataLabels.Visibility == nil {
+		s.x.DataLabels.Visibility = &chartex.CT_DataLabelVisibilities{
+			SeriesNameAttr: unioffice.Bool(false),
+			ValueAttr:      unioffice.Bool(false),
+		}
+	}
+	s.x.DataLabels.Visibility.CategoryNameAttr = unioffice.Bool(b)
+}
+
+func (s ChartExSeries) setDataLabels(pos chartex.ST_DataLabelPos, category, value bool) {
+	s.x.DataLabels = &chartex.CT_DataLabels{
+		PosAttr: pos,
+		Visibility: &chartex.CT_DataLabelVisibilities{
+			SeriesNameAttr:   unioffice.Bool(false),
+			CategoryNameAttr: unioffice.Bool(category),
+			ValueAttr:        unioffice.Bool(value),
+		},
+	}
+}
+
+func (s ChartExSeries) layout() *chartex.CT_SeriesLayoutProperties {
+	if s.x.LayoutPr == nil {
+		s.x.LayoutPr = &chartex.CT_SeriesLayoutProperties{}
+	}
+	return s.x.LayoutPr
+}
+
+// data returns the chart data plotted by the series.
+func (s ChartExSeries) data() *chartex.CT_Data {
+	if s.x.DataId == nil {
+		s.x.DataId = &chartex.CT_UnsignedIntValue{}
+	}
+	for _, d := range s.cs.ChartData.Data {
+		if d.IdAttr == s.x.DataId.ValAttr {
+			return d
+		}
+	}
+	d := &chartex.CT_Data{IdAttr: s.x.DataId.ValAttr}
+	s.cs.ChartData.Data = append(s.cs.ChartData.Data, d)
+	return d
+}
+
+// Categories returns the categories of the series.
+func (s ChartExSeries) Categories() ChartExCategories {
+	d := s.data()
+	for _, dim := range d.StrDim {
+		if dim.TypeAttr == chartex.ST_StringDimensionTypeCat {
+			return ChartExCategories{dim}
+		}
+	}
+	dim := &chartex.CT_StringDimension{TypeAttr: chartex.ST_StringDimensionTypeCat}
+	d.StrDim = append(d.StrDim, dim)
+	return ChartExCategories{dim}
+}
+
+// Values returns the values of the series, the values of a region map are
+// its color values.
+func (s ChartExSeries) Values() ChartExValues {
+	typ := chartex.ST_NumericDimensionTypeVal
+	if s.x.LayoutIdAttr == chartex.ST_SeriesLayoutRegionMap {
+		typ = chartex.ST_NumericDimensionTypeColorVal
+	}
+	d := s.data()
+	for _, dim := range d.NumDim {
+		if dim.TypeAttr == typ {
+			return ChartExValues{dim}
+		}
+	}
+	dim := &chartex.CT_NumericDimension{TypeAttr: typ}
+	d.NumDim = append(d.NumDim, dim)
+	return ChartExValues{dim}
+}
+
+// ChartExCategories are the categories of a chartex series.
+type ChartExCategories struct {
+	x *chartex.CT_StringDimension
+}
+
+// X returns the inner wrapped XML type.
+func (c ChartExCategories) X() *chartex.CT_StringDimension { return c.x }
+
+// SetReference sets the cells holding the categories, e.g.
+// Sheet1!$A$2:$A$6. Hierarchical categories reference a column per level.
+func (c ChartExCategories) SetReference(ref string) {
+	c.x.F = &chartex.CT_Formula{Content: ref}
+}
+
+// SetValues sets the cached categories.
+func (c ChartExCategories) SetValues(v []string) { c.SetLevels(v) }
+
+// SetLevels sets the cached hierarchical categories, ordered as the columns
+// of the referenced cells from the outermost level to the leaves.
+func (c ChartExCategories) SetLevels(levels ...[]string) {
+	c.x.Lvl = nil
+	for i := len(levels) - 1; i >= 0; i-- {
+		lvl := &chartex.CT_StringLevel{PtCountAttr: uint32(len(levels[i]))}
+		for idx, v := range levels[i] {
+			if v == "" {
+				continue
+			}
+			lvl.Pt = append(lvl.Pt, &chartex.CT_StringValue{IdxAttr: uint32(idx), Content: v})
+		}
+		c.x.Lvl = append(c.x.Lvl, lvl)
+	}
+}
+
+// ChartExValues are the values of a chartex series.
+type ChartExValues struct {
+	x *chartex.CT_NumericDimension
+}
+
+// X returns the inner wrapped XML type.
+func (v ChartExValues) X() *chartex.CT_NumericDimension { return v.x }
+
+// SetReference sets the cells holding the values, e.g. Sheet1!$B$2:$B$6.
+func (v ChartExValues) SetReference(ref string) {
+	v.x.F = &chartex.CT_Formula{Content: ref}
+}
+
+// SetValues sets the cached values.
+func (v ChartExValues) SetValues(values []float64) {
+	format := "General"
+	if len(v.x.Lvl) > 0 && v.x.Lvl[0].FormatCodeAttr != "" {
+		format = v.x.Lvl[0].FormatCodeAttr
+	}
+	lvl := &chartex.CT_NumericLevel{PtCountAttr: uint32(len(values)), FormatCodeAttr: format}
+	for idx, f := range values {
+		lvl.Pt = append(lvl.Pt, &chartex.CT_NumericValue{IdxAttr: uint32(idx), Content: f})
+	}
+	v.x.Lvl = []*chartex.CT_NumericLevel{lvl}
+}
+
+// SetFormatCode sets the number format of the cached values.
+func (v ChartExValues) SetFormatCode(f string) {
+	if len(v.x.Lvl) == 0 {
+		v.x.Lvl = []*chartex.CT_NumericLevel{{}}
+	}
+	for _, lvl := range v.x.Lvl {
+		lvl.FormatCodeAttr = f
+	}
+}
+
+// WaterfallSeries is a series of a waterfall chart.
+type WaterfallSeries struct{ ChartExSeries }
+
+// SetSubtotals marks the points at the indexes as totals, they're drawn from
+// the axis instead of from the running total.
+func (s WaterfallSeries) SetSubtotals(idx ...uint32) {
+	st := &chartex.CT_Subtotals{}
+	for _, i := range idx {
+		st.Idx = append(st.Idx, &chartex.CT_UnsignedIntValue{ValAttr: i})
+	}
+	s.layout().Subtotals = st
+}
+
+// SetShowConnectorLines displays or hides the lines connecting the steps.
+func (s WaterfallSeries) SetShowConnectorLines(b bool) {
+	lp := s.layout()
+	if lp.Visibility == nil {
+		lp.Visibility = &chartex.CT_SeriesElementVisibilities{}
+	}
+	lp.Visibility.ConnectorLinesAttr = unioffice.Bool(b)
+}
+
+// FunnelSeries is a series of a funnel chart.
+type FunnelSeries struct{ ChartExSeries }
+
+// TreemapSeries is a series of a treemap chart.
+type TreemapSeries struct{ ChartExSeries }
+
+// SetParentLabelLayout sets how the labels of the parent categories are
+// displayed.
+func (s TreemapSeries) SetParentLabelLayout(l chartex.ST_ParentLabelLayout) {
+	s.layout().ParentLabelLayout = &chartex.CT_ParentLabelLayout{ValAttr: l}
+}
+
+// SunburstSeries is a series of a sunburst chart.
+type SunburstSeries struct{ ChartExSeries }
+
+// HistogramSeries is a series of a histogram or pareto chart.
+type HistogramSeries struct{ ChartExSeries }
+
+func (s HistogramSeries) binning() *chartex.CT_Binning {
+	lp := s.layout()
+	lp.Aggregation = nil
+	if lp.Binning == nil {
+		lp.Binning = &chartex.CT_Binning{IntervalClosedAttr: chartex.ST_IntervalClosedSideR}
+	}
+	return lp.Binning
+}
+
+// SetBinCount divides the values in a number of bins of the same width.
+func (s HistogramSeries) SetBinCount(n uint32) {
+	b := s.binning()
+	b.BinSize = nil
+	b.BinCount = &chartex.CT_UnsignedIntValue{ValAttr: n}
+}
+
+// SetBinSize divides the values in bins of a width.
+func (s HistogramSeries) SetBinSize(w float64) {
+	b := s.binning()
+	b.BinCount = nil
+	b.BinSize = &chartex.CT_DoubleValue{ValAttr: w}
+}
+
+// SetAutomaticBins lets the application choose the bins.
+func (s HistogramSeries) SetAutomaticBins() {
+	b := s.binning()
+	b.BinCount = nil
+	b.BinSize = nil
+}
+
+// SetOverflowBin counts the values above v in a single bin.
+func (s HistogramSeries) SetOverflowBin(v float64) {
+	s.binning().OverflowAttr = strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// SetUnderflowBin counts the values below or equal to v in a single bin.
+func (s HistogramSeries) SetUnderflowBin(v float64) {
+	s.binning().UnderflowAttr = strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// SetIntervalClosed sets the side on which the bins include their bound.
+func (s HistogramSeries) SetIntervalClosed(side chartex.ST_IntervalClosedSide) {
+	s.binning().IntervalClosedAttr = side
+}
+
+// SetAggregateCategories draws a column per distinct category with the sum
+// of its values instead of binning the values.
+func (s HistogramSeries) SetAggregateCategories() {
+	lp := s.layout()
+	lp.Binning = nil
+	lp.Aggregation = &chartex.CT_Aggregation{}
+}
+
+// BoxWhiskerSeries is a series of a box and whisker chart.
+type BoxWhiskerSeries struct{ ChartExSeries }
+
+func (s BoxWhiskerSeries) visibility() *chartex.CT_SeriesElementVisibilities {
+	lp := s.layout()
+	if lp.Visibility == nil {
+		lp.Visibility = &chartex.CT_SeriesElementVisibilities{}
+	}
+	return lp.Visibility
+}
+
+// SetQuartileMethod sets whether the median is included when computing the
+// quartiles.
+func (s BoxWhiskerSeries) SetQuartileMethod(m chartex.ST_QuartileMethod) {
+	s.layout().Statistics = &chartex.CT_Statistics{QuartileMethodAttr: m}
+}
+
+// SetShowMeanLine displays or hides the line connecting the means of the
+// categories.
+func (s BoxWhiskerSeries) SetShowMeanLine(b bool) { s.visibility().MeanLineAttr = unioffice.Bool(b) }
+
+// SetShowMeanMarker displays or hides the marker of the mean.
+func (s BoxWhiskerSeries) SetShowMeanMarker(b bool) {
+	s.visibility().MeanMarkerAttr = unioffice.Bool(b)
+}
+
+// SetShowInnerPoints displays or hides the points between the whiskers.
+func (s BoxWhiskerSeries) SetShowInnerPoints(b bool) {
+	s.visibility().NonoutliersAttr = unioffice.Bool(b)
+}
+
+// SetShowOutliers displays or hides the points beyond the whiskers.
+func (s BoxWhiskerSeries) SetShowOutliers(b bool) { s.visibility().OutliersAttr = unioffice.Bool(b) }
+
+// RegionMapSeries is a series of a region map chart.
+type RegionMapSeries struct{ ChartExSeries }
+
+// SetRegionLabelLayout sets which regions are labeled.
+func (s RegionMapSeries) SetRegionLabelLayout(l chartex.ST_RegionLabelLayout) {
+	s.layout().RegionLabelLayout = &chartex.CT_RegionLabelLayout{ValAttr: l}
+}
+
+// SetCulture sets the language and region used to look up the regions, e.g.
+// en-US and US.
+func (s RegionMapSeries) SetCulture(language, region string) {
+	lp := s.layout()
+	if lp.Geography == nil {
+		lp.Geography = &chartex.CT_Geography{AttributionAttr: "Powered by Bing"}
+	}
+	lp.Geography.CultureLanguageAttr = language
+	lp.Geography.CultureRegionAttr = region
+}
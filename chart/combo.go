@@ -0,0 +1,97 @@
+package chart
+
+import (
+	"github.com/yaklabco/unioffice/v2"
+	"github.com/yaklabco/unioffice/v2/drawing"
+	"github.com/yaklabco/unioffice/v2/schema/soo/dml"
+	crt "github.com/yaklabco/unioffice/v2/schema/soo/dml/chart"
+)
+
+// DataTable is the table of the values of the series displayed below the
+// plot area of a chart.
+type DataTable struct{ x *crt.CT_DTable }
+
+// MakeDataTable wraps a data table.
+func MakeDataTable(x *crt.CT_DTable) DataTable { return DataTable{x} }
+
+// X returns the inner wrapped XML type.
+func (d DataTable) X() *crt.CT_DTable { return d.x }
+
+// AddDataTable adds a data table with borders and legend keys to the chart,
+// replacing the existing one.
+func (c Chart) AddDataTable() DataTable {
+	d := DataTable{crt.NewCT_DTable()}
+	d.SetShowHorizontalBorder(true)
+	d.SetShowVerticalBorder(true)
+	d.SetShowOutline(true)
+	d.SetShowLegendKeys(true)
+	c._egg.Chart.PlotArea.DTable = d.x
+	return d
+}
+
+// DataTable returns the data table of the chart if it has one.
+func (c Chart) DataTable() (DataTable, bool) {
+	if c._egg.Chart == nil || c._egg.Chart.PlotArea == nil || c._egg.Chart.PlotArea.DTable == nil {
+		return DataTable{}, false
+	}
+	return DataTable{c._egg.Chart.PlotArea.DTable}, true
+}
+
+// RemoveDataTable removes the data table of the chart.
+func (c Chart) RemoveDataTable() {
+	if c._egg.Chart == nil || c._egg.Chart.PlotArea == nil {
+		return
+	}
+	c._egg.Chart.PlotArea.DTable = nil
+}
+
+// SetShowHorizontalBorder controls whether the rows of the table are
+// separated by lines.
+func (d DataTable) SetShowHorizontalBorder(b bool) { d.x.ShowHorzBorder = newBoolean(b) }
+
+// SetShowVerticalBorder controls whether the columns of the table are
+// separated by lines.
+func (d DataTable) SetShowVerticalBorder(b bool) { d.x.ShowVertBorder = newBoolean(b) }
+
+// SetShowOutline controls whether the table is outlined.
+func (d DataTable) SetShowOutline(b bool) { d.x.ShowOutline = newBoolean(b) }
+
+// SetShowLegendKeys controls whether the legend keys of the series are
+// displayed next to their names.
+func (d DataTable) SetShowLegendKeys(b bool) { d.x.ShowKeys = newBoolean(b) }
+
+// Properties returns the shape properties of the table, e.g. the color of
+// its borders.
+func (d DataTable) Properties() drawing.ShapeProperties {
+	if d.x.SpPr == nil {
+		d.x.SpPr = dml.NewCT_ShapeProperties()
+	}
+	return drawing.MakeShapeProperties(d.x.SpPr)
+}
+
+func newBoolean(b bool) *crt.CT_Boolean {
+	x := crt.NewCT_Boolean()
+	x.ValAttr = unioffice.Bool(b)
+	return x
+}
+
+// AddSecondaryAxes adds the axes of a secondary plot to the chart, a hidden
+// category axis and a value axis on the right crossing it at its maximum.
+// Adding both axes to a plot, e.g. the line plot of a bar and line combo
+// chart, plots its series against the secondary value axis:
+//
+//	cat, val := c.AddSecondaryAxes()
+//	lc := c.AddLineChart()
+//	lc.AddAxis(cat)
+//	lc.AddAxis(val)
+func (c Chart) AddSecondaryAxes() (CategoryAxis, ValueAxis) {
+	cat, val := c.AddCategoryAxis(), c.AddValueAxis()
+	cat._bef.Delete.ValAttr = unioffice.Bool(true)
+	cat._bef.MajorGridlines = nil
+	cat.SetCrosses(val)
+	val.X().MajorGridlines = nil
+	val.SetPosition(crt.ST_AxPosR)
+	val.SetCrosses(cat)
+	val.X().AxSharedChoice.Crosses.ValAttr = crt.ST_CrossesMax
+	return cat, val
+}
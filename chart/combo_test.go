@@ -0,0 +1,59 @@
+package chart
+
+import (
+	"fmt"
+	"testing"
+
+	crt "github.com/yaklabco/unioffice/v2/schema/soo/dml/chart"
+)
+
+func TestDataTableXML(t *testing.T) {
+	c := MakeChart(crt.NewChartSpace())
+	c.AddBarChart()
+	c.AddDataTable()
+	d := c.AddDataTable()
+	d.SetShowOutline(false)
+	out := marshalChart(t, c)
+	expectXML(t, out, `<c:dTable><c:showHorzBorder val="1"></c:showHorzBorder><c:showVertBorder val="1"></c:showVertBorder><c:showOutline val="0"></c:showOutline><c:showKeys val="1"></c:showKeys></c:dTable>`)
+	if got, ok := c.DataTable(); !ok || got.X() != d.X() {
+		t.Errorf("expected the data table to be replaced")
+	}
+	c.RemoveDataTable()
+	if _, ok := c.DataTable(); ok {
+		t.Errorf("expected the data table to be removed")
+	}
+
+	// charts without a plot area have no table to remove
+	c = MakeChart(crt.NewChartSpace())
+	c.X().Chart.PlotArea = nil
+	c.RemoveDataTable()
+	c.X().Chart = nil
+	c.RemoveDataTable()
+}
+
+func TestSecondaryAxesXML(t *testing.T) {
+	c := MakeChart(crt.NewChartSpace())
+	bc := c.AddBarChart()
+	ca, va := c.AddCategoryAxis(), c.AddValueAxis()
+	bc.AddAxis(ca)
+	bc.AddAxis(va)
+	sc, sv := c.AddSecondaryAxes()
+	lc := c.AddLineChart()
+	lc.AddAxis(sc)
+	lc.AddAxis(sv)
+	cat, val := sc.AxisID(), sv.AxisID()
+	out := marshalChart(t, c)
+	expectXML(t, out,
+		`<c:lineChart>`,
+		fmt.Sprintf(`<c:axId val="%d"></c:axId><c:axId val="%d"></c:axId></c:lineChart>`, cat, val),
+		fmt.Sprintf(`<c:catAx><c:axId val="%d"></c:axId>`, cat),
+		`<c:delete val="1"></c:delete>`,
+		fmt.Sprintf(`<c:crossAx val="%d"></c:crossAx>`, val),
+		fmt.Sprintf(`</c:catAx><c:valAx><c:axId val="%d"></c:axId>`, val),
+		`<c:axPos val="r"></c:axPos><c:majorTickMark`,
+		fmt.Sprintf(`<c:crossAx val="%d"></c:crossAx><c:crosses val="max"></c:crosses>`, cat),
+	)
+	if plots := c.Plots(); len(plots) != 2 || plots[1].Axes()[1].AxisID() != sv.AxisID() {
+		t.Errorf("expected the line plot against the secondary axes")
+	}
+}
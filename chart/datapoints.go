@@ -0,0 +1,236 @@
+package chart
+
+import (
+	"encoding/xml"
+	"errors"
+	"sort"
+
+	"github.com/yaklabco/unioffice/v2"
+	"github.com/yaklabco/unioffice/v2/drawing"
+	"github.com/yaklabco/unioffice/v2/schema/soo/dml"
+	crt "github.com/yaklabco/unioffice/v2/schema/soo/dml/chart"
+)
+
+// DataPoint is the formatting of a single point of a series, overriding the
+// formatting of the series.
+type DataPoint struct{ x *crt.CT_DPt }
+
+// MakeDataPoint wraps the formatting of a data point.
+func MakeDataPoint(x *crt.CT_DPt) DataPoint { return DataPoint{x} }
+
+// X returns the inner wrapped XML type.
+func (d DataPoint) X() *crt.CT_DPt { return d.x }
+
+// DataPoint returns the formatting of the point of the series at an index,
+// adding it if the point isn't formatted yet. Surface series don't support
+// formatting single points.
+func (s Series) DataPoint(idx uint32) (DataPoint, error) {
+	if s.f.dPt == nil {
+		return DataPoint{}, errors.New("series doesn't support data point formatting")
+	}
+	pts := *s.f.dPt
+	i := sort.Search(len(pts), func(i int) bool { return pts[i].Idx.ValAttr >= idx })
+	if i < len(pts) && pts[i].Idx.ValAttr == idx {
+		return DataPoint{pts[i]}, nil
+	}
+	x := crt.NewCT_DPt()
+	x.Idx.ValAttr = idx
+	if _, ok := s.x.(*crt.CT_BarSer); ok {
+		x.InvertIfNegative = crt.NewCT_Boolean()
+		x.InvertIfNegative.ValAttr = unioffice.Bool(false)
+	}
+	pts = append(pts, nil)
+	copy(pts[i+1:], pts[i:])
+	pts[i] = x
+	*s.f.dPt = pts
+	return DataPoint{x}, nil
+}
+
+// DataPoints returns the formatted points of the series.
+func (s Series) DataPoints() []DataPoint {
+	ret := []DataPoint{}
+	if s.f.dPt == nil {
+		return ret
+	}
+	for _, x := range *s.f.dPt {
+		ret = append(ret, DataPoint{x})
+	}
+	return ret
+}
+
+// Index returns the index of the point in the series.
+func (d DataPoint) Index() uint32 { return d.x.Idx.ValAttr }
+
+// Properties returns the shape properties of the point, e.g. the fill of a
+// bar or a pie slice.
+func (d DataPoint) Properties() drawing.ShapeProperties {
+	if d.x.SpPr == nil {
+		d.x.SpPr = dml.NewCT_ShapeProperties()
+	}
+	return drawing.MakeShapeProperties(d.x.SpPr)
+}
+
+// Marker returns the marker of the point of a line, scatter or radar series.
+func (d DataPoint) Marker() Marker {
+	if d.x.Marker == nil {
+		d.x.Marker = crt.NewCT_Marker()
+	}
+	return MakeMarker(d.x.Marker)
+}
+
+// SetExplosion sets how far the slice of a pie series is pulled out of the
+// pie, in percent of the radius.
+func (d DataPoint) SetExplosion(pct uint32) {
+	d.x.Explosion = crt.NewCT_UnsignedInt()
+	d.x.Explosion.ValAttr = pct
+}
+
+// SetInvertIfNegative controls whether the fill of the bar is inverted if its
+// value is negative.
+func (d DataPoint) SetInvertIfNegative(b bool) {
+	d.x.InvertIfNegative = crt.NewCT_Boolean()
+	d.x.InvertIfNegative.ValAttr = unioffice.Bool(b)
+}
+
+// SetBubble3D controls whether the bubble of a bubble series is drawn with a
+// 3D effect.
+func (d DataPoint) SetBubble3D(b bool) {
+	d.x.Bubble3D = crt.NewCT_Boolean()
+	d.x.Bubble3D.ValAttr = unioffice.Bool(b)
+}
+
+// DataPoint returns the formatting of a point of the series, see
+// Series.DataPoint.
+func (c AreaChartSeries) DataPoint(idx uint32) DataPoint {
+	d, _ := makeSeries(c._ad).DataPoint(idx)
+	return d
+}
+
+// DataPoint returns the formatting of a point of the series, see
+// Series.DataPoint.
+func (c BarChartSeries) DataPoint(idx uint32) DataPoint {
+	d, _ := makeSeries(c._gc).DataPoint(idx)
+	return d
+}
+
+// DataPoint returns the formatting of a point of the series, see
+// Series.DataPoint.
+func (c LineChartSeries) DataPoint(idx uint32) DataPoint {
+	d, _ := makeSeries(c._gega).DataPoint(idx)
+	return d
+}
+
+// DataPoint returns the formatting of a point of the series, see
+// Series.DataPoint.
+func (c PieChartSeries) DataPoint(idx uint32) DataPoint {
+	d, _ := makeSeries(c._baa).DataPoint(idx)
+	return d
+}
+
+// DataPoint returns the formatting of a point of the series, see
+// Series.DataPoint.
+func (c RadarChartSeries) DataPoint(idx uint32) DataPoint {
+	d, _ := makeSeries(c._cfe).DataPoint(idx)
+	return d
+}
+
+// DataPoint returns the formatting of a point of the series, see
+// Series.DataPoint.
+func (c ScatterChartSeries) DataPoint(idx uint32) DataPoint {
+	d, _ := makeSeries(c._ffce).DataPoint(idx)
+	return d
+}
+
+// DataPoint returns the formatting of a point of the series, see
+// Series.DataPoint.
+func (c BubbleChartSeries) DataPoint(idx uint32) DataPoint {
+	d, _ := makeSeries(c._ffb).DataPoint(idx)
+	return d
+}
+
+// Extensions of Office 2013 charts to label data points with the text of
+// cells.
+const (
+	c15Namespace           = "http://schemas.microsoft.com/office/drawing/2012/chart"
+	extDataLabelsRange     = "{02D57815-91ED-43cb-87C2-D8B1C6C2E8DE}"
+	extShowDataLabelsRange = "{CE6537A1-D6FC-4f65-9D91-7224C49458BB}"
+)
+
+// Labels returns the data labels of the series. Surface series don't support
+// data labels.
+func (s Series) Labels() (DataLabels, error) {
+	if s.f.dLbls == nil {
+		return DataLabels{}, errors.New("series doesn't support data labels")
+	}
+	if *s.f.dLbls == nil {
+		*s.f.dLbls = crt.NewCT_DLbls()
+	}
+	return MakeDataLabels(*s.f.dLbls), nil
+}
+
+// SetLabelsReference labels the points of the series with the text of the
+// cells of a reference, e.g. "Sheet1!$C$2:$C$5", instead of their values.
+// The labels are displayed by Excel 2013 and later, other viewers display no
+// labels. Surface series don't support data labels.
+func (s Series) SetLabelsReference(ref string) error {
+	dl, err := s.Labels()
+	if err != nil {
+		return err
+	}
+	dl.SetShowLegendKey(false)
+	dl.SetShowValue(false)
+	dl.SetShowCategoryName(false)
+	dl.SetShowSeriesName(false)
+	dl.SetShowPercent(false)
+	setExtension(&(*s.f.dLbls).ExtLst, extShowDataLabelsRange, &unioffice.XSDAny{
+		XMLName: xml.Name{Space: c15Namespace, Local: "showDataLabelsRange"},
+		Attrs:   []xml.Attr{{Name: xml.Name{Local: "val"}, Value: "1"}},
+	})
+	setExtension(s.f.extLst, extDataLabelsRange, &unioffice.XSDAny{
+		XMLName: xml.Name{Space: c15Namespace, Local: "datalabelsRange"},
+		Nodes: []*unioffice.XSDAny{{
+			XMLName: xml.Name{Space: c15Namespace, Local: "f"},
+			Data:    []byte(ref),
+		}},
+	})
+	return nil
+}
+
+// LabelsReference returns the reference of the cells labeling the points of
+// the series, or an empty string if they aren't labeled with cells.
+func (s Series) LabelsReference() string {
+	if *s.f.extLst == nil {
+		return ""
+	}
+	for _, ext := range (*s.f.extLst).Ext {
+		if ext.UriAttr == nil || *ext.UriAttr != extDataLabelsRange {
+			continue
+		}
+		if n, ok := ext.Any.(*unioffice.XSDAny); ok {
+			for _, c := range n.Nodes {
+				if c.XMLName.Local == "f" {
+					return string(c.Data)
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// setExtension sets an extension of a chart element, replacing the extension
+// with the same URI.
+func setExtension(lst **crt.CT_ExtensionList, uri string, el *unioffice.XSDAny) {
+	if *lst == nil {
+		*lst = crt.NewCT_ExtensionList()
+	}
+	ext := crt.NewCT_Extension()
+	ext.UriAttr = unioffice.String(uri)
+	ext.Any = el
+	for i, e := range (*lst).Ext {
+		if e.UriAttr != nil && *e.UriAttr == uri {
+			(*lst).Ext[i] = ext
+			return
+		}
+	}
+	(*lst).Ext = append((*lst).Ext, ext)
+}
@@ -0,0 +1,54 @@
+package chart
+
+import (
+	"testing"
+
+	"github.com/yaklabco/unioffice/v2/color"
+	crt "github.com/yaklabco/unioffice/v2/schema/soo/dml/chart"
+)
+
+func TestDataPointXML(t *testing.T) {
+	c := MakeChart(crt.NewChartSpace())
+	bs := c.AddBarChart().AddSeries()
+	bs.DataPoint(3).Properties().SetSolidFill(color.Red)
+	bs.DataPoint(1).SetInvertIfNegative(true)
+	if d := bs.DataPoint(3); d.Index() != 3 {
+		t.Errorf("expected the existing point, got %d", d.Index())
+	}
+	pie := c.AddPieChart().AddSeries()
+	pie.DataPoint(0).SetExplosion(25)
+	out := marshalChart(t, c)
+	expectXML(t, out,
+		`<c:dPt><c:idx val="1"></c:idx><c:invertIfNegative val="1"></c:invertIfNegative></c:dPt>`,
+		`<c:dPt><c:idx val="3"></c:idx><c:invertIfNegative val="0"></c:invertIfNegative><c:spPr><a:solidFill><a:srgbClr val="ff0000"></a:srgbClr></a:solidFill></c:spPr></c:dPt>`,
+		`<c:pieChart>`,
+		`<c:dPt><c:idx val="0"></c:idx><c:explosion val="25"></c:explosion></c:dPt>`,
+	)
+
+	if pts := MakeSeries(bs.X()).DataPoints(); len(pts) != 2 || pts[0].Index() != 1 || pts[1].Index() != 3 {
+		t.Errorf("expected sorted data points")
+	}
+	if _, err := MakeSeries(crt.NewCT_SurfaceSer()).DataPoint(0); err == nil {
+		t.Errorf("expected error formatting a point of a surface series")
+	}
+}
+
+func TestLabelsReferenceXML(t *testing.T) {
+	c := MakeChart(crt.NewChartSpace())
+	s := MakeSeries(c.AddLineChart().AddSeries().X())
+	if err := s.SetLabelsReference("Sheet1!$A$2:$A$5"); err != nil {
+		t.Fatalf("error labeling series: %s", err)
+	}
+	if ref := s.LabelsReference(); ref != "Sheet1!$A$2:$A$5" {
+		t.Errorf("unexpected labels reference %q", ref)
+	}
+	out := marshalChart(t, c)
+	expectXML(t, out,
+		`<c:dLbls>`,
+		`<c:showVal val="0"></c:showVal>`,
+		`<c:ext uri="{CE6537A1-D6FC-4f65-9D91-7224C49458BB}"><c15:showDataLabelsRange val="1"`,
+		`</c:dLbls>`,
+		`<c:ext uri="{02D57815-91ED-43cb-87C2-D8B1C6C2E8DE}"><c15:datalabelsRange`,
+		`<c15:f>Sheet1!$A$2:$A$5</c15:f>`,
+	)
+}
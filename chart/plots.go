@@ -0,0 +1,978 @@
+package chart
+
+import (
+	"errors"
+	"math"
+	"strconv"
+
+	"github.com/yaklabco/unioffice/v2"
+	"github.com/yaklabco/unioffice/v2/drawing"
+	"github.com/yaklabco/unioffice/v2/schema/soo/dml"
+	crt "github.com/yaklabco/unioffice/v2/schema/soo/dml/chart"
+)
+
+// PlotType is the chart type of a plot.
+type PlotType byte
+
+// PlotType constants.
+const (
+	PlotTypeUnknown PlotType = iota
+	PlotTypeArea
+	PlotTypeArea3D
+	PlotTypeBar
+	PlotTypeBar3D
+	PlotTypeBubble
+	PlotTypeDoughnut
+	PlotTypeLine
+	PlotTypeLine3D
+	PlotTypeOfPie
+	PlotTypePie
+	PlotTypePie3D
+	PlotTypeRadar
+	PlotTypeScatter
+	PlotTypeStock
+	PlotTypeSurface
+	PlotTypeSurface3D
+)
+
+// Plot is one of the chart types plotted in a chart along with its series,
+// e.g. the bars of a combined bar and line chart.
+type Plot struct {
+	c Chart
+	x *crt.CT_PlotAreaChoice
+}
+
+// Plots returns the plots of the chart.
+func (c Chart) Plots() []Plot {
+	ret := []Plot{}
+	if c._egg.Chart == nil || c._egg.Chart.PlotArea == nil {
+		return ret
+	}
+	for _, pc := range c._egg.Chart.PlotArea.PlotAreaChoice {
+		ret = append(ret, Plot{c, pc})
+	}
+	return ret
+}
+
+// X returns the inner wrapped XML type.
+func (p Plot) X() *crt.CT_PlotAreaChoice { return p.x }
+
+// Type returns the chart type of the plot.
+func (p Plot) Type() PlotType {
+	switch {
+	case p.x.AreaChart != nil:
+		return PlotTypeArea
+	case p.x.Area3DChart != nil:
+		return PlotTypeArea3D
+	case p.x.BarChart != nil:
+		return PlotTypeBar
+	case p.x.Bar3DChart != nil:
+		return PlotTypeBar3D
+	case p.x.BubbleChart != nil:
+		return PlotTypeBubble
+	case p.x.DoughnutChart != nil:
+		return PlotTypeDoughnut
+	case p.x.LineChart != nil:
+		return PlotTypeLine
+	case p.x.Line3DChart != nil:
+		return PlotTypeLine3D
+	case p.x.OfPieChart != nil:
+		return PlotTypeOfPie
+	case p.x.PieChart != nil:
+		return PlotTypePie
+	case p.x.Pie3DChart != nil:
+		return PlotTypePie3D
+	case p.x.RadarChart != nil:
+		return PlotTypeRadar
+	case p.x.ScatterChart != nil:
+		return PlotTypeScatter
+	case p.x.StockChart != nil:
+		return PlotTypeStock
+	case p.x.SurfaceChart != nil:
+		return PlotTypeSurface
+	case p.x.Surface3DChart != nil:
+		return PlotTypeSurface3D
+	}
+	return PlotTypeUnknown
+}
+
+// Series returns the series of the plot.
+func (p Plot) Series() []Series {
+	ret := []Series{}
+	add := func(x interface{}) { ret = append(ret, makeSeries(x)) }
+	switch {
+	case p.x.AreaChart != nil:
+		for _, s := range p.x.AreaChart.Ser {
+			add(s)
+		}
+	case p.x.Area3DChart != nil:
+		for _, s := range p.x.Area3DChart.Ser {
+			add(s)
+		}
+	case p.x.BarChart != nil:
+		for _, s := range p.x.BarChart.Ser {
+			add(s)
+		}
+	case p.x.Bar3DChart != nil:
+		for _, s := range p.x.Bar3DChart.Ser {
+			add(s)
+		}
+	case p.x.BubbleChart != nil:
+		for _, s := range p.x.BubbleChart.Ser {
+			add(s)
+		}
+	case p.x.DoughnutChart != nil:
+		for _, s := range p.x.DoughnutChart.Ser {
+			add(s)
+		}
+	case p.x.LineChart != nil:
+		for _, s := range p.x.LineChart.Ser {
+			add(s)
+		}
+	case p.x.Line3DChart != nil:
+		for _, s := range p.x.Line3DChart.Ser {
+			add(s)
+		}
+	case p.x.OfPieChart != nil:
+		for _, s := range p.x.OfPieChart.Ser {
+			add(s)
+		}
+	case p.x.PieChart != nil:
+		for _, s := range p.x.PieChart.Ser {
+			add(s)
+		}
+	case p.x.Pie3DChart != nil:
+		for _, s := range p.x.Pie3DChart.Ser {
+			add(s)
+		}
+	case p.x.RadarChart != nil:
+		for _, s := range p.x.RadarChart.Ser {
+			add(s)
+		}
+	case p.x.ScatterChart != nil:
+		for _, s := range p.x.ScatterChart.Ser {
+			add(s)
+		}
+	case p.x.StockChart != nil:
+		for _, s := range p.x.StockChart.Ser {
+			add(s)
+		}
+	case p.x.SurfaceChart != nil:
+		for _, s := range p.x.SurfaceChart.Ser {
+			add(s)
+		}
+	case p.x.Surface3DChart != nil:
+		for _, s := range p.x.Surface3DChart.Ser {
+			add(s)
+		}
+	}
+	return ret
+}
+
+// RemoveSeries removes a series from the plot.
+func (p Plot) RemoveSeries(s Series) {
+	switch x := s.x.(type) {
+	case *crt.CT_AreaSer:
+		if p.x.AreaChart != nil {
+			p.x.AreaChart.Ser = removeSeries(p.x.AreaChart.Ser, x)
+		} else if p.x.Area3DChart != nil {
+			p.x.Area3DChart.Ser = removeSeries(p.x.Area3DChart.Ser, x)
+		}
+	case *crt.CT_BarSer:
+		if p.x.BarChart != nil {
+			p.x.BarChart.Ser = removeSeries(p.x.BarChart.Ser, x)
+		} else if p.x.Bar3DChart != nil {
+			p.x.Bar3DChart.Ser = removeSeries(p.x.Bar3DChart.Ser, x)
+		}
+	case *crt.CT_LineSer:
+		if p.x.LineChart != nil {
+			p.x.LineChart.Ser = removeSeries(p.x.LineChart.Ser, x)
+		} else if p.x.Line3DChart != nil {
+			p.x.Line3DChart.Ser = removeSeries(p.x.Line3DChart.Ser, x)
+		} else if p.x.StockChart != nil {
+			p.x.StockChart.Ser = removeSeries(p.x.StockChart.Ser, x)
+		}
+	case *crt.CT_PieSer:
+		if p.x.PieChart != nil {
+			p.x.PieChart.Ser = removeSeries(p.x.PieChart.Ser, x)
+		} else if p.x.Pie3DChart != nil {
+			p.x.Pie3DChart.Ser = removeSeries(p.x.Pie3DChart.Ser, x)
+		} else if p.x.DoughnutChart != nil {
+			p.x.DoughnutChart.Ser = removeSeries(p.x.DoughnutChart.Ser, x)
+		} else if p.x.OfPieChart != nil {
+			p.x.OfPieChart.Ser = removeSeries(p.x.OfPieChart.Ser, x)
+		}
+	case *crt.CT_RadarSer:
+		if p.x.RadarChart != nil {
+			p.x.RadarChart.Ser = removeSeries(p.x.RadarChart.Ser, x)
+		}
+	case *crt.CT_ScatterSer:
+		if p.x.ScatterChart != nil {
+			p.x.ScatterChart.Ser = removeSeries(p.x.ScatterChart.Ser, x)
+		}
+	case *crt.CT_BubbleSer:
+		if p.x.BubbleChart != nil {
+			p.x.BubbleChart.Ser = removeSeries(p.x.BubbleChart.Ser, x)
+		}
+	case *crt.CT_SurfaceSer:
+		if p.x.SurfaceChart != nil {
+			p.x.SurfaceChart.Ser = removeSeries(p.x.SurfaceChart.Ser, x)
+		} else if p.x.Surface3DChart != nil {
+			p.x.Surface3DChart.Ser = removeSeries(p.x.Surface3DChart.Ser, x)
+		}
+	}
+}
+
+// removeSeries returns the series of a plot without one of them.
+func removeSeries[T comparable](sers []T, s T) []T {
+	for i, v := range sers {
+		if v == s {
+			return append(sers[:i], sers[i+1:]...)
+		}
+	}
+	return sers
+}
+
+// axisIDs returns the IDs of the axes of the plot, pie plots have none.
+func (p Plot) axisIDs() []*crt.CT_UnsignedInt {
+	switch {
+	case p.x.AreaChart != nil:
+		return p.x.AreaChart.AxId
+	case p.x.Area3DChart != nil:
+		return p.x.Area3DChart.AxId
+	case p.x.BarChart != nil:
+		return p.x.BarChart.AxId
+	case p.x.Bar3DChart != nil:
+		return p.x.Bar3DChart.AxId
+	case p.x.BubbleChart != nil:
+		return p.x.BubbleChart.AxId
+	case p.x.LineChart != nil:
+		return p.x.LineChart.AxId
+	case p.x.Line3DChart != nil:
+		return p.x.Line3DChart.AxId
+	case p.x.RadarChart != nil:
+		return p.x.RadarChart.AxId
+	case p.x.ScatterChart != nil:
+		return p.x.ScatterChart.AxId
+	case p.x.StockChart != nil:
+		return p.x.StockChart.AxId
+	case p.x.SurfaceChart != nil:
+		return p.x.SurfaceChart.AxId
+	case p.x.Surface3DChart != nil:
+		return p.x.Surface3DChart.AxId
+	}
+	return nil
+}
+
+// Axes returns the axes the plot is drawn against, the horizontal one first.
+func (p Plot) Axes() []Axis {
+	ret := []Axis{}
+	for _, id := range p.axisIDs() {
+		if a, ok := p.c.Axis(id.ValAttr); ok {
+			ret = append(ret, a)
+		}
+	}
+	return ret
+}
+
+// SetType changes the chart type of the plot, keeping its series names,
+// categories and values and its axes. The formatting specific to the
+// previous type is replaced by the defaults of the new one. Bubble, stock,
+// surface and pie of pie plots can't be converted from or to, neither can
+// plots sharing their horizontal axis with another plot between scatter and
+// other types as the axis would change for both.
+func (p Plot) SetType(t PlotType) error {
+	if t == p.Type() {
+		return nil
+	}
+	switch p.Type() {
+	case PlotTypeBubble, PlotTypeStock, PlotTypeSurface, PlotTypeSurface3D, PlotTypeOfPie, PlotTypeUnknown:
+		return errors.New("unsupported plot type to convert")
+	}
+	series := p.Series()
+	axes := p.axisIDs()
+	wasScatter := p.Type() == PlotTypeScatter
+	switch t {
+	case PlotTypeDoughnut, PlotTypePie, PlotTypePie3D:
+	default:
+		if (t == PlotTypeScatter) != wasScatter && len(axes) > 0 && p.sharesAxis(axes[0].ValAttr) {
+			return errors.New("horizontal axis shared with another plot")
+		}
+	}
+
+	// build the new plot with the builders of the chart, then move it in place
+	pa := p.c._egg.Chart.PlotArea
+	n := len(pa.PlotAreaChoice)
+	add, ok := map[PlotType]func(){
+		PlotTypeArea:     func() { p.c.AddAreaChart() },
+		PlotTypeArea3D:   func() { p.c.AddArea3DChart() },
+		PlotTypeBar:      func() { p.c.AddBarChart() },
+		PlotTypeBar3D:    func() { p.c.AddBar3DChart() },
+		PlotTypeDoughnut: func() { p.c.AddDoughnutChart() },
+		PlotTypeLine:     func() { p.c.AddLineChart() },
+		PlotTypeLine3D:   func() { p.c.AddLine3DChart() },
+		PlotTypePie:      func() { p.c.AddPieChart() },
+		PlotTypePie3D:    func() { p.c.AddPie3DChart() },
+		PlotTypeRadar:    func() { p.c.AddRadarChart() },
+		PlotTypeScatter:  func() { p.c.AddScatterChart() },
+	}[t]
+	if !ok {
+		return errors.New("unsupported plot type to convert to")
+	}
+	add()
+	*p.x = *pa.PlotAreaChoice[n]
+	pa.PlotAreaChoice = pa.PlotAreaChoice[:n]
+
+	for _, s := range series {
+		ns, ok := p.addSeries()
+		if !ok {
+			return errors.New("unsupported plot type to convert to")
+		}
+		*ns.f.idx, *ns.f.order = *s.f.idx, *s.f.order
+		*ns.f.tx = *s.f.tx
+		*ns.f.cat, *ns.f.val = *s.f.cat, *s.f.val
+	}
+	if p.hasAxes() {
+		if len(axes) == 0 {
+			cat, val := p.c.AddCategoryAxis(), p.c.AddValueAxis()
+			cat.SetCrosses(val)
+			val.SetCrosses(cat)
+			axes = []*crt.CT_UnsignedInt{{ValAttr: cat.AxisID()}, {ValAttr: val.AxisID()}}
+		}
+		p.setAxisIDs(axes)
+		// scatter plots are drawn against two value axes
+		if t == PlotTypeScatter {
+			p.c.convertAxis(axes[0].ValAttr, false)
+		} else if wasScatter {
+			p.c.convertAxis(axes[0].ValAttr, true)
+		}
+	}
+	p.c.removeUnusedAxes()
+	return nil
+}
+
+// removeUnusedAxes removes the axes no plot is drawn against.
+func (c Chart) removeUnusedAxes() {
+	used := map[uint32]bool{}
+	for _, p := range c.Plots() {
+		for _, id := range p.axisIDs() {
+			used[id.ValAttr] = true
+		}
+	}
+	pa := c._egg.Chart.PlotArea
+	kept := pa.PlotAreaChoice1[:0]
+	for _, ac := range pa.PlotAreaChoice1 {
+		var id uint32
+		switch {
+		case ac.CatAx != nil:
+			id = ac.CatAx.AxId.ValAttr
+		case ac.ValAx != nil:
+			id = ac.ValAx.AxId.ValAttr
+		case ac.DateAx != nil:
+			id = ac.DateAx.AxId.ValAttr
+		case ac.SerAx != nil:
+			id = ac.SerAx.AxId.ValAttr
+		}
+		if used[id] {
+			kept = append(kept, ac)
+		}
+	}
+	pa.PlotAreaChoice1 = kept
+}
+
+// sharesAxis returns whether another plot of the chart is drawn against an
+// axis.
+func (p Plot) sharesAxis(id uint32) bool {
+	for _, o := range p.c.Plots() {
+		if o.x == p.x {
+			continue
+		}
+		for _, oid := range o.axisIDs() {
+			if oid.ValAttr == id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasAxes returns whether the plot is drawn against axes.
+func (p Plot) hasAxes() bool {
+	return p.x.PieChart == nil && p.x.Pie3DChart == nil && p.x.DoughnutChart == nil && p.x.OfPieChart == nil
+}
+
+func (p Plot) setAxisIDs(ids []*crt.CT_UnsignedInt) {
+	switch {
+	case p.x.AreaChart != nil:
+		p.x.AreaChart.AxId = ids
+	case p.x.Area3DChart != nil:
+		p.x.Area3DChart.AxId = ids
+	case p.x.BarChart != nil:
+		p.x.BarChart.AxId = ids
+	case p.x.Bar3DChart != nil:
+		p.x.Bar3DChart.AxId = ids
+	case p.x.LineChart != nil:
+		p.x.LineChart.AxId = ids
+	case p.x.Line3DChart != nil:
+		p.x.Line3DChart.AxId = ids
+	case p.x.RadarChart != nil:
+		p.x.RadarChart.AxId = ids
+	case p.x.ScatterChart != nil:
+		p.x.ScatterChart.AxId = ids
+	}
+}
+
+// addSeries adds a series with the defaults of the type of the plot, false
+// is returned if series can't be added to plots of the type.
+func (p Plot) addSeries() (Series, bool) {
+	switch {
+	case p.x.AreaChart != nil:
+		return makeSeries(AreaChart{_cc: p.x.AreaChart}.AddSeries().X()), true
+	case p.x.Area3DChart != nil:
+		return makeSeries(Area3DChart{_ga: p.x.Area3DChart}.AddSeries().X()), true
+	case p.x.BarChart != nil:
+		return makeSeries(BarChart{_dc: p.x.BarChart}.AddSeries().X()), true
+	case p.x.Bar3DChart != nil:
+		return makeSeries(Bar3DChart{_ffc: p.x.Bar3DChart}.AddSeries().X()), true
+	case p.x.DoughnutChart != nil:
+		return makeSeries(DoughnutChart{_gab: p.x.DoughnutChart}.AddSeries().X()), true
+	case p.x.LineChart != nil:
+		return makeSeries(LineChart{_cffa: p.x.LineChart}.AddSeries().X()), true
+	case p.x.Line3DChart != nil:
+		return makeSeries(Line3DChart{_ddf: p.x.Line3DChart}.AddSeries().X()), true
+	case p.x.PieChart != nil:
+		return makeSeries(PieChart{_gdbc: p.x.PieChart}.AddSeries().X()), true
+	case p.x.Pie3DChart != nil:
+		return makeSeries(Pie3DChart{_bcfa: p.x.Pie3DChart}.AddSeries().X()), true
+	case p.x.RadarChart != nil:
+		return makeSeries(RadarChart{_dbf: p.x.RadarChart}.AddSeries().X()), true
+	case p.x.ScatterChart != nil:
+		return makeSeries(ScatterChart{_dfbd: p.x.ScatterChart}.AddSeries().X()), true
+	}
+	return Series{}, false
+}
+
+// Axis returns the axis of the chart with an ID.
+func (c Chart) Axis(id uint32) (Axis, bool) {
+	for _, a := range c.Axes() {
+		if a.AxisID() == id {
+			return a, true
+		}
+	}
+	return nil, false
+}
+
+// Axes returns the axes of the chart, i.e. CategoryAxis, ValueAxis, DateAxis
+// and SeriesAxis values.
+func (c Chart) Axes() []Axis {
+	ret := []Axis{}
+	if c._egg.Chart == nil || c._egg.Chart.PlotArea == nil {
+		return ret
+	}
+	for _, ac := range c._egg.Chart.PlotArea.PlotAreaChoice1 {
+		switch {
+		case ac.CatAx != nil:
+			ret = append(ret, CategoryAxis{ac.CatAx})
+		case ac.ValAx != nil:
+			ret = append(ret, ValueAxis{ac.ValAx})
+		case ac.DateAx != nil:
+			ret = append(ret, DateAxis{ac.DateAx})
+		case ac.SerAx != nil:
+			ret = append(ret, SeriesAxis{ac.SerAx})
+		}
+	}
+	return ret
+}
+
+// convertAxis converts a value axis to a category one or the reverse,
+// keeping its position, title, format and crossing.
+func (c Chart) convertAxis(id uint32, toCategory bool) {
+	for _, ac := range c._egg.Chart.PlotArea.PlotAreaChoice1 {
+		switch {
+		case toCategory && ac.ValAx != nil && ac.ValAx.AxId.ValAttr == id:
+			v := ac.ValAx
+			cat := crt.NewCT_CatAx()
+			cat.AxId, cat.Scaling, cat.Delete, cat.AxPos = v.AxId, crt.NewCT_Scaling(), v.Delete, v.AxPos
+			cat.Scaling.Orientation = v.Scaling.Orientation
+			cat.Title, cat.NumFmt, cat.MajorTickMark, cat.MinorTickMark, cat.TickLblPos = v.Title, v.NumFmt, v.MajorTickMark, v.MinorTickMark, v.TickLblPos
+			cat.SpPr, cat.TxPr, cat.CrossAx, cat.AxSharedChoice = v.SpPr, v.TxPr, v.CrossAx, v.AxSharedChoice
+			cat.Auto = crt.NewCT_Boolean()
+			cat.Auto.ValAttr = unioffice.Bool(true)
+			cat.LblAlgn = crt.NewCT_LblAlgn()
+			cat.LblAlgn.ValAttr = crt.ST_LblAlgnCtr
+			cat.LblOffset = crt.NewCT_LblOffset()
+			ac.ValAx, ac.CatAx = nil, cat
+		case !toCategory && ac.CatAx != nil && ac.CatAx.AxId.ValAttr == id:
+			a := ac.CatAx
+			val := crt.NewCT_ValAx()
+			val.AxId, val.Scaling, val.Delete, val.AxPos = a.AxId, a.Scaling, a.Delete, a.AxPos
+			val.Title, val.NumFmt, val.MajorTickMark, val.MinorTickMark, val.TickLblPos = a.Title, a.NumFmt, a.MajorTickMark, a.MinorTickMark, a.TickLblPos
+			val.SpPr, val.TxPr, val.CrossAx, val.AxSharedChoice = a.SpPr, a.TxPr, a.CrossAx, a.AxSharedChoice
+			val.CrossBetween = crt.NewCT_CrossBetween()
+			val.CrossBetween.ValAttr = crt.ST_CrossBetweenMidCat
+			ac.CatAx, ac.ValAx = nil, val
+		}
+	}
+}
+
+// Title returns the title of the chart if it has one.
+func (c Chart) Title() (Title, bool) {
+	if c._egg.Chart == nil || c._egg.Chart.Title == nil {
+		return Title{}, false
+	}
+	return Title{c._egg.Chart.Title}, true
+}
+
+// Text returns the text of the title, the cached text if it's given by a
+// cell reference.
+func (t Title) Text() string {
+	if t._edff.Tx == nil || t._edff.Tx.TxChoice == nil {
+		return ""
+	}
+	if r := t._edff.Tx.TxChoice.StrRef; r != nil {
+		return strCacheText(r.StrCache)
+	}
+	return richText(t._edff.Tx.TxChoice.Rich)
+}
+
+// richText returns the text of a text body, with its paragraphs on separate
+// lines.
+func richText(tb *dml.CT_TextBody) string {
+	if tb == nil {
+		return ""
+	}
+	s := ""
+	for i, p := range tb.P {
+		if i > 0 {
+			s += "\n"
+		}
+		for _, r := range p.EG_TextRun {
+			if r.TextRunChoice != nil && r.TextRunChoice.R != nil {
+				s += r.TextRunChoice.R.T
+			}
+		}
+	}
+	return s
+}
+
+// strCacheText returns the cached strings joined by spaces.
+func strCacheText(d *crt.CT_StrData) string {
+	if d == nil {
+		return ""
+	}
+	s := ""
+	for i, pt := range d.Pt {
+		if i > 0 {
+			s += " "
+		}
+		s += pt.V
+	}
+	return s
+}
+
+// seriesFields are pointers to the fields common to the series of all chart
+// types, the categories and values being the x and y values of scatter and
+// bubble series. The fields a chart type lacks are nil.
+type seriesFields struct {
+	idx, order *(*crt.CT_UnsignedInt)
+	tx         **crt.CT_SerTx
+	spPr       **dml.CT_ShapeProperties
+	cat        **crt.CT_AxDataSource
+	val        **crt.CT_NumDataSource
+	bubbleSize **crt.CT_NumDataSource
+	dPt        *[]*crt.CT_DPt
+	dLbls      **crt.CT_DLbls
+	trendline  *[]*crt.CT_Trendline
+	extLst     **crt.CT_ExtensionList
+}
+
+// Series is a series of a plot of any chart type, see Plot.Series.
+type Series struct {
+	x interface{}
+	f seriesFields
+}
+
+// MakeSeries wraps a series of any chart type, e.g. a *chart.CT_LineSer.
+func MakeSeries(x interface{}) Series { return makeSeries(x) }
+
+func makeSeries(x interface{}) Series {
+	s := Series{x: x}
+	switch v := x.(type) {
+	case *crt.CT_AreaSer:
+		s.f = seriesFields{idx: &v.Idx, order: &v.Order, tx: &v.Tx, spPr: &v.SpPr, cat: &v.Cat, val: &v.Val,
+			dPt: &v.DPt, dLbls: &v.DLbls, trendline: &v.Trendline, extLst: &v.ExtLst}
+	case *crt.CT_BarSer:
+		s.f = seriesFields{idx: &v.Idx, order: &v.Order, tx: &v.Tx, spPr: &v.SpPr, cat: &v.Cat, val: &v.Val,
+			dPt: &v.DPt, dLbls: &v.DLbls, trendline: &v.Trendline, extLst: &v.ExtLst}
+	case *crt.CT_LineSer:
+		s.f = seriesFields{idx: &v.Idx, order: &v.Order, tx: &v.Tx, spPr: &v.SpPr, cat: &v.Cat, val: &v.Val,
+			dPt: &v.DPt, dLbls: &v.DLbls, trendline: &v.Trendline, extLst: &v.ExtLst}
+	case *crt.CT_PieSer:
+		s.f = seriesFields{idx: &v.Idx, order: &v.Order, tx: &v.Tx, spPr: &v.SpPr, cat: &v.Cat, val: &v.Val,
+			dPt: &v.DPt, dLbls: &v.DLbls, extLst: &v.ExtLst}
+	case *crt.CT_RadarSer:
+		s.f = seriesFields{idx: &v.Idx, order: &v.Order, tx: &v.Tx, spPr: &v.SpPr, cat: &v.Cat, val: &v.Val,
+			dPt: &v.DPt, dLbls: &v.DLbls, extLst: &v.ExtLst}
+	case *crt.CT_SurfaceSer:
+		s.f = seriesFields{idx: &v.Idx, order: &v.Order, tx: &v.Tx, spPr: &v.SpPr, cat: &v.Cat, val: &v.Val,
+			extLst: &v.ExtLst}
+	case *crt.CT_ScatterSer:
+		s.f = seriesFields{idx: &v.Idx, order: &v.Order, tx: &v.Tx, spPr: &v.SpPr, cat: &v.XVal, val: &v.YVal,
+			dPt: &v.DPt, dLbls: &v.DLbls, trendline: &v.Trendline, extLst: &v.ExtLst}
+	case *crt.CT_BubbleSer:
+		s.f = seriesFields{idx: &v.Idx, order: &v.Order, tx: &v.Tx, spPr: &v.SpPr, cat: &v.XVal, val: &v.YVal,
+			bubbleSize: &v.BubbleSize, dPt: &v.DPt, dLbls: &v.DLbls, trendline: &v.Trendline, extLst: &v.ExtLst}
+	}
+	return s
+}
+
+// X returns the inner wrapped XML type, e.g. a *chart.CT_BarSer.
+func (s Series) X() interface{} { return s.x }
+
+// Index returns the index of the series.
+func (s Series) Index() uint32 { return (*s.f.idx).ValAttr }
+
+// Order returns the order of the series.
+func (s Series) Order() uint32 { return (*s.f.order).ValAttr }
+
+// Name returns the name of the series, the cached text if it's given by a
+// cell reference.
+func (s Series) Name() string {
+	tx := *s.f.tx
+	if tx == nil || tx.SerTxChoice == nil {
+		return ""
+	}
+	if tx.SerTxChoice.StrRef != nil {
+		return strCacheText(tx.SerTxChoice.StrRef.StrCache)
+	}
+	if tx.SerTxChoice.V != nil {
+		return *tx.SerTxChoice.V
+	}
+	return ""
+}
+
+// NameReference returns the reference of the cell containing the name of the
+// series, or an empty string if the name is given directly.
+func (s Series) NameReference() string {
+	if tx := *s.f.tx; tx != nil && tx.SerTxChoice != nil && tx.SerTxChoice.StrRef != nil {
+		return tx.SerTxChoice.StrRef.F
+	}
+	return ""
+}
+
+// SetName sets the name of the series.
+func (s Series) SetName(name string) {
+	*s.f.tx = crt.NewCT_SerTx()
+	(*s.f.tx).SerTxChoice.V = &name
+}
+
+// SetNameReference sets the reference of the cell containing the name of the
+// series.
+func (s Series) SetNameReference(ref string) {
+	*s.f.tx = crt.NewCT_SerTx()
+	(*s.f.tx).SerTxChoice.StrRef = crt.NewCT_StrRef()
+	(*s.f.tx).SerTxChoice.StrRef.F = ref
+}
+
+// Properties returns the properties of the series.
+func (s Series) Properties() drawing.ShapeProperties {
+	if *s.f.spPr == nil {
+		*s.f.spPr = dml.NewCT_ShapeProperties()
+	}
+	return drawing.MakeShapeProperties(*s.f.spPr)
+}
+
+// Categories returns the categories of the series, the x values of scatter
+// and bubble series.
+func (s Series) Categories() CategoryAxisDataSource {
+	if *s.f.cat == nil {
+		*s.f.cat = crt.NewCT_AxDataSource()
+	}
+	return MakeAxisDataSource(*s.f.cat)
+}
+
+// Values returns the values of the series, the y values of scatter and bubble
+// series.
+func (s Series) Values() NumberDataSource {
+	if *s.f.val == nil {
+		*s.f.val = crt.NewCT_NumDataSource()
+	}
+	return MakeNumberDataSource(*s.f.val)
+}
+
+// BubbleSizes returns the bubble sizes of a bubble series.
+func (s Series) BubbleSizes() (NumberDataSource, bool) {
+	if s.f.bubbleSize == nil {
+		return NumberDataSource{}, false
+	}
+	if *s.f.bubbleSize == nil {
+		*s.f.bubbleSize = crt.NewCT_NumDataSource()
+	}
+	return MakeNumberDataSource(*s.f.bubbleSize), true
+}
+
+// CategoriesReference returns the reference of the cells containing the
+// categories of the series, or an empty string if they're given directly.
+func (s Series) CategoriesReference() string {
+	if *s.f.cat == nil {
+		return ""
+	}
+	return s.Categories().Reference()
+}
+
+// SetCategoriesReference sets the reference of the cells containing the
+// categories of the series, keeping their kind, i.e. numbers, strings or
+// multiple levels of strings. The cached categories are dropped.
+func (s Series) SetCategoriesReference(ref string) {
+	ds := s.Categories()
+	if ch := ds._ge.AxDataSourceChoice; ch != nil {
+		switch {
+		case ch.NumRef != nil:
+			ch.NumRef.F, ch.NumRef.NumCache = ref, nil
+			return
+		case ch.MultiLvlStrRef != nil:
+			ch.MultiLvlStrRef.F, ch.MultiLvlStrRef.MultiLvlStrCache = ref, nil
+			return
+		}
+	}
+	ds.SetLabelReference(ref)
+}
+
+// ValuesReference returns the reference of the cells containing the values of
+// the series, or an empty string if they're given directly.
+func (s Series) ValuesReference() string {
+	if *s.f.val == nil {
+		return ""
+	}
+	return s.Values().Reference()
+}
+
+// SetValuesReference sets the reference of the cells containing the values
+// of the series. The cached values are dropped.
+func (s Series) SetValuesReference(ref string) {
+	ds := s.Values()
+	ds.SetReference(ref)
+	ds._eeca.NumDataSourceChoice.NumLit = nil
+	ds._eeca.NumDataSourceChoice.NumRef.NumCache = nil
+}
+
+// Reference returns the reference of the cells containing the values, or an
+// empty string if they're given directly.
+func (n NumberDataSource) Reference() string {
+	if ch := n._eeca.NumDataSourceChoice; ch != nil && ch.NumRef != nil {
+		return ch.NumRef.F
+	}
+	return ""
+}
+
+// Values returns the values given directly or cached from the referenced
+// cells. Missing values are NaN.
+func (n NumberDataSource) Values() []float64 {
+	ch := n._eeca.NumDataSourceChoice
+	if ch == nil {
+		return nil
+	}
+	if ch.NumLit != nil {
+		return numDataValues(ch.NumLit)
+	}
+	if ch.NumRef != nil {
+		return numDataValues(ch.NumRef.NumCache)
+	}
+	return nil
+}
+
+func numDataValues(d *crt.CT_NumData) []float64 {
+	if d == nil {
+		return nil
+	}
+	n := 0
+	if d.PtCount != nil {
+		n = int(d.PtCount.ValAttr)
+	}
+	for _, pt := range d.Pt {
+		if int(pt.IdxAttr) >= n {
+			n = int(pt.IdxAttr) + 1
+		}
+	}
+	ret := make([]float64, n)
+	for i := range ret {
+		ret[i] = math.NaN()
+	}
+	for _, pt := range d.Pt {
+		if v, err := strconv.ParseFloat(pt.V, 64); err == nil {
+			ret[pt.IdxAttr] = v
+		}
+	}
+	return ret
+}
+
+// Reference returns the reference of the cells containing the categories, or
+// an empty string if they're given directly.
+func (c CategoryAxisDataSource) Reference() string {
+	ch := c._ge.AxDataSourceChoice
+	switch {
+	case ch == nil:
+		return ""
+	case ch.StrRef != nil:
+		return ch.StrRef.F
+	case ch.NumRef != nil:
+		return ch.NumRef.F
+	case ch.MultiLvlStrRef != nil:
+		return ch.MultiLvlStrRef.F
+	}
+	return ""
+}
+
+// Values returns the categories given directly or cached from the referenced
+// cells, the innermost level of multiple level categories. Numeric
+// categories are formatted without their number format.
+func (c CategoryAxisDataSource) Values() []string {
+	ch := c._ge.AxDataSourceChoice
+	if ch == nil {
+		return nil
+	}
+	var d *crt.CT_StrData
+	switch {
+	case ch.StrLit != nil:
+		d = ch.StrLit
+	case ch.StrRef != nil:
+		d = ch.StrRef.StrCache
+	case ch.MultiLvlStrRef != nil && ch.MultiLvlStrRef.MultiLvlStrCache != nil:
+		mc := ch.MultiLvlStrRef.MultiLvlStrCache
+		if len(mc.Lvl) > 0 {
+			d = &crt.CT_StrData{PtCount: mc.PtCount, Pt: mc.Lvl[0].Pt}
+		}
+	case ch.NumLit != nil, ch.NumRef != nil:
+		nd := ch.NumLit
+		if nd == nil {
+			nd = ch.NumRef.NumCache
+		}
+		ret := []string{}
+		for _, v := range numDataValues(nd) {
+			if math.IsNaN(v) {
+				ret = append(ret, "")
+			} else {
+				ret = append(ret, formatNumber(v))
+			}
+		}
+		return ret
+	}
+	if d == nil {
+		return nil
+	}
+	n := 0
+	if d.PtCount != nil {
+		n = int(d.PtCount.ValAttr)
+	}
+	for _, pt := range d.Pt {
+		if int(pt.IdxAttr) >= n {
+			n = int(pt.IdxAttr) + 1
+		}
+	}
+	ret := make([]string, n)
+	for _, pt := range d.Pt {
+		ret[pt.IdxAttr] = pt.V
+	}
+	return ret
+}
+
+// Min returns the minimum of the axis if it's fixed.
+func (v ValueAxis) Min() (float64, bool) { return scalingMin(v._cac.Scaling) }
+
+// Max returns the maximum of the axis if it's fixed.
+func (v ValueAxis) Max() (float64, bool) { return scalingMax(v._cac.Scaling) }
+
+// SetMin fixes the minimum of the axis.
+func (v ValueAxis) SetMin(min float64) {
+	if v._cac.Scaling == nil {
+		v._cac.Scaling = crt.NewCT_Scaling()
+	}
+	v._cac.Scaling.Min = &crt.CT_Double{ValAttr: min}
+}
+
+// SetMax fixes the maximum of the axis.
+func (v ValueAxis) SetMax(max float64) {
+	if v._cac.Scaling == nil {
+		v._cac.Scaling = crt.NewCT_Scaling()
+	}
+	v._cac.Scaling.Max = &crt.CT_Double{ValAttr: max}
+}
+
+// ClearMinMax lets the minimum and maximum of the axis be computed from the
+// values.
+func (v ValueAxis) ClearMinMax() {
+	if v._cac.Scaling != nil {
+		v._cac.Scaling.Min, v._cac.Scaling.Max = nil, nil
+	}
+}
+
+// NumberFormat returns the number format of the labels of the axis.
+func (v ValueAxis) NumberFormat() string { return numFmtCode(v._cac.NumFmt) }
+
+// SetNumberFormat sets the number format of the labels of the axis, unlinking
+// it from the format of the source cells.
+func (v ValueAxis) SetNumberFormat(code string) { v._cac.NumFmt = newNumFmt(code) }
+
+// Min returns the minimum of the axis if it's fixed.
+func (d DateAxis) Min() (float64, bool) { return scalingMin(d._bgcc.Scaling) }
+
+// Max returns the maximum of the axis if it's fixed.
+func (d DateAxis) Max() (float64, bool) { return scalingMax(d._bgcc.Scaling) }
+
+// SetMin fixes the minimum of the axis.
+func (d DateAxis) SetMin(min float64) {
+	if d._bgcc.Scaling == nil {
+		d._bgcc.Scaling = crt.NewCT_Scaling()
+	}
+	d._bgcc.Scaling.Min = &crt.CT_Double{ValAttr: min}
+}
+
+// SetMax fixes the maximum of the axis.
+func (d DateAxis) SetMax(max float64) {
+	if d._bgcc.Scaling == nil {
+		d._bgcc.Scaling = crt.NewCT_Scaling()
+	}
+	d._bgcc.Scaling.Max = &crt.CT_Double{ValAttr: max}
+}
+
+// NumberFormat returns the number format of the labels of the axis.
+func (d DateAxis) NumberFormat() string { return numFmtCode(d._bgcc.NumFmt) }
+
+// SetNumberFormat sets the number format of the labels of the axis, unlinking
+// it from the format of the source cells.
+func (d DateAxis) SetNumberFormat(code string) { d._bgcc.NumFmt = newNumFmt(code) }
+
+// NumberFormat returns the number format of the labels of the axis.
+func (c CategoryAxis) NumberFormat() string { return numFmtCode(c._bef.NumFmt) }
+
+// SetNumberFormat sets the number format of the labels of the axis, unlinking
+// it from the format of the source cells.
+func (c CategoryAxis) SetNumberFormat(code string) { c._bef.NumFmt = newNumFmt(code) }
+
+func scalingMin(s *crt.CT_Scaling) (float64, bool) {
+	if s == nil || s.Min == nil {
+		return 0, false
+	}
+	return s.Min.ValAttr, true
+}
+
+func scalingMax(s *crt.CT_Scaling) (float64, bool) {
+	if s == nil || s.Max == nil {
+		return 0, false
+	}
+	return s.Max.ValAttr, true
+}
+
+func numFmtCode(f *crt.CT_NumFmt) string {
+	if f == nil {
+		return ""
+	}
+	return f.FormatCodeAttr
+}
+
+func newNumFmt(code string) *crt.CT_NumFmt {
+	f := crt.NewCT_NumFmt()
+	f.FormatCodeAttr = code
+	f.SourceLinkedAttr = unioffice.Bool(false)
+	return f
+}
@@ -0,0 +1,245 @@
+package chart
+
+import (
+	"encoding/xml"
+	"fmt"
+	"math"
+	"testing"
+
+	crt "github.com/yaklabco/unioffice/v2/schema/soo/dml/chart"
+)
+
+// testBarChart is a bar chart as written by Excel, with cached data and a
+// missing value.
+const testBarChart = `<c:chartSpace xmlns:c="http://schemas.openxmlformats.org/drawingml/2006/chart" xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main">
+<c:chart>
+<c:title><c:tx><c:rich><a:bodyPr/><a:p><a:r><a:t>Sales</a:t></a:r></a:p><a:p><a:r><a:t>2024</a:t></a:r></a:p></c:rich></c:tx><c:overlay val="0"/></c:title>
+<c:plotArea>
+<c:barChart>
+<c:barDir val="col"/><c:grouping val="clustered"/>
+<c:ser>
+<c:idx val="0"/><c:order val="0"/>
+<c:tx><c:strRef><c:f>Sheet1!$B$1</c:f><c:strCache><c:ptCount val="1"/><c:pt idx="0"><c:v>Revenue</c:v></c:pt></c:strCache></c:strRef></c:tx>
+<c:cat><c:strRef><c:f>Sheet1!$A$2:$A$4</c:f><c:strCache><c:ptCount val="3"/><c:pt idx="0"><c:v>Q1</c:v></c:pt><c:pt idx="2"><c:v>Q3</c:v></c:pt></c:strCache></c:strRef></c:cat>
+<c:val><c:numRef><c:f>Sheet1!$B$2:$B$4</c:f><c:numCache><c:formatCode>General</c:formatCode><c:ptCount val="3"/><c:pt idx="0"><c:v>10</c:v></c:pt><c:pt idx="1"><c:v>20.5</c:v></c:pt></c:numCache></c:numRef></c:val>
+</c:ser>
+<c:ser>
+<c:idx val="1"/><c:order val="1"/>
+<c:tx><c:v>Costs</c:v></c:tx>
+<c:val><c:numLit><c:ptCount val="2"/><c:pt idx="0"><c:v>1</c:v></c:pt><c:pt idx="1"><c:v>2</c:v></c:pt></c:numLit></c:val>
+</c:ser>
+<c:axId val="10"/><c:axId val="20"/>
+</c:barChart>
+<c:catAx><c:axId val="10"/><c:scaling><c:orientation val="minMax"/></c:scaling><c:delete val="0"/><c:axPos val="b"/><c:crossAx val="20"/></c:catAx>
+<c:valAx><c:axId val="20"/><c:scaling><c:orientation val="minMax"/><c:max val="50"/></c:scaling><c:delete val="0"/><c:axPos val="l"/><c:numFmt formatCode="0.0" sourceLinked="0"/><c:crossAx val="10"/></c:valAx>
+</c:plotArea>
+</c:chart>
+</c:chartSpace>`
+
+func readTestChart(t *testing.T) Chart {
+	cs := crt.NewChartSpace()
+	if err := xml.Unmarshal([]byte(testBarChart), cs); err != nil {
+		t.Fatalf("error decoding chart: %s", err)
+	}
+	return MakeChart(cs)
+}
+
+// testCells is a CellSource of fixed values.
+type testCells map[string][][]CellValue
+
+func (c testCells) ChartValues(ref string) ([][]CellValue, error) {
+	v, ok := c[ref]
+	if !ok {
+		return nil, fmt.Errorf("unknown reference %s", ref)
+	}
+	return v, nil
+}
+
+func TestChartRead(t *testing.T) {
+	c := readTestChart(t)
+	if title, ok := c.Title(); !ok || title.Text() != "Sales\n2024" {
+		t.Errorf("unexpected title")
+	}
+	plots := c.Plots()
+	if len(plots) != 1 || plots[0].Type() != PlotTypeBar {
+		t.Fatalf("expected a bar plot")
+	}
+	if axes := plots[0].Axes(); len(axes) != 2 || axes[0].AxisID() != 10 || axes[1].AxisID() != 20 {
+		t.Errorf("unexpected plot axes")
+	}
+	ser := plots[0].Series()
+	if len(ser) != 2 {
+		t.Fatalf("expected 2 series, got %d", len(ser))
+	}
+	s := ser[0]
+	if s.Index() != 0 || s.Order() != 0 || s.Name() != "Revenue" || s.NameReference() != "Sheet1!$B$1" {
+		t.Errorf("unexpected name %q of %q", s.Name(), s.NameReference())
+	}
+	if s.CategoriesReference() != "Sheet1!$A$2:$A$4" || s.ValuesReference() != "Sheet1!$B$2:$B$4" {
+		t.Errorf("unexpected references %q %q", s.CategoriesReference(), s.ValuesReference())
+	}
+	if cats := s.Categories().Values(); len(cats) != 3 || cats[0] != "Q1" || cats[1] != "" || cats[2] != "Q3" {
+		t.Errorf("unexpected categories %q", cats)
+	}
+	if vals := s.Values().Values(); len(vals) != 3 || vals[0] != 10 || vals[1] != 20.5 || !math.IsNaN(vals[2]) {
+		t.Errorf("unexpected values %v", vals)
+	}
+	if s := ser[1]; s.Name() != "Costs" || s.NameReference() != "" || s.ValuesReference() != "" || len(s.Values().Values()) != 2 {
+		t.Errorf("unexpected literal series")
+	}
+	if _, ok := s.BubbleSizes(); ok {
+		t.Errorf("expected no bubble sizes on a bar series")
+	}
+
+	a, ok := c.Axis(20)
+	if !ok {
+		t.Fatal("expected value axis")
+	}
+	va, ok := a.(ValueAxis)
+	if !ok {
+		t.Fatalf("expected a value axis, got %T", a)
+	}
+	if max, ok := va.Max(); !ok || max != 50 {
+		t.Errorf("unexpected maximum %v", max)
+	}
+	if _, ok := va.Min(); ok {
+		t.Errorf("expected no minimum")
+	}
+	if va.NumberFormat() != "0.0" {
+		t.Errorf("unexpected number format %q", va.NumberFormat())
+	}
+	if _, ok := c.Axis(30); ok {
+		t.Errorf("expected no axis 30")
+	}
+}
+
+func TestChartModify(t *testing.T) {
+	c := readTestChart(t)
+	p := c.Plots()[0]
+	s := p.Series()[0]
+	s.SetName("Income")
+	s.SetValuesReference("Sheet2!$C$2:$C$3")
+	s.SetCategoriesReference("Sheet2!$A$2:$A$3")
+	if s.Name() != "Income" || s.NameReference() != "" {
+		t.Errorf("unexpected name %q", s.Name())
+	}
+	if s.ValuesReference() != "Sheet2!$C$2:$C$3" || len(s.Values().Values()) != 0 {
+		t.Errorf("expected the cached values to be dropped")
+	}
+	if s.CategoriesReference() != "Sheet2!$A$2:$A$3" || len(s.Categories().Values()) != 0 {
+		t.Errorf("expected the cached categories to be dropped")
+	}
+
+	src := testCells{
+		"Sheet2!$C$2:$C$3": {{{Number: 3, IsNumber: true, Text: "3"}}, {{Number: 4, IsNumber: true, Text: "4"}}},
+		"Sheet2!$A$2:$A$3": {{{Text: "East"}}, {{Text: "West"}}},
+	}
+	if err := c.RefreshCaches(src); err != nil {
+		t.Fatalf("error refreshing caches: %s", err)
+	}
+	if vals := s.Values().Values(); len(vals) != 2 || vals[1] != 4 {
+		t.Errorf("unexpected refreshed values %v", vals)
+	}
+	if cats := s.Categories().Values(); len(cats) != 2 || cats[1] != "West" {
+		t.Errorf("unexpected refreshed categories %q", cats)
+	}
+
+	va, _ := c.Axis(20)
+	va.(ValueAxis).SetMin(-5)
+	va.(ValueAxis).ClearMinMax()
+	if _, ok := va.(ValueAxis).Max(); ok {
+		t.Errorf("expected the maximum to be cleared")
+	}
+
+	p.RemoveSeries(p.Series()[1])
+	if ser := p.Series(); len(ser) != 1 || ser[0].Name() != "Income" {
+		t.Errorf("expected the second series to be removed")
+	}
+	if err := c.X().Validate(); err != nil {
+		t.Errorf("invalid chart: %s", err)
+	}
+}
+
+func TestPlotSetType(t *testing.T) {
+	c := readTestChart(t)
+	p := c.Plots()[0]
+	if err := p.SetType(PlotTypeLine); err != nil {
+		t.Fatalf("error converting to line: %s", err)
+	}
+	if p.Type() != PlotTypeLine || len(c.Plots()) != 1 {
+		t.Fatalf("expected a single line plot")
+	}
+	ser := p.Series()
+	if len(ser) != 2 || ser[0].Name() != "Revenue" || ser[0].ValuesReference() != "Sheet1!$B$2:$B$4" || ser[1].Index() != 1 {
+		t.Errorf("expected the series to be kept")
+	}
+	if axes := p.Axes(); len(axes) != 2 || axes[0].AxisID() != 10 || axes[1].AxisID() != 20 {
+		t.Errorf("expected the axes to be kept")
+	}
+
+	if err := p.SetType(PlotTypeScatter); err != nil {
+		t.Fatalf("error converting to scatter: %s", err)
+	}
+	if a, _ := c.Axis(10); a == nil {
+		t.Fatal("expected axis 10")
+	} else if _, ok := a.(ValueAxis); !ok {
+		t.Errorf("expected the horizontal axis of a scatter plot to be a value axis, got %T", a)
+	}
+	if err := p.SetType(PlotTypeBar); err != nil {
+		t.Fatalf("error converting to bar: %s", err)
+	}
+	if a, _ := c.Axis(10); a == nil {
+		t.Fatal("expected axis 10")
+	} else if _, ok := a.(CategoryAxis); !ok {
+		t.Errorf("expected the category axis to be restored, got %T", a)
+	}
+	if err := c.X().Validate(); err != nil {
+		t.Errorf("invalid chart: %s", err)
+	}
+
+	if err := p.SetType(PlotTypePie); err != nil {
+		t.Fatalf("error converting to pie: %s", err)
+	}
+	if len(c.Axes()) != 0 || len(p.Axes()) != 0 || p.Series()[0].CategoriesReference() != "Sheet1!$A$2:$A$4" {
+		t.Errorf("expected a pie plot without axes")
+	}
+
+	c = MakeChart(crt.NewChartSpace())
+	c.AddBubbleChart()
+	if err := c.Plots()[0].SetType(PlotTypeBar); err == nil {
+		t.Errorf("expected error converting a bubble plot")
+	}
+	if _, ok := c.Plots()[0].addSeries(); ok {
+		t.Errorf("expected no series added to a bubble plot")
+	}
+}
+
+func TestPlotSetTypeSharedAxes(t *testing.T) {
+	c := readTestChart(t)
+	c.AddLineChart()
+	// a combination chart, the line plot is drawn against the axes of the bar
+	// plot
+	p := c.Plots()[1]
+	p.setAxisIDs(c.Plots()[0].axisIDs())
+
+	if err := p.SetType(PlotTypeScatter); err == nil {
+		t.Errorf("expected error converting a plot sharing its category axis to scatter")
+	}
+	if p.Type() != PlotTypeLine {
+		t.Errorf("expected the plot to be left alone")
+	}
+	if a, _ := c.Axis(10); a == nil {
+		t.Fatal("expected axis 10")
+	} else if _, ok := a.(CategoryAxis); !ok {
+		t.Errorf("expected the shared axis to be kept, got %T", a)
+	}
+	if err := p.SetType(PlotTypeArea); err != nil {
+		t.Errorf("error converting to area: %s", err)
+	}
+	if err := p.SetType(PlotTypePie); err != nil {
+		t.Errorf("error converting to pie: %s", err)
+	}
+	if len(c.Axes()) != 2 || len(c.Plots()[0].Axes()) != 2 {
+		t.Errorf("expected the axes of the bar plot to be kept")
+	}
+}
@@ -0,0 +1,260 @@
+package document
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/yaklabco/unioffice/v2"
+	uchart "github.com/yaklabco/unioffice/v2/chart"
+	"github.com/yaklabco/unioffice/v2/color"
+	"github.com/yaklabco/unioffice/v2/common"
+	"github.com/yaklabco/unioffice/v2/common/tempstorage"
+	"github.com/yaklabco/unioffice/v2/measurement"
+	"github.com/yaklabco/unioffice/v2/schema/schemas.microsoft.com/office/drawing/2014/chartex"
+	"github.com/yaklabco/unioffice/v2/schema/soo/dml"
+	"github.com/yaklabco/unioffice/v2/schema/soo/dml/picture"
+	"github.com/yaklabco/unioffice/v2/schema/soo/wml"
+)
+
+// chartExPart is a chartex part of the document, kept as an extra file as the
+// package writer doesn't support them.
+type chartExPart struct {
+	path string
+	x    *chartex.ChartSpace
+}
+
+// ChartExDrawing is the inline drawing displaying a chart added with
+// Run.AddChartEx.
+type ChartExDrawing struct {
+	d  *Document
+	x  *wml.WdInline
+	ac *wml.AlternateContentSVGRun
+	c  uchart.ChartEx
+}
+
+// X returns the inner wrapped XML type.
+func (c ChartExDrawing) X() *wml.WdInline { return c.x }
+
+// Chart returns the chart displayed by the drawing.
+func (c ChartExDrawing) Chart() uchart.ChartEx { return c.c }
+
+// SetSize sets the width and height of the drawing.
+func (c ChartExDrawing) SetSize(w, h measurement.Distance) {
+	c.x.Extent.CxAttr = int64(w / measurement.EMU)
+	c.x.Extent.CyAttr = int64(h / measurement.EMU)
+}
+
+// SetFallbackImage sets the image displayed instead of the chart by the
+// versions of Word that don't support it, e.g. a rendering of the chart. The
+// image must have been added to the document.
+func (c ChartExDrawing) SetFallbackImage(img common.ImageRef) error {
+	found := false
+	for _, im := range c.d.Images {
+		if im == img {
+			found = true
+		}
+	}
+	if !found || img.RelID() == "" {
+		return errors.New("image not found in document")
+	}
+	inl := wml.NewWdInline()
+	inl.CNvGraphicFramePr = dml.NewCT_NonVisualGraphicFrameProperties()
+	inl.DistTAttr = unioffice.Uint32(0)
+	inl.DistLAttr = unioffice.Uint32(0)
+	inl.DistBAttr = unioffice.Uint32(0)
+	inl.DistRAttr = unioffice.Uint32(0)
+	// the fallback shares the extent of the chart so it follows resizes
+	inl.Extent = c.x.Extent
+	inl.DocPr.IdAttr = c.x.DocPr.IdAttr
+	inl.DocPr.NameAttr = c.x.DocPr.NameAttr
+	inl.Graphic = dml.NewGraphic()
+	inl.Graphic.GraphicData = dml.NewCT_GraphicalObjectData()
+	inl.Graphic.GraphicData.UriAttr = "http://schemas.openxmlformats.org/drawingml/2006/picture"
+
+	pic := picture.NewPic()
+	pic.NvPicPr.CNvPr.IdAttr = c.x.DocPr.IdAttr
+	pic.NvPicPr.CNvPr.NameAttr = c.x.DocPr.NameAttr
+	pic.BlipFill = dml.NewCT_BlipFillProperties()
+	pic.BlipFill.Blip = dml.NewCT_Blip()
+	pic.BlipFill.Blip.EmbedAttr = unioffice.String(img.RelID())
+	pic.BlipFill.FillModePropertiesChoice.Stretch = dml.NewCT_StretchInfoProperties()
+	pic.BlipFill.FillModePropertiesChoice.Stretch.FillRect = dml.NewCT_RelativeRect()
+	pic.SpPr = dml.NewCT_ShapeProperties()
+	pic.SpPr.Xfrm = dml.NewCT_Transform2D()
+	pic.SpPr.Xfrm.Off = dml.NewCT_Point2D()
+	pic.SpPr.Xfrm.Off.XAttr.ST_CoordinateUnqualified = unioffice.Int64(0)
+	pic.SpPr.Xfrm.Off.YAttr.ST_CoordinateUnqualified = unioffice.Int64(0)
+	pic.SpPr.Xfrm.Ext = c.x.Extent
+	pic.SpPr.GeometryChoice.PrstGeom = dml.NewCT_PresetGeometry2D()
+	pic.SpPr.GeometryChoice.PrstGeom.PrstAttr = dml.ST_ShapeTypeRect
+	inl.Graphic.GraphicData.Any = append(inl.Graphic.GraphicData.Any, pic)
+
+	drawing := wml.NewCT_Drawing()
+	drawing.DrawingChoice = append(drawing.DrawingChoice, &wml.CT_DrawingChoice{Inline: inl})
+	c.ac.Fallback.Drawing = drawing
+	return nil
+}
+
+// AddChartEx adds a chart of one of the types introduced by Office 2016, e.g.
+// a waterfall, inline to the run. The chart is written within an alternate
+// content block, versions of Word older than 2016 display nothing instead of
+// the chart unless a fallback image is set on the returned drawing.
+func (r Run) AddChartEx() (ChartExDrawing, error) {
+	d := r._gdedf
+	cs := chartex.NewChartSpace()
+	partPath, n := d.nextPartPath("word/charts/chartEx%d.xml")
+	part := &chartExPart{path: partPath, x: cs}
+	if err := d.writeExtraFile(part.path, part.x); err != nil {
+		return ChartExDrawing{}, err
+	}
+	d._chartEx = append(d._chartEx, part)
+	rel := d._ead.AddRelationship(fmt.Sprintf("charts/chartEx%d.xml", n), chartex.RelationshipType)
+	d.ContentTypes.AddOverride("/"+partPath, chartex.ContentType)
+
+	drawing := wml.NewCT_Drawing()
+	inl := wml.NewWdInline()
+	drawing.DrawingChoice = append(drawing.DrawingChoice, &wml.CT_DrawingChoice{Inline: inl})
+	inl.CNvGraphicFramePr = dml.NewCT_NonVisualGraphicFrameProperties()
+	inl.DistTAttr = unioffice.Uint32(0)
+	inl.DistLAttr = unioffice.Uint32(0)
+	inl.DistBAttr = unioffice.Uint32(0)
+	inl.DistRAttr = unioffice.Uint32(0)
+	id := 0x7FFFFFFF & rand.Uint32()
+	inl.DocPr.IdAttr = id
+	inl.DocPr.NameAttr = fmt.Sprintf("Chart %d", n)
+	inl.Graphic = dml.NewGraphic()
+	inl.Graphic.GraphicData = dml.NewCT_GraphicalObjectData()
+	inl.Graphic.GraphicData.UriAttr = chartex.Namespace
+	inl.Graphic.GraphicData.Any = []unioffice.Any{&chartex.Chart{IdAttr: rel.ID()}}
+
+	choice := wml.NewAC_ChoiceRun()
+	prefix, ns := cs.Requires()
+	choice.SetRequires(prefix)
+	choice.Drawing = drawing
+	ac := &wml.AlternateContentSVGRun{
+		Choice:     choice,
+		Fallback:   &wml.FallbackDrawing{},
+		Namespaces: map[string]string{prefix: ns},
+	}
+	r._bbdb.Extra = append(r._bbdb.Extra, ac)
+
+	c := uchart.MakeChartEx(cs)
+	c.Properties().SetSolidFill(color.White)
+	cd := ChartExDrawing{d: d, x: inl, ac: ac, c: c}
+	cd.SetSize(6*measurement.Inch, 4*measurement.Inch)
+	return cd, nil
+}
+
+// ChartExCharts returns the chartex charts of the document, both those read
+// with it and those added with Run.AddChartEx.
+func (d *Document) ChartExCharts() []uchart.ChartEx {
+	ret := []uchart.ChartEx{}
+	for _, part := range d._chartEx {
+		ret = append(ret, uchart.MakeChartEx(part.x))
+	}
+	return ret
+}
+
+// nextPartPath returns the first path of a numbered part not in the package
+// along with its number.
+func (d *Document) nextPartPath(format string) (string, int) {
+	for n := 1; ; n++ {
+		taken := false
+		name := fmt.Sprintf(format, n)
+		for _, ef := range d.ExtraFiles {
+			if ef.ZipPath == name {
+				taken = true
+			}
+		}
+		if !taken {
+			return name, n
+		}
+	}
+}
+
+// writeExtraFile encodes a part into the extra files of the document,
+// replacing any previous content.
+func (d *Document) writeExtraFile(zipPath string, v interface{}) error {
+	f, err := tempstorage.TempFile(d.TmpPath, "xml")
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := io.WriteString(f, xml.Header); err != nil {
+		return err
+	}
+	if err := xml.NewEncoder(f).Encode(v); err != nil {
+		return err
+	}
+	for i, ef := range d.ExtraFiles {
+		if ef.ZipPath == zipPath {
+			d.ExtraFiles[i].StoragePath = f.Name()
+			return nil
+		}
+	}
+	d.ExtraFiles = append(d.ExtraFiles, common.ExtraFile{ZipPath: zipPath, StoragePath: f.Name()})
+	return nil
+}
+
+// extraFileData returns the content of an extra file of the document and
+// whether it was found.
+func (d *Document) extraFileData(zipPath string) ([]byte, bool, error) {
+	for _, ef := range d.ExtraFiles {
+		if ef.ZipPath != zipPath {
+			continue
+		}
+		f, err := tempstorage.Open(ef.StoragePath)
+		if err != nil {
+			return nil, false, err
+		}
+		defer f.Close()
+		data, err := io.ReadAll(f)
+		return data, err == nil, err
+	}
+	return nil, false, nil
+}
+
+// flushChartEx writes the chartex parts to the extra files so they're saved
+// with the document.
+func (d *Document) flushChartEx() error {
+	for _, part := range d._chartEx {
+		if err := d.writeExtraFile(part.path, part.x); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadChartEx decodes the chartex parts read with the document so they can be
+// modified, they're written back on save.
+func (d *Document) loadChartEx() error {
+	known := map[string]bool{}
+	for _, part := range d._chartEx {
+		known[part.path] = true
+	}
+	for _, ef := range d.ExtraFiles {
+		dir, name := path.Split(ef.ZipPath)
+		if dir != "word/charts/" || known[ef.ZipPath] || !strings.HasPrefix(name, "chartEx") || path.Ext(name) != ".xml" {
+			continue
+		}
+		if _, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(name, "chartEx"), ".xml")); err != nil {
+			continue
+		}
+		data, _, err := d.extraFileData(ef.ZipPath)
+		if err != nil {
+			return err
+		}
+		cs := chartex.NewChartSpace()
+		if err := xml.Unmarshal(data, cs); err != nil {
+			return fmt.Errorf("error decoding %s: %s", ef.ZipPath, err)
+		}
+		d._chartEx = append(d._chartEx, &chartExPart{path: ef.ZipPath, x: cs})
+	}
+	return nil
+}
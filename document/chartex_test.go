@@ -0,0 +1,131 @@
+package document
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/yaklabco/unioffice/v2/common"
+	"github.com/yaklabco/unioffice/v2/measurement"
+)
+
+func TestAddChartEx_XMLStructure(t *testing.T) {
+	doc := New()
+	run := doc.AddParagraph().AddRun()
+	cd, err := run.AddChartEx()
+	if err != nil {
+		t.Fatalf("AddChartEx: %v", err)
+	}
+	cd.SetSize(4*measurement.Inch, 3*measurement.Inch)
+
+	if len(run.X().Extra) != 1 {
+		t.Fatalf("expected one Extra item on run, got %d", len(run.X().Extra))
+	}
+	xmlData, err := xml.Marshal(run.X().Extra[0])
+	if err != nil {
+		t.Fatalf("xml.Marshal Extra: %v", err)
+	}
+	xmlStr := string(xmlData)
+
+	if !strings.Contains(xmlStr, "AlternateContent") {
+		t.Error("expected mc:AlternateContent in XML")
+	}
+	if !strings.Contains(xmlStr, `Requires="cx1"`) || !strings.Contains(xmlStr, `xmlns:cx1="http://schemas.microsoft.com/office/drawing/2015/9/8/chartex"`) {
+		t.Errorf("expected mc:Choice to require cx1, got %s", xmlStr)
+	}
+	if !strings.Contains(xmlStr, "http://schemas.microsoft.com/office/drawing/2014/chartex") {
+		t.Error("expected the chartex graphic data in XML")
+	}
+	if !strings.Contains(xmlStr, "Fallback") {
+		t.Error("expected mc:Fallback in XML")
+	}
+	if strings.Contains(xmlStr, "blip") {
+		t.Error("expected no fallback image")
+	}
+}
+
+func TestAddChartEx_FallbackImage(t *testing.T) {
+	doc := New()
+	pngImg, err := common.ImageFromBytes(testPNGData(t, 200, 100))
+	if err != nil {
+		t.Fatalf("ImageFromBytes: %v", err)
+	}
+	img, err := doc.AddImage(pngImg)
+	if err != nil {
+		t.Fatalf("AddImage: %v", err)
+	}
+	run := doc.AddParagraph().AddRun()
+	cd, err := run.AddChartEx()
+	if err != nil {
+		t.Fatalf("AddChartEx: %v", err)
+	}
+	if err := cd.SetFallbackImage(common.ImageRef{}); err == nil {
+		t.Error("expected an error for an image not in the document")
+	}
+	if err := cd.SetFallbackImage(img); err != nil {
+		t.Fatalf("SetFallbackImage: %v", err)
+	}
+	cd.SetSize(4*measurement.Inch, 3*measurement.Inch)
+
+	xmlData, err := xml.Marshal(run.X().Extra[0])
+	if err != nil {
+		t.Fatalf("xml.Marshal Extra: %v", err)
+	}
+	xmlStr := string(xmlData)
+	fallback := xmlStr[strings.Index(xmlStr, "Fallback"):]
+	if !strings.Contains(fallback, `embed="`+img.RelID()+`"`) {
+		t.Errorf("expected the fallback to display the image, got %s", fallback)
+	}
+	// the fallback follows the size of the chart
+	if !strings.Contains(fallback, `cx="3657600"`) || !strings.Contains(fallback, `cy="2743200"`) {
+		t.Errorf("expected the fallback to be resized, got %s", fallback)
+	}
+}
+
+func TestAddChartEx_Save(t *testing.T) {
+	doc := New()
+	if _, err := doc.AddParagraph().AddRun().AddChartEx(); err != nil {
+		t.Fatalf("AddChartEx: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := doc.Save(&buf); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("zip.NewReader: %v", err)
+	}
+	var hasChart, hasOverride bool
+	for _, f := range zr.File {
+		switch f.Name {
+		case "word/charts/chartEx1.xml":
+			hasChart = true
+		case "[Content_Types].xml":
+			rc, err := f.Open()
+			if err != nil {
+				t.Fatalf("Open: %v", err)
+			}
+			var ct bytes.Buffer
+			ct.ReadFrom(rc)
+			rc.Close()
+			hasOverride = strings.Contains(ct.String(), "/word/charts/chartEx1.xml")
+		}
+	}
+	if !hasChart {
+		t.Error("expected the chartex part in ZIP")
+	}
+	if !hasOverride {
+		t.Error("expected a content type override for the chartex part")
+	}
+
+	read, err := Read(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if len(read.ChartExCharts()) != 1 {
+		t.Errorf("expected the chartex chart to be read back, got %d", len(read.ChartExCharts()))
+	}
+}
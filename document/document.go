@@ -1161,7 +1161,7 @@ func (_gafgd RunProperties )SetAllCaps (b bool ){if !b {_gafgd ._ccfdc .Caps =ni
 // format. It can be opened from a file on disk and modified, or created from
 // scratch.
 type Document struct{_da .DocBase ;_bbe *_cc .Document ;Settings Settings ;Numbering Numbering ;Styles Styles ;_ade []*_cc .Hdr ;_bbcd []_da .Relationships ;_bgc []*_cc .Ftr ;_dga []_da .Relationships ;_ead _da .Relationships ;_bdc []*_ab .Theme ;_cdac *_cc .WebSettings ;
-_ecbgf *_cc .Fonts ;_aaff _da .Relationships ;_bdcb *_cc .Endnotes ;_gbd *_cc .Footnotes ;_efa []*_fd .Control ;_dbg []*chart ;_ebd *_cc .Comments ;_cdbe *_bc .CommentsEx ;_bcae *_ce .CommentsExtensible ;_fgcd *_ca .CommentsIds ;_edgg string ;};func (_ceba *Document )ensureTableGrids (){for _ ,_cfag :=range _ceba .Tables (){_cfag .EnsureGridColumns ();
+_ecbgf *_cc .Fonts ;_aaff _da .Relationships ;_bdcb *_cc .Endnotes ;_gbd *_cc .Footnotes ;_efa []*_fd .Control ;_dbg []*chart ;_ebd *_cc .Comments ;_cdbe *_bc .CommentsEx ;_bcae *_ce .CommentsExtensible ;_fgcd *_ca .CommentsIds ;_edgg string ;_chartEx []*chartExPart ;};func (_ceba *Document )ensureTableGrids (){for _ ,_cfag :=range _ceba .Tables (){_cfag .EnsureGridColumns ();
 };for _ ,_fbcg :=range _ceba .Headers (){for _ ,_facgc :=range _fbcg .Tables (){_facgc .EnsureGridColumns ();};};for _ ,_bgec :=range _ceba .Footers (){for _ ,_fgdb :=range _bgec .Tables (){_fgdb .EnsureGridColumns ();};};};
 
 // Style return the table style.
@@ -1337,7 +1337,7 @@ if _geeb !=nil {return nil ,_geeb ;};_fdb .TmpPath =_bbd ;_bfga ,_geeb :=_e .New
 _ffec =append (_ffec ,_bfga .File ...);_agbdc :=false ;for _ ,_dega :=range _ffec {if _dega .Name =="\u0064\u006f\u0063\u0050ro\u0070\u0073\u002f\u0063\u0075\u0073\u0074\u006f\u006d\u002e\u0078\u006d\u006c"{_agbdc =true ;break ;};};if _agbdc {_fdb .CreateCustomProperties ();
 };_edfb :=_fdb ._bbe .ConformanceAttr ;_efdc :=_aaa .DecodeMap {};_efdc .SetOnNewRelationshipFunc (_fdb .onNewRelationship );_efdc .AddTarget (_c .ContentTypesFilename ,_fdb .ContentTypes .X (),"",0);_efdc .AddTarget (_c .BaseRelsFilename ,_fdb .Rels .X (),"",0);
 if _dfdg :=_efdc .Decode (_ffec );_dfdg !=nil {return nil ,_dfdg ;};_fdb ._bbe .ConformanceAttr =_edfb ;for _ ,_ceec :=range _ffec {if _ceec ==nil {continue ;};if _cfbg :=_fdb .AddExtraFileFromZip (_ceec );_cfbg !=nil {return nil ,_cfbg ;};};if _agbdc {_gcc :=false ;
-for _ ,_eded :=range _fdb .Rels .X ().Relationship {if _eded .TargetAttr =="\u0064\u006f\u0063\u0050ro\u0070\u0073\u002f\u0063\u0075\u0073\u0074\u006f\u006d\u002e\u0078\u006d\u006c"{_gcc =true ;break ;};};if !_gcc {_fdb .AddCustomRelationships ();};};return _fdb ,nil ;
+for _ ,_eded :=range _fdb .Rels .X ().Relationship {if _eded .TargetAttr =="\u0064\u006f\u0063\u0050ro\u0070\u0073\u002f\u0063\u0075\u0073\u0074\u006f\u006d\u002e\u0078\u006d\u006c"{_gcc =true ;break ;};};if !_gcc {_fdb .AddCustomRelationships ();};};if _ebcf :=_fdb .loadChartEx ();_ebcf !=nil {return nil ,_ebcf ;};return _fdb ,nil ;
 };
 
 // Control returns an *axcontrol.Control object contained in the run or the nil value in case of no controls.
@@ -1945,7 +1945,7 @@ if _aged :=_aaa .AddFileFromBytes (_beg ,_cdbb ,_cgdf );_aged !=nil {return _age
 if _gbdd :=_aaa .MarshalXML (_beg ,_aaeg ,_gcfe );_gbdd !=nil {return _gbdd ;};if !_gafac ._bbcd [_ddd ].IsEmpty (){if _gge :=_aaa .MarshalXML (_beg ,_aaa .RelationsPathFor (_aaeg ),_gafac ._bbcd [_ddd ].X ());_gge !=nil {return _gge ;};};};for _adg ,_dfcf :=range _gafac ._bgc {_affg :=_c .AbsoluteFilename (_dfcc ,_c .FooterType ,_adg +1);
 if _abad :=_aaa .MarshalXMLByTypeIndex (_beg ,_dfcc ,_c .FooterType ,_adg +1,_dfcf );_abad !=nil {return _abad ;};if !_gafac ._dga [_adg ].IsEmpty (){if _bfc :=_aaa .MarshalXML (_beg ,_aaa .RelationsPathFor (_affg ),_gafac ._dga [_adg ].X ());_bfc !=nil {return _bfc ;
 };};};for _fgb ,_afda :=range _gafac .Images {if _ecag :=_da .AddImageToZip (_beg ,_afda ,_fgb +1,_c .DocTypeDocument );_ecag !=nil {return _ecag ;};};for _cedd ,_dced :=range _gafac ._dbg {_dec :=_c .AbsoluteFilename (_dfcc ,_c .ChartType ,_cedd +1);if _eecc :=_aaa .MarshalXML (_beg ,_dec ,_dced ._agcb );
-_eecc !=nil {return _eecc ;};};if _caed :=_aaa .MarshalXML (_beg ,_c .ContentTypesFilename ,_gafac .ContentTypes .X ());_caed !=nil {return _caed ;};if _dac :=_gafac .WriteExtraFiles (_beg );_dac !=nil {return _dac ;};return _beg .Close ();};
+_eecc !=nil {return _eecc ;};};if _caed :=_aaa .MarshalXML (_beg ,_c .ContentTypesFilename ,_gafac .ContentTypes .X ());_caed !=nil {return _caed ;};if _dac :=_gafac .flushChartEx ();_dac !=nil {return _dac ;};if _dac :=_gafac .WriteExtraFiles (_beg );_dac !=nil {return _dac ;};return _beg .Close ();};
 
 // AddFieldWithFormatting adds a field (automatically computed text) to the
 // document with field specifc formatting.
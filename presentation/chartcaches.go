@@ -0,0 +1,79 @@
+package presentation
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+
+	uchart "github.com/yaklabco/unioffice/v2/chart"
+	"github.com/yaklabco/unioffice/v2/common/tempstorage"
+	"github.com/yaklabco/unioffice/v2/schema/soo/pkg/relationships"
+	"github.com/yaklabco/unioffice/v2/spreadsheet"
+)
+
+// RefreshChartCaches fills the caches of the data of the charts of the
+// presentation from the cells of the workbooks embedded with them, see
+// chart.Chart.RefreshCaches. Charts without an embedded workbook are left
+// alone. All charts are refreshed even if some of them fail, the first error
+// being returned.
+func (p *Presentation) RefreshChartCaches() error {
+	var first error
+	for _, c := range p._gcd {
+		if err := p.refreshChartCaches(c); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+func (p *Presentation) refreshChartCaches(c *chart) error {
+	cs := c.X()
+	if cs.ExternalData == nil || cs.ExternalData.IdAttr == "" || c._aba == "" {
+		return nil
+	}
+	chartPath := path.Join("ppt/slides", c._aba)
+	rels := relationships.NewRelationships()
+	relsPath := path.Join(path.Dir(chartPath), "_rels", path.Base(chartPath)+".rels")
+	data, ok, err := p.extraFileData(relsPath)
+	if err != nil || !ok {
+		return err
+	}
+	if err := xml.Unmarshal(data, rels); err != nil {
+		return fmt.Errorf("error decoding %s: %s", relsPath, err)
+	}
+	target := ""
+	for _, r := range rels.Relationship {
+		if r.IdAttr == cs.ExternalData.IdAttr {
+			target = path.Join(path.Dir(chartPath), r.TargetAttr)
+		}
+	}
+	data, ok, err = p.extraFileData(target)
+	if err != nil || !ok {
+		return err
+	}
+	wb, err := spreadsheet.Read(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return fmt.Errorf("error reading %s: %s", target, err)
+	}
+	defer wb.Close()
+	return uchart.MakeChart(cs).RefreshCaches(wb)
+}
+
+// extraFileData returns the content of an extra file of the presentation.
+func (p *Presentation) extraFileData(zipPath string) ([]byte, bool, error) {
+	for _, ef := range p.ExtraFiles {
+		if ef.ZipPath != zipPath {
+			continue
+		}
+		f, err := tempstorage.Open(ef.StoragePath)
+		if err != nil {
+			return nil, false, err
+		}
+		defer f.Close()
+		data, err := io.ReadAll(f)
+		return data, err == nil, err
+	}
+	return nil, false, nil
+}
@@ -0,0 +1,286 @@
+package presentation
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/yaklabco/unioffice/v2"
+	uchart "github.com/yaklabco/unioffice/v2/chart"
+	"github.com/yaklabco/unioffice/v2/color"
+	"github.com/yaklabco/unioffice/v2/common"
+	"github.com/yaklabco/unioffice/v2/common/tempstorage"
+	"github.com/yaklabco/unioffice/v2/measurement"
+	"github.com/yaklabco/unioffice/v2/schema/schemas.microsoft.com/office/drawing/2014/chartex"
+	"github.com/yaklabco/unioffice/v2/schema/soo/dml"
+	"github.com/yaklabco/unioffice/v2/schema/soo/pml"
+)
+
+// chartExFallbackText is the text displayed instead of a chartex chart by the
+// applications that don't support them, the same PowerPoint writes.
+const chartExFallbackText = "This chart isn't available in your version of PowerPoint.\n\n" +
+	"Editing this shape or saving this presentation into a different file format will permanently break the chart."
+
+// chartExPart is a chartex part added to the presentation, kept as an extra
+// file as the package writer doesn't support them.
+type chartExPart struct {
+	path string
+	x    *chartex.ChartSpace
+}
+
+// ChartExFrame is the graphic frame displaying a chart added with
+// Slide.AddChartEx.
+type ChartExFrame struct {
+	slide Slide
+	obj   *pml.CT_GroupShapeChoice
+	c     uchart.ChartEx
+}
+
+// X returns the inner wrapped XML type.
+func (f ChartExFrame) X() *pml.CT_GraphicalObjectFrame { return f.obj.GraphicFrame }
+
+// Chart returns the chart displayed by the frame.
+func (f ChartExFrame) Chart() uchart.ChartEx { return f.c }
+
+// SetPosition sets the position of the frame on the slide.
+func (f ChartExFrame) SetPosition(x, y measurement.Distance) {
+	xfrm := f.obj.GraphicFrame.Xfrm
+	xfrm.Off.XAttr.ST_CoordinateUnqualified = unioffice.Int64(int64(x / measurement.EMU))
+	xfrm.Off.YAttr.ST_CoordinateUnqualified = unioffice.Int64(int64(y / measurement.EMU))
+}
+
+// SetSize sets the width and height of the frame.
+func (f ChartExFrame) SetSize(w, h measurement.Distance) {
+	xfrm := f.obj.GraphicFrame.Xfrm
+	xfrm.Ext.CxAttr = int64(w / measurement.EMU)
+	xfrm.Ext.CyAttr = int64(h / measurement.EMU)
+}
+
+// SetFallbackImage sets the image displayed instead of the chart by the
+// applications that don't support it, e.g. a rendering of the chart. The
+// image must have been added to the presentation.
+func (f ChartExFrame) SetFallbackImage(img common.ImageRef) error {
+	found := false
+	for _, im := range f.slide._cbab.Images {
+		if im == img {
+			found = true
+		}
+	}
+	if !found {
+		return errors.New("image not found in presentation")
+	}
+	cnv := f.obj.GraphicFrame.NvGraphicFramePr.CNvPr
+	pic := pml.NewCT_Picture()
+	pic.NvPicPr.CNvPr.IdAttr = cnv.IdAttr
+	pic.NvPicPr.CNvPr.NameAttr = cnv.NameAttr
+	pic.NvPicPr.CNvPicPr.PicLocks = dml.NewCT_PictureLocking()
+	pic.NvPicPr.CNvPicPr.PicLocks.NoChangeAspectAttr = unioffice.Bool(true)
+	pic.BlipFill.Blip = dml.NewCT_Blip()
+	pic.BlipFill.Blip.EmbedAttr = unioffice.String(f.slide.AddImageToRels(img))
+	pic.BlipFill.FillModePropertiesChoice.Stretch = dml.NewCT_StretchInfoProperties()
+	pic.BlipFill.FillModePropertiesChoice.Stretch.FillRect = dml.NewCT_RelativeRect()
+	// the fallback shares the transform of the frame so it follows moves
+	pic.SpPr.Xfrm = f.obj.GraphicFrame.Xfrm
+	pic.SpPr.GeometryChoice.PrstGeom = dml.NewCT_PresetGeometry2D()
+	pic.SpPr.GeometryChoice.PrstGeom.PrstAttr = dml.ST_ShapeTypeRect
+	f.obj.AlternateContent.Fallback = &pml.CT_GroupShapeChoice{Pic: pic}
+	return nil
+}
+
+// AddChartEx adds a chart of one of the types introduced by Office 2016, e.g.
+// a waterfall, to the slide. Older applications display a text shape instead
+// of the chart unless a fallback image is set on the returned frame.
+func (s Slide) AddChartEx() (ChartExFrame, error) {
+	p := s._cbab
+	rels := s.getSlideRels()
+	if rels.X() == nil {
+		return ChartExFrame{}, errors.New("slide not found in presentation")
+	}
+	cs := chartex.NewChartSpace()
+	partPath, n := p.nextPartPath("ppt/charts/chartEx%d.xml")
+	part := &chartExPart{path: partPath, x: cs}
+	if err := p.writeExtraFile(part.path, part.x); err != nil {
+		return ChartExFrame{}, err
+	}
+	p._chartEx = append(p._chartEx, part)
+	rel := rels.AddRelationship(fmt.Sprintf("../charts/chartEx%d.xml", n), chartex.RelationshipType)
+	p.ContentTypes.AddOverride("/"+partPath, chartex.ContentType)
+
+	id := maxShapeID(s._gddb.CSld.SpTree) + 1
+	name := fmt.Sprintf("Chart %d", id)
+	gf := pml.NewCT_GraphicalObjectFrame()
+	gf.NvGraphicFramePr.CNvPr.IdAttr = id
+	gf.NvGraphicFramePr.CNvPr.NameAttr = name
+	gf.Xfrm.Off = dml.NewCT_Point2D()
+	gf.Xfrm.Ext = dml.NewCT_PositiveSize2D()
+	gf.Graphic.GraphicData.UriAttr = chartex.Namespace
+	gf.Graphic.GraphicData.Any = []unioffice.Any{&chartex.Chart{IdAttr: rel.ID()}}
+	obj := pml.NewCT_GroupShapeChoice()
+	obj.GraphicFrame = gf
+	prefix, ns := cs.Requires()
+	obj.AlternateContent = &pml.AlternateContent{
+		Requires:   prefix,
+		Namespaces: map[string]string{prefix: ns},
+		Fallback:   &pml.CT_GroupShapeChoice{Sp: chartExFallbackShape(id, name, gf.Xfrm)},
+	}
+	s._gddb.CSld.SpTree.GroupShapeChoice = append(s._gddb.CSld.SpTree.GroupShapeChoice, obj)
+
+	c := uchart.MakeChartEx(cs)
+	c.Properties().SetSolidFill(color.White)
+	f := ChartExFrame{slide: s, obj: obj, c: c}
+	f.SetPosition(measurement.Inch, measurement.Inch)
+	f.SetSize(6*measurement.Inch, 4*measurement.Inch)
+	return f, nil
+}
+
+// chartExFallbackShape returns the text shape displayed instead of a chartex
+// chart.
+func chartExFallbackShape(id uint32, name string, xfrm *dml.CT_Transform2D) *pml.CT_Shape {
+	sp := pml.NewCT_Shape()
+	sp.NvSpPr.CNvPr.IdAttr = id
+	sp.NvSpPr.CNvPr.NameAttr = name
+	sp.NvSpPr.CNvSpPr.SpLocks = dml.NewCT_ShapeLocking()
+	sp.NvSpPr.CNvSpPr.SpLocks.NoTextEditAttr = unioffice.Bool(true)
+	sp.SpPr.Xfrm = xfrm
+	sp.SpPr.GeometryChoice.PrstGeom = dml.NewCT_PresetGeometry2D()
+	sp.SpPr.GeometryChoice.PrstGeom.PrstAttr = dml.ST_ShapeTypeRect
+	sp.TxBody = dml.NewCT_TextBody()
+	sp.TxBody.BodyPr.WrapAttr = dml.ST_TextWrappingTypeSquare
+	for _, line := range strings.Split(chartExFallbackText, "\n") {
+		para := dml.NewCT_TextParagraph()
+		if line != "" {
+			run := dml.NewEG_TextRun()
+			run.TextRunChoice.R = dml.NewCT_RegularTextRun()
+			run.TextRunChoice.R.RPr = dml.NewCT_TextCharacterProperties()
+			run.TextRunChoice.R.RPr.SzAttr = unioffice.Int32(1200)
+			run.TextRunChoice.R.T = line
+			para.EG_TextRun = append(para.EG_TextRun, run)
+		}
+		sp.TxBody.P = append(sp.TxBody.P, para)
+	}
+	return sp
+}
+
+// maxShapeID returns the largest shape ID in a group shape.
+func maxShapeID(g *pml.CT_GroupShape) uint32 {
+	max := uint32(0)
+	use := func(pr *dml.CT_NonVisualDrawingProps) {
+		if pr != nil && pr.IdAttr > max {
+			max = pr.IdAttr
+		}
+	}
+	if g.NvGrpSpPr != nil {
+		use(g.NvGrpSpPr.CNvPr)
+	}
+	for _, c := range g.GroupShapeChoice {
+		switch {
+		case c.Sp != nil && c.Sp.NvSpPr != nil:
+			use(c.Sp.NvSpPr.CNvPr)
+		case c.GraphicFrame != nil && c.GraphicFrame.NvGraphicFramePr != nil:
+			use(c.GraphicFrame.NvGraphicFramePr.CNvPr)
+		case c.Pic != nil && c.Pic.NvPicPr != nil:
+			use(c.Pic.NvPicPr.CNvPr)
+		case c.CxnSp != nil && c.CxnSp.NvCxnSpPr != nil:
+			use(c.CxnSp.NvCxnSpPr.CNvPr)
+		case c.GrpSp != nil:
+			if id := maxShapeID(c.GrpSp); id > max {
+				max = id
+			}
+		}
+	}
+	return max
+}
+
+// nextPartPath returns the first path of a numbered part not in the package
+// along with its number.
+func (p *Presentation) nextPartPath(format string) (string, int) {
+	for n := 1; ; n++ {
+		taken := false
+		name := fmt.Sprintf(format, n)
+		for _, ef := range p.ExtraFiles {
+			if ef.ZipPath == name {
+				taken = true
+			}
+		}
+		if !taken {
+			return name, n
+		}
+	}
+}
+
+// writeExtraFile encodes a part into the extra files of the presentation,
+// replacing any previous content.
+func (p *Presentation) writeExtraFile(zipPath string, v interface{}) error {
+	f, err := tempstorage.TempFile(p.TmpPath, "xml")
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := io.WriteString(f, xml.Header); err != nil {
+		return err
+	}
+	if err := xml.NewEncoder(f).Encode(v); err != nil {
+		return err
+	}
+	for i, ef := range p.ExtraFiles {
+		if ef.ZipPath == zipPath {
+			p.ExtraFiles[i].StoragePath = f.Name()
+			return nil
+		}
+	}
+	p.ExtraFiles = append(p.ExtraFiles, common.ExtraFile{ZipPath: zipPath, StoragePath: f.Name()})
+	return nil
+}
+
+// flushChartEx writes the chartex parts to the extra files so they're saved
+// with the presentation.
+func (p *Presentation) flushChartEx() error {
+	for _, part := range p._chartEx {
+		if err := p.writeExtraFile(part.path, part.x); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadChartEx decodes the chartex parts read with the presentation so they
+// can be modified, they're written back on save.
+func (p *Presentation) loadChartEx() error {
+	known := map[string]bool{}
+	for _, part := range p._chartEx {
+		known[part.path] = true
+	}
+	for _, ef := range p.ExtraFiles {
+		dir, name := path.Split(ef.ZipPath)
+		if dir != "ppt/charts/" || known[ef.ZipPath] || !strings.HasPrefix(name, "chartEx") || path.Ext(name) != ".xml" {
+			continue
+		}
+		if _, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(name, "chartEx"), ".xml")); err != nil {
+			continue
+		}
+		data, _, err := p.extraFileData(ef.ZipPath)
+		if err != nil {
+			return err
+		}
+		cs := chartex.NewChartSpace()
+		if err := xml.Unmarshal(data, cs); err != nil {
+			return fmt.Errorf("error decoding %s: %s", ef.ZipPath, err)
+		}
+		p._chartEx = append(p._chartEx, &chartExPart{path: ef.ZipPath, x: cs})
+	}
+	return nil
+}
+
+// ChartExCharts returns the chartex charts of the presentation, both those
+// read with it and those added with Slide.AddChartEx.
+func (p *Presentation) ChartExCharts() []uchart.ChartEx {
+	ret := []uchart.ChartEx{}
+	for _, part := range p._chartEx {
+		ret = append(ret, uchart.MakeChartEx(part.x))
+	}
+	return ret
+}
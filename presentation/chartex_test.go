@@ -0,0 +1,97 @@
+package presentation
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/yaklabco/unioffice/v2/common"
+	"github.com/yaklabco/unioffice/v2/schema/schemas.microsoft.com/office/drawing/2014/chartex"
+)
+
+func TestAddChartEx(t *testing.T) {
+	ppt := New()
+	slide := ppt.AddSlide()
+	f, err := slide.AddChartEx()
+	if err != nil {
+		t.Fatalf("error adding chart: %s", err)
+	}
+	f.Chart().AddWaterfallSeries()
+
+	tree := slide.X().CSld.SpTree.GroupShapeChoice
+	if len(tree) != 1 || tree[0].GraphicFrame != f.X() {
+		t.Fatalf("expected the frame on the slide")
+	}
+	if f.X().Graphic.GraphicData.UriAttr != chartex.Namespace {
+		t.Errorf("unexpected graphic data %q", f.X().Graphic.GraphicData.UriAttr)
+	}
+	ac := tree[0].AlternateContent
+	if ac == nil || ac.Requires != "cx1" || ac.Namespaces["cx1"] == "" {
+		t.Fatalf("expected the frame within alternate content")
+	}
+	if ac.Fallback == nil || ac.Fallback.Sp == nil || ac.Fallback.Sp.SpPr.Xfrm != f.X().Xfrm {
+		t.Fatalf("expected a fallback shape sharing the transform of the frame")
+	}
+	if len(ppt.ChartExCharts()) != 1 || len(ppt.ExtraFiles) != 1 || ppt.ExtraFiles[0].ZipPath != "ppt/charts/chartEx1.xml" {
+		t.Errorf("expected the chartex part")
+	}
+
+	// a second chart gets the next part and shape ID
+	g, err := slide.AddChartEx()
+	if err != nil {
+		t.Fatalf("error adding chart: %s", err)
+	}
+	if g.X().NvGraphicFramePr.CNvPr.IdAttr != f.X().NvGraphicFramePr.CNvPr.IdAttr+1 {
+		t.Errorf("expected the next shape ID")
+	}
+	if len(ppt.ExtraFiles) != 2 || ppt.ExtraFiles[1].ZipPath != "ppt/charts/chartEx2.xml" {
+		t.Errorf("expected the next chartex part")
+	}
+}
+
+func TestChartExFallbackImage(t *testing.T) {
+	ppt := New()
+	slide := ppt.AddSlide()
+	f, err := slide.AddChartEx()
+	if err != nil {
+		t.Fatalf("error adding chart: %s", err)
+	}
+	if err := f.SetFallbackImage(common.ImageRef{}); err == nil {
+		t.Errorf("expected an error for an image not in the presentation")
+	}
+
+	buf := bytes.Buffer{}
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 4, 4))); err != nil {
+		t.Fatal(err)
+	}
+	img, err := common.ImageFromBytes(buf.Bytes())
+	if err != nil {
+		t.Fatalf("error decoding image: %s", err)
+	}
+	ref, err := ppt.AddImage(img)
+	if err != nil {
+		t.Fatalf("error adding image: %s", err)
+	}
+	if err := f.SetFallbackImage(ref); err != nil {
+		t.Fatalf("error setting fallback: %s", err)
+	}
+	fb := slide.X().CSld.SpTree.GroupShapeChoice[0].AlternateContent.Fallback
+	if fb == nil || fb.Pic == nil || fb.Sp != nil {
+		t.Fatalf("expected a fallback picture")
+	}
+	if fb.Pic.BlipFill.Blip.EmbedAttr == nil || *fb.Pic.BlipFill.Blip.EmbedAttr == "" || fb.Pic.SpPr.Xfrm != f.X().Xfrm {
+		t.Errorf("expected the picture to display the image over the frame")
+	}
+
+	data, err := xml.Marshal(slide.X())
+	if err != nil {
+		t.Fatalf("error encoding slide: %s", err)
+	}
+	s := string(data)
+	if !strings.Contains(s, `Requires="cx1"`) || !strings.Contains(s, "<mc:Fallback>") || !strings.Contains(s, *fb.Pic.BlipFill.Blip.EmbedAttr) {
+		t.Errorf("unexpected slide %s", s)
+	}
+}
@@ -193,7 +193,7 @@ if _gaca :=_gb .MarshalXML (_agag ,_bcac ,_bbdc .X ());_gaca !=nil {return _gaca
 };};for _fffe ,_fbeb :=range _febd ._efab {if _fbeb ==nil {continue ;};_cffa :=_gd .AbsoluteFilename (_bbbg ,_gd .NotesMasterType ,_febd ._bbd [_fffe ]);if _cbfa :=_gb .MarshalXML (_agag ,_cffa ,_fbeb );_cbfa !=nil {return _cbfa ;};};for _fcbd ,_edfc :=range _febd .Images {_dccg :=_fcbd +1;
 if _fdc ,_ggd :=_ae .StringToNumbers (_edfc .Target ());_ggd &&_dccg !=_fdc {_dccg =_fdc ;};if _eeeg :=_ge .AddImageToZip (_agag ,_edfc ,_dccg ,_gd .DocTypePresentation );_eeeg !=nil {return _eeeg ;};};_febd .ContentTypes .EnsureDefault ("\u0070\u006e\u0067","\u0069m\u0061\u0067\u0065\u002f\u0070\u006eg");
 _febd .ContentTypes .EnsureDefault ("\u006a\u0070\u0065\u0067","\u0069\u006d\u0061\u0067\u0065\u002f\u006a\u0070\u0065\u0067");_febd .ContentTypes .EnsureDefault ("\u006a\u0070\u0067","\u0069\u006d\u0061\u0067\u0065\u002f\u006a\u0070\u0065\u0067");_febd .ContentTypes .EnsureDefault ("\u0077\u006d\u0066","i\u006d\u0061\u0067\u0065\u002f\u0078\u002d\u0077\u006d\u0066");
-if _facg :=_gb .MarshalXML (_agag ,_gd .ContentTypesFilename ,_febd .ContentTypes .X ());_facg !=nil {return _facg ;};if _ede :=_febd .WriteExtraFiles (_agag );_ede !=nil {return _ede ;};return nil ;};
+if _facg :=_gb .MarshalXML (_agag ,_gd .ContentTypesFilename ,_febd .ContentTypes .X ());_facg !=nil {return _facg ;};if _ede :=_febd .flushChartEx ();_ede !=nil {return _ede ;};if _ede :=_febd .WriteExtraFiles (_agag );_ede !=nil {return _ede ;};return nil ;};
 
 // New initializes and returns a new presentation
 func New ()*Presentation {_afg :=_ggg ();_afg .ContentTypes .AddOverride ("/\u0070\u0070\u0074\u002fpr\u0065s\u0065\u006e\u0074\u0061\u0074i\u006f\u006e\u002e\u0078\u006d\u006c","\u0061\u0070\u0070\u006c\u0069\u0063\u0061t\u0069\u006f\u006e\u002f\u0076\u006e\u0064\u002e\u006f\u0070\u0065\u006e\u0078\u006d\u006c\u0066\u006f\u0072m\u0061\u0074\u0073\u002d\u006ff\u0066\u0069\u0063\u0065\u0064\u006f\u0063\u0075\u006de\u006e\u0074\u002e\u0070\u0072\u0065\u0073\u0065\u006e\u0074\u0061\u0074\u0069\u006f\u006e\u006d\u006c\u002e\u0070\u0072\u0065\u0073\u0065\u006e\u0074\u0061\u0074\u0069\u006f\u006e\u002e\u006d\u0061\u0069\u006e\u002b\u0078\u006d\u006c");
@@ -434,7 +434,7 @@ func (_bdac ViewProperties )ExtLst ()*_cf .CT_ExtensionList {return _bdac ._dddf
 // Presentation is the a presentation base document.
 type Presentation struct{_ge .DocBase ;_dgf *_cf .Presentation ;_cbb _ge .Relationships ;_fde []*_cf .Sld ;_bafb []_ge .Relationships ;_ff []int ;_dfd []*_cf .SldMaster ;_cac []_ge .Relationships ;_gceb []int ;_cbag []*_cf .SldLayout ;_gbd []_ge .Relationships ;
 _adfc []*_ee .Theme ;_ec []_ge .Relationships ;_dfgd []int ;_ffe _ge .TableStyles ;_bcaf PresentationProperties ;_eeae ViewProperties ;_ddd []*_ee .CT_Hyperlink ;_gcd []*chart ;_dbf []*_cf .HandoutMaster ;_efab []*_cf .NotesMaster ;_bbd []int ;_gfa []*_gd .XSDAny ;
-_gag []int ;_dcc map[string ]string ;_ecf string ;};
+_gag []int ;_dcc map[string ]string ;_ecf string ;_chartEx []*chartExPart ;};
 
 // Properties returns the properties of the TextBox.
 func (_cage TextBox )Properties ()_fb .ShapeProperties {if _cage ._bcfg .SpPr ==nil {_cage ._bcfg .SpPr =_ee .NewCT_ShapeProperties ();};return _fb .MakeShapeProperties (_cage ._bcfg .SpPr );};
@@ -587,7 +587,7 @@ _bge .TmpPath =_bafe ;_ecdaf ,_cfc :=_d .NewReader (r ,size );if _cfc !=nil {ret
 _bfag :=false ;for _ ,_adeg :=range _cfgfd {if _adeg .FileHeader .Name =="\u0064\u006f\u0063\u0050ro\u0070\u0073\u002f\u0063\u0075\u0073\u0074\u006f\u006d\u002e\u0078\u006d\u006c"{_bfag =true ;break ;};};if _bfag {_bge .CreateCustomProperties ();};_gaee :=_gb .DecodeMap {};
 _gaee .SetOnNewRelationshipFunc (_bge .onNewRelationship );_gaee .AddTarget (_gd .ContentTypesFilename ,_bge .ContentTypes .X (),"",0);_gaee .AddTarget (_gd .BaseRelsFilename ,_bge .Rels .X (),"",0);if _ced :=_gaee .Decode (_cfgfd );_ced !=nil {return nil ,_ced ;
 };for _ ,_dbg :=range _cfgfd {if _dbg ==nil {continue ;};if _ggaa :=_bge .AddExtraFileFromZip (_dbg );_ggaa !=nil {return nil ,_ggaa ;};};if _bfag {_eeeb :=false ;for _ ,_gagc :=range _bge .Rels .X ().Relationship {if _gagc .TargetAttr =="\u0064\u006f\u0063\u0050ro\u0070\u0073\u002f\u0063\u0075\u0073\u0074\u006f\u006d\u002e\u0078\u006d\u006c"{_eeeb =true ;
-break ;};};if !_eeeb {_bge .AddCustomRelationships ();};};return _bge ,nil ;};
+break ;};};if !_eeeb {_bge .AddCustomRelationships ();};};if _cgbe :=_bge .loadChartEx ();_cgbe !=nil {return nil ,_cgbe ;};return _bge ,nil ;};
 
 // ExtractText returns text from a slide as a SlideText object.
 func (_ggb *Slide )ExtractText ()*SlideText {_cce :=_gdg (_ggb ._cbab ,_ggb ._gddb .CSld .SpTree .GroupShapeChoice ,[]rectangle {},[]*TextItem {});_ca .Sort (sort2d (_cce ));return &SlideText {Items :_cce };};func (_ebf sort2d )Swap (i ,j int ){_ebf [i ],_ebf [j ]=_ebf [j ],_ebf [i ]};
@@ -0,0 +1,552 @@
+// Package chartex contains the types of the chartex parts introduced by
+// Office 2016 for the waterfall, funnel, treemap, sunburst, histogram, pareto,
+// box and whisker and region map charts.
+package chartex
+
+import (
+	"encoding/xml"
+
+	"github.com/yaklabco/unioffice/v2"
+	"github.com/yaklabco/unioffice/v2/schema/soo/dml"
+)
+
+const (
+	// Namespace is the namespace of the chartex parts and of the element
+	// referencing them from a graphic frame.
+	Namespace = "http://schemas.microsoft.com/office/drawing/2014/chartex"
+
+	// RelationshipType is the type of the relationship to a chartex part.
+	RelationshipType = "http://schemas.microsoft.com/office/2014/relationships/chartEx"
+
+	// ContentType is the content type of a chartex part.
+	ContentType = "application/vnd.ms-office.chartex+xml"
+
+	drawingNamespace = "http://schemas.openxmlformats.org/drawingml/2006/main"
+	relNamespace     = "http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+)
+
+// ST_SeriesLayout is the chart type of a series.
+type ST_SeriesLayout string
+
+const (
+	ST_SeriesLayoutBoxWhisker      ST_SeriesLayout = "boxWhisker"
+	ST_SeriesLayoutClusteredColumn ST_SeriesLayout = "clusteredColumn"
+	ST_SeriesLayoutFunnel          ST_SeriesLayout = "funnel"
+	ST_SeriesLayoutParetoLine      ST_SeriesLayout = "paretoLine"
+	ST_SeriesLayoutRegionMap       ST_SeriesLayout = "regionMap"
+	ST_SeriesLayoutSunburst        ST_SeriesLayout = "sunburst"
+	ST_SeriesLayoutTreemap         ST_SeriesLayout = "treemap"
+	ST_SeriesLayoutWaterfall       ST_SeriesLayout = "waterfall"
+)
+
+// ST_StringDimensionType is the use of a string dimension of the chart data.
+type ST_StringDimensionType string
+
+const (
+	ST_StringDimensionTypeCat      ST_StringDimensionType = "cat"
+	ST_StringDimensionTypeColorStr ST_StringDimensionType = "colorStr"
+	ST_StringDimensionTypeEntityId ST_StringDimensionType = "entityId"
+)
+
+// ST_NumericDimensionType is the use of a numeric dimension of the chart data.
+type ST_NumericDimensionType string
+
+const (
+	ST_NumericDimensionTypeVal      ST_NumericDimensionType = "val"
+	ST_NumericDimensionTypeX        ST_NumericDimensionType = "x"
+	ST_NumericDimensionTypeY        ST_NumericDimensionType = "y"
+	ST_NumericDimensionTypeSize     ST_NumericDimensionType = "size"
+	ST_NumericDimensionTypeColorVal ST_NumericDimensionType = "colorVal"
+)
+
+// ST_FormulaDirection is the direction of the range of a formula.
+type ST_FormulaDirection string
+
+const (
+	ST_FormulaDirectionCol ST_FormulaDirection = "col"
+	ST_FormulaDirectionRow ST_FormulaDirection = "row"
+)
+
+// ST_SidePos is the side of the chart a title or legend is placed on.
+type ST_SidePos string
+
+const (
+	ST_SidePosL ST_SidePos = "l"
+	ST_SidePosT ST_SidePos = "t"
+	ST_SidePosR ST_SidePos = "r"
+	ST_SidePosB ST_SidePos = "b"
+)
+
+// ST_PosAlign is the alignment of a title or legend along its side.
+type ST_PosAlign string
+
+const (
+	ST_PosAlignMin ST_PosAlign = "min"
+	ST_PosAlignCtr ST_PosAlign = "ctr"
+	ST_PosAlignMax ST_PosAlign = "max"
+)
+
+// ST_DataLabelPos is the position of data labels.
+type ST_DataLabelPos string
+
+const (
+	ST_DataLabelPosBestFit ST_DataLabelPos = "bestFit"
+	ST_DataLabelPosB       ST_DataLabelPos = "b"
+	ST_DataLabelPosCtr     ST_DataLabelPos = "ctr"
+	ST_DataLabelPosInBase  ST_DataLabelPos = "inBase"
+	ST_DataLabelPosInEnd   ST_DataLabelPos = "inEnd"
+	ST_DataLabelPosL       ST_DataLabelPos = "l"
+	ST_DataLabelPosOutEnd  ST_DataLabelPos = "outEnd"
+	ST_DataLabelPosR       ST_DataLabelPos = "r"
+	ST_DataLabelPosT       ST_DataLabelPos = "t"
+)
+
+// ST_ParentLabelLayout is the layout of the labels of the parent categories
+// of a treemap.
+type ST_ParentLabelLayout string
+
+const (
+	ST_ParentLabelLayoutNone        ST_ParentLabelLayout = "none"
+	ST_ParentLabelLayoutBanner      ST_ParentLabelLayout = "banner"
+	ST_ParentLabelLayoutOverlapping ST_ParentLabelLayout = "overlapping"
+)
+
+// ST_RegionLabelLayout is the layout of the labels of a region map.
+type ST_RegionLabelLayout string
+
+const (
+	ST_RegionLabelLayoutNone        ST_RegionLabelLayout = "none"
+	ST_RegionLabelLayoutBestFitOnly ST_RegionLabelLayout = "bestFitOnly"
+	ST_RegionLabelLayoutShowAll     ST_RegionLabelLayout = "showAll"
+)
+
+// ST_QuartileMethod is the method used to compute the quartiles of a box and
+// whisker chart.
+type ST_QuartileMethod string
+
+const (
+	ST_QuartileMethodInclusive ST_QuartileMethod = "inclusive"
+	ST_QuartileMethodExclusive ST_QuartileMethod = "exclusive"
+)
+
+// ST_IntervalClosedSide is the side on which the bins of a histogram are
+// closed.
+type ST_IntervalClosedSide string
+
+const (
+	ST_IntervalClosedSideL ST_IntervalClosedSide = "l"
+	ST_IntervalClosedSideR ST_IntervalClosedSide = "r"
+)
+
+// ST_TickMarksType is the type of the tick marks of an axis.
+type ST_TickMarksType string
+
+const (
+	ST_TickMarksTypeIn    ST_TickMarksType = "in"
+	ST_TickMarksTypeOut   ST_TickMarksType = "out"
+	ST_TickMarksTypeCross ST_TickMarksType = "cross"
+	ST_TickMarksTypeNone  ST_TickMarksType = "none"
+)
+
+// ChartSpace is the root element of a chartex part.
+type ChartSpace struct {
+	XMLName   xml.Name                `xml:"http://schemas.microsoft.com/office/drawing/2014/chartex chartSpace"`
+	ChartData *CT_ChartData           `xml:"chartData"`
+	Chart     *CT_Chart               `xml:"chart"`
+	SpPr      *dml.CT_ShapeProperties `xml:"spPr"`
+	TxPr      *dml.CT_TextBody        `xml:"txPr"`
+	ExtLst    *CT_ExtensionList       `xml:"extLst"`
+}
+
+// NewChartSpace returns a chart space with empty chart data and plot area.
+func NewChartSpace() *ChartSpace {
+	return &ChartSpace{
+		ChartData: &CT_ChartData{},
+		Chart:     &CT_Chart{PlotArea: &CT_PlotArea{PlotAreaRegion: &CT_PlotAreaRegion{}}},
+	}
+}
+
+// MarshalXML declares the DrawingML prefix used by the shape and text
+// properties before encoding the chart space.
+func (cs *ChartSpace) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type chartSpace ChartSpace
+	start.Name = xml.Name{Space: Namespace, Local: "chartSpace"}
+	start.Attr = append(start.Attr,
+		xml.Attr{Name: xml.Name{Local: "xmlns:a"}, Value: drawingNamespace},
+		xml.Attr{Name: xml.Name{Local: "xmlns:r"}, Value: relNamespace})
+	return e.EncodeElement((*chartSpace)(cs), start)
+}
+
+// Requires returns the markup compatibility prefix and namespace an
+// application must understand to display the chart, newer chart types need
+// newer revisions of the chartex namespace.
+func (cs *ChartSpace) Requires() (prefix, namespace string) {
+	prefix, namespace = "cx1", "http://schemas.microsoft.com/office/drawing/2015/9/8/chartex"
+	if cs.Chart == nil || cs.Chart.PlotArea == nil || cs.Chart.PlotArea.PlotAreaRegion == nil {
+		return
+	}
+	for _, s := range cs.Chart.PlotArea.PlotAreaRegion.Series {
+		switch s.LayoutIdAttr {
+		case ST_SeriesLayoutRegionMap:
+			return "cx4", "http://schemas.microsoft.com/office/drawing/2016/5/10/chartex"
+		case ST_SeriesLayoutFunnel:
+			prefix, namespace = "cx2", "http://schemas.microsoft.com/office/drawing/2015/10/21/chartex"
+		}
+	}
+	return
+}
+
+// CT_ChartData is the data plotted by the chart, cached from the formulas it
+// references.
+type CT_ChartData struct {
+	ExternalData *CT_ExternalData `xml:"externalData"`
+	Data         []*CT_Data       `xml:"data"`
+}
+
+// CT_ExternalData references the embedded workbook holding the chart data.
+type CT_ExternalData struct {
+	IdAttr         string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	AutoUpdateAttr *bool  `xml:"http://schemas.microsoft.com/office/drawing/2014/chartex autoUpdate,attr"`
+}
+
+// CT_Data is a set of dimensions plotted by a series.
+type CT_Data struct {
+	IdAttr uint32                 `xml:"id,attr"`
+	StrDim []*CT_StringDimension  `xml:"strDim"`
+	NumDim []*CT_NumericDimension `xml:"numDim"`
+}
+
+// CT_StringDimension is a text dimension of the chart data, e.g. the
+// categories.
+type CT_StringDimension struct {
+	TypeAttr ST_StringDimensionType `xml:"type,attr"`
+	F        *CT_Formula            `xml:"f"`
+	Nf       *CT_Formula            `xml:"nf"`
+	Lvl      []*CT_StringLevel      `xml:"lvl"`
+}
+
+// CT_NumericDimension is a numeric dimension of the chart data, e.g. the
+// values.
+type CT_NumericDimension struct {
+	TypeAttr ST_NumericDimensionType `xml:"type,attr"`
+	F        *CT_Formula             `xml:"f"`
+	Nf       *CT_Formula             `xml:"nf"`
+	Lvl      []*CT_NumericLevel      `xml:"lvl"`
+}
+
+// CT_Formula is a formula referencing the cells of a dimension.
+type CT_Formula struct {
+	DirAttr ST_FormulaDirection `xml:"dir,attr,omitempty"`
+	Content string              `xml:",chardata"`
+}
+
+// CT_StringLevel is the cache of a level of a string dimension, hierarchical
+// categories have a level per depth starting with the leaves.
+type CT_StringLevel struct {
+	PtCountAttr uint32            `xml:"ptCount,attr"`
+	NameAttr    string            `xml:"name,attr,omitempty"`
+	Pt          []*CT_StringValue `xml:"pt"`
+}
+
+// CT_StringValue is a cached text value.
+type CT_StringValue struct {
+	IdxAttr uint32 `xml:"idx,attr"`
+	Content string `xml:",chardata"`
+}
+
+// CT_NumericLevel is the cache of a level of a numeric dimension.
+type CT_NumericLevel struct {
+	PtCountAttr    uint32             `xml:"ptCount,attr"`
+	FormatCodeAttr string             `xml:"formatCode,attr"`
+	NameAttr       string             `xml:"name,attr,omitempty"`
+	Pt             []*CT_NumericValue `xml:"pt"`
+}
+
+// CT_NumericValue is a cached numeric value.
+type CT_NumericValue struct {
+	IdxAttr uint32  `xml:"idx,attr"`
+	Content float64 `xml:",chardata"`
+}
+
+// CT_Chart is the chart displayed by the chart space.
+type CT_Chart struct {
+	Title    *CT_Title         `xml:"title"`
+	PlotArea *CT_PlotArea      `xml:"plotArea"`
+	Legend   *CT_Legend        `xml:"legend"`
+	ExtLst   *CT_ExtensionList `xml:"extLst"`
+}
+
+// CT_Title is the title of the chart or of an axis.
+type CT_Title struct {
+	PosAttr     ST_SidePos              `xml:"pos,attr,omitempty"`
+	AlignAttr   ST_PosAlign             `xml:"align,attr,omitempty"`
+	OverlayAttr *bool                   `xml:"overlay,attr"`
+	Tx          *CT_Text                `xml:"tx"`
+	SpPr        *dml.CT_ShapeProperties `xml:"spPr"`
+	TxPr        *dml.CT_TextBody        `xml:"txPr"`
+}
+
+// CT_Text is either a formula with cached text or rich text.
+type CT_Text struct {
+	TxData *CT_TextData     `xml:"txData"`
+	Rich   *dml.CT_TextBody `xml:"rich"`
+}
+
+// CT_TextData is a text referenced by a formula or a literal.
+type CT_TextData struct {
+	F *CT_Formula `xml:"f"`
+	V *string     `xml:"v"`
+}
+
+// CT_PlotArea contains the series and the axes of the chart.
+type CT_PlotArea struct {
+	PlotAreaRegion *CT_PlotAreaRegion      `xml:"plotAreaRegion"`
+	Axis           []*CT_Axis              `xml:"axis"`
+	SpPr           *dml.CT_ShapeProperties `xml:"spPr"`
+	ExtLst         *CT_ExtensionList       `xml:"extLst"`
+}
+
+// CT_PlotAreaRegion contains the series of the chart.
+type CT_PlotAreaRegion struct {
+	PlotSurface *CT_PlotSurface   `xml:"plotSurface"`
+	Series      []*CT_Series      `xml:"series"`
+	ExtLst      *CT_ExtensionList `xml:"extLst"`
+}
+
+// CT_PlotSurface is the background of the plot area region.
+type CT_PlotSurface struct {
+	SpPr *dml.CT_ShapeProperties `xml:"spPr"`
+}
+
+// CT_Series is a series of the chart, its layout determines the chart type.
+type CT_Series struct {
+	LayoutIdAttr  ST_SeriesLayout            `xml:"layoutId,attr"`
+	HiddenAttr    *bool                      `xml:"hidden,attr"`
+	OwnerIdxAttr  *uint32                    `xml:"ownerIdx,attr"`
+	UniqueIdAttr  string                     `xml:"uniqueId,attr,omitempty"`
+	FormatIdxAttr *uint32                    `xml:"formatIdx,attr"`
+	Tx            *CT_Text                   `xml:"tx"`
+	SpPr          *dml.CT_ShapeProperties    `xml:"spPr"`
+	DataPt        []*CT_DataPoint            `xml:"dataPt"`
+	DataLabels    *CT_DataLabels             `xml:"dataLabels"`
+	DataId        *CT_UnsignedIntValue       `xml:"dataId"`
+	LayoutPr      *CT_SeriesLayoutProperties `xml:"layoutPr"`
+	AxisId        []*CT_UnsignedIntValue     `xml:"axisId"`
+	ExtLst        *CT_ExtensionList          `xml:"extLst"`
+}
+
+// CT_DataPoint formats a single point of a series.
+type CT_DataPoint struct {
+	IdxAttr uint32                  `xml:"idx,attr"`
+	SpPr    *dml.CT_ShapeProperties `xml:"spPr"`
+}
+
+// CT_DataLabels are the labels of the points of a series.
+type CT_DataLabels struct {
+	PosAttr    ST_DataLabelPos           `xml:"pos,attr,omitempty"`
+	NumFmt     *CT_NumberFormat          `xml:"numFmt"`
+	SpPr       *dml.CT_ShapeProperties   `xml:"spPr"`
+	TxPr       *dml.CT_TextBody          `xml:"txPr"`
+	Visibility *CT_DataLabelVisibilities `xml:"visibility"`
+	Separator  *string                   `xml:"separator"`
+}
+
+// CT_DataLabelVisibilities are the parts of the data labels displayed.
+type CT_DataLabelVisibilities struct {
+	SeriesNameAttr   *bool `xml:"seriesName,attr"`
+	CategoryNameAttr *bool `xml:"categoryName,attr"`
+	ValueAttr        *bool `xml:"value,attr"`
+}
+
+// CT_NumberFormat is the number format of labels.
+type CT_NumberFormat struct {
+	FormatCodeAttr   string `xml:"formatCode,attr"`
+	SourceLinkedAttr *bool  `xml:"sourceLinked,attr"`
+}
+
+// CT_UnsignedIntValue is an element with an unsigned integer value.
+type CT_UnsignedIntValue struct {
+	ValAttr uint32 `xml:"val,attr"`
+}
+
+// CT_DoubleValue is an element with a floating point value.
+type CT_DoubleValue struct {
+	ValAttr float64 `xml:"val,attr"`
+}
+
+// CT_SeriesLayoutProperties are the properties specific to the layout of a
+// series.
+type CT_SeriesLayoutProperties struct {
+	ParentLabelLayout *CT_ParentLabelLayout         `xml:"parentLabelLayout"`
+	RegionLabelLayout *CT_RegionLabelLayout         `xml:"regionLabelLayout"`
+	Visibility        *CT_SeriesElementVisibilities `xml:"visibility"`
+	Aggregation       *CT_Aggregation               `xml:"aggregation"`
+	Binning           *CT_Binning                   `xml:"binning"`
+	Geography         *CT_Geography                 `xml:"geography"`
+	Statistics        *CT_Statistics                `xml:"statistics"`
+	Subtotals         *CT_Subtotals                 `xml:"subtotals"`
+	ExtLst            *CT_ExtensionList             `xml:"extLst"`
+}
+
+// CT_ParentLabelLayout is the layout of the parent labels of a treemap.
+type CT_ParentLabelLayout struct {
+	ValAttr ST_ParentLabelLayout `xml:"val,attr"`
+}
+
+// CT_RegionLabelLayout is the layout of the labels of a region map.
+type CT_RegionLabelLayout struct {
+	ValAttr ST_RegionLabelLayout `xml:"val,attr"`
+}
+
+// CT_SeriesElementVisibilities are the optional elements of a series that
+// are displayed.
+type CT_SeriesElementVisibilities struct {
+	ConnectorLinesAttr *bool `xml:"connectorLines,attr"`
+	MeanLineAttr       *bool `xml:"meanLine,attr"`
+	MeanMarkerAttr     *bool `xml:"meanMarker,attr"`
+	NonoutliersAttr    *bool `xml:"nonoutliers,attr"`
+	OutliersAttr       *bool `xml:"outliers,attr"`
+}
+
+// CT_Aggregation aggregates the values of identical categories of a
+// histogram.
+type CT_Aggregation struct{}
+
+// CT_Binning are the bins of a histogram, either of a fixed size or a fixed
+// count. The underflow and overflow bins are a value or "auto".
+type CT_Binning struct {
+	IntervalClosedAttr ST_IntervalClosedSide `xml:"intervalClosed,attr,omitempty"`
+	UnderflowAttr      string                `xml:"underflow,attr,omitempty"`
+	OverflowAttr       string                `xml:"overflow,attr,omitempty"`
+	BinSize            *CT_DoubleValue       `xml:"binSize"`
+	BinCount           *CT_UnsignedIntValue  `xml:"binCount"`
+}
+
+// CT_Geography are the settings of the geography data of a region map.
+type CT_Geography struct {
+	ProjectionTypeAttr   string `xml:"projectionType,attr,omitempty"`
+	ViewedRegionTypeAttr string `xml:"viewedRegionType,attr,omitempty"`
+	CultureLanguageAttr  string `xml:"cultureLanguage,attr"`
+	CultureRegionAttr    string `xml:"cultureRegion,attr"`
+	AttributionAttr      string `xml:"attribution,attr"`
+}
+
+// CT_Statistics are the statistics settings of a box and whisker chart.
+type CT_Statistics struct {
+	QuartileMethodAttr ST_QuartileMethod `xml:"quartileMethod,attr,omitempty"`
+}
+
+// CT_Subtotals are the indexes of the points of a waterfall that are totals.
+type CT_Subtotals struct {
+	Idx []*CT_UnsignedIntValue `xml:"idx"`
+}
+
+// CT_Axis is an axis of the chart, category axes have a category scaling and
+// value axes a value scaling.
+type CT_Axis struct {
+	IdAttr         uint32                  `xml:"id,attr"`
+	HiddenAttr     *bool                   `xml:"hidden,attr"`
+	CatScaling     *CT_CategoryAxisScaling `xml:"catScaling"`
+	ValScaling     *CT_ValueAxisScaling    `xml:"valScaling"`
+	Title          *CT_Title               `xml:"title"`
+	Units          *CT_AxisUnits           `xml:"units"`
+	MajorGridlines *CT_Gridlines           `xml:"majorGridlines"`
+	MinorGridlines *CT_Gridlines           `xml:"minorGridlines"`
+	MajorTickMarks *CT_TickMarks           `xml:"majorTickMarks"`
+	MinorTickMarks *CT_TickMarks           `xml:"minorTickMarks"`
+	TickLabels     *CT_TickLabels          `xml:"tickLabels"`
+	NumFmt         *CT_NumberFormat        `xml:"numFmt"`
+	SpPr           *dml.CT_ShapeProperties `xml:"spPr"`
+	TxPr           *dml.CT_TextBody        `xml:"txPr"`
+	ExtLst         *CT_ExtensionList       `xml:"extLst"`
+}
+
+// CT_CategoryAxisScaling is the scaling of a category axis, the gap width is
+// a ratio of the column width or "auto".
+type CT_CategoryAxisScaling struct {
+	GapWidthAttr string `xml:"gapWidth,attr,omitempty"`
+}
+
+// CT_ValueAxisScaling is the scaling of a value axis, each bound is a value
+// or "auto".
+type CT_ValueAxisScaling struct {
+	MaxAttr       string `xml:"max,attr,omitempty"`
+	MinAttr       string `xml:"min,attr,omitempty"`
+	MajorUnitAttr string `xml:"majorUnit,attr,omitempty"`
+	MinorUnitAttr string `xml:"minorUnit,attr,omitempty"`
+}
+
+// CT_AxisUnits is the display unit of a value axis, e.g. "percentage".
+type CT_AxisUnits struct {
+	UnitAttr string `xml:"unit,attr,omitempty"`
+}
+
+// CT_Gridlines are the gridlines of an axis.
+type CT_Gridlines struct {
+	SpPr *dml.CT_ShapeProperties `xml:"spPr"`
+}
+
+// CT_TickMarks are the tick marks of an axis.
+type CT_TickMarks struct {
+	TypeAttr ST_TickMarksType `xml:"type,attr,omitempty"`
+}
+
+// CT_TickLabels displays the labels of an axis.
+type CT_TickLabels struct{}
+
+// CT_Legend is the legend of the chart.
+type CT_Legend struct {
+	PosAttr     ST_SidePos              `xml:"pos,attr,omitempty"`
+	AlignAttr   ST_PosAlign             `xml:"align,attr,omitempty"`
+	OverlayAttr *bool                   `xml:"overlay,attr"`
+	SpPr        *dml.CT_ShapeProperties `xml:"spPr"`
+	TxPr        *dml.CT_TextBody        `xml:"txPr"`
+}
+
+// CT_ExtensionList is a list of extensions, which are kept as is.
+type CT_ExtensionList struct {
+	Ext []*CT_Extension `xml:"ext"`
+}
+
+// CT_Extension is an extension identified by its URI.
+type CT_Extension struct {
+	UriAttr string `xml:"uri,attr"`
+	Content string `xml:",innerxml"`
+}
+
+// Chart is the element of a graphic frame referencing a chartex part.
+type Chart struct {
+	IdAttr string
+}
+
+// NewChart returns a reference to a chartex part.
+func NewChart() *Chart { return &Chart{} }
+
+// MarshalXML encodes the reference, the element is written within graphic
+// data that doesn't declare the chartex prefixes.
+func (c *Chart) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name = xml.Name{Local: "cx:chart"}
+	start.Attr = []xml.Attr{
+		{Name: xml.Name{Local: "xmlns:cx"}, Value: Namespace},
+		{Name: xml.Name{Local: "xmlns:r"}, Value: relNamespace},
+		{Name: xml.Name{Local: "r:id"}, Value: c.IdAttr},
+	}
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	return e.EncodeToken(xml.EndElement{Name: start.Name})
+}
+
+// UnmarshalXML decodes the reference.
+func (c *Chart) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	for _, a := range start.Attr {
+		if a.Name.Space == relNamespace && a.Name.Local == "id" {
+			c.IdAttr = a.Value
+		}
+	}
+	return d.Skip()
+}
+
+func init() {
+	unioffice.RegisterConstructor(Namespace, "chart", NewChart)
+}
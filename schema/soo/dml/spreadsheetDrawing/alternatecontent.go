@@ -0,0 +1,253 @@
+package spreadsheetDrawing
+
+import (
+	"encoding/xml"
+	"sort"
+)
+
+const mcNamespace = "http://schemas.openxmlformats.org/markup-compatibility/2006"
+
+// decodeAlternateAnchors decodes the anchors of an alternate content block in
+// a drawing, e.g. the slicers written by newer applications. The anchors of
+// the first choice with any are added to the drawing, their objects keeping
+// the objects of the fallback anchors in an alternate content block so the
+// fallback is written back.
+func decodeAlternateAnchors(d *xml.Decoder, dr *CT_Drawing) error {
+	var ac *AlternateContent
+	var chosen, fallback []*EG_Anchor
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch el := tok.(type) {
+		case xml.StartElement:
+			if el.Name.Space != mcNamespace || (el.Name.Local != "Choice" && el.Name.Local != "Fallback") {
+				if err := d.Skip(); err != nil {
+					return err
+				}
+				continue
+			}
+			anchors, err := decodeAnchors(d)
+			if err != nil {
+				return err
+			}
+			if el.Name.Local == "Fallback" {
+				fallback = anchors
+				continue
+			}
+			if ac != nil || len(anchors) == 0 {
+				continue
+			}
+			ac = newAlternateContent(el)
+			chosen = anchors
+		case xml.EndElement:
+			if ac == nil {
+				chosen, fallback = fallback, nil
+			}
+			for i, a := range chosen {
+				if i < len(fallback) {
+					obj, fb := anchorObject(a), anchorObject(fallback[i])
+					if obj != nil && fb != nil && obj.AlternateContent == nil {
+						block := *ac
+						block.Fallback = fb
+						obj.AlternateContent = &block
+					}
+				}
+				dr.EG_Anchor = append(dr.EG_Anchor, a)
+			}
+			return nil
+		}
+	}
+}
+
+// decodeAnchors decodes the anchors of a choice or fallback.
+func decodeAnchors(d *xml.Decoder) ([]*EG_Anchor, error) {
+	anchors := []*EG_Anchor{}
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch el := tok.(type) {
+		case xml.StartElement:
+			switch el.Name.Local {
+			case "twoCellAnchor", "oneCellAnchor", "absoluteAnchor":
+				anchor := NewEG_Anchor()
+				anchor.AnchorChoice = NewEG_AnchorChoice()
+				if err := d.DecodeElement(anchor.AnchorChoice, &el); err != nil {
+					return nil, err
+				}
+				anchors = append(anchors, anchor)
+				continue
+			}
+			if err := d.Skip(); err != nil {
+				return nil, err
+			}
+		case xml.EndElement:
+			return anchors, nil
+		}
+	}
+}
+
+// anchorObject returns the object of an anchor.
+func anchorObject(a *EG_Anchor) *EG_ObjectChoicesChoice {
+	c := a.AnchorChoice
+	switch {
+	case c == nil:
+		return nil
+	case c.TwoCellAnchor != nil:
+		return c.TwoCellAnchor.ObjectChoicesChoice
+	case c.OneCellAnchor != nil:
+		return c.OneCellAnchor.ObjectChoicesChoice
+	case c.AbsoluteAnchor != nil:
+		return c.AbsoluteAnchor.ObjectChoicesChoice
+	}
+	return nil
+}
+
+// newAlternateContent returns a block with the requirements and namespaces of
+// a choice.
+func newAlternateContent(choice xml.StartElement) *AlternateContent {
+	ac := &AlternateContent{Namespaces: map[string]string{}}
+	for _, a := range choice.Attr {
+		switch {
+		case a.Name.Space == "" && a.Name.Local == "Requires":
+			ac.Requires = a.Value
+		case a.Name.Space == "xmlns":
+			ac.Namespaces[a.Name.Local] = a.Value
+		}
+	}
+	return ac
+}
+
+// decodeAlternateObject decodes the object of an alternate content block in
+// an anchor. An object chosen from a choice keeps the block so the fallback is
+// written back.
+func decodeAlternateObject(d *xml.Decoder, choice **EG_ObjectChoicesChoice) error {
+	var ac *AlternateContent
+	var fallback *EG_ObjectChoicesChoice
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch el := tok.(type) {
+		case xml.StartElement:
+			if el.Name.Space != mcNamespace || (el.Name.Local != "Choice" && el.Name.Local != "Fallback") {
+				if err := d.Skip(); err != nil {
+					return err
+				}
+				continue
+			}
+			obj, err := decodeObject(d)
+			if err != nil {
+				return err
+			}
+			if obj == nil {
+				continue
+			}
+			if el.Name.Local == "Fallback" {
+				fallback = obj
+				continue
+			}
+			if ac != nil {
+				continue
+			}
+			ac = newAlternateContent(el)
+			*choice = obj
+		case xml.EndElement:
+			if ac != nil {
+				ac.Fallback = fallback
+				(*choice).AlternateContent = ac
+			} else if fallback != nil {
+				*choice = fallback
+			}
+			return nil
+		}
+	}
+}
+
+// decodeObject decodes the first object of a choice or fallback.
+func decodeObject(d *xml.Decoder) (*EG_ObjectChoicesChoice, error) {
+	var obj *EG_ObjectChoicesChoice
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch el := tok.(type) {
+		case xml.StartElement:
+			switch el.Name.Local {
+			case "sp", "grpSp", "graphicFrame", "cxnSp", "pic", "contentPart":
+				if obj == nil {
+					obj = NewEG_ObjectChoicesChoice()
+					if err := d.DecodeElement(obj, &el); err != nil {
+						return nil, err
+					}
+					continue
+				}
+			}
+			if err := d.Skip(); err != nil {
+				return nil, err
+			}
+		case xml.EndElement:
+			return obj, nil
+		}
+	}
+}
+
+// AlternateContent is a markup compatibility block around an object. The
+// object is the choice displayed by the applications that understand the
+// required namespaces, the others display the fallback.
+type AlternateContent struct {
+	// Requires are the space separated prefixes of the namespaces the choice
+	// requires.
+	Requires string
+	// Namespaces are the namespaces of the prefixes by prefix.
+	Namespaces map[string]string
+	Fallback   *EG_ObjectChoicesChoice
+}
+
+// marshal writes the block with obj as the choice.
+func (ac *AlternateContent) marshal(e *xml.Encoder, obj *EG_ObjectChoicesChoice) error {
+	choice := *obj
+	choice.AlternateContent = nil
+	block := xml.StartElement{Name: xml.Name{Local: "mc:AlternateContent"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "xmlns:mc"}, Value: mcNamespace}}}
+	if err := e.EncodeToken(block); err != nil {
+		return err
+	}
+	prefixes := make([]string, 0, len(ac.Namespaces))
+	for p := range ac.Namespaces {
+		prefixes = append(prefixes, p)
+	}
+	sort.Strings(prefixes)
+	start := xml.StartElement{Name: xml.Name{Local: "mc:Choice"}}
+	for _, p := range prefixes {
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "xmlns:" + p}, Value: ac.Namespaces[p]})
+	}
+	start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "Requires"}, Value: ac.Requires})
+	if err := encodeWrapped(e, start, &choice); err != nil {
+		return err
+	}
+	if ac.Fallback != nil {
+		start = xml.StartElement{Name: xml.Name{Local: "mc:Fallback"}}
+		if err := encodeWrapped(e, start, ac.Fallback); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(block.End())
+}
+
+// encodeWrapped writes obj within the start element, objects choose their own
+// element name.
+func encodeWrapped(e *xml.Encoder, start xml.StartElement, obj *EG_ObjectChoicesChoice) error {
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	if err := obj.MarshalXML(e, xml.StartElement{}); err != nil {
+		return err
+	}
+	return e.EncodeToken(start.End())
+}
@@ -23,7 +23,11 @@ func (_cdb *CT_GraphicalObjectFrameNonVisual )Validate ()error {return _cdb .Val
 // ValidateWithPath validates the CT_Picture and its children, prefixing error messages with path
 func (_dec *CT_Picture )ValidateWithPath (path string )error {if _gdb :=_dec .NvPicPr .ValidateWithPath (path +"\u002f\u004e\u0076\u0050\u0069\u0063\u0050\u0072");_gdb !=nil {return _gdb ;};if _dbe :=_dec .BlipFill .ValidateWithPath (path +"\u002fB\u006c\u0069\u0070\u0046\u0069\u006cl");
 _dbe !=nil {return _dbe ;};if _gab :=_dec .SpPr .ValidateWithPath (path +"\u002f\u0053\u0070P\u0072");_gab !=nil {return _gab ;};if _dec .Style !=nil {if _dee :=_dec .Style .ValidateWithPath (path +"\u002f\u0053\u0074\u0079\u006c\u0065");_dee !=nil {return _dee ;
-};};return nil ;};type EG_ObjectChoicesChoice struct{Sp *CT_Shape ;GrpSp *CT_GroupShape ;GraphicFrame *CT_GraphicalObjectFrame ;CxnSp *CT_Connector ;Pic *CT_Picture ;ContentPart *CT_Rel ;};type CT_GroupShape struct{
+};};return nil ;};type EG_ObjectChoicesChoice struct{Sp *CT_Shape ;GrpSp *CT_GroupShape ;GraphicFrame *CT_GraphicalObjectFrame ;CxnSp *CT_Connector ;Pic *CT_Picture ;ContentPart *CT_Rel ;
+
+// AlternateContent, if set, writes the object as the choice of a markup
+// compatibility block with a fallback for older applications.
+AlternateContent *AlternateContent ;};type CT_GroupShape struct{
 
 // Non-Visual Properties for a Group Shape
 NvGrpSpPr *CT_GroupShapeNonVisual ;
@@ -79,7 +83,7 @@ if _aca :=d .DecodeElement (&_ff .ObjectChoicesChoice .GraphicFrame ,&_faa );_ac
 if _dad :=d .DecodeElement (&_ff .ObjectChoicesChoice .CxnSp ,&_faa );_dad !=nil {return _dad ;};case _e .Name {Space :"\u0068\u0074\u0074\u0070\u003a\u002f/\u0073\u0063\u0068\u0065\u006da\u0073\u002e\u006f\u0070\u0065\u006ex\u006d\u006c\u0066\u006f\u0072m\u0061\u0074\u0073\u002e\u006f\u0072\u0067\u002f\u0064\u0072\u0061\u0077i\u006e\u0067\u006d\u006c\u002f\u0032\u0030\u0030\u0036\u002f\u0073\u0070\u0072\u0065\u0061d\u0073\u0068\u0065\u0065\u0074\u0044\u0072\u0061w\u0069\u006e\u0067",Local :"\u0070\u0069\u0063"},_e .Name {Space :"\u0068\u0074\u0074\u0070\u003a\u002f\u002fp\u0075\u0072\u006c.\u006f\u0063\u006cc\u002e\u006fr\u0067\u002f\u006f\u006f\u0078\u006dl\u002fdr\u0061\u0077\u0069\u006e\u0067\u006d\u006c\u002f\u0073\u0070\u0072\u0065\u0061\u0064\u0073\u0068\u0065\u0065\u0074\u0044\u0072\u0061\u0077\u0069\u006e\u0067",Local :"\u0070\u0069\u0063"}:_ff .ObjectChoicesChoice =NewEG_ObjectChoicesChoice ();
 if _aa :=d .DecodeElement (&_ff .ObjectChoicesChoice .Pic ,&_faa );_aa !=nil {return _aa ;};case _e .Name {Space :"\u0068\u0074\u0074\u0070\u003a\u002f/\u0073\u0063\u0068\u0065\u006da\u0073\u002e\u006f\u0070\u0065\u006ex\u006d\u006c\u0066\u006f\u0072m\u0061\u0074\u0073\u002e\u006f\u0072\u0067\u002f\u0064\u0072\u0061\u0077i\u006e\u0067\u006d\u006c\u002f\u0032\u0030\u0030\u0036\u002f\u0073\u0070\u0072\u0065\u0061d\u0073\u0068\u0065\u0065\u0074\u0044\u0072\u0061w\u0069\u006e\u0067",Local :"c\u006f\u006e\u0074\u0065\u006e\u0074\u0050\u0061\u0072\u0074"},_e .Name {Space :"\u0068\u0074\u0074\u0070\u003a\u002f\u002fp\u0075\u0072\u006c.\u006f\u0063\u006cc\u002e\u006fr\u0067\u002f\u006f\u006f\u0078\u006dl\u002fdr\u0061\u0077\u0069\u006e\u0067\u006d\u006c\u002f\u0073\u0070\u0072\u0065\u0061\u0064\u0073\u0068\u0065\u0065\u0074\u0044\u0072\u0061\u0077\u0069\u006e\u0067",Local :"c\u006f\u006e\u0074\u0065\u006e\u0074\u0050\u0061\u0072\u0074"}:_ff .ObjectChoicesChoice =NewEG_ObjectChoicesChoice ();
 if _ab :=d .DecodeElement (&_ff .ObjectChoicesChoice .ContentPart ,&_faa );_ab !=nil {return _ab ;};case _e .Name {Space :"\u0068\u0074\u0074\u0070\u003a\u002f/\u0073\u0063\u0068\u0065\u006da\u0073\u002e\u006f\u0070\u0065\u006ex\u006d\u006c\u0066\u006f\u0072m\u0061\u0074\u0073\u002e\u006f\u0072\u0067\u002f\u0064\u0072\u0061\u0077i\u006e\u0067\u006d\u006c\u002f\u0032\u0030\u0030\u0036\u002f\u0073\u0070\u0072\u0065\u0061d\u0073\u0068\u0065\u0065\u0074\u0044\u0072\u0061w\u0069\u006e\u0067",Local :"\u0063\u006c\u0069\u0065\u006e\u0074\u0044\u0061\u0074\u0061"},_e .Name {Space :"\u0068\u0074\u0074\u0070\u003a\u002f\u002fp\u0075\u0072\u006c.\u006f\u0063\u006cc\u002e\u006fr\u0067\u002f\u006f\u006f\u0078\u006dl\u002fdr\u0061\u0077\u0069\u006e\u0067\u006d\u006c\u002f\u0073\u0070\u0072\u0065\u0061\u0064\u0073\u0068\u0065\u0065\u0074\u0044\u0072\u0061\u0077\u0069\u006e\u0067",Local :"\u0063\u006c\u0069\u0065\u006e\u0074\u0044\u0061\u0074\u0061"}:if _eb :=d .DecodeElement (_ff .ClientData ,&_faa );
-_eb !=nil {return _eb ;};default:_fb .Log .Debug ("\u0073\u006bi\u0070\u0070\u0069\u006e\u0067 \u0075\u006e\u0073\u0075\u0070p\u006f\u0072\u0074\u0065\u0064\u0020\u0065\u006c\u0065\u006d\u0065\u006e\u0074\u0020\u006f\u006e\u0020\u0043\u0054\u005f\u0041\u0062\u0073\u006f\u006c\u0075\u0074\u0065\u0041\u006e\u0063\u0068\u006f\u0072\u0020\u0025\u0076",_faa .Name );
+_eb !=nil {return _eb ;};case _e .Name {Space :mcNamespace ,Local :"AlternateContent"}:if _err :=decodeAlternateObject (d ,&_ff .ObjectChoicesChoice );_err !=nil {return _err ;};default:_fb .Log .Debug ("\u0073\u006bi\u0070\u0070\u0069\u006e\u0067 \u0075\u006e\u0073\u0075\u0070p\u006f\u0072\u0074\u0065\u0064\u0020\u0065\u006c\u0065\u006d\u0065\u006e\u0074\u0020\u006f\u006e\u0020\u0043\u0054\u005f\u0041\u0062\u0073\u006f\u006c\u0075\u0074\u0065\u0041\u006e\u0063\u0068\u006f\u0072\u0020\u0025\u0076",_faa .Name );
 if _ga :=d .Skip ();_ga !=nil {return _ga ;};};case _e .EndElement :break _fbd ;case _e .CharData :};};return nil ;};func (_dcd *CT_GroupShapeChoice )MarshalXML (e *_e .Encoder ,start _e .StartElement )error {if _dcd .Sp !=nil {_eagg :=_e .StartElement {Name :_e .Name {Local :"\u0078\u0064\u0072\u003a\u0073\u0070"}};
 e .EncodeElement (_dcd .Sp ,_eagg );}else if _dcd .GrpSp !=nil {_abb :=_e .StartElement {Name :_e .Name {Local :"\u0078d\u0072\u003a\u0067\u0072\u0070\u0053p"}};e .EncodeElement (_dcd .GrpSp ,_abb );}else if _dcd .GraphicFrame !=nil {_ddf :=_e .StartElement {Name :_e .Name {Local :"\u0078\u0064r\u003a\u0067\u0072a\u0070\u0068\u0069\u0063\u0046\u0072\u0061\u006d\u0065"}};
 e .EncodeElement (_dcd .GraphicFrame ,_ddf );}else if _dcd .CxnSp !=nil {_bgb :=_e .StartElement {Name :_e .Name {Local :"\u0078d\u0072\u003a\u0063\u0078\u006e\u0053p"}};e .EncodeElement (_dcd .CxnSp ,_bgb );}else if _dcd .Pic !=nil {_bgcf :=_e .StartElement {Name :_e .Name {Local :"\u0078d\u0072\u003a\u0070\u0069\u0063"}};
@@ -100,7 +104,7 @@ if _bfd :=d .DecodeElement (&_fgd .ObjectChoicesChoice .GraphicFrame ,&_agg );_b
 if _eec :=d .DecodeElement (&_fgd .ObjectChoicesChoice .CxnSp ,&_agg );_eec !=nil {return _eec ;};case _e .Name {Space :"\u0068\u0074\u0074\u0070\u003a\u002f/\u0073\u0063\u0068\u0065\u006da\u0073\u002e\u006f\u0070\u0065\u006ex\u006d\u006c\u0066\u006f\u0072m\u0061\u0074\u0073\u002e\u006f\u0072\u0067\u002f\u0064\u0072\u0061\u0077i\u006e\u0067\u006d\u006c\u002f\u0032\u0030\u0030\u0036\u002f\u0073\u0070\u0072\u0065\u0061d\u0073\u0068\u0065\u0065\u0074\u0044\u0072\u0061w\u0069\u006e\u0067",Local :"\u0070\u0069\u0063"},_e .Name {Space :"\u0068\u0074\u0074\u0070\u003a\u002f\u002fp\u0075\u0072\u006c.\u006f\u0063\u006cc\u002e\u006fr\u0067\u002f\u006f\u006f\u0078\u006dl\u002fdr\u0061\u0077\u0069\u006e\u0067\u006d\u006c\u002f\u0073\u0070\u0072\u0065\u0061\u0064\u0073\u0068\u0065\u0065\u0074\u0044\u0072\u0061\u0077\u0069\u006e\u0067",Local :"\u0070\u0069\u0063"}:_fgd .ObjectChoicesChoice =NewEG_ObjectChoicesChoice ();
 if _dfd :=d .DecodeElement (&_fgd .ObjectChoicesChoice .Pic ,&_agg );_dfd !=nil {return _dfd ;};case _e .Name {Space :"\u0068\u0074\u0074\u0070\u003a\u002f/\u0073\u0063\u0068\u0065\u006da\u0073\u002e\u006f\u0070\u0065\u006ex\u006d\u006c\u0066\u006f\u0072m\u0061\u0074\u0073\u002e\u006f\u0072\u0067\u002f\u0064\u0072\u0061\u0077i\u006e\u0067\u006d\u006c\u002f\u0032\u0030\u0030\u0036\u002f\u0073\u0070\u0072\u0065\u0061d\u0073\u0068\u0065\u0065\u0074\u0044\u0072\u0061w\u0069\u006e\u0067",Local :"c\u006f\u006e\u0074\u0065\u006e\u0074\u0050\u0061\u0072\u0074"},_e .Name {Space :"\u0068\u0074\u0074\u0070\u003a\u002f\u002fp\u0075\u0072\u006c.\u006f\u0063\u006cc\u002e\u006fr\u0067\u002f\u006f\u006f\u0078\u006dl\u002fdr\u0061\u0077\u0069\u006e\u0067\u006d\u006c\u002f\u0073\u0070\u0072\u0065\u0061\u0064\u0073\u0068\u0065\u0065\u0074\u0044\u0072\u0061\u0077\u0069\u006e\u0067",Local :"c\u006f\u006e\u0074\u0065\u006e\u0074\u0050\u0061\u0072\u0074"}:_fgd .ObjectChoicesChoice =NewEG_ObjectChoicesChoice ();
 if _aad :=d .DecodeElement (&_fgd .ObjectChoicesChoice .ContentPart ,&_agg );_aad !=nil {return _aad ;};case _e .Name {Space :"\u0068\u0074\u0074\u0070\u003a\u002f/\u0073\u0063\u0068\u0065\u006da\u0073\u002e\u006f\u0070\u0065\u006ex\u006d\u006c\u0066\u006f\u0072m\u0061\u0074\u0073\u002e\u006f\u0072\u0067\u002f\u0064\u0072\u0061\u0077i\u006e\u0067\u006d\u006c\u002f\u0032\u0030\u0030\u0036\u002f\u0073\u0070\u0072\u0065\u0061d\u0073\u0068\u0065\u0065\u0074\u0044\u0072\u0061w\u0069\u006e\u0067",Local :"\u0063\u006c\u0069\u0065\u006e\u0074\u0044\u0061\u0074\u0061"},_e .Name {Space :"\u0068\u0074\u0074\u0070\u003a\u002f\u002fp\u0075\u0072\u006c.\u006f\u0063\u006cc\u002e\u006fr\u0067\u002f\u006f\u006f\u0078\u006dl\u002fdr\u0061\u0077\u0069\u006e\u0067\u006d\u006c\u002f\u0073\u0070\u0072\u0065\u0061\u0064\u0073\u0068\u0065\u0065\u0074\u0044\u0072\u0061\u0077\u0069\u006e\u0067",Local :"\u0063\u006c\u0069\u0065\u006e\u0074\u0044\u0061\u0074\u0061"}:if _abgec :=d .DecodeElement (_fgd .ClientData ,&_agg );
-_abgec !=nil {return _abgec ;};default:_fb .Log .Debug ("\u0073\u006b\u0069\u0070\u0070i\u006e\u0067\u0020\u0075\u006e\u0073\u0075\u0070\u0070\u006f\u0072\u0074\u0065d\u0020\u0065\u006c\u0065\u006d\u0065\u006e\u0074\u0020\u006f\u006e\u0020\u0043\u0054\u005f\u004f\u006e\u0065\u0043\u0065\u006c\u006c\u0041\u006e\u0063\u0068\u006f\u0072\u0020\u0025v",_agg .Name );
+_abgec !=nil {return _abgec ;};case _e .Name {Space :mcNamespace ,Local :"AlternateContent"}:if _err :=decodeAlternateObject (d ,&_fgd .ObjectChoicesChoice );_err !=nil {return _err ;};default:_fb .Log .Debug ("\u0073\u006b\u0069\u0070\u0070i\u006e\u0067\u0020\u0075\u006e\u0073\u0075\u0070\u0070\u006f\u0072\u0074\u0065d\u0020\u0065\u006c\u0065\u006d\u0065\u006e\u0074\u0020\u006f\u006e\u0020\u0043\u0054\u005f\u004f\u006e\u0065\u0043\u0065\u006c\u006c\u0041\u006e\u0063\u0068\u006f\u0072\u0020\u0025v",_agg .Name );
 if _aedb :=d .Skip ();_aedb !=nil {return _aedb ;};};case _e .EndElement :break _gag ;case _e .CharData :};};return nil ;};func (_bcdb *CT_Picture )UnmarshalXML (d *_e .Decoder ,start _e .StartElement )error {_bcdb .NvPicPr =NewCT_PictureNonVisual ();_bcdb .BlipFill =_ed .NewCT_BlipFillProperties ();
 _bcdb .SpPr =_ed .NewCT_ShapeProperties ();for _ ,_ecff :=range start .Attr {if _ecff .Name .Local =="\u006d\u0061\u0063r\u006f"{_cdad :=_ecff .Value ;_bcdb .MacroAttr =&_cdad ;continue ;};if _ecff .Name .Local =="\u0066\u0050\u0075\u0062\u006c\u0069\u0073\u0068\u0065\u0064"{_dgad ,_dafa :=_f .ParseBool (_ecff .Value );
 if _dafa !=nil {return _dafa ;};_bcdb .FPublishedAttr =&_dgad ;continue ;};};_bab :for {_geb ,_ffce :=d .Token ();if _ffce !=nil {return _ffce ;};switch _gce :=_geb .(type ){case _e .StartElement :switch _gce .Name {case _e .Name {Space :"\u0068\u0074\u0074\u0070\u003a\u002f/\u0073\u0063\u0068\u0065\u006da\u0073\u002e\u006f\u0070\u0065\u006ex\u006d\u006c\u0066\u006f\u0072m\u0061\u0074\u0073\u002e\u006f\u0072\u0067\u002f\u0064\u0072\u0061\u0077i\u006e\u0067\u006d\u006c\u002f\u0032\u0030\u0030\u0036\u002f\u0073\u0070\u0072\u0065\u0061d\u0073\u0068\u0065\u0065\u0074\u0044\u0072\u0061w\u0069\u006e\u0067",Local :"\u006ev\u0050\u0069\u0063\u0050\u0072"},_e .Name {Space :"\u0068\u0074\u0074\u0070\u003a\u002f\u002fp\u0075\u0072\u006c.\u006f\u0063\u006cc\u002e\u006fr\u0067\u002f\u006f\u006f\u0078\u006dl\u002fdr\u0061\u0077\u0069\u006e\u0067\u006d\u006c\u002f\u0073\u0070\u0072\u0065\u0061\u0064\u0073\u0068\u0065\u0065\u0074\u0044\u0072\u0061\u0077\u0069\u006e\u0067",Local :"\u006ev\u0050\u0069\u0063\u0050\u0072"}:if _ddee :=d .DecodeElement (_bcdb .NvPicPr ,&_gce );
@@ -172,7 +176,7 @@ e .EncodeElement (_ddea .OneCellAnchor ,_aaaa );}else if _ddea .AbsoluteAnchor !
 };switch _eg :=_bfg .(type ){case _e .StartElement :switch _eg .Name {case _e .Name {Space :"\u0068\u0074\u0074\u0070\u003a\u002f/\u0073\u0063\u0068\u0065\u006da\u0073\u002e\u006f\u0070\u0065\u006ex\u006d\u006c\u0066\u006f\u0072m\u0061\u0074\u0073\u002e\u006f\u0072\u0067\u002f\u0064\u0072\u0061\u0077i\u006e\u0067\u006d\u006c\u002f\u0032\u0030\u0030\u0036\u002f\u0073\u0070\u0072\u0065\u0061d\u0073\u0068\u0065\u0065\u0074\u0044\u0072\u0061w\u0069\u006e\u0067",Local :"\u0074\u0077\u006f\u0043\u0065\u006c\u006c\u0041\u006e\u0063\u0068\u006f\u0072"},_e .Name {Space :"\u0068\u0074\u0074\u0070\u003a\u002f\u002fp\u0075\u0072\u006c.\u006f\u0063\u006cc\u002e\u006fr\u0067\u002f\u006f\u006f\u0078\u006dl\u002fdr\u0061\u0077\u0069\u006e\u0067\u006d\u006c\u002f\u0073\u0070\u0072\u0065\u0061\u0064\u0073\u0068\u0065\u0065\u0074\u0044\u0072\u0061\u0077\u0069\u006e\u0067",Local :"\u0074\u0077\u006f\u0043\u0065\u006c\u006c\u0041\u006e\u0063\u0068\u006f\u0072"}:_abg :=NewEG_Anchor ();
 _abg .AnchorChoice =NewEG_AnchorChoice ();_ggg .EG_Anchor =append (_ggg .EG_Anchor ,_abg );if _ge :=d .DecodeElement (&_abg .AnchorChoice .TwoCellAnchor ,&_eg );_ge !=nil {return _ge ;};case _e .Name {Space :"\u0068\u0074\u0074\u0070\u003a\u002f/\u0073\u0063\u0068\u0065\u006da\u0073\u002e\u006f\u0070\u0065\u006ex\u006d\u006c\u0066\u006f\u0072m\u0061\u0074\u0073\u002e\u006f\u0072\u0067\u002f\u0064\u0072\u0061\u0077i\u006e\u0067\u006d\u006c\u002f\u0032\u0030\u0030\u0036\u002f\u0073\u0070\u0072\u0065\u0061d\u0073\u0068\u0065\u0065\u0074\u0044\u0072\u0061w\u0069\u006e\u0067",Local :"\u006f\u006e\u0065\u0043\u0065\u006c\u006c\u0041\u006e\u0063\u0068\u006f\u0072"},_e .Name {Space :"\u0068\u0074\u0074\u0070\u003a\u002f\u002fp\u0075\u0072\u006c.\u006f\u0063\u006cc\u002e\u006fr\u0067\u002f\u006f\u006f\u0078\u006dl\u002fdr\u0061\u0077\u0069\u006e\u0067\u006d\u006c\u002f\u0073\u0070\u0072\u0065\u0061\u0064\u0073\u0068\u0065\u0065\u0074\u0044\u0072\u0061\u0077\u0069\u006e\u0067",Local :"\u006f\u006e\u0065\u0043\u0065\u006c\u006c\u0041\u006e\u0063\u0068\u006f\u0072"}:_ege :=NewEG_Anchor ();
 _ege .AnchorChoice =NewEG_AnchorChoice ();_ggg .EG_Anchor =append (_ggg .EG_Anchor ,_ege );if _gb :=d .DecodeElement (&_ege .AnchorChoice .OneCellAnchor ,&_eg );_gb !=nil {return _gb ;};case _e .Name {Space :"\u0068\u0074\u0074\u0070\u003a\u002f/\u0073\u0063\u0068\u0065\u006da\u0073\u002e\u006f\u0070\u0065\u006ex\u006d\u006c\u0066\u006f\u0072m\u0061\u0074\u0073\u002e\u006f\u0072\u0067\u002f\u0064\u0072\u0061\u0077i\u006e\u0067\u006d\u006c\u002f\u0032\u0030\u0030\u0036\u002f\u0073\u0070\u0072\u0065\u0061d\u0073\u0068\u0065\u0065\u0074\u0044\u0072\u0061w\u0069\u006e\u0067",Local :"\u0061\u0062\u0073\u006f\u006c\u0075\u0074\u0065\u0041n\u0063\u0068\u006f\u0072"},_e .Name {Space :"\u0068\u0074\u0074\u0070\u003a\u002f\u002fp\u0075\u0072\u006c.\u006f\u0063\u006cc\u002e\u006fr\u0067\u002f\u006f\u006f\u0078\u006dl\u002fdr\u0061\u0077\u0069\u006e\u0067\u006d\u006c\u002f\u0073\u0070\u0072\u0065\u0061\u0064\u0073\u0068\u0065\u0065\u0074\u0044\u0072\u0061\u0077\u0069\u006e\u0067",Local :"\u0061\u0062\u0073\u006f\u006c\u0075\u0074\u0065\u0041n\u0063\u0068\u006f\u0072"}:_dadg :=NewEG_Anchor ();
-_dadg .AnchorChoice =NewEG_AnchorChoice ();_ggg .EG_Anchor =append (_ggg .EG_Anchor ,_dadg );if _ddg :=d .DecodeElement (&_dadg .AnchorChoice .AbsoluteAnchor ,&_eg );_ddg !=nil {return _ddg ;};default:_fb .Log .Debug ("\u0073k\u0069\u0070p\u0069\u006e\u0067 \u0075\u006e\u0073\u0075\u0070\u0070\u006fr\u0074\u0065\u0064\u0020\u0065\u006ce\u006d\u0065\u006e\u0074\u0020\u006f\u006e\u0020\u0043\u0054\u005fD\u0072\u0061\u0077\u0069\u006e\u0067\u0020\u0025\u0076",_eg .Name );
+_dadg .AnchorChoice =NewEG_AnchorChoice ();_ggg .EG_Anchor =append (_ggg .EG_Anchor ,_dadg );if _ddg :=d .DecodeElement (&_dadg .AnchorChoice .AbsoluteAnchor ,&_eg );_ddg !=nil {return _ddg ;};case _e .Name {Space :mcNamespace ,Local :"AlternateContent"}:if _err :=decodeAlternateAnchors (d ,_ggg );_err !=nil {return _err ;};default:_fb .Log .Debug ("\u0073k\u0069\u0070p\u0069\u006e\u0067 \u0075\u006e\u0073\u0075\u0070\u0070\u006fr\u0074\u0065\u0064\u0020\u0065\u006ce\u006d\u0065\u006e\u0074\u0020\u006f\u006e\u0020\u0043\u0054\u005fD\u0072\u0061\u0077\u0069\u006e\u0067\u0020\u0025\u0076",_eg .Name );
 if _abge :=d .Skip ();_abge !=nil {return _abge ;};};case _e .EndElement :break _efg ;case _e .CharData :};};return nil ;};func NewCT_AnchorClientData ()*CT_AnchorClientData {_cba :=&CT_AnchorClientData {};return _cba };
 
 // Validate validates the CT_GroupShape and its children
@@ -212,7 +216,7 @@ if _ebe :=d .DecodeElement (&_gabc .ObjectChoicesChoice .GraphicFrame ,&_bgag );
 if _ddeg :=d .DecodeElement (&_gabc .ObjectChoicesChoice .CxnSp ,&_bgag );_ddeg !=nil {return _ddeg ;};case _e .Name {Space :"\u0068\u0074\u0074\u0070\u003a\u002f/\u0073\u0063\u0068\u0065\u006da\u0073\u002e\u006f\u0070\u0065\u006ex\u006d\u006c\u0066\u006f\u0072m\u0061\u0074\u0073\u002e\u006f\u0072\u0067\u002f\u0064\u0072\u0061\u0077i\u006e\u0067\u006d\u006c\u002f\u0032\u0030\u0030\u0036\u002f\u0073\u0070\u0072\u0065\u0061d\u0073\u0068\u0065\u0065\u0074\u0044\u0072\u0061w\u0069\u006e\u0067",Local :"\u0070\u0069\u0063"},_e .Name {Space :"\u0068\u0074\u0074\u0070\u003a\u002f\u002fp\u0075\u0072\u006c.\u006f\u0063\u006cc\u002e\u006fr\u0067\u002f\u006f\u006f\u0078\u006dl\u002fdr\u0061\u0077\u0069\u006e\u0067\u006d\u006c\u002f\u0073\u0070\u0072\u0065\u0061\u0064\u0073\u0068\u0065\u0065\u0074\u0044\u0072\u0061\u0077\u0069\u006e\u0067",Local :"\u0070\u0069\u0063"}:_gabc .ObjectChoicesChoice =NewEG_ObjectChoicesChoice ();
 if _adg :=d .DecodeElement (&_gabc .ObjectChoicesChoice .Pic ,&_bgag );_adg !=nil {return _adg ;};case _e .Name {Space :"\u0068\u0074\u0074\u0070\u003a\u002f/\u0073\u0063\u0068\u0065\u006da\u0073\u002e\u006f\u0070\u0065\u006ex\u006d\u006c\u0066\u006f\u0072m\u0061\u0074\u0073\u002e\u006f\u0072\u0067\u002f\u0064\u0072\u0061\u0077i\u006e\u0067\u006d\u006c\u002f\u0032\u0030\u0030\u0036\u002f\u0073\u0070\u0072\u0065\u0061d\u0073\u0068\u0065\u0065\u0074\u0044\u0072\u0061w\u0069\u006e\u0067",Local :"c\u006f\u006e\u0074\u0065\u006e\u0074\u0050\u0061\u0072\u0074"},_e .Name {Space :"\u0068\u0074\u0074\u0070\u003a\u002f\u002fp\u0075\u0072\u006c.\u006f\u0063\u006cc\u002e\u006fr\u0067\u002f\u006f\u006f\u0078\u006dl\u002fdr\u0061\u0077\u0069\u006e\u0067\u006d\u006c\u002f\u0073\u0070\u0072\u0065\u0061\u0064\u0073\u0068\u0065\u0065\u0074\u0044\u0072\u0061\u0077\u0069\u006e\u0067",Local :"c\u006f\u006e\u0074\u0065\u006e\u0074\u0050\u0061\u0072\u0074"}:_gabc .ObjectChoicesChoice =NewEG_ObjectChoicesChoice ();
 if _gaaa :=d .DecodeElement (&_gabc .ObjectChoicesChoice .ContentPart ,&_bgag );_gaaa !=nil {return _gaaa ;};case _e .Name {Space :"\u0068\u0074\u0074\u0070\u003a\u002f/\u0073\u0063\u0068\u0065\u006da\u0073\u002e\u006f\u0070\u0065\u006ex\u006d\u006c\u0066\u006f\u0072m\u0061\u0074\u0073\u002e\u006f\u0072\u0067\u002f\u0064\u0072\u0061\u0077i\u006e\u0067\u006d\u006c\u002f\u0032\u0030\u0030\u0036\u002f\u0073\u0070\u0072\u0065\u0061d\u0073\u0068\u0065\u0065\u0074\u0044\u0072\u0061w\u0069\u006e\u0067",Local :"\u0063\u006c\u0069\u0065\u006e\u0074\u0044\u0061\u0074\u0061"},_e .Name {Space :"\u0068\u0074\u0074\u0070\u003a\u002f\u002fp\u0075\u0072\u006c.\u006f\u0063\u006cc\u002e\u006fr\u0067\u002f\u006f\u006f\u0078\u006dl\u002fdr\u0061\u0077\u0069\u006e\u0067\u006d\u006c\u002f\u0073\u0070\u0072\u0065\u0061\u0064\u0073\u0068\u0065\u0065\u0074\u0044\u0072\u0061\u0077\u0069\u006e\u0067",Local :"\u0063\u006c\u0069\u0065\u006e\u0074\u0044\u0061\u0074\u0061"}:if _fag :=d .DecodeElement (_gabc .ClientData ,&_bgag );
-_fag !=nil {return _fag ;};default:_fb .Log .Debug ("\u0073\u006b\u0069\u0070\u0070i\u006e\u0067\u0020\u0075\u006e\u0073\u0075\u0070\u0070\u006f\u0072\u0074\u0065d\u0020\u0065\u006c\u0065\u006d\u0065\u006e\u0074\u0020\u006f\u006e\u0020\u0043\u0054\u005f\u0054\u0077\u006f\u0043\u0065\u006c\u006c\u0041\u006e\u0063\u0068\u006f\u0072\u0020\u0025v",_bgag .Name );
+_fag !=nil {return _fag ;};case _e .Name {Space :mcNamespace ,Local :"AlternateContent"}:if _err :=decodeAlternateObject (d ,&_gabc .ObjectChoicesChoice );_err !=nil {return _err ;};default:_fb .Log .Debug ("\u0073\u006b\u0069\u0070\u0070i\u006e\u0067\u0020\u0075\u006e\u0073\u0075\u0070\u0070\u006f\u0072\u0074\u0065d\u0020\u0065\u006c\u0065\u006d\u0065\u006e\u0074\u0020\u006f\u006e\u0020\u0043\u0054\u005f\u0054\u0077\u006f\u0043\u0065\u006c\u006c\u0041\u006e\u0063\u0068\u006f\u0072\u0020\u0025v",_bgag .Name );
 if _afcc :=d .Skip ();_afcc !=nil {return _afcc ;};};case _e .EndElement :break _aef ;case _e .CharData :};};return nil ;};func NewCT_Drawing ()*CT_Drawing {_fcc :=&CT_Drawing {};return _fcc };func (_ccg *CT_Picture )MarshalXML (e *_e .Encoder ,start _e .StartElement )error {if _ccg .MacroAttr !=nil {start .Attr =append (start .Attr ,_e .Attr {Name :_e .Name {Local :"\u006d\u0061\u0063r\u006f"},Value :_ef .Sprintf ("\u0025\u0076",*_ccg .MacroAttr )});
 };if _ccg .FPublishedAttr !=nil {start .Attr =append (start .Attr ,_e .Attr {Name :_e .Name {Local :"\u0066\u0050\u0075\u0062\u006c\u0069\u0073\u0068\u0065\u0064"},Value :_ef .Sprintf ("\u0025\u0064",_eeae (*_ccg .FPublishedAttr ))});};e .EncodeToken (start );
 _edd :=_e .StartElement {Name :_e .Name {Local :"x\u0064\u0072\u003a\u006e\u0076\u0050\u0069\u0063\u0050\u0072"}};e .EncodeElement (_ccg .NvPicPr ,_edd );_bca :=_e .StartElement {Name :_e .Name {Local :"\u0078\u0064\u0072:\u0062\u006c\u0069\u0070\u0046\u0069\u006c\u006c"}};
@@ -281,7 +285,7 @@ if _fbg :=d .DecodeElement (_afdf .Pic ,&_aeb );_fbg !=nil {return _fbg ;};defau
 if _gc :=d .Skip ();_gc !=nil {return _gc ;};};return nil ;};func (_gage *WsDr )UnmarshalXML (d *_e .Decoder ,start _e .StartElement )error {_gage .CT_Drawing =*NewCT_Drawing ();_cce :for {_bdeb ,_fcbf :=d .Token ();if _fcbf !=nil {return _fcbf ;};switch _dbcg :=_bdeb .(type ){case _e .StartElement :switch _dbcg .Name {case _e .Name {Space :"\u0068\u0074\u0074\u0070\u003a\u002f/\u0073\u0063\u0068\u0065\u006da\u0073\u002e\u006f\u0070\u0065\u006ex\u006d\u006c\u0066\u006f\u0072m\u0061\u0074\u0073\u002e\u006f\u0072\u0067\u002f\u0064\u0072\u0061\u0077i\u006e\u0067\u006d\u006c\u002f\u0032\u0030\u0030\u0036\u002f\u0073\u0070\u0072\u0065\u0061d\u0073\u0068\u0065\u0065\u0074\u0044\u0072\u0061w\u0069\u006e\u0067",Local :"\u0074\u0077\u006f\u0043\u0065\u006c\u006c\u0041\u006e\u0063\u0068\u006f\u0072"},_e .Name {Space :"\u0068\u0074\u0074\u0070\u003a\u002f\u002fp\u0075\u0072\u006c.\u006f\u0063\u006cc\u002e\u006fr\u0067\u002f\u006f\u006f\u0078\u006dl\u002fdr\u0061\u0077\u0069\u006e\u0067\u006d\u006c\u002f\u0073\u0070\u0072\u0065\u0061\u0064\u0073\u0068\u0065\u0065\u0074\u0044\u0072\u0061\u0077\u0069\u006e\u0067",Local :"\u0074\u0077\u006f\u0043\u0065\u006c\u006c\u0041\u006e\u0063\u0068\u006f\u0072"}:_bedbc :=NewEG_Anchor ();
 _bedbc .AnchorChoice =NewEG_AnchorChoice ();_gage .EG_Anchor =append (_gage .EG_Anchor ,_bedbc );if _cbbc :=d .DecodeElement (&_bedbc .AnchorChoice .TwoCellAnchor ,&_dbcg );_cbbc !=nil {return _cbbc ;};case _e .Name {Space :"\u0068\u0074\u0074\u0070\u003a\u002f/\u0073\u0063\u0068\u0065\u006da\u0073\u002e\u006f\u0070\u0065\u006ex\u006d\u006c\u0066\u006f\u0072m\u0061\u0074\u0073\u002e\u006f\u0072\u0067\u002f\u0064\u0072\u0061\u0077i\u006e\u0067\u006d\u006c\u002f\u0032\u0030\u0030\u0036\u002f\u0073\u0070\u0072\u0065\u0061d\u0073\u0068\u0065\u0065\u0074\u0044\u0072\u0061w\u0069\u006e\u0067",Local :"\u006f\u006e\u0065\u0043\u0065\u006c\u006c\u0041\u006e\u0063\u0068\u006f\u0072"},_e .Name {Space :"\u0068\u0074\u0074\u0070\u003a\u002f\u002fp\u0075\u0072\u006c.\u006f\u0063\u006cc\u002e\u006fr\u0067\u002f\u006f\u006f\u0078\u006dl\u002fdr\u0061\u0077\u0069\u006e\u0067\u006d\u006c\u002f\u0073\u0070\u0072\u0065\u0061\u0064\u0073\u0068\u0065\u0065\u0074\u0044\u0072\u0061\u0077\u0069\u006e\u0067",Local :"\u006f\u006e\u0065\u0043\u0065\u006c\u006c\u0041\u006e\u0063\u0068\u006f\u0072"}:_abdb :=NewEG_Anchor ();
 _abdb .AnchorChoice =NewEG_AnchorChoice ();_gage .EG_Anchor =append (_gage .EG_Anchor ,_abdb );if _fbce :=d .DecodeElement (&_abdb .AnchorChoice .OneCellAnchor ,&_dbcg );_fbce !=nil {return _fbce ;};case _e .Name {Space :"\u0068\u0074\u0074\u0070\u003a\u002f/\u0073\u0063\u0068\u0065\u006da\u0073\u002e\u006f\u0070\u0065\u006ex\u006d\u006c\u0066\u006f\u0072m\u0061\u0074\u0073\u002e\u006f\u0072\u0067\u002f\u0064\u0072\u0061\u0077i\u006e\u0067\u006d\u006c\u002f\u0032\u0030\u0030\u0036\u002f\u0073\u0070\u0072\u0065\u0061d\u0073\u0068\u0065\u0065\u0074\u0044\u0072\u0061w\u0069\u006e\u0067",Local :"\u0061\u0062\u0073\u006f\u006c\u0075\u0074\u0065\u0041n\u0063\u0068\u006f\u0072"},_e .Name {Space :"\u0068\u0074\u0074\u0070\u003a\u002f\u002fp\u0075\u0072\u006c.\u006f\u0063\u006cc\u002e\u006fr\u0067\u002f\u006f\u006f\u0078\u006dl\u002fdr\u0061\u0077\u0069\u006e\u0067\u006d\u006c\u002f\u0073\u0070\u0072\u0065\u0061\u0064\u0073\u0068\u0065\u0065\u0074\u0044\u0072\u0061\u0077\u0069\u006e\u0067",Local :"\u0061\u0062\u0073\u006f\u006c\u0075\u0074\u0065\u0041n\u0063\u0068\u006f\u0072"}:_adda :=NewEG_Anchor ();
-_adda .AnchorChoice =NewEG_AnchorChoice ();_gage .EG_Anchor =append (_gage .EG_Anchor ,_adda );if _cgbd :=d .DecodeElement (&_adda .AnchorChoice .AbsoluteAnchor ,&_dbcg );_cgbd !=nil {return _cgbd ;};default:_fb .Log .Debug ("\u0073\u006b\u0069\u0070\u0070\u0069\u006e\u0067\u0020\u0075\u006e\u0073\u0075p\u0070\u006f\u0072\u0074\u0065\u0064 \u0065\u006c\u0065\u006d\u0065\u006e\u0074\u0020\u006f\u006e\u0020\u0057\u0073D\u0072\u0020\u0025\u0076",_dbcg .Name );
+_adda .AnchorChoice =NewEG_AnchorChoice ();_gage .EG_Anchor =append (_gage .EG_Anchor ,_adda );if _cgbd :=d .DecodeElement (&_adda .AnchorChoice .AbsoluteAnchor ,&_dbcg );_cgbd !=nil {return _cgbd ;};case _e .Name {Space :mcNamespace ,Local :"AlternateContent"}:if _err :=decodeAlternateAnchors (d ,&_gage .CT_Drawing );_err !=nil {return _err ;};default:_fb .Log .Debug ("\u0073\u006b\u0069\u0070\u0070\u0069\u006e\u0067\u0020\u0075\u006e\u0073\u0075p\u0070\u006f\u0072\u0074\u0065\u0064 \u0065\u006c\u0065\u006d\u0065\u006e\u0074\u0020\u006f\u006e\u0020\u0057\u0073D\u0072\u0020\u0025\u0076",_dbcg .Name );
 if _cceb :=d .Skip ();_cceb !=nil {return _cceb ;};};case _e .EndElement :break _cce ;case _e .CharData :};};return nil ;};type CT_PictureNonVisual struct{CNvPr *_ed .CT_NonVisualDrawingProps ;
 
 // Non-Visual Picture Drawing Properties
@@ -509,7 +513,7 @@ NvPicPr *CT_PictureNonVisual ;
 BlipFill *_ed .CT_BlipFillProperties ;SpPr *_ed .CT_ShapeProperties ;
 
 // Shape Style
-Style *_ed .CT_ShapeStyle ;};func NewCT_Rel ()*CT_Rel {_edf :=&CT_Rel {};return _edf };type ST_EditAs byte ;func (_ddfc *EG_ObjectChoicesChoice )MarshalXML (e *_e .Encoder ,start _e .StartElement )error {if _ddfc .Sp !=nil {_becf :=_e .StartElement {Name :_e .Name {Local :"\u0078\u0064\u0072\u003a\u0073\u0070"}};
+Style *_ed .CT_ShapeStyle ;};func NewCT_Rel ()*CT_Rel {_edf :=&CT_Rel {};return _edf };type ST_EditAs byte ;func (_ddfc *EG_ObjectChoicesChoice )MarshalXML (e *_e .Encoder ,start _e .StartElement )error {if _ddfc .AlternateContent !=nil {return _ddfc .AlternateContent .marshal (e ,_ddfc );};if _ddfc .Sp !=nil {_becf :=_e .StartElement {Name :_e .Name {Local :"\u0078\u0064\u0072\u003a\u0073\u0070"}};
 e .EncodeElement (_ddfc .Sp ,_becf );}else if _ddfc .GrpSp !=nil {_adgg :=_e .StartElement {Name :_e .Name {Local :"\u0078d\u0072\u003a\u0067\u0072\u0070\u0053p"}};e .EncodeElement (_ddfc .GrpSp ,_adgg );}else if _ddfc .GraphicFrame !=nil {_eecg :=_e .StartElement {Name :_e .Name {Local :"\u0078\u0064r\u003a\u0067\u0072a\u0070\u0068\u0069\u0063\u0046\u0072\u0061\u006d\u0065"}};
 e .EncodeElement (_ddfc .GraphicFrame ,_eecg );}else if _ddfc .CxnSp !=nil {_cege :=_e .StartElement {Name :_e .Name {Local :"\u0078d\u0072\u003a\u0063\u0078\u006e\u0053p"}};e .EncodeElement (_ddfc .CxnSp ,_cege );}else if _ddfc .Pic !=nil {_efcg :=_e .StartElement {Name :_e .Name {Local :"\u0078d\u0072\u003a\u0070\u0069\u0063"}};
 e .EncodeElement (_ddfc .Pic ,_efcg );}else if _ddfc .ContentPart !=nil {_abbc :=_e .StartElement {Name :_e .Name {Local :"\u0078d\u0072:\u0063\u006f\u006e\u0074\u0065\u006e\u0074\u0050\u0061\u0072\u0074"}};e .EncodeElement (_ddfc .ContentPart ,_abbc );
@@ -0,0 +1,169 @@
+package pml
+
+import (
+	"encoding/xml"
+	"sort"
+)
+
+const mcNamespace = "http://schemas.openxmlformats.org/markup-compatibility/2006"
+
+// AlternateContent is a markup compatibility block around a shape. The shape
+// is the choice displayed by the applications that understand the required
+// namespaces, the others display the fallback.
+type AlternateContent struct {
+	// Requires are the space separated prefixes of the namespaces the choice
+	// requires.
+	Requires string
+	// Namespaces are the namespaces of the prefixes by prefix.
+	Namespaces map[string]string
+	Fallback   *CT_GroupShapeChoice
+}
+
+// decodeAlternateShape decodes the shape of an alternate content block in a
+// group shape. A shape chosen from a choice keeps the block so the fallback is
+// written back.
+func decodeAlternateShape(d *xml.Decoder, g *CT_GroupShape) error {
+	var choice, fallback *CT_GroupShapeChoice
+	var ac *AlternateContent
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch el := tok.(type) {
+		case xml.StartElement:
+			if el.Name.Space != mcNamespace || (el.Name.Local != "Choice" && el.Name.Local != "Fallback") {
+				if err := d.Skip(); err != nil {
+					return err
+				}
+				continue
+			}
+			shape, err := decodeShape(d)
+			if err != nil {
+				return err
+			}
+			if shape == nil {
+				continue
+			}
+			if el.Name.Local == "Fallback" {
+				fallback = shape
+				continue
+			}
+			if ac != nil {
+				continue
+			}
+			ac = &AlternateContent{Namespaces: map[string]string{}}
+			for _, a := range el.Attr {
+				switch {
+				case a.Name.Space == "" && a.Name.Local == "Requires":
+					ac.Requires = a.Value
+				case a.Name.Space == "xmlns":
+					ac.Namespaces[a.Name.Local] = a.Value
+				}
+			}
+			choice = shape
+		case xml.EndElement:
+			if choice != nil {
+				ac.Fallback = fallback
+				choice.AlternateContent = ac
+			} else {
+				choice = fallback
+			}
+			if choice != nil {
+				g.GroupShapeChoice = append(g.GroupShapeChoice, choice)
+			}
+			return nil
+		}
+	}
+}
+
+// decodeShape decodes the first shape of a choice or fallback.
+func decodeShape(d *xml.Decoder) (*CT_GroupShapeChoice, error) {
+	var shape *CT_GroupShapeChoice
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch el := tok.(type) {
+		case xml.StartElement:
+			if shape == nil {
+				var err error
+				switch el.Name.Local {
+				case "sp":
+					shape = NewCT_GroupShapeChoice()
+					err = d.DecodeElement(&shape.Sp, &el)
+				case "grpSp":
+					shape = NewCT_GroupShapeChoice()
+					err = d.DecodeElement(&shape.GrpSp, &el)
+				case "graphicFrame":
+					shape = NewCT_GroupShapeChoice()
+					err = d.DecodeElement(&shape.GraphicFrame, &el)
+				case "cxnSp":
+					shape = NewCT_GroupShapeChoice()
+					err = d.DecodeElement(&shape.CxnSp, &el)
+				case "pic":
+					shape = NewCT_GroupShapeChoice()
+					err = d.DecodeElement(&shape.Pic, &el)
+				case "contentPart":
+					shape = NewCT_GroupShapeChoice()
+					err = d.DecodeElement(&shape.ContentPart, &el)
+				default:
+					err = d.Skip()
+				}
+				if err != nil {
+					return nil, err
+				}
+				continue
+			}
+			if err := d.Skip(); err != nil {
+				return nil, err
+			}
+		case xml.EndElement:
+			return shape, nil
+		}
+	}
+}
+
+// marshal writes the block with shape as the choice.
+func (ac *AlternateContent) marshal(e *xml.Encoder, shape *CT_GroupShapeChoice) error {
+	choice := *shape
+	choice.AlternateContent = nil
+	block := xml.StartElement{Name: xml.Name{Local: "mc:AlternateContent"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "xmlns:mc"}, Value: mcNamespace}}}
+	if err := e.EncodeToken(block); err != nil {
+		return err
+	}
+	prefixes := make([]string, 0, len(ac.Namespaces))
+	for p := range ac.Namespaces {
+		prefixes = append(prefixes, p)
+	}
+	sort.Strings(prefixes)
+	start := xml.StartElement{Name: xml.Name{Local: "mc:Choice"}}
+	for _, p := range prefixes {
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "xmlns:" + p}, Value: ac.Namespaces[p]})
+	}
+	start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "Requires"}, Value: ac.Requires})
+	if err := encodeWrapped(e, start, &choice); err != nil {
+		return err
+	}
+	if ac.Fallback != nil {
+		start = xml.StartElement{Name: xml.Name{Local: "mc:Fallback"}}
+		if err := encodeWrapped(e, start, ac.Fallback); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(block.End())
+}
+
+// encodeWrapped writes shape within the start element, shapes choose their
+// own element name.
+func encodeWrapped(e *xml.Encoder, start xml.StartElement, shape *CT_GroupShapeChoice) error {
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	if err := shape.MarshalXML(e, xml.StartElement{}); err != nil {
+		return err
+	}
+	return e.EncodeToken(start.End())
+}
@@ -0,0 +1,74 @@
+package pml
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+// testAlternateTree is a shape tree as written by PowerPoint with a chartex
+// frame within an alternate content block.
+const testAlternateTree = `<p:spTree xmlns:p="http://schemas.openxmlformats.org/presentationml/2006/main" xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main" xmlns:mc="http://schemas.openxmlformats.org/markup-compatibility/2006">
+<p:nvGrpSpPr><p:cNvPr id="1" name=""/><p:cNvGrpSpPr/><p:nvPr/></p:nvGrpSpPr><p:grpSpPr/>
+<p:sp><p:nvSpPr><p:cNvPr id="2" name="Title"/><p:cNvSpPr/><p:nvPr/></p:nvSpPr><p:spPr/></p:sp>
+<mc:AlternateContent>
+<mc:Choice xmlns:cx1="http://schemas.microsoft.com/office/drawing/2015/9/8/chartex" Requires="cx1">
+<p:graphicFrame><p:nvGraphicFramePr><p:cNvPr id="3" name="Chart 3"/><p:cNvGraphicFramePr/><p:nvPr/></p:nvGraphicFramePr><p:xfrm><a:off x="0" y="0"/><a:ext cx="100" cy="100"/></p:xfrm><a:graphic><a:graphicData uri="http://schemas.microsoft.com/office/drawing/2014/chartex"/></a:graphic></p:graphicFrame>
+</mc:Choice>
+<mc:Fallback>
+<p:sp><p:nvSpPr><p:cNvPr id="3" name="Chart 3"/><p:cNvSpPr/><p:nvPr/></p:nvSpPr><p:spPr/></p:sp>
+</mc:Fallback>
+</mc:AlternateContent>
+<mc:AlternateContent>
+<mc:Choice Requires="unknown"><p:unknown/></mc:Choice>
+<mc:Fallback><p:sp><p:nvSpPr><p:cNvPr id="4" name="Shape 4"/><p:cNvSpPr/><p:nvPr/></p:nvSpPr><p:spPr/></p:sp></mc:Fallback>
+</mc:AlternateContent>
+</p:spTree>`
+
+func TestAlternateContentRoundTrip(t *testing.T) {
+	g := NewCT_GroupShape()
+	if err := xml.Unmarshal([]byte(testAlternateTree), g); err != nil {
+		t.Fatalf("error decoding: %s", err)
+	}
+	if len(g.GroupShapeChoice) != 3 {
+		t.Fatalf("expected 3 shapes, got %d", len(g.GroupShapeChoice))
+	}
+
+	frame := g.GroupShapeChoice[1]
+	ac := frame.AlternateContent
+	if frame.GraphicFrame == nil || ac == nil {
+		t.Fatalf("expected the frame chosen with its alternate content")
+	}
+	if ac.Requires != "cx1" || ac.Namespaces["cx1"] != "http://schemas.microsoft.com/office/drawing/2015/9/8/chartex" {
+		t.Errorf("unexpected requirements %q %v", ac.Requires, ac.Namespaces)
+	}
+	if ac.Fallback == nil || ac.Fallback.Sp == nil || ac.Fallback.Sp.NvSpPr.CNvPr.NameAttr != "Chart 3" {
+		t.Errorf("expected the fallback shape")
+	}
+
+	// a choice that can't be decoded falls back without alternate content
+	if sp := g.GroupShapeChoice[2]; sp.Sp == nil || sp.AlternateContent != nil || sp.Sp.NvSpPr.CNvPr.IdAttr != 4 {
+		t.Errorf("expected the fallback of the unknown choice")
+	}
+
+	data, err := xml.Marshal(g)
+	if err != nil {
+		t.Fatalf("error encoding: %s", err)
+	}
+	s := string(data)
+	if strings.Count(s, "<mc:AlternateContent") != 1 || !strings.Contains(s, `<mc:Choice xmlns:cx1="http://schemas.microsoft.com/office/drawing/2015/9/8/chartex" Requires="cx1">`) ||
+		!strings.Contains(s, "<mc:Fallback>") {
+		t.Errorf("expected the alternate content to be written back, got %s", s)
+	}
+
+	// the namespaces are declared by the slide when written within it
+	s = strings.Replace(s, "<CT_GroupShape>", testAlternateTree[:strings.Index(testAlternateTree, ">")+1], 1)
+	s = strings.Replace(s, "</CT_GroupShape>", "</p:spTree>", 1)
+	read := NewCT_GroupShape()
+	if err := xml.Unmarshal([]byte(s), read); err != nil {
+		t.Fatalf("error decoding: %s", err)
+	}
+	if len(read.GroupShapeChoice) != 3 || read.GroupShapeChoice[1].AlternateContent == nil || read.GroupShapeChoice[1].AlternateContent.Fallback == nil {
+		t.Errorf("expected the alternate content to be read back")
+	}
+}
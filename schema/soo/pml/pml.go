@@ -1241,7 +1241,7 @@ if _gadae :=d .DecodeElement (&_afgda .GraphicFrame ,&_gegec );_gadae !=nil {ret
 if _beaa :=d .DecodeElement (&_ddce .CxnSp ,&_gegec );_beaa !=nil {return _beaa ;};_cdcb .GroupShapeChoice =append (_cdcb .GroupShapeChoice ,_ddce );case _f .Name {Space :"\u0068\u0074\u0074\u0070\u003a\u002f\u002f\u0073\u0063\u0068\u0065\u006d\u0061\u0073\u002e\u006f\u0070\u0065\u006e\u0078m\u006c\u0066\u006f\u0072\u006d\u0061\u0074\u0073\u002eo\u0072\u0067\u002f\u0070\u0072\u0065\u0073\u0065\u006e\u0074\u0061\u0074\u0069o\u006e\u006d\u006c\u002f\u0032\u00300\u0036\u002f\u006da\u0069\u006e",Local :"\u0070\u0069\u0063"},_f .Name {Space :"\u0068\u0074t\u0070\u003a\u002f\u002f\u0070\u0075\u0072\u006c\u002e\u006f\u0063\u006c\u0063\u002e\u006f\u0072\u0067\u002f\u006f\u006f\u0078\u006d\u006c\u002f\u0070\u0072\u0065\u0073\u0065\u006e\u0074\u0061\u0074\u0069\u006f\u006e\u006d\u006c\u002f\u006d\u0061\u0069\u006e",Local :"\u0070\u0069\u0063"}:_bccff :=NewCT_GroupShapeChoice ();
 if _cbed :=d .DecodeElement (&_bccff .Pic ,&_gegec );_cbed !=nil {return _cbed ;};_cdcb .GroupShapeChoice =append (_cdcb .GroupShapeChoice ,_bccff );case _f .Name {Space :"\u0068\u0074\u0074\u0070\u003a\u002f\u002f\u0073\u0063\u0068\u0065\u006d\u0061\u0073\u002e\u006f\u0070\u0065\u006e\u0078m\u006c\u0066\u006f\u0072\u006d\u0061\u0074\u0073\u002eo\u0072\u0067\u002f\u0070\u0072\u0065\u0073\u0065\u006e\u0074\u0061\u0074\u0069o\u006e\u006d\u006c\u002f\u0032\u00300\u0036\u002f\u006da\u0069\u006e",Local :"c\u006f\u006e\u0074\u0065\u006e\u0074\u0050\u0061\u0072\u0074"},_f .Name {Space :"\u0068\u0074t\u0070\u003a\u002f\u002f\u0070\u0075\u0072\u006c\u002e\u006f\u0063\u006c\u0063\u002e\u006f\u0072\u0067\u002f\u006f\u006f\u0078\u006d\u006c\u002f\u0070\u0072\u0065\u0073\u0065\u006e\u0074\u0061\u0074\u0069\u006f\u006e\u006d\u006c\u002f\u006d\u0061\u0069\u006e",Local :"c\u006f\u006e\u0074\u0065\u006e\u0074\u0050\u0061\u0072\u0074"}:_fbed :=NewCT_GroupShapeChoice ();
 if _fac :=d .DecodeElement (&_fbed .ContentPart ,&_gegec );_fac !=nil {return _fac ;};_cdcb .GroupShapeChoice =append (_cdcb .GroupShapeChoice ,_fbed );case _f .Name {Space :"\u0068\u0074\u0074\u0070\u003a\u002f\u002f\u0073\u0063\u0068\u0065\u006d\u0061\u0073\u002e\u006f\u0070\u0065\u006e\u0078m\u006c\u0066\u006f\u0072\u006d\u0061\u0074\u0073\u002eo\u0072\u0067\u002f\u0070\u0072\u0065\u0073\u0065\u006e\u0074\u0061\u0074\u0069o\u006e\u006d\u006c\u002f\u0032\u00300\u0036\u002f\u006da\u0069\u006e",Local :"\u0065\u0078\u0074\u004c\u0073\u0074"},_f .Name {Space :"\u0068\u0074t\u0070\u003a\u002f\u002f\u0070\u0075\u0072\u006c\u002e\u006f\u0063\u006c\u0063\u002e\u006f\u0072\u0067\u002f\u006f\u006f\u0078\u006d\u006c\u002f\u0070\u0072\u0065\u0073\u0065\u006e\u0074\u0061\u0074\u0069\u006f\u006e\u006d\u006c\u002f\u006d\u0061\u0069\u006e",Local :"\u0065\u0078\u0074\u004c\u0073\u0074"}:_cdcb .ExtLst =NewCT_ExtensionListModify ();
-if _gaba :=d .DecodeElement (_cdcb .ExtLst ,&_gegec );_gaba !=nil {return _gaba ;};default:_bc .Log .Debug ("\u0073\u006b\u0069\u0070\u0070\u0069\u006e\u0067 \u0075\u006e\u0073up\u0070\u006f\u0072\u0074\u0065\u0064 \u0065\u006c\u0065\u006d\u0065\u006e\u0074\u0020\u006f\u006e\u0020\u0043\u0054\u005f\u0047r\u006f\u0075\u0070\u0053\u0068\u0061\u0070\u0065 \u0025\u0076",_gegec .Name );
+if _gaba :=d .DecodeElement (_cdcb .ExtLst ,&_gegec );_gaba !=nil {return _gaba ;};case _f .Name {Space :mcNamespace ,Local :"AlternateContent"}:if _err :=decodeAlternateShape (d ,_cdcb );_err !=nil {return _err ;};default:_bc .Log .Debug ("\u0073\u006b\u0069\u0070\u0070\u0069\u006e\u0067 \u0075\u006e\u0073up\u0070\u006f\u0072\u0074\u0065\u0064 \u0065\u006c\u0065\u006d\u0065\u006e\u0074\u0020\u006f\u006e\u0020\u0043\u0054\u005f\u0047r\u006f\u0075\u0070\u0053\u0068\u0061\u0070\u0065 \u0025\u0076",_gegec .Name );
 if _ggbd :=d .Skip ();_ggbd !=nil {return _ggbd ;};};case _f .EndElement :break _ffab ;case _f .CharData :};};return nil ;};
 
 // ValidateWithPath validates the CT_SplitTransition and its children, prefixing error messages with path
@@ -2061,7 +2061,11 @@ e .EncodeElement (_decfc .SubTnLst ,_dfda );};e .EncodeToken (_f .EndElement {Na
 
 // ValidateWithPath validates the CT_TLAnimateRotationBehavior and its children, prefixing error messages with path
 func (_eadbf *CT_TLAnimateRotationBehavior )ValidateWithPath (path string )error {if _ggddd :=_eadbf .CBhvr .ValidateWithPath (path +"\u002f\u0043\u0042\u0068\u0076\u0072");_ggddd !=nil {return _ggddd ;};return nil ;};type CT_GroupShapeChoice struct{Sp *CT_Shape ;
-GrpSp *CT_GroupShape ;GraphicFrame *CT_GraphicalObjectFrame ;CxnSp *CT_Connector ;Pic *CT_Picture ;ContentPart *CT_Rel ;};func (_dg *AG_Ole )UnmarshalXML (d *_f .Decoder ,start _f .StartElement )error {for _ ,_aa :=range start .Attr {if _aa .Name .Space =="\u0068\u0074\u0074\u0070\u003a\u002f/\u0073\u0063\u0068\u0065\u006da\u0073\u002e\u006f\u0070\u0065\u006ex\u006d\u006c\u0066\u006f\u0072m\u0061\u0074\u0073\u002e\u006f\u0072\u0067\u002f\u006f\u0066\u0066\u0069c\u0065\u0044\u006f\u0063\u0075\u006d\u0065\u006e\u0074\u002f\u0032\u0030\u0030\u0036\u002fr\u0065\u006c\u0061\u0074\u0069\u006f\u006e\u0073h\u0069\u0070\u0073"&&_aa .Name .Local =="\u0069\u0064"{_ee :=_aa .Value ;
+GrpSp *CT_GroupShape ;GraphicFrame *CT_GraphicalObjectFrame ;CxnSp *CT_Connector ;Pic *CT_Picture ;ContentPart *CT_Rel ;
+
+// AlternateContent, if set, writes the shape as the choice of a markup
+// compatibility block with a fallback for older applications.
+AlternateContent *AlternateContent ;};func (_dg *AG_Ole )UnmarshalXML (d *_f .Decoder ,start _f .StartElement )error {for _ ,_aa :=range start .Attr {if _aa .Name .Space =="\u0068\u0074\u0074\u0070\u003a\u002f/\u0073\u0063\u0068\u0065\u006da\u0073\u002e\u006f\u0070\u0065\u006ex\u006d\u006c\u0066\u006f\u0072m\u0061\u0074\u0073\u002e\u006f\u0072\u0067\u002f\u006f\u0066\u0066\u0069c\u0065\u0044\u006f\u0063\u0075\u006d\u0065\u006e\u0074\u002f\u0032\u0030\u0030\u0036\u002fr\u0065\u006c\u0061\u0074\u0069\u006f\u006e\u0073h\u0069\u0070\u0073"&&_aa .Name .Local =="\u0069\u0064"{_ee :=_aa .Value ;
 _dg .IdAttr =&_ee ;continue ;};if _aa .Name .Local =="\u0073\u0070\u0069\u0064"{_fd :=_aa .Value ;_dg .SpidAttr =&_fd ;continue ;};if _aa .Name .Local =="\u006e\u0061\u006d\u0065"{_bba :=_aa .Value ;_dg .NameAttr =&_bba ;continue ;};if _aa .Name .Local =="\u0073\u0068\u006f\u0077\u0041\u0073\u0049\u0063\u006f\u006e"{_ad ,_aag :=_a .ParseBool (_aa .Value );
 if _aag !=nil {return _aag ;};_dg .ShowAsIconAttr =&_ad ;continue ;};if _aa .Name .Local =="\u0069\u006d\u0067\u0057"{_ab ,_cb :=_a .ParseInt (_aa .Value ,10,32);if _cb !=nil {return _cb ;};_adg :=int32 (_ab );_dg .ImgWAttr =&_adg ;continue ;};if _aa .Name .Local =="\u0069\u006d\u0067\u0048"{_dd ,_fgg :=_a .ParseInt (_aa .Value ,10,32);
 if _fgg !=nil {return _fgg ;};_fdf :=int32 (_dd );_dg .ImgHAttr =&_fdf ;continue ;};};for {_ef ,_cfa :=d .Token ();if _cfa !=nil {return _ec .Errorf ("\u0070a\u0072s\u0069\u006e\u0067\u0020\u0041G\u005f\u004fl\u0065\u003a\u0020\u0025\u0073",_cfa );};if _gca ,_ccg :=_ef .(_f .EndElement );
@@ -4870,7 +4874,7 @@ for _ ,_aefa :=range _fbfa .Tag {e .EncodeElement (_aefa ,_egffg );};};e .Encode
 
 // ValidateWithPath validates the SldSyncPr and its children, prefixing error messages with path
 func (_aecdf *SldSyncPr )ValidateWithPath (path string )error {if _acfed :=_aecdf .CT_SlideSyncProperties .ValidateWithPath (path );_acfed !=nil {return _acfed ;};return nil ;};func (_bgfcde *ST_TLCommandType )UnmarshalXMLAttr (attr _f .Attr )error {switch attr .Value {case "":*_bgfcde =0;
-case "\u0065\u0076\u0074":*_bgfcde =1;case "\u0063\u0061\u006c\u006c":*_bgfcde =2;case "\u0076\u0065\u0072\u0062":*_bgfcde =3;};return nil ;};func (_effc *CT_GroupShapeChoice )MarshalXML (e *_f .Encoder ,start _f .StartElement )error {if _effc .Sp !=nil {_fgffc :=_f .StartElement {Name :_f .Name {Local :"\u0070\u003a\u0073\u0070"}};
+case "\u0065\u0076\u0074":*_bgfcde =1;case "\u0063\u0061\u006c\u006c":*_bgfcde =2;case "\u0076\u0065\u0072\u0062":*_bgfcde =3;};return nil ;};func (_effc *CT_GroupShapeChoice )MarshalXML (e *_f .Encoder ,start _f .StartElement )error {if _effc .AlternateContent !=nil {return _effc .AlternateContent .marshal (e ,_effc );};if _effc .Sp !=nil {_fgffc :=_f .StartElement {Name :_f .Name {Local :"\u0070\u003a\u0073\u0070"}};
 e .EncodeElement (_effc .Sp ,_fgffc );}else if _effc .GrpSp !=nil {_agcg :=_f .StartElement {Name :_f .Name {Local :"\u0070:\u0067\u0072\u0070\u0053\u0070"}};e .EncodeElement (_effc .GrpSp ,_agcg );}else if _effc .GraphicFrame !=nil {_dffc :=_f .StartElement {Name :_f .Name {Local :"\u0070\u003a\u0067\u0072\u0061\u0070\u0068\u0069\u0063F\u0072\u0061\u006d\u0065"}};
 e .EncodeElement (_effc .GraphicFrame ,_dffc );}else if _effc .CxnSp !=nil {_dddb :=_f .StartElement {Name :_f .Name {Local :"\u0070:\u0063\u0078\u006e\u0053\u0070"}};e .EncodeElement (_effc .CxnSp ,_dddb );}else if _effc .Pic !=nil {_bcca :=_f .StartElement {Name :_f .Name {Local :"\u0070\u003a\u0070i\u0063"}};
 e .EncodeElement (_effc .Pic ,_bcca );}else if _effc .ContentPart !=nil {_gbf :=_f .StartElement {Name :_f .Name {Local :"\u0070\u003a\u0063\u006f\u006e\u0074\u0065\u006e\u0074\u0050\u0061\u0072\u0074"}};e .EncodeElement (_effc .ContentPart ,_gbf );};return nil ;
@@ -1,6 +1,9 @@
 package wml
 
-import "encoding/xml"
+import (
+	"encoding/xml"
+	"sort"
+)
 
 // SetRequires sets the mc:Choice Requires attribute value.
 // This is needed because the _egdddc field on AC_ChoiceRun is private.
@@ -35,9 +38,14 @@ func (f *FallbackDrawing) UnmarshalXML(d *xml.Decoder, start xml.StartElement) e
 
 // AlternateContentSVGRun is an SVG-aware version of AlternateContentRun
 // that includes the asvg namespace declaration required for SVG embedding.
+// The choice requires asvg unless another prefix is set with
+// AC_ChoiceRun.SetRequires.
 type AlternateContentSVGRun struct {
 	Choice   *AC_ChoiceRun
 	Fallback *FallbackDrawing
+	// Namespaces are the namespaces declared on mc:Choice by prefix, needed
+	// when the choice requires a namespace other than asvg.
+	Namespaces map[string]string
 }
 
 // acElementName is the fully-qualified mc:AlternateContent element name
@@ -83,12 +91,20 @@ func (a *AlternateContentSVGRun) MarshalXML(e *xml.Encoder, start xml.StartEleme
 
 	if a.Choice != nil {
 		// Use EncodeElement which writes the start tag, content, and end tag.
-		choiceStart := xml.StartElement{
-			Name: xml.Name{Local: "mc:Choice"},
-			Attr: []xml.Attr{
-				{Name: xml.Name{Local: "Requires"}, Value: "asvg"},
-			},
+		requires := a.Choice._egdddc
+		if requires == "" {
+			requires = "asvg"
 		}
+		choiceStart := xml.StartElement{Name: xml.Name{Local: "mc:Choice"}}
+		prefixes := make([]string, 0, len(a.Namespaces))
+		for p := range a.Namespaces {
+			prefixes = append(prefixes, p)
+		}
+		sort.Strings(prefixes)
+		for _, p := range prefixes {
+			choiceStart.Attr = append(choiceStart.Attr, xml.Attr{Name: xml.Name{Local: "xmlns:" + p}, Value: a.Namespaces[p]})
+		}
+		choiceStart.Attr = append(choiceStart.Attr, xml.Attr{Name: xml.Name{Local: "Requires"}, Value: requires})
 		if err := e.EncodeElement(a.Choice, choiceStart); err != nil {
 			return err
 		}
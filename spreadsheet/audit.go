@@ -0,0 +1,444 @@
+package spreadsheet
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yaklabco/unioffice/v2/spreadsheet/formula"
+	"github.com/yaklabco/unioffice/v2/spreadsheet/reference"
+)
+
+// AuditReference is a cell or range found when tracing the precedents or
+// dependents of a cell.
+type AuditReference struct {
+	Sheet string
+	// Reference is the cell or range without sheet name or absolute markers,
+	// e.g. B2 or A1:C10.
+	Reference string
+	// Name is the defined name the reference was reached through, if any.
+	Name string
+	// Depth is 1 for direct precedents or dependents, 2 for their precedents or
+	// dependents and so on.
+	Depth int
+}
+
+func (a AuditReference) String() string {
+	return quoteSheetName(a.Sheet) + "!" + a.Reference
+}
+
+// quoteSheetName quotes a sheet name for use in a reference if needed.
+func quoteSheetName(s string) string {
+	for _, c := range s {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '.') {
+			return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+		}
+	}
+	return s
+}
+
+type cellKey struct{ row, col uint32 }
+
+// auditArea is a rectangular area of a sheet referenced by a formula.
+type auditArea struct {
+	sheet          string
+	ref            string
+	name           string
+	r1, r2, c1, c2 uint32
+	singleCell     bool
+}
+
+func (a auditArea) contains(sheet string, k cellKey) bool {
+	return strings.EqualFold(a.sheet, sheet) && k.row >= a.r1 && k.row <= a.r2 && k.col >= a.c1 && k.col <= a.c2
+}
+
+// auditCell is a cell with a formula.
+type auditCell struct {
+	sheet string
+	ref   string
+	key   cellKey
+	cell  Cell
+	expr  formula.Expression
+	areas []auditArea
+}
+
+type auditSheet struct {
+	name     string
+	cells    map[cellKey]Cell
+	formulas []*auditCell
+}
+
+// auditIndex holds the formula cells of a workbook and the areas their
+// formulas refer to.
+type auditIndex struct {
+	wb     *Workbook
+	sheets map[string]*auditSheet
+	order  []*auditSheet
+}
+
+func newAuditIndex(wb *Workbook) *auditIndex {
+	idx := &auditIndex{wb: wb, sheets: map[string]*auditSheet{}}
+	for _, s := range wb.AllSheets() {
+		s := s
+		as := &auditSheet{name: s.Name(), cells: map[cellKey]Cell{}}
+		idx.sheets[strings.ToLower(as.name)] = as
+		idx.order = append(idx.order, as)
+		if s._bbbe.SheetData == nil {
+			continue
+		}
+		for _, r := range s._bbbe.SheetData.Row {
+			for _, c := range r.C {
+				if c.RAttr == nil {
+					continue
+				}
+				cr, err := reference.ParseCellReference(*c.RAttr)
+				if err != nil {
+					continue
+				}
+				k := cellKey{cr.RowIdx, cr.ColumnIdx}
+				cell := Cell{wb, &s, r, c}
+				as.cells[k] = cell
+				if f := cell.GetFormula(); f != "" {
+					if expr := formula.ParseString(f); expr != nil {
+						as.formulas = append(as.formulas, &auditCell{sheet: as.name, ref: *c.RAttr, key: k, cell: cell, expr: expr})
+					}
+				}
+			}
+		}
+	}
+	for _, as := range idx.order {
+		for _, fc := range as.formulas {
+			fc.areas = idx.resolve(formula.References(nil, nil, fc.expr), as.name, "", map[string]bool{})
+		}
+	}
+	return idx
+}
+
+// resolve converts formula references into areas, following defined names.
+func (idx *auditIndex) resolve(refs []formula.Reference, sheet, name string, seen map[string]bool) []auditArea {
+	ret := []auditArea{}
+	for _, r := range refs {
+		if r.Type == formula.ReferenceTypeNamedRange {
+			dn, ok := idx.definedName(r.Value, sheet)
+			if !ok || seen[strings.ToLower(r.Value)] {
+				continue
+			}
+			seen[strings.ToLower(r.Value)] = true
+			expr := formula.ParseString(dn.Content())
+			if expr == nil {
+				continue
+			}
+			via := name
+			if via == "" {
+				via = dn.Name()
+			}
+			ret = append(ret, idx.resolve(formula.References(nil, nil, expr), sheet, via, seen)...)
+			continue
+		}
+		if a, ok := parseAuditArea(r, sheet); ok {
+			a.name = name
+			if as, ok := idx.sheets[strings.ToLower(a.sheet)]; ok {
+				a.sheet = as.name
+			}
+			ret = append(ret, a)
+		}
+	}
+	return ret
+}
+
+// definedName returns a defined name, preferring one local to the sheet.
+func (idx *auditIndex) definedName(name, sheet string) (DefinedName, bool) {
+	var global DefinedName
+	found := false
+	for _, dn := range idx.wb.DefinedNames() {
+		if !strings.EqualFold(dn.Name(), name) {
+			continue
+		}
+		if id := dn._agac.LocalSheetIdAttr; id != nil {
+			if int(*id) < len(idx.order) && strings.EqualFold(idx.order[*id].name, sheet) {
+				return dn, true
+			}
+			continue
+		}
+		global, found = dn, true
+	}
+	return global, found
+}
+
+// parseAuditArea converts a formula reference to the area it covers.
+func parseAuditArea(r formula.Reference, sheet string) (auditArea, bool) {
+	v := strings.ReplaceAll(r.Value, "$", "")
+	if i := strings.LastIndex(v, "!"); i >= 0 {
+		sheet = v[:i]
+		if len(sheet) > 1 && sheet[0] == '\'' && sheet[len(sheet)-1] == '\'' {
+			sheet = strings.ReplaceAll(sheet[1:len(sheet)-1], "''", "'")
+		}
+		v = v[i+1:]
+	}
+	a := auditArea{sheet: sheet, ref: v}
+	switch r.Type {
+	case formula.ReferenceTypeCell:
+		cr, err := reference.ParseCellReference(v)
+		if err != nil {
+			return a, false
+		}
+		a.r1, a.r2, a.c1, a.c2 = cr.RowIdx, cr.RowIdx, cr.ColumnIdx, cr.ColumnIdx
+		a.singleCell = true
+	case formula.ReferenceTypeRange:
+		from, to, err := reference.ParseRangeReference(v)
+		if err != nil {
+			return a, false
+		}
+		a.r1, a.r2, a.c1, a.c2 = from.RowIdx, to.RowIdx, from.ColumnIdx, to.ColumnIdx
+	case formula.ReferenceTypeVerticalRange:
+		parts := strings.Split(v, ":")
+		if len(parts) != 2 {
+			return a, false
+		}
+		a.c1, a.c2 = reference.ColumnToIndex(parts[0]), reference.ColumnToIndex(parts[1])
+		a.r1, a.r2 = 1, 1048576
+	case formula.ReferenceTypeHorizontalRange:
+		var r1, r2 uint32
+		if _, err := fmt.Sscanf(v, "%d:%d", &r1, &r2); err != nil {
+			return a, false
+		}
+		a.r1, a.r2 = r1, r2
+		a.c1, a.c2 = 0, 16383
+	default:
+		return a, false
+	}
+	if a.r1 > a.r2 {
+		a.r1, a.r2 = a.r2, a.r1
+	}
+	if a.c1 > a.c2 {
+		a.c1, a.c2 = a.c2, a.c1
+	}
+	return a, true
+}
+
+// formulasIn returns the formula cells within an area.
+func (idx *auditIndex) formulasIn(a auditArea) []*auditCell {
+	as, ok := idx.sheets[strings.ToLower(a.sheet)]
+	if !ok {
+		return nil
+	}
+	ret := []*auditCell{}
+	for _, fc := range as.formulas {
+		if a.contains(as.name, fc.key) {
+			ret = append(ret, fc)
+		}
+	}
+	return ret
+}
+
+func (idx *auditIndex) formulaCell(sheet, cellRef string) *auditCell {
+	as, ok := idx.sheets[strings.ToLower(sheet)]
+	if !ok {
+		return nil
+	}
+	cr, err := reference.ParseCellReference(strings.ReplaceAll(cellRef, "$", ""))
+	if err != nil {
+		return nil
+	}
+	k := cellKey{cr.RowIdx, cr.ColumnIdx}
+	for _, fc := range as.formulas {
+		if fc.key == k {
+			return fc
+		}
+	}
+	return nil
+}
+
+// Precedents returns the cells and ranges the formula of a cell refers to,
+// including those reached through defined names and on other sheets. The
+// precedents of formulas within the precedents are traced up to depth levels,
+// a depth of zero or less traces all levels.
+func (s *Sheet) Precedents(cellRef string, depth int) []AuditReference {
+	idx := newAuditIndex(s._fgeg)
+	start := idx.formulaCell(s.Name(), cellRef)
+	if start == nil {
+		return nil
+	}
+	ret := []AuditReference{}
+	seenRefs := map[string]bool{}
+	seenCells := map[*auditCell]bool{start: true}
+	level := []*auditCell{start}
+	for d := 1; len(level) > 0 && (depth <= 0 || d <= depth); d++ {
+		next := []*auditCell{}
+		for _, fc := range level {
+			for _, a := range fc.areas {
+				ar := AuditReference{Sheet: a.sheet, Reference: a.ref, Name: a.name, Depth: d}
+				if k := strings.ToLower(ar.String()); !seenRefs[k] {
+					seenRefs[k] = true
+					ret = append(ret, ar)
+				}
+				for _, p := range idx.formulasIn(a) {
+					if !seenCells[p] {
+						seenCells[p] = true
+						next = append(next, p)
+					}
+				}
+			}
+		}
+		level = next
+	}
+	return ret
+}
+
+// Dependents returns the cells with formulas that refer to a cell, directly or
+// through defined names and from other sheets. Dependents of the dependents
+// are traced up to depth levels, a depth of zero or less traces all levels.
+func (s *Sheet) Dependents(cellRef string, depth int) []AuditReference {
+	cr, err := reference.ParseCellReference(strings.ReplaceAll(cellRef, "$", ""))
+	if err != nil {
+		return nil
+	}
+	idx := newAuditIndex(s._fgeg)
+	type target struct {
+		sheet string
+		key   cellKey
+	}
+	ret := []AuditReference{}
+	seen := map[*auditCell]bool{}
+	level := []target{{s.Name(), cellKey{cr.RowIdx, cr.ColumnIdx}}}
+	for d := 1; len(level) > 0 && (depth <= 0 || d <= depth); d++ {
+		next := []target{}
+		for _, as := range idx.order {
+			for _, fc := range as.formulas {
+				if seen[fc] {
+					continue
+				}
+				for _, a := range fc.areas {
+					hit := false
+					for _, t := range level {
+						if a.contains(t.sheet, t.key) {
+							hit = true
+							break
+						}
+					}
+					if hit {
+						seen[fc] = true
+						ret = append(ret, AuditReference{Sheet: fc.sheet, Reference: fc.ref, Name: a.name, Depth: d})
+						next = append(next, target{fc.sheet, fc.key})
+						break
+					}
+				}
+			}
+		}
+		level = next
+	}
+	return ret
+}
+
+// FormulaCell is a cell with a formula found by a formula inventory.
+type FormulaCell struct {
+	Sheet   string
+	Cell    string
+	Formula string
+}
+
+// FormulaGroup is a set of cells with the same formula in R1C1 form, i.e.
+// formulas that only differ by their position.
+type FormulaGroup struct {
+	R1C1  string
+	Cells []FormulaCell
+}
+
+// InconsistentFormula is a formula that differs from the formulas on both
+// sides of it in its row or column while those agree with each other.
+type InconsistentFormula struct {
+	FormulaCell
+	// Expected is the R1C1 form of the neighbouring formulas.
+	Expected string
+	// InRow is true if the neighbours are in the same row, otherwise they're
+	// in the same column.
+	InRow bool
+}
+
+// HardCodedConstant is a formula containing numeric constants.
+type HardCodedConstant struct {
+	FormulaCell
+	Constants []string
+}
+
+// EmptyReference is a formula referring to an empty cell.
+type EmptyReference struct {
+	FormulaCell
+	Reference AuditReference
+}
+
+// FormulaInventory summarizes the formulas of a workbook for review.
+type FormulaInventory struct {
+	// Groups are the distinct formulas in R1C1 form in order of appearance.
+	Groups          []FormulaGroup
+	Inconsistent    []InconsistentFormula
+	HardCoded       []HardCodedConstant
+	EmptyReferences []EmptyReference
+}
+
+// FormulaInventory groups the formulas of the workbook by their R1C1 form and
+// flags formulas that are inconsistent with their neighbours, contain numeric
+// constants other than 0 and 1, or refer to empty cells.
+func (wb *Workbook) FormulaInventory() FormulaInventory {
+	idx := newAuditIndex(wb)
+	inv := FormulaInventory{}
+	groups := map[string]int{}
+	for _, as := range idx.order {
+		r1c1 := map[cellKey]string{}
+		for _, fc := range as.formulas {
+			r1c1[fc.key] = fc.expr.StringR1C1(fc.ref)
+		}
+		for _, fc := range as.formulas {
+			cell := FormulaCell{Sheet: as.name, Cell: fc.ref, Formula: fc.cell.GetFormula()}
+			f := r1c1[fc.key]
+			if i, ok := groups[f]; ok {
+				inv.Groups[i].Cells = append(inv.Groups[i].Cells, cell)
+			} else {
+				groups[f] = len(inv.Groups)
+				inv.Groups = append(inv.Groups, FormulaGroup{R1C1: f, Cells: []FormulaCell{cell}})
+			}
+
+			k := fc.key
+			if k.col > 0 {
+				left, lok := r1c1[cellKey{k.row, k.col - 1}]
+				right, rok := r1c1[cellKey{k.row, k.col + 1}]
+				if lok && rok && left == right && left != f {
+					inv.Inconsistent = append(inv.Inconsistent, InconsistentFormula{cell, left, true})
+				}
+			}
+			if k.row > 1 {
+				above, aok := r1c1[cellKey{k.row - 1, k.col}]
+				below, bok := r1c1[cellKey{k.row + 1, k.col}]
+				if aok && bok && above == below && above != f {
+					inv.Inconsistent = append(inv.Inconsistent, InconsistentFormula{cell, above, false})
+				}
+			}
+
+			constants := []string{}
+			formula.Walk(fc.expr, func(e formula.Expression) bool {
+				if n, ok := e.(formula.Number); ok {
+					if v := n.String(); v != "0" && v != "1" {
+						constants = append(constants, v)
+					}
+				}
+				return true
+			})
+			if len(constants) > 0 {
+				inv.HardCoded = append(inv.HardCoded, HardCodedConstant{cell, constants})
+			}
+
+			for _, a := range fc.areas {
+				if !a.singleCell {
+					continue
+				}
+				ts, ok := idx.sheets[strings.ToLower(a.sheet)]
+				if !ok {
+					continue
+				}
+				if c, ok := ts.cells[cellKey{a.r1, a.c1}]; !ok || c.IsEmpty() {
+					inv.EmptyReferences = append(inv.EmptyReferences, EmptyReference{cell, AuditReference{Sheet: ts.name, Reference: a.ref, Name: a.name, Depth: 1}})
+				}
+			}
+		}
+	}
+	return inv
+}
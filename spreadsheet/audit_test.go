@@ -0,0 +1,105 @@
+package spreadsheet
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yaklabco/unioffice/v2/schema/soo/sml"
+)
+
+func auditRefs(refs []AuditReference) []string {
+	ret := []string{}
+	for _, r := range refs {
+		s := r.String()
+		if r.Name != "" {
+			s += " via " + r.Name
+		}
+		ret = append(ret, s)
+	}
+	return ret
+}
+
+func TestPrecedentsDependents(t *testing.T) {
+	wb := New()
+	inputs := wb.AddSheet()
+	inputs.SetName("Inputs")
+	inputs.Cell("A1").SetNumber(0.2)
+	wb.AddDefinedName("Rate", "Inputs!$A$1")
+	calc := wb.AddSheet()
+	calc.SetName("Calc")
+	calc.Cell("A1").SetNumber(100)
+	calc.Cell("B1").SetFormulaRaw("A1*Rate")
+	calc.Cell("C1").SetFormulaRaw("B1+SUM(A1:A3)")
+	calc.Cell("D1").SetFormulaRaw("C1*2")
+
+	got := auditRefs(calc.Precedents("D1", 1))
+	if exp := []string{"Calc!C1"}; !reflect.DeepEqual(got, exp) {
+		t.Errorf("expected %v, got %v", exp, got)
+	}
+	got = auditRefs(calc.Precedents("D1", 0))
+	if exp := []string{"Calc!C1", "Calc!B1", "Calc!A1:A3", "Calc!A1", "Inputs!A1 via Rate"}; !reflect.DeepEqual(got, exp) {
+		t.Errorf("expected %v, got %v", exp, got)
+	}
+
+	deps := inputs.Dependents("A1", 0)
+	got = auditRefs(deps)
+	if exp := []string{"Calc!B1 via Rate", "Calc!C1", "Calc!D1"}; !reflect.DeepEqual(got, exp) {
+		t.Errorf("expected %v, got %v", exp, got)
+	}
+	if deps[2].Depth != 3 {
+		t.Errorf("expected depth 3, got %d", deps[2].Depth)
+	}
+}
+
+func TestPrecedentsHiddenSheet(t *testing.T) {
+	wb := New()
+	inputs := wb.AddSheet()
+	inputs.SetName("Inputs")
+	inputs.Cell("A1").SetNumber(0.2)
+	inputs.Cell("A2").SetNumber(0.3)
+	calc := wb.AddSheet()
+	calc.SetName("Calc")
+	calc.Cell("B1").SetFormulaRaw("100*Rate")
+	wb.AddDefinedName("Rate", "Inputs!$A$1")
+	// the local name of the second sheet takes precedence
+	wb.AddDefinedName("Rate", "Inputs!$A$2").SetLocalSheetID(1)
+	if err := inputs.SetState(sml.ST_SheetStateHidden); err != nil {
+		t.Fatalf("error hiding sheet: %s", err)
+	}
+
+	got := auditRefs(calc.Precedents("B1", 0))
+	if exp := []string{"Inputs!A2 via Rate"}; !reflect.DeepEqual(got, exp) {
+		t.Errorf("expected %v, got %v", exp, got)
+	}
+	got = auditRefs(wb.AllSheets()[0].Dependents("A2", 0))
+	if exp := []string{"Calc!B1 via Rate"}; !reflect.DeepEqual(got, exp) {
+		t.Errorf("expected %v, got %v", exp, got)
+	}
+}
+
+func TestFormulaInventory(t *testing.T) {
+	wb := New()
+	sheet := wb.AddSheet()
+	for _, r := range []string{"1", "2", "3", "4"} {
+		sheet.Cell("A" + r).SetNumber(1)
+	}
+	sheet.Cell("B1").SetFormulaRaw("A1*2")
+	sheet.Cell("B2").SetFormulaRaw("A2*2")
+	sheet.Cell("B3").SetFormulaRaw("A3+1")
+	sheet.Cell("B4").SetFormulaRaw("A4*2")
+	sheet.Cell("C1").SetFormulaRaw("Z9")
+
+	inv := wb.FormulaInventory()
+	if len(inv.Groups) != 3 || inv.Groups[0].R1C1 != "RC[-1]*2" || len(inv.Groups[0].Cells) != 3 {
+		t.Errorf("unexpected groups %+v", inv.Groups)
+	}
+	if len(inv.Inconsistent) != 1 || inv.Inconsistent[0].Cell != "B3" || inv.Inconsistent[0].Expected != "RC[-1]*2" || inv.Inconsistent[0].InRow {
+		t.Errorf("unexpected inconsistent formulas %+v", inv.Inconsistent)
+	}
+	if len(inv.HardCoded) != 3 || inv.HardCoded[0].Constants[0] != "2" {
+		t.Errorf("unexpected hard coded constants %+v", inv.HardCoded)
+	}
+	if len(inv.EmptyReferences) != 1 || inv.EmptyReferences[0].Reference.Reference != "Z9" {
+		t.Errorf("unexpected empty references %+v", inv.EmptyReferences)
+	}
+}
@@ -0,0 +1,811 @@
+package spreadsheet
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	unioffice "github.com/yaklabco/unioffice/v2"
+	"github.com/yaklabco/unioffice/v2/internal/wildcard"
+	"github.com/yaklabco/unioffice/v2/schema/soo/sml"
+	"github.com/yaklabco/unioffice/v2/spreadsheet/reference"
+)
+
+// FilterType is the kind of criteria applied to an autofilter column.
+type FilterType byte
+
+// FilterType constants.
+const (
+	FilterTypeNone FilterType = iota
+	FilterTypeValues
+	FilterTypeCustom
+	FilterTypeTop10
+	FilterTypeDynamic
+	FilterTypeColor
+	FilterTypeIcon
+)
+
+// AutoFilter is the autofilter range of a sheet along with its column
+// criteria.
+type AutoFilter struct {
+	w *Workbook
+	x *sml.CT_AutoFilter
+}
+
+// CustomFilter is a single comparison used by a custom autofilter. Values may
+// contain the wildcards '*' and '?' when compared for (in)equality.
+type CustomFilter struct {
+	Operator sml.ST_FilterOperator
+	Value    string
+}
+
+// FilterColumn is the filter criteria for a single column of an autofilter.
+type FilterColumn struct {
+	w *Workbook
+	x *sml.CT_FilterColumn
+}
+
+// AutoFilter returns the autofilter of the sheet. If the sheet has no
+// autofilter, the returned value has a nil X(); use SetAutoFilter to create
+// one.
+func (s *Sheet) AutoFilter() AutoFilter {
+	return AutoFilter{s._fgeg, s._bbbe.AutoFilter}
+}
+
+// X returns the inner wrapped XML type.
+func (a AutoFilter) X() *sml.CT_AutoFilter { return a.x }
+
+// Reference returns the range the autofilter applies to, including the header
+// row.
+func (a AutoFilter) Reference() string {
+	if a.x == nil || a.x.RefAttr == nil {
+		return ""
+	}
+	return *a.x.RefAttr
+}
+
+// Columns returns the columns of the autofilter that have criteria or button
+// settings defined.
+func (a AutoFilter) Columns() []FilterColumn {
+	if a.x == nil {
+		return nil
+	}
+	ret := []FilterColumn{}
+	for _, fc := range a.x.FilterColumn {
+		if fc == nil {
+			continue
+		}
+		ret = append(ret, FilterColumn{a.w, fc})
+	}
+	return ret
+}
+
+// Column returns the filter column with the given zero-based offset from the
+// first column of the autofilter range, creating it if necessary. If the
+// sheet has no autofilter, the returned value has a nil X().
+func (a AutoFilter) Column(colID uint32) FilterColumn {
+	if a.x == nil {
+		return FilterColumn{a.w, nil}
+	}
+	for _, fc := range a.x.FilterColumn {
+		if fc != nil && fc.ColIdAttr == colID {
+			return FilterColumn{a.w, fc}
+		}
+	}
+	fc := sml.NewCT_FilterColumn()
+	fc.ColIdAttr = colID
+	a.x.FilterColumn = append(a.x.FilterColumn, fc)
+	sort.SliceStable(a.x.FilterColumn, func(i, j int) bool {
+		fi, fj := a.x.FilterColumn[i], a.x.FilterColumn[j]
+		return fi != nil && (fj == nil || fi.ColIdAttr < fj.ColIdAttr)
+	})
+	return FilterColumn{a.w, fc}
+}
+
+// RemoveColumn removes any criteria defined for the given column.
+func (a AutoFilter) RemoveColumn(colID uint32) {
+	if a.x == nil {
+		return
+	}
+	for i, fc := range a.x.FilterColumn {
+		if fc != nil && fc.ColIdAttr == colID {
+			copy(a.x.FilterColumn[i:], a.x.FilterColumn[i+1:])
+			a.x.FilterColumn = a.x.FilterColumn[:len(a.x.FilterColumn)-1]
+			return
+		}
+	}
+}
+
+// X returns the inner wrapped XML type.
+func (f FilterColumn) X() *sml.CT_FilterColumn { return f.x }
+
+// ColumnID returns the zero-based offset of the column from the first column
+// of the autofilter range.
+func (f FilterColumn) ColumnID() uint32 { return f.x.ColIdAttr }
+
+// SetHiddenButton controls whether the filter drop down button is hidden.
+func (f FilterColumn) SetHiddenButton(b bool) {
+	if !b {
+		f.x.HiddenButtonAttr = nil
+	} else {
+		f.x.HiddenButtonAttr = unioffice.Bool(true)
+	}
+}
+
+// Type returns the kind of criteria defined for the column.
+func (f FilterColumn) Type() FilterType {
+	if f.x == nil {
+		return FilterTypeNone
+	}
+	ch := f.x.FilterColumnChoice
+	switch {
+	case ch == nil:
+		return FilterTypeNone
+	case ch.Filters != nil:
+		return FilterTypeValues
+	case ch.CustomFilters != nil:
+		return FilterTypeCustom
+	case ch.Top10 != nil:
+		return FilterTypeTop10
+	case ch.DynamicFilter != nil:
+		return FilterTypeDynamic
+	case ch.ColorFilter != nil:
+		return FilterTypeColor
+	case ch.IconFilter != nil:
+		return FilterTypeIcon
+	}
+	return FilterTypeNone
+}
+
+// Clear removes the criteria from the column.
+func (f FilterColumn) Clear() { f.x.FilterColumnChoice = nil }
+
+func (f FilterColumn) resetChoice() *sml.CT_FilterColumnChoice {
+	f.x.FilterColumnChoice = sml.NewCT_FilterColumnChoice()
+	return f.x.FilterColumnChoice
+}
+
+func (f FilterColumn) filters() *sml.CT_Filters {
+	if f.Type() != FilterTypeValues {
+		f.resetChoice().Filters = sml.NewCT_Filters()
+	}
+	return f.x.FilterColumnChoice.Filters
+}
+
+// SetValues restricts the column to rows whose displayed value matches one of
+// values. Comparison is case insensitive, as in Excel.
+func (f FilterColumn) SetValues(values []string) {
+	fl := f.filters()
+	fl.Filter = nil
+	for _, v := range values {
+		flt := sml.NewCT_Filter()
+		flt.ValAttr = unioffice.String(v)
+		fl.Filter = append(fl.Filter, flt)
+	}
+}
+
+// Values returns the values of a value list filter.
+func (f FilterColumn) Values() []string {
+	if f.Type() != FilterTypeValues {
+		return nil
+	}
+	ret := []string{}
+	for _, flt := range f.x.FilterColumnChoice.Filters.Filter {
+		if flt.ValAttr != nil {
+			ret = append(ret, *flt.ValAttr)
+		}
+	}
+	return ret
+}
+
+// SetBlank controls whether blank cells pass a value list filter.
+func (f FilterColumn) SetBlank(b bool) {
+	fl := f.filters()
+	if b {
+		fl.BlankAttr = unioffice.Bool(true)
+	} else {
+		fl.BlankAttr = nil
+	}
+}
+
+// IncludesBlank returns true if blank cells pass a value list filter.
+func (f FilterColumn) IncludesBlank() bool {
+	return f.Type() == FilterTypeValues && f.x.FilterColumnChoice.Filters.BlankAttr != nil &&
+		*f.x.FilterColumnChoice.Filters.BlankAttr
+}
+
+// AddDateGroupItem adds a date to a value list filter. All dates that match t
+// down to the given grouping (e.g. the same month for
+// ST_DateTimeGroupingMonth) pass the filter.
+func (f FilterColumn) AddDateGroupItem(grouping sml.ST_DateTimeGrouping, t time.Time) {
+	fl := f.filters()
+	dg := sml.NewCT_DateGroupItem()
+	dg.DateTimeGroupingAttr = grouping
+	dg.YearAttr = uint16(t.Year())
+	fields := []**uint16{&dg.MonthAttr, &dg.DayAttr, &dg.HourAttr, &dg.MinuteAttr, &dg.SecondAttr}
+	values := []int{int(t.Month()), t.Day(), t.Hour(), t.Minute(), t.Second()}
+	for i := 0; i < int(grouping)-1 && i < len(fields); i++ {
+		v := uint16(values[i])
+		*fields[i] = &v
+	}
+	fl.DateGroupItem = append(fl.DateGroupItem, dg)
+}
+
+// SetCustomFilters replaces the column criteria with up to two custom
+// comparisons. If and is true, both comparisons must be satisfied, otherwise
+// either one is sufficient.
+func (f FilterColumn) SetCustomFilters(and bool, filters ...CustomFilter) error {
+	if len(filters) == 0 || len(filters) > 2 {
+		return errors.New("custom filters require one or two comparisons")
+	}
+	cf := sml.NewCT_CustomFilters()
+	if and {
+		cf.AndAttr = unioffice.Bool(true)
+	}
+	for _, flt := range filters {
+		c := sml.NewCT_CustomFilter()
+		c.OperatorAttr = flt.Operator
+		c.ValAttr = unioffice.String(flt.Value)
+		cf.CustomFilter = append(cf.CustomFilter, c)
+	}
+	f.resetChoice().CustomFilters = cf
+	return nil
+}
+
+// CustomFilters returns the comparisons of a custom filter and whether they
+// are combined with AND.
+func (f FilterColumn) CustomFilters() (bool, []CustomFilter) {
+	if f.Type() != FilterTypeCustom {
+		return false, nil
+	}
+	cf := f.x.FilterColumnChoice.CustomFilters
+	ret := []CustomFilter{}
+	for _, c := range cf.CustomFilter {
+		flt := CustomFilter{Operator: c.OperatorAttr}
+		if flt.Operator == sml.ST_FilterOperatorUnset {
+			flt.Operator = sml.ST_FilterOperatorEqual
+		}
+		if c.ValAttr != nil {
+			flt.Value = *c.ValAttr
+		}
+		ret = append(ret, flt)
+	}
+	return cf.AndAttr != nil && *cf.AndAttr, ret
+}
+
+// SetTop10 filters the column to the top (or bottom) val items, or val
+// percent of items if percent is true.
+func (f FilterColumn) SetTop10(top, percent bool, val float64) {
+	t := sml.NewCT_Top10()
+	t.ValAttr = val
+	if !top {
+		t.TopAttr = unioffice.Bool(false)
+	}
+	if percent {
+		t.PercentAttr = unioffice.Bool(true)
+	}
+	f.resetChoice().Top10 = t
+}
+
+// Top10 returns the settings of a top 10 filter.
+func (f FilterColumn) Top10() (top, percent bool, val float64) {
+	if f.Type() != FilterTypeTop10 {
+		return false, false, 0
+	}
+	t := f.x.FilterColumnChoice.Top10
+	return t.TopAttr == nil || *t.TopAttr, t.PercentAttr != nil && *t.PercentAttr, t.ValAttr
+}
+
+// SetDynamicFilter filters the column using a dynamic filter such as above
+// average or a relative date period. The concrete boundaries are computed
+// when the filter is applied.
+func (f FilterColumn) SetDynamicFilter(t sml.ST_DynamicFilterType) {
+	d := sml.NewCT_DynamicFilter()
+	d.TypeAttr = t
+	f.resetChoice().DynamicFilter = d
+}
+
+// DynamicFilter returns the type of a dynamic filter.
+func (f FilterColumn) DynamicFilter() sml.ST_DynamicFilterType {
+	if f.Type() != FilterTypeDynamic {
+		return sml.ST_DynamicFilterTypeUnset
+	}
+	return f.x.FilterColumnChoice.DynamicFilter.TypeAttr
+}
+
+// SetColorFilter filters the column by the fill color (cellColor true) or
+// the font color (cellColor false) described by the differential style.
+func (f FilterColumn) SetColorFilter(d DifferentialStyle, cellColor bool) {
+	c := sml.NewCT_ColorFilter()
+	c.DxfIdAttr = unioffice.Uint32(d.Index())
+	if !cellColor {
+		c.CellColorAttr = unioffice.Bool(false)
+	}
+	f.resetChoice().ColorFilter = c
+}
+
+// ColorFilter returns the differential style and target of a color filter.
+// The boolean is false if the column does not have a color filter.
+func (f FilterColumn) ColorFilter() (d DifferentialStyle, cellColor bool, ok bool) {
+	if f.Type() != FilterTypeColor {
+		return DifferentialStyle{}, false, false
+	}
+	c := f.x.FilterColumnChoice.ColorFilter
+	cellColor = c.CellColorAttr == nil || *c.CellColorAttr
+	ss := f.w.StyleSheet._gccd
+	if c.DxfIdAttr != nil && ss.Dxfs != nil && int(*c.DxfIdAttr) < len(ss.Dxfs.Dxf) {
+		d = DifferentialStyle{ss.Dxfs.Dxf[*c.DxfIdAttr], f.w, ss.Dxfs}
+	}
+	return d, cellColor, true
+}
+
+// SetIconFilter filters the column by a conditional formatting icon.
+func (f FilterColumn) SetIconFilter(set sml.ST_IconSetType, iconID uint32) {
+	i := sml.NewCT_IconFilter()
+	i.IconSetAttr = set
+	i.IconIdAttr = unioffice.Uint32(iconID)
+	f.resetChoice().IconFilter = i
+}
+
+// IconFilter returns the icon set and the icon of an icon filter, the icon
+// being nil if the filter matches the cells without an icon. The boolean is
+// false if the column does not have an icon filter.
+func (f FilterColumn) IconFilter() (set sml.ST_IconSetType, iconID *uint32, ok bool) {
+	if f.Type() != FilterTypeIcon {
+		return sml.ST_IconSetTypeUnset, nil, false
+	}
+	i := f.x.FilterColumnChoice.IconFilter
+	return i.IconSetAttr, i.IconIdAttr, true
+}
+
+// filterCell is the value of a cell as seen by the autofilter.
+type filterCell struct {
+	cell  Cell
+	text  string
+	num   float64
+	isNum bool
+	blank bool
+}
+
+func (s *Sheet) filterCellAt(row *sml.CT_Row, ref string) filterCell {
+	if row == nil {
+		return filterCell{blank: true}
+	}
+	for _, c := range row.C {
+		if c.RAttr != nil && *c.RAttr == ref {
+			cell := Cell{s._fgeg, s, row, c}
+			fc := filterCell{cell: cell, text: cell.GetFormattedValue()}
+			raw, isNum := cell.getRawSortValue()
+			fc.blank = raw == "" && fc.text == ""
+			if isNum && c.TAttr != sml.ST_CellTypeS && c.TAttr != sml.ST_CellTypeInlineStr &&
+				c.TAttr != sml.ST_CellTypeE && c.TAttr != sml.ST_CellTypeB {
+				fc.num, _ = strconv.ParseFloat(raw, 64)
+				fc.isNum = true
+			}
+			return fc
+		}
+	}
+	return filterCell{blank: true}
+}
+
+// columnFilter evaluates the criteria of a single column.
+type columnFilter struct {
+	fc       FilterColumn
+	min, max float64
+	epoch    time.Time
+	match    func(filterCell) bool
+}
+
+// ApplyAutoFilter evaluates the autofilter criteria and hides the rows that
+// don't satisfy them, as Excel does when the filter is reapplied. Rows that
+// satisfy all criteria are made visible. Icon filters depend on conditional
+// formatting evaluation and are treated as always satisfied.
+func (s *Sheet) ApplyAutoFilter() error {
+	af := s.AutoFilter()
+	if af.X() == nil || af.Reference() == "" {
+		return errors.New("sheet has no autofilter")
+	}
+	return s.applyAutoFilter(af)
+}
+
+// applyAutoFilter hides the rows of the sheet that don't satisfy the criteria
+// of af, which is either the autofilter of the sheet or of one of its tables.
+func (s *Sheet) applyAutoFilter(af AutoFilter) error {
+	from, to, err := reference.ParseRangeReference(af.Reference())
+	if err != nil {
+		return fmt.Errorf("invalid autofilter reference: %w", err)
+	}
+	rows := map[uint32]*sml.CT_Row{}
+	for _, r := range s._bbbe.SheetData.Row {
+		if r.RAttr != nil {
+			rows[*r.RAttr] = r
+		}
+	}
+	cellAt := func(colID, r uint32) filterCell {
+		col := reference.IndexToColumn(from.ColumnIdx + colID)
+		return s.filterCellAt(rows[r], fmt.Sprintf("%s%d", col, r))
+	}
+	filters := []*columnFilter{}
+	for _, fc := range af.Columns() {
+		if fc.Type() == FilterTypeNone || fc.Type() == FilterTypeIcon {
+			continue
+		}
+		values := []filterCell{}
+		for r := from.RowIdx + 1; r <= to.RowIdx; r++ {
+			values = append(values, cellAt(fc.ColumnID(), r))
+		}
+		cf := &columnFilter{fc: fc, epoch: s._fgeg.Epoch()}
+		if err := cf.prepare(values); err != nil {
+			return err
+		}
+		filters = append(filters, cf)
+	}
+	for r := from.RowIdx + 1; r <= to.RowIdx; r++ {
+		visible := true
+		for _, cf := range filters {
+			if !cf.match(cellAt(cf.fc.ColumnID(), r)) {
+				visible = false
+				break
+			}
+		}
+		row := rows[r]
+		switch {
+		case visible && row != nil:
+			row.HiddenAttr = nil
+		case !visible:
+			if row == nil {
+				row = s.Row(r).X()
+				rows[r] = row
+			}
+			row.HiddenAttr = unioffice.Bool(true)
+		}
+	}
+	return nil
+}
+
+func (cf *columnFilter) prepare(values []filterCell) error {
+	ch := cf.fc.X().FilterColumnChoice
+	switch cf.fc.Type() {
+	case FilterTypeValues:
+		cf.match = cf.valuesMatcher(ch.Filters)
+	case FilterTypeCustom:
+		and, flts := cf.fc.CustomFilters()
+		cf.match = func(v filterCell) bool {
+			for _, flt := range flts {
+				ok := customFilterMatch(flt, v)
+				if and && !ok {
+					return false
+				}
+				if !and && ok {
+					return true
+				}
+			}
+			return and
+		}
+	case FilterTypeTop10:
+		cf.prepareTop10(ch.Top10, values)
+	case FilterTypeDynamic:
+		return cf.prepareDynamic(ch.DynamicFilter, values)
+	case FilterTypeColor:
+		d, cellColor, _ := cf.fc.ColorFilter()
+		cf.match = func(v filterCell) bool { return colorFilterMatch(d, cellColor, v) }
+	default:
+		cf.match = func(filterCell) bool { return true }
+	}
+	return nil
+}
+
+func (cf *columnFilter) valuesMatcher(fl *sml.CT_Filters) func(filterCell) bool {
+	allowed := map[string]struct{}{}
+	for _, flt := range fl.Filter {
+		if flt.ValAttr != nil {
+			allowed[strings.ToLower(*flt.ValAttr)] = struct{}{}
+		}
+	}
+	blank := fl.BlankAttr != nil && *fl.BlankAttr
+	return func(v filterCell) bool {
+		if v.blank {
+			return blank
+		}
+		if _, ok := allowed[strings.ToLower(v.text)]; ok {
+			return true
+		}
+		if !v.isNum || len(fl.DateGroupItem) == 0 {
+			return false
+		}
+		t := cf.serialToTime(v.num)
+		for _, dg := range fl.DateGroupItem {
+			if dateGroupMatch(dg, t) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func dateGroupMatch(dg *sml.CT_DateGroupItem, t time.Time) bool {
+	if int(dg.YearAttr) != t.Year() {
+		return false
+	}
+	fields := []*uint16{dg.MonthAttr, dg.DayAttr, dg.HourAttr, dg.MinuteAttr, dg.SecondAttr}
+	values := []int{int(t.Month()), t.Day(), t.Hour(), t.Minute(), t.Second()}
+	for i := 0; i < int(dg.DateTimeGroupingAttr)-1 && i < len(fields); i++ {
+		if fields[i] != nil && int(*fields[i]) != values[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func customFilterMatch(flt CustomFilter, v filterCell) bool {
+	op := flt.Operator
+	if flt.Value == " " && (op == sml.ST_FilterOperatorNotEqual || op == sml.ST_FilterOperatorEqual) {
+		// Excel encodes "(Non blanks)" as notEqual to a single space.
+		return v.blank == (op == sml.ST_FilterOperatorEqual)
+	}
+	fv, err := strconv.ParseFloat(flt.Value, 64)
+	numeric := err == nil
+	if numeric && v.isNum {
+		switch op {
+		case sml.ST_FilterOperatorLessThan:
+			return v.num < fv
+		case sml.ST_FilterOperatorLessThanOrEqual:
+			return v.num <= fv
+		case sml.ST_FilterOperatorGreaterThan:
+			return v.num > fv
+		case sml.ST_FilterOperatorGreaterThanOrEqual:
+			return v.num >= fv
+		case sml.ST_FilterOperatorNotEqual:
+			return v.num != fv
+		default:
+			return v.num == fv
+		}
+	}
+	text := strings.ToLower(v.text)
+	val := strings.ToLower(flt.Value)
+	switch op {
+	case sml.ST_FilterOperatorNotEqual:
+		return !wildcard.Match(val, text)
+	case sml.ST_FilterOperatorLessThan, sml.ST_FilterOperatorLessThanOrEqual,
+		sml.ST_FilterOperatorGreaterThan, sml.ST_FilterOperatorGreaterThanOrEqual:
+		// a numeric comparison never matches text
+		if numeric || v.isNum || v.blank {
+			return false
+		}
+		switch op {
+		case sml.ST_FilterOperatorLessThan:
+			return text < val
+		case sml.ST_FilterOperatorLessThanOrEqual:
+			return text <= val
+		case sml.ST_FilterOperatorGreaterThan:
+			return text > val
+		}
+		return text >= val
+	}
+	return wildcard.Match(val, text)
+}
+
+func (cf *columnFilter) prepareTop10(t *sml.CT_Top10, values []filterCell) {
+	nums := []float64{}
+	for _, v := range values {
+		if v.isNum {
+			nums = append(nums, v.num)
+		}
+	}
+	top := t.TopAttr == nil || *t.TopAttr
+	if top {
+		sort.Sort(sort.Reverse(sort.Float64Slice(nums)))
+	} else {
+		sort.Float64s(nums)
+	}
+	n := int(t.ValAttr)
+	if t.PercentAttr != nil && *t.PercentAttr {
+		n = int(math.Floor(float64(len(nums)) * t.ValAttr / 100))
+		if n < 1 && len(nums) > 0 {
+			n = 1
+		}
+	}
+	if n > len(nums) {
+		n = len(nums)
+	}
+	if n <= 0 {
+		cf.match = func(filterCell) bool { return false }
+		return
+	}
+	threshold := nums[n-1]
+	t.FilterValAttr = unioffice.Float64(threshold)
+	cf.match = func(v filterCell) bool {
+		if !v.isNum {
+			return false
+		}
+		if top {
+			return v.num >= threshold
+		}
+		return v.num <= threshold
+	}
+}
+
+func (cf *columnFilter) serialToTime(v float64) time.Time {
+	d := time.Duration(math.Round(v * 24 * float64(time.Hour) / float64(time.Second)))
+	return cf.epoch.Add(d * time.Second)
+}
+
+func (cf *columnFilter) timeToSerial(t time.Time) float64 {
+	return float64(t.Sub(cf.epoch)) / float64(24*time.Hour)
+}
+
+// dynamicFilterNow is the clock used for relative date filters.
+var dynamicFilterNow = time.Now
+
+func (cf *columnFilter) prepareDynamic(d *sml.CT_DynamicFilter, values []filterCell) error {
+	switch d.TypeAttr {
+	case sml.ST_DynamicFilterTypeAboveAverage, sml.ST_DynamicFilterTypeBelowAverage:
+		sum, n := 0.0, 0
+		for _, v := range values {
+			if v.isNum {
+				sum += v.num
+				n++
+			}
+		}
+		avg := 0.0
+		if n > 0 {
+			avg = sum / float64(n)
+		}
+		d.ValAttr = unioffice.Float64(avg)
+		above := d.TypeAttr == sml.ST_DynamicFilterTypeAboveAverage
+		cf.match = func(v filterCell) bool {
+			return v.isNum && ((above && v.num > avg) || (!above && v.num < avg))
+		}
+		return nil
+	case sml.ST_DynamicFilterTypeNull, sml.ST_DynamicFilterTypeUnset:
+		cf.match = func(filterCell) bool { return true }
+		return nil
+	}
+	if d.TypeAttr >= sml.ST_DynamicFilterTypeQ1 {
+		// Q1-Q4 and M1-M12 match the period in any year.
+		cf.match = func(v filterCell) bool {
+			if !v.isNum {
+				return false
+			}
+			m := int(cf.serialToTime(v.num).Month())
+			if d.TypeAttr >= sml.ST_DynamicFilterTypeM1 {
+				return m == int(d.TypeAttr-sml.ST_DynamicFilterTypeM1)+1
+			}
+			return (m-1)/3 == int(d.TypeAttr-sml.ST_DynamicFilterTypeQ1)
+		}
+		return nil
+	}
+	start, end, err := dynamicDateRange(d.TypeAttr, dynamicFilterNow())
+	if err != nil {
+		return err
+	}
+	cf.min, cf.max = cf.timeToSerial(start), cf.timeToSerial(end)
+	d.ValAttr = unioffice.Float64(cf.min)
+	d.MaxValAttr = unioffice.Float64(cf.max)
+	cf.match = func(v filterCell) bool { return v.isNum && v.num >= cf.min && v.num < cf.max }
+	return nil
+}
+
+// dynamicDateRange returns the half-open interval [start, end) of a relative
+// date filter.
+func dynamicDateRange(t sml.ST_DynamicFilterType, now time.Time) (time.Time, time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	week := today.AddDate(0, 0, -int(today.Weekday()))
+	month := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+	quarter := time.Date(today.Year(), time.Month((int(today.Month())-1)/3*3+1), 1, 0, 0, 0, 0, time.UTC)
+	year := time.Date(today.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+	switch t {
+	case sml.ST_DynamicFilterTypeYesterday:
+		return today.AddDate(0, 0, -1), today, nil
+	case sml.ST_DynamicFilterTypeToday:
+		return today, today.AddDate(0, 0, 1), nil
+	case sml.ST_DynamicFilterTypeTomorrow:
+		return today.AddDate(0, 0, 1), today.AddDate(0, 0, 2), nil
+	case sml.ST_DynamicFilterTypeLastWeek:
+		return week.AddDate(0, 0, -7), week, nil
+	case sml.ST_DynamicFilterTypeThisWeek:
+		return week, week.AddDate(0, 0, 7), nil
+	case sml.ST_DynamicFilterTypeNextWeek:
+		return week.AddDate(0, 0, 7), week.AddDate(0, 0, 14), nil
+	case sml.ST_DynamicFilterTypeLastMonth:
+		return month.AddDate(0, -1, 0), month, nil
+	case sml.ST_DynamicFilterTypeThisMonth:
+		return month, month.AddDate(0, 1, 0), nil
+	case sml.ST_DynamicFilterTypeNextMonth:
+		return month.AddDate(0, 1, 0), month.AddDate(0, 2, 0), nil
+	case sml.ST_DynamicFilterTypeLastQuarter:
+		return quarter.AddDate(0, -3, 0), quarter, nil
+	case sml.ST_DynamicFilterTypeThisQuarter:
+		return quarter, quarter.AddDate(0, 3, 0), nil
+	case sml.ST_DynamicFilterTypeNextQuarter:
+		return quarter.AddDate(0, 3, 0), quarter.AddDate(0, 6, 0), nil
+	case sml.ST_DynamicFilterTypeLastYear:
+		return year.AddDate(-1, 0, 0), year, nil
+	case sml.ST_DynamicFilterTypeThisYear:
+		return year, year.AddDate(1, 0, 0), nil
+	case sml.ST_DynamicFilterTypeNextYear:
+		return year.AddDate(1, 0, 0), year.AddDate(2, 0, 0), nil
+	case sml.ST_DynamicFilterTypeYearToDate:
+		return year, today.AddDate(0, 0, 1), nil
+	}
+	return time.Time{}, time.Time{}, fmt.Errorf("unsupported dynamic filter type %s", t)
+}
+
+func colorFilterMatch(d DifferentialStyle, cellColor bool, v filterCell) bool {
+	if d.X() == nil {
+		return true
+	}
+	var want, got *sml.CT_Color
+	if cellColor {
+		want = dxfFillColor(d.X().Fill)
+		if v.cell.X() != nil && v.cell.X().SAttr != nil {
+			cs := v.cell._bgg.StyleSheet.GetCellStyle(*v.cell.X().SAttr)
+			if cs._faf != nil {
+				got = fillColor(cs.GetFill())
+			}
+		}
+	} else {
+		want = fontColor(d.X().Font)
+		if v.cell.X() != nil && v.cell.X().SAttr != nil {
+			cs := v.cell._bgg.StyleSheet.GetCellStyle(*v.cell.X().SAttr)
+			if cs._faf != nil {
+				got = fontColor(cs.GetFont())
+			}
+		}
+	}
+	if want == nil {
+		return got == nil
+	}
+	return got != nil && reflect.DeepEqual(normalizeColor(want), normalizeColor(got))
+}
+
+func normalizeColor(c *sml.CT_Color) sml.CT_Color {
+	n := *c
+	if n.RgbAttr != nil {
+		rgb := strings.ToUpper(*n.RgbAttr)
+		if len(rgb) == 6 {
+			rgb = "FF" + rgb
+		}
+		n.RgbAttr = &rgb
+	}
+	return n
+}
+
+func dxfFillColor(f *sml.CT_Fill) *sml.CT_Color {
+	if f == nil || f.FillChoice == nil || f.FillChoice.PatternFill == nil {
+		return nil
+	}
+	// Differential fills store a solid color as the background color.
+	if f.FillChoice.PatternFill.BgColor != nil {
+		return f.FillChoice.PatternFill.BgColor
+	}
+	return f.FillChoice.PatternFill.FgColor
+}
+
+func fillColor(f *sml.CT_Fill) *sml.CT_Color {
+	if f == nil || f.FillChoice == nil || f.FillChoice.PatternFill == nil {
+		return nil
+	}
+	pf := f.FillChoice.PatternFill
+	if pf.PatternTypeAttr == sml.ST_PatternTypeNone || pf.PatternTypeAttr == sml.ST_PatternTypeUnset {
+		return nil
+	}
+	return pf.FgColor
+}
+
+func fontColor(f *sml.CT_Font) *sml.CT_Color {
+	if f == nil {
+		return nil
+	}
+	for _, fc := range f.FontChoice {
+		if fc.Color != nil {
+			return fc.Color
+		}
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package spreadsheet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yaklabco/unioffice/v2/schema/soo/sml"
+)
+
+func newFilterSheet(t *testing.T) (*Workbook, Sheet) {
+	t.Helper()
+	wb := New()
+	sheet := wb.AddSheet()
+	sheet.Cell("A1").SetString("Name")
+	sheet.Cell("B1").SetString("Amount")
+	names := []string{"apple", "Banana", "cherry", "date", ""}
+	amounts := []float64{10, 25, 5, 40, 15}
+	for i := range names {
+		row := sheet.Row(uint32(i + 2))
+		if names[i] != "" {
+			row.Cell("A").SetString(names[i])
+		}
+		row.Cell("B").SetNumber(amounts[i])
+	}
+	sheet.SetAutoFilter("A1:B6")
+	return wb, sheet
+}
+
+func hiddenRows(sheet Sheet) []uint32 {
+	ret := []uint32{}
+	for _, r := range sheet.Rows() {
+		if r.IsHidden() {
+			ret = append(ret, r.RowNumber())
+		}
+	}
+	return ret
+}
+
+func equalRows(a, b []uint32) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestApplyAutoFilterValues(t *testing.T) {
+	_, sheet := newFilterSheet(t)
+	sheet.AutoFilter().Column(0).SetValues([]string{"APPLE", "cherry"})
+	if err := sheet.ApplyAutoFilter(); err != nil {
+		t.Fatalf("apply: %s", err)
+	}
+	if got, exp := hiddenRows(sheet), []uint32{3, 5, 6}; !equalRows(got, exp) {
+		t.Errorf("expected hidden rows %v, got %v", exp, got)
+	}
+
+	sheet.AutoFilter().Column(0).SetBlank(true)
+	if err := sheet.ApplyAutoFilter(); err != nil {
+		t.Fatalf("apply: %s", err)
+	}
+	if got, exp := hiddenRows(sheet), []uint32{3, 5}; !equalRows(got, exp) {
+		t.Errorf("expected hidden rows %v, got %v", exp, got)
+	}
+	if vals := sheet.AutoFilter().Column(0).Values(); len(vals) != 2 || vals[0] != "APPLE" {
+		t.Errorf("unexpected values read back: %v", vals)
+	}
+}
+
+func TestApplyAutoFilterCustomAndTop10(t *testing.T) {
+	_, sheet := newFilterSheet(t)
+	col := sheet.AutoFilter().Column(1)
+	err := col.SetCustomFilters(true,
+		CustomFilter{sml.ST_FilterOperatorGreaterThanOrEqual, "10"},
+		CustomFilter{sml.ST_FilterOperatorLessThan, "30"})
+	if err != nil {
+		t.Fatalf("set custom filters: %s", err)
+	}
+	if err := sheet.ApplyAutoFilter(); err != nil {
+		t.Fatalf("apply: %s", err)
+	}
+	if got, exp := hiddenRows(sheet), []uint32{4, 5}; !equalRows(got, exp) {
+		t.Errorf("expected hidden rows %v, got %v", exp, got)
+	}
+
+	col.SetTop10(true, false, 2)
+	if err := sheet.ApplyAutoFilter(); err != nil {
+		t.Fatalf("apply: %s", err)
+	}
+	if got, exp := hiddenRows(sheet), []uint32{2, 4, 6}; !equalRows(got, exp) {
+		t.Errorf("expected hidden rows %v, got %v", exp, got)
+	}
+	if top, pct, val := col.Top10(); !top || pct || val != 2 {
+		t.Errorf("unexpected top10 settings read back: %v %v %v", top, pct, val)
+	}
+
+	sheet.AutoFilter().Column(0).SetCustomFilters(false, CustomFilter{sml.ST_FilterOperatorEqual, "b*"})
+	col.Clear()
+	if err := sheet.ApplyAutoFilter(); err != nil {
+		t.Fatalf("apply: %s", err)
+	}
+	if got, exp := hiddenRows(sheet), []uint32{2, 4, 5, 6}; !equalRows(got, exp) {
+		t.Errorf("expected hidden rows %v, got %v", exp, got)
+	}
+}
+
+func TestApplyAutoFilterNumericCriterionOnText(t *testing.T) {
+	_, sheet := newFilterSheet(t)
+	sheet.Cell("A3").SetNumber(20)
+	sheet.AutoFilter().Column(0).SetCustomFilters(false, CustomFilter{sml.ST_FilterOperatorGreaterThan, "10"})
+	if err := sheet.ApplyAutoFilter(); err != nil {
+		t.Fatalf("apply: %s", err)
+	}
+	if got, exp := hiddenRows(sheet), []uint32{2, 4, 5, 6}; !equalRows(got, exp) {
+		t.Errorf("expected hidden rows %v, got %v", exp, got)
+	}
+}
+
+func TestApplyAutoFilterDynamic(t *testing.T) {
+	wb := New()
+	sheet := wb.AddSheet()
+	sheet.Cell("A1").SetString("Date")
+	dynamicFilterNow = func() time.Time { return time.Date(2024, 5, 15, 12, 0, 0, 0, time.UTC) }
+	defer func() { dynamicFilterNow = time.Now }()
+	dates := []time.Time{
+		time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 4, 30, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 5, 31, 0, 0, 0, 0, time.UTC),
+	}
+	for i, d := range dates {
+		sheet.Cell("A" + string(rune('2'+i))).SetDate(d)
+	}
+	sheet.SetAutoFilter("A1:A4")
+	sheet.AutoFilter().Column(0).SetDynamicFilter(sml.ST_DynamicFilterTypeThisMonth)
+	if err := sheet.ApplyAutoFilter(); err != nil {
+		t.Fatalf("apply: %s", err)
+	}
+	if got, exp := hiddenRows(sheet), []uint32{3}; !equalRows(got, exp) {
+		t.Errorf("expected hidden rows %v, got %v", exp, got)
+	}
+}
+
+func TestAutoFilterIconFilter(t *testing.T) {
+	_, sheet := newFilterSheet(t)
+	sheet.AutoFilter().Column(1).SetIconFilter(sml.ST_IconSetType3Arrows, 2)
+	set, id, ok := sheet.AutoFilter().Column(1).IconFilter()
+	if !ok || set != sml.ST_IconSetType3Arrows || id == nil || *id != 2 {
+		t.Errorf("unexpected icon filter %v %v %v", set, id, ok)
+	}
+	if _, _, ok := sheet.AutoFilter().Column(0).IconFilter(); ok {
+		t.Error("expected no icon filter on the first column")
+	}
+}
+
+func TestAutoFilterMissingColumns(t *testing.T) {
+	wb := New()
+	sheet := wb.AddSheet()
+	if fc := sheet.AutoFilter().Column(0); fc.X() != nil || fc.Type() != FilterTypeNone {
+		t.Error("expected no filter column without an autofilter")
+	}
+	sheet.AutoFilter().RemoveColumn(0)
+
+	sheet.SetAutoFilter("A1:B6")
+	af := sheet.AutoFilter()
+	af.X().FilterColumn = []*sml.CT_FilterColumn{nil}
+	if fc := af.Column(1); fc.X() == nil || fc.ColumnID() != 1 {
+		t.Error("expected the column to be created")
+	}
+	if cols := af.Columns(); len(cols) != 1 || cols[0].ColumnID() != 1 {
+		t.Errorf("expected the missing column to be skipped, got %d", len(cols))
+	}
+}
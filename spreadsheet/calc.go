@@ -0,0 +1,206 @@
+package spreadsheet
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	unioffice "github.com/yaklabco/unioffice/v2"
+	"github.com/yaklabco/unioffice/v2/schema/soo/sml"
+)
+
+const (
+	calcChainType        = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/calcChain"
+	calcChainContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.calcChain+xml"
+)
+
+// RebuildCalcChain replaces the calculation chain with one listing every
+// formula cell of the workbook in sheet order, e.g. after formulas were added
+// or removed. Workbooks without formulas don't have a calculation chain.
+func (wb *Workbook) RebuildCalcChain() error {
+	wb.RemoveCalcChain()
+	chain := sml.NewCalcChain()
+	for _, s := range wb.AllSheets() {
+		first := true
+		for _, r := range s._bbbe.SheetData.Row {
+			for _, c := range r.C {
+				if c.F == nil || c.RAttr == nil {
+					continue
+				}
+				cc := sml.NewCT_CalcCell()
+				cc.RAttr = unioffice.String(*c.RAttr)
+				if first {
+					// the sheet ID applies to the following cells as well
+					cc.IAttr = unioffice.Int32(int32(s._abea.SheetIdAttr))
+					first = false
+				}
+				if c.F.TAttr == sml.ST_CellFormulaTypeArray {
+					cc.AAttr = unioffice.Bool(true)
+				}
+				chain.C = append(chain.C, cc)
+			}
+		}
+	}
+	if len(chain.C) == 0 {
+		return nil
+	}
+	if err := wb.writeExtraFile("xl/calcChain.xml", chain); err != nil {
+		return err
+	}
+	wb._bcg.AddRelationship("calcChain.xml", calcChainType)
+	wb.ContentTypes.AddOverride("/xl/calcChain.xml", calcChainContentType)
+	return nil
+}
+
+// CalcProperties are the calculation settings of a workbook.
+type CalcProperties struct{ x *sml.CT_CalcPr }
+
+// CalcProperties returns the calculation settings of the workbook, creating
+// them if necessary.
+func (wb *Workbook) CalcProperties() CalcProperties {
+	if wb._gbadf.CalcPr == nil {
+		wb._gbadf.CalcPr = sml.NewCT_CalcPr()
+	}
+	return CalcProperties{wb._gbadf.CalcPr}
+}
+
+// X returns the inner wrapped XML type.
+func (c CalcProperties) X() *sml.CT_CalcPr { return c.x }
+
+// SetFullCalcOnLoad controls whether Excel recalculates all formulas when the
+// workbook is opened, instead of trusting the cached values.
+func (c CalcProperties) SetFullCalcOnLoad(b bool) { setBoolAttr(&c.x.FullCalcOnLoadAttr, b, false) }
+
+// FullCalcOnLoad returns whether all formulas are recalculated on load.
+func (c CalcProperties) FullCalcOnLoad() bool { return boolAttr(c.x.FullCalcOnLoadAttr, false) }
+
+// SetCalcMode sets whether formulas are recalculated automatically or
+// manually.
+func (c CalcProperties) SetCalcMode(m sml.ST_CalcMode) { c.x.CalcModeAttr = m }
+
+// CalcMode returns the calculation mode, which defaults to automatic.
+func (c CalcProperties) CalcMode() sml.ST_CalcMode {
+	if c.x.CalcModeAttr == sml.ST_CalcModeUnset {
+		return sml.ST_CalcModeAuto
+	}
+	return c.x.CalcModeAttr
+}
+
+// SetIterate enables iterative calculation of circular references, stopping
+// after count iterations or when values change by less than delta.
+func (c CalcProperties) SetIterate(enabled bool, count uint32, delta float64) {
+	if !enabled {
+		c.x.IterateAttr = nil
+		c.x.IterateCountAttr = nil
+		c.x.IterateDeltaAttr = nil
+		return
+	}
+	c.x.IterateAttr = unioffice.Bool(true)
+	c.x.IterateCountAttr = unioffice.Uint32(count)
+	c.x.IterateDeltaAttr = unioffice.Float64(delta)
+}
+
+// Iterate returns whether iterative calculation is enabled and its maximum
+// number of iterations and maximum change.
+func (c CalcProperties) Iterate() (enabled bool, count uint32, delta float64) {
+	count, delta = 100, 0.001
+	if c.x.IterateCountAttr != nil {
+		count = *c.x.IterateCountAttr
+	}
+	if c.x.IterateDeltaAttr != nil {
+		delta = *c.x.IterateDeltaAttr
+	}
+	return c.x.IterateAttr != nil && *c.x.IterateAttr, count, delta
+}
+
+// SetPrecisionAsDisplayed controls whether calculated values are rounded to
+// the precision of their number format. This also applies to
+// RecalculateFormulas.
+func (c CalcProperties) SetPrecisionAsDisplayed(b bool) {
+	if b {
+		c.x.FullPrecisionAttr = unioffice.Bool(false)
+	} else {
+		c.x.FullPrecisionAttr = nil
+	}
+}
+
+// PrecisionAsDisplayed returns whether calculated values are rounded to the
+// precision of their number format.
+func (c CalcProperties) PrecisionAsDisplayed() bool {
+	return c.x.FullPrecisionAttr != nil && !*c.x.FullPrecisionAttr
+}
+
+// SetForceFullCalc controls whether Excel recalculates all formulas on every
+// calculation, instead of only those depending on changed cells.
+func (c CalcProperties) SetForceFullCalc(b bool) { setBoolAttr(&c.x.ForceFullCalcAttr, b, false) }
+
+// ForceFullCalc returns whether all formulas are recalculated on every
+// calculation.
+func (c CalcProperties) ForceFullCalc() bool { return boolAttr(c.x.ForceFullCalcAttr, false) }
+
+// displayedPrecision rounds a calculated value to the precision of the number
+// format of the cell if the workbook calculates with the precision as
+// displayed.
+func (c Cell) displayedPrecision(v string) string {
+	cp := c._bgg._gbadf.CalcPr
+	if cp == nil || cp.FullPrecisionAttr == nil || *cp.FullPrecisionAttr {
+		return v
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return v
+	}
+	decimals, ok := formatDecimals(c.getFormat())
+	if !ok {
+		return v
+	}
+	p := math.Pow10(decimals)
+	return strconv.FormatFloat(math.Round(f*p)/p, 'f', -1, 64)
+}
+
+// formatDecimals returns the number of decimal places a number format
+// displays, taking percent and thousands scaling into account. General,
+// scientific and date formats return false.
+func formatDecimals(format string) (int, bool) {
+	decimals, scale := 0, 0
+	inFraction, sawDigit := false, false
+	trailingCommas := 0
+	for i := 0; i < len(format); i++ {
+		switch ch := format[i]; ch {
+		case ';':
+			i = len(format)
+		case '"':
+			for i++; i < len(format) && format[i] != '"'; i++ {
+			}
+		case '\\', '_', '*':
+			i++
+		case '[':
+			for ; i < len(format) && format[i] != ']'; i++ {
+			}
+		case '.':
+			inFraction = true
+		case '0', '#', '?':
+			sawDigit = true
+			trailingCommas = 0
+			if inFraction {
+				decimals++
+			}
+		case ',':
+			if sawDigit {
+				trailingCommas++
+			}
+		case '%':
+			scale += 2
+		case 'E', 'e', 'y', 'Y', 'm', 'M', 'd', 'D', 'h', 'H', 's', 'S', '/', '@':
+			return 0, false
+		default:
+			if strings.HasPrefix(strings.ToLower(format[i:]), "general") {
+				return 0, false
+			}
+		}
+	}
+	if !sawDigit {
+		return 0, false
+	}
+	return decimals + scale - 3*trailingCommas, true
+}
@@ -0,0 +1,113 @@
+package spreadsheet
+
+import (
+	"testing"
+
+	"github.com/yaklabco/unioffice/v2/schema/soo/sml"
+)
+
+func TestRebuildCalcChain(t *testing.T) {
+	wb := New()
+	first := wb.AddSheet()
+	first.Cell("A1").SetNumber(1)
+	first.Cell("B1").SetFormulaRaw("A1+1")
+	first.Cell("B2").SetFormulaArray("A1:A2*2")
+	second := wb.AddSheet()
+	second.Cell("C3").SetFormulaRaw("Sheet1!B1")
+
+	if err := wb.RebuildCalcChain(); err != nil {
+		t.Fatalf("error rebuilding calc chain: %s", err)
+	}
+	if err := wb.RebuildCalcChain(); err != nil {
+		t.Fatalf("error rebuilding calc chain twice: %s", err)
+	}
+	n := 0
+	for _, r := range wb._bcg.Relationships() {
+		if r.Type() == calcChainType {
+			n++
+		}
+	}
+	if n != 1 {
+		t.Fatalf("expected a single calc chain relationship, got %d", n)
+	}
+	chain := sml.NewCalcChain()
+	if ok, err := wb.readExtraFile("xl/calcChain.xml", chain); !ok || err != nil {
+		t.Fatalf("expected calc chain part: %v", err)
+	}
+	if len(chain.C) != 3 {
+		t.Fatalf("expected 3 cells in the chain, got %d", len(chain.C))
+	}
+	if *chain.C[0].RAttr != "B1" || chain.C[0].IAttr == nil || *chain.C[0].IAttr != int32(first._abea.SheetIdAttr) {
+		t.Errorf("unexpected first cell of the chain")
+	}
+	if chain.C[1].IAttr != nil || chain.C[1].AAttr == nil || !*chain.C[1].AAttr {
+		t.Errorf("expected array formula cell inheriting the sheet")
+	}
+	if chain.C[2].IAttr == nil || *chain.C[2].IAttr != int32(second._abea.SheetIdAttr) {
+		t.Errorf("expected second sheet ID on its first cell")
+	}
+}
+
+func TestCalcProperties(t *testing.T) {
+	wb := New()
+	cp := wb.CalcProperties()
+	if cp.CalcMode() != sml.ST_CalcModeAuto || cp.FullCalcOnLoad() || cp.PrecisionAsDisplayed() {
+		t.Errorf("unexpected defaults")
+	}
+	if on, count, delta := cp.Iterate(); on || count != 100 || delta != 0.001 {
+		t.Errorf("unexpected iterate defaults %v %d %g", on, count, delta)
+	}
+	cp.SetCalcMode(sml.ST_CalcModeManual)
+	cp.SetFullCalcOnLoad(true)
+	cp.SetIterate(true, 50, 0.01)
+	if cp.CalcMode() != sml.ST_CalcModeManual || !cp.FullCalcOnLoad() {
+		t.Errorf("settings not applied")
+	}
+	if on, count, delta := cp.Iterate(); !on || count != 50 || delta != 0.01 {
+		t.Errorf("unexpected iterate settings %v %d %g", on, count, delta)
+	}
+}
+
+func TestPrecisionAsDisplayed(t *testing.T) {
+	wb := New()
+	sheet := wb.AddSheet()
+	sheet.Cell("A1").SetNumber(1.23456)
+	cs := wb.StyleSheet.AddCellStyle()
+	cs.SetNumberFormat("0.00")
+	pct := wb.StyleSheet.AddCellStyle()
+	pct.SetNumberFormat("0.0%")
+	sheet.Cell("B1").SetFormulaRaw("A1*1")
+	sheet.Cell("B1").SetStyle(cs)
+	sheet.Cell("C1").SetFormulaRaw("A1/100")
+	sheet.Cell("C1").SetStyle(pct)
+	sheet.Cell("D1").SetFormulaRaw("A1*1")
+
+	wb.CalcProperties().SetPrecisionAsDisplayed(true)
+	sheet.RecalculateFormulas()
+	for ref, exp := range map[string]string{"B1": "1.23", "C1": "0.012", "D1": "1.23456"} {
+		if got := *sheet.Cell(ref).X().V; got != exp {
+			t.Errorf("expected %s to be %s, got %s", ref, exp, got)
+		}
+	}
+}
+
+func TestFormatDecimals(t *testing.T) {
+	for format, exp := range map[string]int{
+		"0":           0,
+		"0.00":        2,
+		"#,##0.000":   3,
+		"0.0%":        3,
+		"#,##0,":      -3,
+		`0.0 "USD"`:   1,
+		"0.00;(0.00)": 2,
+	} {
+		if got, ok := formatDecimals(format); !ok || got != exp {
+			t.Errorf("expected %d decimals for %s, got %d", exp, format, got)
+		}
+	}
+	for _, format := range []string{"General", "0.00E+00", "yyyy-mm-dd", "@"} {
+		if _, ok := formatDecimals(format); ok {
+			t.Errorf("expected %s to not round", format)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package spreadsheet
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yaklabco/unioffice/v2/chart"
+	"github.com/yaklabco/unioffice/v2/common/logger"
+	"github.com/yaklabco/unioffice/v2/schema/soo/sml"
+	"github.com/yaklabco/unioffice/v2/spreadsheet/formula"
+	"github.com/yaklabco/unioffice/v2/spreadsheet/reference"
+)
+
+// splitUnion splits a union of references such as "(Sheet1!$A$1,Sheet1!$A$3)"
+// into its references.
+func splitUnion(ref string) []string {
+	ref = strings.TrimSpace(ref)
+	if strings.HasPrefix(ref, "(") && strings.HasSuffix(ref, ")") {
+		ref = ref[1 : len(ref)-1]
+	}
+	ret := []string{}
+	quoted := false
+	start := 0
+	for i, r := range ref {
+		switch {
+		case r == '\'':
+			quoted = !quoted
+		case r == ',' && !quoted:
+			ret = append(ret, strings.TrimSpace(ref[start:i]))
+			start = i + 1
+		}
+	}
+	return append(ret, strings.TrimSpace(ref[start:]))
+}
+
+// ChartValues returns the values of the cells of a reference to the data of a
+// chart by row. The reference is a possibly sheet qualified cell or range
+// such as "Sheet1!$B$2:$B$10", a union of them in parentheses or a defined
+// name, unqualified references referring to the first sheet, hidden or not.
+// It makes the workbook a chart.CellSource.
+func (wb *Workbook) ChartValues(ref string) ([][]chart.CellValue, error) {
+	refs := splitUnion(ref)
+	if len(refs) == 1 && !strings.ContainsAny(refs[0], "!:$") {
+		for _, dn := range wb.DefinedNames() {
+			if strings.EqualFold(dn.Name(), refs[0]) {
+				refs = splitUnion(dn.Content())
+				break
+			}
+		}
+	}
+	ret := [][]chart.CellValue{}
+	for _, r := range refs {
+		typ := formula.ReferenceTypeCell
+		if strings.Contains(r, ":") {
+			typ = formula.ReferenceTypeRange
+		}
+		a, ok := parseAuditArea(formula.Reference{Type: typ, Value: r}, "")
+		if !ok {
+			return nil, fmt.Errorf("invalid reference %q", r)
+		}
+		var sheet Sheet
+		if a.sheet == "" {
+			sheets := wb.AllSheets()
+			if len(sheets) == 0 {
+				return nil, ErrorNotFound
+			}
+			sheet = sheets[0]
+		} else {
+			s, err := wb.sheetByName(a.sheet)
+			if err != nil {
+				return nil, fmt.Errorf("sheet %s: %w", a.sheet, err)
+			}
+			sheet = s
+		}
+		ret = append(ret, sheet.chartValues(a)...)
+	}
+	return ret, nil
+}
+
+// chartValues returns the values of the cells of an area of the sheet by
+// row, without creating the missing cells.
+func (s Sheet) chartValues(a auditArea) [][]chart.CellValue {
+	rows := map[uint32]*sml.CT_Row{}
+	for _, r := range s.X().SheetData.Row {
+		if r.RAttr != nil && *r.RAttr >= a.r1 && *r.RAttr <= a.r2 {
+			rows[*r.RAttr] = r
+		}
+	}
+	ret := [][]chart.CellValue{}
+	for r := a.r1; r <= a.r2; r++ {
+		cells := map[uint32]*sml.CT_Cell{}
+		if row := rows[r]; row != nil {
+			for _, c := range row.C {
+				if c.RAttr == nil {
+					continue
+				}
+				if cr, err := reference.ParseCellReference(*c.RAttr); err == nil {
+					cells[cr.ColumnIdx] = c
+				}
+			}
+		}
+		vals := make([]chart.CellValue, 0, a.c2-a.c1+1)
+		for col := a.c1; col <= a.c2; col++ {
+			x := cells[col]
+			if x == nil {
+				vals = append(vals, chart.CellValue{Empty: true})
+				continue
+			}
+			c := Cell{s._fgeg, &s, rows[r], x}
+			if c.IsEmpty() {
+				vals = append(vals, chart.CellValue{Empty: true})
+				continue
+			}
+			v := chart.CellValue{Text: c.GetFormattedValue(), FormatCode: c.getFormat()}
+			if c.IsNumber() {
+				if n, err := c.GetValueAsNumber(); err == nil {
+					v.Number, v.IsNumber = n, true
+				}
+			}
+			vals = append(vals, v)
+		}
+		ret = append(ret, vals)
+	}
+	return ret
+}
+
+// RefreshChartCaches fills the caches of the data of the charts of the
+// workbook, including the chartex ones, from the cells they reference so
+// viewers that don't evaluate the references display the current data. All
+// charts are refreshed even if some of them fail, the first error being
+// returned.
+func (wb *Workbook) RefreshChartCaches() error {
+	var first error
+	fail := func(err error) {
+		if err != nil && first == nil {
+			first = err
+		}
+	}
+	for _, cs := range wb._faebe {
+		fail(chart.MakeChart(cs).RefreshCaches(wb))
+	}
+	for _, p := range wb._chartEx {
+		fail(chart.MakeChartEx(p.x).RefreshCaches(wb))
+	}
+	return first
+}
+
+// SetRefreshChartCachesOnSave controls whether the chart caches are refreshed
+// from the cells they reference when the workbook is saved, see
+// RefreshChartCaches. Errors refreshing the caches don't fail the save.
+func (wb *Workbook) SetRefreshChartCachesOnSave(b bool) { wb._refreshChartCaches = b }
+
+// flushChartCaches refreshes the chart caches before saving if requested.
+func (wb *Workbook) flushChartCaches() error {
+	if !wb._refreshChartCaches {
+		return nil
+	}
+	if err := wb.RefreshChartCaches(); err != nil {
+		logger.Log.Debug("error refreshing chart caches: %s", err)
+	}
+	return nil
+}
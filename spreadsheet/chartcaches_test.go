@@ -0,0 +1,258 @@
+package spreadsheet
+
+import (
+	"encoding/xml"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/yaklabco/unioffice/v2/chart"
+	"github.com/yaklabco/unioffice/v2/color"
+	"github.com/yaklabco/unioffice/v2/schema/schemas.microsoft.com/office/drawing/2014/chartex"
+	crt "github.com/yaklabco/unioffice/v2/schema/soo/dml/chart"
+	"github.com/yaklabco/unioffice/v2/schema/soo/sml"
+)
+
+// addChartData adds quarters and sales on A1:B5 to the sheet, the sales being
+// formatted as currency.
+func addChartData(wb *Workbook, sheet Sheet) {
+	sheet.SetName("Sales Data")
+	sheet.Cell("A1").SetString("Quarter")
+	sheet.Cell("B1").SetString("Sales")
+	cs := wb.StyleSheet.AddCellStyle()
+	cs.SetNumberFormat("$#,##0.00")
+	for i, q := range []string{"Q1", "Q2", "", "Q4"} {
+		r := sheet.Row(uint32(i + 2))
+		if q != "" {
+			r.Cell("A").SetString(q)
+			r.Cell("B").SetNumber(float64(i+1) * 1000)
+			r.Cell("B").SetStyle(cs)
+		}
+	}
+}
+
+func TestRefreshChartCaches(t *testing.T) {
+	wb := New()
+	sheet := wb.AddSheet()
+	addChartData(wb, sheet)
+	dr := wb.AddDrawing()
+	sheet.SetDrawing(dr)
+	c, _ := dr.AddChart(AnchorTypeTwoCell)
+	s := c.AddBarChart().AddSeries()
+	s.X().Tx = crt.NewCT_SerTx()
+	s.X().Tx.SerTxChoice = &crt.CT_SerTxChoice{StrRef: &crt.CT_StrRef{F: "'Sales Data'!$B$1"}}
+	s.CategoryAxis().SetLabelReference("'Sales Data'!$A$2:$A$5")
+	s.Values().SetReference("'Sales Data'!$B$2:$B$5")
+
+	ex, _, err := dr.AddChartEx(AnchorTypeTwoCell)
+	if err != nil {
+		t.Fatalf("error adding chart: %s", err)
+	}
+	ws := ex.AddWaterfallSeries()
+	ws.Categories().SetReference("'Sales Data'!$A$2:$A$5")
+	ws.Values().SetReference("'Sales Data'!$B$2:$B$5")
+
+	if err := wb.RefreshChartCaches(); err != nil {
+		t.Fatalf("error refreshing caches: %s", err)
+	}
+	if tx := s.X().Tx.SerTxChoice.StrRef.StrCache; tx == nil || len(tx.Pt) != 1 || tx.Pt[0].V != "Sales" {
+		t.Errorf("unexpected series name cache")
+	}
+	cat := s.X().Cat.AxDataSourceChoice.StrRef.StrCache
+	if cat.PtCount.ValAttr != 4 || len(cat.Pt) != 3 || cat.Pt[2].IdxAttr != 3 || cat.Pt[2].V != "Q4" {
+		t.Errorf("unexpected category cache %+v", cat.Pt)
+	}
+	val := s.X().Val.NumDataSourceChoice.NumRef.NumCache
+	if val.PtCount.ValAttr != 4 || len(val.Pt) != 3 || val.Pt[1].V != "2000" || val.FormatCode == nil || *val.FormatCode != "$#,##0.00" {
+		t.Errorf("unexpected value cache %+v", val.Pt)
+	}
+
+	nd := ex.X().ChartData.Data[0].NumDim[0]
+	if len(nd.Lvl) != 1 || nd.Lvl[0].PtCountAttr != 4 || len(nd.Lvl[0].Pt) != 3 || nd.Lvl[0].Pt[2].Content != 4000 {
+		t.Errorf("unexpected chartex values")
+	}
+	if sd := ex.X().ChartData.Data[0].StrDim[0]; len(sd.Lvl) != 1 || sd.Lvl[0].Pt[0].Content != "Q1" {
+		t.Errorf("unexpected chartex categories")
+	}
+	if ex.X().ChartData.Data[0].StrDim[0].TypeAttr != chartex.ST_StringDimensionTypeCat {
+		t.Errorf("unexpected chartex dimension type")
+	}
+}
+
+func TestChartValues(t *testing.T) {
+	wb := New()
+	sheet := wb.AddSheet()
+	addChartData(wb, sheet)
+	wb.AddDefinedName("Quarters", "'Sales Data'!$A$2:$A$3")
+
+	vals, err := wb.ChartValues("('Sales Data'!$A$2,'Sales Data'!$B$2:$B$3)")
+	if err != nil {
+		t.Fatalf("error reading values: %s", err)
+	}
+	if len(vals) != 3 || vals[0][0].Text != "Q1" || !vals[2][0].IsNumber || vals[2][0].Number != 2000 || vals[2][0].Text != "$2,000.00" {
+		t.Errorf("unexpected values %+v", vals)
+	}
+	vals, err = wb.ChartValues("Quarters")
+	if err != nil || len(vals) != 2 || vals[1][0].Text != "Q2" {
+		t.Errorf("unexpected defined name values %+v %v", vals, err)
+	}
+	if _, err := wb.ChartValues("Missing!A1"); err == nil {
+		t.Error("expected error for a missing sheet")
+	}
+	if len(sheet.X().SheetData.Row) != 5 {
+		t.Errorf("expected no rows to be created, got %d", len(sheet.X().SheetData.Row))
+	}
+}
+
+func TestChartValuesHiddenSheet(t *testing.T) {
+	wb := New()
+	sheet := wb.AddSheet()
+	addChartData(wb, sheet)
+	other := wb.AddSheet()
+	other.Cell("A1").SetString("other")
+	if err := sheet.SetState(sml.ST_SheetStateHidden); err != nil {
+		t.Fatalf("error hiding sheet: %s", err)
+	}
+
+	vals, err := wb.ChartValues("'sales data'!$A$2")
+	if err != nil || len(vals) != 1 || vals[0][0].Text != "Q1" {
+		t.Errorf("unexpected qualified values %+v %v", vals, err)
+	}
+	vals, err = wb.ChartValues("$A$2")
+	if err != nil || len(vals) != 1 || vals[0][0].Text != "Q1" {
+		t.Errorf("expected unqualified references on the first sheet, got %+v %v", vals, err)
+	}
+}
+
+func TestChartPlots(t *testing.T) {
+	wb := New()
+	sheet := wb.AddSheet()
+	addChartData(wb, sheet)
+	dr := wb.AddDrawing()
+	sheet.SetDrawing(dr)
+	c, _ := dr.AddChart(AnchorTypeTwoCell)
+	bc := c.AddBarChart()
+	ca, va := c.AddCategoryAxis(), c.AddValueAxis()
+	bc.AddAxis(ca)
+	bc.AddAxis(va)
+	va.SetMax(5000)
+	s := bc.AddSeries()
+	s.CategoryAxis().SetLabelReference("'Sales Data'!$A$2:$A$5")
+	s.Values().SetReference("'Sales Data'!$B$2:$B$5")
+	if err := wb.RefreshChartCaches(); err != nil {
+		t.Fatalf("error refreshing caches: %s", err)
+	}
+
+	plots := c.Plots()
+	if len(plots) != 1 || plots[0].Type() != chart.PlotTypeBar || len(plots[0].Axes()) != 2 {
+		t.Fatalf("unexpected plots")
+	}
+	ser := plots[0].Series()
+	if len(ser) != 1 || ser[0].ValuesReference() != "'Sales Data'!$B$2:$B$5" {
+		t.Fatalf("unexpected series")
+	}
+	if cats := ser[0].Categories().Values(); len(cats) != 4 || cats[3] != "Q4" {
+		t.Errorf("unexpected categories %v", cats)
+	}
+	if vals := ser[0].Values().Values(); len(vals) != 4 || vals[1] != 2000 || !math.IsNaN(vals[2]) {
+		t.Errorf("unexpected values %v", vals)
+	}
+	if a, ok := c.Axis(va.AxisID()); !ok {
+		t.Error("expected value axis")
+	} else if max, ok := a.(chart.ValueAxis).Max(); !ok || max != 5000 {
+		t.Errorf("unexpected maximum %v", max)
+	}
+
+	ser[0].SetNameReference("'Sales Data'!$B$1")
+	ser[0].SetValuesReference("'Sales Data'!$B$2:$B$3")
+	ser[0].SetCategoriesReference("'Sales Data'!$A$2:$A$3")
+	if err := plots[0].SetType(chart.PlotTypeLine); err != nil {
+		t.Fatalf("error changing the type: %s", err)
+	}
+	if err := wb.RefreshChartCaches(); err != nil {
+		t.Fatalf("error refreshing caches: %s", err)
+	}
+	ser = c.Plots()[0].Series()
+	if c.Plots()[0].Type() != chart.PlotTypeLine || len(ser) != 1 || ser[0].Name() != "Sales" || len(ser[0].Values().Values()) != 2 {
+		t.Errorf("unexpected line plot")
+	}
+	if len(c.Plots()[0].Axes()) != 2 {
+		t.Errorf("expected the axes to be kept")
+	}
+	if err := c.X().Validate(); err != nil {
+		t.Errorf("invalid chart: %s", err)
+	}
+	if err := c.Plots()[0].SetType(chart.PlotTypePie); err != nil {
+		t.Fatalf("error changing the type: %s", err)
+	}
+	if len(c.Axes()) != 0 || len(c.Plots()) != 1 || c.Plots()[0].Series()[0].CategoriesReference() != "'Sales Data'!$A$2:$A$3" {
+		t.Errorf("unexpected pie plot")
+	}
+}
+
+func TestChartAnalysis(t *testing.T) {
+	wb := New()
+	sheet := wb.AddSheet()
+	addChartData(wb, sheet)
+	dr := wb.AddDrawing()
+	sheet.SetDrawing(dr)
+	c, _ := dr.AddChart(AnchorTypeTwoCell)
+	bc := c.AddBarChart()
+	ca, va := c.AddCategoryAxis(), c.AddValueAxis()
+	bc.AddAxis(ca)
+	bc.AddAxis(va)
+	bs := bc.AddSeries()
+	bs.CategoryAxis().SetLabelReference("'Sales Data'!$A$2:$A$5")
+	bs.Values().SetReference("'Sales Data'!$B$2:$B$5")
+	bs.DataPoint(3).Properties().SetSolidFill(color.Red)
+	bs.DataPoint(1)
+	bs.AddErrorBars().SetCustomReferences("'Sales Data'!$B$2:$B$5", "")
+	tl := bs.AddTrendline(crt.ST_TrendlineTypePoly)
+	tl.SetDisplayEquation(true)
+	tl.SetDisplayRSquared(true)
+
+	sc, sv := c.AddSecondaryAxes()
+	lc := c.AddLineChart()
+	lc.AddAxis(sc)
+	lc.AddAxis(sv)
+	ls := lc.AddSeries()
+	ls.Values().SetReference("'Sales Data'!$B$2:$B$5")
+	if err := chart.MakeSeries(ls.X()).SetLabelsReference("'Sales Data'!$A$2:$A$5"); err != nil {
+		t.Fatalf("error labeling series: %s", err)
+	}
+	c.AddDataTable().SetShowOutline(false)
+
+	if err := wb.RefreshChartCaches(); err != nil {
+		t.Fatalf("error refreshing caches: %s", err)
+	}
+	if err := c.X().Validate(); err != nil {
+		t.Errorf("invalid chart: %s", err)
+	}
+	ser := c.Plots()[0].Series()[0]
+	if pts := ser.DataPoints(); len(pts) != 2 || pts[0].Index() != 1 || pts[1].Index() != 3 {
+		t.Errorf("expected sorted data points")
+	}
+	if eb := ser.ErrorBars(); len(eb) != 1 || eb[0].X().Plus.NumDataSourceChoice.NumRef.NumCache == nil || eb[0].X().Minus != nil {
+		t.Errorf("unexpected error bars")
+	}
+	if tls := ser.Trendlines(); len(tls) != 1 || *tls[0].X().Order.ValAttr != 2 || tls[0].X().TrendlineLbl == nil {
+		t.Errorf("unexpected trendlines")
+	}
+	if ref := c.Plots()[1].Series()[0].LabelsReference(); ref != "'Sales Data'!$A$2:$A$5" {
+		t.Errorf("unexpected labels reference %q", ref)
+	}
+	if a, _ := c.Axis(sv.AxisID()); a.(chart.ValueAxis).X().AxPos.ValAttr != crt.ST_AxPosR {
+		t.Errorf("expected the secondary axis on the right")
+	}
+	if _, err := chart.MakeSeries(crt.NewCT_SurfaceSer()).AddTrendline(crt.ST_TrendlineTypeLinear); err == nil {
+		t.Errorf("expected error adding a trendline to a surface series")
+	}
+
+	out, err := xml.Marshal(c.X())
+	if err != nil {
+		t.Fatalf("error marshaling chart: %s", err)
+	}
+	if !strings.Contains(string(out), "<c15:datalabelsRange") || !strings.Contains(string(out), "<c15:showDataLabelsRange val=\"1\"") {
+		t.Errorf("expected the labels reference extensions")
+	}
+}
@@ -0,0 +1,272 @@
+package spreadsheet
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/yaklabco/unioffice/v2"
+	"github.com/yaklabco/unioffice/v2/chart"
+	"github.com/yaklabco/unioffice/v2/color"
+	"github.com/yaklabco/unioffice/v2/common"
+	"github.com/yaklabco/unioffice/v2/schema/schemas.microsoft.com/office/drawing/2014/chartex"
+	"github.com/yaklabco/unioffice/v2/schema/soo/dml"
+	sd "github.com/yaklabco/unioffice/v2/schema/soo/dml/spreadsheetDrawing"
+)
+
+// chartExFallbackText is the text displayed instead of a chartex chart by the
+// applications that don't support them, the same Excel writes.
+const chartExFallbackText = "This chart isn't available in your version of Excel.\n\n" +
+	"Editing this shape or saving this workbook into a different file format will permanently break the chart."
+
+// chartExPart is a chartex part added to the workbook, kept as an extra file
+// as the package writer doesn't support them.
+type chartExPart struct {
+	path string
+	x    *chartex.ChartSpace
+}
+
+// flushChartEx writes the chartex parts to the extra files so they're saved
+// with the workbook.
+func (wb *Workbook) flushChartEx() error {
+	for _, p := range wb._chartEx {
+		if err := wb.writeExtraFile(p.path, p.x); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadChartEx decodes the chartex parts read with the workbook so they can be
+// modified, they're written back on save.
+func (wb *Workbook) loadChartEx() error {
+	known := map[string]bool{}
+	for _, p := range wb._chartEx {
+		known[p.path] = true
+	}
+	for _, ef := range wb.ExtraFiles {
+		dir, name := path.Split(ef.ZipPath)
+		if dir != "xl/charts/" || known[ef.ZipPath] || !strings.HasPrefix(name, "chartEx") || path.Ext(name) != ".xml" {
+			continue
+		}
+		if _, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(name, "chartEx"), ".xml")); err != nil {
+			continue
+		}
+		cs := chartex.NewChartSpace()
+		if _, err := wb.readExtraFile(ef.ZipPath, cs); err != nil {
+			return err
+		}
+		wb._chartEx = append(wb._chartEx, &chartExPart{path: ef.ZipPath, x: cs})
+	}
+	return nil
+}
+
+// ChartExCharts returns the chartex charts of the workbook, both those read
+// with it and those added with AddChartEx.
+func (wb *Workbook) ChartExCharts() []chart.ChartEx {
+	ret := []chart.ChartEx{}
+	for _, p := range wb._chartEx {
+		ret = append(ret, chart.MakeChartEx(p.x))
+	}
+	return ret
+}
+
+// AddChartEx adds a chart of one of the types introduced by Office 2016, e.g.
+// a waterfall, to the drawing. Older applications display a text shape
+// instead of the chart unless a fallback image is set with
+// SetChartExFallbackImage.
+func (d Drawing) AddChartEx(at AnchorType) (chart.ChartEx, Anchor, error) {
+	wb := d._bgbe
+	rels, ok := d.rels()
+	if !ok {
+		return chart.ChartEx{}, nil, errors.New("drawing not found in workbook")
+	}
+	cs := chartex.NewChartSpace()
+	partPath, n := wb.nextPartPath("xl/charts/chartEx%d.xml")
+	part := &chartExPart{path: partPath, x: cs}
+	if err := wb.writeExtraFile(part.path, part.x); err != nil {
+		return chart.ChartEx{}, nil, err
+	}
+	wb._chartEx = append(wb._chartEx, part)
+	rel := rels.AddRelationship(fmt.Sprintf("../charts/chartEx%d.xml", n), chartex.RelationshipType)
+	wb.ContentTypes.AddOverride("/"+partPath, chartex.ContentType)
+
+	// numbered as the charts of AddChart, by position of the anchor
+	id := uint32(len(d._gbad.EG_Anchor) + 1)
+	anchor, obj := d.addAnchor(at)
+	name := fmt.Sprintf("Chart %d", id)
+	gf := sd.NewCT_GraphicalObjectFrame()
+	gf.MacroAttr = unioffice.String("")
+	gf.NvGraphicFramePr.CNvPr.IdAttr = id
+	gf.NvGraphicFramePr.CNvPr.NameAttr = name
+	gf.Graphic.GraphicData.UriAttr = chartex.Namespace
+	gf.Graphic.GraphicData.Any = []unioffice.Any{&chartex.Chart{IdAttr: rel.ID()}}
+	obj.GraphicFrame = gf
+	prefix, ns := cs.Requires()
+	obj.AlternateContent = &sd.AlternateContent{
+		Requires:   prefix,
+		Namespaces: map[string]string{prefix: ns},
+		Fallback:   &sd.EG_ObjectChoicesChoice{Sp: chartExFallbackShape(id, name)},
+	}
+
+	c := chart.MakeChartEx(cs)
+	c.Properties().SetSolidFill(color.White)
+	return c, anchor, nil
+}
+
+// addAnchor adds an empty anchor of a type to the drawing, placed as the
+// anchors of AddChart.
+func (d Drawing) addAnchor(at AnchorType) (Anchor, *sd.EG_ObjectChoicesChoice) {
+	obj := sd.NewEG_ObjectChoicesChoice()
+	eg := &sd.EG_Anchor{AnchorChoice: &sd.EG_AnchorChoice{}}
+	var anchor Anchor
+	switch at {
+	case AnchorTypeAbsolute:
+		a := sd.NewCT_AbsoluteAnchor()
+		a.ObjectChoicesChoice = obj
+		eg.AnchorChoice.AbsoluteAnchor = a
+		anchor = AbsoluteAnchor{a}
+	case AnchorTypeOneCell:
+		a := sd.NewCT_OneCellAnchor()
+		a.ObjectChoicesChoice = obj
+		eg.AnchorChoice.OneCellAnchor = a
+		anchor = OneCellAnchor{a}
+	default:
+		a := sd.NewCT_TwoCellAnchor()
+		a.EditAsAttr = sd.ST_EditAsOneCell
+		a.From.Col = 5
+		a.To.Col = 10
+		a.To.Row = 20
+		for _, m := range []*sd.CT_Marker{a.From, a.To} {
+			m.ColOff.ST_CoordinateUnqualified = unioffice.Int64(0)
+			m.RowOff.ST_CoordinateUnqualified = unioffice.Int64(0)
+		}
+		a.ObjectChoicesChoice = obj
+		eg.AnchorChoice.TwoCellAnchor = a
+		anchor = TwoCellAnchor{a}
+	}
+	d._gbad.EG_Anchor = append(d._gbad.EG_Anchor, eg)
+	return anchor, obj
+}
+
+// chartExFallbackShape returns the text shape displayed instead of a chartex
+// chart.
+func chartExFallbackShape(id uint32, name string) *sd.CT_Shape {
+	sp := sd.NewCT_Shape()
+	sp.MacroAttr = unioffice.String("")
+	sp.TextlinkAttr = unioffice.String("")
+	sp.NvSpPr.CNvPr.IdAttr = id
+	sp.NvSpPr.CNvPr.NameAttr = name
+	sp.NvSpPr.CNvSpPr.SpLocks = dml.NewCT_ShapeLocking()
+	sp.NvSpPr.CNvSpPr.SpLocks.NoTextEditAttr = unioffice.Bool(true)
+	sp.SpPr.GeometryChoice.PrstGeom = dml.NewCT_PresetGeometry2D()
+	sp.SpPr.GeometryChoice.PrstGeom.PrstAttr = dml.ST_ShapeTypeRect
+	sp.SpPr.FillPropertiesChoice.SolidFill = dml.NewCT_SolidColorFillProperties()
+	sp.SpPr.FillPropertiesChoice.SolidFill.PrstClr = dml.NewCT_PresetColor()
+	sp.SpPr.FillPropertiesChoice.SolidFill.PrstClr.ValAttr = dml.ST_PresetColorValWhite
+	sp.SpPr.Ln = dml.NewCT_LineProperties()
+	sp.SpPr.Ln.WAttr = unioffice.Int32(1)
+	sp.SpPr.Ln.LineFillPropertiesChoice.SolidFill = dml.NewCT_SolidColorFillProperties()
+	sp.SpPr.Ln.LineFillPropertiesChoice.SolidFill.PrstClr = dml.NewCT_PresetColor()
+	sp.SpPr.Ln.LineFillPropertiesChoice.SolidFill.PrstClr.ValAttr = dml.ST_PresetColorValGreen
+	sp.TxBody = dml.NewCT_TextBody()
+	sp.TxBody.BodyPr.VertOverflowAttr = dml.ST_TextVertOverflowTypeClip
+	sp.TxBody.BodyPr.HorzOverflowAttr = dml.ST_TextHorzOverflowTypeClip
+	sp.TxBody.LstStyle = dml.NewCT_TextListStyle()
+	for _, line := range strings.Split(chartExFallbackText, "\n") {
+		para := dml.NewCT_TextParagraph()
+		if line != "" {
+			run := dml.NewEG_TextRun()
+			run.TextRunChoice.R = dml.NewCT_RegularTextRun()
+			run.TextRunChoice.R.RPr = dml.NewCT_TextCharacterProperties()
+			run.TextRunChoice.R.RPr.SzAttr = unioffice.Int32(1100)
+			run.TextRunChoice.R.T = line
+			para.EG_TextRun = append(para.EG_TextRun, run)
+		}
+		sp.TxBody.P = append(sp.TxBody.P, para)
+	}
+	return sp
+}
+
+// chartExFrame returns the graphic frame on the drawing that displays a chart
+// added with AddChartEx.
+func (d Drawing) chartExFrame(c chart.ChartEx) (*sd.EG_ObjectChoicesChoice, error) {
+	rels, ok := d.rels()
+	if !ok {
+		return nil, errors.New("drawing not found in workbook")
+	}
+	var p *chartExPart
+	for _, part := range d._bgbe._chartEx {
+		if part.x == c.X() {
+			p = part
+		}
+	}
+	if p == nil {
+		return nil, errors.New("chart not found in workbook")
+	}
+	for _, r := range rels.Relationships() {
+		if r.Type() != chartex.RelationshipType || partPath("xl/drawings", r.Target()) != p.path {
+			continue
+		}
+		for _, eg := range d._gbad.EG_Anchor {
+			if _, obj := anchorFor(eg); obj != nil && obj.GraphicFrame != nil && frameChartRelID(obj.GraphicFrame) == r.ID() {
+				return obj, nil
+			}
+		}
+	}
+	return nil, errors.New("chart not found on drawing")
+}
+
+// SetChartExFallbackImage sets the image displayed instead of a chart added
+// with AddChartEx by the applications that don't support it, e.g. a
+// rendering of the chart. The image must have been added to the workbook.
+func (d Drawing) SetChartExFallbackImage(c chart.ChartEx, img common.ImageRef) error {
+	obj, err := d.chartExFrame(c)
+	if err != nil {
+		return err
+	}
+	idx := 0
+	for i, im := range d._bgbe.Images {
+		if im == img {
+			idx = i + 1
+		}
+	}
+	if idx == 0 {
+		return errors.New("image not found in workbook")
+	}
+	rels, _ := d.rels()
+	target := fmt.Sprintf("../media/image%d.%s", idx, img.Format())
+	// the image may already be related to the drawing, e.g. when read with
+	// the workbook
+	relID := ""
+	for _, r := range rels.X().Relationship {
+		if r.TargetAttr == target && r.TypeAttr == unioffice.ImageType {
+			relID = r.IdAttr
+		}
+	}
+	if relID == "" {
+		relID = rels.AddRelationship(target, unioffice.ImageType).ID()
+	}
+
+	cnv := obj.GraphicFrame.NvGraphicFramePr.CNvPr
+	pic := sd.NewCT_Picture()
+	pic.NvPicPr.CNvPr.IdAttr = cnv.IdAttr
+	pic.NvPicPr.CNvPr.NameAttr = cnv.NameAttr
+	pic.NvPicPr.CNvPicPr.PicLocks = dml.NewCT_PictureLocking()
+	pic.NvPicPr.CNvPicPr.PicLocks.NoChangeAspectAttr = unioffice.Bool(true)
+	pic.BlipFill.Blip = dml.NewCT_Blip()
+	pic.BlipFill.Blip.EmbedAttr = unioffice.String(relID)
+	pic.BlipFill.FillModePropertiesChoice.Stretch = dml.NewCT_StretchInfoProperties()
+	pic.BlipFill.FillModePropertiesChoice.Stretch.FillRect = dml.NewCT_RelativeRect()
+	pic.SpPr.GeometryChoice.PrstGeom = dml.NewCT_PresetGeometry2D()
+	pic.SpPr.GeometryChoice.PrstGeom.PrstAttr = dml.ST_ShapeTypeRect
+
+	if obj.AlternateContent == nil {
+		prefix, ns := c.X().Requires()
+		obj.AlternateContent = &sd.AlternateContent{Requires: prefix, Namespaces: map[string]string{prefix: ns}}
+	}
+	obj.AlternateContent.Fallback = &sd.EG_ObjectChoicesChoice{Pic: pic}
+	return nil
+}
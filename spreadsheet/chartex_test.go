@@ -0,0 +1,149 @@
+package spreadsheet
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image"
+	"strings"
+	"testing"
+
+	"github.com/yaklabco/unioffice/v2/common"
+	"github.com/yaklabco/unioffice/v2/schema/schemas.microsoft.com/office/drawing/2014/chartex"
+	sd "github.com/yaklabco/unioffice/v2/schema/soo/dml/spreadsheetDrawing"
+)
+
+func TestChartEx(t *testing.T) {
+	wb := New()
+	sheet := wb.AddSheet()
+	dr := wb.AddDrawing()
+	sheet.SetDrawing(dr)
+
+	c, _, err := dr.AddChartEx(AnchorTypeTwoCell)
+	if err != nil {
+		t.Fatalf("error adding chart: %s", err)
+	}
+	c.SetTitle("Cash flow")
+	s := c.AddWaterfallSeries()
+	s.SetText("Cash")
+	s.Categories().SetValues([]string{"Start", "Sales", "Costs", "End"})
+	s.Values().SetValues([]float64{100, 50, -30, 120})
+	s.SetSubtotals(0, 3)
+
+	buf := bytes.Buffer{}
+	if err := xml.NewEncoder(&buf).Encode(dr.X()); err != nil {
+		t.Fatalf("error encoding drawing: %s", err)
+	}
+	for _, exp := range []string{"<mc:AlternateContent", `Requires="cx1"`, "<mc:Fallback>", `uri="` + chartex.Namespace + `"`, "available in your version of Excel"} {
+		if !strings.Contains(buf.String(), exp) {
+			t.Errorf("expected %s in drawing", exp)
+		}
+	}
+
+	// the frame and its fallback are kept when the drawing is read back
+	got := sd.NewWsDr()
+	if err := xml.Unmarshal(buf.Bytes(), got); err != nil {
+		t.Fatalf("error decoding drawing: %s", err)
+	}
+	_, obj := anchorFor(got.EG_Anchor[0])
+	if obj == nil || obj.GraphicFrame == nil || obj.AlternateContent == nil || obj.AlternateContent.Fallback.Sp == nil {
+		t.Fatal("expected chart frame with a fallback shape")
+	}
+	if id := frameChartRelID(obj.GraphicFrame); id == "" {
+		t.Error("expected chart relationship")
+	}
+	if cnv := obj.GraphicFrame.NvGraphicFramePr.CNvPr; cnv.IdAttr != 1 || cnv.NameAttr != "Chart 1" {
+		t.Errorf("expected the frame to be numbered by its anchor, got %d %q", cnv.IdAttr, cnv.NameAttr)
+	}
+
+	data := []byte{1, 2, 3}
+	img, err := wb.AddImage(common.Image{Data: &data, Format: "png", Size: image.Point{X: 10, Y: 20}})
+	if err != nil {
+		t.Fatalf("error adding image: %s", err)
+	}
+	if err := dr.SetChartExFallbackImage(c, img); err != nil {
+		t.Fatalf("error setting fallback image: %s", err)
+	}
+	if fb := dr.X().EG_Anchor[0].AnchorChoice.TwoCellAnchor.ObjectChoicesChoice.AlternateContent.Fallback; fb.Pic == nil {
+		t.Error("expected fallback picture")
+	}
+	// setting the image again, e.g. after reading the workbook back, reuses
+	// the relationship to the image
+	rels, _ := dr.rels()
+	nrels := len(rels.X().Relationship)
+	if err := dr.SetChartExFallbackImage(c, img); err != nil {
+		t.Fatalf("error setting fallback image: %s", err)
+	}
+	fb := dr.X().EG_Anchor[0].AnchorChoice.TwoCellAnchor.ObjectChoicesChoice.AlternateContent.Fallback
+	if len(rels.X().Relationship) != nrels || *fb.Pic.BlipFill.Blip.EmbedAttr != rels.X().Relationship[nrels-1].IdAttr {
+		t.Errorf("expected the image relationship to be reused")
+	}
+
+	if err := wb.flushChartEx(); err != nil {
+		t.Fatalf("error writing chart: %s", err)
+	}
+	cs := chartex.NewChartSpace()
+	if ok, err := wb.readExtraFile("xl/charts/chartEx1.xml", cs); !ok || err != nil {
+		t.Fatalf("expected chart part: %v %v", ok, err)
+	}
+	series := cs.Chart.PlotArea.PlotAreaRegion.Series
+	if len(series) != 1 || series[0].LayoutIdAttr != chartex.ST_SeriesLayoutWaterfall {
+		t.Fatalf("unexpected series")
+	}
+	if st := series[0].LayoutPr.Subtotals; st == nil || len(st.Idx) != 2 || st.Idx[1].ValAttr != 3 {
+		t.Errorf("unexpected subtotals")
+	}
+	if len(cs.ChartData.Data) != 1 || len(cs.ChartData.Data[0].NumDim) != 1 || len(cs.ChartData.Data[0].NumDim[0].Lvl[0].Pt) != 4 {
+		t.Errorf("unexpected chart data")
+	}
+}
+
+func TestLoadChartEx(t *testing.T) {
+	wb := New()
+	cs := chartex.NewChartSpace()
+	if err := wb.writeExtraFile("xl/charts/chartEx2.xml", cs); err != nil {
+		t.Fatalf("error writing chart: %s", err)
+	}
+	if err := wb.writeExtraFile("xl/charts/colors2.xml", cs); err != nil {
+		t.Fatalf("error writing part: %s", err)
+	}
+	// parts read with the workbook are decoded once
+	for i := 0; i < 2; i++ {
+		if err := wb.loadChartEx(); err != nil {
+			t.Fatalf("error loading charts: %s", err)
+		}
+	}
+	charts := wb.ChartExCharts()
+	if len(charts) != 1 {
+		t.Fatalf("expected 1 chart, got %d", len(charts))
+	}
+	charts[0].SetTitle("Loaded")
+	if err := wb.flushChartEx(); err != nil {
+		t.Fatalf("error writing chart: %s", err)
+	}
+	got := chartex.NewChartSpace()
+	if ok, err := wb.readExtraFile("xl/charts/chartEx2.xml", got); !ok || err != nil || got.Chart.Title == nil {
+		t.Errorf("expected the loaded chart to be written back")
+	}
+}
+
+func TestRemoveChartEx(t *testing.T) {
+	wb := New()
+	sheet := wb.AddSheet()
+	dr := wb.AddDrawing()
+	sheet.SetDrawing(dr)
+	_, anchor, err := dr.AddChartEx(AnchorTypeTwoCell)
+	if err != nil {
+		t.Fatalf("error adding chart: %s", err)
+	}
+	if !dr.Remove(anchor) {
+		t.Fatal("expected chart to be removed")
+	}
+	if len(wb.ChartExCharts()) != 0 || len(wb.ExtraFiles) != 0 {
+		t.Errorf("expected the chartex part to be removed")
+	}
+	for _, tc := range wb.ContentTypes.X().TypesChoice {
+		if tc.Override != nil && tc.Override.PartNameAttr == "/xl/charts/chartEx1.xml" {
+			t.Error("expected the content type of the chartex part to be dropped")
+		}
+	}
+}
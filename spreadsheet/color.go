@@ -0,0 +1,238 @@
+package spreadsheet
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/yaklabco/unioffice/v2/color"
+	"github.com/yaklabco/unioffice/v2/schema/soo/dml"
+	"github.com/yaklabco/unioffice/v2/schema/soo/sml"
+)
+
+// indexedColors is the default legacy color palette referenced by indexed
+// colors.
+var indexedColors = [...]string{
+	"000000", "FFFFFF", "FF0000", "00FF00", "0000FF", "FFFF00", "FF00FF", "00FFFF",
+	"000000", "FFFFFF", "FF0000", "00FF00", "0000FF", "FFFF00", "FF00FF", "00FFFF",
+	"800000", "008000", "000080", "808000", "800080", "008080", "C0C0C0", "808080",
+	"9999FF", "993366", "FFFFCC", "CCFFFF", "660066", "FF8080", "0066CC", "CCCCFF",
+	"000080", "FF00FF", "FFFF00", "00FFFF", "800080", "800000", "008080", "0000FF",
+	"00CCFF", "CCFFFF", "CCFFCC", "FFFF99", "99CCFF", "FF99CC", "CC99FF", "FFCC99",
+	"3366FF", "33CCCC", "99CC00", "FFCC00", "FF9900", "FF6600", "666699", "969696",
+	"003366", "339966", "003300", "333300", "993300", "993366", "333399", "333333",
+}
+
+// themeColor returns the RGB value of a theme color index. Spreadsheet theme
+// indexes swap the dark and light entries of the color scheme.
+func (wb *Workbook) themeColor(idx uint32) (string, bool) {
+	themes := wb.Themes()
+	if len(themes) == 0 || themes[0].ThemeElements == nil || themes[0].ThemeElements.ClrScheme == nil {
+		return "", false
+	}
+	cs := themes[0].ThemeElements.ClrScheme
+	var c *dml.CT_Color
+	switch idx {
+	case 0:
+		c = cs.Lt1
+	case 1:
+		c = cs.Dk1
+	case 2:
+		c = cs.Lt2
+	case 3:
+		c = cs.Dk2
+	case 4:
+		c = cs.Accent1
+	case 5:
+		c = cs.Accent2
+	case 6:
+		c = cs.Accent3
+	case 7:
+		c = cs.Accent4
+	case 8:
+		c = cs.Accent5
+	case 9:
+		c = cs.Accent6
+	case 10:
+		c = cs.Hlink
+	case 11:
+		c = cs.FolHlink
+	}
+	switch {
+	case c == nil:
+		return "", false
+	case c.SrgbClr != nil:
+		return strings.ToUpper(c.SrgbClr.ValAttr), true
+	case c.SysClr != nil && c.SysClr.LastClrAttr != nil:
+		return strings.ToUpper(*c.SysClr.LastClrAttr), true
+	}
+	return "", false
+}
+
+// indexedColor returns the RGB value of an indexed color from the custom
+// palette of the workbook styles, or the default palette if there is none.
+func (wb *Workbook) indexedColor(idx uint32) (string, bool) {
+	if wb != nil {
+		if cs := wb.StyleSheet.X().Colors; cs != nil && cs.IndexedColors != nil && len(cs.IndexedColors.RgbColor) > 0 {
+			if int(idx) >= len(cs.IndexedColors.RgbColor) || cs.IndexedColors.RgbColor[idx].RgbAttr == nil {
+				return "", false
+			}
+			rgb := strings.ToUpper(*cs.IndexedColors.RgbColor[idx].RgbAttr)
+			if len(rgb) == 8 {
+				rgb = rgb[2:]
+			}
+			return rgb, true
+		}
+	}
+	if int(idx) >= len(indexedColors) {
+		return "", false
+	}
+	return indexedColors[idx], true
+}
+
+// ResolveColor returns the RGB color of a color, resolving theme colors with
+// the workbook theme and indexed colors with the legacy palette of the
+// workbook styles, and applying the tint. Automatic colors and colors that
+// can't be resolved return color.Auto.
+func (wb *Workbook) ResolveColor(c *sml.CT_Color) color.Color {
+	rgb, ok := wb.resolveColor(c)
+	if !ok {
+		return color.Auto
+	}
+	return color.FromHex(rgb)
+}
+
+// resolveColor returns the RGB value (e.g. "FF0000") of a color, resolving
+// indexed and theme colors and applying the tint. Automatic and unresolvable
+// colors return false.
+func (wb *Workbook) resolveColor(c *sml.CT_Color) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	var rgb string
+	switch {
+	case c.RgbAttr != nil:
+		rgb = strings.ToUpper(*c.RgbAttr)
+		if len(rgb) == 8 {
+			// drop the alpha channel
+			rgb = rgb[2:]
+		}
+	case c.ThemeAttr != nil:
+		var ok bool
+		if wb == nil {
+			return "", false
+		}
+		if rgb, ok = wb.themeColor(*c.ThemeAttr); !ok {
+			return "", false
+		}
+	case c.IndexedAttr != nil:
+		var ok bool
+		if rgb, ok = wb.indexedColor(*c.IndexedAttr); !ok {
+			return "", false
+		}
+	default:
+		return "", false
+	}
+	if len(rgb) != 6 {
+		return "", false
+	}
+	if c.TintAttr != nil && *c.TintAttr != 0 {
+		rgb = applyTint(rgb, *c.TintAttr)
+	}
+	return rgb, true
+}
+
+// applyTint lightens (positive tint) or darkens (negative tint) an RGB color
+// by adjusting its luminance.
+func applyTint(rgb string, tint float64) string {
+	v, err := strconv.ParseUint(rgb, 16, 32)
+	if err != nil {
+		return rgb
+	}
+	r, g, b := float64(v>>16&0xFF)/255, float64(v>>8&0xFF)/255, float64(v&0xFF)/255
+	h, l, s := rgbToHLS(r, g, b)
+	if tint < 0 {
+		l *= 1 + tint
+	} else {
+		l = l*(1-tint) + tint
+	}
+	r, g, b = hlsToRGB(h, l, s)
+	return fmt.Sprintf("%02X%02X%02X", int(math.Round(r*255)), int(math.Round(g*255)), int(math.Round(b*255)))
+}
+
+func rgbToHLS(r, g, b float64) (h, l, s float64) {
+	max, min := math.Max(r, math.Max(g, b)), math.Min(r, math.Min(g, b))
+	l = (max + min) / 2
+	if max == min {
+		return 0, l, 0
+	}
+	d := max - min
+	if l > 0.5 {
+		s = d / (2 - max - min)
+	} else {
+		s = d / (max + min)
+	}
+	switch max {
+	case r:
+		h = (g - b) / d
+		if g < b {
+			h += 6
+		}
+	case g:
+		h = (b-r)/d + 2
+	default:
+		h = (r-g)/d + 4
+	}
+	return h / 6, l, s
+}
+
+func hlsToRGB(h, l, s float64) (r, g, b float64) {
+	if s == 0 {
+		return l, l, l
+	}
+	var q float64
+	if l < 0.5 {
+		q = l * (1 + s)
+	} else {
+		q = l + s - l*s
+	}
+	p := 2*l - q
+	hue := func(t float64) float64 {
+		switch {
+		case t < 0:
+			t++
+		case t > 1:
+			t--
+		}
+		switch {
+		case t < 1.0/6:
+			return p + (q-p)*6*t
+		case t < 0.5:
+			return q
+		case t < 2.0/3:
+			return p + (q-p)*(2.0/3-t)*6
+		}
+		return p
+	}
+	return hue(h + 1.0/3), hue(h), hue(h - 1.0/3)
+}
+
+// ResolvedColor returns the RGB color of the font, or color.Auto if the font
+// uses the automatic color.
+func (f Font) ResolvedColor() color.Color {
+	for _, fc := range f._fceef.FontChoice {
+		if fc.Color != nil {
+			return f._dfcab.ResolveColor(fc.Color)
+		}
+	}
+	return color.Auto
+}
+
+// ResolvedFgColor returns the RGB foreground color of the fill, or color.Auto
+// if it isn't set.
+func (f PatternFill) ResolvedFgColor() color.Color { return f._fdcg.ResolveColor(f._eaecg.FgColor) }
+
+// ResolvedBgColor returns the RGB background color of the fill, or color.Auto
+// if it isn't set.
+func (f PatternFill) ResolvedBgColor() color.Color { return f._fdcg.ResolveColor(f._eaecg.BgColor) }
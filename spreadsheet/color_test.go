@@ -0,0 +1,67 @@
+package spreadsheet
+
+import (
+	"testing"
+
+	"github.com/yaklabco/unioffice/v2"
+	"github.com/yaklabco/unioffice/v2/color"
+	"github.com/yaklabco/unioffice/v2/schema/soo/dml"
+	"github.com/yaklabco/unioffice/v2/schema/soo/sml"
+)
+
+func TestWorkbookResolveColor(t *testing.T) {
+	wb := New()
+	if got := wb.ResolveColor(&sml.CT_Color{IndexedAttr: unioffice.Uint32(22)}); *got.AsRGBString() != "c0c0c0" {
+		t.Errorf("expected indexed 22 to be c0c0c0, got %s", *got.AsRGBString())
+	}
+	if got := wb.ResolveColor(&sml.CT_Color{AutoAttr: unioffice.Bool(true)}); !got.IsAuto() {
+		t.Errorf("expected automatic color")
+	}
+	if got := wb.ResolveColor(nil); !got.IsAuto() {
+		t.Errorf("expected automatic color for nil")
+	}
+	if got := wb.ResolveColor(&sml.CT_Color{ThemeAttr: unioffice.Uint32(4)}); !got.IsAuto() {
+		t.Errorf("expected theme color to be unresolved without a theme")
+	}
+
+	theme := dml.NewTheme()
+	theme.ThemeElements.ClrScheme.Accent1.SrgbClr = dml.NewCT_SRgbColor()
+	theme.ThemeElements.ClrScheme.Accent1.SrgbClr.ValAttr = "4472C4"
+	wb._bgbc = append(wb._bgbc, theme)
+	got := wb.ResolveColor(&sml.CT_Color{ThemeAttr: unioffice.Uint32(4), TintAttr: unioffice.Float64(-0.25)})
+	if *got.AsRGBString() != "2f5597" {
+		t.Errorf("expected darker accent 1 2f5597, got %s", *got.AsRGBString())
+	}
+
+	// a custom palette replaces the default one
+	ss := wb.StyleSheet.X()
+	ss.Colors = sml.NewCT_Colors()
+	ss.Colors.IndexedColors = sml.NewCT_IndexedColors()
+	for _, rgb := range []string{"FF000000", "FF123456"} {
+		ss.Colors.IndexedColors.RgbColor = append(ss.Colors.IndexedColors.RgbColor, &sml.CT_RgbColor{RgbAttr: unioffice.String(rgb)})
+	}
+	if got := wb.ResolveColor(&sml.CT_Color{IndexedAttr: unioffice.Uint32(1)}); *got.AsRGBString() != "123456" {
+		t.Errorf("expected custom indexed color 123456, got %s", *got.AsRGBString())
+	}
+}
+
+func TestResolvedFontAndFillColors(t *testing.T) {
+	wb := New()
+	font := wb.StyleSheet.AddFont()
+	if !font.ResolvedColor().IsAuto() {
+		t.Errorf("expected automatic font color")
+	}
+	font.X().FontChoice = append(font.X().FontChoice, &sml.CT_FontChoice{Color: &sml.CT_Color{IndexedAttr: unioffice.Uint32(2), TintAttr: unioffice.Float64(-0.5)}})
+	if got := font.ResolvedColor(); *got.AsRGBString() != "800000" {
+		t.Errorf("expected font color 800000, got %s", *got.AsRGBString())
+	}
+
+	pf := wb.StyleSheet.Fills().AddFill().SetPatternFill()
+	pf.SetFgColor(color.Blue)
+	if got := pf.ResolvedFgColor(); *got.AsRGBString() != "0000ff" {
+		t.Errorf("expected fill color 0000ff, got %s", *got.AsRGBString())
+	}
+	if !pf.ResolvedBgColor().IsAuto() {
+		t.Errorf("expected automatic background color")
+	}
+}
@@ -0,0 +1,345 @@
+package spreadsheet
+
+import (
+	"encoding/xml"
+	"fmt"
+
+	unioffice "github.com/yaklabco/unioffice/v2"
+	"github.com/yaklabco/unioffice/v2/schema/soo/sml"
+)
+
+// styleKey returns a key identifying the content of a style element, equal
+// elements have equal keys.
+func styleKey(v interface{}) string {
+	b, err := xml.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%p", v)
+	}
+	return string(b)
+}
+
+// dedupIndexes returns for each element the index of the first element with
+// the same key.
+func dedupIndexes(n int, key func(i int) string) []uint32 {
+	seen := map[string]uint32{}
+	ret := make([]uint32, n)
+	for i := 0; i < n; i++ {
+		k := key(i)
+		if j, ok := seen[k]; ok {
+			ret[i] = j
+			continue
+		}
+		seen[k] = uint32(i)
+		ret[i] = uint32(i)
+	}
+	return ret
+}
+
+// remapID replaces an index with its mapped value, indexes out of range of the
+// mapping are reset to the default entry.
+func remapID(v *uint32, m []uint32) *uint32 {
+	if v == nil {
+		return nil
+	}
+	if int(*v) >= len(m) {
+		return unioffice.Uint32(0)
+	}
+	return unioffice.Uint32(m[*v])
+}
+
+// compaction keeps the used entries of a style list and maps the old indexes
+// to the new ones.
+type compaction struct {
+	used  []bool
+	remap []uint32
+}
+
+func newCompaction(n int, keep ...int) *compaction {
+	c := &compaction{used: make([]bool, n), remap: make([]uint32, n)}
+	for _, k := range keep {
+		if k < n {
+			c.used[k] = true
+		}
+	}
+	return c
+}
+
+func (c *compaction) use(v *uint32) {
+	if v != nil && int(*v) < len(c.used) {
+		c.used[*v] = true
+	}
+}
+
+// compute assigns the new indexes and returns the indexes of the entries kept
+// in order.
+func (c *compaction) compute() []int {
+	kept := []int{}
+	for i, u := range c.used {
+		if u {
+			c.remap[i] = uint32(len(kept))
+			kept = append(kept, i)
+		}
+	}
+	return kept
+}
+
+// styleXfs returns the cell formats and the named style formats of the style
+// sheet, each format once.
+func styleXfs(ss *sml.StyleSheet) []*sml.CT_Xf {
+	ret := []*sml.CT_Xf{}
+	seen := map[*sml.CT_Xf]bool{}
+	add := func(xfs []*sml.CT_Xf) {
+		for _, xf := range xfs {
+			if !seen[xf] {
+				seen[xf] = true
+				ret = append(ret, xf)
+			}
+		}
+	}
+	if ss.CellXfs != nil {
+		add(ss.CellXfs.Xf)
+	}
+	if ss.CellStyleXfs != nil {
+		add(ss.CellStyleXfs.Xf)
+	}
+	return ret
+}
+
+// keepFirst keeps the entries of a deduplication mapping that are mapped to
+// themselves and returns their new indexes.
+func keepFirst(m []uint32) *compaction {
+	c := newCompaction(len(m))
+	for i, j := range m {
+		c.used[i] = uint32(i) == j
+	}
+	c.compute()
+	for i, j := range m {
+		c.remap[i] = c.remap[j]
+	}
+	return c
+}
+
+// dedupStyles merges the equal fonts, fills, borders, number formats and cell
+// formats of the style sheet, updating the references to them, so styles
+// created or modified separately with the same content are written once.
+func (wb *Workbook) dedupStyles() {
+	ss := wb.StyleSheet._gccd
+	if ss == nil || ss.CellXfs == nil {
+		return
+	}
+	allXfs := styleXfs(ss)
+	if ss.Fonts != nil {
+		fonts := ss.Fonts.Font
+		c := keepFirst(dedupIndexes(len(fonts), func(i int) string { return styleKey(fonts[i]) }))
+		ss.Fonts.Font = keptEntries(fonts, c)
+		ss.Fonts.CountAttr = unioffice.Uint32(uint32(len(ss.Fonts.Font)))
+		for _, xf := range allXfs {
+			xf.FontIdAttr = remapID(xf.FontIdAttr, c.remap)
+		}
+	}
+	if ss.Fills != nil {
+		fills := ss.Fills.Fill
+		c := keepFirst(dedupIndexes(len(fills), func(i int) string { return styleKey(fills[i]) }))
+		ss.Fills.Fill = keptEntries(fills, c)
+		ss.Fills.CountAttr = unioffice.Uint32(uint32(len(ss.Fills.Fill)))
+		for _, xf := range allXfs {
+			xf.FillIdAttr = remapID(xf.FillIdAttr, c.remap)
+		}
+	}
+	if ss.Borders != nil {
+		borders := ss.Borders.Border
+		c := keepFirst(dedupIndexes(len(borders), func(i int) string { return styleKey(borders[i]) }))
+		ss.Borders.Border = keptEntries(borders, c)
+		ss.Borders.CountAttr = unioffice.Uint32(uint32(len(ss.Borders.Border)))
+		for _, xf := range allXfs {
+			xf.BorderIdAttr = remapID(xf.BorderIdAttr, c.remap)
+		}
+	}
+	if ss.NumFmts != nil {
+		numFmtMap := map[uint32]uint32{}
+		byCode := map[string]uint32{}
+		for _, nf := range ss.NumFmts.NumFmt {
+			if id, ok := builtinNumberFormatID(nf.FormatCodeAttr); ok {
+				numFmtMap[nf.NumFmtIdAttr] = id
+			} else if id, ok := byCode[nf.FormatCodeAttr]; ok {
+				numFmtMap[nf.NumFmtIdAttr] = id
+			} else {
+				byCode[nf.FormatCodeAttr] = nf.NumFmtIdAttr
+			}
+		}
+		// formats used by differential formats are kept
+		if ss.Dxfs != nil {
+			for _, dxf := range ss.Dxfs.Dxf {
+				if dxf.NumFmt != nil {
+					delete(numFmtMap, dxf.NumFmt.NumFmtIdAttr)
+				}
+			}
+		}
+		kept := []*sml.CT_NumFmt{}
+		for _, nf := range ss.NumFmts.NumFmt {
+			if _, merged := numFmtMap[nf.NumFmtIdAttr]; !merged {
+				kept = append(kept, nf)
+			}
+		}
+		ss.NumFmts.NumFmt = kept
+		ss.NumFmts.CountAttr = unioffice.Uint32(uint32(len(kept)))
+		for _, xf := range allXfs {
+			if xf.NumFmtIdAttr != nil {
+				if id, ok := numFmtMap[*xf.NumFmtIdAttr]; ok {
+					xf.NumFmtIdAttr = unioffice.Uint32(id)
+				}
+			}
+		}
+	}
+
+	cellXfs := ss.CellXfs.Xf
+	c := keepFirst(dedupIndexes(len(cellXfs), func(i int) string { return styleKey(cellXfs[i]) }))
+	ss.CellXfs.Xf = keptEntries(cellXfs, c)
+	ss.CellXfs.CountAttr = unioffice.Uint32(uint32(len(ss.CellXfs.Xf)))
+	for _, ws := range wb._fbef {
+		forEachStyleRef(ws, func(v *uint32) *uint32 { return remapID(v, c.remap) })
+	}
+}
+
+// keptEntries returns the entries of a style list kept by a compaction.
+func keptEntries[T any](entries []T, c *compaction) []T {
+	ret := make([]T, 0, len(entries))
+	for i, e := range entries {
+		if c.used[i] {
+			ret = append(ret, e)
+		}
+	}
+	return ret
+}
+
+// CompactStyles removes duplicate and unused fonts, fills, borders, number
+// formats, cell formats and named style formats from the style sheet and
+// updates the style references of cells, rows and columns. The default entries
+// and the formats of named cell styles are always kept. The indexes of the
+// cell styles change, so it's best called once the styles are applied, e.g.
+// before saving.
+func (wb *Workbook) CompactStyles() {
+	ss := wb.StyleSheet._gccd
+	if ss == nil || ss.CellXfs == nil {
+		return
+	}
+	wb.dedupStyles()
+
+	// drop the cell formats no cell, row or column uses
+	cellXfs := ss.CellXfs.Xf
+	xfs := newCompaction(len(cellXfs), 0)
+	for _, ws := range wb._fbef {
+		forEachStyleRef(ws, func(v *uint32) *uint32 {
+			xfs.use(v)
+			return v
+		})
+	}
+	xfs.compute()
+	for _, ws := range wb._fbef {
+		forEachStyleRef(ws, func(v *uint32) *uint32 { return remapID(v, xfs.remap) })
+	}
+	ss.CellXfs.Xf = keptEntries(cellXfs, xfs)
+	ss.CellXfs.CountAttr = unioffice.Uint32(uint32(len(ss.CellXfs.Xf)))
+
+	// named style formats are kept while a named style or cell format uses them
+	if ss.CellStyleXfs != nil {
+		sxfs := newCompaction(len(ss.CellStyleXfs.Xf), 0)
+		if ss.CellStyles != nil {
+			for _, cs := range ss.CellStyles.CellStyle {
+				sxfs.use(&cs.XfIdAttr)
+			}
+		}
+		for _, xf := range ss.CellXfs.Xf {
+			sxfs.use(xf.XfIdAttr)
+		}
+		sxfs.compute()
+		ss.CellStyleXfs.Xf = keptEntries(ss.CellStyleXfs.Xf, sxfs)
+		ss.CellStyleXfs.CountAttr = unioffice.Uint32(uint32(len(ss.CellStyleXfs.Xf)))
+		for _, xf := range ss.CellXfs.Xf {
+			xf.XfIdAttr = remapID(xf.XfIdAttr, sxfs.remap)
+		}
+		if ss.CellStyles != nil {
+			for _, cs := range ss.CellStyles.CellStyle {
+				cs.XfIdAttr = *remapID(&cs.XfIdAttr, sxfs.remap)
+			}
+		}
+	}
+
+	// drop the fonts, fills, borders and number formats that no format uses
+	allXfs := styleXfs(ss)
+	fontC, fillC, borderC := newCompaction(0), newCompaction(0), newCompaction(0)
+	if ss.Fonts != nil {
+		fontC = newCompaction(len(ss.Fonts.Font), 0)
+	}
+	if ss.Fills != nil {
+		fillC = newCompaction(len(ss.Fills.Fill), 0, 1)
+	}
+	if ss.Borders != nil {
+		borderC = newCompaction(len(ss.Borders.Border), 0)
+	}
+	usedNumFmts := map[uint32]bool{}
+	for _, xf := range allXfs {
+		fontC.use(xf.FontIdAttr)
+		fillC.use(xf.FillIdAttr)
+		borderC.use(xf.BorderIdAttr)
+		if xf.NumFmtIdAttr != nil {
+			usedNumFmts[*xf.NumFmtIdAttr] = true
+		}
+	}
+	if ss.Dxfs != nil {
+		for _, dxf := range ss.Dxfs.Dxf {
+			if dxf.NumFmt != nil {
+				usedNumFmts[dxf.NumFmt.NumFmtIdAttr] = true
+			}
+		}
+	}
+	fontC.compute()
+	fillC.compute()
+	borderC.compute()
+	if ss.Fonts != nil {
+		ss.Fonts.Font = keptEntries(ss.Fonts.Font, fontC)
+		ss.Fonts.CountAttr = unioffice.Uint32(uint32(len(ss.Fonts.Font)))
+	}
+	if ss.Fills != nil {
+		ss.Fills.Fill = keptEntries(ss.Fills.Fill, fillC)
+		ss.Fills.CountAttr = unioffice.Uint32(uint32(len(ss.Fills.Fill)))
+	}
+	if ss.Borders != nil {
+		ss.Borders.Border = keptEntries(ss.Borders.Border, borderC)
+		ss.Borders.CountAttr = unioffice.Uint32(uint32(len(ss.Borders.Border)))
+	}
+	for _, xf := range allXfs {
+		xf.FontIdAttr = remapID(xf.FontIdAttr, fontC.remap)
+		xf.FillIdAttr = remapID(xf.FillIdAttr, fillC.remap)
+		xf.BorderIdAttr = remapID(xf.BorderIdAttr, borderC.remap)
+	}
+	if ss.NumFmts != nil {
+		kept := ss.NumFmts.NumFmt[:0]
+		for _, nf := range ss.NumFmts.NumFmt {
+			if usedNumFmts[nf.NumFmtIdAttr] {
+				kept = append(kept, nf)
+			}
+		}
+		ss.NumFmts.NumFmt = kept
+		ss.NumFmts.CountAttr = unioffice.Uint32(uint32(len(kept)))
+	}
+}
+
+// forEachStyleRef replaces each cell format index used by the cells, rows and
+// columns of a worksheet with the value returned by fn.
+func forEachStyleRef(ws *sml.Worksheet, fn func(v *uint32) *uint32) {
+	for _, cols := range ws.Cols {
+		for _, c := range cols.Col {
+			c.StyleAttr = fn(c.StyleAttr)
+		}
+	}
+	if ws.SheetData == nil {
+		return
+	}
+	for _, r := range ws.SheetData.Row {
+		r.SAttr = fn(r.SAttr)
+		for _, c := range r.C {
+			c.SAttr = fn(c.SAttr)
+		}
+	}
+}
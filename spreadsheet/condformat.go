@@ -0,0 +1,482 @@
+package spreadsheet
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yaklabco/unioffice/v2/schema/soo/sml"
+	"github.com/yaklabco/unioffice/v2/spreadsheet/formula"
+	"github.com/yaklabco/unioffice/v2/spreadsheet/reference"
+)
+
+// cfStyle is the formatting conditional formats apply to a cell.
+type cfStyle struct {
+	// dxfs are the differential styles of the matching rules, highest
+	// priority first.
+	dxfs []*sml.CT_Dxf
+	// background is the color from a color scale.
+	background string
+	// bar is the data bar length as a fraction of the cell width.
+	bar      float64
+	barColor string
+	stopped  bool
+}
+
+// cfCell is a cell covered by a conditional format.
+type cfCell struct {
+	ref      string
+	col, row uint32
+	cell     Cell
+	num      float64
+	isNum    bool
+	str      string
+}
+
+// conditionalStyles evaluates the conditional formats of the sheet over the
+// cells between columns c1 to c2 and rows r1 to r2, returning the formatting
+// of each cell a rule applies to keyed by cell reference. Rules comparing
+// cells, e.g. top 10 or color scales, still take the values of their whole
+// range into account. Icon sets and time period rules are not evaluated.
+func (s *Sheet) conditionalStyles(c1, r1, c2, r2 uint32) map[string]*cfStyle {
+	type cfRule struct {
+		rule  *sml.CT_CfRule
+		cells []cfCell
+		all   []cfCell
+		col   uint32
+		row   uint32
+	}
+	index := s.cfIndex()
+	rules := []cfRule{}
+	for _, cf := range s.X().ConditionalFormatting {
+		if cf.SqrefAttr == nil {
+			continue
+		}
+		cells, all, col, row := s.cfCells(*cf.SqrefAttr, index, c1, r1, c2, r2)
+		for _, r := range cf.CfRule {
+			rules = append(rules, cfRule{r, cells, all, col, row})
+		}
+	}
+	sort.SliceStable(rules, func(i, j int) bool {
+		return rules[i].rule.PriorityAttr < rules[j].rule.PriorityAttr
+	})
+
+	styles := map[string]*cfStyle{}
+	dxfs := []*sml.CT_Dxf{}
+	if ss := s._fgeg.StyleSheet.X(); ss.Dxfs != nil {
+		dxfs = ss.Dxfs.Dxf
+	}
+	for _, r := range rules {
+		matches := s.cfMatches(r.rule, r.cells, r.all, r.col, r.row)
+		for i, c := range r.cells {
+			st := styles[c.ref]
+			if st != nil && st.stopped {
+				continue
+			}
+			switch r.rule.TypeAttr {
+			case sml.ST_CfTypeColorScale, sml.ST_CfTypeDataBar:
+				continue
+			}
+			if !matches[i] {
+				continue
+			}
+			if st == nil {
+				st = &cfStyle{}
+				styles[c.ref] = st
+			}
+			if id := r.rule.DxfIdAttr; id != nil && int(*id) < len(dxfs) {
+				st.dxfs = append(st.dxfs, dxfs[*id])
+			}
+			st.stopped = r.rule.StopIfTrueAttr != nil && *r.rule.StopIfTrueAttr
+		}
+		switch {
+		case r.rule.ColorScale != nil:
+			s.applyColorScale(r.rule.ColorScale, r.cells, r.all, styles)
+		case r.rule.DataBar != nil:
+			s.applyDataBar(r.rule.DataBar, r.cells, r.all, styles)
+		}
+	}
+	return styles
+}
+
+// cfIndex returns the cells of the sheet keyed by column and row.
+func (s *Sheet) cfIndex() map[[2]uint32]Cell {
+	index := map[[2]uint32]Cell{}
+	for _, r := range s.X().SheetData.Row {
+		for _, c := range r.C {
+			if c.RAttr == nil {
+				continue
+			}
+			cr, err := reference.ParseCellReference(strings.ReplaceAll(*c.RAttr, "$", ""))
+			if err != nil {
+				continue
+			}
+			index[[2]uint32{cr.ColumnIdx, cr.RowIdx}] = Cell{s._fgeg, s, r, c}
+		}
+	}
+	return index
+}
+
+// cfCells returns the cells of a conditional format range between columns c1
+// to c2 and rows r1 to r2, the existing cells of the whole range and its top
+// left cell, which relative references in the rule formulas refer to.
+func (s *Sheet) cfCells(sqref sml.ST_Sqref, index map[[2]uint32]Cell, c1, r1, c2, r2 uint32) ([]cfCell, []cfCell, uint32, uint32) {
+	type area struct{ from, to reference.CellReference }
+	areas := []area{}
+	for _, ref := range sqref {
+		from, to, err := reference.ParseRangeReference(ref)
+		if err != nil {
+			cr, cerr := reference.ParseCellReference(ref)
+			if cerr != nil {
+				continue
+			}
+			from, to = cr, cr
+		}
+		areas = append(areas, area{from, to})
+	}
+	var col, row uint32
+	if len(areas) > 0 {
+		col, row = areas[0].from.ColumnIdx, areas[0].from.RowIdx
+	}
+
+	cells := []cfCell{}
+	for _, a := range areas {
+		for r := maxUint32(a.from.RowIdx, r1); r <= minUint32(a.to.RowIdx, r2); r++ {
+			for c := maxUint32(a.from.ColumnIdx, c1); c <= minUint32(a.to.ColumnIdx, c2); c++ {
+				cell, ok := index[[2]uint32{c, r}]
+				cells = append(cells, makeCfCell(c, r, cell, ok))
+			}
+		}
+	}
+	all := []cfCell{}
+	for k, cell := range index {
+		for _, a := range areas {
+			if k[0] >= a.from.ColumnIdx && k[0] <= a.to.ColumnIdx && k[1] >= a.from.RowIdx && k[1] <= a.to.RowIdx {
+				all = append(all, makeCfCell(k[0], k[1], cell, true))
+				break
+			}
+		}
+	}
+	return cells, all, col, row
+}
+
+// makeCfCell returns the conditional format value of the cell at column c and
+// row r, ok being false for missing cells.
+func makeCfCell(c, r uint32, cell Cell, ok bool) cfCell {
+	v := cfCell{ref: reference.IndexToColumn(c) + strconv.Itoa(int(r)), col: c, row: r, cell: cell}
+	if ok {
+		v.str = cell.GetString()
+		if cell.X().TAttr != sml.ST_CellTypeS && cell.X().TAttr != sml.ST_CellTypeStr &&
+			cell.X().TAttr != sml.ST_CellTypeInlineStr && cell.X().TAttr != sml.ST_CellTypeE {
+			if n, err := cell.GetValueAsNumber(); err == nil {
+				v.num, v.isNum = n, true
+			}
+		}
+	}
+	return v
+}
+
+// cfEval evaluates a rule formula for a cell, shifting its relative
+// references by the cell's offset from the top left cell of the range.
+func (s *Sheet) cfEval(f string, c cfCell, col, row uint32) formula.Result {
+	ctx := s.FormulaContext()
+	if c.col >= col && c.row >= row {
+		ctx.SetOffset(c.col-col, c.row-row)
+	}
+	return formula.NewEvaluator().Eval(ctx, f)
+}
+
+func cfTruthy(r formula.Result) bool {
+	switch r.Type {
+	case formula.ResultTypeNumber:
+		return r.ValueNumber != 0
+	case formula.ResultTypeString:
+		return strings.EqualFold(r.ValueString, "TRUE")
+	}
+	return false
+}
+
+// cfCompare compares a cell value to a formula result, returning -1, 0 or 1.
+// Numbers sort before text, text is compared case insensitively.
+func cfCompare(c cfCell, r formula.Result) int {
+	if r.Type == formula.ResultTypeNumber {
+		if !c.isNum && c.str != "" {
+			return 1
+		}
+		switch {
+		case c.num < r.ValueNumber:
+			return -1
+		case c.num > r.ValueNumber:
+			return 1
+		}
+		return 0
+	}
+	if c.isNum {
+		return -1
+	}
+	return strings.Compare(strings.ToLower(c.str), strings.ToLower(r.Value()))
+}
+
+// cfMatches returns whether each of the cells matches a rule, rules comparing
+// cells to each other taking all the cells of the range into account.
+func (s *Sheet) cfMatches(rule *sml.CT_CfRule, cells, all []cfCell, col, row uint32) []bool {
+	matches := make([]bool, len(cells))
+	text := ""
+	if rule.TextAttr != nil {
+		text = strings.ToLower(*rule.TextAttr)
+	}
+	switch rule.TypeAttr {
+	case sml.ST_CfTypeDuplicateValues, sml.ST_CfTypeUniqueValues:
+		counts := map[string]int{}
+		for _, c := range all {
+			if c.str != "" {
+				counts[strings.ToLower(c.str)]++
+			}
+		}
+		for i, c := range cells {
+			n := counts[strings.ToLower(c.str)]
+			matches[i] = c.str != "" && (n > 1) == (rule.TypeAttr == sml.ST_CfTypeDuplicateValues)
+		}
+		return matches
+	case sml.ST_CfTypeTop10:
+		nums := []float64{}
+		for _, c := range all {
+			if c.isNum {
+				nums = append(nums, c.num)
+			}
+		}
+		if len(nums) == 0 {
+			return matches
+		}
+		bottom := rule.BottomAttr != nil && *rule.BottomAttr
+		sort.Float64s(nums)
+		if !bottom {
+			sort.Sort(sort.Reverse(sort.Float64Slice(nums)))
+		}
+		rank := 10
+		if rule.RankAttr != nil {
+			rank = int(*rule.RankAttr)
+		}
+		if rule.PercentAttr != nil && *rule.PercentAttr {
+			rank = int(float64(len(nums)) * float64(rank) / 100)
+		}
+		if rank < 1 {
+			rank = 1
+		}
+		if rank > len(nums) {
+			rank = len(nums)
+		}
+		limit := nums[rank-1]
+		for i, c := range cells {
+			matches[i] = c.isNum && ((!bottom && c.num >= limit) || (bottom && c.num <= limit))
+		}
+		return matches
+	case sml.ST_CfTypeAboveAverage:
+		sum, n := 0.0, 0
+		for _, c := range all {
+			if c.isNum {
+				sum += c.num
+				n++
+			}
+		}
+		if n == 0 {
+			return matches
+		}
+		avg := sum / float64(n)
+		above := rule.AboveAverageAttr == nil || *rule.AboveAverageAttr
+		equal := rule.EqualAverageAttr != nil && *rule.EqualAverageAttr
+		for i, c := range cells {
+			switch {
+			case !c.isNum:
+			case equal && c.num == avg:
+				matches[i] = true
+			case above:
+				matches[i] = c.num > avg
+			default:
+				matches[i] = c.num < avg
+			}
+		}
+		return matches
+	}
+
+	for i, c := range cells {
+		switch rule.TypeAttr {
+		case sml.ST_CfTypeCellIs:
+			if len(rule.Formula) == 0 {
+				continue
+			}
+			v1 := cfCompare(c, s.cfEval(rule.Formula[0], c, col, row))
+			v2 := 0
+			if len(rule.Formula) > 1 {
+				v2 = cfCompare(c, s.cfEval(rule.Formula[1], c, col, row))
+			}
+			switch rule.OperatorAttr {
+			case sml.ST_ConditionalFormattingOperatorLessThan:
+				matches[i] = v1 < 0
+			case sml.ST_ConditionalFormattingOperatorLessThanOrEqual:
+				matches[i] = v1 <= 0
+			case sml.ST_ConditionalFormattingOperatorEqual:
+				matches[i] = v1 == 0
+			case sml.ST_ConditionalFormattingOperatorNotEqual:
+				matches[i] = v1 != 0
+			case sml.ST_ConditionalFormattingOperatorGreaterThanOrEqual:
+				matches[i] = v1 >= 0
+			case sml.ST_ConditionalFormattingOperatorGreaterThan:
+				matches[i] = v1 > 0
+			case sml.ST_ConditionalFormattingOperatorBetween:
+				matches[i] = len(rule.Formula) > 1 && v1 >= 0 && v2 <= 0
+			case sml.ST_ConditionalFormattingOperatorNotBetween:
+				matches[i] = len(rule.Formula) > 1 && (v1 < 0 || v2 > 0)
+			}
+		case sml.ST_CfTypeContainsBlanks:
+			matches[i] = strings.TrimSpace(c.str) == ""
+		case sml.ST_CfTypeNotContainsBlanks:
+			matches[i] = strings.TrimSpace(c.str) != ""
+		case sml.ST_CfTypeContainsErrors, sml.ST_CfTypeNotContainsErrors:
+			isErr := c.cell.X() != nil && c.cell.X().TAttr == sml.ST_CellTypeE
+			matches[i] = isErr == (rule.TypeAttr == sml.ST_CfTypeContainsErrors)
+		case sml.ST_CfTypeContainsText:
+			matches[i] = strings.Contains(strings.ToLower(c.str), text)
+		case sml.ST_CfTypeNotContainsText:
+			matches[i] = !strings.Contains(strings.ToLower(c.str), text)
+		case sml.ST_CfTypeBeginsWith:
+			matches[i] = strings.HasPrefix(strings.ToLower(c.str), text)
+		case sml.ST_CfTypeEndsWith:
+			matches[i] = strings.HasSuffix(strings.ToLower(c.str), text)
+		case sml.ST_CfTypeExpression:
+			matches[i] = len(rule.Formula) > 0 && cfTruthy(s.cfEval(rule.Formula[0], c, col, row))
+		}
+	}
+	return matches
+}
+
+// cfvoValue returns the number a color scale or data bar threshold refers to.
+func (s *Sheet) cfvoValue(v *sml.CT_Cfvo, nums []float64) float64 {
+	min, max := nums[0], nums[len(nums)-1]
+	val := 0.0
+	if v.ValAttr != nil {
+		val, _ = strconv.ParseFloat(*v.ValAttr, 64)
+	}
+	switch v.TypeAttr {
+	case sml.ST_CfvoTypeMin:
+		return min
+	case sml.ST_CfvoTypeMax:
+		return max
+	case sml.ST_CfvoTypePercent:
+		return min + (max-min)*val/100
+	case sml.ST_CfvoTypePercentile:
+		pos := val / 100 * float64(len(nums)-1)
+		lo := int(math.Floor(pos))
+		if lo >= len(nums)-1 {
+			return max
+		}
+		return nums[lo] + (nums[lo+1]-nums[lo])*(pos-float64(lo))
+	case sml.ST_CfvoTypeFormula:
+		if v.ValAttr != nil {
+			if r := formula.NewEvaluator().Eval(s.FormulaContext(), *v.ValAttr); r.Type == formula.ResultTypeNumber {
+				return r.ValueNumber
+			}
+		}
+	}
+	return val
+}
+
+func cfNumbers(cells []cfCell) []float64 {
+	nums := []float64{}
+	for _, c := range cells {
+		if c.isNum {
+			nums = append(nums, c.num)
+		}
+	}
+	sort.Float64s(nums)
+	return nums
+}
+
+func (s *Sheet) applyColorScale(cs *sml.CT_ColorScale, cells, all []cfCell, styles map[string]*cfStyle) {
+	nums := cfNumbers(all)
+	if len(nums) == 0 || len(cs.Cfvo) < 2 || len(cs.Color) < len(cs.Cfvo) {
+		return
+	}
+	stops := make([]float64, len(cs.Cfvo))
+	colors := make([][3]float64, len(cs.Cfvo))
+	for i, v := range cs.Cfvo {
+		stops[i] = s.cfvoValue(v, nums)
+		rgb, ok := s._fgeg.resolveColor(cs.Color[i])
+		if !ok {
+			return
+		}
+		n, _ := strconv.ParseUint(rgb, 16, 32)
+		colors[i] = [3]float64{float64(n >> 16 & 0xFF), float64(n >> 8 & 0xFF), float64(n & 0xFF)}
+	}
+	for _, c := range cells {
+		if !c.isNum {
+			continue
+		}
+		if st := styles[c.ref]; st != nil && st.stopped {
+			continue
+		}
+		i := 0
+		for i < len(stops)-2 && c.num > stops[i+1] {
+			i++
+		}
+		t := 0.0
+		if stops[i+1] != stops[i] {
+			t = (c.num - stops[i]) / (stops[i+1] - stops[i])
+		}
+		t = math.Max(0, math.Min(1, t))
+		var rgb [3]int
+		for k := range rgb {
+			rgb[k] = int(math.Round(colors[i][k] + (colors[i+1][k]-colors[i][k])*t))
+		}
+		st := styles[c.ref]
+		if st == nil {
+			st = &cfStyle{}
+			styles[c.ref] = st
+		}
+		if st.background == "" {
+			st.background = fmt.Sprintf("%02X%02X%02X", rgb[0], rgb[1], rgb[2])
+		}
+	}
+}
+
+func (s *Sheet) applyDataBar(db *sml.CT_DataBar, cells, all []cfCell, styles map[string]*cfStyle) {
+	nums := cfNumbers(all)
+	if len(nums) == 0 || len(db.Cfvo) < 2 {
+		return
+	}
+	lo, hi := s.cfvoValue(db.Cfvo[0], nums), s.cfvoValue(db.Cfvo[1], nums)
+	minLen, maxLen := 0.1, 0.9
+	if db.MinLengthAttr != nil {
+		minLen = float64(*db.MinLengthAttr) / 100
+	}
+	if db.MaxLengthAttr != nil {
+		maxLen = float64(*db.MaxLengthAttr) / 100
+	}
+	color, ok := s._fgeg.resolveColor(db.Color)
+	if !ok {
+		color = "638EC6"
+	}
+	for _, c := range cells {
+		if !c.isNum {
+			continue
+		}
+		st := styles[c.ref]
+		if st != nil && st.stopped {
+			continue
+		}
+		t := 1.0
+		if hi != lo {
+			t = math.Max(0, math.Min(1, (c.num-lo)/(hi-lo)))
+		}
+		if st == nil {
+			st = &cfStyle{}
+			styles[c.ref] = st
+		}
+		if st.bar == 0 {
+			st.bar = minLen + (maxLen-minLen)*t
+			st.barColor = color
+		}
+	}
+}
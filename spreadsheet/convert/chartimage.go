@@ -0,0 +1,857 @@
+package convert
+
+import (
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/yaklabco/unioffice/v2/chart"
+	"github.com/yaklabco/unioffice/v2/schema/soo/dml"
+	crt "github.com/yaklabco/unioffice/v2/schema/soo/dml/chart"
+)
+
+// chartPalette are the default series colors of the Office theme.
+var chartPalette = []color.RGBA{
+	{0x44, 0x72, 0xc4, 0xff}, {0xed, 0x7d, 0x31, 0xff}, {0xa5, 0xa5, 0xa5, 0xff},
+	{0xff, 0xc0, 0x00, 0xff}, {0x5b, 0x9b, 0xd5, 0xff}, {0x70, 0xad, 0x47, 0xff},
+}
+
+var (
+	chartTextColor = color.RGBA{0x59, 0x59, 0x59, 0xff}
+	chartLineColor = color.RGBA{0xd9, 0xd9, 0xd9, 0xff}
+	chartFont      = textFace{family: "Calibri", size: 9}
+	chartTitleFont = textFace{family: "Calibri", size: 14}
+)
+
+type chartKind byte
+
+const (
+	chartBar chartKind = iota
+	chartColumn
+	chartLine
+	chartArea
+	chartPie
+	chartDoughnut
+	chartScatter
+)
+
+// chartSeries are the values of a series from the caches of the chart.
+type chartSeries struct {
+	name   string
+	cats   []string
+	xs     []float64
+	vals   []float64
+	color  color.RGBA
+	points map[int]color.RGBA
+}
+
+// chartGroup is a chart of the plot area and its series.
+type chartGroup struct {
+	kind    chartKind
+	stacked bool
+	percent bool
+	lines   bool
+	series  []chartSeries
+}
+
+// drawChart draws a chart from the values cached in it. Bar, line, area,
+// pie, doughnut and scatter charts are drawn flat, other chart types only
+// draw their frame and title.
+func drawChart(p painter, r rect, ch chart.Chart) {
+	p.fillRect(r, white)
+	outline(p, r, chartLineColor)
+	cs := ch.X()
+	if cs == nil || cs.Chart == nil {
+		return
+	}
+	c := cs.Chart
+	groups := chartGroups(c)
+	inner := rect{r.x + 8, r.y + 8, r.w - 16, r.h - 16}
+
+	title := chartTitle(c, groups)
+	if title != "" {
+		m := p.fonts().face(chartTitleFont, 96).Metrics()
+		h := float64(m.Height) / 64
+		w := p.fonts().measure(chartTitleFont, title)
+		p.text(point{inner.x + (inner.w-w)/2, inner.y + float64(m.Ascent)/64}, title, chartTitleFont, chartTextColor, r)
+		inner.y += h + 4
+		inner.h -= h + 4
+	}
+	if len(groups) == 0 {
+		return
+	}
+
+	if c.Legend != nil {
+		inner = drawLegend(p, r, inner, c.Legend, groups)
+	}
+	switch groups[0].kind {
+	case chartPie, chartDoughnut:
+		drawPie(p, inner, groups[0])
+	default:
+		drawAxes(p, inner, groups)
+	}
+}
+
+func outline(p painter, r rect, c color.RGBA) {
+	p.line(point{r.x, r.y + 0.5}, point{r.x + r.w, r.y + 0.5}, 1, nil, c)
+	p.line(point{r.x, r.y + r.h - 0.5}, point{r.x + r.w, r.y + r.h - 0.5}, 1, nil, c)
+	p.line(point{r.x + 0.5, r.y}, point{r.x + 0.5, r.y + r.h}, 1, nil, c)
+	p.line(point{r.x + r.w - 0.5, r.y}, point{r.x + r.w - 0.5, r.y + r.h}, 1, nil, c)
+}
+
+// chartTitle returns the text of the title of a chart. Charts with a title
+// element but no text are titled by the name of their only series.
+func chartTitle(c *crt.CT_Chart, groups []chartGroup) string {
+	if c.Title == nil {
+		return ""
+	}
+	if tx := c.Title.Tx; tx != nil && tx.TxChoice != nil {
+		switch {
+		case tx.TxChoice.Rich != nil:
+			return richText(tx.TxChoice.Rich)
+		case tx.TxChoice.StrRef != nil:
+			return strCache(tx.TxChoice.StrRef.StrCache, " ")
+		}
+	}
+	if len(groups) == 1 && len(groups[0].series) == 1 {
+		return groups[0].series[0].name
+	}
+	return "Chart Title"
+}
+
+// richText returns the text of a text body, one line per paragraph.
+func richText(tb *dml.CT_TextBody) string {
+	lines := []string{}
+	for _, p := range tb.P {
+		sb := strings.Builder{}
+		for _, r := range p.EG_TextRun {
+			if r.TextRunChoice != nil && r.TextRunChoice.R != nil {
+				sb.WriteString(r.TextRunChoice.R.T)
+			}
+		}
+		lines = append(lines, sb.String())
+	}
+	return strings.Join(lines, " ")
+}
+
+// strCache returns the cached strings of a reference joined by sep.
+func strCache(d *crt.CT_StrData, sep string) string {
+	if d == nil {
+		return ""
+	}
+	s := []string{}
+	for _, pt := range d.Pt {
+		s = append(s, pt.V)
+	}
+	return strings.Join(s, sep)
+}
+
+// chartGroups returns the charts of the plot area with the values of their
+// series.
+func chartGroups(c *crt.CT_Chart) []chartGroup {
+	if c.PlotArea == nil {
+		return nil
+	}
+	groups := []chartGroup{}
+	idx := 0
+	add := func(g chartGroup, sers []series) {
+		for _, s := range sers {
+			cs := chartSeries{name: serName(s.tx), color: chartPalette[idx%len(chartPalette)], points: map[int]color.RGBA{}}
+			if c, ok := shapeColor(s.spPr, g.kind == chartLine || (g.kind == chartScatter && g.lines)); ok {
+				cs.color = c
+			}
+			for _, dpt := range s.dPt {
+				if dpt.Idx == nil {
+					continue
+				}
+				if c, ok := shapeColor(dpt.SpPr, false); ok {
+					cs.points[int(dpt.Idx.ValAttr)] = c
+				}
+			}
+			cs.cats, cs.xs = axData(s.cat)
+			cs.vals = numData(s.val)
+			g.series = append(g.series, cs)
+			idx++
+		}
+		if len(g.series) > 0 {
+			groups = append(groups, g)
+		}
+	}
+	for _, pc := range c.PlotArea.PlotAreaChoice {
+		switch {
+		case pc.BarChart != nil:
+			add(barGroup(pc.BarChart.BarDir, pc.BarChart.Grouping), barSeries(pc.BarChart.Ser))
+		case pc.Bar3DChart != nil:
+			add(barGroup(pc.Bar3DChart.BarDir, pc.Bar3DChart.Grouping), barSeries(pc.Bar3DChart.Ser))
+		case pc.LineChart != nil:
+			add(lineGroup(chartLine, pc.LineChart.Grouping), lineSeries(pc.LineChart.Ser))
+		case pc.Line3DChart != nil:
+			add(lineGroup(chartLine, pc.Line3DChart.Grouping), lineSeries(pc.Line3DChart.Ser))
+		case pc.StockChart != nil:
+			add(chartGroup{kind: chartLine}, lineSeries(pc.StockChart.Ser))
+		case pc.AreaChart != nil:
+			add(lineGroup(chartArea, pc.AreaChart.Grouping), areaSeries(pc.AreaChart.Ser))
+		case pc.Area3DChart != nil:
+			add(lineGroup(chartArea, pc.Area3DChart.Grouping), areaSeries(pc.Area3DChart.Ser))
+		case pc.PieChart != nil:
+			add(chartGroup{kind: chartPie}, pieSeries(pc.PieChart.Ser))
+		case pc.Pie3DChart != nil:
+			add(chartGroup{kind: chartPie}, pieSeries(pc.Pie3DChart.Ser))
+		case pc.OfPieChart != nil:
+			add(chartGroup{kind: chartPie}, pieSeries(pc.OfPieChart.Ser))
+		case pc.DoughnutChart != nil:
+			add(chartGroup{kind: chartDoughnut}, pieSeries(pc.DoughnutChart.Ser))
+		case pc.ScatterChart != nil:
+			g := chartGroup{kind: chartScatter}
+			if st := pc.ScatterChart.ScatterStyle; st != nil {
+				switch st.ValAttr {
+				case crt.ST_ScatterStyleLine, crt.ST_ScatterStyleLineMarker, crt.ST_ScatterStyleSmooth, crt.ST_ScatterStyleSmoothMarker:
+					g.lines = true
+				}
+			}
+			sers := []series{}
+			for _, s := range pc.ScatterChart.Ser {
+				sers = append(sers, series{s.Tx, s.SpPr, s.DPt, s.XVal, s.YVal})
+			}
+			add(g, sers)
+		case pc.BubbleChart != nil:
+			sers := []series{}
+			for _, s := range pc.BubbleChart.Ser {
+				sers = append(sers, series{s.Tx, s.SpPr, s.DPt, s.XVal, s.YVal})
+			}
+			add(chartGroup{kind: chartScatter}, sers)
+		}
+	}
+	// pie charts can't be combined with other charts
+	if len(groups) > 1 && (groups[0].kind == chartPie || groups[0].kind == chartDoughnut) {
+		groups = groups[:1]
+	}
+	return groups
+}
+
+// series are the elements shared by the series of all chart types.
+type series struct {
+	tx   *crt.CT_SerTx
+	spPr *dml.CT_ShapeProperties
+	dPt  []*crt.CT_DPt
+	cat  *crt.CT_AxDataSource
+	val  *crt.CT_NumDataSource
+}
+
+func barSeries(sers []*crt.CT_BarSer) []series {
+	ret := []series{}
+	for _, s := range sers {
+		ret = append(ret, series{s.Tx, s.SpPr, s.DPt, s.Cat, s.Val})
+	}
+	return ret
+}
+
+func lineSeries(sers []*crt.CT_LineSer) []series {
+	ret := []series{}
+	for _, s := range sers {
+		ret = append(ret, series{s.Tx, s.SpPr, s.DPt, s.Cat, s.Val})
+	}
+	return ret
+}
+
+func areaSeries(sers []*crt.CT_AreaSer) []series {
+	ret := []series{}
+	for _, s := range sers {
+		ret = append(ret, series{s.Tx, s.SpPr, s.DPt, s.Cat, s.Val})
+	}
+	return ret
+}
+
+func pieSeries(sers []*crt.CT_PieSer) []series {
+	ret := []series{}
+	for _, s := range sers {
+		ret = append(ret, series{s.Tx, s.SpPr, s.DPt, s.Cat, s.Val})
+	}
+	return ret
+}
+
+func barGroup(dir *crt.CT_BarDir, grouping *crt.CT_BarGrouping) chartGroup {
+	g := chartGroup{kind: chartColumn}
+	if dir != nil && dir.ValAttr == crt.ST_BarDirBar {
+		g.kind = chartBar
+	}
+	if grouping != nil {
+		g.stacked = grouping.ValAttr == crt.ST_BarGroupingStacked || grouping.ValAttr == crt.ST_BarGroupingPercentStacked
+		g.percent = grouping.ValAttr == crt.ST_BarGroupingPercentStacked
+	}
+	return g
+}
+
+func lineGroup(kind chartKind, grouping *crt.CT_Grouping) chartGroup {
+	g := chartGroup{kind: kind}
+	if grouping != nil {
+		g.stacked = grouping.ValAttr == crt.ST_GroupingStacked || grouping.ValAttr == crt.ST_GroupingPercentStacked
+		g.percent = grouping.ValAttr == crt.ST_GroupingPercentStacked
+	}
+	return g
+}
+
+func serName(tx *crt.CT_SerTx) string {
+	if tx == nil || tx.SerTxChoice == nil {
+		return ""
+	}
+	if tx.SerTxChoice.V != nil {
+		return *tx.SerTxChoice.V
+	}
+	if tx.SerTxChoice.StrRef != nil {
+		return strCache(tx.SerTxChoice.StrRef.StrCache, " ")
+	}
+	return ""
+}
+
+// shapeColor returns the solid RGB color of the fill, or of the outline for
+// lines, of shape properties.
+func shapeColor(sp *dml.CT_ShapeProperties, line bool) (color.RGBA, bool) {
+	if sp == nil {
+		return color.RGBA{}, false
+	}
+	var solid *dml.CT_SolidColorFillProperties
+	if line {
+		if sp.Ln != nil && sp.Ln.LineFillPropertiesChoice != nil {
+			solid = sp.Ln.LineFillPropertiesChoice.SolidFill
+		}
+	} else if sp.FillPropertiesChoice != nil {
+		solid = sp.FillPropertiesChoice.SolidFill
+	}
+	if solid == nil || solid.SrgbClr == nil {
+		return color.RGBA{}, false
+	}
+	c := parseRGB(solid.SrgbClr.ValAttr, color.RGBA{})
+	return c, c.A != 0
+}
+
+// numCache returns the cached values of numeric data, missing points are NaN.
+func numCache(d *crt.CT_NumData) []float64 {
+	if d == nil {
+		return nil
+	}
+	n := 0
+	if d.PtCount != nil {
+		n = int(d.PtCount.ValAttr)
+	}
+	for _, pt := range d.Pt {
+		if int(pt.IdxAttr) >= n {
+			n = int(pt.IdxAttr) + 1
+		}
+	}
+	ret := make([]float64, n)
+	for i := range ret {
+		ret[i] = math.NaN()
+	}
+	for _, pt := range d.Pt {
+		if v, err := strconv.ParseFloat(pt.V, 64); err == nil {
+			ret[pt.IdxAttr] = v
+		}
+	}
+	return ret
+}
+
+func numData(ds *crt.CT_NumDataSource) []float64 {
+	if ds == nil || ds.NumDataSourceChoice == nil {
+		return nil
+	}
+	if ds.NumDataSourceChoice.NumRef != nil {
+		return numCache(ds.NumDataSourceChoice.NumRef.NumCache)
+	}
+	return numCache(ds.NumDataSourceChoice.NumLit)
+}
+
+// axData returns the labels of category data, and their values for numeric
+// categories.
+func axData(ds *crt.CT_AxDataSource) ([]string, []float64) {
+	if ds == nil || ds.AxDataSourceChoice == nil {
+		return nil, nil
+	}
+	ch := ds.AxDataSourceChoice
+	var strs *crt.CT_StrData
+	var nums *crt.CT_NumData
+	switch {
+	case ch.StrRef != nil:
+		strs = ch.StrRef.StrCache
+	case ch.StrLit != nil:
+		strs = ch.StrLit
+	case ch.NumRef != nil:
+		nums = ch.NumRef.NumCache
+	case ch.NumLit != nil:
+		nums = ch.NumLit
+	case ch.MultiLvlStrRef != nil && ch.MultiLvlStrRef.MultiLvlStrCache != nil:
+		// the first level holds the innermost labels
+		mc := ch.MultiLvlStrRef.MultiLvlStrCache
+		if len(mc.Lvl) > 0 {
+			strs = &crt.CT_StrData{PtCount: mc.PtCount, Pt: mc.Lvl[0].Pt}
+		}
+	}
+	if nums != nil {
+		vals := numCache(nums)
+		labels := make([]string, len(vals))
+		for i, v := range vals {
+			if !math.IsNaN(v) {
+				labels[i] = strconv.FormatFloat(v, 'f', -1, 64)
+			}
+		}
+		return labels, vals
+	}
+	if strs == nil {
+		return nil, nil
+	}
+	n := 0
+	if strs.PtCount != nil {
+		n = int(strs.PtCount.ValAttr)
+	}
+	for _, pt := range strs.Pt {
+		if int(pt.IdxAttr) >= n {
+			n = int(pt.IdxAttr) + 1
+		}
+	}
+	labels := make([]string, n)
+	for _, pt := range strs.Pt {
+		labels[pt.IdxAttr] = pt.V
+	}
+	return labels, nil
+}
+
+// drawLegend draws the legend of a chart, returning the remaining area of the
+// plot.
+func drawLegend(p painter, frame, inner rect, l *crt.CT_Legend, groups []chartGroup) rect {
+	type entry struct {
+		name  string
+		color color.RGBA
+	}
+	entries := []entry{}
+	if g := groups[0]; g.kind == chartPie || g.kind == chartDoughnut {
+		s := g.series[0]
+		for i, cat := range s.cats {
+			entries = append(entries, entry{cat, pointColor(s, i, true)})
+		}
+	} else {
+		for _, g := range groups {
+			for _, s := range g.series {
+				entries = append(entries, entry{s.name, s.color})
+			}
+		}
+	}
+	if len(entries) == 0 {
+		return inner
+	}
+	m := p.fonts().face(chartFont, 96).Metrics()
+	lh := float64(m.Height)/64 + 2
+	widths := make([]float64, len(entries))
+	max := 0.0
+	for i, e := range entries {
+		widths[i] = p.fonts().measure(chartFont, e.name) + 14
+		max = math.Max(max, widths[i])
+	}
+	pos := crt.ST_LegendPosR
+	if l.LegendPos != nil && l.LegendPos.ValAttr != crt.ST_LegendPosUnset {
+		pos = l.LegendPos.ValAttr
+	}
+	drawEntry := func(x, y float64, e entry) {
+		p.fillRect(rect{x, y + (lh-7)/2, 7, 7}, e.color)
+		p.text(point{x + 10, y + float64(m.Ascent)/64 + 1}, e.name, chartFont, chartTextColor, frame)
+	}
+	switch pos {
+	case crt.ST_LegendPosB, crt.ST_LegendPosT:
+		total := 0.0
+		for _, w := range widths {
+			total += w + 8
+		}
+		x := inner.x + math.Max(0, (inner.w-total)/2)
+		y := inner.y + inner.h - lh
+		if pos == crt.ST_LegendPosT {
+			y = inner.y
+			inner.y += lh + 4
+		}
+		inner.h -= lh + 4
+		for i, e := range entries {
+			drawEntry(x, y, e)
+			x += widths[i] + 8
+		}
+	default:
+		x := inner.x + inner.w - max
+		if pos == crt.ST_LegendPosL {
+			x = inner.x
+			inner.x += max + 8
+		}
+		inner.w -= max + 8
+		y := inner.y + (inner.h-lh*float64(len(entries)))/2
+		if pos == crt.ST_LegendPosTr {
+			y = inner.y
+		}
+		for _, e := range entries {
+			drawEntry(x, y, e)
+			y += lh
+		}
+	}
+	return inner
+}
+
+// pointColor returns the color of a data point, points of pie charts vary
+// their colors by default.
+func pointColor(s chartSeries, i int, vary bool) color.RGBA {
+	if c, ok := s.points[i]; ok {
+		return c
+	}
+	if vary {
+		return chartPalette[i%len(chartPalette)]
+	}
+	return s.color
+}
+
+// drawPie draws the first series of a pie or doughnut chart clockwise from
+// the top.
+func drawPie(p painter, r rect, g chartGroup) {
+	s := g.series[0]
+	total := 0.0
+	for _, v := range s.vals {
+		if !math.IsNaN(v) && v > 0 {
+			total += v
+		}
+	}
+	if total == 0 {
+		return
+	}
+	radius := math.Min(r.w, r.h)/2 - 4
+	if radius <= 0 {
+		return
+	}
+	c := point{r.x + r.w/2, r.y + r.h/2}
+	hole := 0.0
+	if g.kind == chartDoughnut {
+		hole = radius / 2
+	}
+	arc := func(from, to, rad float64) []point {
+		pts := []point{}
+		steps := int(math.Ceil((to-from)/(math.Pi/90))) + 1
+		for i := 0; i <= steps; i++ {
+			a := from + (to-from)*float64(i)/float64(steps)
+			pts = append(pts, point{c.x + rad*math.Sin(a), c.y - rad*math.Cos(a)})
+		}
+		return pts
+	}
+	angle := 0.0
+	for i, v := range s.vals {
+		if math.IsNaN(v) || v <= 0 {
+			continue
+		}
+		next := angle + v/total*2*math.Pi
+		pts := arc(angle, next, radius)
+		if hole > 0 {
+			inner := arc(angle, next, hole)
+			for j := len(inner) - 1; j >= 0; j-- {
+				pts = append(pts, inner[j])
+			}
+		} else {
+			pts = append(pts, c)
+		}
+		p.fillPolygon(pts, pointColor(s, i, true))
+		// separate the slices the way Excel draws them with a white outline
+		p.line(pts[0], point{c.x + hole*math.Sin(angle), c.y - hole*math.Cos(angle)}, 1.5, nil, white)
+		angle = next
+	}
+}
+
+// niceScale returns the bounds and step of an axis showing values between
+// lo and hi with about five gridlines.
+func niceScale(lo, hi float64) (float64, float64, float64) {
+	if lo == hi {
+		if lo == 0 {
+			return 0, 1, 0.2
+		}
+		lo, hi = math.Min(0, lo), math.Max(0, hi)
+	}
+	raw := (hi - lo) / 5
+	mag := math.Pow(10, math.Floor(math.Log10(raw)))
+	step := mag
+	for _, m := range []float64{1, 2, 2.5, 5, 10} {
+		if raw <= m*mag {
+			step = m * mag
+			break
+		}
+	}
+	return math.Floor(lo/step) * step, math.Ceil(hi/step) * step, step
+}
+
+func axisLabel(v float64, percent bool) string {
+	v = math.Round(v*1e9) / 1e9
+	if percent {
+		return strconv.FormatFloat(v*100, 'f', -1, 64) + "%"
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// drawAxes draws the charts that have a category or X axis and a value axis.
+func drawAxes(p painter, r rect, groups []chartGroup) {
+	fc := p.fonts()
+	m := fc.face(chartFont, 96).Metrics()
+	lh := float64(m.Height) / 64
+	ascent := float64(m.Ascent) / 64
+
+	// the value range of all series, stacked series add up their values
+	lo, hi := math.Inf(1), math.Inf(-1)
+	xlo, xhi := math.Inf(1), math.Inf(-1)
+	cats := []string{}
+	percent, scatter, horizontal := false, false, false
+	for _, g := range groups {
+		percent = percent || g.percent
+		scatter = scatter || g.kind == chartScatter
+		horizontal = horizontal || g.kind == chartBar
+		if g.kind == chartBar || g.kind == chartColumn || g.kind == chartArea {
+			lo, hi = math.Min(lo, 0), math.Max(hi, 0)
+		}
+		for i := 0; i < seriesLength(g); i++ {
+			pos, neg := 0.0, 0.0
+			for _, s := range g.series {
+				v := stackedValue(g, s, i)
+				if math.IsNaN(v) {
+					continue
+				}
+				if !g.stacked {
+					lo, hi = math.Min(lo, v), math.Max(hi, v)
+					continue
+				}
+				if v >= 0 {
+					pos += v
+				} else {
+					neg += v
+				}
+			}
+			if g.stacked {
+				lo, hi = math.Min(lo, neg), math.Max(hi, pos)
+			}
+		}
+		for _, s := range g.series {
+			if len(s.cats) > len(cats) {
+				cats = s.cats
+			}
+			for i := range s.vals {
+				x := float64(i + 1)
+				if i < len(s.xs) && !math.IsNaN(s.xs[i]) {
+					x = s.xs[i]
+				}
+				xlo, xhi = math.Min(xlo, x), math.Max(xhi, x)
+			}
+		}
+	}
+	if math.IsInf(lo, 0) {
+		return
+	}
+	lo, hi, step := niceScale(lo, hi)
+
+	// labels of the value axis and the space they take
+	labels := []string{}
+	labelW := 0.0
+	for v := lo; v <= hi+step/2; v += step {
+		l := axisLabel(v, percent)
+		labels = append(labels, l)
+		labelW = math.Max(labelW, fc.measure(chartFont, l))
+	}
+	plot := rect{r.x + labelW + 6, r.y + ascent/2, r.w - labelW - 6 - 4, r.h - lh - 4 - ascent/2}
+	if horizontal {
+		catW := 0.0
+		for _, c := range cats {
+			catW = math.Max(catW, fc.measure(chartFont, c))
+		}
+		plot = rect{r.x + catW + 6, r.y + 2, r.w - catW - 6 - labelW/2 - 2, r.h - lh - 6}
+	}
+	if plot.w <= 0 || plot.h <= 0 {
+		return
+	}
+
+	// value gridlines and labels
+	for i, l := range labels {
+		v := lo + float64(i)*step
+		f := (v - lo) / (hi - lo)
+		w := fc.measure(chartFont, l)
+		if horizontal {
+			x := plot.x + f*plot.w
+			p.line(point{x, plot.y}, point{x, plot.y + plot.h}, 1, nil, chartLineColor)
+			p.text(point{x - w/2, plot.y + plot.h + 2 + ascent}, l, chartFont, chartTextColor, r)
+			continue
+		}
+		y := plot.y + plot.h - f*plot.h
+		p.line(point{plot.x, y}, point{plot.x + plot.w, y}, 1, nil, chartLineColor)
+		p.text(point{plot.x - 4 - w, y + ascent/2 - 1}, l, chartFont, chartTextColor, r)
+	}
+	valuePos := func(v float64) float64 {
+		f := (v - lo) / (hi - lo)
+		if horizontal {
+			return plot.x + f*plot.w
+		}
+		return plot.y + plot.h - f*plot.h
+	}
+
+	// category labels, or the X axis of scatter charts
+	n := len(cats)
+	for _, g := range groups {
+		n = max(n, seriesLength(g))
+	}
+	if n == 0 {
+		return
+	}
+	var xlo2, xhi2, xstep float64
+	if scatter {
+		xlo2, xhi2, xstep = niceScale(xlo, xhi)
+		for v := xlo2; v <= xhi2+xstep/2; v += xstep {
+			l := axisLabel(v, false)
+			x := plot.x + (v-xlo2)/(xhi2-xlo2)*plot.w
+			p.text(point{x - fc.measure(chartFont, l)/2, plot.y + plot.h + 2 + ascent}, l, chartFont, chartTextColor, r)
+		}
+	} else {
+		band := plot.w / float64(n)
+		if horizontal {
+			band = plot.h / float64(n)
+		}
+		for i := 0; i < n && i < len(cats); i++ {
+			w := fc.measure(chartFont, cats[i])
+			if horizontal {
+				// the first category is at the bottom
+				y := plot.y + plot.h - (float64(i)+0.5)*band
+				p.text(point{plot.x - 4 - w, y + ascent/2 - 1}, cats[i], chartFont, chartTextColor, r)
+				continue
+			}
+			x := plot.x + (float64(i)+0.5)*band
+			p.text(point{x - w/2, plot.y + plot.h + 2 + ascent}, cats[i], chartFont, chartTextColor, r)
+		}
+	}
+	axis := valuePos(math.Max(lo, math.Min(0, hi)))
+	catPos := func(i int) float64 {
+		if horizontal {
+			return plot.y + plot.h - (float64(i)+0.5)*plot.h/float64(n)
+		}
+		return plot.x + (float64(i)+0.5)*plot.w/float64(n)
+	}
+
+	for _, g := range groups {
+		switch g.kind {
+		case chartBar, chartColumn:
+			band := plot.w / float64(n)
+			if horizontal {
+				band = plot.h / float64(n)
+			}
+			// the default gap between categories is 150% of a bar
+			slots := 1
+			if !g.stacked {
+				slots = len(g.series)
+			}
+			barW := band / (float64(slots) + 1.5)
+			for i := 0; i < n; i++ {
+				pos, neg := 0.0, 0.0
+				start := catPos(i) - barW*float64(slots)/2
+				for j, s := range g.series {
+					v := stackedValue(g, s, i)
+					if math.IsNaN(v) {
+						continue
+					}
+					from, to := 0.0, v
+					if g.stacked {
+						if v >= 0 {
+							from, to = pos, pos+v
+							pos += v
+						} else {
+							from, to = neg, neg+v
+							neg += v
+						}
+					}
+					offset := start
+					if !g.stacked {
+						offset += float64(j) * barW
+					}
+					a, b := valuePos(from), valuePos(to)
+					bar := rect{offset, math.Min(a, b), barW, math.Abs(b - a)}
+					if horizontal {
+						bar = rect{math.Min(a, b), offset, math.Abs(b - a), barW}
+					}
+					p.fillRect(bar, pointColor(s, i, false))
+				}
+			}
+		case chartLine, chartArea:
+			base := make([]float64, n)
+			for _, s := range g.series {
+				pts := []point{}
+				for i := 0; i < n; i++ {
+					v := stackedValue(g, s, i)
+					if math.IsNaN(v) {
+						v = 0
+					}
+					if g.stacked {
+						v += base[i]
+						base[i] = v
+					}
+					pts = append(pts, point{catPos(i), valuePos(v)})
+				}
+				if g.kind == chartArea {
+					poly := append([]point{{pts[0].x, axis}}, pts...)
+					poly = append(poly, point{pts[len(pts)-1].x, axis})
+					p.fillPolygon(poly, s.color)
+					continue
+				}
+				for i := 1; i < len(pts); i++ {
+					p.line(pts[i-1], pts[i], 2.25, nil, s.color)
+				}
+			}
+		case chartScatter:
+			for _, s := range g.series {
+				pts := []point{}
+				for i, v := range s.vals {
+					if math.IsNaN(v) {
+						continue
+					}
+					x := float64(i + 1)
+					if i < len(s.xs) && !math.IsNaN(s.xs[i]) {
+						x = s.xs[i]
+					}
+					pts = append(pts, point{plot.x + (x-xlo2)/(xhi2-xlo2)*plot.w, valuePos(v)})
+				}
+				for i, pt := range pts {
+					if g.lines && i > 0 {
+						p.line(pts[i-1], pt, 2.25, nil, s.color)
+					}
+					p.fillRect(rect{pt.x - 2.5, pt.y - 2.5, 5, 5}, s.color)
+				}
+			}
+		}
+	}
+	// the category axis line
+	if horizontal {
+		p.line(point{axis, plot.y}, point{axis, plot.y + plot.h}, 1, nil, chartLineColor)
+	} else {
+		p.line(point{plot.x, axis}, point{plot.x + plot.w, axis}, 1, nil, chartLineColor)
+	}
+}
+
+// seriesLength returns the number of points of the longest series of a chart.
+func seriesLength(g chartGroup) int {
+	n := 0
+	for _, s := range g.series {
+		n = max(n, len(s.vals))
+	}
+	return n
+}
+
+// stackedValue returns the value of a point, as a fraction of the total of
+// the category for percent stacked charts.
+func stackedValue(g chartGroup, s chartSeries, i int) float64 {
+	if i >= len(s.vals) {
+		return math.NaN()
+	}
+	v := s.vals[i]
+	if !g.percent || math.IsNaN(v) {
+		return v
+	}
+	total := 0.0
+	for _, o := range g.series {
+		if i < len(o.vals) && !math.IsNaN(o.vals[i]) {
+			total += math.Abs(o.vals[i])
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return v / total
+}
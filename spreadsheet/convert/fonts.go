@@ -0,0 +1,92 @@
+package convert
+
+import (
+	"strings"
+	"sync"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/gobolditalic"
+	"golang.org/x/image/font/gofont/goitalic"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/gomonobold"
+	"golang.org/x/image/font/gofont/gomonobolditalic"
+	"golang.org/x/image/font/gofont/gomonoitalic"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+)
+
+// goFonts are the parsed Go fonts by monospace, bold and italic.
+var (
+	goFontsOnce sync.Once
+	goFonts     [2][2][2]*opentype.Font
+)
+
+func loadGoFonts() {
+	goFontsOnce.Do(func() {
+		for i, ttf := range [][]byte{
+			goregular.TTF, goitalic.TTF, gobold.TTF, gobolditalic.TTF,
+			gomono.TTF, gomonoitalic.TTF, gomonobold.TTF, gomonobolditalic.TTF,
+		} {
+			// the embedded fonts are known to be valid
+			f, err := opentype.Parse(ttf)
+			if err != nil {
+				panic(err)
+			}
+			goFonts[i>>2][i>>1&1][i&1] = f
+		}
+	})
+}
+
+// isMonospace returns true for the font families of fixed width fonts.
+func isMonospace(family string) bool {
+	family = strings.ToLower(family)
+	for _, m := range []string{"courier", "consolas", "mono", "lucida console"} {
+		if strings.Contains(family, m) {
+			return true
+		}
+	}
+	return false
+}
+
+type faceKey struct {
+	mono, bold, italic bool
+	size, dpi          float64
+}
+
+// fontCache caches the faces of the Go fonts at the sizes used.
+type fontCache struct {
+	faces map[faceKey]font.Face
+}
+
+func newFontCache() *fontCache {
+	loadGoFonts()
+	return &fontCache{faces: map[faceKey]font.Face{}}
+}
+
+// face returns the face used for a font at a resolution.
+func (fc *fontCache) face(f textFace, dpi float64) font.Face {
+	k := faceKey{isMonospace(f.family), f.bold, f.italic, f.size, dpi}
+	if face, ok := fc.faces[k]; ok {
+		return face
+	}
+	b2i := func(b bool) int {
+		if b {
+			return 1
+		}
+		return 0
+	}
+	otf := goFonts[b2i(k.mono)][b2i(k.bold)][b2i(k.italic)]
+	face, err := opentype.NewFace(otf, &opentype.FaceOptions{Size: f.size, DPI: dpi, Hinting: font.HintingNone})
+	if err != nil {
+		face = basicfont.Face7x13
+	}
+	fc.faces[k] = face
+	return face
+}
+
+// measure returns the width of text in pixels at 96 DPI.
+func (fc *fontCache) measure(f textFace, s string) float64 {
+	return float64(font.MeasureString(fc.face(f, 96), s)) / 64
+}
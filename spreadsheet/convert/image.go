@@ -0,0 +1,518 @@
+package convert
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif"  // decoding of GIF pictures
+	_ "image/jpeg" // decoding of JPEG pictures
+	_ "image/png"  // decoding of PNG pictures
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	xdraw "golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+
+	"github.com/yaklabco/unioffice/v2/common"
+	"github.com/yaklabco/unioffice/v2/common/tempstorage"
+	"github.com/yaklabco/unioffice/v2/schema/soo/ofc/sharedTypes"
+	"github.com/yaklabco/unioffice/v2/schema/soo/sml"
+	"github.com/yaklabco/unioffice/v2/spreadsheet"
+)
+
+// ImageOptions controls how ranges are drawn by RenderRangeToImage and
+// RenderRangeToSVG.
+type ImageOptions struct {
+	// DPI is the resolution of the image, it defaults to 96 which is the
+	// size Excel displays the range at 100% zoom.
+	DPI float64
+	// Gridlines draws light gray lines between cells without a fill.
+	Gridlines bool
+	// Transparent leaves the background of cells without a fill transparent
+	// instead of white.
+	Transparent bool
+	// NoImages omits the images of the sheet drawing.
+	NoImages bool
+	// NoCharts omits the charts of the sheet drawing.
+	NoCharts bool
+}
+
+// RenderRangeToImage draws a range of cells of a sheet, e.g. "A1:H30", with
+// their styles, borders, merged cells and conditional formats along with the
+// images and charts placed over the range. Charts are drawn from the values
+// cached in the chart. Text uses the Go fonts, measured the same way for
+// every font family.
+func RenderRangeToImage(s *spreadsheet.Sheet, ref string, opts *ImageOptions) (image.Image, error) {
+	opts = imageOptions(opts)
+	lay, err := s.LayoutRange(ref)
+	if err != nil {
+		return nil, err
+	}
+	scale := opts.DPI / 96
+	w, h := int(math.Ceil(float64(lay.Bounds.Dx())*scale)), int(math.Ceil(float64(lay.Bounds.Dy())*scale))
+	if w <= 0 || h <= 0 {
+		return nil, errors.New("range has no visible cells")
+	}
+	rp := &rasterPainter{img: image.NewRGBA(image.Rect(0, 0, w, h)), scale: scale, fc: newFontCache()}
+	drawLayout(rp, lay, opts)
+	return rp.img, nil
+}
+
+// RenderRangeToSVG draws a range of cells the way RenderRangeToImage does as
+// an SVG document, sized in pixels at the DPI of the options.
+func RenderRangeToSVG(s *spreadsheet.Sheet, ref string, opts *ImageOptions) ([]byte, error) {
+	opts = imageOptions(opts)
+	lay, err := s.LayoutRange(ref)
+	if err != nil {
+		return nil, err
+	}
+	if lay.Bounds.Empty() {
+		return nil, errors.New("range has no visible cells")
+	}
+	sp := newSVGPainter(lay.Bounds, opts.DPI/96)
+	drawLayout(sp, lay, opts)
+	return sp.bytes(), nil
+}
+
+func imageOptions(opts *ImageOptions) *ImageOptions {
+	ret := ImageOptions{}
+	if opts != nil {
+		ret = *opts
+	}
+	if ret.DPI <= 0 {
+		ret.DPI = 96
+	}
+	return &ret
+}
+
+// rect is an area in pixels at 96 DPI.
+type rect struct{ x, y, w, h float64 }
+
+func rectOf(r image.Rectangle) rect {
+	return rect{float64(r.Min.X), float64(r.Min.Y), float64(r.Dx()), float64(r.Dy())}
+}
+
+type point struct{ x, y float64 }
+
+// painter draws shapes in pixels at 96 DPI, scaling them to the resolution of
+// its output.
+type painter interface {
+	fillRect(r rect, c color.RGBA)
+	fillPolygon(pts []point, c color.RGBA)
+	// line strokes a line of the given width, dashes are lengths of the
+	// alternating dashes and gaps in multiples of the width.
+	line(a, b point, width float64, dash []float64, c color.RGBA)
+	// text draws text starting at the baseline point p, clipped to an area.
+	text(p point, s string, f textFace, c color.RGBA, clip rect)
+	picture(r rect, data []byte, format string)
+	fonts() *fontCache
+}
+
+// textFace is the font of a run of text.
+type textFace struct {
+	family       string
+	size         float64
+	bold, italic bool
+}
+
+var (
+	black     = color.RGBA{0, 0, 0, 0xff}
+	white     = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	gridColor = color.RGBA{0xd4, 0xd4, 0xd4, 0xff}
+)
+
+// parseRGB parses an RGB value such as "FF0000".
+func parseRGB(s string, def color.RGBA) color.RGBA {
+	if len(s) == 8 {
+		s = s[2:]
+	}
+	v, err := strconv.ParseUint(s, 16, 32)
+	if len(s) != 6 || err != nil {
+		return def
+	}
+	return color.RGBA{uint8(v >> 16), uint8(v >> 8), uint8(v), 0xff}
+}
+
+// drawLayout draws the cells, images and charts of a range.
+func drawLayout(p painter, lay spreadsheet.RangeLayout, opts *ImageOptions) {
+	if !opts.Transparent {
+		p.fillRect(rectOf(lay.Bounds), white)
+	}
+	for _, c := range lay.Cells {
+		r := rectOf(c.Bounds)
+		if c.Fill != "" {
+			p.fillRect(r, parseRGB(c.Fill, white))
+		}
+		if c.DataBar > 0 {
+			p.fillRect(rect{r.x + 1, r.y + 2, (r.w - 2) * math.Min(c.DataBar, 1), r.h - 4}, parseRGB(c.DataBarColor, black))
+		}
+		if opts.Gridlines && c.Fill == "" {
+			p.fillRect(rect{r.x + r.w - 1, r.y, 1, r.h}, gridColor)
+			p.fillRect(rect{r.x, r.y + r.h - 1, r.w, 1}, gridColor)
+			if c.Bounds.Min.X == 0 {
+				p.fillRect(rect{r.x, r.y, 1, r.h}, gridColor)
+			}
+			if c.Bounds.Min.Y == 0 {
+				p.fillRect(rect{r.x, r.y, r.w, 1}, gridColor)
+			}
+		}
+	}
+	for i, c := range lay.Cells {
+		if len(c.Runs) > 0 {
+			drawCellText(p, c, textClip(lay, i))
+		}
+	}
+	for _, c := range lay.Cells {
+		r := rectOf(c.Bounds)
+		drawBorder(p, c.Top, point{r.x, r.y}, point{r.x + r.w, r.y}, 1)
+		drawBorder(p, c.Bottom, point{r.x, r.y + r.h}, point{r.x + r.w, r.y + r.h}, -1)
+		drawBorder(p, c.Left, point{r.x, r.y}, point{r.x, r.y + r.h}, 1)
+		drawBorder(p, c.Right, point{r.x + r.w, r.y}, point{r.x + r.w, r.y + r.h}, -1)
+	}
+	if !opts.NoImages {
+		for _, img := range lay.Images {
+			if data, ok := imageData(img.Image); ok {
+				p.picture(rectOf(img.Bounds), data, img.Image.Format())
+			}
+		}
+	}
+	if !opts.NoCharts {
+		for _, ch := range lay.Charts {
+			drawChart(p, rectOf(ch.Bounds), ch.Chart)
+		}
+	}
+}
+
+// textClip returns the area the text of a cell can use. Text that isn't
+// wrapped overflows into the neighboring cells of the row that are empty.
+func textClip(lay spreadsheet.RangeLayout, i int) rect {
+	c := lay.Cells[i]
+	r := rectOf(c.Bounds)
+	if c.Wrap || c.Merged {
+		return r
+	}
+	left, right := c.HorizontalAlignment != sml.ST_HorizontalAlignmentLeft, c.HorizontalAlignment != sml.ST_HorizontalAlignmentRight
+	if right {
+		max := float64(lay.Bounds.Max.X)
+		for j := i + 1; j < len(lay.Cells) && lay.Cells[j].Bounds.Min.Y == c.Bounds.Min.Y; j++ {
+			if n := lay.Cells[j]; len(n.Runs) > 0 || n.Merged || n.Bounds.Dy() != c.Bounds.Dy() {
+				max = float64(lay.Cells[j].Bounds.Min.X)
+				break
+			}
+		}
+		r.w = max - r.x
+	}
+	if left {
+		min := 0.0
+		for j := i - 1; j >= 0 && lay.Cells[j].Bounds.Min.Y == c.Bounds.Min.Y; j-- {
+			if n := lay.Cells[j]; len(n.Runs) > 0 || n.Merged || n.Bounds.Dy() != c.Bounds.Dy() {
+				min = float64(lay.Cells[j].Bounds.Max.X)
+				break
+			}
+		}
+		r.w += r.x - min
+		r.x = min
+	}
+	return r
+}
+
+// borderLine returns the width of a border style and its dash pattern.
+func borderLine(st sml.ST_BorderStyle) (float64, []float64) {
+	switch st {
+	case sml.ST_BorderStyleThin:
+		return 1, nil
+	case sml.ST_BorderStyleMedium:
+		return 2, nil
+	case sml.ST_BorderStyleThick, sml.ST_BorderStyleDouble:
+		return 3, nil
+	case sml.ST_BorderStyleHair, sml.ST_BorderStyleDotted:
+		return 1, []float64{1, 1}
+	case sml.ST_BorderStyleDashed:
+		return 1, []float64{3, 1}
+	case sml.ST_BorderStyleDashDot:
+		return 1, []float64{3, 1, 1, 1}
+	case sml.ST_BorderStyleDashDotDot:
+		return 1, []float64{3, 1, 1, 1, 1, 1}
+	case sml.ST_BorderStyleMediumDashed:
+		return 2, []float64{3, 1}
+	case sml.ST_BorderStyleMediumDashDot, sml.ST_BorderStyleSlantDashDot:
+		return 2, []float64{3, 1, 1, 1}
+	case sml.ST_BorderStyleMediumDashDotDot:
+		return 2, []float64{3, 1, 1, 1, 1, 1}
+	}
+	return 0, nil
+}
+
+// drawBorder draws a border along the edge of a cell from a to b, inside the
+// cell in the direction of inward.
+func drawBorder(p painter, b spreadsheet.LayoutBorder, a, z point, inward float64) {
+	width, dash := borderLine(b.Style)
+	if width == 0 {
+		return
+	}
+	c := parseRGB(b.Color, black)
+	// shift the line so that it is centered on the edge pixels of the cell
+	off := func(d float64) (point, point) {
+		if a.x == z.x {
+			return point{a.x + d, a.y}, point{z.x + d, z.y}
+		}
+		return point{a.x, a.y + d}, point{z.x, z.y + d}
+	}
+	if b.Style == sml.ST_BorderStyleDouble {
+		s, e := off(inward * 0.5)
+		p.line(s, e, 1, nil, c)
+		s, e = off(inward * 2.5)
+		p.line(s, e, 1, nil, c)
+		return
+	}
+	s, e := off(inward * width / 2)
+	p.line(s, e, width, dash, c)
+}
+
+// textSpan is a piece of a run of text on a line.
+type textSpan struct {
+	text  string
+	run   spreadsheet.TextRun
+	face  textFace
+	width float64
+}
+
+// textLine is a line of text of a cell.
+type textLine struct {
+	spans           []textSpan
+	width           float64
+	ascent, descent float64
+}
+
+// runFace returns the font of a run, superscript and subscript text is drawn
+// at two thirds of the size.
+func runFace(r spreadsheet.TextRun) textFace {
+	f := textFace{family: r.Font, size: r.Size, bold: r.Bold, italic: r.Italic}
+	if f.family == "" {
+		f.family = "Calibri"
+	}
+	if f.size <= 0 {
+		f.size = 11
+	}
+	if r.VertAlign == sharedTypes.ST_VerticalAlignRunSuperscript || r.VertAlign == sharedTypes.ST_VerticalAlignRunSubscript {
+		f.size = f.size * 2 / 3
+	}
+	return f
+}
+
+// layoutText breaks the runs of a cell into lines, wrapping them at spaces to
+// fit the width if wrap is set.
+func layoutText(fc *fontCache, runs []spreadsheet.TextRun, width float64, wrap bool) []textLine {
+	lines := []textLine{{}}
+	add := func(text string, r spreadsheet.TextRun, f textFace) {
+		l := &lines[len(lines)-1]
+		w := fc.measure(f, text)
+		if n := len(l.spans); n > 0 && l.spans[n-1].run == r {
+			l.spans[n-1].text += text
+			l.spans[n-1].width += w
+		} else {
+			l.spans = append(l.spans, textSpan{text, r, f, w})
+		}
+		l.width += w
+		m := fc.face(f, 96).Metrics()
+		l.ascent = math.Max(l.ascent, float64(m.Ascent)/64)
+		l.descent = math.Max(l.descent, float64(m.Descent)/64)
+	}
+	for _, r := range runs {
+		f := runFace(r)
+		for i, para := range strings.Split(r.Text, "\n") {
+			if i > 0 {
+				if !wrap {
+					continue
+				}
+				lines = append(lines, textLine{})
+			}
+			if !wrap {
+				add(para, r, f)
+				continue
+			}
+			for _, word := range strings.SplitAfter(para, " ") {
+				if word == "" {
+					continue
+				}
+				l := &lines[len(lines)-1]
+				if l.width > 0 && l.width+fc.measure(f, strings.TrimRight(word, " ")) > width {
+					lines = append(lines, textLine{})
+				}
+				add(word, r, f)
+			}
+		}
+	}
+	for i := range lines {
+		if lines[i].ascent == 0 {
+			m := fc.face(runFace(runs[0]), 96).Metrics()
+			lines[i].ascent, lines[i].descent = float64(m.Ascent)/64, float64(m.Descent)/64
+		}
+	}
+	return lines
+}
+
+// drawCellText draws the text of a cell aligned within the cell.
+func drawCellText(p painter, c spreadsheet.LayoutCell, clip rect) {
+	const padding = 2
+	r := rectOf(c.Bounds)
+	lines := layoutText(p.fonts(), c.Runs, r.w-2*padding-float64(c.Indent), c.Wrap)
+	height := 0.0
+	for _, l := range lines {
+		height += l.ascent + l.descent
+	}
+	y := r.y + r.h - height - 1
+	switch c.VerticalAlignment {
+	case sml.ST_VerticalAlignmentTop:
+		y = r.y + 1
+	case sml.ST_VerticalAlignmentCenter, sml.ST_VerticalAlignmentJustify, sml.ST_VerticalAlignmentDistributed:
+		y = r.y + (r.h-height)/2
+	}
+	for _, l := range lines {
+		x := r.x + padding + float64(c.Indent)
+		switch c.HorizontalAlignment {
+		case sml.ST_HorizontalAlignmentRight:
+			x = r.x + r.w - padding - l.width
+		case sml.ST_HorizontalAlignmentCenter:
+			x = r.x + (r.w-l.width)/2
+		}
+		y += l.ascent
+		for _, s := range l.spans {
+			col := parseRGB(s.run.Color, black)
+			base := y
+			switch s.run.VertAlign {
+			case sharedTypes.ST_VerticalAlignRunSuperscript:
+				base -= s.face.size * 96 / 72 / 2
+			case sharedTypes.ST_VerticalAlignRunSubscript:
+				base += s.face.size * 96 / 72 / 4
+			}
+			p.text(point{x, base}, s.text, s.face, col, clip)
+			width := math.Max(1, math.Round(s.face.size/12))
+			switch s.run.Underline {
+			case sml.ST_UnderlineValuesUnset, sml.ST_UnderlineValuesNone:
+			case sml.ST_UnderlineValuesDouble, sml.ST_UnderlineValuesDoubleAccounting:
+				p.line(point{x, base + 1.5}, point{x + s.width, base + 1.5}, width, nil, col)
+				p.line(point{x, base + 3.5}, point{x + s.width, base + 3.5}, width, nil, col)
+			default:
+				p.line(point{x, base + 1.5}, point{x + s.width, base + 1.5}, width, nil, col)
+			}
+			if s.run.Strike {
+				mid := base - s.face.size*96/72*0.3
+				p.line(point{x, mid}, point{x + s.width, mid}, width, nil, col)
+			}
+			x += s.width
+		}
+		y += l.descent
+	}
+}
+
+// imageData returns the encoded data of an image.
+func imageData(img common.ImageRef) ([]byte, bool) {
+	switch {
+	case img.Data() != nil:
+		return *img.Data(), true
+	case img.Path() != "":
+		f, err := tempstorage.Open(img.Path())
+		if err != nil {
+			return nil, false
+		}
+		defer f.Close()
+		data, err := io.ReadAll(f)
+		return data, err == nil
+	}
+	return nil, false
+}
+
+// rasterPainter draws on an RGBA image.
+type rasterPainter struct {
+	img   *image.RGBA
+	scale float64
+	fc    *fontCache
+}
+
+func (rp *rasterPainter) fonts() *fontCache { return rp.fc }
+
+func (rp *rasterPainter) rect(r rect) image.Rectangle {
+	return image.Rect(int(math.Round(r.x*rp.scale)), int(math.Round(r.y*rp.scale)),
+		int(math.Round((r.x+r.w)*rp.scale)), int(math.Round((r.y+r.h)*rp.scale)))
+}
+
+func (rp *rasterPainter) fillRect(r rect, c color.RGBA) {
+	draw.Draw(rp.img, rp.rect(r), image.NewUniform(c), image.Point{}, draw.Over)
+}
+
+func (rp *rasterPainter) fillPolygon(pts []point, c color.RGBA) {
+	if len(pts) < 3 {
+		return
+	}
+	b := rp.img.Bounds()
+	z := vector.NewRasterizer(b.Dx(), b.Dy())
+	z.MoveTo(float32(pts[0].x*rp.scale), float32(pts[0].y*rp.scale))
+	for _, pt := range pts[1:] {
+		z.LineTo(float32(pt.x*rp.scale), float32(pt.y*rp.scale))
+	}
+	z.ClosePath()
+	z.Draw(rp.img, b, image.NewUniform(c), image.Point{})
+}
+
+func (rp *rasterPainter) line(a, b point, width float64, dash []float64, c color.RGBA) {
+	length := math.Hypot(b.x-a.x, b.y-a.y)
+	if length == 0 {
+		return
+	}
+	dx, dy := (b.x-a.x)/length, (b.y-a.y)/length
+	segment := func(from, to float64) {
+		s := point{a.x + dx*from, a.y + dy*from}
+		e := point{a.x + dx*to, a.y + dy*to}
+		// horizontal and vertical lines are filled rectangles to keep them
+		// sharp, others are drawn as a polygon
+		switch {
+		case dy == 0:
+			rp.fillRect(rect{math.Min(s.x, e.x), s.y - width/2, math.Abs(e.x - s.x), width}, c)
+		case dx == 0:
+			rp.fillRect(rect{s.x - width/2, math.Min(s.y, e.y), width, math.Abs(e.y - s.y)}, c)
+		default:
+			nx, ny := -dy*width/2, dx*width/2
+			rp.fillPolygon([]point{{s.x + nx, s.y + ny}, {e.x + nx, e.y + ny}, {e.x - nx, e.y - ny}, {s.x - nx, s.y - ny}}, c)
+		}
+	}
+	if len(dash) == 0 {
+		segment(0, length)
+		return
+	}
+	for pos, i := 0.0, 0; pos < length; i++ {
+		d := dash[i%len(dash)] * math.Max(width, 1)
+		if i%2 == 0 {
+			segment(pos, math.Min(pos+d, length))
+		}
+		pos += d
+	}
+}
+
+func (rp *rasterPainter) text(p point, s string, f textFace, c color.RGBA, clip rect) {
+	dst, ok := rp.img.SubImage(rp.rect(clip)).(*image.RGBA)
+	if !ok || dst.Bounds().Empty() {
+		return
+	}
+	d := font.Drawer{
+		Dst:  dst,
+		Src:  image.NewUniform(c),
+		Face: rp.fc.face(f, 96*rp.scale),
+		Dot:  fixed.Point26_6{X: fixed.Int26_6(p.x * rp.scale * 64), Y: fixed.Int26_6(p.y * rp.scale * 64)},
+	}
+	d.DrawString(s)
+}
+
+func (rp *rasterPainter) picture(r rect, data []byte, format string) {
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return
+	}
+	xdraw.CatmullRom.Scale(rp.img, rp.rect(r), src, src.Bounds(), draw.Over, nil)
+}
@@ -0,0 +1,213 @@
+package convert
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image"
+	"image/color"
+	"strconv"
+	"testing"
+
+	ucolor "github.com/yaklabco/unioffice/v2/color"
+	"github.com/yaklabco/unioffice/v2/schema/soo/sml"
+	"github.com/yaklabco/unioffice/v2/spreadsheet"
+)
+
+// testImageSheet returns a sheet with text in A1, a number in C1, a red fill
+// on B2, empty merged cells on A3:C3 and a yellow filled merged cell on A5:B5.
+func testImageSheet() *spreadsheet.Sheet {
+	wb := spreadsheet.New()
+	sheet := wb.AddSheet()
+	sheet.Cell("A1").SetString("ab")
+	sheet.Cell("C1").SetNumber(1)
+	fillStyle := func(c ucolor.Color) spreadsheet.CellStyle {
+		style := wb.StyleSheet.AddCellStyle()
+		fill := wb.StyleSheet.Fills().AddFill()
+		pf := fill.SetPatternFill()
+		pf.SetPattern(sml.ST_PatternTypeSolid)
+		pf.SetFgColor(c)
+		style.SetFill(fill)
+		return style
+	}
+	sheet.Cell("B2").SetStyle(fillStyle(ucolor.Red))
+	sheet.AddMergedCells("A3", "C3")
+	sheet.Cell("A5").SetStyle(fillStyle(ucolor.Yellow))
+	sheet.AddMergedCells("A5", "B5")
+	return &sheet
+}
+
+// cellBounds returns the bounds of the cells of a layout by reference.
+func cellBounds(t *testing.T, s *spreadsheet.Sheet, ref string) map[string]image.Rectangle {
+	lay, err := s.LayoutRange(ref)
+	if err != nil {
+		t.Fatalf("error laying out %s: %s", ref, err)
+	}
+	ret := map[string]image.Rectangle{"": lay.Bounds}
+	for _, c := range lay.Cells {
+		ret[c.Reference] = c.Bounds
+	}
+	return ret
+}
+
+func isDark(c color.Color) bool {
+	r, g, b, _ := c.RGBA()
+	return r < 0x8000 && g < 0x8000 && b < 0x8000
+}
+
+// hasDark returns true if any pixel of the area is dark.
+func hasDark(img image.Image, r image.Rectangle) bool {
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if isDark(img.At(x, y)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func center(r image.Rectangle) image.Point {
+	return image.Pt((r.Min.X+r.Max.X)/2, (r.Min.Y+r.Max.Y)/2)
+}
+
+func TestRenderRangeToImage(t *testing.T) {
+	sheet := testImageSheet()
+	bounds := cellBounds(t, sheet, "A1:C5")
+	img, err := RenderRangeToImage(sheet, "A1:C5", &ImageOptions{Gridlines: true})
+	if err != nil {
+		t.Fatalf("error rendering: %s", err)
+	}
+	if img.Bounds() != bounds[""] {
+		t.Errorf("expected an image of %v, got %v", bounds[""], img.Bounds())
+	}
+
+	red, yellow, white := color.RGBA{0xFF, 0, 0, 0xFF}, color.RGBA{0xFF, 0xFF, 0, 0xFF}, color.RGBA{0xFF, 0xFF, 0xFF, 0xFF}
+	if p := center(bounds["B2"]); img.At(p.X, p.Y) != red {
+		t.Errorf("expected B2 to be red, got %v", img.At(p.X, p.Y))
+	}
+	if p := center(bounds["A2"]); img.At(p.X, p.Y) != white {
+		t.Errorf("expected A2 to be white, got %v", img.At(p.X, p.Y))
+	}
+
+	// text is left aligned, numbers right aligned
+	a1, c1 := bounds["A1"], bounds["C1"]
+	left := func(r image.Rectangle) image.Rectangle { return image.Rect(r.Min.X, r.Min.Y, center(r).X, r.Max.Y) }
+	right := func(r image.Rectangle) image.Rectangle { return image.Rect(center(r).X, r.Min.Y, r.Max.X, r.Max.Y) }
+	if !hasDark(img, left(a1)) || hasDark(img, right(a1)) {
+		t.Errorf("expected the text of A1 on its left")
+	}
+	if hasDark(img, left(c1)) || !hasDark(img, right(c1)) {
+		t.Errorf("expected the number of C1 on its right")
+	}
+
+	// merged cells span the columns without grid lines between them
+	a3, a4, a5 := bounds["A3"], bounds["A4"], bounds["A5"]
+	if _, ok := bounds["B3"]; ok || a3.Max.X != bounds["C2"].Max.X || a5.Max.X != bounds["B4"].Max.X {
+		t.Fatalf("expected the merged cells to cover their columns")
+	}
+	if x, y := bounds["A4"].Max.X-1, center(a3).Y; img.At(x, y) != white {
+		t.Errorf("expected no grid line within the merged cells, got %v", img.At(x, y))
+	}
+	if x, y := a4.Max.X-1, center(a4).Y; img.At(x, y) == white {
+		t.Errorf("expected a grid line between A4 and B4")
+	}
+	if p := center(image.Rect(bounds["B4"].Min.X, a5.Min.Y, a5.Max.X, a5.Max.Y)); img.At(p.X, p.Y) != yellow {
+		t.Errorf("expected the merged fill to cover B5, got %v", img.At(p.X, p.Y))
+	}
+
+	img, err = RenderRangeToImage(sheet, "A1:C5", &ImageOptions{DPI: 192})
+	if err != nil {
+		t.Fatalf("error rendering: %s", err)
+	}
+	if sz := img.Bounds().Size(); sz.X != 2*bounds[""].Dx() || sz.Y != 2*bounds[""].Dy() {
+		t.Errorf("expected the image to be scaled to the DPI, got %v", sz)
+	}
+	if _, err := RenderRangeToImage(sheet, "A1", nil); err == nil {
+		t.Errorf("expected an error for an invalid range")
+	}
+}
+
+// svgElement is an element of an SVG document.
+type svgElement struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr   `xml:",any,attr"`
+	Text     string       `xml:",chardata"`
+	Children []svgElement `xml:",any"`
+}
+
+func (e svgElement) attr(name string) string {
+	for _, a := range e.Attrs {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+func (e svgElement) all(name string) []svgElement {
+	ret := []svgElement{}
+	for _, c := range e.Children {
+		if c.XMLName.Local == name {
+			ret = append(ret, c)
+		}
+		ret = append(ret, c.all(name)...)
+	}
+	return ret
+}
+
+func TestRenderRangeToSVG(t *testing.T) {
+	sheet := testImageSheet()
+	bounds := cellBounds(t, sheet, "A1:C5")
+	data, err := RenderRangeToSVG(sheet, "A1:C5", &ImageOptions{DPI: 192})
+	if err != nil {
+		t.Fatalf("error rendering: %s", err)
+	}
+	doc := svgElement{}
+	if err := xml.NewDecoder(bytes.NewReader(data)).Decode(&doc); err != nil {
+		t.Fatalf("error decoding svg: %s\n%s", err, data)
+	}
+	w, h := bounds[""].Dx(), bounds[""].Dy()
+	if doc.XMLName.Local != "svg" || doc.attr("width") != strconv.Itoa(2*w) || doc.attr("height") != strconv.Itoa(2*h) ||
+		doc.attr("viewBox") != "0 0 "+strconv.Itoa(w)+" "+strconv.Itoa(h) {
+		t.Errorf("unexpected svg element %v", doc.Attrs)
+	}
+
+	rects := map[string]bool{}
+	for _, r := range doc.all("rect") {
+		rects[r.attr("x")+","+r.attr("y")+","+r.attr("width")+","+r.attr("height")+","+r.attr("fill")] = true
+	}
+	rectKey := func(r image.Rectangle, fill string) string {
+		return strconv.Itoa(r.Min.X) + "," + strconv.Itoa(r.Min.Y) + "," + strconv.Itoa(r.Dx()) + "," + strconv.Itoa(r.Dy()) + "," + fill
+	}
+	if !rects[rectKey(bounds[""], "#FFFFFF")] {
+		t.Errorf("expected a white background")
+	}
+	if !rects[rectKey(bounds["B2"], "#FF0000")] {
+		t.Errorf("expected a red rectangle over B2")
+	}
+	if !rects[rectKey(bounds["A5"], "#FFFF00")] {
+		t.Errorf("expected a yellow rectangle over the merged cells A5:B5")
+	}
+
+	clips := map[string]bool{}
+	for _, c := range doc.all("clipPath") {
+		clips["url(#"+c.attr("id")+")"] = true
+	}
+	texts := map[string]svgElement{}
+	for _, txt := range doc.all("text") {
+		texts[txt.Text] = txt
+		if !clips[txt.attr("clip-path")] {
+			t.Errorf("expected the clip path of %q to be defined", txt.Text)
+		}
+	}
+	if txt, ok := texts["ab"]; !ok || txt.attr("textLength") == "" {
+		t.Errorf("expected the text of A1 with its measured length")
+	} else if x, _ := strconv.ParseFloat(txt.attr("x"), 64); x >= float64(center(bounds["A1"]).X) {
+		t.Errorf("expected the text of A1 on its left, got x=%v", x)
+	}
+	if txt, ok := texts["1"]; !ok {
+		t.Errorf("expected the number of C1")
+	} else if x, _ := strconv.ParseFloat(txt.attr("x"), 64); x <= float64(center(bounds["C1"]).X) {
+		t.Errorf("expected the number of C1 on its right, got x=%v", x)
+	}
+}
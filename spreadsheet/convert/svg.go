@@ -0,0 +1,126 @@
+package convert
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// svgPainter writes the shapes drawn as SVG elements.
+type svgPainter struct {
+	buf   bytes.Buffer
+	defs  bytes.Buffer
+	clips map[rect]string
+	fc    *fontCache
+	size  image.Rectangle
+	scale float64
+}
+
+func newSVGPainter(size image.Rectangle, scale float64) *svgPainter {
+	return &svgPainter{clips: map[rect]string{}, fc: newFontCache(), size: size, scale: scale}
+}
+
+func (sp *svgPainter) fonts() *fontCache { return sp.fc }
+
+// bytes returns the SVG document.
+func (sp *svgPainter) bytes() []byte {
+	out := bytes.Buffer{}
+	w, h := sp.size.Dx(), sp.size.Dy()
+	fmt.Fprintf(&out, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%s\" height=\"%s\" viewBox=\"0 0 %d %d\">\n",
+		svgNumber(float64(w)*sp.scale), svgNumber(float64(h)*sp.scale), w, h)
+	if sp.defs.Len() > 0 {
+		out.WriteString("<defs>\n")
+		out.Write(sp.defs.Bytes())
+		out.WriteString("</defs>\n")
+	}
+	out.Write(sp.buf.Bytes())
+	out.WriteString("</svg>\n")
+	return out.Bytes()
+}
+
+func svgNumber(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+
+func svgColor(c color.RGBA) string { return fmt.Sprintf("#%02X%02X%02X", c.R, c.G, c.B) }
+
+func (sp *svgPainter) fillRect(r rect, c color.RGBA) {
+	fmt.Fprintf(&sp.buf, "<rect x=\"%s\" y=\"%s\" width=\"%s\" height=\"%s\" fill=\"%s\" shape-rendering=\"crispEdges\"/>\n",
+		svgNumber(r.x), svgNumber(r.y), svgNumber(r.w), svgNumber(r.h), svgColor(c))
+}
+
+func (sp *svgPainter) fillPolygon(pts []point, c color.RGBA) {
+	if len(pts) < 3 {
+		return
+	}
+	d := make([]string, 0, len(pts))
+	for _, pt := range pts {
+		d = append(d, svgNumber(pt.x)+","+svgNumber(pt.y))
+	}
+	fmt.Fprintf(&sp.buf, "<polygon points=\"%s\" fill=\"%s\"/>\n", strings.Join(d, " "), svgColor(c))
+}
+
+func (sp *svgPainter) line(a, b point, width float64, dash []float64, c color.RGBA) {
+	fmt.Fprintf(&sp.buf, "<line x1=\"%s\" y1=\"%s\" x2=\"%s\" y2=\"%s\" stroke=\"%s\" stroke-width=\"%s\"",
+		svgNumber(a.x), svgNumber(a.y), svgNumber(b.x), svgNumber(b.y), svgColor(c), svgNumber(width))
+	if len(dash) > 0 {
+		d := make([]string, 0, len(dash))
+		for _, v := range dash {
+			d = append(d, svgNumber(v*width))
+		}
+		fmt.Fprintf(&sp.buf, " stroke-dasharray=\"%s\"", strings.Join(d, " "))
+	}
+	if a.x == b.x || a.y == b.y {
+		sp.buf.WriteString(" shape-rendering=\"crispEdges\"")
+	}
+	sp.buf.WriteString("/>\n")
+}
+
+// clip returns the ID of a clip path of an area.
+func (sp *svgPainter) clip(r rect) string {
+	if id, ok := sp.clips[r]; ok {
+		return id
+	}
+	id := "c" + strconv.Itoa(len(sp.clips))
+	sp.clips[r] = id
+	fmt.Fprintf(&sp.defs, "<clipPath id=\"%s\"><rect x=\"%s\" y=\"%s\" width=\"%s\" height=\"%s\"/></clipPath>\n",
+		id, svgNumber(r.x), svgNumber(r.y), svgNumber(r.w), svgNumber(r.h))
+	return id
+}
+
+func (sp *svgPainter) text(p point, s string, f textFace, c color.RGBA, clip rect) {
+	generic := "sans-serif"
+	if isMonospace(f.family) {
+		generic = "monospace"
+	}
+	fmt.Fprintf(&sp.buf, "<text x=\"%s\" y=\"%s\" clip-path=\"url(#%s)\" font-family=\"'%s',%s\" font-size=\"%s\" fill=\"%s\"",
+		svgNumber(p.x), svgNumber(p.y), sp.clip(clip), html.EscapeString(strings.ReplaceAll(f.family, "'", "")), generic,
+		svgNumber(f.size*96/72), svgColor(c))
+	if f.bold {
+		sp.buf.WriteString(" font-weight=\"bold\"")
+	}
+	if f.italic {
+		sp.buf.WriteString(" font-style=\"italic\"")
+	}
+	// fix the width to the measured one so the text lines up with the other
+	// shapes whatever font the viewer uses
+	if w := sp.fc.measure(f, s); w > 0 {
+		fmt.Fprintf(&sp.buf, " textLength=\"%s\" lengthAdjust=\"spacingAndGlyphs\"", svgNumber(w))
+	}
+	sp.buf.WriteString(" xml:space=\"preserve\">" + html.EscapeString(s) + "</text>\n")
+}
+
+func (sp *svgPainter) picture(r rect, data []byte, format string) {
+	format = strings.ToLower(format)
+	switch format {
+	case "jpg":
+		format = "jpeg"
+	case "svg":
+		format = "svg+xml"
+	}
+	fmt.Fprintf(&sp.buf, "<image x=\"%s\" y=\"%s\" width=\"%s\" height=\"%s\" preserveAspectRatio=\"none\" href=\"data:image/%s;base64,%s\"/>\n",
+		svgNumber(r.x), svgNumber(r.y), svgNumber(r.w), svgNumber(r.h), format, base64.StdEncoding.EncodeToString(data))
+}
@@ -0,0 +1,962 @@
+package spreadsheet
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/yaklabco/unioffice/v2"
+	"github.com/yaklabco/unioffice/v2/schema/soo/sml"
+	"github.com/yaklabco/unioffice/v2/spreadsheet/reference"
+)
+
+// ChangeType is the kind of a change found by Diff.
+type ChangeType byte
+
+// ChangeType constants.
+const (
+	ChangeAdded ChangeType = iota
+	ChangeRemoved
+	ChangeModified
+	ChangeRenamed
+)
+
+func (t ChangeType) String() string {
+	switch t {
+	case ChangeAdded:
+		return "added"
+	case ChangeRemoved:
+		return "removed"
+	case ChangeModified:
+		return "modified"
+	case ChangeRenamed:
+		return "renamed"
+	}
+	return fmt.Sprintf("ChangeType(%d)", t)
+}
+
+// ChangeObject is the kind of object a change applies to.
+type ChangeObject byte
+
+// ChangeObject constants.
+const (
+	ChangeObjectSheet ChangeObject = iota
+	ChangeObjectRow
+	ChangeObjectColumn
+	ChangeObjectCell
+	ChangeObjectDefinedName
+	ChangeObjectMergedCell
+	ChangeObjectValidation
+)
+
+func (o ChangeObject) String() string {
+	switch o {
+	case ChangeObjectSheet:
+		return "sheet"
+	case ChangeObjectRow:
+		return "row"
+	case ChangeObjectColumn:
+		return "column"
+	case ChangeObjectCell:
+		return "cell"
+	case ChangeObjectDefinedName:
+		return "defined name"
+	case ChangeObjectMergedCell:
+		return "merged cell"
+	case ChangeObjectValidation:
+		return "validation"
+	}
+	return fmt.Sprintf("ChangeObject(%d)", o)
+}
+
+// Change is a difference between two workbooks.
+type Change struct {
+	Type   ChangeType
+	Object ChangeObject
+	// Sheet is the name of the sheet in the new workbook, or in the old
+	// workbook for removed sheets. It is empty for global defined names.
+	Sheet string
+	// Ref identifies the object: a cell reference, row number, column name,
+	// defined name or cell range in the new workbook. Removed objects use
+	// their position in the old workbook.
+	Ref string
+	// OldRef is the reference of a cell in the old workbook if it moved due
+	// to added or removed rows and columns.
+	OldRef string
+	// Property is the cell property that changed, "value", "formula" or a
+	// style property such as "style.font.bold", or "state" for a sheet that
+	// was hidden or unhidden.
+	Property string
+	// Old and New are the values before and after the change, for renamed
+	// sheets the old and new names.
+	Old, New string
+}
+
+func (c Change) String() string {
+	where := c.Ref
+	if c.Sheet != "" {
+		where = quoteSheetName(c.Sheet) + "!" + c.Ref
+	}
+	switch c.Object {
+	case ChangeObjectSheet:
+		switch c.Type {
+		case ChangeRenamed:
+			return fmt.Sprintf("sheet %q renamed to %q", c.Old, c.New)
+		case ChangeModified:
+			return fmt.Sprintf("sheet %q %s %s %q -> %q", c.Sheet, c.Property, c.Type, c.Old, c.New)
+		}
+		return fmt.Sprintf("sheet %q %s", c.Sheet, c.Type)
+	case ChangeObjectRow, ChangeObjectColumn:
+		return fmt.Sprintf("%s %s %s %s", quoteSheetName(c.Sheet), c.Object, c.Ref, c.Type)
+	case ChangeObjectCell:
+		if c.OldRef != "" {
+			where += " (was " + c.OldRef + ")"
+		}
+		return fmt.Sprintf("%s: %s %s %q -> %q", where, c.Property, c.Type, c.Old, c.New)
+	}
+	if c.Type == ChangeModified {
+		return fmt.Sprintf("%s %s %s %q -> %q", c.Object, where, c.Type, c.Old, c.New)
+	}
+	return fmt.Sprintf("%s %s %s", c.Object, where, c.Type)
+}
+
+// WorkbookDiff is the list of changes between two workbooks.
+type WorkbookDiff struct {
+	Changes []Change
+}
+
+// String returns the changes as text, one per line.
+func (d WorkbookDiff) String() string {
+	b := strings.Builder{}
+	for _, c := range d.Changes {
+		b.WriteString(c.String())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// DiffOptions controls the comparison performed by Diff.
+type DiffOptions struct {
+	// IgnoreStyles skips comparing cell styles.
+	IgnoreStyles bool
+	// IgnoreCachedValues skips comparing the cached values of formula cells.
+	IgnoreCachedValues bool
+	// NoAlignment compares rows and columns by position instead of detecting
+	// added and removed rows and columns.
+	NoAlignment bool
+}
+
+// diffCell is the content of a cell being compared.
+type diffCell struct {
+	value   string
+	formula string
+	xf      uint32
+}
+
+// diffSheet is the content of a sheet being compared.
+type diffSheet struct {
+	s              Sheet
+	cells          map[cellKey]diffCell
+	maxRow, maxCol uint32
+}
+
+func newDiffSheet(s Sheet) *diffSheet {
+	d := &diffSheet{s: s, cells: map[cellKey]diffCell{}}
+	for _, r := range s.X().SheetData.Row {
+		if r.RAttr == nil {
+			continue
+		}
+		for _, x := range r.C {
+			if x.RAttr == nil {
+				continue
+			}
+			cr, err := reference.ParseCellReference(*x.RAttr)
+			if err != nil {
+				continue
+			}
+			c := Cell{s._fgeg, &s, r, x}
+			dc := diffCell{formula: c.GetFormula()}
+			if x.SAttr != nil {
+				dc.xf = *x.SAttr
+			}
+			switch x.TAttr {
+			case sml.ST_CellTypeB:
+				dc.value = "FALSE"
+				if b, _ := c.GetValueAsBool(); b {
+					dc.value = "TRUE"
+				}
+			case sml.ST_CellTypeS, sml.ST_CellTypeInlineStr, sml.ST_CellTypeStr:
+				dc.value = c.GetString()
+			default:
+				if x.V != nil {
+					dc.value = *x.V
+				}
+			}
+			if dc == (diffCell{}) {
+				continue
+			}
+			k := cellKey{cr.RowIdx, cr.ColumnIdx + 1}
+			d.cells[k] = dc
+			d.maxRow = maxUint32(d.maxRow, k.row)
+			d.maxCol = maxUint32(d.maxCol, k.col)
+		}
+	}
+	return d
+}
+
+// alignment pairs the one based rows or columns of two sheets, zero marks a
+// row or column that only exists on one side.
+type alignment [][2]uint32
+
+// align pairs two sequences of signatures, matching equal runs with a longest
+// common subsequence and pairing the remaining entries between matches by
+// position. Large inputs that differ in the middle are paired by position.
+func align(a, b []string, positional bool) alignment {
+	ret := alignment{}
+	pairGap := func(ai, aj, bi, bj int) {
+		for ai < aj || bi < bj {
+			p := [2]uint32{}
+			if ai < aj {
+				p[0] = uint32(ai + 1)
+				ai++
+			}
+			if bi < bj {
+				p[1] = uint32(bi + 1)
+				bi++
+			}
+			ret = append(ret, p)
+		}
+	}
+	if positional {
+		pairGap(0, len(a), 0, len(b))
+		return ret
+	}
+	pre := 0
+	for pre < len(a) && pre < len(b) && a[pre] == b[pre] {
+		pre++
+	}
+	suf := 0
+	for suf < len(a)-pre && suf < len(b)-pre && a[len(a)-1-suf] == b[len(b)-1-suf] {
+		suf++
+	}
+	pairGap(0, pre, 0, pre)
+	ma, mb := a[pre:len(a)-suf], b[pre:len(b)-suf]
+	if len(ma)*len(mb) > 4000000 {
+		pairGap(pre, len(a)-suf, pre, len(b)-suf)
+	} else {
+		// lcs[i][j] is the length of the common subsequence of ma[i:] and mb[j:]
+		lcs := make([][]int, len(ma)+1)
+		for i := range lcs {
+			lcs[i] = make([]int, len(mb)+1)
+		}
+		for i := len(ma) - 1; i >= 0; i-- {
+			for j := len(mb) - 1; j >= 0; j-- {
+				if ma[i] == mb[j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else if lcs[i+1][j] >= lcs[i][j+1] {
+					lcs[i][j] = lcs[i+1][j]
+				} else {
+					lcs[i][j] = lcs[i][j+1]
+				}
+			}
+		}
+		i, j, gi, gj := 0, 0, 0, 0
+		for i < len(ma) && j < len(mb) {
+			switch {
+			case ma[i] == mb[j]:
+				pairGap(pre+gi, pre+i, pre+gj, pre+j)
+				ret = append(ret, [2]uint32{uint32(pre + i + 1), uint32(pre + j + 1)})
+				i++
+				j++
+				gi, gj = i, j
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				i++
+			default:
+				j++
+			}
+		}
+		pairGap(pre+gi, len(a)-suf, pre+gj, len(b)-suf)
+	}
+	pairGap(len(a)-suf, len(a), len(b)-suf, len(b))
+	return ret
+}
+
+// mapping returns a function translating an old position to the new side.
+// Positions past the aligned content are shifted by the difference in length.
+func (al alignment) mapping() func(uint32) (uint32, bool) {
+	m := map[uint32]uint32{}
+	var lastA, lastB uint32
+	for _, p := range al {
+		if p[0] != 0 && p[1] != 0 {
+			m[p[0]] = p[1]
+		}
+		lastA, lastB = maxUint32(lastA, p[0]), maxUint32(lastB, p[1])
+	}
+	return func(pos uint32) (uint32, bool) {
+		if pos > lastA {
+			return pos - lastA + lastB, true
+		}
+		n, ok := m[pos]
+		return n, ok
+	}
+}
+
+func (d *diffSheet) rowSignatures() []string {
+	sigs := make([]string, d.maxRow)
+	rows := make([][]string, d.maxRow)
+	keys := make([]cellKey, 0, len(d.cells))
+	for k := range d.cells {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].col < keys[j].col })
+	for _, k := range keys {
+		c := d.cells[k]
+		if c.value == "" && c.formula == "" {
+			continue
+		}
+		rows[k.row-1] = append(rows[k.row-1], strconv.Itoa(int(k.col))+"\x01"+c.value+"\x01"+c.formula)
+	}
+	for i, r := range rows {
+		sigs[i] = strings.Join(r, "\x00")
+	}
+	return sigs
+}
+
+// columnSignatures returns the signature of each column over the rows paired
+// by the row alignment, so added rows don't disturb column matching.
+func (d *diffSheet) columnSignatures(rows alignment, side int) []string {
+	sigs := make([]string, d.maxCol)
+	for col := uint32(1); col <= d.maxCol; col++ {
+		parts := []string{}
+		for _, p := range rows {
+			if p[0] == 0 || p[1] == 0 {
+				continue
+			}
+			c := d.cells[cellKey{p[side], col}]
+			parts = append(parts, c.value+"\x01"+c.formula)
+		}
+		sigs[col-1] = strings.Join(parts, "\x00")
+	}
+	return sigs
+}
+
+// differ compares two workbooks.
+type differ struct {
+	a, b    *Workbook
+	opts    DiffOptions
+	changes []Change
+	styles  map[*Workbook]map[uint32]map[string]string
+}
+
+// Diff compares two workbooks, returning the changes that turn a into b.
+// Sheets are matched by name, then by sheet ID or content to detect renamed
+// sheets. Rows and columns are aligned by content to detect added and removed
+// rows and columns before comparing cell values, formulas and styles. Styles
+// are compared by their resolved properties rather than by style index.
+func Diff(a, b *Workbook, opts DiffOptions) WorkbookDiff {
+	d := &differ{a: a, b: b, opts: opts, styles: map[*Workbook]map[uint32]map[string]string{}}
+	pairs := d.matchSheets()
+	for _, p := range pairs {
+		switch {
+		case p[0] == nil:
+			d.add(Change{Type: ChangeAdded, Object: ChangeObjectSheet, Sheet: p[1].s.Name()})
+		case p[1] == nil:
+			d.add(Change{Type: ChangeRemoved, Object: ChangeObjectSheet, Sheet: p[0].s.Name()})
+		default:
+			if p[0].s.Name() != p[1].s.Name() {
+				d.add(Change{Type: ChangeRenamed, Object: ChangeObjectSheet, Sheet: p[1].s.Name(), Old: p[0].s.Name(), New: p[1].s.Name()})
+			}
+			if old, new := sheetState(p[0].s), sheetState(p[1].s); old != new {
+				d.add(Change{Type: ChangeModified, Object: ChangeObjectSheet, Sheet: p[1].s.Name(), Property: "state", Old: old, New: new})
+			}
+			d.diffSheets(p[0], p[1])
+		}
+	}
+	d.diffDefinedNames(pairs)
+	return WorkbookDiff{Changes: d.changes}
+}
+
+func (d *differ) add(c Change) { d.changes = append(d.changes, c) }
+
+// sheetState returns the visibility of a sheet, "visible", "hidden" or
+// "veryHidden".
+func sheetState(s Sheet) string {
+	if s._abea.StateAttr == sml.ST_SheetStateUnset {
+		return sml.ST_SheetStateVisible.String()
+	}
+	return s._abea.StateAttr.String()
+}
+
+// matchSheets pairs the sheets of both workbooks, in the order of the new
+// workbook followed by removed sheets.
+func (d *differ) matchSheets() [][2]*diffSheet {
+	as, bs := d.a.AllSheets(), d.b.AllSheets()
+	matched := make([]*diffSheet, len(bs))
+	used := make([]bool, len(as))
+	match := func(eq func(x, y Sheet) bool) {
+		for j, sb := range bs {
+			if matched[j] != nil {
+				continue
+			}
+			for i, sa := range as {
+				if !used[i] && eq(sa, sb) {
+					matched[j], used[i] = newDiffSheet(sa), true
+					break
+				}
+			}
+		}
+	}
+	match(func(x, y Sheet) bool { return x.Name() == y.Name() })
+	match(func(x, y Sheet) bool { return x._abea.SheetIdAttr == y._abea.SheetIdAttr })
+	pairs := make([][2]*diffSheet, len(bs))
+	for j, sb := range bs {
+		pairs[j] = [2]*diffSheet{matched[j], newDiffSheet(sb)}
+	}
+	// remaining sheets are matched by the share of equal cells
+	for j := range pairs {
+		if pairs[j][0] != nil {
+			continue
+		}
+		best, bestScore := -1, 0.5
+		for i, sa := range as {
+			if used[i] {
+				continue
+			}
+			if score := sheetSimilarity(newDiffSheet(sa), pairs[j][1]); score >= bestScore {
+				best, bestScore = i, score
+			}
+		}
+		if best >= 0 {
+			pairs[j][0], used[best] = newDiffSheet(as[best]), true
+		}
+	}
+	for i, sa := range as {
+		if !used[i] {
+			pairs = append(pairs, [2]*diffSheet{newDiffSheet(sa), nil})
+		}
+	}
+	return pairs
+}
+
+func sheetSimilarity(a, b *diffSheet) float64 {
+	total, same := 0, 0
+	for k, c := range a.cells {
+		total++
+		if o, ok := b.cells[k]; ok && o.value == c.value && o.formula == c.formula {
+			same++
+		}
+	}
+	for k := range b.cells {
+		if _, ok := a.cells[k]; !ok {
+			total++
+		}
+	}
+	if total == 0 {
+		return 1
+	}
+	return float64(same) / float64(total)
+}
+
+func (d *differ) diffSheets(a, b *diffSheet) {
+	name := b.s.Name()
+	rows := align(a.rowSignatures(), b.rowSignatures(), d.opts.NoAlignment)
+	cols := align(a.columnSignatures(rows, 0), b.columnSignatures(rows, 1), d.opts.NoAlignment)
+	for _, p := range rows {
+		switch {
+		case p[0] == 0:
+			d.add(Change{Type: ChangeAdded, Object: ChangeObjectRow, Sheet: name, Ref: strconv.Itoa(int(p[1]))})
+		case p[1] == 0:
+			d.add(Change{Type: ChangeRemoved, Object: ChangeObjectRow, Sheet: name, Ref: strconv.Itoa(int(p[0]))})
+		}
+	}
+	for _, p := range cols {
+		switch {
+		case p[0] == 0:
+			d.add(Change{Type: ChangeAdded, Object: ChangeObjectColumn, Sheet: name, Ref: reference.IndexToColumn(p[1] - 1)})
+		case p[1] == 0:
+			d.add(Change{Type: ChangeRemoved, Object: ChangeObjectColumn, Sheet: name, Ref: reference.IndexToColumn(p[0] - 1)})
+		}
+	}
+	for _, r := range rows {
+		if r[0] == 0 || r[1] == 0 {
+			continue
+		}
+		for _, c := range cols {
+			if c[0] == 0 || c[1] == 0 {
+				continue
+			}
+			d.diffCells(name, a, b, cellKey{r[0], c[0]}, cellKey{r[1], c[1]})
+		}
+	}
+	rowMap, colMap := rows.mapping(), cols.mapping()
+	d.diffMerges(name, a, b, rowMap, colMap)
+	d.diffValidations(name, a, b, rowMap, colMap)
+}
+
+func keyRef(k cellKey) string { return reference.IndexToColumn(k.col-1) + strconv.Itoa(int(k.row)) }
+
+func valueChange(old, new string) ChangeType {
+	switch {
+	case old == "":
+		return ChangeAdded
+	case new == "":
+		return ChangeRemoved
+	}
+	return ChangeModified
+}
+
+func (d *differ) diffCells(sheet string, a, b *diffSheet, ka, kb cellKey) {
+	ca, oka := a.cells[ka]
+	cb, okb := b.cells[kb]
+	if !oka && !okb {
+		return
+	}
+	ref := keyRef(kb)
+	oldRef := ""
+	if ka != kb {
+		oldRef = keyRef(ka)
+	}
+	change := func(prop, old, new string) {
+		d.add(Change{Type: valueChange(old, new), Object: ChangeObjectCell, Sheet: sheet, Ref: ref, OldRef: oldRef, Property: prop, Old: old, New: new})
+	}
+	if ca.formula != cb.formula {
+		change("formula", ca.formula, cb.formula)
+	}
+	hasFormula := ca.formula != "" || cb.formula != ""
+	if ca.value != cb.value && !(hasFormula && d.opts.IgnoreCachedValues) && !(ca.formula != "" && cb.formula != "" && ca.formula != cb.formula) {
+		change("value", ca.value, cb.value)
+	}
+	if d.opts.IgnoreStyles {
+		return
+	}
+	sa, sb := d.styleProperties(d.a, ca.xf), d.styleProperties(d.b, cb.xf)
+	props := []string{}
+	for p := range sa {
+		props = append(props, p)
+	}
+	for p := range sb {
+		if _, ok := sa[p]; !ok {
+			props = append(props, p)
+		}
+	}
+	sort.Strings(props)
+	for _, p := range props {
+		if sa[p] != sb[p] {
+			change("style."+p, sa[p], sb[p])
+		}
+	}
+}
+
+// styleProperties returns the resolved properties of a cell format, keyed by
+// property name. Unset and default properties are omitted.
+func (d *differ) styleProperties(wb *Workbook, id uint32) map[string]string {
+	cache := d.styles[wb]
+	if cache == nil {
+		cache = map[uint32]map[string]string{}
+		d.styles[wb] = cache
+	}
+	if p, ok := cache[id]; ok {
+		return p
+	}
+	p := map[string]string{}
+	cache[id] = p
+	ss := wb.StyleSheet.X()
+	if ss.CellXfs == nil || int(id) >= len(ss.CellXfs.Xf) {
+		return p
+	}
+	xf := ss.CellXfs.Xf[id]
+	set := func(k, v string) {
+		if v != "" {
+			p[k] = v
+		}
+	}
+	color := func(c *sml.CT_Color) string {
+		rgb, _ := wb.resolveColor(c)
+		return rgb
+	}
+	boolProp := func(b *sml.CT_BooleanProperty) string {
+		if b != nil && (b.ValAttr == nil || *b.ValAttr) {
+			return "true"
+		}
+		return ""
+	}
+	if xf.NumFmtIdAttr != nil && *xf.NumFmtIdAttr != 0 {
+		set("numberFormat", wb.StyleSheet.GetNumberFormat(*xf.NumFmtIdAttr).GetFormat())
+	}
+	if ss.Fonts != nil && len(ss.Fonts.Font) > 0 {
+		// fonts without a name or size use those of the default font
+		fonts := []*sml.CT_Font{ss.Fonts.Font[0]}
+		if xf.FontIdAttr != nil && *xf.FontIdAttr != 0 && int(*xf.FontIdAttr) < len(ss.Fonts.Font) {
+			fonts = []*sml.CT_Font{ss.Fonts.Font[*xf.FontIdAttr], ss.Fonts.Font[0]}
+		}
+		for _, f := range fonts {
+			for _, fc := range f.FontChoice {
+				switch {
+				case fc.Name != nil && p["font.name"] == "":
+					set("font.name", fc.Name.ValAttr)
+				case fc.Sz != nil && p["font.size"] == "":
+					set("font.size", strconv.FormatFloat(fc.Sz.ValAttr, 'f', -1, 64))
+				}
+			}
+		}
+		for _, fc := range fonts[0].FontChoice {
+			switch {
+			case fc.B != nil:
+				set("font.bold", boolProp(fc.B))
+			case fc.I != nil:
+				set("font.italic", boolProp(fc.I))
+			case fc.Strike != nil:
+				set("font.strike", boolProp(fc.Strike))
+			case fc.U != nil:
+				if fc.U.ValAttr != sml.ST_UnderlineValuesNone {
+					u := fc.U.ValAttr
+					if u == sml.ST_UnderlineValuesUnset {
+						u = sml.ST_UnderlineValuesSingle
+					}
+					set("font.underline", u.String())
+				}
+			case fc.Color != nil:
+				set("font.color", color(fc.Color))
+			}
+		}
+	}
+	if xf.FillIdAttr != nil && ss.Fills != nil && int(*xf.FillIdAttr) < len(ss.Fills.Fill) {
+		if fc := ss.Fills.Fill[*xf.FillIdAttr].FillChoice; fc != nil && fc.PatternFill != nil {
+			pf := fc.PatternFill
+			if pf.PatternTypeAttr != sml.ST_PatternTypeUnset && pf.PatternTypeAttr != sml.ST_PatternTypeNone {
+				set("fill.pattern", pf.PatternTypeAttr.String())
+				set("fill.color", color(pf.FgColor))
+				if pf.PatternTypeAttr != sml.ST_PatternTypeSolid {
+					set("fill.background", color(pf.BgColor))
+				}
+			}
+		}
+	}
+	if xf.BorderIdAttr != nil && ss.Borders != nil && int(*xf.BorderIdAttr) < len(ss.Borders.Border) {
+		b := ss.Borders.Border[*xf.BorderIdAttr]
+		for _, side := range []struct {
+			name string
+			pr   *sml.CT_BorderPr
+		}{{"left", b.Left}, {"right", b.Right}, {"top", b.Top}, {"bottom", b.Bottom}, {"diagonal", b.Diagonal}} {
+			if side.pr == nil || side.pr.StyleAttr == sml.ST_BorderStyleUnset || side.pr.StyleAttr == sml.ST_BorderStyleNone {
+				continue
+			}
+			v := side.pr.StyleAttr.String()
+			if c := color(side.pr.Color); c != "" {
+				v += " " + c
+			}
+			set("border."+side.name, v)
+		}
+	}
+	if a := xf.Alignment; a != nil {
+		if a.HorizontalAttr != sml.ST_HorizontalAlignmentUnset && a.HorizontalAttr != sml.ST_HorizontalAlignmentGeneral {
+			set("alignment.horizontal", a.HorizontalAttr.String())
+		}
+		if a.VerticalAttr != sml.ST_VerticalAlignmentUnset && a.VerticalAttr != sml.ST_VerticalAlignmentBottom {
+			set("alignment.vertical", a.VerticalAttr.String())
+		}
+		if a.WrapTextAttr != nil && *a.WrapTextAttr {
+			set("alignment.wrap", "true")
+		}
+		if a.ShrinkToFitAttr != nil && *a.ShrinkToFitAttr {
+			set("alignment.shrink", "true")
+		}
+		if a.IndentAttr != nil && *a.IndentAttr != 0 {
+			set("alignment.indent", strconv.Itoa(int(*a.IndentAttr)))
+		}
+		if a.TextRotationAttr != nil && *a.TextRotationAttr != 0 {
+			set("alignment.rotation", strconv.Itoa(int(*a.TextRotationAttr)))
+		}
+	}
+	if pr := xf.Protection; pr != nil {
+		if pr.LockedAttr != nil && !*pr.LockedAttr {
+			set("protection.locked", "false")
+		}
+		if pr.HiddenAttr != nil && *pr.HiddenAttr {
+			set("protection.hidden", "true")
+		}
+	}
+	return p
+}
+
+// mapRange translates an old range to the new sheet, returning false if any
+// of its corners was removed.
+func mapRange(ref string, rows, cols func(uint32) (uint32, bool)) (string, bool) {
+	from, to, err := reference.ParseRangeReference(ref)
+	if err != nil {
+		cr, err := reference.ParseCellReference(ref)
+		if err != nil {
+			return ref, false
+		}
+		from, to = cr, cr
+	}
+	fr, ok1 := rows(from.RowIdx)
+	tr, ok2 := rows(to.RowIdx)
+	fc, ok3 := cols(from.ColumnIdx + 1)
+	tc, ok4 := cols(to.ColumnIdx + 1)
+	if !ok1 || !ok2 || !ok3 || !ok4 {
+		return ref, false
+	}
+	a := keyRef(cellKey{fr, fc})
+	if from == to {
+		return a, true
+	}
+	return a + ":" + keyRef(cellKey{tr, tc}), true
+}
+
+// diffSets reports the keys only found on one side, and the keys whose
+// values differ.
+func (d *differ) diffSets(obj ChangeObject, sheet string, old, new map[string]string) {
+	keys := []string{}
+	for k := range old {
+		keys = append(keys, k)
+	}
+	for k := range new {
+		if _, ok := old[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		o, inOld := old[k]
+		n, inNew := new[k]
+		switch {
+		case !inOld:
+			d.add(Change{Type: ChangeAdded, Object: obj, Sheet: sheet, Ref: k, New: n})
+		case !inNew:
+			d.add(Change{Type: ChangeRemoved, Object: obj, Sheet: sheet, Ref: k, Old: o})
+		case o != n:
+			d.add(Change{Type: ChangeModified, Object: obj, Sheet: sheet, Ref: k, Old: o, New: n})
+		}
+	}
+}
+
+func (d *differ) diffMerges(sheet string, a, b *diffSheet, rows, cols func(uint32) (uint32, bool)) {
+	old, new := map[string]string{}, map[string]string{}
+	for _, m := range a.s.MergedCells() {
+		ref, _ := mapRange(m.Reference(), rows, cols)
+		old[ref] = ""
+	}
+	for _, m := range b.s.MergedCells() {
+		new[m.Reference()] = ""
+	}
+	d.diffSets(ChangeObjectMergedCell, sheet, old, new)
+}
+
+func validationSignature(dv *sml.CT_DataValidation) string {
+	parts := []string{dv.TypeAttr.String(), dv.OperatorAttr.String()}
+	for _, f := range []*string{dv.Formula1, dv.Formula2} {
+		if f != nil {
+			parts = append(parts, *f)
+		}
+	}
+	if dv.AllowBlankAttr != nil && *dv.AllowBlankAttr {
+		parts = append(parts, "allowBlank")
+	}
+	return strings.Join(parts, " ")
+}
+
+func (d *differ) diffValidations(sheet string, a, b *diffSheet, rows, cols func(uint32) (uint32, bool)) {
+	collect := func(s *diffSheet, translate bool) map[string]string {
+		m := map[string]string{}
+		if s.s.X().DataValidations == nil {
+			return m
+		}
+		for _, dv := range s.s.X().DataValidations.DataValidation {
+			refs := []string{}
+			for _, r := range dv.SqrefAttr {
+				if translate {
+					r, _ = mapRange(r, rows, cols)
+				}
+				refs = append(refs, r)
+			}
+			m[strings.Join(refs, " ")] = validationSignature(dv)
+		}
+		return m
+	}
+	d.diffSets(ChangeObjectValidation, sheet, collect(a, true), collect(b, false))
+}
+
+func (d *differ) diffDefinedNames(pairs [][2]*diffSheet) {
+	// scoped names are keyed by the name of the sheet in the new workbook
+	renamed := map[string]string{}
+	for _, p := range pairs {
+		if p[0] != nil && p[1] != nil {
+			renamed[p[0].s.Name()] = p[1].s.Name()
+		}
+	}
+	collect := func(wb *Workbook, names map[string]string) map[string]map[string]string {
+		sheets := wb.AllSheets()
+		m := map[string]map[string]string{}
+		for _, dn := range wb.DefinedNames() {
+			scope := ""
+			if id := dn.X().LocalSheetIdAttr; id != nil && int(*id) < len(sheets) {
+				scope = sheets[*id].Name()
+				if n, ok := names[scope]; ok {
+					scope = n
+				}
+			}
+			if m[scope] == nil {
+				m[scope] = map[string]string{}
+			}
+			m[scope][dn.Name()] = dn.Content()
+		}
+		return m
+	}
+	old, new := collect(d.a, renamed), collect(d.b, nil)
+	scopes := []string{}
+	for s := range old {
+		scopes = append(scopes, s)
+	}
+	for s := range new {
+		if _, ok := old[s]; !ok {
+			scopes = append(scopes, s)
+		}
+	}
+	sort.Strings(scopes)
+	for _, s := range scopes {
+		d.diffSets(ChangeObjectDefinedName, s, old[s], new[s])
+	}
+}
+
+// Annotate highlights the changes in wb, which should be the new workbook
+// passed to Diff. Changed cells and cells of added rows and columns are
+// filled, with a note listing the changes of each cell, and a sheet listing
+// all changes is added. Notes left by a previous Annotate are replaced, cells
+// with other notes don't get one.
+func (d WorkbookDiff) Annotate(wb *Workbook) error {
+	const (
+		addedColor    = "FFC6EFCE"
+		modifiedColor = "FFFFEB9C"
+	)
+	xfs := map[[2]string]uint32{}
+	highlight := func(c Cell, rgb string) {
+		ss := wb.StyleSheet.X()
+		orig := uint32(0)
+		if c.X().SAttr != nil {
+			orig = *c.X().SAttr
+		}
+		key := [2]string{strconv.Itoa(int(orig)), rgb}
+		if id, ok := xfs[key]; ok {
+			c.X().SAttr = unioffice.Uint32(id)
+			return
+		}
+		if ss.Fills == nil {
+			ss.Fills = sml.NewCT_Fills()
+		}
+		fill := sml.NewCT_Fill()
+		fill.FillChoice = sml.NewCT_FillChoice()
+		fill.FillChoice.PatternFill = sml.NewCT_PatternFill()
+		fill.FillChoice.PatternFill.PatternTypeAttr = sml.ST_PatternTypeSolid
+		fill.FillChoice.PatternFill.FgColor = sml.NewCT_Color()
+		fill.FillChoice.PatternFill.FgColor.RgbAttr = unioffice.String(rgb)
+		ss.Fills.Fill = append(ss.Fills.Fill, fill)
+		ss.Fills.CountAttr = unioffice.Uint32(uint32(len(ss.Fills.Fill)))
+
+		xf := sml.NewCT_Xf()
+		if ss.CellXfs != nil && int(orig) < len(ss.CellXfs.Xf) {
+			cp := *ss.CellXfs.Xf[orig]
+			xf = &cp
+		}
+		if ss.CellXfs == nil {
+			ss.CellXfs = sml.NewCT_CellXfs()
+		}
+		xf.FillIdAttr = unioffice.Uint32(uint32(len(ss.Fills.Fill) - 1))
+		xf.ApplyFillAttr = unioffice.Bool(true)
+		ss.CellXfs.Xf = append(ss.CellXfs.Xf, xf)
+		ss.CellXfs.CountAttr = unioffice.Uint32(uint32(len(ss.CellXfs.Xf)))
+		id := uint32(len(ss.CellXfs.Xf) - 1)
+		xfs[key] = id
+		c.X().SAttr = unioffice.Uint32(id)
+	}
+
+	notes := map[string]map[string][]string{}
+	order := []string{}
+	for _, ch := range d.Changes {
+		s, err := wb.sheetByName(ch.Sheet)
+		if err != nil {
+			continue
+		}
+		switch ch.Object {
+		case ChangeObjectCell:
+			if ch.Type == ChangeAdded && ch.Property == "value" {
+				highlight(s.Cell(ch.Ref), addedColor)
+			} else {
+				highlight(s.Cell(ch.Ref), modifiedColor)
+			}
+			if notes[ch.Sheet] == nil {
+				notes[ch.Sheet] = map[string][]string{}
+			}
+			if _, ok := notes[ch.Sheet][ch.Ref]; !ok {
+				order = append(order, ch.Sheet+"!"+ch.Ref)
+			}
+			notes[ch.Sheet][ch.Ref] = append(notes[ch.Sheet][ch.Ref], fmt.Sprintf("%s: %q -> %q", ch.Property, ch.Old, ch.New))
+		case ChangeObjectRow, ChangeObjectColumn:
+			if ch.Type != ChangeAdded {
+				continue
+			}
+			for _, r := range s.X().SheetData.Row {
+				for _, x := range r.C {
+					if x.RAttr == nil {
+						continue
+					}
+					cr, err := reference.ParseCellReference(*x.RAttr)
+					if err != nil {
+						continue
+					}
+					if (ch.Object == ChangeObjectRow && strconv.Itoa(int(cr.RowIdx)) == ch.Ref) ||
+						(ch.Object == ChangeObjectColumn && cr.Column == ch.Ref) {
+						highlight(Cell{wb, &s, r, x}, addedColor)
+					}
+				}
+			}
+		}
+	}
+	for _, key := range order {
+		i := strings.LastIndex(key, "!")
+		sheet, ref := key[:i], key[i+1:]
+		s, _ := wb.sheetByName(sheet)
+		text := strings.Join(notes[sheet][ref], "\n")
+		if c, ok := s.note(ref); ok {
+			if r := c.X().Text; c.Author() == "Diff" && r != nil && len(r.R) > 0 {
+				r.R[len(r.R)-1].T = "\r\n" + text + "\r\n"
+			}
+			continue
+		}
+		if err := s.Comments().AddCommentWithStyle(ref, "Diff", text); err != nil {
+			return err
+		}
+	}
+
+	name := "Changes"
+	for i := 2; ; i++ {
+		if _, err := wb.sheetByName(name); err != nil {
+			break
+		}
+		name = fmt.Sprintf("Changes %d", i)
+	}
+	s := wb.AddSheet()
+	s.SetName(name)
+	header := s.AddRow()
+	for _, h := range []string{"Change", "Object", "Sheet", "Reference", "Old Reference", "Property", "Old", "New"} {
+		header.AddCell().SetString(h)
+	}
+	for _, ch := range d.Changes {
+		r := s.AddRow()
+		for _, v := range []string{ch.Type.String(), ch.Object.String(), ch.Sheet, ch.Ref, ch.OldRef, ch.Property, ch.Old, ch.New} {
+			r.AddCell().SetString(v)
+		}
+	}
+	return nil
+}
+
+// note returns the note of the cell at ref.
+func (s *Sheet) note(ref string) (Comment, bool) {
+	for _, c := range s.Comments().Comments() {
+		if strings.EqualFold(c.CellReference(), ref) {
+			return c, true
+		}
+	}
+	return Comment{}, false
+}
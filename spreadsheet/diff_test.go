@@ -0,0 +1,147 @@
+package spreadsheet
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yaklabco/unioffice/v2/schema/soo/sml"
+)
+
+func diffTestWorkbook() *Workbook {
+	wb := New()
+	sheet := wb.AddSheet()
+	for i, v := range []string{"a", "b", "c", "d"} {
+		row := sheet.AddRow()
+		row.Cell("A").SetString(v)
+		row.Cell("B").SetNumber(float64(i))
+	}
+	sheet.Cell("C1").SetFormulaRaw("SUM(B1:B4)")
+	sheet.AddMergedCells("A6", "B6")
+	other := wb.AddSheet()
+	other.SetName("Data")
+	other.Cell("A1").SetString("x")
+	wb.AddDefinedName("Total", "'Sheet 1'!$C$1")
+	return wb
+}
+
+func TestDiff(t *testing.T) {
+	a, b := diffTestWorkbook(), diffTestWorkbook()
+	sheet := b.Sheets()[0]
+	// insert a row before the second row
+	sheet.InsertRow(2)
+	sheet.Cell("A2").SetString("new")
+	sheet.Cell("B4").SetNumber(20)
+	style := b.StyleSheet.AddCellStyle()
+	font := b.StyleSheet.AddFont()
+	font.SetBold(true)
+	style.SetFont(font)
+	sheet.Cell("A5").SetStyle(style)
+	data := b.Sheets()[1]
+	data.SetName("Inputs")
+	b.AddDefinedName("Rate", "Inputs!$A$1")
+
+	d := Diff(a, b, DiffOptions{})
+	text := d.String()
+	for _, exp := range []string{
+		`sheet "Data" renamed to "Inputs"`,
+		`'Sheet 1' row 2 added`,
+		`'Sheet 1'!B4 (was B3): value modified "2" -> "20"`,
+		`'Sheet 1'!A5 (was A4): style.font.bold added "" -> "true"`,
+		`defined name Rate added`,
+	} {
+		if !strings.Contains(text, exp) {
+			t.Errorf("expected diff to contain %q, got\n%s", exp, text)
+		}
+	}
+	for _, unexp := range []string{"merged cell", "'Sheet 1'!A3", "formula"} {
+		if strings.Contains(text, unexp) {
+			t.Errorf("unexpected %q in diff\n%s", unexp, text)
+		}
+	}
+
+	if err := d.Annotate(b); err != nil {
+		t.Fatalf("error annotating: %s", err)
+	}
+	changes, err := b.GetSheet("Changes")
+	if err != nil {
+		t.Fatal("expected changes sheet")
+	}
+	if n := len(changes.Rows()); n != len(d.Changes)+1 {
+		t.Errorf("expected %d rows, got %d", len(d.Changes)+1, n)
+	}
+	if x := sheet.Cell("B4").X(); x.SAttr == nil {
+		t.Error("expected changed cell to be highlighted")
+	}
+	if len(sheet.Comments().Comments()) != 2 {
+		t.Errorf("expected 2 notes, got %d", len(sheet.Comments().Comments()))
+	}
+}
+
+func TestDiffHiddenSheets(t *testing.T) {
+	a, b := diffTestWorkbook(), diffTestWorkbook()
+	a.AddDefinedName("Local", "Data!$A$1").SetLocalSheetID(1)
+	b.AddDefinedName("Local", "Data!$A$2").SetLocalSheetID(1)
+	data := b.AllSheets()[1]
+	data.Cell("A1").SetString("y")
+	if err := data.SetState(sml.ST_SheetStateHidden); err != nil {
+		t.Fatalf("error hiding sheet: %s", err)
+	}
+
+	d := Diff(a, b, DiffOptions{})
+	text := d.String()
+	for _, exp := range []string{
+		`sheet "Data" state modified "visible" -> "hidden"`,
+		`Data!A1: value modified "x" -> "y"`,
+		`defined name Data!Local modified "Data!$A$1" -> "Data!$A$2"`,
+	} {
+		if !strings.Contains(text, exp) {
+			t.Errorf("expected diff to contain %q, got\n%s", exp, text)
+		}
+	}
+	if strings.Contains(text, "removed") || strings.Contains(text, "added") {
+		t.Errorf("expected the hidden sheet to be matched, got\n%s", text)
+	}
+
+	if err := d.Annotate(b); err != nil {
+		t.Fatalf("error annotating: %s", err)
+	}
+	if x := data.Cell("A1").X(); x.SAttr == nil {
+		t.Error("expected the changed cell of the hidden sheet to be highlighted")
+	}
+}
+
+func TestDiffAnnotateTwice(t *testing.T) {
+	a, b := diffTestWorkbook(), diffTestWorkbook()
+	sheet := b.Sheets()[0]
+	sheet.Cell("B2").SetNumber(10)
+	sheet.Cell("B3").SetNumber(30)
+	sheet.Comments().AddCommentWithStyle("B3", "Reviewer", "check this")
+
+	if err := Diff(a, b, DiffOptions{}).Annotate(b); err != nil {
+		t.Fatalf("error annotating: %s", err)
+	}
+	sheet.Cell("B2").SetNumber(11)
+	if err := Diff(a, b, DiffOptions{}).Annotate(b); err != nil {
+		t.Fatalf("error annotating: %s", err)
+	}
+	notes := sheet.Comments().Comments()
+	if len(notes) != 2 {
+		t.Fatalf("expected 2 notes, got %d", len(notes))
+	}
+	for _, c := range notes {
+		text := ""
+		for _, r := range c.X().Text.R {
+			text += r.T
+		}
+		switch c.CellReference() {
+		case "B2":
+			if c.Author() != "Diff" || !strings.Contains(text, `"1" -> "11"`) || strings.Contains(text, `"10"`) {
+				t.Errorf("expected the note of B2 to be replaced, got %q", text)
+			}
+		case "B3":
+			if c.Author() != "Reviewer" || !strings.Contains(text, "check this") {
+				t.Errorf("expected the note of B3 to be kept, got %q", text)
+			}
+		}
+	}
+}
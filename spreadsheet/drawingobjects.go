@@ -0,0 +1,512 @@
+package spreadsheet
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	unioffice "github.com/yaklabco/unioffice/v2"
+	"github.com/yaklabco/unioffice/v2/chart"
+	"github.com/yaklabco/unioffice/v2/common"
+	"github.com/yaklabco/unioffice/v2/schema/schemas.microsoft.com/office/drawing/2014/chartex"
+	"github.com/yaklabco/unioffice/v2/schema/soo/dml"
+	crt "github.com/yaklabco/unioffice/v2/schema/soo/dml/chart"
+	sd "github.com/yaklabco/unioffice/v2/schema/soo/dml/spreadsheetDrawing"
+	"github.com/yaklabco/unioffice/v2/schema/soo/sml"
+)
+
+// SheetHyperlink is a hyperlink attached to a range of cells in a sheet.
+type SheetHyperlink struct {
+	// Reference is the cell or range of cells the hyperlink applies to.
+	Reference string
+	// Target is the external target of the hyperlink, it is empty for links to
+	// locations within the workbook.
+	Target string
+	// Location is the location within the target or the workbook, e.g.
+	// 'Sheet2'!A1 or a defined name.
+	Location string
+	Tooltip  string
+	Display  string
+	X        *sml.CT_Hyperlink
+}
+
+// rels returns the relationships of the sheet.
+func (s *Sheet) rels() (common.Relationships, bool) {
+	for i, ws := range s._fgeg._fbef {
+		if ws == s._bbbe {
+			return s._fgeg._aedf[i], true
+		}
+	}
+	return common.Relationships{}, false
+}
+
+// Hyperlinks returns the hyperlinks of the sheet with their targets resolved.
+func (s *Sheet) Hyperlinks() []SheetHyperlink {
+	if s._bbbe.Hyperlinks == nil {
+		return nil
+	}
+	rels, _ := s.rels()
+	ret := []SheetHyperlink{}
+	for _, hl := range s._bbbe.Hyperlinks.Hyperlink {
+		h := SheetHyperlink{Reference: hl.RefAttr, X: hl}
+		if hl.IdAttr != nil && rels.X() != nil {
+			h.Target = rels.GetTargetByRelId(*hl.IdAttr)
+		}
+		if hl.LocationAttr != nil {
+			h.Location = *hl.LocationAttr
+		}
+		if hl.TooltipAttr != nil {
+			h.Tooltip = *hl.TooltipAttr
+		}
+		if hl.DisplayAttr != nil {
+			h.Display = *hl.DisplayAttr
+		}
+		ret = append(ret, h)
+	}
+	return ret
+}
+
+// RemoveHyperlink removes the hyperlinks applied to a cell reference or range,
+// returning true if any were removed. The relationship of an external target
+// is removed once no other hyperlink refers to it.
+func (s *Sheet) RemoveHyperlink(ref string) bool {
+	if s._bbbe.Hyperlinks == nil {
+		return false
+	}
+	ref = strings.ToUpper(strings.ReplaceAll(ref, "$", ""))
+	kept := s._bbbe.Hyperlinks.Hyperlink[:0]
+	removed := []string{}
+	for _, hl := range s._bbbe.Hyperlinks.Hyperlink {
+		if strings.ToUpper(hl.RefAttr) == ref {
+			if hl.IdAttr != nil {
+				removed = append(removed, *hl.IdAttr)
+			}
+			continue
+		}
+		kept = append(kept, hl)
+	}
+	if len(kept) == len(s._bbbe.Hyperlinks.Hyperlink) {
+		return false
+	}
+	s._bbbe.Hyperlinks.Hyperlink = kept
+	if len(kept) == 0 {
+		s._bbbe.Hyperlinks = nil
+	}
+	if rels, ok := s.rels(); ok {
+		for _, id := range removed {
+			if s.hyperlinkRelUsed(id) {
+				continue
+			}
+			for _, r := range rels.Relationships() {
+				if r.ID() == id && r.Type() == unioffice.HyperLinkType {
+					rels.Remove(r)
+					break
+				}
+			}
+		}
+	}
+	return true
+}
+
+func (s *Sheet) hyperlinkRelUsed(id string) bool {
+	if s._bbbe.Hyperlinks == nil {
+		return false
+	}
+	for _, hl := range s._bbbe.Hyperlinks.Hyperlink {
+		if hl.IdAttr != nil && *hl.IdAttr == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Drawing returns the drawing displayed on the sheet, if any.
+func (s *Sheet) Drawing() (Drawing, bool) {
+	if s._bbbe.Drawing == nil {
+		return Drawing{}, false
+	}
+	if rels, ok := s.rels(); ok {
+		target := rels.GetTargetByRelId(s._bbbe.Drawing.IdAttr)
+		if n := partNumber(target, "drawing"); n > 0 && n <= len(s._fgeg._ecgc) {
+			return Drawing{s._fgeg, s._fgeg._ecgc[n-1]}, true
+		}
+	}
+	if dr, _ := s.GetDrawing(); dr != nil {
+		return Drawing{s._fgeg, dr}, true
+	}
+	return Drawing{}, false
+}
+
+// partNumber returns the number of a part from its file name, e.g. 3 for
+// ../media/image3.png with a prefix of image, or zero if the name doesn't match.
+func partNumber(target, prefix string) int {
+	base := path.Base(target)
+	if !strings.HasPrefix(base, prefix) {
+		return 0
+	}
+	base = strings.TrimPrefix(base, prefix)
+	if i := strings.IndexByte(base, '.'); i >= 0 {
+		base = base[:i]
+	}
+	n, err := strconv.Atoi(base)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// DrawingImage is a picture placed on a drawing.
+type DrawingImage struct {
+	Anchor Anchor
+	// Image is the image referenced by the picture, it is the zero value if the
+	// image couldn't be resolved.
+	Image common.ImageRef
+	X     *sd.CT_Picture
+}
+
+// DrawingChart is a chart placed on a drawing.
+type DrawingChart struct {
+	Anchor Anchor
+	Chart  chart.Chart
+	X      *sd.CT_GraphicalObjectFrame
+}
+
+// rels returns the relationships of the drawing.
+func (d Drawing) rels() (common.Relationships, bool) {
+	for i, dr := range d._bgbe._ecgc {
+		if dr == d._gbad {
+			return d._bgbe._fcdfa[i], true
+		}
+	}
+	return common.Relationships{}, false
+}
+
+// anchorFor wraps an anchor element, returning the anchor and the objects
+// placed by it.
+func anchorFor(eg *sd.EG_Anchor) (Anchor, *sd.EG_ObjectChoicesChoice) {
+	if eg == nil || eg.AnchorChoice == nil {
+		return nil, nil
+	}
+	switch {
+	case eg.AnchorChoice.TwoCellAnchor != nil:
+		return TwoCellAnchor{eg.AnchorChoice.TwoCellAnchor}, eg.AnchorChoice.TwoCellAnchor.ObjectChoicesChoice
+	case eg.AnchorChoice.OneCellAnchor != nil:
+		return OneCellAnchor{eg.AnchorChoice.OneCellAnchor}, eg.AnchorChoice.OneCellAnchor.ObjectChoicesChoice
+	case eg.AnchorChoice.AbsoluteAnchor != nil:
+		return AbsoluteAnchor{eg.AnchorChoice.AbsoluteAnchor}, eg.AnchorChoice.AbsoluteAnchor.ObjectChoicesChoice
+	}
+	return nil, nil
+}
+
+// sameAnchor returns true if both anchors wrap the same element.
+func sameAnchor(a, b Anchor) bool {
+	switch at := a.(type) {
+	case TwoCellAnchor:
+		bt, ok := b.(TwoCellAnchor)
+		return ok && at._gegg == bt._gegg
+	case OneCellAnchor:
+		bt, ok := b.(OneCellAnchor)
+		return ok && at._cedd == bt._cedd
+	case AbsoluteAnchor:
+		bt, ok := b.(AbsoluteAnchor)
+		return ok && at._be == bt._be
+	}
+	return false
+}
+
+// Images returns the pictures placed on the drawing along with their anchors
+// and image data.
+func (d Drawing) Images() []DrawingImage {
+	rels, _ := d.rels()
+	ret := []DrawingImage{}
+	for _, eg := range d._gbad.EG_Anchor {
+		a, obj := anchorFor(eg)
+		if obj == nil || obj.Pic == nil {
+			continue
+		}
+		img := DrawingImage{Anchor: a, X: obj.Pic}
+		if id := pictureRelID(obj.Pic); id != "" && rels.X() != nil {
+			if i := d.imageIndex(rels.GetTargetByRelId(id)); i >= 0 {
+				img.Image = d._bgbe.Images[i]
+			}
+		}
+		ret = append(ret, img)
+	}
+	return ret
+}
+
+// imageIndex returns the index of the workbook image a relationship target of
+// the drawing refers to, or -1 if it doesn't refer to an image.
+func (d Drawing) imageIndex(target string) int {
+	if target == "" {
+		return -1
+	}
+	target = drawingPartPath(target)
+	for i := range d._bgbe.Images {
+		img := &d._bgbe.Images[i]
+		if img.Target() != "" && drawingPartPath(img.Target()) == target {
+			return i
+		}
+		if drawingPartPath(imageTarget(i, *img)) == target {
+			return i
+		}
+	}
+	return -1
+}
+
+// imageTarget returns the target of a relationship from a drawing to the
+// image at an index of the workbook images.
+func imageTarget(idx int, img common.ImageRef) string {
+	return fmt.Sprintf("../media/image%d.%s", idx+1, img.Format())
+}
+
+// drawingPartPath returns the path of a part in the package from the target
+// of a relationship of a drawing.
+func drawingPartPath(target string) string {
+	if strings.HasPrefix(target, "/") {
+		return path.Clean(target[1:])
+	}
+	return path.Join("xl/drawings", target)
+}
+
+// ReplaceImage replaces the image displayed by a picture of the drawing,
+// keeping its anchor and properties. The image must have been added to the
+// workbook with Workbook.AddImage. The relationship to the previous image is
+// removed once no other object refers to it.
+func (d Drawing) ReplaceImage(img DrawingImage, ref common.ImageRef) error {
+	idx := -1
+	for i, wi := range d._bgbe.Images {
+		if wi == ref {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return errors.New("image must be added to the workbook")
+	}
+	rels, ok := d.rels()
+	if !ok {
+		return errors.New("drawing not found in the workbook")
+	}
+	found := false
+	for _, eg := range d._gbad.EG_Anchor {
+		if _, obj := anchorFor(eg); obj != nil && obj.Pic == img.X {
+			found = true
+			break
+		}
+	}
+	if !found || img.X == nil {
+		return errors.New("picture not found on the drawing")
+	}
+	old := pictureRelID(img.X)
+	rel := rels.AddRelationship(imageTarget(idx, ref), unioffice.ImageType)
+	if img.X.BlipFill == nil {
+		img.X.BlipFill = dml.NewCT_BlipFillProperties()
+	}
+	if img.X.BlipFill.Blip == nil {
+		img.X.BlipFill.Blip = dml.NewCT_Blip()
+	}
+	img.X.BlipFill.Blip.EmbedAttr = unioffice.String(rel.ID())
+	if old != "" && !d.relUsed(old) {
+		for _, r := range rels.Relationships() {
+			if r.ID() == old {
+				rels.Remove(r)
+				break
+			}
+		}
+	}
+	return nil
+}
+
+func pictureRelID(pic *sd.CT_Picture) string {
+	if pic.BlipFill == nil || pic.BlipFill.Blip == nil || pic.BlipFill.Blip.EmbedAttr == nil {
+		return ""
+	}
+	return *pic.BlipFill.Blip.EmbedAttr
+}
+
+// frameChartRelID returns the relationship ID of the chart displayed by a
+// graphic frame or an empty string if the frame doesn't display a chart.
+func frameChartRelID(gf *sd.CT_GraphicalObjectFrame) string {
+	if gf.Graphic == nil || gf.Graphic.GraphicData == nil {
+		return ""
+	}
+	for _, a := range gf.Graphic.GraphicData.Any {
+		switch c := a.(type) {
+		case *crt.Chart:
+			return c.IdAttr
+		case *crt.CT_RelId:
+			return c.IdAttr
+		case *chartex.Chart:
+			return c.IdAttr
+		}
+	}
+	return ""
+}
+
+// Charts returns the charts placed on the drawing along with their anchors.
+func (d Drawing) Charts() []DrawingChart {
+	rels, ok := d.rels()
+	if !ok {
+		return nil
+	}
+	ret := []DrawingChart{}
+	for _, eg := range d._gbad.EG_Anchor {
+		a, obj := anchorFor(eg)
+		if obj == nil || obj.GraphicFrame == nil {
+			continue
+		}
+		id := frameChartRelID(obj.GraphicFrame)
+		if id == "" {
+			continue
+		}
+		target := rels.GetTargetByRelIdAndType(id, unioffice.ChartType)
+		cs := d._bgbe.GetChartByTargetId(target)
+		if cs == nil {
+			if n := partNumber(target, "chart"); n > 0 && n <= len(d._bgbe._faebe) {
+				cs = d._bgbe._faebe[n-1]
+			}
+		}
+		if cs == nil {
+			continue
+		}
+		ret = append(ret, DrawingChart{Anchor: a, Chart: chart.MakeChart(cs), X: obj.GraphicFrame})
+	}
+	return ret
+}
+
+// Anchors returns the anchors of all objects placed on the drawing.
+func (d Drawing) Anchors() []Anchor {
+	ret := []Anchor{}
+	for _, eg := range d._gbad.EG_Anchor {
+		if a, _ := anchorFor(eg); a != nil {
+			ret = append(ret, a)
+		}
+	}
+	return ret
+}
+
+// Remove removes the object placed by an anchor from the drawing, returning
+// true if it was found. The relationship to the image or chart is removed once
+// no other object refers to it. The chart part is removed too once no drawing
+// refers to it, the image is kept as other parts may use it.
+func (d Drawing) Remove(a Anchor) bool {
+	for i, eg := range d._gbad.EG_Anchor {
+		ea, obj := anchorFor(eg)
+		if ea == nil || !sameAnchor(ea, a) {
+			continue
+		}
+		copy(d._gbad.EG_Anchor[i:], d._gbad.EG_Anchor[i+1:])
+		d._gbad.EG_Anchor = d._gbad.EG_Anchor[:len(d._gbad.EG_Anchor)-1]
+		if obj != nil {
+			id := ""
+			if obj.Pic != nil {
+				id = pictureRelID(obj.Pic)
+			} else if obj.GraphicFrame != nil {
+				id = frameChartRelID(obj.GraphicFrame)
+			}
+			if id != "" && !d.relUsed(id) {
+				if rels, ok := d.rels(); ok {
+					for _, r := range rels.Relationships() {
+						if r.ID() == id {
+							rels.Remove(r)
+							d._bgbe.removeChartPart(r.Type(), partPath("xl/drawings", r.Target()))
+							break
+						}
+					}
+				}
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// removeChartPart removes a chart or chartex part along with its content type
+// and relationships once no drawing refers to it. The following charts are
+// renumbered as the chart parts are written in order.
+func (wb *Workbook) removeChartPart(typ, zipPath string) {
+	if typ != unioffice.ChartType && typ != chartex.RelationshipType {
+		return
+	}
+	for _, rels := range wb._fcdfa {
+		for _, r := range rels.Relationships() {
+			if r.Type() == typ && partPath("xl/drawings", r.Target()) == zipPath {
+				return
+			}
+		}
+	}
+	chartRels := func(n int) string { return fmt.Sprintf("xl/charts/_rels/chart%d.xml.rels", n) }
+	if typ == chartex.RelationshipType {
+		for i, p := range wb._chartEx {
+			if p.path == zipPath {
+				wb._chartEx = append(wb._chartEx[:i], wb._chartEx[i+1:]...)
+				break
+			}
+		}
+		dir, file := path.Split(zipPath)
+		wb.ContentTypes.RemoveOverride(zipPath)
+		wb.removeExtraFile(zipPath)
+		wb.removeExtraFile(dir + "_rels/" + file + ".rels")
+		return
+	}
+
+	n := partNumber(zipPath, "chart")
+	if n <= 0 || n > len(wb._faebe) {
+		return
+	}
+	cs := wb._faebe[n-1]
+	wb._faebe = append(wb._faebe[:n-1], wb._faebe[n:]...)
+	renumber := func(target string) string {
+		if m := partNumber(target, "chart"); m > n {
+			return unioffice.RelativeFilename(unioffice.DocTypeSpreadsheet, unioffice.DrawingType, unioffice.ChartType, m-1)
+		}
+		return target
+	}
+	byTarget := map[string]*crt.ChartSpace{}
+	for target, c := range wb._ffaff {
+		if c != cs {
+			byTarget[renumber(target)] = c
+		}
+	}
+	wb._ffaff = byTarget
+	for _, rels := range wb._fcdfa {
+		for _, r := range rels.Relationships() {
+			if r.Type() == unioffice.ChartType {
+				r.SetTarget(renumber(r.Target()))
+			}
+		}
+	}
+	wb.removeExtraFile(chartRels(n))
+	for i, ef := range wb.ExtraFiles {
+		if m := partNumber(ef.ZipPath, "chart"); m > n && ef.ZipPath == chartRels(m) {
+			wb.ExtraFiles[i].ZipPath = chartRels(m - 1)
+		}
+	}
+	// the parts are numbered from one, the last number is no longer used
+	wb.ContentTypes.RemoveOverride(unioffice.AbsoluteFilename(unioffice.DocTypeSpreadsheet, unioffice.ChartContentType, len(wb._faebe)+1))
+}
+
+// RemoveImage removes a picture from the drawing.
+func (d Drawing) RemoveImage(img DrawingImage) bool { return d.Remove(img.Anchor) }
+
+// RemoveChart removes a chart from the drawing.
+func (d Drawing) RemoveChart(c DrawingChart) bool { return d.Remove(c.Anchor) }
+
+func (d Drawing) relUsed(id string) bool {
+	for _, eg := range d._gbad.EG_Anchor {
+		_, obj := anchorFor(eg)
+		if obj == nil {
+			continue
+		}
+		if obj.Pic != nil && pictureRelID(obj.Pic) == id {
+			return true
+		}
+		if obj.GraphicFrame != nil && frameChartRelID(obj.GraphicFrame) == id {
+			return true
+		}
+	}
+	return false
+}
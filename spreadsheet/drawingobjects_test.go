@@ -0,0 +1,161 @@
+package spreadsheet
+
+import (
+	"image"
+	"testing"
+
+	"github.com/yaklabco/unioffice/v2/common"
+)
+
+func TestSheetHyperlinks(t *testing.T) {
+	wb := New()
+	sheet := wb.AddSheet()
+	sheet.Cell("A1").SetHyperlink(sheet.AddHyperlink("https://example.com"))
+	sheet.Cell("A2").SetHyperlink(sheet.AddHyperlink("https://example.org"))
+
+	hls := sheet.Hyperlinks()
+	if len(hls) != 2 {
+		t.Fatalf("expected 2 hyperlinks, got %d", len(hls))
+	}
+	if hls[0].Reference != "A1" || hls[0].Target != "https://example.com" {
+		t.Errorf("unexpected hyperlink %+v", hls[0])
+	}
+	if !sheet.RemoveHyperlink("$A$1") {
+		t.Fatal("expected hyperlink to be removed")
+	}
+	hls = sheet.Hyperlinks()
+	if len(hls) != 1 || hls[0].Target != "https://example.org" {
+		t.Errorf("unexpected hyperlinks after removal %+v", hls)
+	}
+	rels, _ := sheet.rels()
+	for _, r := range rels.Relationships() {
+		if r.Target() == "https://example.com" {
+			t.Error("expected hyperlink relationship to be removed")
+		}
+	}
+}
+
+func TestDrawingObjects(t *testing.T) {
+	wb := New()
+	sheet := wb.AddSheet()
+	if _, ok := sheet.Drawing(); ok {
+		t.Fatal("expected no drawing")
+	}
+	dr := wb.AddDrawing()
+	sheet.SetDrawing(dr)
+
+	data := []byte{1, 2, 3}
+	img, err := wb.AddImage(common.Image{Data: &data, Format: "png", Size: image.Point{X: 10, Y: 20}})
+	if err != nil {
+		t.Fatalf("error adding image: %s", err)
+	}
+	imgAnchor := dr.AddImage(img, AnchorTypeTwoCell)
+	imgAnchor.MoveTo(3, 4)
+	_, chartAnchor := dr.AddChart(AnchorTypeOneCell)
+
+	got, ok := sheet.Drawing()
+	if !ok || got.X() != dr.X() {
+		t.Fatal("expected sheet drawing to be found")
+	}
+	images := got.Images()
+	if len(images) != 1 {
+		t.Fatalf("expected 1 image, got %d", len(images))
+	}
+	if images[0].Image.Data() != &data {
+		t.Error("expected image data to be resolved")
+	}
+	if images[0].Anchor.TopLeft().Col() != 3 || images[0].Anchor.TopLeft().Row() != 4 {
+		t.Errorf("unexpected image position %d,%d", images[0].Anchor.TopLeft().Col(), images[0].Anchor.TopLeft().Row())
+	}
+	charts := got.Charts()
+	if len(charts) != 1 || charts[0].Chart.X() != wb._faebe[0] {
+		t.Fatalf("expected chart to be resolved, got %d", len(charts))
+	}
+
+	if !got.Remove(chartAnchor) {
+		t.Fatal("expected chart to be removed")
+	}
+	if len(got.Charts()) != 0 || len(got.Anchors()) != 1 {
+		t.Error("expected only the image to remain")
+	}
+	if got.Remove(chartAnchor) {
+		t.Error("expected second removal to fail")
+	}
+	if !got.RemoveImage(images[0]) || len(got.Anchors()) != 0 {
+		t.Error("expected image to be removed")
+	}
+	rels, _ := got.rels()
+	if n := len(rels.Relationships()); n != 0 {
+		t.Errorf("expected drawing relationships to be removed, got %d", n)
+	}
+}
+
+func TestDrawingReplaceImage(t *testing.T) {
+	wb := New()
+	sheet := wb.AddSheet()
+	dr := wb.AddDrawing()
+	sheet.SetDrawing(dr)
+
+	logo, banner := []byte{1}, []byte{2}
+	logoRef, _ := wb.AddImage(common.Image{Data: &logo, Format: "png", Size: image.Point{X: 1, Y: 1}})
+	bannerRef, _ := wb.AddImage(common.Image{Data: &banner, Format: "jpeg", Size: image.Point{X: 1, Y: 1}})
+	dr.AddImage(bannerRef, AnchorTypeTwoCell)
+
+	// images read from a file are resolved by the target of their relationship
+	wb.Images[1].SetTarget("../media/image9.jpeg")
+	rels, _ := dr.rels()
+	rels.X().Relationship[0].TargetAttr = "../media/image9.jpeg"
+	images := dr.Images()
+	if len(images) != 1 || images[0].Image.Data() != &banner {
+		t.Fatal("expected the image to be resolved by its target")
+	}
+
+	if err := dr.ReplaceImage(images[0], logoRef); err != nil {
+		t.Fatalf("error replacing image: %s", err)
+	}
+	images = dr.Images()
+	if len(images) != 1 || images[0].Image.Data() != &logo {
+		t.Fatal("expected the image to be replaced")
+	}
+	if n := len(rels.Relationships()); n != 1 {
+		t.Errorf("expected the previous image relationship to be removed, got %d", n)
+	}
+	other, _ := New().AddImage(common.Image{Data: &logo, Format: "png", Size: image.Point{X: 1, Y: 1}})
+	if err := dr.ReplaceImage(images[0], other); err == nil {
+		t.Error("expected an error for an image of another workbook")
+	}
+}
+
+func TestDrawingRemoveChartPart(t *testing.T) {
+	wb := New()
+	sheet := wb.AddSheet()
+	dr := wb.AddDrawing()
+	sheet.SetDrawing(dr)
+	_, first := dr.AddChart(AnchorTypeTwoCell)
+	second, _ := dr.AddChart(AnchorTypeTwoCell)
+	wb.ExtraFiles = append(wb.ExtraFiles,
+		common.ExtraFile{ZipPath: "xl/charts/_rels/chart1.xml.rels"},
+		common.ExtraFile{ZipPath: "xl/charts/_rels/chart2.xml.rels"})
+
+	if !dr.Remove(first) {
+		t.Fatal("expected chart to be removed")
+	}
+	if len(wb._faebe) != 1 || wb._faebe[0] != second.X() {
+		t.Fatalf("expected only the second chart part to remain")
+	}
+	rels, _ := dr.rels()
+	if r := rels.Relationships(); len(r) != 1 || r[0].Target() != "../charts/chart1.xml" {
+		t.Errorf("expected the second chart to be renumbered")
+	}
+	if charts := dr.Charts(); len(charts) != 1 || charts[0].Chart.X() != second.X() {
+		t.Errorf("expected the second chart to be resolved")
+	}
+	for _, tc := range wb.ContentTypes.X().TypesChoice {
+		if tc.Override != nil && tc.Override.PartNameAttr == "/xl/charts/chart2.xml" {
+			t.Error("expected the content type of the removed part to be dropped")
+		}
+	}
+	if len(wb.ExtraFiles) != 1 || wb.ExtraFiles[0].ZipPath != "xl/charts/_rels/chart1.xml.rels" {
+		t.Errorf("expected the chart relationships to follow the parts, got %+v", wb.ExtraFiles)
+	}
+}
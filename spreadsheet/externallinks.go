@@ -0,0 +1,511 @@
+package spreadsheet
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yaklabco/unioffice/v2/schema/soo/pkg/relationships"
+	"github.com/yaklabco/unioffice/v2/schema/soo/sml"
+	"github.com/yaklabco/unioffice/v2/spreadsheet/formula"
+	"github.com/yaklabco/unioffice/v2/spreadsheet/reference"
+)
+
+const (
+	externalLinkType        = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/externalLink"
+	externalLinkContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.externalLink+xml"
+)
+
+// externalState holds the external link parts of a workbook that have been
+// read along with the workbooks they've been resolved to.
+type externalState struct {
+	links    []*externalLinkPart
+	resolved map[int]*Workbook
+}
+
+type externalLinkPart struct {
+	relID  string
+	path   string
+	target string
+	x      *sml.ExternalLink
+}
+
+// ExternalLink is a link to another workbook that formulas of the workbook
+// refer to, e.g. =[1]Sheet1!A1.
+type ExternalLink struct {
+	wb    *Workbook
+	index int
+	part  *externalLinkPart
+}
+
+// ExternalCachedValue is a value of another workbook cached in an external
+// link, formulas are evaluated against these values until the link is
+// resolved.
+type ExternalCachedValue struct {
+	Sheet string
+	Ref   string
+	Type  sml.ST_CellType
+	Value string
+}
+
+// X returns the inner wrapped XML type.
+func (e ExternalLink) X() *sml.ExternalLink { return e.part.x }
+
+// Index returns the one based index of the link that formulas use to refer to
+// it (e.g. 1 for [1]Sheet1!A1).
+func (e ExternalLink) Index() int { return e.index }
+
+// Target returns the path or URL of the linked workbook as stored in the file.
+func (e ExternalLink) Target() string { return e.part.target }
+
+// IsResolved returns true if the link has been resolved to a workbook with
+// ResolveExternalLinks.
+func (e ExternalLink) IsResolved() bool {
+	return e.wb._external.resolved[e.index] != nil
+}
+
+func (e ExternalLink) book() *sml.CT_ExternalBook {
+	if e.part.x.ExternalLinkChoice == nil {
+		return nil
+	}
+	return e.part.x.ExternalLinkChoice.ExternalBook
+}
+
+// SheetNames returns the names of the sheets of the linked workbook.
+func (e ExternalLink) SheetNames() []string {
+	ret := []string{}
+	bk := e.book()
+	if bk == nil || bk.SheetNames == nil {
+		return ret
+	}
+	for _, sn := range bk.SheetNames.SheetName {
+		if sn.ValAttr != nil {
+			ret = append(ret, *sn.ValAttr)
+		} else {
+			ret = append(ret, "")
+		}
+	}
+	return ret
+}
+
+// CachedValues returns the values of the linked workbook that are cached in
+// the link.
+func (e ExternalLink) CachedValues() []ExternalCachedValue {
+	ret := []ExternalCachedValue{}
+	bk := e.book()
+	if bk == nil || bk.SheetDataSet == nil {
+		return ret
+	}
+	names := e.SheetNames()
+	for _, sd := range bk.SheetDataSet.SheetData {
+		sheet := ""
+		if int(sd.SheetIdAttr) < len(names) {
+			sheet = names[sd.SheetIdAttr]
+		}
+		for _, r := range sd.Row {
+			for _, c := range r.Cell {
+				cv := ExternalCachedValue{Sheet: sheet, Type: c.TAttr}
+				if c.RAttr != nil {
+					cv.Ref = *c.RAttr
+				}
+				if c.V != nil {
+					cv.Value = *c.V
+				}
+				ret = append(ret, cv)
+			}
+		}
+	}
+	return ret
+}
+
+// CachedValue returns the value of a cell of the linked workbook cached in
+// the link.
+func (e ExternalLink) CachedValue(sheet, ref string) (ExternalCachedValue, bool) {
+	ref = strings.Replace(ref, "$", "", -1)
+	for _, cv := range e.CachedValues() {
+		if strings.EqualFold(cv.Sheet, sheet) && cv.Ref == ref {
+			return cv, true
+		}
+	}
+	return ExternalCachedValue{}, false
+}
+
+// sheetData returns the cached data of a sheet of the linked workbook.
+func (e ExternalLink) sheetData(sheet string) *sml.CT_ExternalSheetData {
+	bk := e.book()
+	if bk == nil || bk.SheetDataSet == nil {
+		return nil
+	}
+	for i, n := range e.SheetNames() {
+		if !strings.EqualFold(n, sheet) {
+			continue
+		}
+		for _, sd := range bk.SheetDataSet.SheetData {
+			if int(sd.SheetIdAttr) == i {
+				return sd
+			}
+		}
+	}
+	return nil
+}
+
+// ExternalLinks returns the links to other workbooks.
+func (wb *Workbook) ExternalLinks() ([]ExternalLink, error) {
+	st, err := wb.externalState()
+	if err != nil {
+		return nil, err
+	}
+	ret := []ExternalLink{}
+	for i, p := range st.links {
+		ret = append(ret, ExternalLink{wb, i + 1, p})
+	}
+	return ret, nil
+}
+
+// externalState reads the external link parts, they're kept as extra files
+// as the package reader doesn't support them.
+func (wb *Workbook) externalState() (*externalState, error) {
+	if wb._external != nil {
+		return wb._external, nil
+	}
+	st := &externalState{resolved: map[int]*Workbook{}}
+	if wb._gbadf.ExternalReferences != nil {
+		for _, er := range wb._gbadf.ExternalReferences.ExternalReference {
+			p := &externalLinkPart{relID: er.IdAttr, x: sml.NewExternalLink()}
+			if target := wb._bcg.GetTargetByRelId(er.IdAttr); target != "" {
+				p.path = partPath("xl", target)
+				if _, err := wb.readExtraFile(p.path, p.x); err != nil {
+					return nil, err
+				}
+			}
+			if bk := p.x.ExternalLinkChoice; bk != nil && bk.ExternalBook != nil && p.path != "" {
+				dir, file := path.Split(p.path)
+				rels := relationships.NewRelationships()
+				ok, err := wb.readExtraFile(dir+"_rels/"+file+".rels", rels)
+				if err != nil {
+					return nil, err
+				}
+				if ok {
+					for _, r := range rels.Relationship {
+						if r.IdAttr == bk.ExternalBook.IdAttr {
+							p.target = r.TargetAttr
+						}
+					}
+				}
+			}
+			st.links = append(st.links, p)
+		}
+	}
+	wb._external = st
+	return st, nil
+}
+
+// ResolveExternalLinks calls resolver with the target of each external link
+// and evaluates formulas referring to the link against the returned workbook
+// instead of the values cached in the link. Links that resolver returns a nil
+// workbook for keep using the cached values.
+func (wb *Workbook) ResolveExternalLinks(resolver func(path string) (*Workbook, error)) error {
+	links, err := wb.ExternalLinks()
+	if err != nil {
+		return err
+	}
+	for _, l := range links {
+		ext, err := resolver(l.Target())
+		if err != nil {
+			return fmt.Errorf("error resolving external link %s: %s", l.Target(), err)
+		}
+		if ext != nil {
+			wb._external.resolved[l.Index()] = ext
+		}
+	}
+	return nil
+}
+
+// linkFor returns the external link that the workbook part of an external
+// reference refers to, either by its index or by the file name of its target.
+func (wb *Workbook) linkFor(book string) (ExternalLink, bool) {
+	links, err := wb.ExternalLinks()
+	if err != nil {
+		return ExternalLink{}, false
+	}
+	if idx, err := strconv.Atoi(book); err == nil {
+		if idx < 1 || idx > len(links) {
+			return ExternalLink{}, false
+		}
+		return links[idx-1], true
+	}
+	for _, l := range links {
+		target := strings.Replace(l.Target(), "\\", "/", -1)
+		if strings.EqualFold(path.Base(target), book) || strings.EqualFold(target, book) {
+			return l, true
+		}
+	}
+	return ExternalLink{}, false
+}
+
+// externalSheet returns the evaluation context for a sheet name referring to
+// another workbook (e.g. "[1]Sheet1"). It returns false for sheets of this
+// workbook.
+func (wb *Workbook) externalSheet(name string) (formula.Context, bool) {
+	book, sheet, ok := formula.SplitExternalSheet(name)
+	if !ok {
+		return nil, false
+	}
+	l, ok := wb.linkFor(book)
+	if !ok {
+		return formula.InvalidReferenceContext, true
+	}
+	if ext := wb._external.resolved[l.Index()]; ext != nil {
+		for _, s := range ext.AllSheets() {
+			if sheet == "" || strings.EqualFold(s.Name(), sheet) {
+				return &externalContext{s.FormulaContext()}, true
+			}
+		}
+		return formula.InvalidReferenceContext, true
+	}
+	if sd := l.sheetData(sheet); sd != nil || sheet == "" {
+		return &cachedContext{link: l, data: sd}, true
+	}
+	return formula.InvalidReferenceContext, true
+}
+
+// externalContext evaluates cells of a sheet in a resolved workbook. Cells are
+// evaluated with their own evaluator as cached results are keyed by the sheet
+// name which may also exist in the referring workbook.
+type externalContext struct {
+	formula.Context
+}
+
+func (c *externalContext) Cell(ref string, ev formula.Evaluator) formula.Result {
+	return c.Context.Cell(ref, formula.NewEvaluator())
+}
+
+// cachedContext evaluates cells of a sheet in another workbook using the values
+// cached in the external link.
+type cachedContext struct {
+	link ExternalLink
+	data *sml.CT_ExternalSheetData
+}
+
+func (c *cachedContext) cell(ref string) *sml.CT_ExternalCell {
+	if c.data == nil {
+		return nil
+	}
+	cr, err := reference.ParseCellReference(ref)
+	if err != nil {
+		return nil
+	}
+	want := cr.Column + strconv.Itoa(int(cr.RowIdx))
+	for _, r := range c.data.Row {
+		if r.RAttr != cr.RowIdx {
+			continue
+		}
+		for _, x := range r.Cell {
+			if x.RAttr != nil && *x.RAttr == want {
+				return x
+			}
+		}
+	}
+	return nil
+}
+
+// Sheet returns the context of another sheet of the linked workbook, used when
+// evaluating the defined names of the linked workbook.
+func (c *cachedContext) Sheet(name string) formula.Context {
+	name = strings.Replace(strings.Trim(name, "'"), "''", "'", -1)
+	if sd := c.link.sheetData(name); sd != nil {
+		return &cachedContext{link: c.link, data: sd}
+	}
+	return formula.InvalidReferenceContext
+}
+
+func (c *cachedContext) Cell(ref string, ev formula.Evaluator) formula.Result {
+	x := c.cell(ref)
+	if x == nil || x.V == nil {
+		return formula.MakeEmptyResult()
+	}
+	switch x.TAttr {
+	case sml.ST_CellTypeS, sml.ST_CellTypeStr, sml.ST_CellTypeInlineStr:
+		return formula.MakeStringResult(*x.V)
+	case sml.ST_CellTypeB:
+		return formula.MakeBoolResult(*x.V == "1")
+	case sml.ST_CellTypeE:
+		r := formula.MakeErrorResult("")
+		r.ValueString = *x.V
+		return r
+	}
+	f, err := strconv.ParseFloat(*x.V, 64)
+	if err != nil {
+		return formula.MakeStringResult(*x.V)
+	}
+	return formula.MakeNumberResult(f)
+}
+
+func (c *cachedContext) GetEpoch() time.Time             { return time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC) }
+func (c *cachedContext) GetFilename() string             { return c.link.Target() }
+func (c *cachedContext) GetWidth(colIdx int) float64     { return 0 }
+func (c *cachedContext) GetFormat(cellRef string) string { return "" }
+func (c *cachedContext) GetLabelPrefix(cellRef string) string {
+	return ""
+}
+func (c *cachedContext) GetLocked(cellRef string) bool    { return false }
+func (c *cachedContext) HasFormula(cellRef string) bool   { return false }
+func (c *cachedContext) IsDBCS() bool                     { return false }
+func (c *cachedContext) SetLocked(cellRef string, l bool) {}
+func (c *cachedContext) SetOffset(col, row uint32)        {}
+
+func (c *cachedContext) IsBool(cellRef string) bool {
+	x := c.cell(cellRef)
+	return x != nil && x.TAttr == sml.ST_CellTypeB
+}
+
+func (c *cachedContext) LastColumn(rowFrom, rowTo int) string {
+	max := uint32(0)
+	if c.data != nil {
+		for _, r := range c.data.Row {
+			if int(r.RAttr) < rowFrom || int(r.RAttr) > rowTo {
+				continue
+			}
+			for _, x := range r.Cell {
+				if x.RAttr == nil {
+					continue
+				}
+				if cr, err := reference.ParseCellReference(*x.RAttr); err == nil && cr.ColumnIdx+1 > max {
+					max = cr.ColumnIdx + 1
+				}
+			}
+		}
+	}
+	if max == 0 {
+		return "A"
+	}
+	return reference.IndexToColumn(max - 1)
+}
+
+func (c *cachedContext) LastRow(colFrom string) int {
+	max := 0
+	if c.data != nil {
+		for _, r := range c.data.Row {
+			if int(r.RAttr) > max {
+				max = int(r.RAttr)
+			}
+		}
+	}
+	return max
+}
+
+func (c *cachedContext) NamedRange(ref string) formula.Reference {
+	bk := c.link.book()
+	if bk == nil || bk.DefinedNames == nil {
+		return formula.ReferenceInvalid
+	}
+	for _, dn := range bk.DefinedNames.DefinedName {
+		if strings.EqualFold(dn.NameAttr, ref) && dn.RefersToAttr != nil {
+			return formula.MakeRangeReference(strings.TrimPrefix(*dn.RefersToAttr, "="))
+		}
+	}
+	return formula.ReferenceInvalid
+}
+
+// BreakLinks replaces the formulas and defined names that refer to other
+// workbooks with their cached values and removes the external links.
+func (wb *Workbook) BreakLinks() error {
+	links, err := wb.ExternalLinks()
+	if err != nil {
+		return err
+	}
+	if len(links) == 0 {
+		return nil
+	}
+	for _, s := range wb.AllSheets() {
+		shared := map[uint32]struct{}{}
+		for _, r := range s.Rows() {
+			for _, c := range r.Cells() {
+				f := c.X().F
+				if f == nil || !formula.HasExternalReferences(f.Content) {
+					continue
+				}
+				if f.TAttr == sml.ST_CellFormulaTypeShared && f.SiAttr != nil {
+					shared[*f.SiAttr] = struct{}{}
+				}
+				c.X().F = nil
+			}
+		}
+		if len(shared) == 0 {
+			continue
+		}
+		for _, r := range s.Rows() {
+			for _, c := range r.Cells() {
+				f := c.X().F
+				if f == nil || f.TAttr != sml.ST_CellFormulaTypeShared || f.SiAttr == nil {
+					continue
+				}
+				if _, ok := shared[*f.SiAttr]; ok {
+					c.X().F = nil
+				}
+			}
+		}
+	}
+
+	if sheets := wb.AllSheets(); len(sheets) > 0 {
+		for _, dn := range wb.DefinedNames() {
+			if !formula.HasExternalReferences(dn.Content()) {
+				continue
+			}
+			// names local to a sheet are evaluated on their sheet
+			s := sheets[0]
+			if id := dn.X().LocalSheetIdAttr; id != nil && int(*id) < len(sheets) {
+				s = sheets[*id]
+			}
+			dn.SetContent(constantFor(formula.NewEvaluator().Eval(s.FormulaContext(), dn.Content())))
+		}
+	}
+
+	for _, p := range wb._external.links {
+		wb._bcg.Remove(wb._bcg.GetByRelId(p.relID))
+		if p.path == "" {
+			continue
+		}
+		dir, file := path.Split(p.path)
+		wb.ContentTypes.RemoveOverride(p.path)
+		wb.removeExtraFile(p.path)
+		wb.removeExtraFile(dir + "_rels/" + file + ".rels")
+	}
+	wb._gbadf.ExternalReferences = nil
+	wb._external = nil
+	return nil
+}
+
+// constantFor returns the formula constant for an evaluation result, e.g. the
+// quoted string for a string result.
+func constantFor(r formula.Result) string {
+	switch r.Type {
+	case formula.ResultTypeNumber:
+		if r.IsBoolean {
+			if r.ValueNumber != 0 {
+				return "TRUE"
+			}
+			return "FALSE"
+		}
+		return strconv.FormatFloat(r.ValueNumber, 'g', -1, 64)
+	case formula.ResultTypeString:
+		return `"` + strings.Replace(r.ValueString, `"`, `""`, -1) + `"`
+	case formula.ResultTypeError:
+		if strings.HasPrefix(r.ValueString, "#") {
+			return r.ValueString
+		}
+	}
+	return "#REF!"
+}
+
+// removeExtraFile removes a part from the extra files of the workbook.
+func (wb *Workbook) removeExtraFile(zipPath string) {
+	for i, ef := range wb.ExtraFiles {
+		if ef.ZipPath == zipPath {
+			wb.ExtraFiles = append(wb.ExtraFiles[:i], wb.ExtraFiles[i+1:]...)
+			return
+		}
+	}
+}
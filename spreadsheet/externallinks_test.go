@@ -0,0 +1,186 @@
+package spreadsheet
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/yaklabco/unioffice/v2"
+	"github.com/yaklabco/unioffice/v2/schema/soo/pkg/relationships"
+	"github.com/yaklabco/unioffice/v2/schema/soo/sml"
+)
+
+// addTestExternalLink adds a link to Budget.xlsx with cached values for
+// Sheet1!A1 and Sheet1!B1.
+func addTestExternalLink(t *testing.T, wb *Workbook) {
+	link := sml.NewExternalLink()
+	book := sml.NewCT_ExternalBook()
+	book.IdAttr = "rId1"
+	book.SheetNames = sml.NewCT_ExternalSheetNames()
+	book.SheetNames.SheetName = []*sml.CT_ExternalSheetName{{ValAttr: unioffice.String("Sheet1")}}
+	book.SheetDataSet = sml.NewCT_ExternalSheetDataSet()
+	book.SheetDataSet.SheetData = []*sml.CT_ExternalSheetData{{
+		SheetIdAttr: 0,
+		Row: []*sml.CT_ExternalRow{{RAttr: 1, Cell: []*sml.CT_ExternalCell{
+			{RAttr: unioffice.String("A1"), V: unioffice.String("40")},
+			{RAttr: unioffice.String("B1"), TAttr: sml.ST_CellTypeStr, V: unioffice.String("budget")},
+		}}},
+	}}
+	link.ExternalLinkChoice = &sml.CT_ExternalLinkChoice{ExternalBook: book}
+	if err := wb.writeExtraFile("xl/externalLinks/externalLink1.xml", link); err != nil {
+		t.Fatalf("error writing link: %s", err)
+	}
+	rels := relationships.NewRelationships()
+	rel := relationships.NewRelationship()
+	rel.IdAttr = "rId1"
+	rel.TargetAttr = "Budget.xlsx"
+	rel.TargetModeAttr = relationships.ST_TargetModeExternal
+	rels.Relationship = append(rels.Relationship, rel)
+	if err := wb.writeExtraFile("xl/externalLinks/_rels/externalLink1.xml.rels", rels); err != nil {
+		t.Fatalf("error writing link rels: %s", err)
+	}
+	r := wb._bcg.AddRelationship("externalLinks/externalLink1.xml", externalLinkType)
+	wb.ContentTypes.AddOverride("/xl/externalLinks/externalLink1.xml", externalLinkContentType)
+	wb._gbadf.ExternalReferences = sml.NewCT_ExternalReferences()
+	wb._gbadf.ExternalReferences.ExternalReference = []*sml.CT_ExternalReference{{IdAttr: r.ID()}}
+}
+
+func TestExternalLinks(t *testing.T) {
+	wb := New()
+	sheet := wb.AddSheet()
+	addTestExternalLink(t, wb)
+	sheet.Cell("A1").SetFormulaRaw("[1]Sheet1!A1*2")
+	sheet.Cell("A2").SetFormulaRaw("'[Budget.xlsx]Sheet1'!B1")
+	sheet.Cell("A3").SetFormulaRaw("SUM([1]Sheet1!A1:B1)")
+	sheet.RecalculateFormulas()
+
+	links, err := wb.ExternalLinks()
+	if err != nil {
+		t.Fatalf("error reading links: %s", err)
+	}
+	if len(links) != 1 || links[0].Target() != "Budget.xlsx" || links[0].Index() != 1 {
+		t.Fatalf("unexpected links %v", links)
+	}
+	if names := links[0].SheetNames(); len(names) != 1 || names[0] != "Sheet1" {
+		t.Errorf("unexpected sheet names %v", names)
+	}
+	if cv, ok := links[0].CachedValue("Sheet1", "$B$1"); !ok || cv.Value != "budget" {
+		t.Errorf("unexpected cached value %v", cv)
+	}
+	if v := sheet.Cell("A1").GetFormattedValue(); v != "80" {
+		t.Errorf("expected 80 from the cached value, got %s", v)
+	}
+	if v := sheet.Cell("A2").GetString(); v != "budget" {
+		t.Errorf("expected budget from the cached value, got %s", v)
+	}
+	if v := sheet.Cell("A3").GetFormattedValue(); v != "40" {
+		t.Errorf("expected 40 from the cached values, got %s", v)
+	}
+
+	budget := New()
+	bs := budget.AddSheet()
+	bs.SetName("Sheet1")
+	bs.Cell("A1").SetNumber(5)
+	bs.Cell("B1").SetFormulaRaw("A1+1")
+	var resolved string
+	if err := wb.ResolveExternalLinks(func(path string) (*Workbook, error) {
+		resolved = path
+		return budget, nil
+	}); err != nil {
+		t.Fatalf("error resolving links: %s", err)
+	}
+	if resolved != "Budget.xlsx" || !links[0].IsResolved() {
+		t.Errorf("expected Budget.xlsx to be resolved, got %s", resolved)
+	}
+	sheet.RecalculateFormulas()
+	if v := sheet.Cell("A1").GetFormattedValue(); v != "10" {
+		t.Errorf("expected 10 from the resolved workbook, got %s", v)
+	}
+	if v := sheet.Cell("A3").GetFormattedValue(); v != "11" {
+		t.Errorf("expected 11 from the resolved workbook, got %s", v)
+	}
+
+	wantErr := errors.New("missing")
+	if err := wb.ResolveExternalLinks(func(string) (*Workbook, error) { return nil, wantErr }); err == nil {
+		t.Errorf("expected resolver error")
+	}
+}
+
+func TestBreakLinks(t *testing.T) {
+	wb := New()
+	sheet := wb.AddSheet()
+	addTestExternalLink(t, wb)
+	sheet.Cell("A1").SetFormulaRaw("[1]Sheet1!A1+1")
+	sheet.Cell("A2").SetFormulaRaw("A1+1")
+	wb.AddDefinedName("Budget", "[1]Sheet1!$B$1")
+	sheet.RecalculateFormulas()
+
+	if err := wb.BreakLinks(); err != nil {
+		t.Fatalf("error breaking links: %s", err)
+	}
+	if sheet.Cell("A1").HasFormula() || sheet.Cell("A1").GetFormattedValue() != "41" {
+		t.Errorf("expected A1 to be the value 41, got %s", sheet.Cell("A1").GetFormattedValue())
+	}
+	if !sheet.Cell("A2").HasFormula() {
+		t.Errorf("expected A2 to keep its formula")
+	}
+	if dn := wb.DefinedNames()[0]; dn.Content() != `"budget"` {
+		t.Errorf("expected defined name to be the cached value, got %s", dn.Content())
+	}
+	if links, _ := wb.ExternalLinks(); len(links) != 0 {
+		t.Errorf("expected no links, got %d", len(links))
+	}
+	if wb._gbadf.ExternalReferences != nil {
+		t.Errorf("expected external references to be removed")
+	}
+	for _, ef := range wb.ExtraFiles {
+		t.Errorf("expected link parts to be removed, found %s", ef.ZipPath)
+	}
+}
+
+func TestBreakLinksHiddenSheet(t *testing.T) {
+	wb := New()
+	sheet := wb.AddSheet()
+	hidden := wb.AddSheet()
+	addTestExternalLink(t, wb)
+	sheet.Cell("A1").SetNumber(1)
+	hidden.Cell("A1").SetNumber(5)
+	hidden.Cell("B1").SetFormulaRaw("[1]Sheet1!A1*2")
+	wb.AddDefinedName("Scaled", "[1]Sheet1!$A$1+A1").SetLocalSheetID(1)
+	if err := hidden.SetState(sml.ST_SheetStateVeryHidden); err != nil {
+		t.Fatalf("error hiding sheet: %s", err)
+	}
+	hidden.RecalculateFormulas()
+
+	if err := wb.BreakLinks(); err != nil {
+		t.Fatalf("error breaking links: %s", err)
+	}
+	if hidden.Cell("B1").HasFormula() || hidden.Cell("B1").GetFormattedValue() != "80" {
+		t.Errorf("expected B1 of the hidden sheet to be the value 80, got %s", hidden.Cell("B1").GetFormattedValue())
+	}
+	if dn := wb.DefinedNames()[0]; dn.Content() != "45" {
+		t.Errorf("expected the local name to be evaluated on its sheet, got %s", dn.Content())
+	}
+}
+
+func TestResolveExternalLinksHiddenSheet(t *testing.T) {
+	wb := New()
+	sheet := wb.AddSheet()
+	addTestExternalLink(t, wb)
+	sheet.Cell("A1").SetFormulaRaw("[1]Sheet1!A1*2")
+
+	budget := New()
+	budget.AddSheet()
+	bs := budget.AddSheet()
+	bs.SetName("Sheet1")
+	bs.Cell("A1").SetNumber(7)
+	if err := bs.SetState(sml.ST_SheetStateHidden); err != nil {
+		t.Fatalf("error hiding sheet: %s", err)
+	}
+	if err := wb.ResolveExternalLinks(func(string) (*Workbook, error) { return budget, nil }); err != nil {
+		t.Fatalf("error resolving links: %s", err)
+	}
+	sheet.RecalculateFormulas()
+	if v := sheet.Cell("A1").GetFormattedValue(); v != "14" {
+		t.Errorf("expected 14 from the hidden sheet of the resolved workbook, got %s", v)
+	}
+}
@@ -38,7 +38,7 @@ if len (_ddf )==2{_bg ._ac =true ;_bg ._ee ,_ =_f .ParseInt (_ddf [1],10,64);for
 // Number is used to format a number with a format string.  If the format
 // string is empty, then General number formatting is used which attempts to mimic
 // Excel's general formatting.
-func Number (v float64 ,f string )string {if f ==""||f =="\u0047e\u006e\u0065\u0072\u0061\u006c"||f =="\u0040"{return NumberGeneric (v );};_dff :=Parse (f );if len (_dff )==1{return _ge (v ,_dff [0],false );}else if len (_dff )> 1&&v < 0{return _ge (v ,_dff [1],true );
+func Number (v float64 ,f string )string {if _c .Contains (f ,"[$"){return DefaultLocale .Number (v ,f );};if f ==""||f =="\u0047e\u006e\u0065\u0072\u0061\u006c"||f =="\u0040"{return NumberGeneric (v );};_dff :=Parse (f );if len (_dff )==1{return _ge (v ,_dff [0],false );}else if len (_dff )> 1&&v < 0{return _ge (v ,_dff [1],true );
 }else if len (_dff )> 2&&v ==0{return _ge (v ,_dff [2],false );};return _ge (v ,_dff [0],false );};func _bfcd (_acbf _e .Time )_e .Time {_acbf =_acbf .UTC ();return _e .Date (_acbf .Year (),_acbf .Month (),_acbf .Day (),_acbf .Hour (),_acbf .Minute (),_acbf .Second (),_acbf .Nanosecond (),_e .Local );
 };const _ad ="\u0046\u006d\u0074\u0054\u0079\u0070\u0065\u004c\u0069\u0074\u0065\u0072a\u006c\u0046\u006d\u0074\u0054\u0079\u0070\u0065\u0044\u0069\u0067\u0069\u0074\u0046\u006d\u0074\u0054y\u0070\u0065\u0044i\u0067\u0069\u0074\u004f\u0070\u0074\u0046\u006d\u0074\u0054\u0079\u0070\u0065\u0043o\u006d\u006d\u0061\u0046\u006d\u0074\u0054\u0079\u0070\u0065\u0044\u0065\u0063\u0069\u006da\u006c\u0046\u006d\u0074\u0054\u0079\u0070\u0065Pe\u0072\u0063e\u006e\u0074\u0046\u006d\u0074\u0054\u0079\u0070e\u0044\u006f\u006c\u006c\u0061\u0072\u0046\u006d\u0074Ty\u0070\u0065\u0044i\u0067\u0069\u0074\u004f\u0070\u0074\u0054\u0068\u006f\u0075\u0073\u0061n\u0064\u0073\u0046\u006d\u0074\u0054\u0079\u0070\u0065\u0055n\u0064\u0065\u0072\u0073c\u006f\u0072\u0065\u0046\u006d\u0074T\u0079\u0070\u0065\u0044\u0061\u0074\u0065\u0046\u006d\u0074\u0054y\u0070e\u0054\u0069\u006d\u0065\u0046\u006d\u0074\u0054\u0079\u0070\u0065\u0046\u0072\u0061\u0063t\u0069\u006f\u006e\u0046\u006dt\u0054\u0079\u0070\u0065\u0054e\u0078\u0074";
 func _ge (_acd float64 ,_dbb Format ,_bf bool )string {if _dbb ._fc {return NumberGeneric (_acd );};_deg :=make ([]byte ,0,20);_cd :=_a .Signbit (_acd );_ba :=_a .Abs (_acd );_ce :=int64 (0);_fe :=int64 (0);if _dbb .IsExponential {for _ba >=10{_fe ++;_ba /=10;
@@ -80,7 +80,7 @@ type FmtType byte ;const _eaeg int =0;func _aac (_aba []byte )[]byte {_gag :=len
 
 // String returns the string formatted according to the type.  In format strings
 // this is the fourth item, where '@' is used as a placeholder for text.
-func String (v string ,f string )string {_da :=Parse (f );var _eed Format ;if len (_da )==1{_eed =_da [0];}else if len (_da )==4{_eed =_da [3];};_dda :=false ;for _ ,_eb :=range _eed .Whole {if _eb .Type ==FmtTypeText {_dda =true ;};};if !_dda {return v ;
+func String (v string ,f string )string {if _c .Contains (f ,"[$"){return DefaultLocale .String (v ,f );};_da :=Parse (f );var _eed Format ;if len (_da )==1{_eed =_da [0];}else if len (_da )==4{_eed =_da [3];};_dda :=false ;for _ ,_eb :=range _eed .Whole {if _eb .Type ==FmtTypeText {_dda =true ;};};if !_dda {return v ;
 };_fcfb :=_af .Buffer {};for _ ,_dbc :=range _eed .Whole {switch _dbc .Type {case FmtTypeLiteral :_fcfb .WriteByte (_dbc .Literal );case FmtTypeText :_fcfb .WriteString (v );};};return _fcfb .String ();};func (_cfe *Lexer )Lex (r _g .Reader ){_edb ,_ggc ,_dba :=0,0,0;
 _gfc :=-1;_ecb ,_eda ,_fbcg :=0,0,0;_ =_eda ;_ =_fbcg ;_dcg :=1;_ =_dcg ;_ffgd :=make ([]byte ,4096);_gbf :=false ;for !_gbf {_ffga :=0;if _ecb > 0{_ffga =_ggc -_ecb ;};_ggc =0;_dde ,_dbde :=r .Read (_ffgd [_ffga :]);if _dde ==0||_dbde !=nil {_gbf =true ;
 };_dba =_dde +_ffga ;if _dba < len (_ffgd ){_gfc =_dba ;};{_edb =_bdg ;_ecb =0;_eda =0;_fbcg =0;};{if _ggc ==_dba {goto _bbde ;};switch _edb {case 34:goto _fbf ;case 35:goto _bbca ;case 0:goto _abaf ;case 36:goto _gca ;case 37:goto _cgc ;case 1:goto _ddfa ;
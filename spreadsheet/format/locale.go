@@ -0,0 +1,295 @@
+package format
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Locale contains the conventions used to display numbers and dates in a
+// region, matching the regional settings that Excel uses when displaying
+// values.
+type Locale struct {
+	// Tag is the language tag of the locale, e.g. "de-DE".
+	Tag string
+	// LCID is the Windows locale identifier used in format codes like [$-407].
+	LCID uint32
+
+	Decimal string
+	Group   string
+
+	// Currency is the currency symbol, CurrencyAfter places it after the
+	// number and CurrencySpace separates it from the number by a space.
+	Currency      string
+	CurrencyAfter bool
+	CurrencySpace bool
+
+	// ShortDate and LongDate are the format codes of the system date formats,
+	// Time and ShortTime of the system time formats.
+	ShortDate string
+	LongDate  string
+	Time      string
+	ShortTime string
+
+	// Month and day names, days start on Sunday.
+	Months      [12]string
+	MonthsShort [12]string
+	Days        [7]string
+	DaysShort   [7]string
+
+	AM, PM      string
+	True, False string
+
+	// Eras are the eras of the calendar used by the g and e format codes,
+	// ordered by their start.
+	Eras []Era
+}
+
+// Era is an era of a calendar such as the Japanese emperor calendar.
+type Era struct {
+	Name   string
+	Short  string
+	Letter string
+	Start  time.Time
+}
+
+var englishMonths = [12]string{"January", "February", "March", "April", "May", "June",
+	"July", "August", "September", "October", "November", "December"}
+var englishMonthsShort = [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun",
+	"Jul", "Aug", "Sep", "Oct", "Nov", "Dec"}
+var englishDays = [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"}
+var englishDaysShort = [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"}
+
+var cjkMonths = [12]string{"1月", "2月", "3月", "4月", "5月", "6月",
+	"7月", "8月", "9月", "10月", "11月", "12月"}
+
+var japaneseEras = []Era{
+	{"明治", "明", "M", time.Date(1868, 9, 8, 0, 0, 0, 0, time.UTC)},
+	{"大正", "大", "T", time.Date(1912, 7, 30, 0, 0, 0, 0, time.UTC)},
+	{"昭和", "昭", "S", time.Date(1926, 12, 25, 0, 0, 0, 0, time.UTC)},
+	{"平成", "平", "H", time.Date(1989, 1, 8, 0, 0, 0, 0, time.UTC)},
+	{"令和", "令", "R", time.Date(2019, 5, 1, 0, 0, 0, 0, time.UTC)},
+}
+
+// DefaultLocale is the locale used when none is specified, en-US.
+var DefaultLocale = locales[0]
+
+var locales = []Locale{
+	{
+		Tag: "en-US", LCID: 0x409, Decimal: ".", Group: ",",
+		Currency:  "$",
+		ShortDate: "m/d/yyyy", LongDate: "dddd, mmmm d, yyyy",
+		Time: "h:mm:ss AM/PM", ShortTime: "h:mm",
+		Months: englishMonths, MonthsShort: englishMonthsShort,
+		Days: englishDays, DaysShort: englishDaysShort,
+		AM: "AM", PM: "PM", True: "TRUE", False: "FALSE",
+	},
+	{
+		Tag: "en-GB", LCID: 0x809, Decimal: ".", Group: ",",
+		Currency:  "£",
+		ShortDate: "dd/mm/yyyy", LongDate: "dd mmmm yyyy",
+		Time: "hh:mm:ss", ShortTime: "hh:mm",
+		Months: englishMonths, MonthsShort: englishMonthsShort,
+		Days: englishDays, DaysShort: englishDaysShort,
+		AM: "am", PM: "pm", True: "TRUE", False: "FALSE",
+	},
+	{
+		Tag: "de-DE", LCID: 0x407, Decimal: ",", Group: ".",
+		Currency: "€", CurrencyAfter: true, CurrencySpace: true,
+		ShortDate: "dd.mm.yyyy", LongDate: "dddd, d. mmmm yyyy",
+		Time: "hh:mm:ss", ShortTime: "hh:mm",
+		Months: [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni",
+			"Juli", "August", "September", "Oktober", "November", "Dezember"},
+		MonthsShort: [12]string{"Jan", "Feb", "Mrz", "Apr", "Mai", "Jun",
+			"Jul", "Aug", "Sep", "Okt", "Nov", "Dez"},
+		Days:      [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+		DaysShort: [7]string{"So", "Mo", "Di", "Mi", "Do", "Fr", "Sa"},
+		AM:        "AM", PM: "PM", True: "WAHR", False: "FALSCH",
+	},
+	{
+		Tag: "fr-FR", LCID: 0x40C, Decimal: ",", Group: "\u00a0",
+		Currency: "€", CurrencyAfter: true, CurrencySpace: true,
+		ShortDate: "dd/mm/yyyy", LongDate: "dddd d mmmm yyyy",
+		Time: "hh:mm:ss", ShortTime: "hh:mm",
+		Months: [12]string{"janvier", "février", "mars", "avril", "mai", "juin",
+			"juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		MonthsShort: [12]string{"janv.", "févr.", "mars", "avr.", "mai", "juin",
+			"juil.", "août", "sept.", "oct.", "nov.", "déc."},
+		Days:      [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+		DaysShort: [7]string{"dim.", "lun.", "mar.", "mer.", "jeu.", "ven.", "sam."},
+		AM:        "AM", PM: "PM", True: "VRAI", False: "FAUX",
+	},
+	{
+		Tag: "es-ES", LCID: 0xC0A, Decimal: ",", Group: ".",
+		Currency: "€", CurrencyAfter: true, CurrencySpace: true,
+		ShortDate: "dd/mm/yyyy", LongDate: `dddd, d "de" mmmm "de" yyyy`,
+		Time: "h:mm:ss", ShortTime: "h:mm",
+		Months: [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio",
+			"julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		MonthsShort: [12]string{"ene", "feb", "mar", "abr", "may", "jun",
+			"jul", "ago", "sep", "oct", "nov", "dic"},
+		Days:      [7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+		DaysShort: [7]string{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"},
+		AM:        "a. m.", PM: "p. m.", True: "VERDADERO", False: "FALSO",
+	},
+	{
+		Tag: "it-IT", LCID: 0x410, Decimal: ",", Group: ".",
+		Currency: "€", CurrencySpace: true,
+		ShortDate: "dd/mm/yyyy", LongDate: "dddd d mmmm yyyy",
+		Time: "hh:mm:ss", ShortTime: "hh:mm",
+		Months: [12]string{"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno",
+			"luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"},
+		MonthsShort: [12]string{"gen", "feb", "mar", "apr", "mag", "giu",
+			"lug", "ago", "set", "ott", "nov", "dic"},
+		Days:      [7]string{"domenica", "lunedì", "martedì", "mercoledì", "giovedì", "venerdì", "sabato"},
+		DaysShort: [7]string{"dom", "lun", "mar", "mer", "gio", "ven", "sab"},
+		AM:        "AM", PM: "PM", True: "VERO", False: "FALSO",
+	},
+	{
+		Tag: "nl-NL", LCID: 0x413, Decimal: ",", Group: ".",
+		Currency: "€", CurrencySpace: true,
+		ShortDate: "d-m-yyyy", LongDate: "dddd d mmmm yyyy",
+		Time: "hh:mm:ss", ShortTime: "hh:mm",
+		Months: [12]string{"januari", "februari", "maart", "april", "mei", "juni",
+			"juli", "augustus", "september", "oktober", "november", "december"},
+		MonthsShort: [12]string{"jan", "feb", "mrt", "apr", "mei", "jun",
+			"jul", "aug", "sep", "okt", "nov", "dec"},
+		Days:      [7]string{"zondag", "maandag", "dinsdag", "woensdag", "donderdag", "vrijdag", "zaterdag"},
+		DaysShort: [7]string{"zo", "ma", "di", "wo", "do", "vr", "za"},
+		AM:        "a.m.", PM: "p.m.", True: "WAAR", False: "ONWAAR",
+	},
+	{
+		Tag: "pt-BR", LCID: 0x416, Decimal: ",", Group: ".",
+		Currency: "R$", CurrencySpace: true,
+		ShortDate: "dd/mm/yyyy", LongDate: `dddd, d "de" mmmm "de" yyyy`,
+		Time: "hh:mm:ss", ShortTime: "hh:mm",
+		Months: [12]string{"janeiro", "fevereiro", "março", "abril", "maio", "junho",
+			"julho", "agosto", "setembro", "outubro", "novembro", "dezembro"},
+		MonthsShort: [12]string{"jan", "fev", "mar", "abr", "mai", "jun",
+			"jul", "ago", "set", "out", "nov", "dez"},
+		Days: [7]string{"domingo", "segunda-feira", "terça-feira", "quarta-feira",
+			"quinta-feira", "sexta-feira", "sábado"},
+		DaysShort: [7]string{"dom", "seg", "ter", "qua", "qui", "sex", "sáb"},
+		AM:        "AM", PM: "PM", True: "VERDADEIRO", False: "FALSO",
+	},
+	{
+		Tag: "ja-JP", LCID: 0x411, Decimal: ".", Group: ",",
+		Currency:  "¥",
+		ShortDate: "yyyy/m/d", LongDate: `yyyy"年"m"月"d"日"`,
+		Time: "h:mm:ss", ShortTime: "h:mm",
+		Months: cjkMonths, MonthsShort: cjkMonths,
+		Days:      [7]string{"日曜日", "月曜日", "火曜日", "水曜日", "木曜日", "金曜日", "土曜日"},
+		DaysShort: [7]string{"日", "月", "火", "水", "木", "金", "土"},
+		AM:        "午前", PM: "午後", True: "TRUE", False: "FALSE",
+		Eras: japaneseEras,
+	},
+	{
+		Tag: "zh-CN", LCID: 0x804, Decimal: ".", Group: ",",
+		Currency:  "¥",
+		ShortDate: "yyyy/m/d", LongDate: `yyyy"年"m"月"d"日"`,
+		Time: "h:mm:ss", ShortTime: "h:mm",
+		Months: [12]string{"一月", "二月", "三月", "四月", "五月", "六月",
+			"七月", "八月", "九月", "十月", "十一月", "十二月"},
+		MonthsShort: cjkMonths,
+		Days:        [7]string{"星期日", "星期一", "星期二", "星期三", "星期四", "星期五", "星期六"},
+		DaysShort:   [7]string{"周日", "周一", "周二", "周三", "周四", "周五", "周六"},
+		AM:          "上午", PM: "下午", True: "TRUE", False: "FALSE",
+	},
+}
+
+// LookupLocale returns the locale with the given language tag, e.g. "de-DE".
+func LookupLocale(tag string) (Locale, bool) {
+	tag = strings.ReplaceAll(tag, "_", "-")
+	for _, l := range locales {
+		if strings.EqualFold(l.Tag, tag) {
+			return l, true
+		}
+	}
+	return Locale{}, false
+}
+
+// LocaleFromLCID returns the locale with the given Windows locale identifier
+// as used in [$-407] format codes.
+func LocaleFromLCID(lcid uint32) (Locale, bool) {
+	lcid &= 0xffff
+	for _, l := range locales {
+		if l.LCID == lcid {
+			return l, true
+		}
+	}
+	return Locale{}, false
+}
+
+// BuiltinFormat returns the format code that Excel displays a built in number
+// format with in the locale. Only the built in formats that depend on the
+// regional settings are returned.
+func (l Locale) BuiltinFormat(id uint32) (string, bool) {
+	sym, sp := `"`+l.Currency+`"`, ""
+	if l.CurrencySpace {
+		sp = " "
+	}
+	us := l.Tag == "en-US"
+	currency := func(n string) string {
+		if l.CurrencyAfter {
+			return n + sp + sym
+		}
+		return sym + sp + n
+	}
+	signed := func(pos string, red bool) string {
+		c := ""
+		if red {
+			c = "[Red]"
+		}
+		if us {
+			return pos + "_);" + c + "(" + pos + ")"
+		}
+		return pos + ";" + c + "-" + pos
+	}
+	accounting := func(n, zero string, cur bool) string {
+		pre, post := "", ""
+		if cur && l.CurrencyAfter {
+			post = sp + sym
+		} else if cur {
+			pre = sym + sp
+		}
+		if us {
+			return "_(" + pre + "* " + n + post + "_);_(" + pre + "* (" + n + ")" + post +
+				";_(" + pre + "* " + zero + post + "_);_(@_)"
+		}
+		return "_-" + pre + "* " + n + post + "_-;-" + pre + "* " + n + post +
+			"_-;_-" + pre + "* " + zero + post + "_-;_-@_-"
+	}
+	switch id {
+	case 5:
+		return signed(currency("#,##0"), false), true
+	case 6:
+		return signed(currency("#,##0"), true), true
+	case 7:
+		return signed(currency("#,##0.00"), false), true
+	case 8:
+		return signed(currency("#,##0.00"), true), true
+	case 14:
+		return l.ShortDate, true
+	case 22:
+		return l.ShortDate + " " + l.ShortTime, true
+	case 41:
+		return accounting("#,##0", `"-"`, false), true
+	case 42:
+		return accounting("#,##0", `"-"`, true), true
+	case 43:
+		return accounting("#,##0.00", `"-"??`, false), true
+	case 44:
+		return accounting("#,##0.00", `"-"??`, true), true
+	}
+	return "", false
+}
+
+// Value formats a value as a number or string depending on if it appears to
+// be a number or string.
+func (l Locale) Value(v string, f string) string {
+	if IsNumber(v) {
+		n, _ := strconv.ParseFloat(v, 64)
+		return l.Number(n, f)
+	}
+	return l.String(v, f)
+}
@@ -0,0 +1,98 @@
+package format_test
+
+import (
+	"testing"
+
+	"github.com/yaklabco/unioffice/v2/spreadsheet/format"
+)
+
+func TestLocaleNumber(t *testing.T) {
+	de, _ := format.LookupLocale("de-DE")
+	fr, _ := format.LookupLocale("fr-FR")
+	ja, _ := format.LookupLocale("ja-JP")
+	us := format.DefaultLocale
+	// 2024-03-05 14:07:09
+	date := 45356.58829861111
+	td := []struct {
+		loc format.Locale
+		v   float64
+		f   string
+		exp string
+	}{
+		{us, 1234567.891, "#,##0.00", "1,234,567.89"},
+		{de, 1234567.891, "#,##0.00", "1.234.567,89"},
+		{fr, 1234567.891, "#,##0.00", "1 234 567,89"},
+		{de, -1234.5, `#,##0.00 [$€-407]`, "-1.234,50 €"},
+		{de, 1234.5, `[$€-2] #,##0.00`, "€ 1.234,50"},
+		{us, 2.675, "0.00", "2.68"},
+		{us, 0.1234, "0.0%", "12.3%"},
+		{us, 1234567, "#,##0,", "1,235"},
+		{us, 12345, "0.00E+00", "1.23E+04"},
+		{de, 12345, "0.00E+00", "1,23E+04"},
+		{us, 12345, "##0.0E+0", "12.3E+3"},
+		{us, 1.5, "# ?/?", "1 1/2"},
+		{us, 0.75, "# ??/100", " 75/100"},
+		{us, -5, "0;(0)", "(5)"},
+		{us, 0, `0;-0;"zero"`, "zero"},
+		{us, 150, `[>100]"big";"small"`, "big"},
+		{us, 5, "000-00", "000-05"},
+		{de, 1.5, "General", "1,5"},
+		{us, date, "yyyy-mm-dd hh:mm:ss", "2024-03-05 14:07:09"},
+		{us, date, "mmmm d, yyyy h:mm AM/PM", "March 5, 2024 2:07 PM"},
+		{de, date, "dddd, d. mmmm yyyy", "Dienstag, 5. März 2024"},
+		{us, date, "[$-407]dddd, d. mmmm yyyy", "Dienstag, 5. März 2024"},
+		{fr, date, "[$-F800]dddd, mmmm dd, yyyy", "mardi 5 mars 2024"},
+		{de, date, "[$-F400]h:mm:ss AM/PM", "14:07:09"},
+		{ja, date, `ggge"年"m"月"d"日"`, "令和6年3月5日"},
+		{ja, date, `[$-411]gge.m.d aaa`, "令6.3.5 火"},
+		{us, 1.5, "[h]:mm", "36:00"},
+		{us, 0.5000058, "hh:mm:ss.00", "12:00:00.50"},
+		// Excel counts February 29, 1900 as a day
+		{us, 0.25, "yyyy-mm-dd hh:mm", "1900-01-00 06:00"},
+		{us, 1, "yyyy-mm-dd ddd", "1900-01-01 Sun"},
+		{us, 59, "yyyy-mm-dd ddd", "1900-02-28 Tue"},
+		{us, 60, "yyyy-mm-dd ddd", "1900-02-29 Wed"},
+		{us, 61, "yyyy-mm-dd ddd", "1900-03-01 Thu"},
+	}
+	for _, tc := range td {
+		if got := tc.loc.Number(tc.v, tc.f); got != tc.exp {
+			t.Errorf("%s: expected %v formatted with %q to be %q, got %q", tc.loc.Tag, tc.v, tc.f, tc.exp, got)
+		}
+	}
+}
+
+func TestLocaleNumber1904(t *testing.T) {
+	us := format.DefaultLocale
+	if got := us.Number1904(0, "yyyy-mm-dd"); got != "1904-01-01" {
+		t.Errorf("expected the 1904 epoch, got %q", got)
+	}
+	if got := us.Number1904(43894.58829861111, "yyyy-mm-dd hh:mm:ss"); got != "2024-03-05 14:07:09" {
+		t.Errorf("unexpected 1904 date %q", got)
+	}
+}
+
+func TestLocaleBuiltinFormat(t *testing.T) {
+	de, _ := format.LookupLocale("de-DE")
+	f, ok := de.BuiltinFormat(14)
+	if !ok {
+		t.Fatalf("expected a locale specific short date format")
+	}
+	if got := de.Number(45356, f); got != "05.03.2024" {
+		t.Errorf("expected 05.03.2024, got %s", got)
+	}
+	f, _ = de.BuiltinFormat(8)
+	if got := de.Number(-1234.5, f); got != "-1.234,50 €" {
+		t.Errorf("expected -1.234,50 €, got %s", got)
+	}
+	us := format.DefaultLocale
+	f, _ = us.BuiltinFormat(7)
+	if got := us.Number(-1234.5, f); got != "($1,234.50)" {
+		t.Errorf("expected ($1,234.50), got %s", got)
+	}
+}
+
+func TestNumberLocaleTag(t *testing.T) {
+	if got := format.Number(1234.5, `[$€-407] #,##0.00`); got != "€ 1,234.50" {
+		t.Errorf("expected the currency symbol of the tag, got %s", got)
+	}
+}
@@ -0,0 +1,892 @@
+package format
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// sectionTokenType is the type of a token of a format section.
+type sectionTokenType byte
+
+const (
+	sectionLiteral sectionTokenType = iota
+	sectionDigit                    // 0, # or ?
+	sectionDecimal
+	sectionComma
+	sectionPercent
+	sectionExponent // E+ or E-
+	sectionSlash
+	sectionText
+	sectionGeneral
+	sectionDate // y, m, d, h, n (minute), s, AM/PM, elapsed time, eras
+)
+
+type sectionToken struct {
+	typ  sectionTokenType
+	text string
+}
+
+// sectionCondition is a condition such as [>100] selecting a format section.
+type sectionCondition struct {
+	op  string
+	val float64
+}
+
+func (c sectionCondition) matches(v float64) bool {
+	switch c.op {
+	case "<":
+		return v < c.val
+	case "<=":
+		return v <= c.val
+	case ">":
+		return v > c.val
+	case ">=":
+		return v >= c.val
+	case "<>":
+		return v != c.val
+	}
+	return v == c.val
+}
+
+// section is one of the up to four semicolon separated sections of a number
+// format.
+type section struct {
+	tokens []sectionToken
+	cond   *sectionCondition
+	// names is the locale of a [$-407] tag that month and day names are
+	// displayed in.
+	names *Locale
+	// system is set for the [$-F800] system long date and [$-F400] system time
+	// formats.
+	system string
+
+	isDate   bool
+	hasText  bool
+	hasAMPM  bool
+	grouping bool
+	scale    int
+	percent  int
+}
+
+func hasPrefixFold(rs []rune, prefix string) bool {
+	p := []rune(prefix)
+	if len(rs) < len(p) {
+		return false
+	}
+	return strings.EqualFold(string(rs[:len(p)]), prefix)
+}
+
+// parseSections splits a format code into its sections.
+func parseSections(f string) []*section {
+	secs := []*section{}
+	cur := &section{}
+	rs := []rune(f)
+	for i := 0; i < len(rs); i++ {
+		c := rs[i]
+		switch {
+		case c == ';':
+			secs = append(secs, cur.finish())
+			cur = &section{}
+		case c == '"':
+			j := i + 1
+			for j < len(rs) && rs[j] != '"' {
+				j++
+			}
+			cur.add(sectionLiteral, string(rs[i+1:min(j, len(rs))]))
+			i = j
+		case c == '\\':
+			if i+1 < len(rs) {
+				i++
+				cur.add(sectionLiteral, string(rs[i]))
+			}
+		case c == '_':
+			// skip the width of the next character
+			i++
+			cur.add(sectionLiteral, " ")
+		case c == '*':
+			// repeating a character to fill the cell isn't representable
+			i++
+		case c == '[':
+			j := i + 1
+			for j < len(rs) && rs[j] != ']' {
+				j++
+			}
+			cur.bracket(string(rs[i+1 : min(j, len(rs))]))
+			i = j
+		case c == '0' || c == '#' || c == '?':
+			cur.add(sectionDigit, string(c))
+		case c == '.':
+			cur.add(sectionDecimal, ".")
+		case c == ',':
+			cur.add(sectionComma, ",")
+		case c == '%':
+			cur.add(sectionPercent, "%")
+		case (c == 'E' || c == 'e') && i+1 < len(rs) && (rs[i+1] == '+' || rs[i+1] == '-'):
+			cur.add(sectionExponent, string(rs[i:i+2]))
+			i++
+		case c == '/':
+			cur.add(sectionSlash, "/")
+		case c == '@':
+			cur.add(sectionText, "@")
+		case hasPrefixFold(rs[i:], "General"):
+			cur.add(sectionGeneral, "General")
+			i += len("General") - 1
+		case hasPrefixFold(rs[i:], "AM/PM"):
+			cur.add(sectionDate, "AM/PM")
+			i += len("AM/PM") - 1
+		case hasPrefixFold(rs[i:], "A/P"):
+			cur.add(sectionDate, string(rs[i:i+3]))
+			i += len("A/P") - 1
+		case (c == 'B' || c == 'b') && i+1 < len(rs) && (rs[i+1] == '1' || rs[i+1] == '2'):
+			// B1 and B2 select the Gregorian and Hijri calendars
+			i++
+		case strings.ContainsRune("yYmMdDhHsSgGeEbBaA", c):
+			j := i
+			for j < len(rs) && unicode.ToLower(rs[j]) == unicode.ToLower(c) {
+				j++
+			}
+			code := strings.ToLower(string(rs[i:j]))
+			if code[0] == 'a' && len(code) < 3 {
+				cur.add(sectionLiteral, string(rs[i:j]))
+			} else {
+				cur.add(sectionDate, code)
+			}
+			i = j - 1
+		default:
+			cur.add(sectionLiteral, string(c))
+		}
+	}
+	return append(secs, cur.finish())
+}
+
+func (s *section) add(t sectionTokenType, text string) {
+	s.tokens = append(s.tokens, sectionToken{t, text})
+}
+
+// bracket handles the content of a [...] part of a format code.
+func (s *section) bracket(b string) {
+	switch {
+	case strings.HasPrefix(b, "$"):
+		sym, code := b[1:], ""
+		if i := strings.Index(sym, "-"); i >= 0 {
+			sym, code = sym[:i], sym[i+1:]
+		}
+		if sym != "" {
+			s.add(sectionLiteral, sym)
+		}
+		switch strings.ToLower(code) {
+		case "":
+		case "x-sysdate":
+			s.system = "date"
+		case "x-systime":
+			s.system = "time"
+		default:
+			lcid, err := strconv.ParseUint(code, 16, 32)
+			if err != nil {
+				return
+			}
+			switch lcid & 0xffff {
+			case 0xf800:
+				s.system = "date"
+			case 0xf400:
+				s.system = "time"
+			default:
+				if l, ok := LocaleFromLCID(uint32(lcid)); ok {
+					s.names = &l
+				}
+			}
+		}
+	case strings.HasPrefix(b, "<") || strings.HasPrefix(b, ">") || strings.HasPrefix(b, "="):
+		op := b[:1]
+		if len(b) > 1 && (b[1] == '=' || b[1] == '>') {
+			op = b[:2]
+		}
+		if v, err := strconv.ParseFloat(strings.TrimSpace(b[len(op):]), 64); err == nil {
+			s.cond = &sectionCondition{op, v}
+		}
+	default:
+		lb := strings.ToLower(b)
+		if lb != "" && strings.Count(lb, lb[:1]) == len(lb) && strings.Contains("hms", lb[:1]) {
+			s.add(sectionDate, "["+lb+"]")
+		}
+		// colors and other options don't affect the text
+	}
+}
+
+// finish classifies the tokens once the section is complete.
+func (s *section) finish() *section {
+	for _, t := range s.tokens {
+		switch t.typ {
+		case sectionDate:
+			s.isDate = true
+			if t.text == "AM/PM" || strings.EqualFold(t.text, "a/p") {
+				s.hasAMPM = true
+			}
+		case sectionText:
+			s.hasText = true
+		}
+	}
+	if s.isDate {
+		s.finishDate()
+		return s
+	}
+	// commas between digit placeholders group thousands, trailing commas
+	// scale by a thousand
+	lastInt := -1
+	for i, t := range s.tokens {
+		if t.typ == sectionDecimal || t.typ == sectionExponent || t.typ == sectionSlash {
+			break
+		}
+		if t.typ == sectionDigit {
+			lastInt = i
+		}
+	}
+	for i, t := range s.tokens {
+		switch t.typ {
+		case sectionComma:
+			if i < lastInt {
+				s.grouping = true
+			} else if lastInt >= 0 && s.onlyCommasBetween(lastInt, i) {
+				s.scale++
+			}
+		case sectionPercent:
+			s.percent++
+		}
+	}
+	return s
+}
+
+func (s *section) onlyCommasBetween(from, to int) bool {
+	for i := from + 1; i < to; i++ {
+		if s.tokens[i].typ != sectionComma {
+			return false
+		}
+	}
+	return true
+}
+
+// finishDate resolves minutes and fractional seconds in date sections.
+func (s *section) finishDate() {
+	tokens := []sectionToken{}
+	for i := 0; i < len(s.tokens); i++ {
+		t := s.tokens[i]
+		switch t.typ {
+		case sectionDate:
+			if t.text == "s" || t.text == "ss" {
+				// fractional seconds, e.g. ss.000
+				if i+1 < len(s.tokens) && s.tokens[i+1].typ == sectionDecimal {
+					j := i + 2
+					for j < len(s.tokens) && s.tokens[j].typ == sectionDigit && s.tokens[j].text == "0" {
+						j++
+					}
+					if j > i+2 {
+						t.text += "." + strings.Repeat("0", j-i-2)
+						i = j - 1
+					}
+				}
+			}
+		case sectionDecimal, sectionComma, sectionSlash, sectionPercent, sectionDigit, sectionExponent:
+			t.typ = sectionLiteral
+		}
+		tokens = append(tokens, t)
+	}
+	// m and mm are minutes when following hours or preceding seconds
+	for i, t := range tokens {
+		if t.typ != sectionDate || (t.text != "m" && t.text != "mm") {
+			continue
+		}
+		prev, next := "", ""
+		for j := i - 1; j >= 0; j-- {
+			if tokens[j].typ == sectionDate {
+				prev = tokens[j].text
+				break
+			}
+		}
+		for j := i + 1; j < len(tokens); j++ {
+			if tokens[j].typ == sectionDate {
+				next = tokens[j].text
+				break
+			}
+		}
+		if strings.HasPrefix(prev, "h") || strings.HasPrefix(prev, "[h") || strings.HasPrefix(next, "s") {
+			tokens[i].text = strings.Repeat("n", len(t.text))
+		}
+	}
+	s.tokens = tokens
+}
+
+// selectSection returns the section used to display v and whether a minus
+// sign must be shown.
+func selectSection(secs []*section, v float64) (*section, bool) {
+	numeric := secs
+	if len(numeric) > 3 {
+		numeric = numeric[:3]
+	}
+	if numeric[0].cond != nil || (len(numeric) > 1 && numeric[1].cond != nil) {
+		for _, s := range numeric {
+			if s.cond == nil || s.cond.matches(v) {
+				return s, v < 0
+			}
+		}
+		return numeric[len(numeric)-1], v < 0
+	}
+	switch {
+	case len(numeric) == 1 || v > 0:
+		return numeric[0], v < 0
+	case v < 0:
+		return numeric[1], false
+	case len(numeric) > 2:
+		return numeric[2], false
+	}
+	return numeric[0], false
+}
+
+// Number formats a number with a format code the way Excel displays it with
+// the locale as the regional settings. Dates are serials of the 1900 date
+// system, see Number1904.
+func (l Locale) Number(v float64, f string) string { return l.number(v, f, false) }
+
+// Number1904 formats a number like Number for workbooks using the 1904 date
+// system, where dates are days since January 1, 1904.
+func (l Locale) Number1904(v float64, f string) string { return l.number(v, f, true) }
+
+func (l Locale) number(v float64, f string, date1904 bool) string {
+	if f == "" || strings.EqualFold(f, "General") || f == "@" {
+		return l.general(v)
+	}
+	s, neg := selectSection(parseSections(f), v)
+	switch s.system {
+	case "date":
+		s = parseSections(l.LongDate)[0]
+	case "time":
+		s = parseSections(l.Time)[0]
+	}
+	names := l
+	if s.names != nil {
+		names = *s.names
+	}
+	if s.isDate {
+		return s.formatDate(v, l, names, date1904)
+	}
+	return s.formatNumber(math.Abs(v), neg, l)
+}
+
+// String formats a string with a format code, which is only affected by the
+// text section of the format.
+func (l Locale) String(v string, f string) string {
+	secs := parseSections(f)
+	var s *section
+	if len(secs) == 4 {
+		s = secs[3]
+	} else if len(secs) == 1 && secs[0].hasText {
+		s = secs[0]
+	}
+	if s == nil || !s.hasText {
+		return v
+	}
+	b := strings.Builder{}
+	for _, t := range s.tokens {
+		switch t.typ {
+		case sectionText:
+			b.WriteString(v)
+		case sectionLiteral:
+			b.WriteString(t.text)
+		}
+	}
+	return b.String()
+}
+
+func (l Locale) general(v float64) string {
+	return strings.Replace(NumberGeneric(v), ".", l.Decimal, 1)
+}
+
+// roundDecimal returns the integer and fractional digits of x rounded half
+// away from zero to frac fractional digits. Like Excel, only the first 15
+// significant digits of x are considered. The integer part has no leading
+// zeros.
+func roundDecimal(x float64, frac int) (string, string) {
+	s := strconv.FormatFloat(x, 'e', 14, 64)
+	e := strings.IndexByte(s, 'e')
+	exp, _ := strconv.Atoi(s[e+1:])
+	digits := strings.Replace(s[:e], ".", "", 1)
+	point := exp + 1
+	if point < 0 {
+		digits = strings.Repeat("0", -point) + digits
+		point = 0
+	}
+	if point > len(digits) {
+		digits += strings.Repeat("0", point-len(digits))
+	}
+	keep := point + frac
+	if keep < len(digits) {
+		up := digits[keep] >= '5'
+		digits = digits[:keep]
+		if up {
+			b := []byte(digits)
+			i := len(b) - 1
+			for ; i >= 0 && b[i] == '9'; i-- {
+				b[i] = '0'
+			}
+			if i >= 0 {
+				b[i]++
+			} else {
+				b = append([]byte{'1'}, b...)
+				point++
+			}
+			digits = string(b)
+		}
+	} else {
+		digits += strings.Repeat("0", keep-len(digits))
+	}
+	return strings.TrimLeft(digits[:point], "0"), digits[point:]
+}
+
+// numberWriter writes the digit placeholders of a number section.
+type numberWriter struct {
+	b        strings.Builder
+	l        Locale
+	grouping bool
+}
+
+// digit writes a digit at the given position counted from the right of the
+// integer part.
+func (w *numberWriter) digit(d byte, pos int) {
+	w.b.WriteByte(d)
+	if w.grouping && pos > 0 && pos%3 == 0 {
+		w.b.WriteString(w.l.Group)
+	}
+}
+
+// integer writes the integer digits for the placeholders.
+func (w *numberWriter) integer(digits string, placeholders []string) {
+	for i, ph := range placeholders {
+		w.integerDigit(digits, len(placeholders), i, ph)
+	}
+}
+
+// integerDigit writes the digit for the i-th of n integer placeholders, where
+// the first placeholder receives any digits that don't fit.
+func (w *numberWriter) integerDigit(digits string, n, i int, ph string) {
+	pos := n - 1 - i
+	if i == 0 {
+		for j := 0; j < len(digits)-n; j++ {
+			w.digit(digits[j], len(digits)-1-j)
+		}
+	}
+	switch {
+	case pos < len(digits):
+		w.digit(digits[len(digits)-1-pos], pos)
+	case ph == "0":
+		w.digit('0', pos)
+	case ph == "?":
+		w.b.WriteByte(' ')
+	}
+}
+
+// fraction writes fractional digits, dropping trailing zeros for # and ?
+// placeholders.
+func (w *numberWriter) fraction(digits string, placeholders []string) {
+	last := strings.LastIndexFunc(digits, func(r rune) bool { return r != '0' })
+	for i, ph := range placeholders {
+		switch {
+		case i <= last || ph == "0":
+			w.b.WriteByte(digits[i])
+		case ph == "?":
+			w.b.WriteByte(' ')
+		}
+	}
+}
+
+func (s *section) formatNumber(x float64, neg bool, l Locale) string {
+	for _, t := range s.tokens {
+		if t.typ == sectionSlash {
+			if f, ok := s.formatFraction(x, neg, l); ok {
+				return f
+			}
+			break
+		}
+	}
+	x *= math.Pow(100, float64(s.percent))
+	x /= math.Pow(1000, float64(s.scale))
+
+	// split the placeholders into the integer, fraction and exponent parts
+	var intPh, fracPh, expPh []string
+	part := 0
+	exponent := ""
+	for _, t := range s.tokens {
+		switch t.typ {
+		case sectionDecimal:
+			if part == 0 {
+				part = 1
+			}
+		case sectionExponent:
+			part = 2
+			exponent = t.text
+		case sectionDigit:
+			switch part {
+			case 0:
+				intPh = append(intPh, t.text)
+			case 1:
+				fracPh = append(fracPh, t.text)
+			default:
+				expPh = append(expPh, t.text)
+			}
+		}
+	}
+
+	exp := 0
+	intDigits, fracDigits := roundDecimal(x, len(fracPh))
+	if exponent != "" && x != 0 {
+		n := max(len(intPh), 1)
+		engineering := strings.Contains(strings.Join(intPh, ""), "#")
+		exp = int(math.Floor(math.Log10(x)))
+		if engineering {
+			exp = int(math.Floor(float64(exp)/float64(n))) * n
+		} else {
+			exp -= n - 1
+		}
+		for {
+			intDigits, fracDigits = roundDecimal(x/math.Pow10(exp), len(fracPh))
+			if len(intDigits) <= n {
+				break
+			}
+			if engineering {
+				exp += n
+			} else {
+				exp++
+			}
+		}
+	}
+	if neg && strings.Trim(intDigits+fracDigits, "0") == "" {
+		// values rounded to zero are displayed without a sign
+		neg = false
+	}
+
+	w := &numberWriter{l: l, grouping: s.grouping}
+	if neg {
+		w.b.WriteByte('-')
+	}
+	part = 0
+	intIndex, fracDone := 0, false
+	for _, t := range s.tokens {
+		switch t.typ {
+		case sectionDigit:
+			switch part {
+			case 0:
+				w.integerDigit(intDigits, len(intPh), intIndex, t.text)
+				intIndex++
+			case 1:
+				if !fracDone {
+					w.fraction(fracDigits, fracPh)
+					fracDone = true
+				}
+			}
+		case sectionDecimal:
+			if part == 0 {
+				part = 1
+				w.b.WriteString(l.Decimal)
+			} else {
+				w.b.WriteString(t.text)
+			}
+		case sectionExponent:
+			part = 2
+			w.b.WriteByte('E')
+			if exp < 0 {
+				w.b.WriteByte('-')
+			} else if t.text[1] == '+' {
+				w.b.WriteByte('+')
+			}
+			e := strconv.Itoa(int(math.Abs(float64(exp))))
+			zeros := strings.Count(strings.Join(expPh, ""), "0")
+			if len(e) < zeros {
+				e = strings.Repeat("0", zeros-len(e)) + e
+			}
+			w.b.WriteString(e)
+		case sectionGeneral:
+			w.b.WriteString(l.general(x))
+		case sectionLiteral, sectionPercent:
+			w.b.WriteString(t.text)
+		case sectionSlash:
+			w.b.WriteByte('/')
+		case sectionText, sectionComma:
+		}
+	}
+	return w.b.String()
+}
+
+// formatFraction formats a number with a fraction format such as # ?/? or
+// # ??/100.
+func (s *section) formatFraction(x float64, neg bool, l Locale) (string, bool) {
+	slash := -1
+	for i, t := range s.tokens {
+		if t.typ == sectionSlash {
+			slash = i
+			break
+		}
+	}
+	// the numerator is the run of placeholders before the slash, the
+	// denominator either placeholders or a fixed number after it
+	numStart := slash
+	for numStart > 0 && s.tokens[numStart-1].typ == sectionDigit {
+		numStart--
+	}
+	if numStart == slash {
+		return "", false
+	}
+	denEnd := slash + 1
+	fixed := ""
+	for denEnd < len(s.tokens) {
+		t := s.tokens[denEnd]
+		isNumber := len(t.text) == 1 && t.text[0] >= '0' && t.text[0] <= '9'
+		if t.typ == sectionDigit && fixed == "" {
+			denEnd++
+		} else if (t.typ == sectionLiteral || fixed != "") && isNumber && denEnd-slash-1 == len(fixed) {
+			fixed += t.text
+			denEnd++
+		} else {
+			break
+		}
+	}
+	if denEnd == slash+1 {
+		return "", false
+	}
+	var intPh []string
+	for _, t := range s.tokens[:numStart] {
+		if t.typ == sectionDigit {
+			intPh = append(intPh, t.text)
+		}
+	}
+	var numPh, denPh []string
+	for _, t := range s.tokens[numStart:slash] {
+		numPh = append(numPh, t.text)
+	}
+	for _, t := range s.tokens[slash+1 : denEnd] {
+		denPh = append(denPh, t.text)
+	}
+
+	whole := 0.0
+	frac := x
+	if len(intPh) > 0 {
+		whole = math.Floor(x)
+		frac = x - whole
+	}
+	var num, den int64
+	if fixed != "" {
+		den, _ = strconv.ParseInt(fixed, 10, 64)
+		num = int64(math.Round(frac * float64(den)))
+	} else {
+		maxDen := int64(math.Pow10(len(denPh))) - 1
+		best := math.Inf(1)
+		for d := int64(1); d <= maxDen; d++ {
+			n := int64(math.Round(frac * float64(d)))
+			if e := math.Abs(frac - float64(n)/float64(d)); e < best {
+				best, num, den = e, n, d
+			}
+		}
+	}
+	if len(intPh) > 0 && num == den {
+		whole++
+		num = 0
+	}
+	if neg && whole == 0 && num == 0 {
+		neg = false
+	}
+
+	w := &numberWriter{l: l, grouping: s.grouping}
+	if neg {
+		w.b.WriteByte('-')
+	}
+	wholeDigits := ""
+	if whole > 0 {
+		wholeDigits = strconv.FormatFloat(whole, 'f', 0, 64)
+	} else if num == 0 {
+		wholeDigits = "0"
+	}
+	intIndex := 0
+	for i := 0; i < len(s.tokens); i++ {
+		t := s.tokens[i]
+		switch {
+		case i < numStart && t.typ == sectionDigit:
+			w.integerDigit(wholeDigits, len(intPh), intIndex, t.text)
+			intIndex++
+		case i == numStart:
+			if num == 0 && len(intPh) > 0 {
+				// zero fractions are replaced by spaces
+				w.b.WriteString(strings.Repeat(" ", len(numPh)+1+len(denPh)))
+			} else {
+				n := strconv.FormatInt(num, 10)
+				w.integer(n, numPh)
+				w.b.WriteByte('/')
+				d := strconv.FormatInt(den, 10)
+				w.b.WriteString(d)
+				for j := len(d); j < len(denPh); j++ {
+					if denPh[j] == "?" {
+						w.b.WriteByte(' ')
+					}
+				}
+			}
+			i = denEnd - 1
+		case t.typ == sectionLiteral || t.typ == sectionPercent:
+			w.b.WriteString(t.text)
+		case t.typ == sectionDecimal:
+			w.b.WriteString(l.Decimal)
+		}
+	}
+	return w.b.String(), true
+}
+
+var (
+	excelEpoch     = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	excelEpoch1904 = time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func (s *section) formatDate(v float64, l, names Locale, date1904 bool) string {
+	if v < 0 {
+		return "#####"
+	}
+	// round to the precision of the displayed seconds
+	precision := 1.0
+	for _, t := range s.tokens {
+		if t.typ == sectionDate && strings.HasPrefix(t.text, "s") {
+			if i := strings.IndexByte(t.text, '.'); i >= 0 {
+				precision = math.Pow10(len(t.text) - i - 1)
+			}
+		}
+	}
+	ticks := math.Round(v * 86400 * precision)
+	elapsed := ticks / precision
+	epoch := excelEpoch
+	if date1904 {
+		epoch = excelEpoch1904
+	}
+	t := epoch.Add(time.Duration(ticks) * (time.Second / time.Duration(precision)))
+	// the weekdays follow t but Excel counts February 29, 1900 as a day, so
+	// the earlier serials are dates one day later, serial 0 being January 0
+	year, month, day := t.Date()
+	if !date1904 && ticks < 61*86400*precision {
+		switch {
+		case ticks < 86400*precision:
+			year, month, day = 1900, time.January, 0
+		case ticks >= 60*86400*precision:
+			year, month, day = 1900, time.February, 29
+		default:
+			year, month, day = t.AddDate(0, 0, 1).Date()
+		}
+	}
+	date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+
+	b := strings.Builder{}
+	for _, tok := range s.tokens {
+		if tok.typ != sectionDate {
+			if tok.typ == sectionLiteral {
+				b.WriteString(tok.text)
+			}
+			continue
+		}
+		code := tok.text
+		switch {
+		case code == "y" || code == "yy":
+			b.WriteString(pad(year%100, 2))
+		case strings.HasPrefix(code, "y"):
+			b.WriteString(pad(year, 4))
+		case code == "m":
+			b.WriteString(strconv.Itoa(int(month)))
+		case code == "mm":
+			b.WriteString(pad(int(month), 2))
+		case code == "mmm":
+			b.WriteString(names.MonthsShort[month-1])
+		case code == "mmmmm":
+			b.WriteString(string([]rune(names.Months[month-1])[:1]))
+		case strings.HasPrefix(code, "mmmm"):
+			b.WriteString(names.Months[month-1])
+		case code == "d":
+			b.WriteString(strconv.Itoa(day))
+		case code == "dd":
+			b.WriteString(pad(day, 2))
+		case code == "ddd" || code == "aaa":
+			b.WriteString(names.DaysShort[t.Weekday()])
+		case strings.HasPrefix(code, "d") || strings.HasPrefix(code, "a"):
+			b.WriteString(names.Days[t.Weekday()])
+		case code == "h" || code == "hh":
+			h := t.Hour()
+			if s.hasAMPM {
+				h %= 12
+				if h == 0 {
+					h = 12
+				}
+			}
+			b.WriteString(pad(h, len(code)))
+		case code == "n" || code == "nn":
+			b.WriteString(pad(t.Minute(), len(code)))
+		case strings.HasPrefix(code, "s"):
+			digits := strings.TrimLeft(code, "s")
+			b.WriteString(pad(t.Second(), len(code)-len(digits)))
+			if len(digits) > 1 {
+				frac := strconv.Itoa(t.Nanosecond() / int(time.Second/time.Duration(math.Pow10(len(digits)-1))))
+				b.WriteString(l.Decimal + strings.Repeat("0", len(digits)-1-len(frac)) + frac)
+			}
+		case code == "[h]" || code == "[hh]":
+			b.WriteString(pad(int(elapsed/3600), len(code)-2))
+		case code == "[m]" || code == "[mm]":
+			b.WriteString(pad(int(elapsed/60), len(code)-2))
+		case code == "[s]" || code == "[ss]":
+			b.WriteString(pad(int(elapsed), len(code)-2))
+		case code == "AM/PM":
+			if t.Hour() < 12 {
+				b.WriteString(names.AM)
+			} else {
+				b.WriteString(names.PM)
+			}
+		case strings.EqualFold(code, "a/p"):
+			if t.Hour() < 12 {
+				b.WriteByte(code[0])
+			} else {
+				b.WriteByte(code[2])
+			}
+		case strings.HasPrefix(code, "g"):
+			if era, ok := eraOf(names, date); ok {
+				switch len(code) {
+				case 1:
+					b.WriteString(era.Letter)
+				case 2:
+					b.WriteString(era.Short)
+				default:
+					b.WriteString(era.Name)
+				}
+			}
+		case strings.HasPrefix(code, "e"):
+			year := year
+			if era, ok := eraOf(names, date); ok {
+				year = year - era.Start.Year() + 1
+			}
+			b.WriteString(pad(year, len(code)))
+		case code == "b" || code == "bb":
+			b.WriteString(pad((year+543)%100, 2))
+		case strings.HasPrefix(code, "b"):
+			b.WriteString(strconv.Itoa(year + 543))
+		}
+	}
+	return b.String()
+}
+
+// eraOf returns the era of the locale's calendar that contains t.
+func eraOf(l Locale, t time.Time) (Era, bool) {
+	for i := len(l.Eras) - 1; i >= 0; i-- {
+		if !t.Before(l.Eras[i].Start) {
+			return l.Eras[i], true
+		}
+	}
+	return Era{}, false
+}
+
+// pad formats v with at least n digits.
+func pad(v, n int) string {
+	s := strconv.Itoa(v)
+	if len(s) < n {
+		s = strings.Repeat("0", n-len(s)) + s
+	}
+	return s
+}
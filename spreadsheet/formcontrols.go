@@ -0,0 +1,732 @@
+package spreadsheet
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/yaklabco/unioffice/v2"
+	"github.com/yaklabco/unioffice/v2/schema/soo/dml/spreadsheetDrawing"
+	"github.com/yaklabco/unioffice/v2/schema/soo/ofc/sharedTypes"
+	"github.com/yaklabco/unioffice/v2/schema/soo/sml"
+	"github.com/yaklabco/unioffice/v2/schema/urn/schemas_microsoft_com/office/excel"
+	"github.com/yaklabco/unioffice/v2/schema/urn/schemas_microsoft_com/vml"
+	"github.com/yaklabco/unioffice/v2/spreadsheet/reference"
+	"github.com/yaklabco/unioffice/v2/vmldrawing"
+)
+
+const (
+	ctrlPropType        = "http://schemas.microsoft.com/office/2006/relationships/ctrlProp"
+	ctrlPropContentType = "application/vnd.ms-excel.controlproperties+xml"
+)
+
+// FormControlType is the type of a form control.
+type FormControlType byte
+
+// FormControlType constants.
+const (
+	FormControlUnknown FormControlType = iota
+	FormControlCheckBox
+	FormControlComboBox
+	FormControlListBox
+	FormControlOptionButton
+	FormControlSpinner
+	FormControlScrollBar
+	FormControlButton
+	FormControlGroupBox
+)
+
+var formControlObjectTypes = map[FormControlType]excel.ST_ObjectType{
+	FormControlCheckBox:     excel.ST_ObjectTypeCheckbox,
+	FormControlComboBox:     excel.ST_ObjectTypeDrop,
+	FormControlListBox:      excel.ST_ObjectTypeList,
+	FormControlOptionButton: excel.ST_ObjectTypeRadio,
+	FormControlSpinner:      excel.ST_ObjectTypeSpin,
+	FormControlScrollBar:    excel.ST_ObjectTypeScroll,
+	FormControlButton:       excel.ST_ObjectTypeButton,
+	FormControlGroupBox:     excel.ST_ObjectTypeGBox,
+}
+
+// String returns the name used by applications for controls of the type,
+// e.g. "Check Box".
+func (t FormControlType) String() string {
+	switch t {
+	case FormControlCheckBox:
+		return "Check Box"
+	case FormControlComboBox:
+		return "Drop Down"
+	case FormControlListBox:
+		return "List Box"
+	case FormControlOptionButton:
+		return "Option Button"
+	case FormControlSpinner:
+		return "Spinner"
+	case FormControlScrollBar:
+		return "Scroll Bar"
+	case FormControlButton:
+		return "Button"
+	case FormControlGroupBox:
+		return "Group Box"
+	}
+	return "Unknown"
+}
+
+// ctrlPropObjectType returns the object type of the control properties part.
+func (t FormControlType) ctrlPropObjectType() string {
+	switch t {
+	case FormControlCheckBox:
+		return "CheckBox"
+	case FormControlComboBox:
+		return "Drop"
+	case FormControlListBox:
+		return "List"
+	case FormControlOptionButton:
+		return "Radio"
+	case FormControlSpinner:
+		return "Spin"
+	case FormControlScrollBar:
+		return "Scroll"
+	case FormControlButton:
+		return "Button"
+	case FormControlGroupBox:
+		return "GBox"
+	}
+	return ""
+}
+
+type xmlFormControlPr struct {
+	XMLName     xml.Name   `xml:"http://schemas.microsoft.com/office/spreadsheetml/2009/9/main formControlPr"`
+	ObjectType  string     `xml:"objectType,attr"`
+	Checked     string     `xml:"checked,attr,omitempty"`
+	DropLines   *int64     `xml:"dropLines,attr"`
+	DropStyle   string     `xml:"dropStyle,attr,omitempty"`
+	Dx          *int64     `xml:"dx,attr"`
+	FirstButton bool       `xml:"firstButton,attr,omitempty"`
+	FmlaLink    string     `xml:"fmlaLink,attr,omitempty"`
+	FmlaRange   string     `xml:"fmlaRange,attr,omitempty"`
+	Horiz       bool       `xml:"horiz,attr,omitempty"`
+	Inc         *int64     `xml:"inc,attr"`
+	LockText    bool       `xml:"lockText,attr,omitempty"`
+	Max         *int64     `xml:"max,attr"`
+	Min         *int64     `xml:"min,attr"`
+	NoThreeD    bool       `xml:"noThreeD,attr,omitempty"`
+	Page        *int64     `xml:"page,attr"`
+	Sel         *int64     `xml:"sel,attr"`
+	SelType     string     `xml:"selType,attr,omitempty"`
+	Val         *int64     `xml:"val,attr"`
+	ExtLst      *xmlExtLst `xml:"extLst,omitempty"`
+}
+
+// formControlState holds the form controls of the workbook, both those read
+// with it and those added, their control properties parts are written from the
+// shapes when the workbook is saved.
+type formControlState struct {
+	parts []*formControlPart
+}
+
+type formControlPart struct {
+	path  string
+	shape *vml.Shape
+	ctrl  *sml.CT_Control
+	// pr are the control properties read with the workbook, the properties
+	// not held by the shape are written back unchanged.
+	pr *xmlFormControlPr
+}
+
+// FormControl is a form control (check box, drop down, button etc.) on a
+// sheet. Its properties are stored in the client data of its shape in the
+// legacy drawing of the sheet.
+type FormControl struct {
+	s     *Sheet
+	shape *vml.Shape
+	cd    *excel.ClientData
+}
+
+// X returns the inner wrapped XML type.
+func (fc FormControl) X() *vml.Shape { return fc.shape }
+
+// ClientData returns the client data of the control's shape.
+func (fc FormControl) ClientData() *excel.ClientData { return fc.cd }
+
+// Type returns the type of the control.
+func (fc FormControl) Type() FormControlType {
+	for t, ot := range formControlObjectTypes {
+		if ot == fc.cd.ObjectTypeAttr {
+			return t
+		}
+	}
+	return FormControlUnknown
+}
+
+// shapeID returns the numeric id of the control's shape.
+func (fc FormControl) shapeID() uint32 {
+	if fc.shape.IdAttr == nil {
+		return 0
+	}
+	id, _ := strconv.ParseUint(strings.TrimPrefix(*fc.shape.IdAttr, "_x0000_s"), 10, 32)
+	return uint32(id)
+}
+
+// Name returns the name of the control, e.g. "Check Box 1".
+func (fc FormControl) Name() string {
+	if fc.s._bbbe.Controls == nil {
+		return ""
+	}
+	id := fc.shapeID()
+	for _, c := range fc.s._bbbe.Controls.Control {
+		if c.ShapeIdAttr == id && c.NameAttr != nil {
+			return *c.NameAttr
+		}
+	}
+	return ""
+}
+
+// Anchor returns the cells the control covers, e.g. "B2:C3".
+func (fc FormControl) Anchor() string {
+	v := fc.stringValue(func(c *excel.CT_ClientDataChoice) **string { return &c.Anchor })
+	a := [8]uint32{}
+	for i, f := range strings.Split(v, ",") {
+		if i < len(a) {
+			n, _ := strconv.ParseUint(strings.TrimSpace(f), 10, 32)
+			a[i] = uint32(n)
+		}
+	}
+	right, bottom := a[4], a[6]
+	// the right and bottom cells are only covered if the control extends into
+	// them
+	if a[5] == 0 && right > a[0] {
+		right--
+	}
+	if a[7] == 0 && bottom > a[2] {
+		bottom--
+	}
+	return fmt.Sprintf("%s%d:%s%d", reference.IndexToColumn(a[0]), a[2]+1, reference.IndexToColumn(right), bottom+1)
+}
+
+// choice returns the first client data choice that set returns true for.
+func (fc FormControl) choice(set func(c *excel.CT_ClientDataChoice) bool) *excel.CT_ClientDataChoice {
+	for _, c := range fc.cd.ClientDataChoice {
+		if set(c) {
+			return c
+		}
+	}
+	return nil
+}
+
+func (fc FormControl) stringValue(field func(c *excel.CT_ClientDataChoice) **string) string {
+	c := fc.choice(func(c *excel.CT_ClientDataChoice) bool { return *field(c) != nil })
+	if c == nil {
+		return ""
+	}
+	return **field(c)
+}
+
+// setString sets a string field of the client data, removing it if v is
+// empty.
+func (fc FormControl) setString(field func(c *excel.CT_ClientDataChoice) **string, v string) {
+	for i, c := range fc.cd.ClientDataChoice {
+		if *field(c) == nil {
+			continue
+		}
+		if v == "" {
+			fc.cd.ClientDataChoice = append(fc.cd.ClientDataChoice[:i], fc.cd.ClientDataChoice[i+1:]...)
+		} else {
+			*field(c) = unioffice.String(v)
+		}
+		return
+	}
+	if v != "" {
+		c := &excel.CT_ClientDataChoice{}
+		*field(c) = unioffice.String(v)
+		fc.cd.ClientDataChoice = append(fc.cd.ClientDataChoice, c)
+	}
+}
+
+func (fc FormControl) intValue(field func(c *excel.CT_ClientDataChoice) **int64) (int, bool) {
+	c := fc.choice(func(c *excel.CT_ClientDataChoice) bool { return *field(c) != nil })
+	if c == nil {
+		return 0, false
+	}
+	return int(**field(c)), true
+}
+
+func (fc FormControl) setInt(field func(c *excel.CT_ClientDataChoice) **int64, v int) {
+	c := fc.choice(func(c *excel.CT_ClientDataChoice) bool { return *field(c) != nil })
+	if c == nil {
+		c = &excel.CT_ClientDataChoice{}
+		fc.cd.ClientDataChoice = append(fc.cd.ClientDataChoice, c)
+	}
+	*field(c) = unioffice.Int64(int64(v))
+}
+
+func (fc FormControl) flag(field func(c *excel.CT_ClientDataChoice) *sharedTypes.ST_TrueFalseBlank) bool {
+	c := fc.choice(func(c *excel.CT_ClientDataChoice) bool { return *field(c) != 0 })
+	if c == nil {
+		return false
+	}
+	v := *field(c)
+	return v != sharedTypes.ST_TrueFalseBlankFalse && v != sharedTypes.ST_TrueFalseBlankF
+}
+
+func (fc FormControl) setFlag(field func(c *excel.CT_ClientDataChoice) *sharedTypes.ST_TrueFalseBlank, v bool) {
+	for i, c := range fc.cd.ClientDataChoice {
+		if *field(c) == 0 {
+			continue
+		}
+		if v {
+			*field(c) = sharedTypes.ST_TrueFalseBlankTrue
+		} else {
+			fc.cd.ClientDataChoice = append(fc.cd.ClientDataChoice[:i], fc.cd.ClientDataChoice[i+1:]...)
+		}
+		return
+	}
+	if v {
+		c := &excel.CT_ClientDataChoice{}
+		*field(c) = sharedTypes.ST_TrueFalseBlankTrue
+		fc.cd.ClientDataChoice = append(fc.cd.ClientDataChoice, c)
+	}
+}
+
+// Text returns the text of the control, e.g. the label of a check box.
+func (fc FormControl) Text() string { return vmldrawing.ShapeText(fc.shape) }
+
+// SetText sets the text of the control.
+func (fc FormControl) SetText(text string) { vmldrawing.SetShapeText(fc.shape, text) }
+
+// LinkedCell returns the cell that holds the value of the control.
+func (fc FormControl) LinkedCell() string {
+	return fc.stringValue(func(c *excel.CT_ClientDataChoice) **string { return &c.FmlaLink })
+}
+
+// SetLinkedCell sets the cell that holds the value of the control, e.g.
+// "$A$1" or "Sheet2!$A$1". The value is the checked state of check boxes and
+// option buttons, the selected index of lists and the value of spinners and
+// scroll bars.
+func (fc FormControl) SetLinkedCell(ref string) {
+	fc.setString(func(c *excel.CT_ClientDataChoice) **string { return &c.FmlaLink }, ref)
+}
+
+// InputRange returns the range that the items of a list or combo box are
+// read from.
+func (fc FormControl) InputRange() string {
+	return fc.stringValue(func(c *excel.CT_ClientDataChoice) **string { return &c.FmlaRange })
+}
+
+// SetInputRange sets the range that the items of a list or combo box are
+// read from, e.g. "$D$1:$D$5".
+func (fc FormControl) SetInputRange(ref string) {
+	fc.setString(func(c *excel.CT_ClientDataChoice) **string { return &c.FmlaRange }, ref)
+}
+
+// Macro returns the name of the macro run when the control is used.
+func (fc FormControl) Macro() string {
+	return fc.stringValue(func(c *excel.CT_ClientDataChoice) **string { return &c.FmlaMacro })
+}
+
+// SetMacro sets the name of the macro run when the control is used, e.g.
+// "[0]!Button1_Click".
+func (fc FormControl) SetMacro(name string) {
+	fc.setString(func(c *excel.CT_ClientDataChoice) **string { return &c.FmlaMacro }, name)
+}
+
+// Checked returns true if a check box or option button is checked.
+func (fc FormControl) Checked() bool {
+	v, _ := fc.intValue(func(c *excel.CT_ClientDataChoice) **int64 { return &c.Checked })
+	return v == 1
+}
+
+// SetChecked checks or clears a check box or option button.
+func (fc FormControl) SetChecked(b bool) {
+	v := 0
+	if b {
+		v = 1
+	}
+	fc.setInt(func(c *excel.CT_ClientDataChoice) **int64 { return &c.Checked }, v)
+}
+
+// Value returns the one based index of the selected item of a list or combo
+// box, or the value of a spinner or scroll bar.
+func (fc FormControl) Value() int {
+	switch fc.Type() {
+	case FormControlListBox, FormControlComboBox:
+		v, _ := fc.intValue(func(c *excel.CT_ClientDataChoice) **int64 { return &c.Sel })
+		return v
+	}
+	v, _ := fc.intValue(func(c *excel.CT_ClientDataChoice) **int64 { return &c.Val })
+	return v
+}
+
+// SetValue sets the selected item of a list or combo box, or the value of a
+// spinner or scroll bar.
+func (fc FormControl) SetValue(v int) {
+	switch fc.Type() {
+	case FormControlListBox, FormControlComboBox:
+		fc.setInt(func(c *excel.CT_ClientDataChoice) **int64 { return &c.Sel }, v)
+	default:
+		fc.setInt(func(c *excel.CT_ClientDataChoice) **int64 { return &c.Val }, v)
+	}
+}
+
+// Limits returns the minimum, maximum, increment and page change of a spinner
+// or scroll bar.
+func (fc FormControl) Limits() (min, max, inc, page int) {
+	min, _ = fc.intValue(func(c *excel.CT_ClientDataChoice) **int64 { return &c.Min })
+	max, _ = fc.intValue(func(c *excel.CT_ClientDataChoice) **int64 { return &c.Max })
+	inc, _ = fc.intValue(func(c *excel.CT_ClientDataChoice) **int64 { return &c.Inc })
+	page, _ = fc.intValue(func(c *excel.CT_ClientDataChoice) **int64 { return &c.Page })
+	return min, max, inc, page
+}
+
+// SetLimits sets the minimum, maximum, increment and page change of a spinner
+// or scroll bar.
+func (fc FormControl) SetLimits(min, max, inc, page int) {
+	fc.setInt(func(c *excel.CT_ClientDataChoice) **int64 { return &c.Min }, min)
+	fc.setInt(func(c *excel.CT_ClientDataChoice) **int64 { return &c.Max }, max)
+	fc.setInt(func(c *excel.CT_ClientDataChoice) **int64 { return &c.Inc }, inc)
+	fc.setInt(func(c *excel.CT_ClientDataChoice) **int64 { return &c.Page }, page)
+}
+
+// DropLines returns the number of items a combo box shows when dropped down.
+func (fc FormControl) DropLines() int {
+	v, _ := fc.intValue(func(c *excel.CT_ClientDataChoice) **int64 { return &c.DropLines })
+	return v
+}
+
+// SetDropLines sets the number of items a combo box shows when dropped down.
+func (fc FormControl) SetDropLines(n int) {
+	fc.setInt(func(c *excel.CT_ClientDataChoice) **int64 { return &c.DropLines }, n)
+}
+
+// vmlDrawing returns the legacy drawing of the sheet, adding one if it
+// doesn't have one and add is set.
+func (s *Sheet) vmlDrawing(add bool) *vmldrawing.Container {
+	wb := s._fgeg
+	rels, ok := s.rels()
+	if !ok {
+		return nil
+	}
+	if s._bbbe.LegacyDrawing != nil {
+		target := rels.GetTargetByRelId(s._bbbe.LegacyDrawing.IdAttr)
+		name := strings.TrimSuffix(path.Base(target), path.Ext(target))
+		if n, err := strconv.Atoi(strings.TrimPrefix(name, "vmlDrawing")); err == nil && n >= 1 && n <= len(wb._adbg) {
+			return wb._adbg[n-1]
+		}
+		return nil
+	}
+	if !add {
+		return nil
+	}
+	c := vmldrawing.NewContainer()
+	c.Layout = vml.NewOfcShapelayout()
+	c.Layout.ExtAttr = vml.ST_ExtEdit
+	c.Layout.Idmap = vml.NewOfcCT_IdMap()
+	c.Layout.Idmap.DataAttr = unioffice.String(strconv.Itoa(len(wb._adbg) + 1))
+	c.Layout.Idmap.ExtAttr = vml.ST_ExtEdit
+	wb._adbg = append(wb._adbg, c)
+	rel := rels.AddAutoRelationship(unioffice.DocTypeSpreadsheet, unioffice.WorksheetType, len(wb._adbg), unioffice.VMLDrawingType)
+	s._bbbe.LegacyDrawing = sml.NewCT_LegacyDrawing()
+	s._bbbe.LegacyDrawing.IdAttr = rel.ID()
+	wb.ContentTypes.EnsureDefault("vml", unioffice.VMLDrawingContentType)
+	return c
+}
+
+// FormControls returns the form controls of the sheet.
+func (s *Sheet) FormControls() []FormControl {
+	ret := []FormControl{}
+	c := s.vmlDrawing(false)
+	if c == nil {
+		return ret
+	}
+	for _, shape := range c.Shape {
+		cd := vmldrawing.ShapeClientData(shape)
+		if cd == nil {
+			continue
+		}
+		fc := FormControl{s, shape, cd}
+		if fc.Type() != FormControlUnknown {
+			ret = append(ret, fc)
+		}
+	}
+	return ret
+}
+
+// AddFormControl adds a form control of the given type covering the anchor
+// cells (e.g. "B2:C3") to the sheet.
+func (s *Sheet) AddFormControl(typ FormControlType, anchor string) (FormControl, error) {
+	objectType, ok := formControlObjectTypes[typ]
+	if !ok {
+		return FormControl{}, errors.New("unsupported form control type")
+	}
+	from, to, err := reference.ParseRangeReference(anchor)
+	if err != nil {
+		from, err = reference.ParseCellReference(anchor)
+		if err != nil {
+			return FormControl{}, fmt.Errorf("invalid anchor %s: %s", anchor, err)
+		}
+		to = from
+	}
+	if to.ColumnIdx < from.ColumnIdx {
+		from.ColumnIdx, to.ColumnIdx = to.ColumnIdx, from.ColumnIdx
+	}
+	if to.RowIdx < from.RowIdx {
+		from.RowIdx, to.RowIdx = to.RowIdx, from.RowIdx
+	}
+	rels, ok := s.rels()
+	if !ok {
+		return FormControl{}, errors.New("sheet not found in workbook")
+	}
+	c := s.vmlDrawing(true)
+	if c == nil {
+		return FormControl{}, errors.New("unable to find the legacy drawing of the sheet")
+	}
+	hasType := false
+	for _, st := range append([]*vml.Shapetype{c.ShapeType}, c.ShapeTypes...) {
+		if st != nil && st.IdAttr != nil && *st.IdAttr == vmldrawing.FormControlShapeTypeID {
+			hasType = true
+		}
+	}
+	if !hasType {
+		if c.ShapeType == nil {
+			c.ShapeType = vmldrawing.NewFormControlShapeType()
+		} else {
+			c.ShapeTypes = append(c.ShapeTypes, vmldrawing.NewFormControlShapeType())
+		}
+	}
+
+	// shape ids are allocated in blocks of 1024 per drawing
+	block := int64(1)
+	if c.Layout != nil && c.Layout.Idmap != nil && c.Layout.Idmap.DataAttr != nil {
+		if n, err := strconv.ParseInt(strings.Split(*c.Layout.Idmap.DataAttr, ",")[0], 10, 64); err == nil {
+			block = n
+		}
+	}
+	id := block*1024 + 1
+	count := 1
+	for _, shape := range c.Shape {
+		if shape.IdAttr != nil {
+			if n, err := strconv.ParseInt(strings.TrimPrefix(*shape.IdAttr, "_x0000_s"), 10, 64); err == nil && n >= id {
+				id = n + 1
+			}
+		}
+		if cd := vmldrawing.ShapeClientData(shape); cd != nil && cd.ObjectTypeAttr == objectType {
+			count++
+		}
+	}
+	vmlAnchor := fmt.Sprintf("%d, 0, %d, 0, %d, 0, %d, 0", from.ColumnIdx, from.RowIdx-1, to.ColumnIdx+1, to.RowIdx)
+	shape := vmldrawing.NewFormControlShape(id, objectType, vmlAnchor)
+	c.Shape = append(c.Shape, shape)
+	fc := FormControl{s, shape, vmldrawing.ShapeClientData(shape)}
+	name := fmt.Sprintf("%s %d", typ, count)
+
+	switch typ {
+	case FormControlCheckBox, FormControlOptionButton:
+		fc.setString(func(c *excel.CT_ClientDataChoice) **string { return &c.TextVAlign }, "Center")
+		fc.setFlag(func(c *excel.CT_ClientDataChoice) *sharedTypes.ST_TrueFalseBlank { return &c.NoThreeD }, true)
+		fc.SetText(name)
+	case FormControlButton, FormControlGroupBox:
+		fc.SetText(name)
+	case FormControlComboBox:
+		fc.setString(func(c *excel.CT_ClientDataChoice) **string { return &c.DropStyle }, "Combo")
+		fc.SetDropLines(8)
+	case FormControlListBox:
+		fc.setString(func(c *excel.CT_ClientDataChoice) **string { return &c.SelType }, "Single")
+	case FormControlSpinner, FormControlScrollBar:
+		fc.SetLimits(0, 100, 1, 10)
+	}
+
+	// the control properties part, written from the shape when saved
+	wb := s._fgeg
+	st := wb.formControlState()
+	n := len(st.parts) + 1
+	for wb.hasPart(fmt.Sprintf("xl/ctrlProps/ctrlProp%d.xml", n)) {
+		n++
+	}
+	part := &formControlPart{path: fmt.Sprintf("xl/ctrlProps/ctrlProp%d.xml", n), shape: shape}
+	rel := rels.AddRelationship(fmt.Sprintf("../ctrlProps/ctrlProp%d.xml", n), ctrlPropType)
+	wb.ContentTypes.AddOverride("/"+part.path, ctrlPropContentType)
+
+	ctrl := sml.NewCT_Control()
+	ctrl.ShapeIdAttr = uint32(id)
+	ctrl.IdAttr = rel.ID()
+	ctrl.NameAttr = unioffice.String(name)
+	ctrl.ControlPr = sml.NewCT_ControlPr()
+	ctrl.ControlPr.DefaultSizeAttr = unioffice.Bool(false)
+	ctrl.ControlPr.AutoFillAttr = unioffice.Bool(false)
+	ctrl.ControlPr.AutoLineAttr = unioffice.Bool(false)
+	ctrl.ControlPr.AutoPictAttr = unioffice.Bool(false)
+	ctrl.ControlPr.Anchor = sml.NewCT_ObjectAnchor()
+	ctrl.ControlPr.Anchor.MoveWithCellsAttr = unioffice.Bool(true)
+	ctrl.ControlPr.Anchor.From = spreadsheetDrawing.NewFrom()
+	ctrl.ControlPr.Anchor.From.Col = int32(from.ColumnIdx)
+	ctrl.ControlPr.Anchor.From.Row = int32(from.RowIdx - 1)
+	ctrl.ControlPr.Anchor.To = spreadsheetDrawing.NewTo()
+	ctrl.ControlPr.Anchor.To.Col = int32(to.ColumnIdx + 1)
+	ctrl.ControlPr.Anchor.To.Row = int32(to.RowIdx)
+	for _, m := range []*spreadsheetDrawing.CT_Marker{&ctrl.ControlPr.Anchor.From.CT_Marker, &ctrl.ControlPr.Anchor.To.CT_Marker} {
+		m.ColOff.ST_CoordinateUnqualified = unioffice.Int64(0)
+		m.RowOff.ST_CoordinateUnqualified = unioffice.Int64(0)
+	}
+	if s._bbbe.Controls == nil {
+		s._bbbe.Controls = sml.NewCT_Controls()
+	}
+	s._bbbe.Controls.Control = append(s._bbbe.Controls.Control, ctrl)
+	part.ctrl = ctrl
+	st.parts = append(st.parts, part)
+	return fc, nil
+}
+
+// hasPart returns true if the workbook has an extra file at the path.
+func (wb *Workbook) hasPart(zipPath string) bool {
+	for _, ef := range wb.ExtraFiles {
+		if ef.ZipPath == zipPath {
+			return true
+		}
+	}
+	return false
+}
+
+func (wb *Workbook) formControlState() *formControlState {
+	if wb._formControls == nil {
+		wb._formControls = &formControlState{}
+	}
+	return wb._formControls
+}
+
+// loadFormControls decodes the control properties parts of the form controls
+// read with the workbook so changes to the controls are written back on save.
+func (wb *Workbook) loadFormControls() error {
+	known := map[string]bool{}
+	for _, p := range wb.formControlState().parts {
+		known[p.path] = true
+	}
+	for _, s := range wb.AllSheets() {
+		rels, ok := s.rels()
+		c := s.vmlDrawing(false)
+		if !ok || c == nil || s._bbbe.Controls == nil {
+			continue
+		}
+		for _, ctrl := range s._bbbe.Controls.Control {
+			target := ""
+			for _, r := range rels.Relationships() {
+				if r.ID() == ctrl.IdAttr && r.Type() == ctrlPropType {
+					target = partPath("xl/worksheets", r.Target())
+				}
+			}
+			if target == "" || known[target] {
+				continue
+			}
+			var shape *vml.Shape
+			for _, sh := range c.Shape {
+				if sh.IdAttr != nil && *sh.IdAttr == fmt.Sprintf("_x0000_s%d", ctrl.ShapeIdAttr) {
+					shape = sh
+				}
+			}
+			pr := &xmlFormControlPr{}
+			if ok, err := wb.readExtraFile(target, pr); err != nil {
+				return err
+			} else if !ok || shape == nil {
+				continue
+			}
+			known[target] = true
+			wb._formControls.parts = append(wb._formControls.parts, &formControlPart{path: target, shape: shape, ctrl: ctrl, pr: pr})
+		}
+	}
+	return nil
+}
+
+// flushFormControls writes the control properties parts of the form controls
+// from their shapes so they're saved with the workbook.
+func (wb *Workbook) flushFormControls() error {
+	if wb._formControls == nil {
+		return nil
+	}
+	for _, p := range wb._formControls.parts {
+		fc := FormControl{shape: p.shape, cd: vmldrawing.ShapeClientData(p.shape)}
+		if fc.cd == nil {
+			continue
+		}
+		x := fc.controlPr(p.pr)
+		if err := wb.writeExtraFile(p.path, x); err != nil {
+			return err
+		}
+		pr := p.ctrl.ControlPr
+		if pr == nil {
+			continue
+		}
+		pr.LinkedCellAttr, pr.ListFillRangeAttr, pr.MacroAttr = nil, nil, nil
+		if x.FmlaLink != "" {
+			pr.LinkedCellAttr = unioffice.String(x.FmlaLink)
+		}
+		if x.FmlaRange != "" {
+			pr.ListFillRangeAttr = unioffice.String(x.FmlaRange)
+		}
+		if m := fc.Macro(); m != "" {
+			pr.MacroAttr = unioffice.String(m)
+		}
+	}
+	return nil
+}
+
+// controlPr returns the control properties of the control, those the shape
+// doesn't hold being taken from read if it isn't nil.
+func (fc FormControl) controlPr(read *xmlFormControlPr) *xmlFormControlPr {
+	typ := fc.Type()
+	x := &xmlFormControlPr{}
+	if read != nil {
+		*x = *read
+	}
+	x.ObjectType = typ.ctrlPropObjectType()
+	x.FmlaLink = fc.LinkedCell()
+	x.FmlaRange = fc.InputRange()
+	x.NoThreeD = fc.flag(func(c *excel.CT_ClientDataChoice) *sharedTypes.ST_TrueFalseBlank { return &c.NoThreeD })
+	intAttr := func(field func(c *excel.CT_ClientDataChoice) **int64) *int64 {
+		if v, ok := fc.intValue(field); ok {
+			return unioffice.Int64(int64(v))
+		}
+		return nil
+	}
+	switch typ {
+	case FormControlCheckBox, FormControlOptionButton:
+		v, _ := fc.intValue(func(c *excel.CT_ClientDataChoice) **int64 { return &c.Checked })
+		x.Checked = ""
+		switch v {
+		case 1:
+			x.Checked = "Checked"
+		case 2:
+			x.Checked = "Mixed"
+		}
+		x.LockText = read == nil || read.LockText
+		x.FirstButton = fc.flag(func(c *excel.CT_ClientDataChoice) *sharedTypes.ST_TrueFalseBlank { return &c.FirstButton })
+	case FormControlComboBox, FormControlListBox:
+		if x.Dx == nil {
+			x.Dx = unioffice.Int64(16)
+		}
+		x.Sel = intAttr(func(c *excel.CT_ClientDataChoice) **int64 { return &c.Sel })
+		if typ == FormControlComboBox {
+			x.DropLines = intAttr(func(c *excel.CT_ClientDataChoice) **int64 { return &c.DropLines })
+			if x.DropStyle == "" {
+				x.DropStyle = "combo"
+			}
+		} else if x.SelType == "" {
+			x.SelType = "single"
+		}
+	case FormControlSpinner, FormControlScrollBar:
+		if x.Dx == nil {
+			x.Dx = unioffice.Int64(16)
+		}
+		x.Val = intAttr(func(c *excel.CT_ClientDataChoice) **int64 { return &c.Val })
+		x.Min = intAttr(func(c *excel.CT_ClientDataChoice) **int64 { return &c.Min })
+		x.Max = intAttr(func(c *excel.CT_ClientDataChoice) **int64 { return &c.Max })
+		x.Inc = intAttr(func(c *excel.CT_ClientDataChoice) **int64 { return &c.Inc })
+		if typ == FormControlScrollBar {
+			x.Page = intAttr(func(c *excel.CT_ClientDataChoice) **int64 { return &c.Page })
+			x.Horiz = fc.flag(func(c *excel.CT_ClientDataChoice) *sharedTypes.ST_TrueFalseBlank { return &c.Horiz })
+		}
+	case FormControlButton:
+		x.LockText = read == nil || read.LockText
+	}
+	return x
+}
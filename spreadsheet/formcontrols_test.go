@@ -0,0 +1,139 @@
+package spreadsheet
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+// saveAndRead saves the workbook and reads it back.
+func saveAndRead(t *testing.T, wb *Workbook) *Workbook {
+	t.Helper()
+	licensed := _gfcca
+	_gfcca = true
+	defer func() { _gfcca = licensed }()
+	buf := bytes.Buffer{}
+	if err := wb.Save(&buf); err != nil {
+		t.Fatalf("error saving: %s", err)
+	}
+	ret, err := Read(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("error reading: %s", err)
+	}
+	return ret
+}
+
+func TestFormControls(t *testing.T) {
+	wb := New()
+	sheet := wb.AddSheet()
+	sheet.Comments().AddCommentWithStyle("A1", "Alice", "note")
+
+	cb, err := sheet.AddFormControl(FormControlCheckBox, "B2:C2")
+	if err != nil {
+		t.Fatalf("error adding check box: %s", err)
+	}
+	cb.SetLinkedCell("$A$2")
+	cb.SetChecked(true)
+	cb.SetMacro("[0]!Toggle")
+	dd, err := sheet.AddFormControl(FormControlComboBox, "B4:C4")
+	if err != nil {
+		t.Fatalf("error adding combo box: %s", err)
+	}
+	dd.SetInputRange("$E$1:$E$3")
+	dd.SetValue(2)
+	sp, _ := sheet.AddFormControl(FormControlSpinner, "D4")
+	sp.SetLimits(1, 10, 1, 5)
+	if _, err := sheet.AddFormControl(FormControlUnknown, "A1"); err == nil {
+		t.Errorf("expected an error for an unknown control type")
+	}
+
+	fcs := sheet.FormControls()
+	if len(fcs) != 3 {
+		t.Fatalf("expected 3 controls, got %d", len(fcs))
+	}
+	if fcs[0].Type() != FormControlCheckBox || fcs[0].Name() != "Check Box 1" || fcs[0].Text() != "Check Box 1" {
+		t.Errorf("unexpected check box %s %s %q", fcs[0].Type(), fcs[0].Name(), fcs[0].Text())
+	}
+	if !fcs[0].Checked() || fcs[0].LinkedCell() != "$A$2" || fcs[0].Macro() != "[0]!Toggle" || fcs[0].Anchor() != "B2:C2" {
+		t.Errorf("unexpected check box properties %v %s %s %s", fcs[0].Checked(), fcs[0].LinkedCell(), fcs[0].Macro(), fcs[0].Anchor())
+	}
+	if fcs[1].InputRange() != "$E$1:$E$3" || fcs[1].Value() != 2 || fcs[1].DropLines() != 8 {
+		t.Errorf("unexpected combo box properties %s %d %d", fcs[1].InputRange(), fcs[1].Value(), fcs[1].DropLines())
+	}
+	if min, max, inc, page := fcs[2].Limits(); min != 1 || max != 10 || inc != 1 || page != 5 || fcs[2].Anchor() != "D4:D4" {
+		t.Errorf("unexpected spinner limits %d %d %d %d at %s", min, max, inc, page, fcs[2].Anchor())
+	}
+
+	// the comment shape type is kept and the form control one added
+	vml := sheet.vmlDrawing(false)
+	if vml == nil || vml.ShapeType == nil || len(vml.ShapeTypes) != 1 || len(vml.Shape) != 4 {
+		t.Fatalf("unexpected legacy drawing")
+	}
+	buf := bytes.Buffer{}
+	if err := xml.NewEncoder(&buf).Encode(vml); err != nil {
+		t.Fatalf("error encoding drawing: %s", err)
+	}
+	for _, exp := range []string{`ObjectType="Checkbox"`, "<x:FmlaLink>$A$2</x:FmlaLink>", `id="_x0000_t201"`, "Check Box 1</font>"} {
+		if !strings.Contains(buf.String(), exp) {
+			t.Errorf("expected %s in drawing", exp)
+		}
+	}
+
+	if err := wb.flushFormControls(); err != nil {
+		t.Fatalf("error writing control properties: %s", err)
+	}
+	pr := &xmlFormControlPr{}
+	if ok, err := wb.readExtraFile("xl/ctrlProps/ctrlProp1.xml", pr); !ok || err != nil {
+		t.Fatalf("expected control properties part: %v %v", ok, err)
+	}
+	if pr.ObjectType != "CheckBox" || pr.Checked != "Checked" || pr.FmlaLink != "$A$2" {
+		t.Errorf("unexpected control properties %+v", pr)
+	}
+	ctrls := sheet.X().Controls.Control
+	if len(ctrls) != 3 || ctrls[0].ControlPr.MacroAttr == nil || *ctrls[0].ControlPr.MacroAttr != "[0]!Toggle" {
+		t.Errorf("unexpected controls")
+	}
+}
+
+func TestFormControlsRead(t *testing.T) {
+	wb := New()
+	sheet := wb.AddSheet()
+	cb, _ := sheet.AddFormControl(FormControlCheckBox, "B2")
+	cb.SetLinkedCell("$A$2")
+	dd, _ := sheet.AddFormControl(FormControlComboBox, "B4")
+	dd.SetInputRange("$E$1:$E$3")
+	dd.SetValue(1)
+
+	wb = saveAndRead(t, wb)
+	sheet = wb.Sheets()[0]
+	if sheet.X().Controls == nil {
+		t.Fatal("expected the controls to be read")
+	}
+	st := wb.formControlState()
+	if len(st.parts) != 2 {
+		t.Fatalf("expected the 2 control properties parts to be read, got %d", len(st.parts))
+	}
+	// properties the shapes don't hold are written back as read
+	st.parts[1].pr.DropStyle = "simple"
+	fcs := sheet.FormControls()
+	fcs[0].SetChecked(true)
+	fcs[1].SetValue(3)
+
+	wb = saveAndRead(t, wb)
+	pr := &xmlFormControlPr{}
+	if ok, err := wb.readExtraFile("xl/ctrlProps/ctrlProp1.xml", pr); !ok || err != nil {
+		t.Fatalf("expected control properties part: %v %v", ok, err)
+	}
+	if pr.Checked != "Checked" || pr.FmlaLink != "$A$2" {
+		t.Errorf("expected the check box change to be saved, got %+v", pr)
+	}
+	pr = &xmlFormControlPr{}
+	wb.readExtraFile("xl/ctrlProps/ctrlProp2.xml", pr)
+	if pr.Sel == nil || *pr.Sel != 3 || pr.DropStyle != "simple" || pr.FmlaRange != "$E$1:$E$3" {
+		t.Errorf("expected the combo box change to be saved, got %+v", pr)
+	}
+	if len(wb.formControlState().parts) != 2 || len(wb.Sheets()[0].X().Controls.Control) != 2 {
+		t.Errorf("expected the controls not to be duplicated")
+	}
+}
@@ -0,0 +1,96 @@
+package formula
+
+import "strings"
+
+// The lexer doesn't accept the brackets of references to other workbooks
+// (e.g. [1]Sheet1!A1 or '[Book.xlsx]Sheet 1'!A1), so before parsing they're
+// rewritten to a quoted sheet name using these runes instead and restored when
+// the sheet prefix is constructed.
+const (
+	externalOpen  = "⟦"
+	externalClose = "⟧"
+)
+
+var externalDecoder = strings.NewReplacer(externalOpen, "[", externalClose, "]")
+
+// encodeExternalReferences rewrites the workbook part of external references
+// in formula so that the lexer can parse them.
+func encodeExternalReferences(formula string) string {
+	if strings.IndexByte(formula, '[') == -1 {
+		return formula
+	}
+	out := strings.Builder{}
+	i := 0
+	for i < len(formula) {
+		c := formula[i]
+		switch {
+		case c == '"':
+			j := skipQuoted(formula, i, '"')
+			out.WriteString(formula[i:j])
+			i = j
+			continue
+		case c == '\'':
+			j := skipQuoted(formula, i, '\'')
+			name := formula[i:j]
+			if k := strings.IndexByte(name, ']'); strings.HasPrefix(name, "'[") && k > 2 {
+				name = "'" + externalOpen + name[2:k] + externalClose + name[k+1:]
+			}
+			out.WriteString(name)
+			i = j
+			continue
+		case c == '[':
+			j := skipBrackets(formula, i)
+			// structured references follow a table name
+			if i > 0 && (isNameChar(formula[i-1]) || formula[i-1] == ']') {
+				out.WriteString(formula[i:j])
+				i = j
+				continue
+			}
+			k := j
+			for k < len(formula) && isNameChar(formula[k]) {
+				k++
+			}
+			if j-i > 2 && k < len(formula) && formula[k] == '!' {
+				out.WriteString("'" + externalOpen + formula[i+1:j-1] + externalClose + formula[j:k] + "'!")
+				i = k + 1
+				continue
+			}
+			out.WriteString(formula[i:j])
+			i = j
+			continue
+		}
+		out.WriteByte(c)
+		i++
+	}
+	return out.String()
+}
+
+// decodeExternalSheet restores the brackets of an external sheet name
+// rewritten by encodeExternalReferences.
+func decodeExternalSheet(name string) string {
+	if !strings.Contains(name, externalOpen) {
+		return name
+	}
+	return externalDecoder.Replace(name)
+}
+
+// HasExternalReferences returns true if formula refers to cells or names of
+// another workbook.
+func HasExternalReferences(formula string) bool {
+	return strings.Contains(encodeExternalReferences(formula), externalOpen)
+}
+
+// SplitExternalSheet splits a sheet name of a reference to another workbook
+// (e.g. "[1]Sheet1") into the workbook, which is either the index of the
+// external link or a file name, and the sheet name. The sheet name is empty
+// for workbook level defined names (e.g. [1]!Total).
+func SplitExternalSheet(name string) (book, sheet string, ok bool) {
+	if !strings.HasPrefix(name, "[") {
+		return "", "", false
+	}
+	i := strings.IndexByte(name, ']')
+	if i < 2 {
+		return "", "", false
+	}
+	return name[1:i], name[i+1:], true
+}
@@ -0,0 +1,40 @@
+package formula
+
+import "testing"
+
+func TestExternalReferences(t *testing.T) {
+	td := []struct {
+		inp      string
+		external bool
+		exp      string
+	}{
+		{"[1]Sheet1!A1*2", true, "[1]Sheet1!A1*2"},
+		{"'[Budget.xlsx]Sheet 1'!A1:B2", true, "[Budget.xlsx]Sheet 1!A1:B2"},
+		{"SUM(Sheet1!A1,\"[1]Sheet1!A1\")", false, "SUM(Sheet1!A1,\"[1]Sheet1!A1\")"},
+		{"Table1[Col]", false, ""},
+	}
+	for _, tc := range td {
+		if got := HasExternalReferences(tc.inp); got != tc.external {
+			t.Errorf("HasExternalReferences(%q) = %v, expected %v", tc.inp, got, tc.external)
+		}
+		if tc.exp == "" {
+			continue
+		}
+		expr := ParseString(tc.inp)
+		if expr == nil {
+			t.Errorf("error parsing %q", tc.inp)
+			continue
+		}
+		if got := expr.String(); got != tc.exp {
+			t.Errorf("ParseString(%q).String() = %q, expected %q", tc.inp, got, tc.exp)
+		}
+	}
+
+	book, sheet, ok := SplitExternalSheet("[Budget.xlsx]Sheet 1")
+	if !ok || book != "Budget.xlsx" || sheet != "Sheet 1" {
+		t.Errorf("unexpected split %q %q %v", book, sheet, ok)
+	}
+	if _, _, ok := SplitExternalSheet("Sheet1"); ok {
+		t.Errorf("expected Sheet1 not to be external")
+	}
+}
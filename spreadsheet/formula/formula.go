@@ -187,7 +187,7 @@ if _bgacf .Type !=ReferenceTypeCell {return MakeErrorResult ("\u0043\u004f\u004c
 func Clean (args []Result )Result {if len (args )!=1{return MakeErrorResult ("\u0043\u004c\u0045\u0041\u004e\u0020\u0072\u0065\u0071\u0075\u0069\u0072\u0065s\u0020\u0061\u0020\u0073\u0069\u006eg\u006c\u0065\u0020\u0073\u0074\u0072\u0069\u006e\u0067\u0020\u0061\u0072\u0067u\u006d\u0065\u006e\u0074");
 };_gfgga :=args [0].AsString ();if _gfgga .Type !=ResultTypeString {return MakeErrorResult ("\u0043\u0048\u0041\u0052\u0020\u0072\u0065\u0071\u0075\u0069\u0072\u0065\u0073\u0020\u0061\u0020\u0073\u0069\u006e\u0067\u006c\u0065\u0020\u0073t\u0072\u0069\u006e\u0067\u0020a\u0072\u0067u\u006d\u0065\u006e\u0074");
 };_bfeb :=_b .Buffer {};for _ ,_cegg :=range _gfgga .ValueString {if _gb .IsPrint (_cegg ){_bfeb .WriteRune (_cegg );};};return MakeStringResult (_bfeb .String ());};var _fbgg =false ;type Expression interface{Eval (_eca Context ,_acf Evaluator )Result ;
-Reference (_daec Context ,_cdc Evaluator )Reference ;String ()string ;Update (_bddc *_cc .UpdateQuery )Expression ;};var _ffeg =[]ri {{1000,"\u004d"},{950,"\u004c\u004d"},{900,"\u0043\u004d"},{500,"\u0044"},{450,"\u004c\u0044"},{400,"\u0043\u0044"},{100,"\u0043"},{95,"\u0056\u0043"},{90,"\u0058\u0043"},{50,"\u004c"},{45,"\u0056\u004c"},{40,"\u0058\u004c"},{10,"\u0058"},{9,"\u0049\u0058"},{5,"\u0056"},{4,"\u0049\u0056"},{1,"\u0049"}};
+Reference (_daec Context ,_cdc Evaluator )Reference ;String ()string ;StringR1C1 (origin string )string ;Update (_bddc *_cc .UpdateQuery )Expression ;};var _ffeg =[]ri {{1000,"\u004d"},{950,"\u004c\u004d"},{900,"\u0043\u004d"},{500,"\u0044"},{450,"\u004c\u0044"},{400,"\u0043\u0044"},{100,"\u0043"},{95,"\u0056\u0043"},{90,"\u0058\u0043"},{50,"\u004c"},{45,"\u0056\u004c"},{40,"\u0058\u004c"},{10,"\u0058"},{9,"\u0049\u0058"},{5,"\u0056"},{4,"\u0049\u0056"},{1,"\u0049"}};
 var _bgad *_ce .Rand ;
 
 // Minute is an implementation of the Excel MINUTE() function.
@@ -1097,7 +1097,7 @@ _egef :=_ecec .AddDate (0,int (_bfbd +1),0);_gbgb ,_ggg ,_ :=_egef .Date ();_ege
 type String struct{_defad string };
 
 // Parse parses a string to get an Expression.
-func ParseString (s string )Expression {if s ==""{return NewEmptyExpr ();};return Parse (_ecg .NewReader (s ));};
+func ParseString (s string )Expression {if s ==""{return NewEmptyExpr ();};return Parse (_ecg .NewReader (encodeExternalReferences (s )));};
 
 // Update returns the same object as updating sheet references does not affect String.
 func (_aecgg String )Update (q *_cc .UpdateQuery )Expression {return _aecgg };
@@ -1319,7 +1319,7 @@ func NewPrefixVerticalRange (pfx Expression ,v string )Expression {_aaed :=_ecg
 func (_ggb Error )Reference (ctx Context ,ev Evaluator )Reference {return ReferenceInvalid };func (_cfde *yyParserImpl )Lookahead ()int {return _cfde ._ebcfb };
 
 // NewSheetPrefixExpr constructs a new prefix expression.
-func NewSheetPrefixExpr (s string )Expression {return &SheetPrefixExpr {_aebe :s }};func _aab (_gbge ,_adfg _a .Time ,_egcg ,_cda int )_a .Time {_aafcg :=_adfg ;_abaf :=_gbge .Year ()-_adfg .Year ();_aafcg =_aafcg .AddDate (_abaf ,0,0);if _gbge .After (_aafcg ){_aafcg =_aafcg .AddDate (1,0,0);
+func NewSheetPrefixExpr (s string )Expression {return &SheetPrefixExpr {_aebe :decodeExternalSheet (s )}};func _aab (_gbge ,_adfg _a .Time ,_egcg ,_cda int )_a .Time {_aafcg :=_adfg ;_abaf :=_gbge .Year ()-_adfg .Year ();_aafcg =_aafcg .AddDate (_abaf ,0,0);if _gbge .After (_aafcg ){_aafcg =_aafcg .AddDate (1,0,0);
 };_faac :=-12/_egcg ;for _aafcg .After (_gbge ){_aafcg =_aafcg .AddDate (0,_faac ,0);};return _aafcg ;};
 
 // SumSquares is an implementation of the Excel SUMSQ() function.
@@ -0,0 +1,355 @@
+package formula
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/yaklabco/unioffice/v2/spreadsheet/reference"
+)
+
+// r1c1Part is the row or column part of an R1C1 reference.
+type r1c1Part struct {
+	present bool
+	abs     bool
+	n       int
+}
+
+// parseR1C1Part parses the number following R or C at position i, either an
+// absolute index (R5), a relative offset (R[-2]) or nothing (R).
+func parseR1C1Part(s string, i int) (r1c1Part, int, bool) {
+	p := r1c1Part{present: true}
+	if i < len(s) && s[i] == '[' {
+		j := i + 1
+		if j < len(s) && (s[j] == '-' || s[j] == '+') {
+			j++
+		}
+		start := j
+		for j < len(s) && isASCIIDigit(s[j]) {
+			j++
+		}
+		if j == start || j >= len(s) || s[j] != ']' {
+			return p, i, false
+		}
+		n, err := strconv.Atoi(s[i+1 : j])
+		if err != nil {
+			return p, i, false
+		}
+		p.n = n
+		return p, j + 1, true
+	}
+	j := i
+	for j < len(s) && isASCIIDigit(s[j]) {
+		j++
+	}
+	if j > i {
+		n, err := strconv.Atoi(s[i:j])
+		if err != nil || n == 0 {
+			return p, i, false
+		}
+		p.abs = true
+		p.n = n
+	}
+	return p, j, true
+}
+
+// matchR1C1 matches an R1C1 reference (R1C1, R[1]C, R2, C[-1], ...) starting
+// at position i, returning the row and column parts and the end position.
+func matchR1C1(s string, i int) (row, col r1c1Part, end int, ok bool) {
+	j := i
+	if j < len(s) && (s[j] == 'R' || s[j] == 'r') {
+		row, j, ok = parseR1C1Part(s, j+1)
+		if !ok {
+			return
+		}
+	}
+	if j < len(s) && (s[j] == 'C' || s[j] == 'c') {
+		col, j, ok = parseR1C1Part(s, j+1)
+		if !ok {
+			return
+		}
+	}
+	if j == i {
+		return row, col, i, false
+	}
+	// the reference must not be the start of a name, function or sheet
+	if j < len(s) && (isNameChar(s[j]) || s[j] == '(' || s[j] == '[' || s[j] == '!') {
+		return row, col, i, false
+	}
+	return row, col, j, true
+}
+
+// resolve returns the one based row or column number of the part relative to
+// the origin number.
+func (p r1c1Part) resolve(origin int) int {
+	if p.abs {
+		return p.n
+	}
+	return origin + p.n
+}
+
+func r1c1Origin(origin string) (row, col int, err error) {
+	cr, err := reference.ParseCellReference(origin)
+	if err != nil {
+		return 0, 0, err
+	}
+	return int(cr.RowIdx), int(cr.ColumnIdx), nil
+}
+
+// R1C1ToA1 converts a formula using R1C1 style references into one using A1
+// style references, with relative references resolved against the origin cell
+// the formula is placed in. References resolved outside of the sheet become
+// #REF!.
+func R1C1ToA1(formula, origin string) (string, error) {
+	oRow, oCol, err := r1c1Origin(origin)
+	if err != nil {
+		return "", err
+	}
+	out := strings.Builder{}
+	for i := 0; i < len(formula); {
+		c := formula[i]
+		switch {
+		case c == '"' || c == '\'':
+			j := skipQuoted(formula, i, c)
+			out.WriteString(formula[i:j])
+			i = j
+			continue
+		case isNameChar(c):
+			if row, col, j, ok := matchR1C1(formula, i); ok {
+				inRange := (i > 0 && formula[i-1] == ':') || (j < len(formula) && formula[j] == ':')
+				out.WriteString(r1c1ToA1Ref(row, col, oRow, oCol, inRange))
+				i = j
+				continue
+			}
+			j := i
+			for j < len(formula) && isNameChar(formula[j]) {
+				j++
+			}
+			out.WriteString(formula[i:j])
+			i = j
+			if i < len(formula) && formula[i] == '[' {
+				j = skipBrackets(formula, i)
+				out.WriteString(formula[i:j])
+				i = j
+			}
+			continue
+		case c == '[':
+			j := skipBrackets(formula, i)
+			out.WriteString(formula[i:j])
+			i = j
+			continue
+		}
+		out.WriteByte(c)
+		i++
+	}
+	return out.String(), nil
+}
+
+func r1c1ToA1Ref(row, col r1c1Part, oRow, oCol int, inRange bool) string {
+	r := a1Ref{kind: refKindCell, absRow: row.abs, absCol: col.abs}
+	if row.present {
+		n := row.resolve(oRow)
+		if n < 1 || n > maxRows {
+			return "#REF!"
+		}
+		r.row = uint32(n)
+	} else {
+		r.kind = refKindColumn
+	}
+	if col.present {
+		n := col.resolve(oCol + 1)
+		if n < 1 || n > maxColumns {
+			return "#REF!"
+		}
+		r.col = uint32(n - 1)
+	} else {
+		r.kind = refKindRow
+	}
+	if r.kind != refKindCell && !inRange {
+		// a lone row or column reference spans the whole row or column
+		return r.String() + ":" + r.String()
+	}
+	return r.String()
+}
+
+// A1ToR1C1 converts a formula using A1 style references into one using R1C1
+// style references relative to the origin cell the formula is placed in.
+func A1ToR1C1(formula, origin string) (string, error) {
+	oRow, oCol, err := r1c1Origin(origin)
+	if err != nil {
+		return "", err
+	}
+	return mapA1References(formula, func(r a1Ref) string {
+		b := strings.Builder{}
+		if r.kind != refKindColumn {
+			b.WriteString("R" + r1c1PartString(int(r.row), oRow, r.absRow))
+		}
+		if r.kind != refKindRow {
+			// R1C1 column numbers are one based
+			b.WriteString("C" + r1c1PartString(int(r.col)+1, oCol+1, r.absCol))
+		}
+		return b.String()
+	}), nil
+}
+
+// r1c1PartString returns the R1C1 form of a row or column number, an
+// absolute number or an offset from the origin.
+func r1c1PartString(n, origin int, abs bool) string {
+	switch {
+	case abs:
+		return strconv.Itoa(n)
+	case n != origin:
+		return "[" + strconv.Itoa(n-origin) + "]"
+	}
+	return ""
+}
+
+// ParseR1C1 parses a formula using R1C1 style references, resolving relative
+// references against the origin cell. It returns nil if the formula or origin
+// can't be parsed.
+func ParseR1C1(s, origin string) Expression {
+	a1, err := R1C1ToA1(s, origin)
+	if err != nil {
+		return nil
+	}
+	return ParseString(a1)
+}
+
+// a1TextToR1C1 converts A1 reference text to R1C1, leaving it unchanged if
+// the origin is invalid.
+func a1TextToR1C1(s, origin string) string {
+	r, err := A1ToR1C1(s, origin)
+	if err != nil {
+		return s
+	}
+	return r
+}
+
+// StringR1C1 returns a string representation of CellRef in R1C1 style.
+func (c CellRef) StringR1C1(origin string) string { return a1TextToR1C1(c.String(), origin) }
+
+// StringR1C1 returns a string representation of Range in R1C1 style.
+func (r Range) StringR1C1(origin string) string {
+	return r._agbg.StringR1C1(origin) + ":" + r._eaebg.StringR1C1(origin)
+}
+
+// StringR1C1 returns a string representation of VerticalRange in R1C1 style.
+func (v VerticalRange) StringR1C1(origin string) string {
+	return a1TextToR1C1(v.String(), origin)
+}
+
+// StringR1C1 returns a string representation of HorizontalRange in R1C1 style.
+func (h HorizontalRange) StringR1C1(origin string) string {
+	return a1TextToR1C1(h.String(), origin)
+}
+
+// StringR1C1 returns a string representation of PrefixExpr in R1C1 style.
+func (p PrefixExpr) StringR1C1(origin string) string {
+	return p._fdfge.String() + "!" + p._fecb.StringR1C1(origin)
+}
+
+// StringR1C1 returns a string representation of PrefixRangeExpr in R1C1 style.
+func (p PrefixRangeExpr) StringR1C1(origin string) string {
+	return p._bcdd.String() + "!" + p._dfedb.StringR1C1(origin) + ":" + p._bebbg.StringR1C1(origin)
+}
+
+// StringR1C1 returns a string representation of PrefixVerticalRange in R1C1
+// style.
+func (p PrefixVerticalRange) StringR1C1(origin string) string {
+	return p._decac.String() + "!" + a1TextToR1C1(p._bddaf+":"+p._cfbe, origin)
+}
+
+// StringR1C1 returns a string representation of PrefixHorizontalRange in R1C1
+// style.
+func (p PrefixHorizontalRange) StringR1C1(origin string) string {
+	return p._efaa.String() + "!" + a1TextToR1C1(strconv.Itoa(p._fceca)+":"+strconv.Itoa(p._fegg), origin)
+}
+
+// StringR1C1 returns a string representation of FunctionCall in R1C1 style.
+func (f FunctionCall) StringR1C1(origin string) string {
+	args := make([]string, 0, len(f._ddaa))
+	for _, a := range f._ddaa {
+		args = append(args, a.StringR1C1(origin))
+	}
+	return f._ddea + "(" + strings.Join(args, ",") + ")"
+}
+
+// StringR1C1 returns a string representation of Negate in R1C1 style.
+func (n Negate) StringR1C1(origin string) string {
+	if _, ok := n._ebadf.(BinaryExpr); ok {
+		return "-(" + n._ebadf.StringR1C1(origin) + ")"
+	}
+	return "-" + n._ebadf.StringR1C1(origin)
+}
+
+var binOpStrings = map[BinOpType]string{
+	BinOpTypePlus:   "+",
+	BinOpTypeMinus:  "-",
+	BinOpTypeMult:   "*",
+	BinOpTypeDiv:    "/",
+	BinOpTypeExp:    "^",
+	BinOpTypeLT:     "<",
+	BinOpTypeGT:     ">",
+	BinOpTypeEQ:     "=",
+	BinOpTypeLEQ:    "<=",
+	BinOpTypeGEQ:    ">=",
+	BinOpTypeNE:     "<>",
+	BinOpTypeConcat: "&",
+}
+
+// binOpPrecedence returns the precedence of an operator, higher binds tighter.
+func binOpPrecedence(op BinOpType) int {
+	switch op {
+	case BinOpTypeExp:
+		return 5
+	case BinOpTypeMult, BinOpTypeDiv:
+		return 4
+	case BinOpTypePlus, BinOpTypeMinus:
+		return 3
+	case BinOpTypeConcat:
+		return 2
+	}
+	return 1
+}
+
+// StringR1C1 returns a string representation of BinaryExpr in R1C1 style,
+// operands are parenthesized where the operator precedence requires it.
+func (b BinaryExpr) StringR1C1(origin string) string {
+	prec := binOpPrecedence(b._ge)
+	lhs := b._da.StringR1C1(origin)
+	if l, ok := b._da.(BinaryExpr); ok && binOpPrecedence(l._ge) < prec {
+		lhs = "(" + lhs + ")"
+	}
+	rhs := b._db.StringR1C1(origin)
+	if r, ok := b._db.(BinaryExpr); ok && binOpPrecedence(r._ge) <= prec {
+		rhs = "(" + rhs + ")"
+	}
+	return lhs + binOpStrings[b._ge] + rhs
+}
+
+// StringR1C1 returns a string representation of Number, it has no references.
+func (n Number) StringR1C1(origin string) string { return n.String() }
+
+// StringR1C1 returns a string representation of String, it has no references.
+func (s String) StringR1C1(origin string) string { return s.String() }
+
+// StringR1C1 returns a string representation of Bool, it has no references.
+func (b Bool) StringR1C1(origin string) string { return b.String() }
+
+// StringR1C1 returns a string representation of Error, it has no references.
+func (e Error) StringR1C1(origin string) string { return e.String() }
+
+// StringR1C1 returns a string representation of EmptyExpr, it has no
+// references.
+func (e EmptyExpr) StringR1C1(origin string) string { return e.String() }
+
+// StringR1C1 returns a string representation of NamedRangeRef, defined names
+// are the same in both reference styles.
+func (n NamedRangeRef) StringR1C1(origin string) string { return n.String() }
+
+// StringR1C1 returns a string representation of SheetPrefixExpr, it has no
+// references.
+func (s SheetPrefixExpr) StringR1C1(origin string) string { return s.String() }
+
+// StringR1C1 returns a string representation of ConstArrayExpr, it has no
+// references.
+func (c ConstArrayExpr) StringR1C1(origin string) string { return c.String() }
@@ -0,0 +1,71 @@
+package formula
+
+import "testing"
+
+func TestR1C1ToA1(t *testing.T) {
+	td := []struct {
+		inp, origin, exp string
+	}{
+		{"R[-1]C+RC[-2]", "C5", "C4+A5"},
+		{"SUM(R1C1:R[2]C)", "B2", "SUM($A$1:B4)"},
+		{"R2C3", "A1", "$C$2"},
+		{"SUM(C[1])", "A1", "SUM(B:B)"},
+		{"SUM(R1:R[1])", "A5", "SUM($1:6)"},
+		{"ROUND(RC[1],2)&\"R1C1\"", "A1", "ROUND(B1,2)&\"R1C1\""},
+		{"'R1C1'!RC", "D4", "'R1C1'!D4"},
+		{"Sheet2!R[1]C[1]", "A1", "Sheet2!B2"},
+		{"R[-1]C", "A1", "#REF!"},
+		{"Table1[Col]+RC", "B2", "Table1[Col]+B2"},
+	}
+	for _, tc := range td {
+		got, err := R1C1ToA1(tc.inp, tc.origin)
+		if err != nil {
+			t.Fatalf("R1C1ToA1(%q, %q) error: %s", tc.inp, tc.origin, err)
+		}
+		if got != tc.exp {
+			t.Errorf("R1C1ToA1(%q, %q) = %q, expected %q", tc.inp, tc.origin, got, tc.exp)
+		}
+	}
+}
+
+func TestA1ToR1C1(t *testing.T) {
+	td := []struct {
+		inp, origin, exp string
+	}{
+		{"C4+A5", "C5", "R[-1]C+RC[-2]"},
+		{"SUM($A$1:B4)", "B2", "SUM(R1C1:R[2]C)"},
+		{"'My Sheet'!$C2", "A1", "'My Sheet'!R[1]C3"},
+		{"SUM(B:B)", "A1", "SUM(C[1]:C[1])"},
+	}
+	for _, tc := range td {
+		got, err := A1ToR1C1(tc.inp, tc.origin)
+		if err != nil {
+			t.Fatalf("A1ToR1C1(%q, %q) error: %s", tc.inp, tc.origin, err)
+		}
+		if got != tc.exp {
+			t.Errorf("A1ToR1C1(%q, %q) = %q, expected %q", tc.inp, tc.origin, got, tc.exp)
+		}
+	}
+}
+
+func TestExpressionStringR1C1(t *testing.T) {
+	td := []struct {
+		inp, origin, exp string
+	}{
+		{"(A1+B1)*2", "C1", "(RC[-2]+RC[-1])*2"},
+		{"SUM(A1:A3)/-(B2-1)", "B4", "SUM(R[-3]C[-1]:R[-1]C[-1])/-(R[-2]C-1)"},
+		{"Sheet1!$A$1", "B2", "Sheet1!R1C1"},
+	}
+	for _, tc := range td {
+		expr := ParseString(tc.inp)
+		if expr == nil {
+			t.Fatalf("error parsing %q", tc.inp)
+		}
+		if got := expr.StringR1C1(tc.origin); got != tc.exp {
+			t.Errorf("StringR1C1(%q, %q) = %q, expected %q", tc.inp, tc.origin, got, tc.exp)
+		}
+	}
+	if expr := ParseR1C1("R[-1]C*2", "B3"); expr == nil || expr.String() != "B2*2" {
+		t.Errorf("unexpected ParseR1C1 result %v", expr)
+	}
+}
@@ -0,0 +1,295 @@
+package formula
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/yaklabco/unioffice/v2/spreadsheet/reference"
+)
+
+// Limits of a worksheet, references beyond them are invalid.
+const (
+	maxRows    = 1048576
+	maxColumns = 16384
+)
+
+// refKind is the kind of A1 style reference found in a formula.
+type refKind byte
+
+const (
+	refKindCell refKind = iota
+	refKindColumn
+	refKindRow
+)
+
+// a1Ref is a single component of an A1 reference found in formula text. For
+// column references row is zero and for row references col is zero.
+type a1Ref struct {
+	kind     refKind
+	col      uint32
+	row      uint32
+	absCol   bool
+	absRow   bool
+	hasSheet bool
+}
+
+func (r a1Ref) String() string {
+	b := strings.Builder{}
+	if r.kind != refKindRow {
+		if r.absCol {
+			b.WriteByte('$')
+		}
+		b.WriteString(reference.IndexToColumn(r.col))
+	}
+	if r.kind != refKindColumn {
+		if r.absRow {
+			b.WriteByte('$')
+		}
+		b.WriteString(strconv.FormatUint(uint64(r.row), 10))
+	}
+	return b.String()
+}
+
+// parseA1Cell parses a cell reference such as A1 or $B$7.
+func parseA1Cell(s string) (a1Ref, bool) {
+	r := a1Ref{kind: refKindCell}
+	i := 0
+	if i < len(s) && s[i] == '$' {
+		r.absCol = true
+		i++
+	}
+	start := i
+	for i < len(s) && isASCIILetter(s[i]) {
+		i++
+	}
+	if i == start || i-start > 3 {
+		return r, false
+	}
+	r.col = reference.ColumnToIndex(strings.ToUpper(s[start:i]))
+	if i < len(s) && s[i] == '$' {
+		r.absRow = true
+		i++
+	}
+	start = i
+	for i < len(s) && isASCIIDigit(s[i]) {
+		i++
+	}
+	if i == start || i != len(s) || s[start] == '0' {
+		return r, false
+	}
+	row, err := strconv.ParseUint(s[start:], 10, 32)
+	if err != nil || row > maxRows || r.col >= maxColumns {
+		return r, false
+	}
+	r.row = uint32(row)
+	return r, true
+}
+
+// parseA1Column parses the column part of a column range such as A:C.
+func parseA1Column(s string) (a1Ref, bool) {
+	r := a1Ref{kind: refKindColumn}
+	if strings.HasPrefix(s, "$") {
+		r.absCol = true
+		s = s[1:]
+	}
+	if len(s) == 0 || len(s) > 3 {
+		return r, false
+	}
+	for i := 0; i < len(s); i++ {
+		if !isASCIILetter(s[i]) {
+			return r, false
+		}
+	}
+	r.col = reference.ColumnToIndex(strings.ToUpper(s))
+	return r, r.col < maxColumns
+}
+
+// parseA1Row parses the row part of a row range such as 1:3.
+func parseA1Row(s string) (a1Ref, bool) {
+	r := a1Ref{kind: refKindRow}
+	if strings.HasPrefix(s, "$") {
+		r.absRow = true
+		s = s[1:]
+	}
+	if len(s) == 0 || s[0] == '0' {
+		return r, false
+	}
+	for i := 0; i < len(s); i++ {
+		if !isASCIIDigit(s[i]) {
+			return r, false
+		}
+	}
+	row, err := strconv.ParseUint(s, 10, 32)
+	if err != nil || row > maxRows {
+		return r, false
+	}
+	r.row = uint32(row)
+	return r, true
+}
+
+func isASCIILetter(c byte) bool { return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') }
+func isASCIIDigit(c byte) bool  { return c >= '0' && c <= '9' }
+
+func isNameChar(c byte) bool {
+	return isASCIILetter(c) || isASCIIDigit(c) || c == '_' || c == '$' || c == '.' || c == '\\' || c >= 0x80
+}
+
+// mapA1References calls fn for every A1 reference in formula and replaces the
+// reference with the returned text. String literals, quoted sheet names,
+// function names, defined names and structured references are left as is.
+func mapA1References(formula string, fn func(r a1Ref) string) string {
+	out := strings.Builder{}
+	i := 0
+	sheetPrefix := false
+	for i < len(formula) {
+		c := formula[i]
+		switch {
+		case c == '"':
+			j := skipQuoted(formula, i, '"')
+			out.WriteString(formula[i:j])
+			i = j
+			continue
+		case c == '\'':
+			j := skipQuoted(formula, i, '\'')
+			out.WriteString(formula[i:j])
+			i = j
+			if i < len(formula) && formula[i] == '!' {
+				out.WriteByte('!')
+				i++
+				sheetPrefix = true
+			}
+			continue
+		case c == '[':
+			j := skipBrackets(formula, i)
+			out.WriteString(formula[i:j])
+			i = j
+			continue
+		case isNameChar(c):
+			j := i
+			for j < len(formula) && isNameChar(formula[j]) {
+				j++
+			}
+			word := formula[i:j]
+			hasSheet := sheetPrefix
+			sheetPrefix = false
+			if j < len(formula) && formula[j] == '!' {
+				out.WriteString(word)
+				out.WriteByte('!')
+				i = j + 1
+				sheetPrefix = true
+				continue
+			}
+			if j < len(formula) && (formula[j] == '(' || formula[j] == '[') {
+				out.WriteString(word)
+				i = j
+				continue
+			}
+			if r, ok := parseA1Cell(word); ok {
+				r.hasSheet = hasSheet
+				out.WriteString(fn(r))
+				i = j
+				// the second cell of a range shares the sheet prefix
+				sheetPrefix = hasSheet && j < len(formula) && formula[j] == ':'
+				continue
+			}
+			// whole column (A:C) and whole row (1:3) ranges
+			if j < len(formula) && formula[j] == ':' {
+				k := j + 1
+				for k < len(formula) && isNameChar(formula[k]) {
+					k++
+				}
+				other := formula[j+1 : k]
+				if from, ok := parseA1Column(word); ok {
+					if to, ok := parseA1Column(other); ok {
+						from.hasSheet, to.hasSheet = hasSheet, hasSheet
+						out.WriteString(fn(from) + ":" + fn(to))
+						i = k
+						continue
+					}
+				}
+				if from, ok := parseA1Row(word); ok {
+					if to, ok := parseA1Row(other); ok {
+						from.hasSheet, to.hasSheet = hasSheet, hasSheet
+						out.WriteString(fn(from) + ":" + fn(to))
+						i = k
+						continue
+					}
+				}
+			}
+			out.WriteString(word)
+			i = j
+			continue
+		}
+		if c != ':' {
+			sheetPrefix = false
+		}
+		out.WriteByte(c)
+		i++
+	}
+	return out.String()
+}
+
+// skipQuoted returns the index following the quoted section starting at i,
+// where doubled quote characters are escapes.
+func skipQuoted(s string, i int, q byte) int {
+	j := i + 1
+	for j < len(s) {
+		if s[j] == q {
+			if j+1 < len(s) && s[j+1] == q {
+				j += 2
+				continue
+			}
+			return j + 1
+		}
+		j++
+	}
+	return len(s)
+}
+
+// skipBrackets returns the index following the (possibly nested) bracketed
+// section starting at i.
+func skipBrackets(s string, i int) int {
+	depth := 0
+	for j := i; j < len(s); j++ {
+		switch s[j] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				return j + 1
+			}
+		case '\'':
+			// escaped characters in structured references
+			j++
+		}
+	}
+	return len(s)
+}
+
+// ShiftReferences returns formula with its relative references moved by the
+// given number of rows and columns, as Excel does when a formula is copied
+// from one cell to another. Absolute references are left unchanged and
+// references moved outside of the sheet become #REF!.
+func ShiftReferences(formula string, rowOffset, colOffset int) string {
+	if rowOffset == 0 && colOffset == 0 {
+		return formula
+	}
+	return mapA1References(formula, func(r a1Ref) string {
+		if r.kind != refKindRow && !r.absCol {
+			c := int(r.col) + colOffset
+			if c < 0 || c >= maxColumns {
+				return "#REF!"
+			}
+			r.col = uint32(c)
+		}
+		if r.kind != refKindColumn && !r.absRow {
+			row := int(r.row) + rowOffset
+			if row < 1 || row > maxRows {
+				return "#REF!"
+			}
+			r.row = uint32(row)
+		}
+		return r.String()
+	})
+}
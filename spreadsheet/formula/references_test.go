@@ -0,0 +1,25 @@
+package formula
+
+import "testing"
+
+func TestShiftReferences(t *testing.T) {
+	td := []struct {
+		inp      string
+		row, col int
+		exp      string
+	}{
+		{"A1+B2", 1, 0, "A2+B3"},
+		{"SUM($A$1:A3)*2", 2, 1, "SUM($A$1:B5)*2"},
+		{"Sheet2!C3&\"A1\"", 1, 1, "Sheet2!D4&\"A1\""},
+		{"'My Sheet'!A$1", 5, 2, "'My Sheet'!C$1"},
+		{"SUM(A:A,2:3)", 1, 1, "SUM(B:B,3:4)"},
+		{"LOG10(A1)+Table1[Col]", 1, 0, "LOG10(A2)+Table1[Col]"},
+		{"A1", -1, 0, "#REF!"},
+		{"1.5E+3*A1", 0, 1, "1.5E+3*B1"},
+	}
+	for _, tc := range td {
+		if got := ShiftReferences(tc.inp, tc.row, tc.col); got != tc.exp {
+			t.Errorf("ShiftReferences(%q, %d, %d) = %q, expected %q", tc.inp, tc.row, tc.col, got, tc.exp)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package formula
+
+// Walk calls fn for an expression and each of its sub-expressions in depth
+// first order. The sub-expressions of an expression are skipped if fn returns
+// false for it.
+func Walk(expr Expression, fn func(e Expression) bool) {
+	if expr == nil || !fn(expr) {
+		return
+	}
+	for _, c := range subExpressions(expr) {
+		Walk(c, fn)
+	}
+}
+
+// subExpressions returns the direct sub-expressions of an expression.
+func subExpressions(expr Expression) []Expression {
+	switch e := expr.(type) {
+	case BinaryExpr:
+		return []Expression{e._da, e._db}
+	case FunctionCall:
+		return e._ddaa
+	case Negate:
+		return []Expression{e._ebadf}
+	case Range:
+		return []Expression{e._agbg, e._eaebg}
+	case PrefixExpr:
+		return []Expression{e._fdfge, e._fecb}
+	case *PrefixExpr:
+		return []Expression{e._fdfge, e._fecb}
+	case PrefixRangeExpr:
+		return []Expression{e._bcdd, e._dfedb, e._bebbg}
+	case *PrefixRangeExpr:
+		return []Expression{e._bcdd, e._dfedb, e._bebbg}
+	case PrefixVerticalRange:
+		return []Expression{e._decac}
+	case PrefixHorizontalRange:
+		return []Expression{e._efaa}
+	case ConstArrayExpr:
+		return flattenConstArray(e._ed)
+	case *ConstArrayExpr:
+		return flattenConstArray(e._ed)
+	}
+	return nil
+}
+
+func flattenConstArray(rows [][]Expression) []Expression {
+	ret := []Expression{}
+	for _, r := range rows {
+		ret = append(ret, r...)
+	}
+	return ret
+}
+
+// References returns the cells, ranges and defined names an expression refers
+// to. Sheet qualified references include the sheet name in their value.
+func References(ctx Context, ev Evaluator, expr Expression) []Reference {
+	ret := []Reference{}
+	Walk(expr, func(e Expression) bool {
+		switch e.(type) {
+		case CellRef, NamedRangeRef, Range, VerticalRange, HorizontalRange,
+			PrefixExpr, *PrefixExpr, PrefixRangeExpr, *PrefixRangeExpr,
+			PrefixVerticalRange, PrefixHorizontalRange:
+			if r := e.Reference(ctx, ev); r.Type != ReferenceTypeInvalid {
+				ret = append(ret, r)
+			}
+			// the parts of a reference aren't references on their own
+			return false
+		}
+		return true
+	})
+	return ret
+}
@@ -0,0 +1,762 @@
+package spreadsheet
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/yaklabco/unioffice/v2/common"
+	"github.com/yaklabco/unioffice/v2/common/tempstorage"
+	"github.com/yaklabco/unioffice/v2/schema/soo/sml"
+	"github.com/yaklabco/unioffice/v2/spreadsheet/reference"
+)
+
+// HTMLOptions controls how sheets are written by WriteHTML.
+type HTMLOptions struct {
+	// Range limits the output to a range of cells (e.g. "A1:F20"), by default
+	// the used range of the sheet is written.
+	Range string
+	// Fragment writes only the style and table elements instead of a complete
+	// HTML document, for embedding in another page.
+	Fragment bool
+	// IncludeHidden writes hidden rows, columns and sheets.
+	IncludeHidden bool
+	// Gridlines draws light gray cell borders where no border is set.
+	Gridlines bool
+	// NoImages omits the images of the sheet drawing.
+	NoImages bool
+	// NoConditionalFormats ignores conditional formatting.
+	NoConditionalFormats bool
+}
+
+// htmlBaseCSS is the style shared by all written tables, cell styles are
+// written as one class per cell format.
+const htmlBaseCSS = `table.ws{border-collapse:collapse;table-layout:fixed}
+table.ws td{overflow:hidden;white-space:nowrap;vertical-align:bottom;padding:0 2px}
+table.ws td.n{text-align:right}
+table.ws td.b{text-align:center}
+table.ws.grid td{border:1px solid #D4D4D4}
+`
+
+// htmlTabsCSS switches between the sheets of a workbook without scripts.
+const htmlTabsCSS = `div.wb>input{display:none}
+div.wb>label{display:inline-block;padding:2px 12px;border:1px solid #ABABAB;border-bottom:none;cursor:pointer;font-family:sans-serif;font-size:10pt}
+div.wb>div.sheet{display:none;border-top:1px solid #ABABAB;overflow:auto}
+`
+
+// htmlWriter collects the tables written and the cell formats they use.
+type htmlWriter struct {
+	wb   *Workbook
+	opts HTMLOptions
+	buf  bytes.Buffer
+	xfs  map[uint32]bool
+}
+
+func newHTMLWriter(wb *Workbook, opts HTMLOptions) *htmlWriter {
+	return &htmlWriter{wb: wb, opts: opts, xfs: map[uint32]bool{0: true}}
+}
+
+// WriteHTML writes the sheet as an HTML table. Cell values are formatted with
+// their number formats, cell fonts, fills, borders and alignment along with
+// matching conditional formats are written as CSS, and merged cells become
+// cells spanning multiple rows or columns.
+func (s *Sheet) WriteHTML(w io.Writer, opts HTMLOptions) error {
+	hw := newHTMLWriter(s._fgeg, opts)
+	if err := hw.table(s); err != nil {
+		return err
+	}
+	return hw.flush(w, s.Name(), "")
+}
+
+// WriteHTML writes the visible sheets of the workbook as HTML tables, with a
+// tab for each sheet to switch between them. The Range option is ignored.
+func (wb *Workbook) WriteHTML(w io.Writer, opts HTMLOptions) error {
+	opts.Range = ""
+	hw := newHTMLWriter(wb, opts)
+	css := strings.Builder{}
+	css.WriteString(htmlTabsCSS)
+	hw.buf.WriteString("<div class=\"wb\">\n")
+	sheets := []Sheet{}
+	for _, s := range wb.AllSheets() {
+		if s.X() == nil {
+			continue
+		}
+		if st := s._abea.StateAttr; !opts.IncludeHidden && (st == sml.ST_SheetStateHidden || st == sml.ST_SheetStateVeryHidden) {
+			continue
+		}
+		sheets = append(sheets, s)
+	}
+	for i, s := range sheets {
+		checked := ""
+		if i == 0 {
+			checked = " checked"
+		}
+		fmt.Fprintf(&hw.buf, "<input type=\"radio\" name=\"wb-tabs\" id=\"wb-tab-%d\"%s><label for=\"wb-tab-%d\">%s</label>\n", i, checked, i, html.EscapeString(s.Name()))
+		fmt.Fprintf(&css, "#wb-tab-%d:checked~#wb-sheet-%d{display:block}\n#wb-tab-%d:checked+label{background:#FFFFFF;font-weight:bold}\n", i, i, i)
+	}
+	for i, s := range sheets {
+		fmt.Fprintf(&hw.buf, "<div class=\"sheet\" id=\"wb-sheet-%d\">\n", i)
+		if err := hw.table(&s); err != nil {
+			return fmt.Errorf("sheet %s: %w", s.Name(), err)
+		}
+		hw.buf.WriteString("</div>\n")
+	}
+	hw.buf.WriteString("</div>\n")
+	title := ""
+	if len(sheets) > 0 {
+		title = sheets[0].Name()
+	}
+	return hw.flush(w, title, css.String())
+}
+
+// flush writes the style and the collected tables, wrapped in a document
+// unless a fragment is requested.
+func (hw *htmlWriter) flush(w io.Writer, title, css string) error {
+	out := bytes.Buffer{}
+	if !hw.opts.Fragment {
+		fmt.Fprintf(&out, "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>%s</title>\n", html.EscapeString(title))
+	}
+	out.WriteString("<style>\n")
+	out.WriteString(htmlBaseCSS)
+	out.WriteString(css)
+	ids := make([]uint32, 0, len(hw.xfs))
+	for id := range hw.xfs {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	for _, id := range ids {
+		if decl := hw.xfCSS(id); decl != "" {
+			fmt.Fprintf(&out, "table.ws td.x%d{%s}\n", id, decl)
+		}
+	}
+	out.WriteString("</style>\n")
+	if !hw.opts.Fragment {
+		out.WriteString("</head>\n<body>\n")
+	}
+	out.Write(hw.buf.Bytes())
+	if !hw.opts.Fragment {
+		out.WriteString("</body>\n</html>\n")
+	}
+	_, err := w.Write(out.Bytes())
+	return err
+}
+
+// htmlSpan is the extent of a merged cell in visible rows and columns.
+type htmlSpan struct {
+	cols, rows int
+}
+
+// table writes a sheet as a table.
+func (hw *htmlWriter) table(s *Sheet) error {
+	c1, r1, c2, r2, err := hw.extents(s)
+	if err != nil {
+		return err
+	}
+	ws := s.X()
+
+	// visible columns and their widths in pixels
+	cols := []uint32{}
+	widths := map[uint32]int{}
+	total := 0
+	for c := c1; c <= c2 && r2 >= r1; c++ {
+		px, hidden := s.columnPixels(c)
+		widths[c] = px
+		if hidden && !hw.opts.IncludeHidden {
+			widths[c] = 0
+			continue
+		}
+		cols = append(cols, c)
+		total += px
+	}
+
+	rows := map[uint32]*sml.CT_Row{}
+	for _, r := range ws.SheetData.Row {
+		if r.RAttr != nil {
+			rows[*r.RAttr] = r
+		}
+	}
+	rowVisible := func(r uint32) bool {
+		row := rows[r]
+		return hw.opts.IncludeHidden || row == nil || row.HiddenAttr == nil || !*row.HiddenAttr
+	}
+	colVisible := func(c uint32) bool {
+		return hw.opts.IncludeHidden || widths[c] > 0
+	}
+
+	spans, covered := hw.merges(s, c1, r1, c2, r2, rowVisible, colVisible)
+	cf := map[string]*cfStyle{}
+	if !hw.opts.NoConditionalFormats {
+		cf = s.conditionalStyles(c1, r1, c2, r2)
+	}
+	images := map[string][]string{}
+	if !hw.opts.NoImages {
+		images = hw.images(s, widths, rows)
+	}
+
+	class := "ws"
+	if hw.opts.Gridlines {
+		class += " grid"
+	}
+	fmt.Fprintf(&hw.buf, "<table class=\"%s\" style=\"width:%dpx\">\n<colgroup>", class, total)
+	for _, c := range cols {
+		fmt.Fprintf(&hw.buf, "<col style=\"width:%dpx\">", widths[c])
+	}
+	hw.buf.WriteString("</colgroup>\n")
+	for r := r1; r <= r2; r++ {
+		if !rowVisible(r) {
+			continue
+		}
+		row := rows[r]
+		fmt.Fprintf(&hw.buf, "<tr style=\"height:%spt\">", formatCSSNumber(s.rowHeight(row)))
+		for _, c := range cols {
+			ref := reference.IndexToColumn(c) + strconv.Itoa(int(r))
+			if covered[ref] {
+				continue
+			}
+			hw.cell(s, row, c, ref, spans[ref], cf[ref], images[ref])
+		}
+		hw.buf.WriteString("</tr>\n")
+	}
+	hw.buf.WriteString("</table>\n")
+	return nil
+}
+
+// extents returns the zero based column and one based row bounds to write.
+func (hw *htmlWriter) extents(s *Sheet) (c1, r1, c2, r2 uint32, err error) {
+	if hw.opts.Range != "" {
+		from, to, err := reference.ParseRangeReference(hw.opts.Range)
+		if err != nil {
+			return 0, 0, 0, 0, fmt.Errorf("invalid range: %w", err)
+		}
+		return from.ColumnIdx, from.RowIdx, to.ColumnIdx, to.RowIdx, nil
+	}
+	if len(s.X().SheetData.Row) == 0 {
+		return 0, 1, 0, 0, nil
+	}
+	fc, fr, tc, tr := s.ExtentsIndex()
+	c1, r1 = reference.ColumnToIndex(fc), fr
+	c2, r2 = reference.ColumnToIndex(tc), tr
+	for _, m := range s.MergedCells() {
+		from, to, err := reference.ParseRangeReference(m.Reference())
+		if err != nil {
+			continue
+		}
+		c1, r1 = minUint32(c1, from.ColumnIdx), minUint32(r1, from.RowIdx)
+		c2, r2 = maxUint32(c2, to.ColumnIdx), maxUint32(r2, to.RowIdx)
+	}
+	return c1, r1, c2, r2, nil
+}
+
+func minUint32(a, b uint32) uint32 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxUint32(a, b uint32) uint32 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// columnPixels returns the width of a zero based column in pixels and
+// whether it is hidden.
+func (s *Sheet) columnPixels(c uint32) (int, bool) {
+	for _, cols := range s.X().Cols {
+		for _, col := range cols.Col {
+			if c+1 < col.MinAttr || c+1 > col.MaxAttr {
+				continue
+			}
+			hidden := col.HiddenAttr != nil && *col.HiddenAttr
+			if col.WidthAttr != nil {
+				return int(math.Trunc((256**col.WidthAttr + math.Trunc(128.0/7)) / 256 * 7)), hidden
+			}
+			return s.defaultColumnPixels(), hidden
+		}
+	}
+	return s.defaultColumnPixels(), false
+}
+
+func (s *Sheet) defaultColumnPixels() int {
+	if pr := s.X().SheetFormatPr; pr != nil {
+		if pr.DefaultColWidthAttr != nil {
+			return int(math.Trunc((256**pr.DefaultColWidthAttr + math.Trunc(128.0/7)) / 256 * 7))
+		}
+		if pr.BaseColWidthAttr != nil {
+			// base width in characters plus padding, rounded up to 8 pixels
+			return int(math.Ceil(float64(*pr.BaseColWidthAttr*7+5)/8) * 8)
+		}
+	}
+	return 64
+}
+
+// rowHeight returns the height of a row in points.
+func (s *Sheet) rowHeight(r *sml.CT_Row) float64 {
+	if r != nil && r.HtAttr != nil {
+		return *r.HtAttr
+	}
+	if pr := s.X().SheetFormatPr; pr != nil && pr.DefaultRowHeightAttr > 0 {
+		return pr.DefaultRowHeightAttr
+	}
+	return 15
+}
+
+func formatCSSNumber(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+
+// merges returns the spans of the merged cells within the bounds keyed by
+// their top left cell, and the cells they cover.
+func (hw *htmlWriter) merges(s *Sheet, c1, r1, c2, r2 uint32, rowVisible, colVisible func(uint32) bool) (map[string]htmlSpan, map[string]bool) {
+	spans := map[string]htmlSpan{}
+	covered := map[string]bool{}
+	for _, m := range s.MergedCells() {
+		from, to, err := reference.ParseRangeReference(m.Reference())
+		if err != nil {
+			continue
+		}
+		fc, fr := maxUint32(from.ColumnIdx, c1), maxUint32(from.RowIdx, r1)
+		tc, tr := minUint32(to.ColumnIdx, c2), minUint32(to.RowIdx, r2)
+		if fc > tc || fr > tr {
+			continue
+		}
+		// the first visible cell of the merge holds its content
+		top := ""
+		span := htmlSpan{}
+		for r := fr; r <= tr; r++ {
+			if rowVisible(r) {
+				span.rows++
+			}
+		}
+		for c := fc; c <= tc; c++ {
+			if colVisible(c) {
+				span.cols++
+			}
+		}
+		for r := fr; r <= tr; r++ {
+			for c := fc; c <= tc; c++ {
+				ref := reference.IndexToColumn(c) + strconv.Itoa(int(r))
+				if top == "" && rowVisible(r) && colVisible(c) {
+					top = ref
+					continue
+				}
+				covered[ref] = true
+			}
+		}
+		if top != "" {
+			spans[top] = span
+		}
+	}
+	return spans, covered
+}
+
+// cell writes a table cell.
+func (hw *htmlWriter) cell(s *Sheet, row *sml.CT_Row, col uint32, ref string, span htmlSpan, cf *cfStyle, images []string) {
+	var x *sml.CT_Cell
+	if row != nil {
+		for _, c := range row.C {
+			if c.RAttr != nil && strings.ReplaceAll(*c.RAttr, "$", "") == ref {
+				x = c
+				break
+			}
+		}
+	}
+	xf := s.styleIndex(row, x, col)
+	hw.xfs[xf] = true
+	classes := []string{"x" + strconv.Itoa(int(xf))}
+	value := ""
+	if x != nil {
+		cell := Cell{s._fgeg, s, row, x}
+		value = cell.GetFormattedValue()
+		switch x.TAttr {
+		case sml.ST_CellTypeB, sml.ST_CellTypeE:
+			classes = []string{"b", classes[0]}
+		case sml.ST_CellTypeN, sml.ST_CellTypeUnset:
+			if x.V != nil && *x.V != "" {
+				classes = []string{"n", classes[0]}
+			}
+		}
+	}
+
+	hw.buf.WriteString("<td class=\"" + strings.Join(classes, " ") + "\"")
+	if span.cols > 1 {
+		fmt.Fprintf(&hw.buf, " colspan=\"%d\"", span.cols)
+	}
+	if span.rows > 1 {
+		fmt.Fprintf(&hw.buf, " rowspan=\"%d\"", span.rows)
+	}
+	style := hw.cfCSS(cf)
+	if len(images) > 0 {
+		style += "position:relative;overflow:visible;"
+	}
+	if style != "" {
+		hw.buf.WriteString(" style=\"" + style + "\"")
+	}
+	hw.buf.WriteString(">")
+	for _, img := range images {
+		hw.buf.WriteString(img)
+	}
+	hw.buf.WriteString(strings.ReplaceAll(html.EscapeString(value), "\n", "<br>"))
+	hw.buf.WriteString("</td>")
+}
+
+// styleIndex returns the cell format of a cell, which is inherited from its
+// row or zero based column if the cell doesn't exist or has no style.
+func (s *Sheet) styleIndex(row *sml.CT_Row, x *sml.CT_Cell, col uint32) uint32 {
+	switch {
+	case x != nil && x.SAttr != nil:
+		return *x.SAttr
+	case row != nil && row.SAttr != nil && row.CustomFormatAttr != nil && *row.CustomFormatAttr:
+		return *row.SAttr
+	}
+	xf := uint32(0)
+	for _, cols := range s.X().Cols {
+		for _, c := range cols.Col {
+			if col+1 >= c.MinAttr && col+1 <= c.MaxAttr && c.StyleAttr != nil {
+				xf = *c.StyleAttr
+			}
+		}
+	}
+	return xf
+}
+
+// color returns a color as a CSS value.
+func (hw *htmlWriter) color(c *sml.CT_Color) (string, bool) {
+	rgb, ok := hw.wb.resolveColor(c)
+	if !ok || len(rgb) != 6 {
+		return "", false
+	}
+	for _, r := range rgb {
+		if !strings.ContainsRune("0123456789ABCDEFabcdef", r) {
+			return "", false
+		}
+	}
+	return "#" + rgb, true
+}
+
+// cssString quotes a value as a CSS string. Characters other than letters,
+// digits, spaces, hyphens and underscores are escaped so the value can't end
+// the string, the declaration or the element it is written to.
+func cssString(v string) string {
+	b := strings.Builder{}
+	b.WriteByte('\'')
+	for _, r := range v {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == ' ' || r == '-' || r == '_':
+			b.WriteRune(r)
+		default:
+			// the trailing space ends the escape and isn't part of the value
+			fmt.Fprintf(&b, "\\%x ", r)
+		}
+	}
+	b.WriteByte('\'')
+	return b.String()
+}
+
+// xfCSS returns the CSS declarations of a cell format.
+func (hw *htmlWriter) xfCSS(id uint32) string {
+	ss := hw.wb.StyleSheet.X()
+	if ss.CellXfs == nil || int(id) >= len(ss.CellXfs.Xf) {
+		return ""
+	}
+	xf := ss.CellXfs.Xf[id]
+	css := strings.Builder{}
+	if xf.FontIdAttr != nil && ss.Fonts != nil && int(*xf.FontIdAttr) < len(ss.Fonts.Font) {
+		css.WriteString(hw.fontCSS(ss.Fonts.Font[*xf.FontIdAttr]))
+	}
+	if xf.FillIdAttr != nil && ss.Fills != nil && int(*xf.FillIdAttr) < len(ss.Fills.Fill) {
+		css.WriteString(hw.fillCSS(ss.Fills.Fill[*xf.FillIdAttr]))
+	}
+	if xf.BorderIdAttr != nil && ss.Borders != nil && int(*xf.BorderIdAttr) < len(ss.Borders.Border) {
+		css.WriteString(hw.borderCSS(ss.Borders.Border[*xf.BorderIdAttr]))
+	}
+	if a := xf.Alignment; a != nil {
+		switch a.HorizontalAttr {
+		case sml.ST_HorizontalAlignmentLeft, sml.ST_HorizontalAlignmentFill:
+			css.WriteString("text-align:left;")
+		case sml.ST_HorizontalAlignmentCenter, sml.ST_HorizontalAlignmentCenterContinuous:
+			css.WriteString("text-align:center;")
+		case sml.ST_HorizontalAlignmentRight:
+			css.WriteString("text-align:right;")
+		case sml.ST_HorizontalAlignmentJustify, sml.ST_HorizontalAlignmentDistributed:
+			css.WriteString("text-align:justify;")
+		}
+		switch a.VerticalAttr {
+		case sml.ST_VerticalAlignmentTop:
+			css.WriteString("vertical-align:top;")
+		case sml.ST_VerticalAlignmentCenter, sml.ST_VerticalAlignmentJustify, sml.ST_VerticalAlignmentDistributed:
+			css.WriteString("vertical-align:middle;")
+		}
+		if a.WrapTextAttr != nil && *a.WrapTextAttr {
+			css.WriteString("white-space:pre-wrap;")
+		}
+		if a.IndentAttr != nil && *a.IndentAttr > 0 {
+			fmt.Fprintf(&css, "padding-left:%dpx;", *a.IndentAttr*9)
+		}
+	}
+	return css.String()
+}
+
+// fontCSS returns the CSS declarations of a font.
+func (hw *htmlWriter) fontCSS(f *sml.CT_Font) string {
+	if f == nil {
+		return ""
+	}
+	css := strings.Builder{}
+	decoration := []string{}
+	for _, fc := range f.FontChoice {
+		switch {
+		case fc.Name != nil:
+			fmt.Fprintf(&css, "font-family:%s,sans-serif;", cssString(fc.Name.ValAttr))
+		case fc.Sz != nil:
+			fmt.Fprintf(&css, "font-size:%spt;", formatCSSNumber(fc.Sz.ValAttr))
+		case fc.B != nil:
+			if fc.B.ValAttr == nil || *fc.B.ValAttr {
+				css.WriteString("font-weight:bold;")
+			}
+		case fc.I != nil:
+			if fc.I.ValAttr == nil || *fc.I.ValAttr {
+				css.WriteString("font-style:italic;")
+			}
+		case fc.U != nil:
+			if fc.U.ValAttr != sml.ST_UnderlineValuesNone {
+				decoration = append(decoration, "underline")
+			}
+		case fc.Strike != nil:
+			if fc.Strike.ValAttr == nil || *fc.Strike.ValAttr {
+				decoration = append(decoration, "line-through")
+			}
+		case fc.Color != nil:
+			if c, ok := hw.color(fc.Color); ok {
+				css.WriteString("color:" + c + ";")
+			}
+		}
+	}
+	if len(decoration) > 0 {
+		css.WriteString("text-decoration:" + strings.Join(decoration, " ") + ";")
+	}
+	return css.String()
+}
+
+// fillCSS returns the CSS background of a fill.
+func (hw *htmlWriter) fillCSS(f *sml.CT_Fill) string {
+	if f == nil || f.FillChoice == nil {
+		return ""
+	}
+	if pf := f.FillChoice.PatternFill; pf != nil {
+		if pf.PatternTypeAttr == sml.ST_PatternTypeUnset || pf.PatternTypeAttr == sml.ST_PatternTypeNone {
+			return ""
+		}
+		if c, ok := hw.color(pf.FgColor); ok {
+			return "background-color:" + c + ";"
+		}
+		if c, ok := hw.color(pf.BgColor); ok {
+			return "background-color:" + c + ";"
+		}
+		return ""
+	}
+	if gf := f.FillChoice.GradientFill; gf != nil && len(gf.Stop) > 0 {
+		stops := []string{}
+		for _, st := range gf.Stop {
+			if c, ok := hw.color(st.Color); ok {
+				stops = append(stops, fmt.Sprintf("%s %s%%", c, formatCSSNumber(st.PositionAttr*100)))
+			}
+		}
+		deg := 0.0
+		if gf.DegreeAttr != nil {
+			deg = *gf.DegreeAttr
+		}
+		if len(stops) > 1 {
+			return fmt.Sprintf("background:linear-gradient(%sdeg,%s);", formatCSSNumber(deg+90), strings.Join(stops, ","))
+		}
+	}
+	return ""
+}
+
+// borderCSS returns the CSS declarations of a border.
+func (hw *htmlWriter) borderCSS(b *sml.CT_Border) string {
+	if b == nil {
+		return ""
+	}
+	css := strings.Builder{}
+	sides := []struct {
+		name string
+		pr   *sml.CT_BorderPr
+	}{{"top", b.Top}, {"right", b.Right}, {"bottom", b.Bottom}, {"left", b.Left}}
+	for _, side := range sides {
+		if side.pr == nil {
+			continue
+		}
+		var line string
+		switch side.pr.StyleAttr {
+		case sml.ST_BorderStyleThin:
+			line = "1px solid"
+		case sml.ST_BorderStyleMedium:
+			line = "2px solid"
+		case sml.ST_BorderStyleThick:
+			line = "3px solid"
+		case sml.ST_BorderStyleDouble:
+			line = "3px double"
+		case sml.ST_BorderStyleDotted, sml.ST_BorderStyleHair:
+			line = "1px dotted"
+		case sml.ST_BorderStyleDashed, sml.ST_BorderStyleDashDot, sml.ST_BorderStyleDashDotDot:
+			line = "1px dashed"
+		case sml.ST_BorderStyleMediumDashed, sml.ST_BorderStyleMediumDashDot,
+			sml.ST_BorderStyleMediumDashDotDot, sml.ST_BorderStyleSlantDashDot:
+			line = "2px dashed"
+		default:
+			continue
+		}
+		color := "#000000"
+		if c, ok := hw.color(side.pr.Color); ok {
+			color = c
+		}
+		fmt.Fprintf(&css, "border-%s:%s %s;", side.name, line, color)
+	}
+	return css.String()
+}
+
+// cfCSS returns the inline CSS of the conditional formatting of a cell.
+func (hw *htmlWriter) cfCSS(cf *cfStyle) string {
+	if cf == nil {
+		return ""
+	}
+	css := strings.Builder{}
+	if cf.background != "" {
+		css.WriteString("background-color:#" + cf.background + ";")
+	}
+	// apply the lowest priority first so higher priorities override it
+	for i := len(cf.dxfs) - 1; i >= 0; i-- {
+		dxf := cf.dxfs[i]
+		css.WriteString(hw.fontCSS(dxf.Font))
+		if dxf.Fill != nil && dxf.Fill.FillChoice != nil && dxf.Fill.FillChoice.PatternFill != nil {
+			// differential fills use the background color for solid fills
+			pf := dxf.Fill.FillChoice.PatternFill
+			if c, ok := hw.color(pf.BgColor); ok {
+				css.WriteString("background-color:" + c + ";")
+			} else if c, ok := hw.color(pf.FgColor); ok {
+				css.WriteString("background-color:" + c + ";")
+			}
+		}
+		css.WriteString(hw.borderCSS(dxf.Border))
+	}
+	if cf.bar > 0 {
+		pct := formatCSSNumber(math.Round(cf.bar * 100))
+		fmt.Fprintf(&css, "background-image:linear-gradient(90deg,#%s %s%%,transparent %s%%);", cf.barColor, pct, pct)
+	}
+	return css.String()
+}
+
+// images returns the img elements of the images anchored to each cell.
+func (hw *htmlWriter) images(s *Sheet, widths map[uint32]int, rows map[uint32]*sml.CT_Row) map[string][]string {
+	out := map[string][]string{}
+	dr, ok := s.Drawing()
+	if !ok {
+		return out
+	}
+	colPx := func(c uint32) int {
+		if w, ok := widths[c]; ok {
+			return w
+		}
+		px, hidden := s.columnPixels(c)
+		if hidden && !hw.opts.IncludeHidden {
+			return 0
+		}
+		return px
+	}
+	rowPx := func(r uint32) int {
+		row := rows[r]
+		if row != nil && row.HiddenAttr != nil && *row.HiddenAttr && !hw.opts.IncludeHidden {
+			return 0
+		}
+		return int(math.Round(s.rowHeight(row) * 96 / 72))
+	}
+	for _, img := range dr.Images() {
+		data, mime, ok := imageDataURI(img.Image)
+		if !ok {
+			continue
+		}
+		var from, to *CellMarker
+		var w, h int
+		switch a := img.Anchor.(type) {
+		case TwoCellAnchor:
+			tl, br := a.TopLeft(), a.BottomRight()
+			from, to = &tl, &br
+		case OneCellAnchor:
+			tl := a.TopLeft()
+			from = &tl
+			if a._cedd.Ext != nil {
+				w, h = int(a._cedd.Ext.CxAttr/9525), int(a._cedd.Ext.CyAttr/9525)
+			}
+		default:
+			continue
+		}
+		if from.X() == nil {
+			continue
+		}
+		x, y := markerOffset(from.X().ColOff.ST_CoordinateUnqualified), markerOffset(from.X().RowOff.ST_CoordinateUnqualified)
+		if to != nil && to.X() != nil {
+			w, h = -x, -y
+			for c := from.Col(); c < to.Col(); c++ {
+				w += colPx(uint32(c))
+			}
+			for r := from.Row(); r < to.Row(); r++ {
+				h += rowPx(uint32(r + 1))
+			}
+			w += markerOffset(to.X().ColOff.ST_CoordinateUnqualified)
+			h += markerOffset(to.X().RowOff.ST_CoordinateUnqualified)
+		}
+		if w <= 0 || h <= 0 {
+			size := img.Image.Size()
+			w, h = size.X, size.Y
+		}
+		ref := reference.IndexToColumn(uint32(from.Col())) + strconv.Itoa(int(from.Row())+1)
+		out[ref] = append(out[ref], fmt.Sprintf("<img style=\"position:absolute;left:%dpx;top:%dpx;width:%dpx;height:%dpx;z-index:1\" src=\"data:%s;base64,%s\">", x, y, w, h, mime, data))
+	}
+	return out
+}
+
+// markerOffset converts an anchor offset in EMUs to pixels.
+func markerOffset(emu *int64) int {
+	if emu == nil {
+		return 0
+	}
+	return int(*emu / 9525)
+}
+
+// imageDataURI returns the base64 encoded data and MIME type of an image.
+func imageDataURI(img common.ImageRef) (string, string, bool) {
+	var data []byte
+	switch {
+	case img.Data() != nil:
+		data = *img.Data()
+	case img.Path() != "":
+		f, err := tempstorage.Open(img.Path())
+		if err != nil {
+			return "", "", false
+		}
+		defer f.Close()
+		if data, err = io.ReadAll(f); err != nil {
+			return "", "", false
+		}
+	default:
+		return "", "", false
+	}
+	format := strings.ToLower(img.Format())
+	switch format {
+	case "jpg":
+		format = "jpeg"
+	case "svg":
+		format = "svg+xml"
+	case "tif":
+		format = "tiff"
+	}
+	return base64.StdEncoding.EncodeToString(data), "image/" + format, true
+}
@@ -0,0 +1,186 @@
+package spreadsheet
+
+import (
+	"bytes"
+	"image"
+	"strings"
+	"testing"
+
+	"github.com/yaklabco/unioffice/v2"
+	"github.com/yaklabco/unioffice/v2/color"
+	"github.com/yaklabco/unioffice/v2/common"
+	"github.com/yaklabco/unioffice/v2/measurement"
+	"github.com/yaklabco/unioffice/v2/schema/soo/sml"
+)
+
+func TestSheetWriteHTML(t *testing.T) {
+	wb := New()
+	sheet := wb.AddSheet()
+	sheet.Cell("A1").SetString("Title <b>")
+	sheet.AddMergedCells("A1", "C1")
+	sheet.Cell("A2").SetNumber(1234.5)
+	sheet.Cell("B2").SetNumber(5)
+	sheet.Cell("C2").SetBool(true)
+	sheet.Column(2).SetWidth(2 * measurement.Inch)
+	sheet.Row(3).SetHidden(true)
+	sheet.Cell("A3").SetString("hidden")
+
+	style := wb.StyleSheet.AddCellStyle()
+	style.SetNumberFormat("#,##0.00")
+	font := wb.StyleSheet.AddFont()
+	font.SetBold(true)
+	font.SetColor(color.Red)
+	style.SetFont(font)
+	fill := wb.StyleSheet.Fills().AddFill()
+	pf := fill.SetPatternFill()
+	pf.SetPattern(sml.ST_PatternTypeSolid)
+	pf.SetFgColor(color.Yellow)
+	style.SetFill(fill)
+	sheet.Cell("A2").SetStyle(style)
+
+	cf := sheet.AddConditionalFormatting([]string{"B2"})
+	rule := cf.AddRule()
+	rule.SetConditionValue("3")
+	rule.SetOperator(sml.ST_ConditionalFormattingOperatorGreaterThan)
+	rule.SetType(sml.ST_CfTypeCellIs)
+	dxf := wb.StyleSheet.AddDifferentialStyle()
+	dxf.X().Font = font.X()
+	rule.SetStyle(dxf)
+
+	dr := wb.AddDrawing()
+	sheet.SetDrawing(dr)
+	data := []byte("png")
+	img, _ := wb.AddImage(common.Image{Data: &data, Format: "png", Size: image.Point{X: 10, Y: 20}})
+	dr.AddImage(img, AnchorTypeOneCell).MoveTo(1, 1)
+
+	buf := bytes.Buffer{}
+	if err := sheet.WriteHTML(&buf, HTMLOptions{}); err != nil {
+		t.Fatalf("error writing html: %s", err)
+	}
+	out := buf.String()
+	for _, exp := range []string{
+		"<!DOCTYPE html>",
+		`colspan="3"`,
+		"Title &lt;b&gt;",
+		"1,234.50",
+		"TRUE",
+		"font-weight:bold;color:#FF0000;",
+		"background-color:#FFFF00;",
+		`<col style="width:144px">`,
+		"data:image/png;base64,cG5n",
+	} {
+		if !strings.Contains(out, exp) {
+			t.Errorf("expected output to contain %q", exp)
+		}
+	}
+	if strings.Contains(out, ">hidden<") {
+		t.Error("expected hidden row to be skipped")
+	}
+	if !strings.Contains(out, `<td class="n x0" style="font-weight:bold;color:#FF0000;position:relative;overflow:visible;">`) {
+		t.Errorf("expected conditional format and image on B2, got %s", out)
+
+	}
+
+	buf.Reset()
+	second := wb.AddSheet()
+	second.Cell("A1").SetString("second")
+	if err := wb.WriteHTML(&buf, HTMLOptions{Fragment: true}); err != nil {
+		t.Fatalf("error writing html: %s", err)
+	}
+	out = buf.String()
+	if strings.Contains(out, "<html>") || strings.Count(out, "<table") != 2 || !strings.Contains(out, `<label for="wb-tab-1">Sheet 2</label>`) {
+		t.Errorf("unexpected workbook output %s", out)
+	}
+}
+
+func TestWorkbookWriteHTMLHidden(t *testing.T) {
+	wb := New()
+	visible := wb.AddSheet()
+	visible.Cell("A1").SetString("visible")
+	hidden := wb.AddSheet()
+	hidden.Cell("A1").SetString("secret")
+	if err := hidden.SetState(sml.ST_SheetStateHidden); err != nil {
+		t.Fatalf("error hiding sheet: %s", err)
+	}
+
+	buf := bytes.Buffer{}
+	if err := wb.WriteHTML(&buf, HTMLOptions{Fragment: true}); err != nil {
+		t.Fatalf("error writing html: %s", err)
+	}
+	if strings.Contains(buf.String(), "secret") {
+		t.Error("expected the hidden sheet to be skipped")
+	}
+	buf.Reset()
+	if err := wb.WriteHTML(&buf, HTMLOptions{Fragment: true, IncludeHidden: true}); err != nil {
+		t.Fatalf("error writing html: %s", err)
+	}
+	if out := buf.String(); strings.Count(out, "<table") != 2 || !strings.Contains(out, "secret") {
+		t.Errorf("expected the hidden sheet to be included, got %s", out)
+	}
+}
+
+func TestSheetWriteHTMLColumnRules(t *testing.T) {
+	wb := New()
+	sheet := wb.AddSheet()
+	for i, v := range []float64{1, 2, 3} {
+		sheet.Cell("A" + string(rune('1'+i))).SetNumber(v)
+	}
+	sheet.Cell("A50").SetNumber(100)
+	sheet.Cell("B1").SetString("x")
+	sheet.Cell("B2").SetString("y")
+	sheet.Cell("B60").SetString("x")
+
+	font := wb.StyleSheet.AddFont()
+	font.SetColor(color.Red)
+	dxf := wb.StyleSheet.AddDifferentialStyle()
+	dxf.X().Font = font.X()
+	top := sheet.AddConditionalFormatting([]string{"A1:A1048576"}).AddRule()
+	top.SetType(sml.ST_CfTypeTop10)
+	top.X().RankAttr = unioffice.Uint32(1)
+	top.SetStyle(dxf)
+	dup := sheet.AddConditionalFormatting([]string{"B1:B1048576"}).AddRule()
+	dup.SetType(sml.ST_CfTypeDuplicateValues)
+	dup.SetStyle(dxf)
+
+	buf := bytes.Buffer{}
+	if err := sheet.WriteHTML(&buf, HTMLOptions{Fragment: true, Range: "A1:B3"}); err != nil {
+		t.Fatalf("error writing html: %s", err)
+	}
+	// only B1 matches, the top value and the other duplicate are outside of
+	// the exported range
+	if out := buf.String(); strings.Count(out, "color:#FF0000;") != 1 || !strings.Contains(out, `color:#FF0000;">x<`) {
+		t.Errorf("expected only B1 to be formatted, got %s", out)
+	}
+}
+
+func TestHTMLFontName(t *testing.T) {
+	wb := New()
+	sheet := wb.AddSheet()
+	font := wb.StyleSheet.AddFont()
+	font.SetName(`Evil'}</style><script>alert(1)</script>"`)
+	style := wb.StyleSheet.AddCellStyle()
+	style.SetFont(font)
+	sheet.Cell("A1").SetString("x")
+	sheet.Cell("A1").SetStyle(style)
+
+	buf := bytes.Buffer{}
+	if err := sheet.WriteHTML(&buf, HTMLOptions{}); err != nil {
+		t.Fatalf("error writing html: %s", err)
+	}
+	out := buf.String()
+	if strings.Contains(out, "<script>") || strings.Contains(out, "</style><") {
+		t.Errorf("expected the font name to be escaped, got %s", out)
+	}
+	if exp := `font-family:'Evil\27 \7d \3c \2f style\3e \3c script\3e alert\28 1\29 \3c \2f script\3e \22 ',sans-serif;`; !strings.Contains(out, exp) {
+		t.Errorf("expected output to contain %s, got %s", exp, out)
+	}
+}
+
+func TestResolveColor(t *testing.T) {
+	wb := New()
+	tint := -0.5
+	idx := uint32(2)
+	if c, ok := wb.resolveColor(&sml.CT_Color{IndexedAttr: &idx, TintAttr: &tint}); !ok || c != "800000" {
+		t.Errorf("expected 800000, got %s", c)
+	}
+}
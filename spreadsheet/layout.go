@@ -0,0 +1,413 @@
+package spreadsheet
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/yaklabco/unioffice/v2/chart"
+	"github.com/yaklabco/unioffice/v2/common"
+	"github.com/yaklabco/unioffice/v2/schema/soo/dml"
+	"github.com/yaklabco/unioffice/v2/schema/soo/sml"
+	"github.com/yaklabco/unioffice/v2/spreadsheet/reference"
+)
+
+// RangeLayout is the position of the cells, images and charts of a range of
+// cells in pixels at 96 DPI along with the effective formatting of each cell,
+// for drawing the range the way Excel displays it. Hidden rows and columns
+// are left out.
+type RangeLayout struct {
+	// Bounds is the area of the range, its top left corner is at 0,0.
+	Bounds image.Rectangle
+	// Cells are the visible cells of the range row by row, including empty
+	// ones. Cells covered by a merged cell are left out.
+	Cells  []LayoutCell
+	Images []LayoutImage
+	Charts []LayoutChart
+}
+
+// LayoutCell is the position and effective formatting of a cell, combining
+// the cell format with the conditional formats that apply to it.
+type LayoutCell struct {
+	Reference string
+	// Bounds is the area of the cell, or of the whole merged cell.
+	Bounds image.Rectangle
+	// Merged is set if the cell is the top left cell of merged cells.
+	Merged bool
+	// Runs are the runs of text the cell displays, values other than rich text
+	// are a single run of the formatted value.
+	Runs []TextRun
+	// Fill is the RGB value of the background color, it's empty for cells
+	// without a fill.
+	Fill string
+	// DataBar is the length of a data bar as a fraction of the cell width,
+	// drawn in DataBarColor.
+	DataBar                  float64
+	DataBarColor             string
+	Top, Right, Bottom, Left LayoutBorder
+	// HorizontalAlignment is the alignment of the text, general alignment is
+	// resolved to left, center or right by the type of the value.
+	HorizontalAlignment sml.ST_HorizontalAlignment
+	// VerticalAlignment is the vertical alignment of the text, it defaults to
+	// bottom.
+	VerticalAlignment sml.ST_VerticalAlignment
+	Wrap              bool
+	// Indent is the left indent of the text in pixels.
+	Indent int
+}
+
+// LayoutBorder is a border line of a cell, its style is unset if the side has
+// no border.
+type LayoutBorder struct {
+	Style sml.ST_BorderStyle
+	// Color is the RGB value of the line color.
+	Color string
+}
+
+// LayoutImage is an image of the sheet drawing overlapping the range.
+type LayoutImage struct {
+	Bounds image.Rectangle
+	Image  common.ImageRef
+}
+
+// LayoutChart is a chart of the sheet drawing overlapping the range.
+type LayoutChart struct {
+	Bounds image.Rectangle
+	Chart  chart.Chart
+}
+
+// LayoutRange returns the layout of a range of cells, e.g. "A1:H30".
+func (s *Sheet) LayoutRange(ref string) (RangeLayout, error) {
+	from, to, err := reference.ParseRangeReference(ref)
+	if err != nil {
+		return RangeLayout{}, fmt.Errorf("invalid range: %w", err)
+	}
+	c1, r1, c2, r2 := from.ColumnIdx, from.RowIdx, to.ColumnIdx, to.RowIdx
+
+	rows := map[uint32]*sml.CT_Row{}
+	cells := map[string]*sml.CT_Cell{}
+	for _, r := range s.X().SheetData.Row {
+		if r.RAttr == nil {
+			continue
+		}
+		rows[*r.RAttr] = r
+		for _, c := range r.C {
+			if c.RAttr != nil {
+				cells[strings.ReplaceAll(*c.RAttr, "$", "")] = c
+			}
+		}
+	}
+	colWidth := func(c uint32) int {
+		px, hidden := s.columnPixels(c)
+		if hidden {
+			return 0
+		}
+		return px
+	}
+	rowHeight := func(r uint32) int {
+		row := rows[r]
+		if row != nil && row.HiddenAttr != nil && *row.HiddenAttr {
+			return 0
+		}
+		return int(math.Round(s.rowHeight(row) * 96 / 72))
+	}
+	// offsets of the zero based columns and one based rows relative to the
+	// top left corner of the range
+	colX := func(c uint32) int {
+		x := 0
+		for i := c; i < c1; i++ {
+			x -= colWidth(i)
+		}
+		for i := c1; i < c; i++ {
+			x += colWidth(i)
+		}
+		return x
+	}
+	rowY := func(r uint32) int {
+		y := 0
+		for i := r; i < r1; i++ {
+			y -= rowHeight(i)
+		}
+		for i := r1; i < r; i++ {
+			y += rowHeight(i)
+		}
+		return y
+	}
+
+	lay := RangeLayout{Bounds: image.Rect(0, 0, colX(c2+1), rowY(r2+1))}
+	ss := s._fgeg.StyleSheet.X()
+	xfOf := func(r, c uint32) *sml.CT_Xf {
+		ref := reference.IndexToColumn(c) + strconv.Itoa(int(r))
+		id := s.styleIndex(rows[r], cells[ref], c)
+		if ss.CellXfs == nil || int(id) >= len(ss.CellXfs.Xf) {
+			return nil
+		}
+		return ss.CellXfs.Xf[id]
+	}
+
+	// merged cells keep the content of their top left cell
+	type merge struct{ c2, r2 uint32 }
+	merges := map[string]merge{}
+	covered := map[string]bool{}
+	// the borders of merged cells may come from cells past the range
+	cfCol, cfRow := c2, r2
+	for _, m := range s.MergedCells() {
+		mf, mt, err := reference.ParseRangeReference(m.Reference())
+		if err != nil || mt.ColumnIdx < c1 || mf.ColumnIdx > c2 || mt.RowIdx < r1 || mf.RowIdx > r2 {
+			continue
+		}
+		fc, fr := maxUint32(mf.ColumnIdx, c1), maxUint32(mf.RowIdx, r1)
+		for r := fr; r <= minUint32(mt.RowIdx, r2); r++ {
+			for c := fc; c <= minUint32(mt.ColumnIdx, c2); c++ {
+				covered[reference.IndexToColumn(c)+strconv.Itoa(int(r))] = true
+			}
+		}
+		top := reference.IndexToColumn(fc) + strconv.Itoa(int(fr))
+		delete(covered, top)
+		merges[top] = merge{mt.ColumnIdx, mt.RowIdx}
+		cfCol, cfRow = maxUint32(cfCol, mt.ColumnIdx), maxUint32(cfRow, mt.RowIdx)
+	}
+
+	cf := s.conditionalStyles(c1, r1, cfCol, cfRow)
+	y := 0
+	for r := r1; r <= r2; r++ {
+		h := rowHeight(r)
+		if h == 0 {
+			continue
+		}
+		x := 0
+		for c := c1; c <= c2; c++ {
+			w := colWidth(c)
+			if w == 0 {
+				continue
+			}
+			ref := reference.IndexToColumn(c) + strconv.Itoa(int(r))
+			if covered[ref] {
+				x += w
+				continue
+			}
+			lc := LayoutCell{Reference: ref, Bounds: image.Rect(x, y, x+w, y+h)}
+			lastCol, lastRow := c, r
+			if m, ok := merges[ref]; ok {
+				lastCol, lastRow = m.c2, m.r2
+				lc.Bounds.Max = image.Pt(colX(m.c2+1), rowY(m.r2+1))
+				lc.Bounds = lc.Bounds.Intersect(lay.Bounds)
+				lc.Merged = true
+			}
+			s.layoutCell(&lc, rows[r], cells[ref], xfOf(r, c), cf[ref])
+			// the borders of a merged cell are those of the cells along its edges
+			if lastCol != c {
+				if xf := xfOf(r, lastCol); xf != nil {
+					lc.Right = s.layoutBorders(xf, cf[reference.IndexToColumn(lastCol)+strconv.Itoa(int(r))])[1]
+				}
+			}
+			if lastRow != r {
+				if xf := xfOf(lastRow, c); xf != nil {
+					lc.Bottom = s.layoutBorders(xf, cf[reference.IndexToColumn(c)+strconv.Itoa(int(lastRow))])[2]
+				}
+			}
+			lay.Cells = append(lay.Cells, lc)
+			x += w
+		}
+		y += h
+	}
+
+	dr, ok := s.Drawing()
+	if !ok {
+		return lay, nil
+	}
+	markerPt := func(m CellMarker) image.Point {
+		return image.Pt(colX(uint32(m.Col()))+markerOffset(m.X().ColOff.ST_CoordinateUnqualified),
+			rowY(uint32(m.Row())+1)+markerOffset(m.X().RowOff.ST_CoordinateUnqualified))
+	}
+	// bounds returns the area of an anchor, objects without a size are
+	// given the default size
+	bounds := func(a Anchor, size image.Point) (image.Rectangle, bool) {
+		var rect image.Rectangle
+		switch a := a.(type) {
+		case TwoCellAnchor:
+			if a._gegg.From == nil || a._gegg.To == nil {
+				return rect, false
+			}
+			rect = image.Rectangle{markerPt(a.TopLeft()), markerPt(a.BottomRight())}
+		case OneCellAnchor:
+			if a._cedd.From == nil {
+				return rect, false
+			}
+			min := markerPt(a.TopLeft())
+			rect = image.Rectangle{min, min.Add(extentPixels(a._cedd.Ext))}
+		case AbsoluteAnchor:
+			if a._be.Pos == nil || a._be.Ext == nil {
+				return rect, false
+			}
+			min := image.Pt(colX(0)+markerOffset(a._be.Pos.XAttr.ST_CoordinateUnqualified),
+				rowY(1)+markerOffset(a._be.Pos.YAttr.ST_CoordinateUnqualified))
+			rect = image.Rectangle{min, min.Add(extentPixels(a._be.Ext))}
+		default:
+			return rect, false
+		}
+		if rect.Empty() {
+			rect.Max = rect.Min.Add(size)
+		}
+		return rect, !rect.Empty() && rect.Overlaps(lay.Bounds)
+	}
+	for _, img := range dr.Images() {
+		if rect, ok := bounds(img.Anchor, img.Image.Size()); ok {
+			lay.Images = append(lay.Images, LayoutImage{rect, img.Image})
+		}
+	}
+	for _, ch := range dr.Charts() {
+		if rect, ok := bounds(ch.Anchor, image.Point{}); ok {
+			lay.Charts = append(lay.Charts, LayoutChart{rect, ch.Chart})
+		}
+	}
+	return lay, nil
+}
+
+// extentPixels converts a drawing extent in EMUs to pixels.
+func extentPixels(ext *dml.CT_PositiveSize2D) image.Point {
+	if ext == nil {
+		return image.Point{}
+	}
+	return image.Pt(int(ext.CxAttr/9525), int(ext.CyAttr/9525))
+}
+
+// layoutCell sets the content and formatting of a cell.
+func (s *Sheet) layoutCell(lc *LayoutCell, row *sml.CT_Row, x *sml.CT_Cell, xf *sml.CT_Xf, cf *cfStyle) {
+	wb := s._fgeg
+	ss := wb.StyleSheet.X()
+	var font *sml.CT_Font
+	if ss.Fonts != nil && len(ss.Fonts.Font) > 0 {
+		font = ss.Fonts.Font[0]
+		if xf != nil && xf.FontIdAttr != nil && int(*xf.FontIdAttr) < len(ss.Fonts.Font) {
+			font = ss.Fonts.Font[*xf.FontIdAttr]
+		}
+	}
+	base := wb.fontFormat(font)
+
+	lc.HorizontalAlignment = sml.ST_HorizontalAlignmentLeft
+	if x != nil {
+		cell := Cell{wb, s, row, x}
+		if runs, err := cell.GetRichText(); err == nil && len(runs) > 0 {
+			lc.Runs = runs
+		} else if v := cell.GetFormattedValue(); v != "" {
+			lc.Runs = []TextRun{{Text: v, RunFormat: base}}
+		}
+		switch x.TAttr {
+		case sml.ST_CellTypeB, sml.ST_CellTypeE:
+			lc.HorizontalAlignment = sml.ST_HorizontalAlignmentCenter
+		case sml.ST_CellTypeN, sml.ST_CellTypeUnset:
+			if x.V != nil && *x.V != "" {
+				lc.HorizontalAlignment = sml.ST_HorizontalAlignmentRight
+			}
+		}
+	}
+	lc.VerticalAlignment = sml.ST_VerticalAlignmentBottom
+	if xf != nil {
+		if xf.FillIdAttr != nil && ss.Fills != nil && int(*xf.FillIdAttr) < len(ss.Fills.Fill) {
+			lc.Fill = wb.fillColor(ss.Fills.Fill[*xf.FillIdAttr])
+		}
+		if a := xf.Alignment; a != nil {
+			switch a.HorizontalAttr {
+			case sml.ST_HorizontalAlignmentUnset, sml.ST_HorizontalAlignmentGeneral:
+			case sml.ST_HorizontalAlignmentCenterContinuous:
+				lc.HorizontalAlignment = sml.ST_HorizontalAlignmentCenter
+			default:
+				lc.HorizontalAlignment = a.HorizontalAttr
+			}
+			if a.VerticalAttr != sml.ST_VerticalAlignmentUnset {
+				lc.VerticalAlignment = a.VerticalAttr
+			}
+			lc.Wrap = a.WrapTextAttr != nil && *a.WrapTextAttr
+			if a.IndentAttr != nil {
+				lc.Indent = int(*a.IndentAttr) * 9
+			}
+		}
+	}
+	borders := s.layoutBorders(xf, cf)
+	lc.Top, lc.Right, lc.Bottom, lc.Left = borders[0], borders[1], borders[2], borders[3]
+	if cf == nil {
+		return
+	}
+
+	if cf.background != "" {
+		lc.Fill = cf.background
+	}
+	// apply the lowest priority first so higher priorities override it
+	for i := len(cf.dxfs) - 1; i >= 0; i-- {
+		dxf := cf.dxfs[i]
+		for j := range lc.Runs {
+			lc.Runs[j].RunFormat = wb.applyFont(lc.Runs[j].RunFormat, dxf.Font)
+		}
+		if dxf.Fill != nil && dxf.Fill.FillChoice != nil && dxf.Fill.FillChoice.PatternFill != nil {
+			// differential fills use the background color for solid fills
+			pf := dxf.Fill.FillChoice.PatternFill
+			if c, ok := wb.resolveColor(pf.BgColor); ok {
+				lc.Fill = c
+			} else if c, ok := wb.resolveColor(pf.FgColor); ok {
+				lc.Fill = c
+			}
+		}
+	}
+	lc.DataBar, lc.DataBarColor = cf.bar, cf.barColor
+}
+
+// fillColor returns the RGB value of the color of a fill, or the first color
+// of a gradient fill.
+func (wb *Workbook) fillColor(f *sml.CT_Fill) string {
+	if f == nil || f.FillChoice == nil {
+		return ""
+	}
+	if pf := f.FillChoice.PatternFill; pf != nil {
+		if pf.PatternTypeAttr == sml.ST_PatternTypeUnset || pf.PatternTypeAttr == sml.ST_PatternTypeNone {
+			return ""
+		}
+		if c, ok := wb.resolveColor(pf.FgColor); ok {
+			return c
+		}
+		c, _ := wb.resolveColor(pf.BgColor)
+		return c
+	}
+	if gf := f.FillChoice.GradientFill; gf != nil && len(gf.Stop) > 0 {
+		c, _ := wb.resolveColor(gf.Stop[0].Color)
+		return c
+	}
+	return ""
+}
+
+// layoutBorders returns the top, right, bottom and left borders of a cell
+// format with the borders of conditional formats applied.
+func (s *Sheet) layoutBorders(xf *sml.CT_Xf, cf *cfStyle) [4]LayoutBorder {
+	ret := [4]LayoutBorder{}
+	apply := func(b *sml.CT_Border) {
+		if b == nil {
+			return
+		}
+		for i, pr := range []*sml.CT_BorderPr{b.Top, b.Right, b.Bottom, b.Left} {
+			if pr == nil || pr.StyleAttr == sml.ST_BorderStyleUnset {
+				continue
+			}
+			if pr.StyleAttr == sml.ST_BorderStyleNone {
+				ret[i] = LayoutBorder{}
+				continue
+			}
+			color, ok := s._fgeg.resolveColor(pr.Color)
+			if !ok {
+				color = "000000"
+			}
+			ret[i] = LayoutBorder{pr.StyleAttr, color}
+		}
+	}
+	ss := s._fgeg.StyleSheet.X()
+	if xf != nil && xf.BorderIdAttr != nil && ss.Borders != nil && int(*xf.BorderIdAttr) < len(ss.Borders.Border) {
+		apply(ss.Borders.Border[*xf.BorderIdAttr])
+	}
+	if cf != nil {
+		for i := len(cf.dxfs) - 1; i >= 0; i-- {
+			apply(cf.dxfs[i].Border)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,112 @@
+package spreadsheet
+
+import (
+	"image"
+	"testing"
+
+	"github.com/yaklabco/unioffice/v2/color"
+	"github.com/yaklabco/unioffice/v2/common"
+	"github.com/yaklabco/unioffice/v2/measurement"
+	"github.com/yaklabco/unioffice/v2/schema/soo/sml"
+)
+
+func TestLayoutRange(t *testing.T) {
+	wb := New()
+	sheet := wb.AddSheet()
+	sheet.Cell("A1").SetString("Title")
+	sheet.AddMergedCells("A1", "B1")
+	sheet.Cell("A2").SetNumber(1234.5)
+	sheet.Cell("B2").SetNumber(5)
+	sheet.Cell("C2").SetBool(true)
+	sheet.Column(2).SetWidth(2 * measurement.Inch)
+	sheet.Row(3).SetHidden(true)
+	sheet.Row(4).SetHeight(30 * measurement.Point)
+
+	style := wb.StyleSheet.AddCellStyle()
+	style.SetNumberFormat("#,##0.00")
+	font := wb.StyleSheet.AddFont()
+	font.SetBold(true)
+	style.SetFont(font)
+	fill := wb.StyleSheet.Fills().AddFill()
+	pf := fill.SetPatternFill()
+	pf.SetPattern(sml.ST_PatternTypeSolid)
+	pf.SetFgColor(color.Yellow)
+	style.SetFill(fill)
+	border := wb.StyleSheet.AddBorder()
+	border.SetBottom(sml.ST_BorderStyleThin, color.Blue)
+	style.SetBorder(border)
+	sheet.Cell("A2").SetStyle(style)
+
+	cf := sheet.AddConditionalFormatting([]string{"B2"})
+	rule := cf.AddRule()
+	rule.SetConditionValue("3")
+	rule.SetOperator(sml.ST_ConditionalFormattingOperatorGreaterThan)
+	rule.SetType(sml.ST_CfTypeCellIs)
+	dxf := wb.StyleSheet.AddDifferentialStyle()
+	red := wb.StyleSheet.AddFont()
+	red.SetColor(color.Red)
+	dxf.X().Font = red.X()
+	rule.SetStyle(dxf)
+
+	dr := wb.AddDrawing()
+	sheet.SetDrawing(dr)
+	data := []byte("png")
+	img, _ := wb.AddImage(common.Image{Data: &data, Format: "png", Size: image.Point{X: 10, Y: 20}})
+	dr.AddImage(img, AnchorTypeOneCell).MoveTo(1, 1)
+
+	lay, err := sheet.LayoutRange("A1:C4")
+	if err != nil {
+		t.Fatalf("error laying out range: %s", err)
+	}
+	// 64 + 144 + 64 pixels wide, rows of 20, 20 and 40 pixels
+	if exp := image.Rect(0, 0, 272, 80); lay.Bounds != exp {
+		t.Errorf("expected bounds %v, got %v", exp, lay.Bounds)
+	}
+	// A1:B1 is merged and row 3 is hidden
+	if len(lay.Cells) != 8 {
+		t.Fatalf("expected 8 cells, got %d", len(lay.Cells))
+	}
+	a1 := lay.Cells[0]
+	if a1.Reference != "A1" || a1.Bounds != image.Rect(0, 0, 208, 20) || !a1.Merged {
+		t.Errorf("expected merged A1 to span two columns, got %s %v", a1.Reference, a1.Bounds)
+	}
+	if len(a1.Runs) != 1 || a1.Runs[0].Text != "Title" || a1.HorizontalAlignment != sml.ST_HorizontalAlignmentLeft {
+		t.Errorf("unexpected A1 layout: %+v", a1)
+	}
+
+	a2 := lay.Cells[2]
+	if a2.Reference != "A2" || a2.Runs[0].Text != "1,234.50" || !a2.Runs[0].Bold {
+		t.Errorf("unexpected A2 text: %+v", a2.Runs)
+	}
+	if a2.Fill != "FFFF00" || a2.HorizontalAlignment != sml.ST_HorizontalAlignmentRight ||
+		a2.VerticalAlignment != sml.ST_VerticalAlignmentBottom {
+		t.Errorf("unexpected A2 formatting: %+v", a2)
+	}
+	if a2.Bottom.Style != sml.ST_BorderStyleThin || a2.Bottom.Color != "0000FF" || a2.Top.Style != sml.ST_BorderStyleUnset {
+		t.Errorf("unexpected A2 borders: %+v %+v", a2.Top, a2.Bottom)
+	}
+	if b2 := lay.Cells[3]; b2.Bounds != image.Rect(64, 20, 208, 40) || b2.Runs[0].Color != "FF0000" {
+		t.Errorf("expected conditional format on B2, got %+v", b2)
+	}
+	if c2 := lay.Cells[4]; c2.Runs[0].Text != "TRUE" || c2.HorizontalAlignment != sml.ST_HorizontalAlignmentCenter {
+		t.Errorf("unexpected C2 layout: %+v", c2)
+	}
+	if a4 := lay.Cells[5]; a4.Reference != "A4" || a4.Bounds != image.Rect(0, 40, 64, 80) || len(a4.Runs) != 0 {
+		t.Errorf("unexpected A4 layout: %+v", a4)
+	}
+
+	if len(lay.Images) != 1 || lay.Images[0].Bounds.Min != image.Pt(64, 20) {
+		t.Fatalf("expected image anchored at B2, got %+v", lay.Images)
+	}
+
+	lay, err = sheet.LayoutRange("B2:C2")
+	if err != nil {
+		t.Fatalf("error laying out range: %s", err)
+	}
+	if len(lay.Cells) != 2 || lay.Cells[0].Bounds.Min != image.Pt(0, 0) {
+		t.Errorf("expected range to start at B2, got %+v", lay.Cells)
+	}
+	if _, err := sheet.LayoutRange("foo"); err == nil {
+		t.Error("expected an error for an invalid range")
+	}
+}
@@ -0,0 +1,72 @@
+package spreadsheet
+
+import (
+	"strconv"
+
+	"github.com/yaklabco/unioffice/v2/schema/soo/sml"
+	"github.com/yaklabco/unioffice/v2/spreadsheet/format"
+)
+
+// SetLocale sets the regional settings used by Cell.GetFormattedValue, which
+// affect the decimal and grouping separators, month and day names, the system
+// date and time formats and the built in currency formats.
+func (wb *Workbook) SetLocale(l format.Locale) { wb._locale = &l }
+
+// Locale returns the regional settings used to format cell values, en-US
+// unless set with SetLocale.
+func (wb *Workbook) Locale() format.Locale {
+	if wb._locale == nil {
+		return format.DefaultLocale
+	}
+	return *wb._locale
+}
+
+// localeFormattedValue returns the formatted value of the cell if the workbook
+// has a locale set.
+func (c Cell) localeFormattedValue() (string, bool) {
+	if c._bgg == nil || c._bgg._locale == nil {
+		return "", false
+	}
+	l := *c._bgg._locale
+	f := c.getFormat()
+	if c._dga.SAttr != nil {
+		cs := c._bgg.StyleSheet.GetCellStyle(*c._dga.SAttr)
+		if cs._faf != nil {
+			if code, ok := l.BuiltinFormat(cs.NumberFormat()); ok {
+				f = code
+			}
+		}
+	}
+	switch c._dga.TAttr {
+	case sml.ST_CellTypeB:
+		if b, _ := c.GetValueAsBool(); b {
+			return l.True, true
+		}
+		return l.False, true
+	case sml.ST_CellTypeE:
+		if c._dga.V != nil {
+			return *c._dga.V, true
+		}
+		return "", true
+	case sml.ST_CellTypeS, sml.ST_CellTypeInlineStr:
+		return l.String(c.GetString(), f), true
+	case sml.ST_CellTypeStr:
+		s := c.GetString()
+		if format.IsNumber(s) {
+			v, _ := strconv.ParseFloat(s, 64)
+			return l.Number(v, f), true
+		}
+		return l.String(s, f), true
+	}
+	raw, _ := c.GetRawValue()
+	if raw == "" {
+		return "", true
+	}
+	if v, err := c.GetValueAsNumber(); err == nil {
+		if c._bgg.Uses1904Dates() {
+			return l.Number1904(v, f), true
+		}
+		return l.Number(v, f), true
+	}
+	return l.String(raw, f), true
+}
@@ -0,0 +1,31 @@
+package spreadsheet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yaklabco/unioffice/v2/spreadsheet/format"
+)
+
+func TestWorkbookLocale(t *testing.T) {
+	wb := New()
+	sheet := wb.AddSheet()
+	num := sheet.Cell("A1")
+	num.SetNumber(1234.5)
+	num.SetStyle(wb.StyleSheet.GetOrCreateStandardNumberFormat(StandardFormat4))
+	date := sheet.Cell("A2")
+	date.SetDateWithStyle(time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC))
+	sheet.Cell("A3").SetBool(true)
+
+	de, _ := format.LookupLocale("de-DE")
+	wb.SetLocale(de)
+	if got := num.GetFormattedValue(); got != "1.234,50" {
+		t.Errorf("expected 1.234,50, got %s", got)
+	}
+	if got := date.GetFormattedValue(); got != "05.03.2024" {
+		t.Errorf("expected the German short date, got %s", got)
+	}
+	if got := sheet.Cell("A3").GetFormattedValue(); got != "WAHR" {
+		t.Errorf("expected WAHR, got %s", got)
+	}
+}
@@ -0,0 +1,370 @@
+package spreadsheet
+
+import (
+	"strings"
+
+	unioffice "github.com/yaklabco/unioffice/v2"
+	"github.com/yaklabco/unioffice/v2/color"
+	"github.com/yaklabco/unioffice/v2/schema/soo/sml"
+	"github.com/yaklabco/unioffice/v2/spreadsheet/format"
+)
+
+// BuiltinCellStyle is one of the named cell styles that Excel predefines and
+// lists in its cell styles gallery.
+type BuiltinCellStyle byte
+
+// BuiltinCellStyle constants.
+const (
+	BuiltinCellStyleNormal BuiltinCellStyle = iota
+	BuiltinCellStyleComma
+	BuiltinCellStyleCurrency
+	BuiltinCellStylePercent
+	BuiltinCellStyleComma0
+	BuiltinCellStyleCurrency0
+	BuiltinCellStyleNote
+	BuiltinCellStyleWarningText
+	BuiltinCellStyleTitle
+	BuiltinCellStyleHeading1
+	BuiltinCellStyleHeading2
+	BuiltinCellStyleHeading3
+	BuiltinCellStyleHeading4
+	BuiltinCellStyleInput
+	BuiltinCellStyleOutput
+	BuiltinCellStyleCalculation
+	BuiltinCellStyleCheckCell
+	BuiltinCellStyleLinkedCell
+	BuiltinCellStyleTotal
+	BuiltinCellStyleGood
+	BuiltinCellStyleBad
+	BuiltinCellStyleNeutral
+	BuiltinCellStyleExplanatoryText
+)
+
+// builtinCellStyle describes the name, the builtinId and the formatting that
+// Excel uses for a built in cell style with the default Office theme.
+type builtinCellStyle struct {
+	name   string
+	id     uint32
+	numFmt StandardFormat
+	font   func(f Font)
+	fill   string
+	border func(b Border)
+}
+
+func styleColor(s string) func(f Font) {
+	return func(f Font) { f.SetColor(color.FromHex(s)) }
+}
+
+func boldStyleColor(s string) func(f Font) {
+	return func(f Font) { f.SetBold(true); f.SetColor(color.FromHex(s)) }
+}
+
+func headingFont(size float64) func(f Font) {
+	return func(f Font) {
+		f.SetBold(true)
+		f.SetSize(size)
+		f.SetColor(color.FromHex("44546A"))
+	}
+}
+
+func bottomBorder(style sml.ST_BorderStyle, c string) func(b Border) {
+	return func(b Border) { b.SetBottom(style, color.FromHex(c)) }
+}
+
+func boxBorder(style sml.ST_BorderStyle, c string) func(b Border) {
+	return func(b Border) {
+		b.SetLeft(style, color.FromHex(c))
+		b.SetRight(style, color.FromHex(c))
+		b.SetTop(style, color.FromHex(c))
+		b.SetBottom(style, color.FromHex(c))
+	}
+}
+
+var builtinCellStyles = map[BuiltinCellStyle]builtinCellStyle{
+	BuiltinCellStyleNormal:    {name: "Normal", id: 0},
+	BuiltinCellStyleComma:     {name: "Comma", id: 3, numFmt: 43},
+	BuiltinCellStyleCurrency:  {name: "Currency", id: 4, numFmt: 44},
+	BuiltinCellStylePercent:   {name: "Percent", id: 5, numFmt: StandardFormatPercent},
+	BuiltinCellStyleComma0:    {name: "Comma [0]", id: 6, numFmt: 41},
+	BuiltinCellStyleCurrency0: {name: "Currency [0]", id: 7, numFmt: 42},
+	BuiltinCellStyleNote: {name: "Note", id: 10, fill: "FFFFCC",
+		border: boxBorder(sml.ST_BorderStyleThin, "B2B2B2")},
+	BuiltinCellStyleWarningText: {name: "Warning Text", id: 11, font: styleColor("FF0000")},
+	BuiltinCellStyleTitle: {name: "Title", id: 15, font: func(f Font) {
+		f.SetName("Calibri Light")
+		f.SetSize(18)
+		f.SetColor(color.FromHex("44546A"))
+	}},
+	BuiltinCellStyleHeading1: {name: "Heading 1", id: 16, font: headingFont(15),
+		border: bottomBorder(sml.ST_BorderStyleThick, "4472C4")},
+	BuiltinCellStyleHeading2: {name: "Heading 2", id: 17, font: headingFont(13),
+		border: bottomBorder(sml.ST_BorderStyleThick, "A2B8E1")},
+	BuiltinCellStyleHeading3: {name: "Heading 3", id: 18, font: headingFont(11),
+		border: bottomBorder(sml.ST_BorderStyleMedium, "8EA9DB")},
+	BuiltinCellStyleHeading4: {name: "Heading 4", id: 19, font: headingFont(11)},
+	BuiltinCellStyleInput: {name: "Input", id: 20, font: styleColor("3F3F76"), fill: "FFCC99",
+		border: boxBorder(sml.ST_BorderStyleThin, "7F7F7F")},
+	BuiltinCellStyleOutput: {name: "Output", id: 21, font: boldStyleColor("3F3F3F"), fill: "F2F2F2",
+		border: boxBorder(sml.ST_BorderStyleThin, "3F3F3F")},
+	BuiltinCellStyleCalculation: {name: "Calculation", id: 22, font: boldStyleColor("FA7D00"), fill: "F2F2F2",
+		border: boxBorder(sml.ST_BorderStyleThin, "7F7F7F")},
+	BuiltinCellStyleCheckCell: {name: "Check Cell", id: 23, font: boldStyleColor("FFFFFF"), fill: "A5A5A5",
+		border: boxBorder(sml.ST_BorderStyleDouble, "3F3F3F")},
+	BuiltinCellStyleLinkedCell: {name: "Linked Cell", id: 24, font: styleColor("FA7D00"),
+		border: bottomBorder(sml.ST_BorderStyleDouble, "FF8001")},
+	BuiltinCellStyleTotal: {name: "Total", id: 25, font: func(f Font) { f.SetBold(true) },
+		border: func(b Border) {
+			b.SetTop(sml.ST_BorderStyleThin, color.FromHex("4472C4"))
+			b.SetBottom(sml.ST_BorderStyleDouble, color.FromHex("4472C4"))
+		}},
+	BuiltinCellStyleGood:    {name: "Good", id: 26, font: styleColor("006100"), fill: "C6EFCE"},
+	BuiltinCellStyleBad:     {name: "Bad", id: 27, font: styleColor("9C0006"), fill: "FFC7CE"},
+	BuiltinCellStyleNeutral: {name: "Neutral", id: 28, font: styleColor("9C5700"), fill: "FFEB9C"},
+	BuiltinCellStyleExplanatoryText: {name: "Explanatory Text", id: 53, font: func(f Font) {
+		f.SetItalic(true)
+		f.SetColor(color.FromHex("7F7F7F"))
+	}},
+}
+
+// NamedCellStyle is a named style such as "Heading 1" or "Currency". Cell
+// styles can be based on a named style, see CellStyle.SetNamedStyle.
+type NamedCellStyle struct {
+	w *Workbook
+	x *sml.CT_CellStyle
+}
+
+// X returns the inner wrapped XML type.
+func (n NamedCellStyle) X() *sml.CT_CellStyle { return n.x }
+
+// Name returns the name of the style.
+func (n NamedCellStyle) Name() string {
+	if n.x == nil || n.x.NameAttr == nil {
+		return ""
+	}
+	return *n.x.NameAttr
+}
+
+// IsBuiltin returns true if the style is one of the styles predefined by Excel.
+func (n NamedCellStyle) IsBuiltin() bool { return n.x != nil && n.x.BuiltinIdAttr != nil }
+
+// Format returns the formatting of the named style. Changes made to it with
+// the CellStyle setters apply to the named style, but cells must not use it
+// directly, use CellStyle.SetNamedStyle instead.
+func (n NamedCellStyle) Format() CellStyle {
+	xfs := n.w.StyleSheet._gccd.CellStyleXfs
+	if n.x == nil || xfs == nil || int(n.x.XfIdAttr) >= len(xfs.Xf) {
+		return CellStyle{}
+	}
+	return CellStyle{n.w, xfs.Xf[n.x.XfIdAttr], &sml.CT_CellXfs{Xf: xfs.Xf}}
+}
+
+// NamedCellStyles returns the named cell styles of the workbook.
+func (s StyleSheet) NamedCellStyles() []NamedCellStyle {
+	ret := []NamedCellStyle{}
+	if s._gccd.CellStyles == nil {
+		return ret
+	}
+	for _, cs := range s._gccd.CellStyles.CellStyle {
+		ret = append(ret, NamedCellStyle{s._ddee, cs})
+	}
+	return ret
+}
+
+// GetNamedCellStyle returns the named cell style with the given name, compared
+// case insensitively as Excel does. If there is no such style, the returned
+// style has a nil X().
+func (s StyleSheet) GetNamedCellStyle(name string) NamedCellStyle {
+	for _, cs := range s.NamedCellStyles() {
+		if strings.EqualFold(cs.Name(), name) {
+			return cs
+		}
+	}
+	return NamedCellStyle{s._ddee, nil}
+}
+
+// AddNamedCellStyle adds a custom named cell style, initially formatted like
+// the Normal style. If a style with the name already exists it is returned
+// instead.
+func (s StyleSheet) AddNamedCellStyle(name string) NamedCellStyle {
+	if cs := s.GetNamedCellStyle(name); cs.X() != nil {
+		return cs
+	}
+	return s.addNamedCellStyle(name, nil)
+}
+
+// AddBuiltinCellStyle adds one of the cell styles predefined by Excel along
+// with its default formatting. If the style already exists it is returned
+// instead.
+func (s StyleSheet) AddBuiltinCellStyle(b BuiltinCellStyle) NamedCellStyle {
+	def, ok := builtinCellStyles[b]
+	if !ok {
+		return NamedCellStyle{s._ddee, nil}
+	}
+	for _, cs := range s.NamedCellStyles() {
+		if cs.x.BuiltinIdAttr != nil && *cs.x.BuiltinIdAttr == def.id {
+			return cs
+		}
+	}
+	cs := s.addNamedCellStyle(def.name, unioffice.Uint32(def.id))
+	f := cs.Format()
+	if def.numFmt != 0 {
+		f.SetNumberFormatStandard(def.numFmt)
+	}
+	if def.font != nil {
+		fnt := s.AddFont()
+		if len(s._gccd.Fonts.Font) > 0 {
+			fnt._fceef = copyFont(s._gccd.Fonts.Font[0])
+		}
+		def.font(fnt)
+		f.SetFont(fnt)
+	}
+	if def.fill != "" {
+		fill := s.Fills().AddFill()
+		pf := fill.SetPatternFill()
+		pf.SetPattern(sml.ST_PatternTypeSolid)
+		pf.SetFgColor(color.FromHex(def.fill))
+		f.SetFill(fill)
+	}
+	if def.border != nil {
+		brd := s.AddBorder()
+		brd.InitializeDefaults()
+		def.border(brd)
+		f.SetBorder(brd)
+	}
+	return cs
+}
+
+func (s StyleSheet) addNamedCellStyle(name string, builtinID *uint32) NamedCellStyle {
+	if s._gccd.CellStyleXfs == nil {
+		s._gccd.CellStyleXfs = sml.NewCT_CellStyleXfs()
+	}
+	if s._gccd.CellStyles == nil {
+		s._gccd.CellStyles = sml.NewCT_CellStyles()
+	}
+	xf := sml.NewCT_Xf()
+	xf.NumFmtIdAttr = unioffice.Uint32(0)
+	xf.FontIdAttr = unioffice.Uint32(0)
+	xf.FillIdAttr = unioffice.Uint32(0)
+	xf.BorderIdAttr = unioffice.Uint32(0)
+	xfs := s._gccd.CellStyleXfs
+	xfs.Xf = append(xfs.Xf, xf)
+	xfs.CountAttr = unioffice.Uint32(uint32(len(xfs.Xf)))
+
+	cs := sml.NewCT_CellStyle()
+	cs.NameAttr = unioffice.String(name)
+	cs.XfIdAttr = uint32(len(xfs.Xf) - 1)
+	cs.BuiltinIdAttr = builtinID
+	s._gccd.CellStyles.CellStyle = append(s._gccd.CellStyles.CellStyle, cs)
+	s._gccd.CellStyles.CountAttr = unioffice.Uint32(uint32(len(s._gccd.CellStyles.CellStyle)))
+	return NamedCellStyle{s._ddee, cs}
+}
+
+// copyFont returns a copy of f that can be modified without affecting f.
+func copyFont(f *sml.CT_Font) *sml.CT_Font {
+	ret := sml.NewCT_Font()
+	for _, fc := range f.FontChoice {
+		if fc == nil {
+			continue
+		}
+		c := *fc
+		ret.FontChoice = append(ret.FontChoice, &c)
+	}
+	return ret
+}
+
+// SetNamedStyle bases the cell style on a named style, copying the number
+// format, font, fill, border, alignment and protection of the named style.
+func (cs CellStyle) SetNamedStyle(n NamedCellStyle) {
+	f := n.Format()
+	if f._faf == nil {
+		return
+	}
+	cs._faf.XfIdAttr = unioffice.Uint32(n.x.XfIdAttr)
+	cs._faf.NumFmtIdAttr = copyUint32(f._faf.NumFmtIdAttr)
+	cs._faf.FontIdAttr = copyUint32(f._faf.FontIdAttr)
+	cs._faf.FillIdAttr = copyUint32(f._faf.FillIdAttr)
+	cs._faf.BorderIdAttr = copyUint32(f._faf.BorderIdAttr)
+	cs._faf.Alignment = nil
+	if f._faf.Alignment != nil {
+		a := *f._faf.Alignment
+		cs._faf.Alignment = &a
+	}
+	cs._faf.Protection = nil
+	if f._faf.Protection != nil {
+		p := *f._faf.Protection
+		cs._faf.Protection = &p
+	}
+	// the formatting is inherited from the named style, not applied by the
+	// cell format
+	cs._faf.ApplyNumberFormatAttr = nil
+	cs._faf.ApplyFontAttr = nil
+	cs._faf.ApplyFillAttr = nil
+	cs._faf.ApplyBorderAttr = nil
+	cs._faf.ApplyAlignmentAttr = nil
+	cs._faf.ApplyProtectionAttr = nil
+}
+
+// NamedStyle returns the named style that the cell style is based on, which is
+// the Normal style unless another one is set.
+func (cs CellStyle) NamedStyle() NamedCellStyle {
+	id := uint32(0)
+	if cs._faf != nil && cs._faf.XfIdAttr != nil {
+		id = *cs._faf.XfIdAttr
+	}
+	for _, n := range cs._bba.StyleSheet.NamedCellStyles() {
+		if n.x.XfIdAttr == id {
+			return n
+		}
+	}
+	return NamedCellStyle{cs._bba, nil}
+}
+
+func copyUint32(v *uint32) *uint32 {
+	if v == nil {
+		return nil
+	}
+	return unioffice.Uint32(*v)
+}
+
+// builtinNumberFormatID returns the ID of the built in number format with the
+// given format code. The formats applications display with the regional
+// settings, e.g. the short date, aren't matched as the code wouldn't be kept.
+func builtinNumberFormatID(code string) (uint32, bool) {
+	if code == "General" {
+		return 0, true
+	}
+	for id := StandardFormat(1); id < 50; id++ {
+		if _, localized := (format.Locale{}).BuiltinFormat(uint32(id)); localized {
+			continue
+		}
+		if nf := CreateDefaultNumberFormat(id); nf.GetFormat() == code && nf.GetFormat() != "General" {
+			return uint32(id), true
+		}
+	}
+	return 0, false
+}
+
+// nextNumberFormatID returns an unused ID for a custom number format. It's
+// 200 plus the number of custom formats as it has always been unless that ID
+// is taken, e.g. after formats were removed, in which case it follows the
+// highest ID. IDs below 164 are reserved for built in formats.
+func (s StyleSheet) nextNumberFormatID() uint32 {
+	if s._gccd.NumFmts == nil {
+		return 200
+	}
+	id := uint32(200 + len(s._gccd.NumFmts.NumFmt))
+	max := uint32(163)
+	taken := false
+	for _, nf := range s._gccd.NumFmts.NumFmt {
+		if nf.NumFmtIdAttr == id {
+			taken = true
+		}
+		if nf.NumFmtIdAttr > max {
+			max = nf.NumFmtIdAttr
+		}
+	}
+	if taken {
+		return max + 1
+	}
+	return id
+}
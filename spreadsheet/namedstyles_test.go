@@ -0,0 +1,140 @@
+package spreadsheet
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/yaklabco/unioffice/v2"
+	"github.com/yaklabco/unioffice/v2/schema/soo/sml"
+)
+
+func TestBuiltinCellStyle(t *testing.T) {
+	wb := New()
+	ss := wb.StyleSheet
+	h1 := ss.AddBuiltinCellStyle(BuiltinCellStyleHeading1)
+	if h1.Name() != "Heading 1" || !h1.IsBuiltin() {
+		t.Fatalf("unexpected style %q", h1.Name())
+	}
+	if again := ss.AddBuiltinCellStyle(BuiltinCellStyleHeading1); again.X() != h1.X() {
+		t.Errorf("expected the existing style to be returned")
+	}
+	if got := ss.GetNamedCellStyle("heading 1"); got.X() != h1.X() {
+		t.Errorf("expected to find the style by name")
+	}
+
+	cs := ss.AddCellStyle()
+	cs.SetNamedStyle(h1)
+	if cs.NamedStyle().X() != h1.X() {
+		t.Errorf("expected the cell style to be based on Heading 1")
+	}
+	if f := cs.GetFont(); f == nil || f != h1.Format().GetFont() {
+		t.Errorf("expected the cell style to use the font of the named style")
+	}
+}
+
+func TestSetNumberFormatBuiltin(t *testing.T) {
+	wb := New()
+	cs := wb.StyleSheet.AddCellStyle()
+	cs.SetNumberFormat("0.00")
+	if cs.NumberFormat() != 2 {
+		t.Errorf("expected built in format 2, got %d", cs.NumberFormat())
+	}
+	cs.SetNumberFormat("0.000")
+	if cs.NumberFormat() < 164 {
+		t.Errorf("expected a custom format ID, got %d", cs.NumberFormat())
+	}
+	// the short date is displayed with the regional settings
+	cs.SetNumberFormat("m/d/yy")
+	if cs.NumberFormat() < 164 {
+		t.Errorf("expected a custom format ID for the date, got %d", cs.NumberFormat())
+	}
+	cs.SetNumberFormat("h:mm")
+	if cs.NumberFormat() != 20 {
+		t.Errorf("expected built in format 20, got %d", cs.NumberFormat())
+	}
+}
+
+// addDuplicateStyles adds three equal bold fonts and three equal cell formats
+// using them to the style sheet as written by other generators, styling A1:A3
+// with one format each, and returns the number of fonts and cell formats
+// before.
+func addDuplicateStyles(ss StyleSheet, sheet Sheet) (int, int) {
+	fonts, xfs := len(ss.X().Fonts.Font), len(ss.X().CellXfs.Xf)
+	for i := 0; i < 3; i++ {
+		f := sml.NewCT_Font()
+		f.FontChoice = append(f.FontChoice, &sml.CT_FontChoice{B: &sml.CT_BooleanProperty{ValAttr: unioffice.Bool(true)}})
+		ss.X().Fonts.Font = append(ss.X().Fonts.Font, f)
+		xf := sml.NewCT_Xf()
+		xf.FontIdAttr = unioffice.Uint32(uint32(len(ss.X().Fonts.Font) - 1))
+		xf.ApplyFontAttr = unioffice.Bool(true)
+		ss.X().CellXfs.Xf = append(ss.X().CellXfs.Xf, xf)
+		sheet.Cell(fmt.Sprintf("A%d", i+1)).SetStyleIndex(uint32(len(ss.X().CellXfs.Xf) - 1))
+	}
+	return fonts, xfs
+}
+
+func TestCompactStyles(t *testing.T) {
+	wb := New()
+	sheet := wb.AddSheet()
+	ss := wb.StyleSheet
+	fonts, xfs := addDuplicateStyles(ss, sheet)
+	unused := sml.NewCT_Xf()
+	unused.NumFmtIdAttr = unioffice.Uint32(200)
+	ss.X().NumFmts = sml.NewCT_NumFmts()
+	ss.X().NumFmts.NumFmt = []*sml.CT_NumFmt{{NumFmtIdAttr: 200, FormatCodeAttr: "0.0000"}}
+	ss.X().CellXfs.Xf = append(ss.X().CellXfs.Xf, unused)
+
+	wb.CompactStyles()
+	if n := len(ss.X().CellXfs.Xf); n != 2 {
+		t.Errorf("expected 2 cell formats, got %d (%d before)", n, xfs)
+	}
+	if n := len(ss.X().Fonts.Font); n != 2 {
+		t.Errorf("expected 2 fonts, got %d (%d before)", n, fonts)
+	}
+	if n := len(ss.X().NumFmts.NumFmt); n != 0 {
+		t.Errorf("expected no custom number formats, got %d", n)
+	}
+	for _, ref := range []string{"A1", "A2", "A3"} {
+		if s := sheet.Cell(ref).X().SAttr; s == nil || *s != 1 {
+			t.Errorf("expected %s to use format 1", ref)
+		}
+	}
+	if id := ss.X().CellXfs.Xf[1].FontIdAttr; id == nil || *id != 1 {
+		t.Errorf("expected the bold font to be font 1")
+	}
+}
+
+func TestDedupStyles(t *testing.T) {
+	wb := New()
+	sheet := wb.AddSheet()
+	ss := wb.StyleSheet
+	fonts, xfs := addDuplicateStyles(ss, sheet)
+	// styles modified after being applied end up equal too
+	for _, ref := range []string{"B1", "B2"} {
+		cs := ss.AddCellStyle()
+		sheet.Cell(ref).SetStyle(cs)
+		f := ss.AddFont()
+		cs.SetFont(f)
+		f.SetItalic(true)
+		cs.SetNumberFormat("0.000")
+	}
+
+	wb.dedupStyles()
+	if n := len(ss.X().Fonts.Font); n != fonts+2 {
+		t.Errorf("expected %d fonts, got %d", fonts+2, n)
+	}
+	if n := len(ss.X().CellXfs.Xf); n != xfs+2 {
+		t.Errorf("expected %d cell formats, got %d", xfs+2, n)
+	}
+	if n := len(ss.X().NumFmts.NumFmt); n != 1 || ss.X().NumFmts.NumFmt[0].NumFmtIdAttr != 200 {
+		t.Errorf("expected one custom number format with ID 200")
+	}
+	for _, ref := range []string{"A2", "A3"} {
+		if *sheet.Cell(ref).X().SAttr != *sheet.Cell("A1").X().SAttr {
+			t.Errorf("expected %s to share the format of A1", ref)
+		}
+	}
+	if s := *sheet.Cell("B2").X().SAttr; s != *sheet.Cell("B1").X().SAttr || s == *sheet.Cell("A1").X().SAttr {
+		t.Errorf("expected B1 and B2 to share their own format")
+	}
+}
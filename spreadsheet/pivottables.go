@@ -0,0 +1,159 @@
+package spreadsheet
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/yaklabco/unioffice/v2/schema/soo/sml"
+)
+
+const (
+	pivotTableType           = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/pivotTable"
+	pivotCacheDefinitionType = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/pivotCacheDefinition"
+)
+
+// PivotTable is a pivot table on a sheet. Pivot tables are read from the
+// pivot table parts of the sheet, they can't be created.
+type PivotTable struct {
+	s    *Sheet
+	path string
+	x    *sml.PivotTableDefinition
+}
+
+// X returns the inner wrapped XML type.
+func (p PivotTable) X() *sml.PivotTableDefinition { return p.x }
+
+// Name returns the name of the pivot table.
+func (p PivotTable) Name() string { return p.x.NameAttr }
+
+// CacheID returns the ID of the pivot cache the pivot table summarizes.
+func (p PivotTable) CacheID() uint32 { return p.x.CacheIdAttr }
+
+// Sheet returns the sheet the pivot table is on.
+func (p PivotTable) Sheet() *Sheet { return p.s }
+
+// PivotTables returns the pivot tables on the sheet.
+func (s *Sheet) PivotTables() ([]PivotTable, error) {
+	rels, ok := s.rels()
+	if !ok {
+		return nil, errors.New("sheet not found in workbook")
+	}
+	ret := []PivotTable{}
+	for _, r := range rels.Relationships() {
+		if r.Type() != pivotTableType {
+			continue
+		}
+		p := PivotTable{s, partPath("xl/worksheets", r.Target()), sml.NewPivotTableDefinition()}
+		ok, err := s._fgeg.readExtraFile(p.path, p.x)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			ret = append(ret, p)
+		}
+	}
+	return ret, nil
+}
+
+// cacheDefinition returns the path and content of the pivot cache definition
+// part of the pivot table.
+func (p PivotTable) cacheDefinition() (string, *sml.PivotCacheDefinition, error) {
+	wb := p.s._fgeg
+	if wb._gbadf.PivotCaches != nil {
+		for _, pc := range wb._gbadf.PivotCaches.PivotCache {
+			if pc.CacheIdAttr != p.x.CacheIdAttr {
+				continue
+			}
+			target := wb._bcg.GetTargetByRelId(pc.IdAttr)
+			if target == "" {
+				break
+			}
+			zipPath := partPath("xl", target)
+			def := sml.NewPivotCacheDefinition()
+			ok, err := wb.readExtraFile(zipPath, def)
+			if err != nil {
+				return "", nil, err
+			}
+			if ok {
+				return zipPath, def, nil
+			}
+		}
+	}
+	return "", nil, fmt.Errorf("pivot cache %d of %s not found", p.x.CacheIdAttr, p.Name())
+}
+
+// Fields returns the names of the source fields of the pivot table.
+func (p PivotTable) Fields() ([]string, error) {
+	_, def, err := p.cacheDefinition()
+	if err != nil {
+		return nil, err
+	}
+	ret := []string{}
+	if def.CacheFields != nil {
+		for _, cf := range def.CacheFields.CacheField {
+			ret = append(ret, cf.NameAttr)
+		}
+	}
+	return ret, nil
+}
+
+// cacheField returns the index and definition of the cache field with the
+// given name.
+func (p PivotTable) cacheField(def *sml.PivotCacheDefinition, name string) (int, *sml.CT_CacheField, error) {
+	if def.CacheFields != nil {
+		for i, cf := range def.CacheFields.CacheField {
+			if cf.NameAttr == name {
+				return i, cf, nil
+			}
+		}
+	}
+	return 0, nil, fmt.Errorf("pivot table %s has no field %s", p.Name(), name)
+}
+
+// sharedItemText returns the text an application displays for a shared item
+// of a pivot cache field.
+func sharedItemText(c *sml.CT_SharedItemsChoice) string {
+	switch {
+	case c.S != nil:
+		return c.S.VAttr
+	case c.N != nil:
+		return strconv.FormatFloat(c.N.VAttr, 'f', -1, 64)
+	case c.B != nil:
+		if c.B.VAttr {
+			return "TRUE"
+		}
+		return "FALSE"
+	case c.E != nil:
+		return c.E.VAttr
+	case c.D != nil:
+		return c.D.VAttr.Format("2006-01-02")
+	}
+	return "(blank)"
+}
+
+// isDateField returns true if the values of a pivot cache field are dates and
+// returns the earliest and latest of them.
+func isDateField(cf *sml.CT_CacheField) (min, max time.Time, ok bool) {
+	si := cf.SharedItems
+	if si == nil {
+		return
+	}
+	if si.MinDateAttr != nil && si.MaxDateAttr != nil {
+		return *si.MinDateAttr, *si.MaxDateAttr, true
+	}
+	for _, c := range si.SharedItemsChoice {
+		if c.D == nil {
+			continue
+		}
+		if !ok || c.D.VAttr.Before(min) {
+			min = c.D.VAttr
+		}
+		if !ok || c.D.VAttr.After(max) {
+			max = c.D.VAttr
+		}
+		ok = true
+	}
+	return
+}
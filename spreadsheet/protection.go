@@ -0,0 +1,363 @@
+package spreadsheet
+
+import (
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"hash"
+	"io"
+	"strings"
+	"unicode/utf16"
+
+	unioffice "github.com/yaklabco/unioffice/v2"
+	"github.com/yaklabco/unioffice/v2/schema/soo/sml"
+)
+
+// Password hashing parameters used by current versions of Excel.
+const (
+	passwordHashAlgorithm = "SHA-512"
+	passwordSpinCount     = 100000
+)
+
+// passwordHashes are the hash algorithms of password verifiers that can be
+// checked, keyed by their name in the protection attributes.
+var passwordHashes = map[string]func() hash.Hash{
+	"SHA-1":   sha1.New,
+	"SHA-256": sha256.New,
+	"SHA-384": sha512.New384,
+	"SHA-512": sha512.New,
+}
+
+// hashPassword computes the password verifier of ECMA-376 Agile Encryption
+// that Excel uses for sheet and range protection: the salted UTF-16LE
+// password is hashed, then rehashed spinCount times together with the
+// iteration number.
+func hashPassword(newHash func() hash.Hash, pw string, salt []byte, spinCount uint32) []byte {
+	h := newHash()
+	h.Write(salt)
+	for _, u := range utf16.Encode([]rune(pw)) {
+		h.Write([]byte{byte(u), byte(u >> 8)})
+	}
+	sum := h.Sum(nil)
+	iter := make([]byte, 4)
+	for i := uint32(0); i < spinCount; i++ {
+		binary.LittleEndian.PutUint32(iter, i)
+		h.Reset()
+		h.Write(sum)
+		h.Write(iter)
+		sum = h.Sum(sum[:0])
+	}
+	return sum
+}
+
+// passwordVerifier holds the attributes of a protection element that store a
+// password.
+type passwordVerifier struct {
+	legacy    **string
+	algorithm **string
+	hash      **string
+	salt      **string
+	spinCount **uint32
+}
+
+func (p passwordVerifier) set(pw string) error {
+	salt := make([]byte, 16)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return err
+	}
+	*p.legacy = nil
+	*p.algorithm = unioffice.String(passwordHashAlgorithm)
+	*p.hash = unioffice.String(base64.StdEncoding.EncodeToString(hashPassword(sha512.New, pw, salt, passwordSpinCount)))
+	*p.salt = unioffice.String(base64.StdEncoding.EncodeToString(salt))
+	*p.spinCount = unioffice.Uint32(passwordSpinCount)
+	return nil
+}
+
+func (p passwordVerifier) clear() {
+	*p.legacy = nil
+	*p.algorithm = nil
+	*p.hash = nil
+	*p.salt = nil
+	*p.spinCount = nil
+}
+
+func (p passwordVerifier) isSet() bool { return *p.legacy != nil || *p.hash != nil }
+
+func (p passwordVerifier) verify(pw string) bool {
+	if *p.hash != nil {
+		if *p.algorithm == nil {
+			return false
+		}
+		newHash, ok := passwordHashes[strings.ToUpper(**p.algorithm)]
+		if !ok {
+			return false
+		}
+		want, err := base64.StdEncoding.DecodeString(**p.hash)
+		if err != nil {
+			return false
+		}
+		var salt []byte
+		if *p.salt != nil {
+			if salt, err = base64.StdEncoding.DecodeString(**p.salt); err != nil {
+				return false
+			}
+		}
+		spinCount := uint32(0)
+		if *p.spinCount != nil {
+			spinCount = **p.spinCount
+		}
+		return subtle.ConstantTimeCompare(hashPassword(newHash, pw, salt, spinCount), want) == 1
+	}
+	if *p.legacy != nil {
+		return strings.EqualFold(PasswordHash(pw), **p.legacy)
+	}
+	return pw == ""
+}
+
+func (p SheetProtection) verifier() passwordVerifier {
+	x := p._bfcbd
+	return passwordVerifier{&x.PasswordAttr, &x.AlgorithmNameAttr, &x.HashValueAttr, &x.SaltValueAttr, &x.SpinCountAttr}
+}
+
+// SetPasswordSHA512 protects the sheet with a salted SHA-512 hash of the
+// password, as current versions of Excel do. It replaces any legacy password
+// hash. An error is returned if the salt can't be generated.
+func (p SheetProtection) SetPasswordSHA512(pw string) error { return p.verifier().set(pw) }
+
+// ClearPassword removes the legacy and SHA-512 password hashes.
+func (p SheetProtection) ClearPassword() { p.verifier().clear() }
+
+// HasPassword returns true if the sheet protection has a password.
+func (p SheetProtection) HasPassword() bool { return p.verifier().isSet() }
+
+// VerifyPassword returns true if pw matches the password of the sheet
+// protection, using either a SHA-1, SHA-256, SHA-384 or SHA-512 hash or the
+// legacy hash.
+func (p SheetProtection) VerifyPassword(pw string) bool { return p.verifier().verify(pw) }
+
+// setBoolAttr sets an optional boolean attribute, omitting it if it has the
+// default value.
+func setBoolAttr(attr **bool, b, def bool) {
+	if b == def {
+		*attr = nil
+	} else {
+		*attr = unioffice.Bool(b)
+	}
+}
+
+// boolAttr returns the value of an optional boolean attribute.
+func boolAttr(attr *bool, def bool) bool {
+	if attr == nil {
+		return def
+	}
+	return *attr
+}
+
+// The sheet protection flags are true if the action is prohibited while the
+// sheet is locked. By default, users of a locked sheet may only select cells.
+
+// LockScenarios controls whether scenarios can be edited.
+func (p SheetProtection) LockScenarios(b bool) { setBoolAttr(&p._bfcbd.ScenariosAttr, b, false) }
+
+// IsScenariosLocked returns whether scenarios can't be edited.
+func (p SheetProtection) IsScenariosLocked() bool { return boolAttr(p._bfcbd.ScenariosAttr, false) }
+
+// LockFormatCells controls whether cells can be formatted.
+func (p SheetProtection) LockFormatCells(b bool) { setBoolAttr(&p._bfcbd.FormatCellsAttr, b, true) }
+
+// IsFormatCellsLocked returns whether cells can't be formatted.
+func (p SheetProtection) IsFormatCellsLocked() bool {
+	return boolAttr(p._bfcbd.FormatCellsAttr, true)
+}
+
+// LockFormatColumns controls whether columns can be formatted.
+func (p SheetProtection) LockFormatColumns(b bool) {
+	setBoolAttr(&p._bfcbd.FormatColumnsAttr, b, true)
+}
+
+// IsFormatColumnsLocked returns whether columns can't be formatted.
+func (p SheetProtection) IsFormatColumnsLocked() bool {
+	return boolAttr(p._bfcbd.FormatColumnsAttr, true)
+}
+
+// LockFormatRows controls whether rows can be formatted.
+func (p SheetProtection) LockFormatRows(b bool) { setBoolAttr(&p._bfcbd.FormatRowsAttr, b, true) }
+
+// IsFormatRowsLocked returns whether rows can't be formatted.
+func (p SheetProtection) IsFormatRowsLocked() bool { return boolAttr(p._bfcbd.FormatRowsAttr, true) }
+
+// LockInsertColumns controls whether columns can be inserted.
+func (p SheetProtection) LockInsertColumns(b bool) {
+	setBoolAttr(&p._bfcbd.InsertColumnsAttr, b, true)
+}
+
+// IsInsertColumnsLocked returns whether columns can't be inserted.
+func (p SheetProtection) IsInsertColumnsLocked() bool {
+	return boolAttr(p._bfcbd.InsertColumnsAttr, true)
+}
+
+// LockInsertRows controls whether rows can be inserted.
+func (p SheetProtection) LockInsertRows(b bool) { setBoolAttr(&p._bfcbd.InsertRowsAttr, b, true) }
+
+// IsInsertRowsLocked returns whether rows can't be inserted.
+func (p SheetProtection) IsInsertRowsLocked() bool { return boolAttr(p._bfcbd.InsertRowsAttr, true) }
+
+// LockInsertHyperlinks controls whether hyperlinks can be inserted.
+func (p SheetProtection) LockInsertHyperlinks(b bool) {
+	setBoolAttr(&p._bfcbd.InsertHyperlinksAttr, b, true)
+}
+
+// IsInsertHyperlinksLocked returns whether hyperlinks can't be inserted.
+func (p SheetProtection) IsInsertHyperlinksLocked() bool {
+	return boolAttr(p._bfcbd.InsertHyperlinksAttr, true)
+}
+
+// LockDeleteColumns controls whether columns can be deleted.
+func (p SheetProtection) LockDeleteColumns(b bool) {
+	setBoolAttr(&p._bfcbd.DeleteColumnsAttr, b, true)
+}
+
+// IsDeleteColumnsLocked returns whether columns can't be deleted.
+func (p SheetProtection) IsDeleteColumnsLocked() bool {
+	return boolAttr(p._bfcbd.DeleteColumnsAttr, true)
+}
+
+// LockDeleteRows controls whether rows can be deleted.
+func (p SheetProtection) LockDeleteRows(b bool) { setBoolAttr(&p._bfcbd.DeleteRowsAttr, b, true) }
+
+// IsDeleteRowsLocked returns whether rows can't be deleted.
+func (p SheetProtection) IsDeleteRowsLocked() bool { return boolAttr(p._bfcbd.DeleteRowsAttr, true) }
+
+// LockSelectLockedCells controls whether locked cells can be selected.
+func (p SheetProtection) LockSelectLockedCells(b bool) {
+	setBoolAttr(&p._bfcbd.SelectLockedCellsAttr, b, false)
+}
+
+// IsSelectLockedCellsLocked returns whether locked cells can't be selected.
+func (p SheetProtection) IsSelectLockedCellsLocked() bool {
+	return boolAttr(p._bfcbd.SelectLockedCellsAttr, false)
+}
+
+// LockSelectUnlockedCells controls whether unlocked cells can be selected.
+func (p SheetProtection) LockSelectUnlockedCells(b bool) {
+	setBoolAttr(&p._bfcbd.SelectUnlockedCellsAttr, b, false)
+}
+
+// IsSelectUnlockedCellsLocked returns whether unlocked cells can't be
+// selected.
+func (p SheetProtection) IsSelectUnlockedCellsLocked() bool {
+	return boolAttr(p._bfcbd.SelectUnlockedCellsAttr, false)
+}
+
+// LockSort controls whether ranges can be sorted.
+func (p SheetProtection) LockSort(b bool) { setBoolAttr(&p._bfcbd.SortAttr, b, true) }
+
+// IsSortLocked returns whether ranges can't be sorted.
+func (p SheetProtection) IsSortLocked() bool { return boolAttr(p._bfcbd.SortAttr, true) }
+
+// LockAutoFilter controls whether auto filters can be used.
+func (p SheetProtection) LockAutoFilter(b bool) { setBoolAttr(&p._bfcbd.AutoFilterAttr, b, true) }
+
+// IsAutoFilterLocked returns whether auto filters can't be used.
+func (p SheetProtection) IsAutoFilterLocked() bool { return boolAttr(p._bfcbd.AutoFilterAttr, true) }
+
+// LockPivotTables controls whether pivot tables can be used.
+func (p SheetProtection) LockPivotTables(b bool) { setBoolAttr(&p._bfcbd.PivotTablesAttr, b, true) }
+
+// IsPivotTablesLocked returns whether pivot tables can't be used.
+func (p SheetProtection) IsPivotTablesLocked() bool {
+	return boolAttr(p._bfcbd.PivotTablesAttr, true)
+}
+
+// ProtectedRange is a range of a protected sheet that users may edit, listed
+// under "Allow Users to Edit Ranges" in Excel. A range can require its own
+// password.
+type ProtectedRange struct{ x *sml.CT_ProtectedRange }
+
+// X returns the inner wrapped XML type.
+func (r ProtectedRange) X() *sml.CT_ProtectedRange { return r.x }
+
+// Name returns the title of the range.
+func (r ProtectedRange) Name() string { return r.x.NameAttr }
+
+// SetName sets the title of the range.
+func (r ProtectedRange) SetName(name string) { r.x.NameAttr = name }
+
+// Reference returns the cell references of the range, e.g. "B2:B10 D2".
+func (r ProtectedRange) Reference() string { return strings.Join(r.x.SqrefAttr, " ") }
+
+// SetReference sets the cell references of the range, separated by spaces.
+func (r ProtectedRange) SetReference(ref string) { r.x.SqrefAttr = strings.Fields(ref) }
+
+func (r ProtectedRange) verifier() passwordVerifier {
+	x := r.x
+	return passwordVerifier{&x.PasswordAttr, &x.AlgorithmNameAttr, &x.HashValueAttr, &x.SaltValueAttr, &x.SpinCountAttr}
+}
+
+// SetPassword requires pw to edit the range, stored as a salted SHA-512 hash.
+// An error is returned if the salt can't be generated.
+func (r ProtectedRange) SetPassword(pw string) error { return r.verifier().set(pw) }
+
+// SetLegacyPassword requires pw to edit the range, stored using the legacy
+// hash that is compatible with older versions of Excel.
+func (r ProtectedRange) SetLegacyPassword(pw string) {
+	r.verifier().clear()
+	r.x.PasswordAttr = unioffice.String(PasswordHash(pw))
+}
+
+// ClearPassword allows editing the range without a password.
+func (r ProtectedRange) ClearPassword() { r.verifier().clear() }
+
+// HasPassword returns true if a password is required to edit the range.
+func (r ProtectedRange) HasPassword() bool { return r.verifier().isSet() }
+
+// VerifyPassword returns true if pw matches the password of the range.
+func (r ProtectedRange) VerifyPassword(pw string) bool { return r.verifier().verify(pw) }
+
+// AddProtectedRange adds a range that users may edit while the sheet is
+// protected. ref is a space separated list of cell references.
+func (s *Sheet) AddProtectedRange(name, ref string) ProtectedRange {
+	if s._bbbe.ProtectedRanges == nil {
+		s._bbbe.ProtectedRanges = sml.NewCT_ProtectedRanges()
+	}
+	x := sml.NewCT_ProtectedRange()
+	r := ProtectedRange{x}
+	r.SetName(name)
+	r.SetReference(ref)
+	s._bbbe.ProtectedRanges.ProtectedRange = append(s._bbbe.ProtectedRanges.ProtectedRange, x)
+	return r
+}
+
+// ProtectedRanges returns the ranges that users may edit while the sheet is
+// protected.
+func (s *Sheet) ProtectedRanges() []ProtectedRange {
+	ret := []ProtectedRange{}
+	if s._bbbe.ProtectedRanges != nil {
+		for _, x := range s._bbbe.ProtectedRanges.ProtectedRange {
+			ret = append(ret, ProtectedRange{x})
+		}
+	}
+	return ret
+}
+
+// RemoveProtectedRange removes the protected range with the given name.
+func (s *Sheet) RemoveProtectedRange(name string) error {
+	if s._bbbe.ProtectedRanges != nil {
+		for i, x := range s._bbbe.ProtectedRanges.ProtectedRange {
+			if x.NameAttr != name {
+				continue
+			}
+			pr := s._bbbe.ProtectedRanges
+			pr.ProtectedRange = append(pr.ProtectedRange[:i], pr.ProtectedRange[i+1:]...)
+			if len(pr.ProtectedRange) == 0 {
+				s._bbbe.ProtectedRanges = nil
+			}
+			return nil
+		}
+	}
+	return ErrorNotFound
+}
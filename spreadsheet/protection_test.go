@@ -0,0 +1,121 @@
+package spreadsheet
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/yaklabco/unioffice/v2"
+)
+
+func TestSheetProtectionFlags(t *testing.T) {
+	wb := New()
+	sheet := wb.AddSheet()
+	p := sheet.Protection()
+	p.LockSheet(true)
+	if !p.IsFormatCellsLocked() || !p.IsSortLocked() || p.IsSelectLockedCellsLocked() {
+		t.Errorf("expected Excel defaults for unset flags")
+	}
+	p.LockFormatCells(false)
+	p.LockAutoFilter(false)
+	p.LockSelectLockedCells(true)
+	p.LockSort(true)
+	if p.IsFormatCellsLocked() || p.IsAutoFilterLocked() || !p.IsSelectLockedCellsLocked() || !p.IsSortLocked() {
+		t.Errorf("unexpected flags after setting them")
+	}
+	out, err := xml.Marshal(p.X())
+	if err != nil {
+		t.Fatalf("error marshalling: %s", err)
+	}
+	got := string(out)
+	for _, exp := range []string{`formatCells="0"`, `autoFilter="0"`, `selectLockedCells="1"`} {
+		if !strings.Contains(got, exp) {
+			t.Errorf("expected %s in %s", exp, got)
+		}
+	}
+	if strings.Contains(got, "sort=") {
+		t.Errorf("expected default sort flag to be omitted, got %s", got)
+	}
+}
+
+func TestSheetProtectionPassword(t *testing.T) {
+	wb := New()
+	sheet := wb.AddSheet()
+	p := sheet.Protection()
+	p.SetPassword("secret")
+	if !p.VerifyPassword("secret") || p.VerifyPassword("other") {
+		t.Errorf("legacy password verification failed")
+	}
+	if err := p.SetPasswordSHA512("secret"); err != nil {
+		t.Fatalf("error setting password: %s", err)
+	}
+	x := p.X()
+	if x.PasswordAttr != nil || x.AlgorithmNameAttr == nil || *x.AlgorithmNameAttr != "SHA-512" ||
+		x.SpinCountAttr == nil || *x.SpinCountAttr != 100000 || x.SaltValueAttr == nil || x.HashValueAttr == nil {
+		t.Fatalf("expected SHA-512 password attributes")
+	}
+	if !p.VerifyPassword("secret") || p.VerifyPassword("Secret") {
+		t.Errorf("SHA-512 password verification failed")
+	}
+	p.ClearPassword()
+	if p.HasPassword() {
+		t.Errorf("expected no password after clearing")
+	}
+}
+
+func TestSheetProtectionPasswordHashes(t *testing.T) {
+	wb := New()
+	sheet := wb.AddSheet()
+	p := sheet.Protection()
+	x := p.X()
+	x.SaltValueAttr = unioffice.String("AAECAwQFBgcICQoLDA0ODw==")
+	x.SpinCountAttr = unioffice.Uint32(1000)
+	for alg, hash := range map[string]string{
+		"SHA-1":   "7WgVgymqgzjUurVycXvvXSISJJg=",
+		"SHA-256": "1hvNaZVedBhuL72Ixo8LZhhwuMIHSOEFDlM3NvODYrU=",
+	} {
+		x.AlgorithmNameAttr = unioffice.String(alg)
+		x.HashValueAttr = unioffice.String(hash)
+		if !p.VerifyPassword("secret") || p.VerifyPassword("Secret") {
+			t.Errorf("%s password verification failed", alg)
+		}
+	}
+	x.AlgorithmNameAttr = unioffice.String("MD5")
+	if p.VerifyPassword("secret") {
+		t.Errorf("expected an unsupported algorithm not to verify")
+	}
+}
+
+func TestProtectedRanges(t *testing.T) {
+	wb := New()
+	sheet := wb.AddSheet()
+	sheet.Protection().LockSheet(true)
+	inputs := sheet.AddProtectedRange("Inputs", "B2:B10 D2")
+	if err := inputs.SetPassword("pw"); err != nil {
+		t.Fatalf("error setting password: %s", err)
+	}
+	sheet.AddProtectedRange("Notes", "F1:F5")
+
+	ranges := sheet.ProtectedRanges()
+	if len(ranges) != 2 {
+		t.Fatalf("expected 2 protected ranges, got %d", len(ranges))
+	}
+	if ranges[0].Name() != "Inputs" || ranges[0].Reference() != "B2:B10 D2" {
+		t.Errorf("unexpected range %s %s", ranges[0].Name(), ranges[0].Reference())
+	}
+	if !ranges[0].HasPassword() || !ranges[0].VerifyPassword("pw") || ranges[0].VerifyPassword("") {
+		t.Errorf("range password verification failed")
+	}
+	if ranges[1].HasPassword() || !ranges[1].VerifyPassword("") {
+		t.Errorf("expected range without password")
+	}
+	if err := sheet.RemoveProtectedRange("Inputs"); err != nil {
+		t.Fatalf("error removing range: %s", err)
+	}
+	if err := sheet.RemoveProtectedRange("Inputs"); err != ErrorNotFound {
+		t.Errorf("expected ErrorNotFound, got %v", err)
+	}
+	if len(sheet.ProtectedRanges()) != 1 {
+		t.Errorf("expected 1 range after removal")
+	}
+}
@@ -0,0 +1,37 @@
+package spreadsheet
+
+import (
+	"fmt"
+
+	"github.com/yaklabco/unioffice/v2/spreadsheet/formula"
+)
+
+// GetFormulaR1C1 returns the formula of the cell using R1C1 style references
+// relative to the cell. Formulas that differ only by their position have the
+// same R1C1 form.
+func (c Cell) GetFormulaR1C1() string {
+	f := c.GetFormula()
+	if f == "" {
+		return ""
+	}
+	r, err := formula.A1ToR1C1(f, c.Reference())
+	if err != nil {
+		return f
+	}
+	return r
+}
+
+// SetFormulaR1C1 sets the formula of the cell from a formula using R1C1 style
+// references, which are resolved relative to the cell. The same R1C1 formula
+// can be set on many cells to fill a range with a relative formula.
+func (c Cell) SetFormulaR1C1(s string) error {
+	f, err := formula.R1C1ToA1(s, c.Reference())
+	if err != nil {
+		return err
+	}
+	if formula.ParseString(f) == nil {
+		return fmt.Errorf("cannot parse %s", s)
+	}
+	c.SetFormulaRaw(f)
+	return nil
+}
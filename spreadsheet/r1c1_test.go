@@ -0,0 +1,24 @@
+package spreadsheet
+
+import "testing"
+
+func TestCellFormulaR1C1(t *testing.T) {
+	wb := New()
+	sheet := wb.AddSheet()
+	for r := uint32(1); r <= 3; r++ {
+		row := sheet.Row(r)
+		row.Cell("A").SetNumber(float64(r))
+		if err := row.Cell("B").SetFormulaR1C1("RC[-1]*R1C1"); err != nil {
+			t.Fatalf("error setting formula: %s", err)
+		}
+	}
+	if got := sheet.Cell("B3").GetFormula(); got != "A3*$A$1" {
+		t.Errorf("expected A3*$A$1, got %s", got)
+	}
+	if got := sheet.Cell("B2").GetFormulaR1C1(); got != "RC[-1]*R1C1" {
+		t.Errorf("expected RC[-1]*R1C1, got %s", got)
+	}
+	if sheet.Cell("B1").GetFormulaR1C1() != sheet.Cell("B3").GetFormulaR1C1() {
+		t.Error("expected filled formulas to have the same R1C1 form")
+	}
+}
@@ -0,0 +1,305 @@
+package spreadsheet
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/yaklabco/unioffice/v2/schema/soo/ofc/sharedTypes"
+	"github.com/yaklabco/unioffice/v2/schema/soo/sml"
+)
+
+// RunFormat is the effective formatting of a run of text, combining the run
+// properties with the font of the cell.
+type RunFormat struct {
+	Font string
+	// Size is the font size in points.
+	Size float64
+	// Color is the RGB value of the text color, e.g. "FF0000". It's empty for
+	// the automatic color.
+	Color     string
+	Bold      bool
+	Italic    bool
+	Strike    bool
+	Underline sml.ST_UnderlineValues
+	// VertAlign is superscript or subscript, or unset for normal text.
+	VertAlign sharedTypes.ST_VerticalAlignRun
+}
+
+// TextRun is a segment of the text of a cell with its effective formatting.
+type TextRun struct {
+	Text string
+	RunFormat
+}
+
+// rstText returns the text of a rich string.
+func rstText(r *sml.CT_Rst) string {
+	if r == nil {
+		return ""
+	}
+	if r.T != nil {
+		return *r.T
+	}
+	sb := strings.Builder{}
+	for _, run := range r.R {
+		sb.WriteString(run.T)
+	}
+	return sb.String()
+}
+
+// richString returns the inline or shared rich string of the cell, or nil if
+// the cell doesn't contain a string.
+func (c Cell) richString() (*sml.CT_Rst, error) {
+	switch c._dga.TAttr {
+	case sml.ST_CellTypeInlineStr:
+		return c._dga.Is, nil
+	case sml.ST_CellTypeS:
+		if c._dga.V == nil {
+			return nil, nil
+		}
+		id, err := strconv.Atoi(*c._dga.V)
+		if err != nil {
+			return nil, err
+		}
+		sst := c._bgg.SharedStrings.X()
+		if id < 0 || id >= len(sst.Si) {
+			return nil, errors.New("invalid shared string index " + *c._dga.V)
+		}
+		return sst.Si[id], nil
+	}
+	return nil, nil
+}
+
+// cellFont returns the font of the cell style, or the default font.
+func (c Cell) cellFont() *sml.CT_Font {
+	ss := c._bgg.StyleSheet.X()
+	if ss.Fonts == nil || len(ss.Fonts.Font) == 0 {
+		return nil
+	}
+	id := uint32(0)
+	if c._dga.SAttr != nil && ss.CellXfs != nil && int(*c._dga.SAttr) < len(ss.CellXfs.Xf) {
+		if xf := ss.CellXfs.Xf[*c._dga.SAttr]; xf.FontIdAttr != nil {
+			id = *xf.FontIdAttr
+		}
+	}
+	if int(id) >= len(ss.Fonts.Font) {
+		id = 0
+	}
+	return ss.Fonts.Font[id]
+}
+
+func boolProperty(p *sml.CT_BooleanProperty) bool { return p.ValAttr == nil || *p.ValAttr }
+
+func underlineProperty(p *sml.CT_UnderlineProperty) sml.ST_UnderlineValues {
+	if p.ValAttr == sml.ST_UnderlineValuesUnset {
+		return sml.ST_UnderlineValuesSingle
+	}
+	return p.ValAttr
+}
+
+// fontFormat returns the formatting of a cell font.
+func (wb *Workbook) fontFormat(f *sml.CT_Font) RunFormat {
+	return wb.applyFont(RunFormat{}, f)
+}
+
+// applyFont overrides the formatting with the properties set by a font, e.g.
+// the font of a differential format.
+func (wb *Workbook) applyFont(rf RunFormat, f *sml.CT_Font) RunFormat {
+	if f == nil {
+		return rf
+	}
+	for _, fc := range f.FontChoice {
+		switch {
+		case fc.Name != nil:
+			rf.Font = fc.Name.ValAttr
+		case fc.Sz != nil:
+			rf.Size = fc.Sz.ValAttr
+		case fc.Color != nil:
+			rf.Color, _ = wb.resolveColor(fc.Color)
+		case fc.B != nil:
+			rf.Bold = boolProperty(fc.B)
+		case fc.I != nil:
+			rf.Italic = boolProperty(fc.I)
+		case fc.Strike != nil:
+			rf.Strike = boolProperty(fc.Strike)
+		case fc.U != nil:
+			rf.Underline = underlineProperty(fc.U)
+		case fc.VertAlign != nil:
+			rf.VertAlign = fc.VertAlign.ValAttr
+		}
+	}
+	return rf
+}
+
+// applyRunProperties overrides the formatting with the properties of a run.
+func (wb *Workbook) applyRunProperties(rf RunFormat, rpr *sml.CT_RPrElt) RunFormat {
+	if rpr == nil {
+		return rf
+	}
+	for _, p := range rpr.RPrEltChoice {
+		switch {
+		case p.RFont != nil:
+			rf.Font = p.RFont.ValAttr
+		case p.Sz != nil:
+			rf.Size = p.Sz.ValAttr
+		case p.Color != nil:
+			rf.Color, _ = wb.resolveColor(p.Color)
+		case p.B != nil:
+			rf.Bold = boolProperty(p.B)
+		case p.I != nil:
+			rf.Italic = boolProperty(p.I)
+		case p.Strike != nil:
+			rf.Strike = boolProperty(p.Strike)
+		case p.U != nil:
+			rf.Underline = underlineProperty(p.U)
+		case p.VertAlign != nil:
+			rf.VertAlign = p.VertAlign.ValAttr
+		}
+	}
+	return rf
+}
+
+// GetRichText returns the runs of text of a string cell, from either the
+// shared strings table or an inline string, with their effective formatting.
+// Plain strings return a single run with the formatting of the cell font.
+// Cells that don't contain a string return no runs.
+func (c Cell) GetRichText() ([]TextRun, error) {
+	rst, err := c.richString()
+	if err != nil || rst == nil {
+		return nil, err
+	}
+	base := c._bgg.fontFormat(c.cellFont())
+	if len(rst.R) == 0 {
+		return []TextRun{{Text: rstText(rst), RunFormat: base}}, nil
+	}
+	ret := make([]TextRun, 0, len(rst.R))
+	for _, r := range rst.R {
+		ret = append(ret, TextRun{Text: r.T, RunFormat: c._bgg.applyRunProperties(base, r.RPr)})
+	}
+	return ret, nil
+}
+
+// EditRichText returns the rich text of a string cell for editing, e.g. to
+// replace the text of its runs while keeping their formatting. Shared strings
+// can be used by other cells, so the cell is changed to an inline string with
+// a copy of the runs first. A plain string becomes a single unformatted run.
+func (c Cell) EditRichText() (RichText, error) {
+	rst, err := c.richString()
+	if err != nil {
+		return RichText{}, err
+	}
+	if rst == nil {
+		return RichText{}, errors.New("cell doesn't contain a string")
+	}
+	if c._dga.TAttr == sml.ST_CellTypeS {
+		rst = copyRst(rst)
+		c.clearValue()
+		c._dga.TAttr = sml.ST_CellTypeInlineStr
+		c._dga.Is = rst
+	}
+	if rst.T != nil {
+		run := sml.NewCT_RElt()
+		run.T = *rst.T
+		rst.T = nil
+		rst.R = append([]*sml.CT_RElt{run}, rst.R...)
+	}
+	return RichText{rst}, nil
+}
+
+// copyRst returns a copy of a rich string whose runs can be changed without
+// changing the original.
+func copyRst(r *sml.CT_Rst) *sml.CT_Rst {
+	ret := sml.NewCT_Rst()
+	if r.T != nil {
+		t := *r.T
+		ret.T = &t
+	}
+	for _, run := range r.R {
+		cp := sml.NewCT_RElt()
+		cp.T = run.T
+		if run.RPr != nil {
+			cp.RPr = sml.NewCT_RPrElt()
+			for _, p := range run.RPr.RPrEltChoice {
+				c := *p
+				switch {
+				case p.RFont != nil:
+					v := *p.RFont
+					c.RFont = &v
+				case p.Sz != nil:
+					v := *p.Sz
+					c.Sz = &v
+				case p.Color != nil:
+					v := *p.Color
+					c.Color = &v
+				case p.B != nil:
+					v := *p.B
+					c.B = &v
+				case p.I != nil:
+					v := *p.I
+					c.I = &v
+				case p.U != nil:
+					v := *p.U
+					c.U = &v
+				case p.Strike != nil:
+					v := *p.Strike
+					c.Strike = &v
+				case p.VertAlign != nil:
+					v := *p.VertAlign
+					c.VertAlign = &v
+				}
+				cp.RPr.RPrEltChoice = append(cp.RPr.RPrEltChoice, &c)
+			}
+		}
+		ret.R = append(ret.R, cp)
+	}
+	ret.RPh = r.RPh
+	ret.PhoneticPr = r.PhoneticPr
+	return ret
+}
+
+// Runs returns the runs of the rich text.
+func (r RichText) Runs() []RichTextRun {
+	ret := make([]RichTextRun, 0, len(r._fbd.R))
+	for _, x := range r._fbd.R {
+		ret = append(ret, RichTextRun{x})
+	}
+	return ret
+}
+
+// Text returns the text of the rich text.
+func (r RichText) Text() string { return rstText(r._fbd) }
+
+// RemoveRun removes the run at index i.
+func (r RichText) RemoveRun(i int) {
+	if i < 0 || i >= len(r._fbd.R) {
+		return
+	}
+	r._fbd.R = append(r._fbd.R[:i], r._fbd.R[i+1:]...)
+}
+
+// Text returns the text of the run.
+func (r RichTextRun) Text() string { return r._egda.T }
+
+// SetStrike controls whether the text is struck through.
+func (r RichTextRun) SetStrike(b bool) {
+	r.ensureRpr()
+	for _, p := range r._egda.RPr.RPrEltChoice {
+		if p.Strike != nil {
+			p.Strike.ValAttr = &b
+			return
+		}
+	}
+	r._egda.RPr.RPrEltChoice = append(r._egda.RPr.RPrEltChoice, &sml.CT_RPrEltChoice{Strike: &sml.CT_BooleanProperty{ValAttr: &b}})
+}
+
+// SetVertAlign sets the run to superscript or subscript.
+func (r RichTextRun) SetVertAlign(v sharedTypes.ST_VerticalAlignRun) {
+	r.ensureRpr()
+	for _, p := range r._egda.RPr.RPrEltChoice {
+		if p.VertAlign != nil {
+			p.VertAlign.ValAttr = v
+			return
+		}
+	}
+	r._egda.RPr.RPrEltChoice = append(r._egda.RPr.RPrEltChoice, &sml.CT_RPrEltChoice{VertAlign: &sml.CT_VerticalAlignFontProperty{ValAttr: v}})
+}
@@ -0,0 +1,125 @@
+package spreadsheet
+
+import (
+	"testing"
+
+	"github.com/yaklabco/unioffice/v2/color"
+	"github.com/yaklabco/unioffice/v2/measurement"
+	"github.com/yaklabco/unioffice/v2/schema/soo/ofc/sharedTypes"
+	"github.com/yaklabco/unioffice/v2/schema/soo/sml"
+)
+
+func TestGetRichTextInline(t *testing.T) {
+	wb := New()
+	sheet := wb.AddSheet()
+	font := wb.StyleSheet.AddFont()
+	font.SetName("Arial")
+	font.SetSize(14)
+	font.SetItalic(true)
+	cs := wb.StyleSheet.AddCellStyle()
+	cs.SetFont(font)
+
+	cell := sheet.Cell("A1")
+	rt := cell.SetRichTextString()
+	r := rt.AddRun()
+	r.SetText("Hello ")
+	r = rt.AddRun()
+	r.SetText("world")
+	r.SetBold(true)
+	r.SetColor(color.Red)
+	r.SetSize(10 * measurement.Point)
+	r = rt.AddRun()
+	r.SetText("2")
+	r.SetVertAlign(sharedTypes.ST_VerticalAlignRunSuperscript)
+	cell.SetStyle(cs)
+
+	if got := cell.GetString(); got != "Hello world2" {
+		t.Errorf("expected Hello world2, got %s", got)
+	}
+	runs, err := cell.GetRichText()
+	if err != nil {
+		t.Fatalf("error getting rich text: %s", err)
+	}
+	if len(runs) != 3 {
+		t.Fatalf("expected 3 runs, got %d", len(runs))
+	}
+	if runs[0].Text != "Hello " || runs[0].Font != "Arial" || runs[0].Size != 14 || !runs[0].Italic || runs[0].Bold {
+		t.Errorf("unexpected first run %+v", runs[0])
+	}
+	if runs[1].Font != "Arial" || runs[1].Size != 10 || !runs[1].Bold || runs[1].Color != "FF0000" {
+		t.Errorf("unexpected second run %+v", runs[1])
+	}
+	if runs[2].VertAlign != sharedTypes.ST_VerticalAlignRunSuperscript {
+		t.Errorf("expected superscript run, got %+v", runs[2])
+	}
+}
+
+func TestEditRichTextShared(t *testing.T) {
+	wb := New()
+	sheet := wb.AddSheet()
+	si := sml.NewCT_Rst()
+	for _, text := range []string{"Total ", "sales"} {
+		run := sml.NewCT_RElt()
+		run.T = text
+		si.R = append(si.R, run)
+	}
+	si.R[1].RPr = sml.NewCT_RPrElt()
+	si.R[1].RPr.RPrEltChoice = []*sml.CT_RPrEltChoice{{B: sml.NewCT_BooleanProperty()}}
+	sst := wb.SharedStrings.X()
+	sst.Si = append(sst.Si, si)
+	for _, ref := range []string{"A1", "A2"} {
+		c := sheet.Cell(ref).X()
+		c.TAttr = sml.ST_CellTypeS
+		v := "0"
+		c.V = &v
+	}
+
+	runs, err := sheet.Cell("A2").GetRichText()
+	if err != nil || len(runs) != 2 || !runs[1].Bold || runs[0].Bold {
+		t.Fatalf("unexpected shared string runs %+v %v", runs, err)
+	}
+
+	rt, err := sheet.Cell("A1").EditRichText()
+	if err != nil {
+		t.Fatalf("error editing rich text: %s", err)
+	}
+	edit := rt.Runs()
+	edit[0].SetText("Umsatz ")
+	edit[1].SetText("gesamt")
+	edit[1].SetBold(false)
+
+	if got := sheet.Cell("A1").GetString(); got != "Umsatz gesamt" {
+		t.Errorf("expected edited text, got %s", got)
+	}
+	if got := sheet.Cell("A2").GetString(); got != "Total sales" {
+		t.Errorf("expected shared string to be unchanged, got %s", got)
+	}
+	if runs, _ := sheet.Cell("A2").GetRichText(); !runs[1].Bold {
+		t.Errorf("expected shared string formatting to be unchanged")
+	}
+	if runs, _ := sheet.Cell("A1").GetRichText(); runs[1].Bold {
+		t.Errorf("expected edited run to not be bold")
+	}
+}
+
+func TestEditRichTextPlain(t *testing.T) {
+	wb := New()
+	sheet := wb.AddSheet()
+	sheet.Cell("A1").SetString("plain")
+	rt, err := sheet.Cell("A1").EditRichText()
+	if err != nil {
+		t.Fatalf("error editing rich text: %s", err)
+	}
+	runs := rt.Runs()
+	if len(runs) != 1 || runs[0].Text() != "plain" {
+		t.Fatalf("expected a single plain run")
+	}
+	runs[0].SetStrike(true)
+	got, _ := sheet.Cell("A1").GetRichText()
+	if len(got) != 1 || !got[0].Strike || got[0].Text != "plain" {
+		t.Errorf("unexpected runs %+v", got)
+	}
+	if _, err := sheet.Cell("B1").EditRichText(); err == nil {
+		t.Errorf("expected error for empty cell")
+	}
+}
@@ -0,0 +1,182 @@
+package spreadsheet
+
+import (
+	"fmt"
+
+	unioffice "github.com/yaklabco/unioffice/v2"
+	"github.com/yaklabco/unioffice/v2/schema/soo/sml"
+	"github.com/yaklabco/unioffice/v2/spreadsheet/formula"
+	"github.com/yaklabco/unioffice/v2/spreadsheet/reference"
+)
+
+// effectiveFormula returns the formula of the cell. Cells inside a shared
+// formula block other than its first cell don't store a formula, for these the
+// formula of the first cell is returned translated to the cell.
+func (c Cell) effectiveFormula() string {
+	f := c._dga.F
+	if f.Content != "" || f.TAttr != sml.ST_CellFormulaTypeShared || f.SiAttr == nil || c._cee == nil {
+		return f.Content
+	}
+	master := c._cee.sharedFormulaMaster(*f.SiAttr)
+	if master == nil {
+		return ""
+	}
+	return translateSharedFormula(master, c.Reference())
+}
+
+// sharedFormulaMaster returns the cell that stores the formula of the shared
+// formula block with the given index.
+func (s *Sheet) sharedFormulaMaster(si uint32) *sml.CT_Cell {
+	for _, r := range s._bbbe.SheetData.Row {
+		for _, c := range r.C {
+			f := c.F
+			if f != nil && f.TAttr == sml.ST_CellFormulaTypeShared && f.SiAttr != nil &&
+				*f.SiAttr == si && f.RefAttr != nil && f.Content != "" {
+				return c
+			}
+		}
+	}
+	return nil
+}
+
+// translateSharedFormula returns the formula of the first cell of a shared
+// formula block as it applies to the cell ref.
+func translateSharedFormula(master *sml.CT_Cell, ref string) string {
+	if master.RAttr == nil {
+		return master.F.Content
+	}
+	from, err := reference.ParseCellReference(*master.RAttr)
+	if err != nil {
+		return master.F.Content
+	}
+	to, err := reference.ParseCellReference(ref)
+	if err != nil {
+		return master.F.Content
+	}
+	return formula.ShiftReferences(master.F.Content, int(to.RowIdx)-int(from.RowIdx), int(to.ColumnIdx)-int(from.ColumnIdx))
+}
+
+// ExpandSharedFormulas replaces the shared formulas of the sheet by a normal
+// formula in every cell of the shared formula blocks. Cached values are kept.
+func (s *Sheet) ExpandSharedFormulas() {
+	masters := map[uint32]*sml.CT_Cell{}
+	for _, r := range s._bbbe.SheetData.Row {
+		for _, c := range r.C {
+			f := c.F
+			if f != nil && f.TAttr == sml.ST_CellFormulaTypeShared && f.SiAttr != nil &&
+				f.RefAttr != nil && f.Content != "" {
+				if _, ok := masters[*f.SiAttr]; !ok {
+					masters[*f.SiAttr] = c
+				}
+			}
+		}
+	}
+	for _, r := range s._bbbe.SheetData.Row {
+		for _, c := range r.C {
+			f := c.F
+			if f == nil || f.TAttr != sml.ST_CellFormulaTypeShared || f.SiAttr == nil {
+				continue
+			}
+			master, ok := masters[*f.SiAttr]
+			if !ok {
+				continue
+			}
+			content := f.Content
+			if c != master && c.RAttr != nil {
+				content = translateSharedFormula(master, *c.RAttr)
+			}
+			c.F = sml.NewCT_CellFormula()
+			c.F.Content = content
+		}
+	}
+}
+
+// CompactFormulas replaces normal formulas of adjacent cells that differ only
+// by their position with shared formulas, which is how Excel stores filled
+// ranges. Cells are grouped in rectangular blocks, the first cell of a block
+// stores the formula and the others only refer to it.
+func (s *Sheet) CompactFormulas() {
+	type formulaCell struct {
+		x     *sml.CT_Cell
+		r1c1  string
+		taken bool
+	}
+	grid := map[uint32]map[uint32]*formulaCell{}
+	order := []reference.CellReference{}
+	si := uint32(0)
+	for _, r := range s._bbbe.SheetData.Row {
+		for _, c := range r.C {
+			f := c.F
+			if f == nil {
+				continue
+			}
+			if f.SiAttr != nil && *f.SiAttr >= si {
+				si = *f.SiAttr + 1
+			}
+			if c.RAttr == nil || f.Content == "" || f.RefAttr != nil ||
+				(f.TAttr != sml.ST_CellFormulaTypeUnset && f.TAttr != sml.ST_CellFormulaTypeNormal) {
+				continue
+			}
+			ref, err := reference.ParseCellReference(*c.RAttr)
+			if err != nil {
+				continue
+			}
+			r1c1, err := formula.A1ToR1C1(f.Content, *c.RAttr)
+			if err != nil {
+				continue
+			}
+			if grid[ref.RowIdx] == nil {
+				grid[ref.RowIdx] = map[uint32]*formulaCell{}
+			}
+			grid[ref.RowIdx][ref.ColumnIdx] = &formulaCell{x: c, r1c1: r1c1}
+			order = append(order, ref)
+		}
+	}
+	at := func(row, col uint32, r1c1 string) *formulaCell {
+		fc := grid[row][col]
+		if fc == nil || fc.taken || fc.r1c1 != r1c1 {
+			return nil
+		}
+		return fc
+	}
+
+	for _, ref := range order {
+		first := grid[ref.RowIdx][ref.ColumnIdx]
+		if first.taken {
+			continue
+		}
+		// extend the block to the right, then down while whole rows match
+		lastCol := ref.ColumnIdx
+		for at(ref.RowIdx, lastCol+1, first.r1c1) != nil {
+			lastCol++
+		}
+		lastRow := ref.RowIdx
+	rows:
+		for {
+			for col := ref.ColumnIdx; col <= lastCol; col++ {
+				if at(lastRow+1, col, first.r1c1) == nil {
+					break rows
+				}
+			}
+			lastRow++
+		}
+		if lastCol == ref.ColumnIdx && lastRow == ref.RowIdx {
+			continue
+		}
+
+		content := first.x.F.Content
+		for row := ref.RowIdx; row <= lastRow; row++ {
+			for col := ref.ColumnIdx; col <= lastCol; col++ {
+				fc := grid[row][col]
+				fc.taken = true
+				f := sml.NewCT_CellFormula()
+				f.TAttr = sml.ST_CellFormulaTypeShared
+				f.SiAttr = unioffice.Uint32(si)
+				fc.x.F = f
+			}
+		}
+		first.x.F.RefAttr = unioffice.String(fmt.Sprintf("%s%d:%s%d", ref.Column, ref.RowIdx, reference.IndexToColumn(lastCol), lastRow))
+		first.x.F.Content = content
+		si++
+	}
+}
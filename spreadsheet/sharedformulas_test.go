@@ -0,0 +1,63 @@
+package spreadsheet
+
+import "testing"
+
+func TestSharedFormulaGetFormula(t *testing.T) {
+	wb := New()
+	sheet := wb.AddSheet()
+	if err := sheet.Cell("C1").SetFormulaShared("A1+$B$1*B1", 2, 1); err != nil {
+		t.Fatalf("error setting shared formula: %s", err)
+	}
+	exp := map[string]string{
+		"C1": "A1+$B$1*B1",
+		"D1": "B1+$B$1*C1",
+		"C3": "A3+$B$1*B3",
+		"D2": "B2+$B$1*C2",
+	}
+	for ref, f := range exp {
+		if got := sheet.Cell(ref).GetFormula(); got != f {
+			t.Errorf("expected %s formula %s, got %s", ref, f, got)
+		}
+	}
+
+	sheet.ExpandSharedFormulas()
+	for ref, f := range exp {
+		c := sheet.Cell(ref).X()
+		if c.F.SiAttr != nil || c.F.RefAttr != nil || c.F.Content != f {
+			t.Errorf("expected %s to be expanded to %s, got %s", ref, f, c.F.Content)
+		}
+	}
+}
+
+func TestCompactFormulas(t *testing.T) {
+	wb := New()
+	sheet := wb.AddSheet()
+	for r := uint32(1); r <= 3; r++ {
+		row := sheet.Row(r)
+		row.Cell("C").SetFormulaRaw("A" + row.Cell("C").Reference()[1:] + "*2")
+		row.Cell("D").SetFormulaRaw("B" + row.Cell("D").Reference()[1:] + "*2")
+	}
+	sheet.Cell("E1").SetFormulaRaw("SUM(A1:A3)")
+	sheet.Cell("E2").SetFormulaRaw("SUM(A1:A3)")
+
+	sheet.CompactFormulas()
+	master := sheet.Cell("C1").X().F
+	if master.RefAttr == nil || *master.RefAttr != "C1:D3" || master.Content != "A1*2" {
+		t.Fatalf("expected shared block C1:D3, got %v %s", master.RefAttr, master.Content)
+	}
+	child := sheet.Cell("D3").X().F
+	if child.Content != "" || child.SiAttr == nil || *child.SiAttr != *master.SiAttr {
+		t.Errorf("expected D3 to refer to the shared formula")
+	}
+	if got := sheet.Cell("D3").GetFormula(); got != "B3*2" {
+		t.Errorf("expected D3 formula B3*2, got %s", got)
+	}
+	if f := sheet.Cell("E1").X().F; f.SiAttr != nil || f.Content != "SUM(A1:A3)" {
+		t.Errorf("expected E1 to keep its formula")
+	}
+
+	sheet.ExpandSharedFormulas()
+	if got := sheet.Cell("D2").X().F.Content; got != "B2*2" {
+		t.Errorf("expected D2 formula B2*2 after expanding, got %s", got)
+	}
+}
@@ -0,0 +1,1274 @@
+package spreadsheet
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/yaklabco/unioffice/v2"
+	sd "github.com/yaklabco/unioffice/v2/schema/soo/dml/spreadsheetDrawing"
+	"github.com/yaklabco/unioffice/v2/schema/soo/sml"
+	"github.com/yaklabco/unioffice/v2/spreadsheet/reference"
+)
+
+const (
+	slicerType               = "http://schemas.microsoft.com/office/2007/relationships/slicer"
+	slicerContentType        = "application/vnd.ms-excel.slicer+xml"
+	slicerCacheType          = "http://schemas.microsoft.com/office/2007/relationships/slicerCache"
+	slicerCacheContentType   = "application/vnd.ms-excel.slicerCache+xml"
+	timelineType             = "http://schemas.microsoft.com/office/2011/relationships/timeline"
+	timelineContentType      = "application/vnd.ms-excel.timeline+xml"
+	timelineCacheType        = "http://schemas.microsoft.com/office/2011/relationships/timelineCache"
+	timelineCacheContentType = "application/vnd.ms-excel.timelineCache+xml"
+
+	x14Namespace       = "http://schemas.microsoft.com/office/spreadsheetml/2009/9/main"
+	x15Namespace       = "http://schemas.microsoft.com/office/spreadsheetml/2010/11/main"
+	officeRelNamespace = "http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+	slicerDrawingURI   = "http://schemas.microsoft.com/office/drawing/2010/slicer"
+	timelineDrawingURI = "http://schemas.microsoft.com/office/drawing/2012/timeslicer"
+
+	// URIs of the extensions that refer to slicer and timeline parts
+	extPivotSlicerCaches = "{BBE1A952-AA13-448e-AADC-164F8A28A991}"
+	extTableSlicerCaches = "{46BE6895-7355-4a93-B00E-2C351335B9C9}"
+	extTimelineCaches    = "{D0CA8CA8-9F24-4464-BF8E-62219DCF47F9}"
+	extPivotSlicerList   = "{A8765BA9-456A-4dab-B4F3-ACF838C121DE}"
+	extTableSlicerList   = "{3A4CF648-6AED-40f4-86FF-DC5316D8AED3}"
+	extTimelineList      = "{7E03D99C-DC04-49d9-9315-930204A7B6E9}"
+	extTableSlicerCache  = "{2F2917AC-EB37-4324-AD4E-5DD8C200BD13}"
+	extPivotCacheID      = "{725AE2AE-9491-48be-B2B4-4EB974FC3084}"
+
+	timelineDateLayout     = "2006-01-02T15:04:05"
+	defaultSlicerRowHeight = 241300
+	blankSlicerItem        = "(blank)"
+)
+
+type xmlExtensionList struct {
+	Ext []*xmlExtension `xml:"http://schemas.openxmlformats.org/spreadsheetml/2006/main ext"`
+}
+
+type xmlExtension struct {
+	URI string              `xml:"uri,attr"`
+	Any []*unioffice.XSDAny `xml:",any"`
+}
+
+type xmlSlicers struct {
+	XMLName xml.Name          `xml:"http://schemas.microsoft.com/office/spreadsheetml/2009/9/main slicers"`
+	Slicer  []*xmlSlicer      `xml:"slicer"`
+	ExtLst  *xmlExtensionList `xml:"extLst,omitempty"`
+}
+
+type xmlSlicer struct {
+	Name           string            `xml:"name,attr"`
+	Cache          string            `xml:"cache,attr"`
+	Caption        string            `xml:"caption,attr,omitempty"`
+	StartItem      *uint32           `xml:"startItem,attr,omitempty"`
+	ColumnCount    *uint32           `xml:"columnCount,attr,omitempty"`
+	ShowCaption    *bool             `xml:"showCaption,attr,omitempty"`
+	Level          *uint32           `xml:"level,attr,omitempty"`
+	Style          string            `xml:"style,attr,omitempty"`
+	LockedPosition *bool             `xml:"lockedPosition,attr,omitempty"`
+	RowHeight      uint64            `xml:"rowHeight,attr"`
+	ExtLst         *xmlExtensionList `xml:"extLst,omitempty"`
+}
+
+type xmlSlicerCacheDefinition struct {
+	XMLName     xml.Name              `xml:"http://schemas.microsoft.com/office/spreadsheetml/2009/9/main slicerCacheDefinition"`
+	Name        string                `xml:"name,attr"`
+	SourceName  string                `xml:"sourceName,attr"`
+	PivotTables *xmlSlicerCachePivots `xml:"pivotTables,omitempty"`
+	Data        *xmlSlicerCacheData   `xml:"data,omitempty"`
+	ExtLst      *xmlExtensionList     `xml:"extLst,omitempty"`
+}
+
+type xmlSlicerCachePivots struct {
+	PivotTable []*xmlSlicerCachePivot `xml:"pivotTable"`
+}
+
+type xmlSlicerCachePivot struct {
+	TabID uint32 `xml:"tabId,attr"`
+	Name  string `xml:"name,attr"`
+}
+
+type xmlSlicerCacheData struct {
+	OLAP    *unioffice.XSDAny `xml:"olap,omitempty"`
+	Tabular *xmlTabularSlicer `xml:"tabular,omitempty"`
+}
+
+type xmlTabularSlicer struct {
+	PivotCacheID   uint32                 `xml:"pivotCacheId,attr"`
+	SortOrder      string                 `xml:"sortOrder,attr,omitempty"`
+	CustomListSort *bool                  `xml:"customListSort,attr,omitempty"`
+	ShowMissing    *bool                  `xml:"showMissing,attr,omitempty"`
+	CrossFilter    string                 `xml:"crossFilter,attr,omitempty"`
+	Items          *xmlTabularSlicerItems `xml:"items,omitempty"`
+	ExtLst         *xmlExtensionList      `xml:"extLst,omitempty"`
+}
+
+type xmlTabularSlicerItems struct {
+	Count int                     `xml:"count,attr"`
+	I     []*xmlTabularSlicerItem `xml:"i"`
+}
+
+type xmlTabularSlicerItem struct {
+	X  uint32 `xml:"x,attr"`
+	S  bool   `xml:"s,attr,omitempty"`
+	ND bool   `xml:"nd,attr,omitempty"`
+}
+
+type xmlTimelines struct {
+	XMLName  xml.Name          `xml:"http://schemas.microsoft.com/office/spreadsheetml/2010/11/main timelines"`
+	Timeline []*xmlTimeline    `xml:"timeline"`
+	ExtLst   *xmlExtensionList `xml:"extLst,omitempty"`
+}
+
+type xmlTimeline struct {
+	Name                    string            `xml:"name,attr"`
+	Cache                   string            `xml:"cache,attr"`
+	Caption                 string            `xml:"caption,attr,omitempty"`
+	ShowHeader              *bool             `xml:"showHeader,attr,omitempty"`
+	ShowSelectionLabel      *bool             `xml:"showSelectionLabel,attr,omitempty"`
+	ShowTimeLevel           *bool             `xml:"showTimeLevel,attr,omitempty"`
+	ShowHorizontalScrollbar *bool             `xml:"showHorizontalScrollbar,attr,omitempty"`
+	Level                   uint32            `xml:"level,attr"`
+	SelectionLevel          uint32            `xml:"selectionLevel,attr"`
+	ScrollPosition          string            `xml:"scrollPosition,attr,omitempty"`
+	Style                   string            `xml:"style,attr,omitempty"`
+	ExtLst                  *xmlExtensionList `xml:"extLst,omitempty"`
+}
+
+type xmlTimelineCacheDefinition struct {
+	XMLName     xml.Name              `xml:"http://schemas.microsoft.com/office/spreadsheetml/2010/11/main timelineCacheDefinition"`
+	Name        string                `xml:"name,attr"`
+	SourceName  string                `xml:"sourceName,attr"`
+	PivotTables *xmlSlicerCachePivots `xml:"pivotTables,omitempty"`
+	State       *xmlTimelineState     `xml:"state"`
+	PivotFilter *unioffice.XSDAny     `xml:"pivotFilter,omitempty"`
+	ExtLst      *xmlExtensionList     `xml:"extLst,omitempty"`
+}
+
+type xmlTimelineState struct {
+	SingleRangeFilterState *bool             `xml:"singleRangeFilterState,attr,omitempty"`
+	MinimalRefreshVersion  uint32            `xml:"minimalRefreshVersion,attr"`
+	LastRefreshVersion     uint32            `xml:"lastRefreshVersion,attr"`
+	PivotCacheID           uint32            `xml:"pivotCacheId,attr"`
+	FilterType             string            `xml:"filterType,attr"`
+	FilterID               *uint32           `xml:"filterId,attr,omitempty"`
+	FilterTabID            *uint32           `xml:"filterTabId,attr,omitempty"`
+	FilterPivotName        string            `xml:"filterPivotName,attr,omitempty"`
+	Selection              *xmlTimelineRange `xml:"selection,omitempty"`
+	Bounds                 *xmlTimelineRange `xml:"bounds,omitempty"`
+	ExtLst                 *xmlExtensionList `xml:"extLst,omitempty"`
+}
+
+type xmlTimelineRange struct {
+	StartDate string `xml:"startDate,attr"`
+	EndDate   string `xml:"endDate,attr"`
+}
+
+// slicerState holds the slicer and timeline parts of a workbook that have been
+// accessed, modified parts are written back when the workbook is saved.
+type slicerState struct {
+	caches         []*slicerCachePart
+	timelineCaches []*timelineCachePart
+	sheets         map[*sml.Worksheet]*sheetSlicers
+}
+
+type slicerCachePart struct {
+	path  string
+	dirty bool
+	x     *xmlSlicerCacheDefinition
+}
+
+type timelineCachePart struct {
+	path  string
+	dirty bool
+	x     *xmlTimelineCacheDefinition
+}
+
+// sheetSlicers holds the slicer and timeline parts of a sheet. Table and pivot
+// table slicers are kept in separate parts.
+type sheetSlicers struct {
+	slicers   []*slicerPart
+	timelines []*timelinePart
+}
+
+type slicerPart struct {
+	path  string
+	table bool
+	dirty bool
+	x     *xmlSlicers
+}
+
+type timelinePart struct {
+	path  string
+	dirty bool
+	x     *xmlTimelines
+}
+
+// slicerState reads the slicer and timeline cache parts of the workbook, they
+// are kept as extra files as the package reader doesn't support them.
+func (wb *Workbook) slicerState() (*slicerState, error) {
+	if wb._slicers != nil {
+		return wb._slicers, nil
+	}
+	st := &slicerState{sheets: map[*sml.Worksheet]*sheetSlicers{}}
+	for _, r := range wb._bcg.Relationships() {
+		switch r.Type() {
+		case slicerCacheType:
+			p := &slicerCachePart{path: partPath("xl", r.Target()), x: &xmlSlicerCacheDefinition{}}
+			ok, err := wb.readExtraFile(p.path, p.x)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				st.caches = append(st.caches, p)
+			}
+		case timelineCacheType:
+			p := &timelineCachePart{path: partPath("xl", r.Target()), x: &xmlTimelineCacheDefinition{}}
+			ok, err := wb.readExtraFile(p.path, p.x)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				st.timelineCaches = append(st.timelineCaches, p)
+			}
+		}
+	}
+	wb._slicers = st
+	return st, nil
+}
+
+// sheetSlicers reads the slicer and timeline parts of the sheet.
+func (s *Sheet) sheetSlicers() (*sheetSlicers, error) {
+	st, err := s._fgeg.slicerState()
+	if err != nil {
+		return nil, err
+	}
+	if ss, ok := st.sheets[s._bbbe]; ok {
+		return ss, nil
+	}
+	rels, ok := s.rels()
+	if !ok {
+		return nil, errors.New("sheet not found in workbook")
+	}
+	tableRefs := map[string]bool{}
+	for _, id := range extRefs(s._bbbe.ExtLst, extTableSlicerList) {
+		tableRefs[id] = true
+	}
+	ss := &sheetSlicers{}
+	for _, r := range rels.Relationships() {
+		switch r.Type() {
+		case slicerType:
+			p := &slicerPart{path: partPath("xl/worksheets", r.Target()), table: tableRefs[r.ID()], x: &xmlSlicers{}}
+			ok, err := s._fgeg.readExtraFile(p.path, p.x)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				ss.slicers = append(ss.slicers, p)
+			}
+		case timelineType:
+			p := &timelinePart{path: partPath("xl/worksheets", r.Target()), x: &xmlTimelines{}}
+			ok, err := s._fgeg.readExtraFile(p.path, p.x)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				ss.timelines = append(ss.timelines, p)
+			}
+		}
+	}
+	st.sheets[s._bbbe] = ss
+	return ss, nil
+}
+
+// flushSlicers writes the modified slicer and timeline parts to the extra
+// files so they're saved with the workbook.
+func (wb *Workbook) flushSlicers() error {
+	st := wb._slicers
+	if st == nil {
+		return nil
+	}
+	write := func(dirty *bool, zipPath string, v interface{}) error {
+		if !*dirty {
+			return nil
+		}
+		*dirty = false
+		return wb.writeExtraFile(zipPath, v)
+	}
+	for _, p := range st.caches {
+		if err := write(&p.dirty, p.path, p.x); err != nil {
+			return err
+		}
+	}
+	for _, p := range st.timelineCaches {
+		if err := write(&p.dirty, p.path, p.x); err != nil {
+			return err
+		}
+	}
+	for _, ss := range st.sheets {
+		for _, p := range ss.slicers {
+			if err := write(&p.dirty, p.path, p.x); err != nil {
+				return err
+			}
+		}
+		for _, p := range ss.timelines {
+			if err := write(&p.dirty, p.path, p.x); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// findExt returns the extension of an extension list with the given URI.
+func findExt(lst *sml.CT_ExtensionList, uri string) *sml.CT_Extension {
+	if lst == nil {
+		return nil
+	}
+	for _, ext := range lst.Ext {
+		if ext.UriAttr != nil && strings.EqualFold(*ext.UriAttr, uri) {
+			return ext
+		}
+	}
+	return nil
+}
+
+// extRefs returns the relationship IDs referred to by an extension.
+func extRefs(lst *sml.CT_ExtensionList, uri string) []string {
+	ext := findExt(lst, uri)
+	if ext == nil {
+		return nil
+	}
+	n, ok := ext.Any.(*unioffice.XSDAny)
+	if !ok {
+		return nil
+	}
+	ids := []string{}
+	var walk func(n *unioffice.XSDAny)
+	walk = func(n *unioffice.XSDAny) {
+		for _, a := range n.Attrs {
+			if a.Name.Space == officeRelNamespace && a.Name.Local == "id" {
+				ids = append(ids, a.Value)
+			}
+		}
+		for _, c := range n.Nodes {
+			walk(c)
+		}
+	}
+	walk(n)
+	return ids
+}
+
+// addExtRef adds an item referring to a relationship to the list element of
+// an extension, adding the extension if needed.
+func addExtRef(lst **sml.CT_ExtensionList, uri string, list, item xml.Name, id string) {
+	if *lst == nil {
+		*lst = sml.NewCT_ExtensionList()
+	}
+	ext := findExt(*lst, uri)
+	if ext == nil {
+		ext = sml.NewCT_Extension()
+		ext.UriAttr = unioffice.String(uri)
+		(*lst).Ext = append((*lst).Ext, ext)
+	}
+	n, ok := ext.Any.(*unioffice.XSDAny)
+	if !ok {
+		n = &unioffice.XSDAny{XMLName: list}
+		ext.Any = n
+	}
+	n.Nodes = append(n.Nodes, &unioffice.XSDAny{
+		XMLName: item,
+		Attrs:   []xml.Attr{{Name: xml.Name{Space: officeRelNamespace, Local: "id"}, Value: id}},
+	})
+}
+
+// anyAttr returns the value of an attribute of an element.
+func anyAttr(n *unioffice.XSDAny, local string) (string, bool) {
+	for _, a := range n.Attrs {
+		if a.Name.Local == local {
+			return a.Value, true
+		}
+	}
+	return "", false
+}
+
+// nextPartPath returns the path of the first part numbered from one that
+// doesn't exist yet, e.g. xl/slicers/slicer2.xml for xl/slicers/slicer%d.xml.
+func (wb *Workbook) nextPartPath(format string) (string, int) {
+	for n := 1; ; n++ {
+		if p := fmt.Sprintf(format, n); !wb.hasPart(p) {
+			return p, n
+		}
+	}
+}
+
+// slicerIdentifier returns a defined name for a slicer or timeline cache of a
+// source field.
+func (wb *Workbook) slicerIdentifier(prefix, field string) string {
+	b := strings.Builder{}
+	b.WriteString(prefix)
+	for _, r := range field {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.' {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('_')
+		}
+	}
+	taken := map[string]bool{}
+	for _, dn := range wb.DefinedNames() {
+		taken[strings.ToLower(dn.Name())] = true
+	}
+	name := b.String()
+	for i := 1; taken[strings.ToLower(name)]; i++ {
+		name = b.String() + strconv.Itoa(i)
+	}
+	return name
+}
+
+// slicerName returns a workbook unique name for a slicer or timeline.
+func (wb *Workbook) slicerName(base string) (string, error) {
+	taken := map[string]bool{}
+	for _, s := range wb.AllSheets() {
+		ss, err := s.sheetSlicers()
+		if err != nil {
+			return "", err
+		}
+		for _, p := range ss.slicers {
+			for _, x := range p.x.Slicer {
+				taken[x.Name] = true
+			}
+		}
+		for _, p := range ss.timelines {
+			for _, x := range p.x.Timeline {
+				taken[x.Name] = true
+			}
+		}
+	}
+	name := base
+	for i := 1; taken[name]; i++ {
+		name = fmt.Sprintf("%s %d", base, i)
+	}
+	return name, nil
+}
+
+// parseDrawingAnchor parses the cells an object covers, a single cell anchors
+// an object of the default size.
+func parseDrawingAnchor(anchor string, cols, rows uint32) (from, to reference.CellReference, err error) {
+	from, to, err = reference.ParseRangeReference(anchor)
+	if err != nil {
+		from, err = reference.ParseCellReference(anchor)
+		if err != nil {
+			return from, to, fmt.Errorf("invalid anchor %s: %s", anchor, err)
+		}
+		to = from
+		to.ColumnIdx += cols - 1
+		to.RowIdx += rows - 1
+	}
+	if to.ColumnIdx < from.ColumnIdx {
+		from.ColumnIdx, to.ColumnIdx = to.ColumnIdx, from.ColumnIdx
+	}
+	if to.RowIdx < from.RowIdx {
+		from.RowIdx, to.RowIdx = to.RowIdx, from.RowIdx
+	}
+	return from, to, nil
+}
+
+// addDrawingFrame places a graphic frame displaying el over the anchor cells,
+// adding a drawing to the sheet if needed.
+func (s *Sheet) addDrawingFrame(anchor, name, uri string, el *unioffice.XSDAny) error {
+	from, to, err := parseDrawingAnchor(anchor, 3, 14)
+	if err != nil {
+		return err
+	}
+	dr, ok := s.Drawing()
+	if !ok {
+		dr = s._fgeg.AddDrawing()
+		s.SetDrawing(dr)
+	}
+	a := sd.NewCT_TwoCellAnchor()
+	a.EditAsAttr = sd.ST_EditAsOneCell
+	a.From.Col = int32(from.ColumnIdx)
+	a.From.Row = int32(from.RowIdx - 1)
+	a.To.Col = int32(to.ColumnIdx + 1)
+	a.To.Row = int32(to.RowIdx)
+	for _, m := range []*sd.CT_Marker{a.From, a.To} {
+		m.ColOff.ST_CoordinateUnqualified = unioffice.Int64(0)
+		m.RowOff.ST_CoordinateUnqualified = unioffice.Int64(0)
+	}
+	gf := sd.NewCT_GraphicalObjectFrame()
+	gf.MacroAttr = unioffice.String("")
+	a.ObjectChoicesChoice = &sd.EG_ObjectChoicesChoice{GraphicFrame: gf}
+	dr.X().EG_Anchor = append(dr.X().EG_Anchor, &sd.EG_Anchor{AnchorChoice: &sd.EG_AnchorChoice{TwoCellAnchor: a}})
+	gf.NvGraphicFramePr.CNvPr.IdAttr = uint32(len(dr.X().EG_Anchor) + 1)
+	gf.NvGraphicFramePr.CNvPr.NameAttr = name
+	gf.Graphic.GraphicData.UriAttr = uri
+	gf.Graphic.GraphicData.Any = []unioffice.Any{el}
+	return nil
+}
+
+// SlicerItem is an item of a slicer, a distinct value of the field the slicer
+// filters by.
+type SlicerItem struct {
+	Value    string
+	Selected bool
+}
+
+// Slicer is a slicer filtering a table or a pivot table.
+type Slicer struct {
+	wb    *Workbook
+	part  *slicerPart
+	cache *slicerCachePart
+	x     *xmlSlicer
+}
+
+// Slicers returns the slicers on the sheet.
+func (s *Sheet) Slicers() ([]Slicer, error) {
+	ss, err := s.sheetSlicers()
+	if err != nil {
+		return nil, err
+	}
+	st, _ := s._fgeg.slicerState()
+	ret := []Slicer{}
+	for _, p := range ss.slicers {
+		for _, x := range p.x.Slicer {
+			sl := Slicer{wb: s._fgeg, part: p, x: x}
+			for _, c := range st.caches {
+				if c.x.Name == x.Cache {
+					sl.cache = c
+				}
+			}
+			ret = append(ret, sl)
+		}
+	}
+	return ret, nil
+}
+
+// AddSlicer adds a slicer filtering the column of a table or the field of a
+// pivot table to the sheet. Source is either a Table or a PivotTable and the
+// slicer covers the anchor cells (e.g. "H2:J15"), or a default size from the
+// anchor cell. All items of a new slicer are selected.
+func (s *Sheet) AddSlicer(source interface{}, column, anchor string) (Slicer, error) {
+	wb := s._fgeg
+	rels, ok := s.rels()
+	if !ok {
+		return Slicer{}, errors.New("sheet not found in workbook")
+	}
+	ss, err := s.sheetSlicers()
+	if err != nil {
+		return Slicer{}, err
+	}
+	st, _ := wb.slicerState()
+
+	cache := &xmlSlicerCacheDefinition{SourceName: column}
+	table := false
+	switch src := source.(type) {
+	case Table:
+		colID, ok := uint32(0), false
+		if src.X().TableColumns != nil {
+			for _, tc := range src.X().TableColumns.TableColumn {
+				if tc.NameAttr == column {
+					colID, ok = tc.IdAttr, true
+				}
+			}
+		}
+		if !ok {
+			return Slicer{}, fmt.Errorf("table %s has no column %s", src.Name(), column)
+		}
+		table = true
+		tsc := &unioffice.XSDAny{
+			XMLName: xml.Name{Space: x15Namespace, Local: "tableSlicerCache"},
+			Attrs: []xml.Attr{
+				{Name: xml.Name{Local: "tableId"}, Value: strconv.FormatUint(uint64(src.X().IdAttr), 10)},
+				{Name: xml.Name{Local: "column"}, Value: strconv.FormatUint(uint64(colID), 10)},
+			},
+		}
+		cache.ExtLst = &xmlExtensionList{Ext: []*xmlExtension{{URI: extTableSlicerCache, Any: []*unioffice.XSDAny{tsc}}}}
+	case PivotTable:
+		_, def, err := src.cacheDefinition()
+		if err != nil {
+			return Slicer{}, err
+		}
+		_, cf, err := src.cacheField(def, column)
+		if err != nil {
+			return Slicer{}, err
+		}
+		id, err := src.slicerCacheID()
+		if err != nil {
+			return Slicer{}, err
+		}
+		cache.PivotTables = &xmlSlicerCachePivots{PivotTable: []*xmlSlicerCachePivot{{TabID: src.s._abea.SheetIdAttr, Name: src.Name()}}}
+		tab := &xmlTabularSlicer{PivotCacheID: id, Items: &xmlTabularSlicerItems{}}
+		if cf.SharedItems != nil {
+			for i := range cf.SharedItems.SharedItemsChoice {
+				tab.Items.I = append(tab.Items.I, &xmlTabularSlicerItem{X: uint32(i), S: true})
+			}
+		}
+		tab.Items.Count = len(tab.Items.I)
+		cache.Data = &xmlSlicerCacheData{Tabular: tab}
+	default:
+		return Slicer{}, errors.New("slicer source must be a table or pivot table")
+	}
+
+	name, err := wb.slicerName(column)
+	if err != nil {
+		return Slicer{}, err
+	}
+	cache.Name = wb.slicerIdentifier("Slicer_", column)
+	cachePath, n := wb.nextPartPath("xl/slicerCaches/slicerCache%d.xml")
+	cp := &slicerCachePart{path: cachePath, dirty: true, x: cache}
+	if err := wb.writeExtraFile(cp.path, cp.x); err != nil {
+		return Slicer{}, err
+	}
+	st.caches = append(st.caches, cp)
+	wbRel := wb._bcg.AddRelationship(fmt.Sprintf("slicerCaches/slicerCache%d.xml", n), slicerCacheType)
+	wb.ContentTypes.AddOverride("/"+cachePath, slicerCacheContentType)
+	if table {
+		addExtRef(&wb._gbadf.ExtLst, extTableSlicerCaches, xml.Name{Space: x15Namespace, Local: "slicerCaches"},
+			xml.Name{Space: x14Namespace, Local: "slicerCache"}, wbRel.ID())
+	} else {
+		addExtRef(&wb._gbadf.ExtLst, extPivotSlicerCaches, xml.Name{Space: x14Namespace, Local: "slicerCaches"},
+			xml.Name{Space: x14Namespace, Local: "slicerCache"}, wbRel.ID())
+	}
+	wb.AddDefinedName(cache.Name, "#N/A")
+
+	var part *slicerPart
+	for _, p := range ss.slicers {
+		if p.table == table {
+			part = p
+		}
+	}
+	if part == nil {
+		partPath, n := wb.nextPartPath("xl/slicers/slicer%d.xml")
+		part = &slicerPart{path: partPath, table: table, x: &xmlSlicers{}}
+		rel := rels.AddRelationship(fmt.Sprintf("../slicers/slicer%d.xml", n), slicerType)
+		wb.ContentTypes.AddOverride("/"+partPath, slicerContentType)
+		if table {
+			addExtRef(&s._bbbe.ExtLst, extTableSlicerList, xml.Name{Space: x15Namespace, Local: "slicerList"},
+				xml.Name{Space: x14Namespace, Local: "slicer"}, rel.ID())
+		} else {
+			addExtRef(&s._bbbe.ExtLst, extPivotSlicerList, xml.Name{Space: x14Namespace, Local: "slicerList"},
+				xml.Name{Space: x14Namespace, Local: "slicer"}, rel.ID())
+		}
+		ss.slicers = append(ss.slicers, part)
+	}
+	x := &xmlSlicer{Name: name, Cache: cache.Name, Caption: column, RowHeight: defaultSlicerRowHeight}
+	part.x.Slicer = append(part.x.Slicer, x)
+	part.dirty = true
+	if err := wb.writeExtraFile(part.path, part.x); err != nil {
+		return Slicer{}, err
+	}
+
+	el := &unioffice.XSDAny{
+		XMLName: xml.Name{Space: slicerDrawingURI, Local: "slicer"},
+		Attrs:   []xml.Attr{{Name: xml.Name{Local: "name"}, Value: name}},
+	}
+	if err := s.addDrawingFrame(anchor, name, slicerDrawingURI, el); err != nil {
+		return Slicer{}, err
+	}
+	return Slicer{wb, part, cp, x}, nil
+}
+
+// Name returns the name of the slicer.
+func (sl Slicer) Name() string { return sl.x.Name }
+
+// CacheName returns the name of the slicer cache, the defined name the slicer
+// is referred to by in formulas.
+func (sl Slicer) CacheName() string { return sl.x.Cache }
+
+// SourceName returns the name of the table column or pivot table field the
+// slicer filters by.
+func (sl Slicer) SourceName() string {
+	if sl.cache == nil {
+		return ""
+	}
+	return sl.cache.x.SourceName
+}
+
+// IsTableSlicer returns true if the slicer filters a table rather than a
+// pivot table.
+func (sl Slicer) IsTableSlicer() bool { return sl.part.table }
+
+// Caption returns the caption displayed in the header of the slicer.
+func (sl Slicer) Caption() string { return sl.x.Caption }
+
+// SetCaption sets the caption displayed in the header of the slicer.
+func (sl Slicer) SetCaption(caption string) {
+	sl.x.Caption = caption
+	sl.part.dirty = true
+}
+
+// Style returns the name of the slicer style, e.g. SlicerStyleLight1, or an
+// empty string for the default style.
+func (sl Slicer) Style() string { return sl.x.Style }
+
+// SetStyle sets the slicer style, e.g. SlicerStyleDark2.
+func (sl Slicer) SetStyle(style string) {
+	sl.x.Style = style
+	sl.part.dirty = true
+}
+
+// ColumnCount returns the number of columns the items are displayed in.
+func (sl Slicer) ColumnCount() int {
+	if sl.x.ColumnCount == nil {
+		return 1
+	}
+	return int(*sl.x.ColumnCount)
+}
+
+// SetColumnCount sets the number of columns the items are displayed in.
+func (sl Slicer) SetColumnCount(n int) {
+	if n <= 1 {
+		sl.x.ColumnCount = nil
+	} else {
+		sl.x.ColumnCount = unioffice.Uint32(uint32(n))
+	}
+	sl.part.dirty = true
+}
+
+// tableSource returns the table ID and column ID of a table slicer cache.
+func (c *slicerCachePart) tableSource() (tableID, columnID uint32, ok bool) {
+	if c.x.ExtLst == nil {
+		return 0, 0, false
+	}
+	for _, ext := range c.x.ExtLst.Ext {
+		for _, n := range ext.Any {
+			if n.XMLName.Local != "tableSlicerCache" {
+				continue
+			}
+			t, _ := anyAttr(n, "tableId")
+			col, _ := anyAttr(n, "column")
+			tid, err1 := strconv.ParseUint(t, 10, 32)
+			cid, err2 := strconv.ParseUint(col, 10, 32)
+			return uint32(tid), uint32(cid), err1 == nil && err2 == nil
+		}
+	}
+	return 0, 0, false
+}
+
+// tableColumn returns the table a table slicer filters along with the sheet
+// it is on, the zero based index of the column and the data rows.
+func (sl Slicer) tableColumn() (*Sheet, *sml.Table, uint32, reference.CellReference, reference.CellReference, error) {
+	var from, to reference.CellReference
+	if sl.cache == nil {
+		return nil, nil, 0, from, to, errors.New("slicer cache not found")
+	}
+	tableID, columnID, ok := sl.cache.tableSource()
+	if !ok {
+		return nil, nil, 0, from, to, errors.New("slicer doesn't filter a table")
+	}
+	i := 0
+	for _, s := range sl.wb.AllSheets() {
+		if s._bbbe.TableParts == nil {
+			continue
+		}
+		for range s._bbbe.TableParts.TablePart {
+			if i >= len(sl.wb._eeegg) {
+				break
+			}
+			t := sl.wb._eeegg[i]
+			i++
+			if t.IdAttr != tableID || t.TableColumns == nil {
+				continue
+			}
+			for col, tc := range t.TableColumns.TableColumn {
+				if tc.IdAttr != columnID {
+					continue
+				}
+				from, to, err := reference.ParseRangeReference(t.RefAttr)
+				if err != nil {
+					return nil, nil, 0, from, to, fmt.Errorf("invalid table reference: %w", err)
+				}
+				if t.HeaderRowCountAttr == nil {
+					from.RowIdx++
+				} else {
+					from.RowIdx += *t.HeaderRowCountAttr
+				}
+				if t.TotalsRowCountAttr != nil {
+					to.RowIdx -= *t.TotalsRowCountAttr
+				}
+				sheet := s
+				return &sheet, t, uint32(col), from, to, nil
+			}
+		}
+	}
+	return nil, nil, 0, from, to, fmt.Errorf("table %d not found", tableID)
+}
+
+// Items returns the items of the slicer and whether they are selected. Items
+// of table slicers are the distinct values of the column in ascending order.
+func (sl Slicer) Items() ([]SlicerItem, error) {
+	if sl.part.table {
+		return sl.tableItems()
+	}
+	_, cf, tab, err := sl.pivotSource()
+	if err != nil {
+		return nil, err
+	}
+	ret := []SlicerItem{}
+	if cf.SharedItems == nil {
+		return ret, nil
+	}
+	selected := map[uint32]bool{}
+	if tab.Items != nil {
+		for _, it := range tab.Items.I {
+			selected[it.X] = it.S
+		}
+	}
+	for i, c := range cf.SharedItems.SharedItemsChoice {
+		s, ok := selected[uint32(i)]
+		ret = append(ret, SlicerItem{sharedItemText(c), s || !ok})
+	}
+	return ret, nil
+}
+
+func (sl Slicer) tableItems() ([]SlicerItem, error) {
+	sheet, t, col, from, to, err := sl.tableColumn()
+	if err != nil {
+		return nil, err
+	}
+	rows := map[uint32]*sml.CT_Row{}
+	for _, r := range sheet._bbbe.SheetData.Row {
+		if r.RAttr != nil {
+			rows[*r.RAttr] = r
+		}
+	}
+	colName := reference.IndexToColumn(from.ColumnIdx + col)
+	seen := map[string]bool{}
+	cells := []filterCell{}
+	for r := from.RowIdx; r <= to.RowIdx; r++ {
+		fc := sheet.filterCellAt(rows[r], fmt.Sprintf("%s%d", colName, r))
+		if fc.blank {
+			fc.text = blankSlicerItem
+		}
+		if !seen[fc.text] {
+			seen[fc.text] = true
+			cells = append(cells, fc)
+		}
+	}
+	sort.SliceStable(cells, func(i, j int) bool {
+		a, b := cells[i], cells[j]
+		switch {
+		case a.blank != b.blank:
+			return b.blank
+		case a.isNum && b.isNum:
+			return a.num < b.num
+		case a.isNum != b.isNum:
+			return a.isNum
+		}
+		return strings.ToLower(a.text) < strings.ToLower(b.text)
+	})
+
+	// the selection of a table slicer is the autofilter of the table column
+	var values map[string]bool
+	blank := true
+	if t.AutoFilter != nil {
+		for _, fc := range t.AutoFilter.FilterColumn {
+			if fc.ColIdAttr == col {
+				f := FilterColumn{sl.wb, fc}
+				if f.Type() == FilterTypeValues {
+					values = map[string]bool{}
+					for _, v := range f.Values() {
+						values[v] = true
+					}
+					blank = f.IncludesBlank()
+				}
+			}
+		}
+	}
+	ret := []SlicerItem{}
+	for _, c := range cells {
+		selected := values == nil || values[c.text]
+		if c.blank {
+			selected = values == nil || blank
+		}
+		ret = append(ret, SlicerItem{c.text, selected})
+	}
+	return ret, nil
+}
+
+// pivotSource returns the pivot table a pivot table slicer filters, the
+// cache field of the slicer and its tabular cache data.
+func (sl Slicer) pivotSource() (PivotTable, *sml.CT_CacheField, *xmlTabularSlicer, error) {
+	if sl.cache == nil {
+		return PivotTable{}, nil, nil, errors.New("slicer cache not found")
+	}
+	x := sl.cache.x
+	if x.PivotTables == nil || len(x.PivotTables.PivotTable) == 0 || x.Data == nil || x.Data.Tabular == nil {
+		return PivotTable{}, nil, nil, errors.New("slicer doesn't filter a pivot table")
+	}
+	pt, err := sl.wb.pivotTable(x.PivotTables.PivotTable[0].TabID, x.PivotTables.PivotTable[0].Name)
+	if err != nil {
+		return PivotTable{}, nil, nil, err
+	}
+	_, def, err := pt.cacheDefinition()
+	if err != nil {
+		return PivotTable{}, nil, nil, err
+	}
+	_, cf, err := pt.cacheField(def, x.SourceName)
+	if err != nil {
+		return PivotTable{}, nil, nil, err
+	}
+	return pt, cf, x.Data.Tabular, nil
+}
+
+// pivotTable returns the pivot table with the given name on the sheet with the
+// given sheet ID.
+func (wb *Workbook) pivotTable(tabID uint32, name string) (PivotTable, error) {
+	for _, s := range wb.AllSheets() {
+		if s._abea.SheetIdAttr != tabID {
+			continue
+		}
+		sheet := s
+		pts, err := sheet.PivotTables()
+		if err != nil {
+			return PivotTable{}, err
+		}
+		for _, pt := range pts {
+			if pt.Name() == name {
+				return pt, nil
+			}
+		}
+	}
+	return PivotTable{}, fmt.Errorf("pivot table %s not found", name)
+}
+
+// SelectedItems returns the values of the selected items of the slicer.
+func (sl Slicer) SelectedItems() ([]string, error) {
+	items, err := sl.Items()
+	if err != nil {
+		return nil, err
+	}
+	ret := []string{}
+	for _, it := range items {
+		if it.Selected {
+			ret = append(ret, it.Value)
+		}
+	}
+	return ret, nil
+}
+
+// SetSelectedItems selects the items of the slicer with the given values and
+// clears the selection of the others, no values selects all items. Selecting
+// items of a table slicer sets the autofilter of the table column and hides
+// the rows of the table that don't match it. Pivot tables are filtered by
+// the selection when they are refreshed.
+func (sl Slicer) SetSelectedItems(values ...string) error {
+	items, err := sl.Items()
+	if err != nil {
+		return err
+	}
+	known := map[string]bool{}
+	for _, it := range items {
+		known[it.Value] = true
+	}
+	selected := map[string]bool{}
+	for _, v := range values {
+		if !known[v] {
+			return fmt.Errorf("slicer %s has no item %s", sl.Name(), v)
+		}
+		selected[v] = true
+	}
+	all := len(values) == 0
+
+	if sl.part.table {
+		sheet, t, col, from, to, err := sl.tableColumn()
+		if err != nil {
+			return err
+		}
+		if t.AutoFilter == nil {
+			t.AutoFilter = sml.NewCT_AutoFilter()
+			ref := reference.IndexToColumn(from.ColumnIdx) + strconv.Itoa(int(from.RowIdx-1)) + ":" +
+				reference.IndexToColumn(to.ColumnIdx) + strconv.Itoa(int(to.RowIdx))
+			t.AutoFilter.RefAttr = unioffice.String(ref)
+		}
+		af := AutoFilter{sl.wb, t.AutoFilter}
+		if all {
+			af.RemoveColumn(col)
+		} else {
+			fc := af.Column(col)
+			vals := []string{}
+			for _, v := range values {
+				if v != blankSlicerItem {
+					vals = append(vals, v)
+				}
+			}
+			fc.SetValues(vals)
+			fc.SetBlank(selected[blankSlicerItem])
+		}
+		return sheet.applyAutoFilter(af)
+	}
+
+	_, _, tab, err := sl.pivotSource()
+	if err != nil {
+		return err
+	}
+	tab.Items = &xmlTabularSlicerItems{Count: len(items)}
+	for i, it := range items {
+		tab.Items.I = append(tab.Items.I, &xmlTabularSlicerItem{X: uint32(i), S: all || selected[it.Value]})
+	}
+	sl.cache.dirty = true
+	return nil
+}
+
+// slicerCacheID returns the ID slicers and timelines refer to the pivot cache
+// of the pivot table by, assigning one if needed.
+func (p PivotTable) slicerCacheID() (uint32, error) {
+	zipPath, def, err := p.cacheDefinition()
+	if err != nil {
+		return 0, err
+	}
+	if ext := findExt(def.ExtLst, extPivotCacheID); ext != nil {
+		if n, ok := ext.Any.(*unioffice.XSDAny); ok {
+			if v, ok := anyAttr(n, "pivotCacheId"); ok {
+				if id, err := strconv.ParseUint(v, 10, 32); err == nil {
+					return uint32(id), nil
+				}
+			}
+		}
+	}
+	id := p.x.CacheIdAttr
+	ext := sml.NewCT_Extension()
+	ext.UriAttr = unioffice.String(extPivotCacheID)
+	ext.Any = &unioffice.XSDAny{
+		XMLName: xml.Name{Space: x14Namespace, Local: "pivotCacheDefinition"},
+		Attrs:   []xml.Attr{{Name: xml.Name{Local: "pivotCacheId"}, Value: strconv.FormatUint(uint64(id), 10)}},
+	}
+	if def.ExtLst == nil {
+		def.ExtLst = sml.NewCT_ExtensionList()
+	}
+	def.ExtLst.Ext = append(def.ExtLst.Ext, ext)
+	return id, p.s._fgeg.writeExtraFile(zipPath, def)
+}
+
+// TimelineLevel is the time level a timeline displays.
+type TimelineLevel byte
+
+// TimelineLevel constants.
+const (
+	TimelineLevelYears TimelineLevel = iota
+	TimelineLevelQuarters
+	TimelineLevelMonths
+	TimelineLevelDays
+)
+
+// Timeline is a timeline filtering a date field of a pivot table.
+type Timeline struct {
+	wb    *Workbook
+	part  *timelinePart
+	cache *timelineCachePart
+	x     *xmlTimeline
+}
+
+// Timelines returns the timelines on the sheet.
+func (s *Sheet) Timelines() ([]Timeline, error) {
+	ss, err := s.sheetSlicers()
+	if err != nil {
+		return nil, err
+	}
+	st, _ := s._fgeg.slicerState()
+	ret := []Timeline{}
+	for _, p := range ss.timelines {
+		for _, x := range p.x.Timeline {
+			tl := Timeline{wb: s._fgeg, part: p, x: x}
+			for _, c := range st.timelineCaches {
+				if c.x.Name == x.Cache {
+					tl.cache = c
+				}
+			}
+			ret = append(ret, tl)
+		}
+	}
+	return ret, nil
+}
+
+// AddTimeline adds a timeline filtering a date field of a pivot table to the
+// sheet. The timeline covers the anchor cells (e.g. "H2:M8"), or a default
+// size from the anchor cell, and displays months.
+func (s *Sheet) AddTimeline(pivot PivotTable, field, anchor string) (Timeline, error) {
+	wb := s._fgeg
+	rels, ok := s.rels()
+	if !ok {
+		return Timeline{}, errors.New("sheet not found in workbook")
+	}
+	ss, err := s.sheetSlicers()
+	if err != nil {
+		return Timeline{}, err
+	}
+	st, _ := wb.slicerState()
+	_, def, err := pivot.cacheDefinition()
+	if err != nil {
+		return Timeline{}, err
+	}
+	_, cf, err := pivot.cacheField(def, field)
+	if err != nil {
+		return Timeline{}, err
+	}
+	min, max, ok := isDateField(cf)
+	if !ok {
+		return Timeline{}, fmt.Errorf("field %s of pivot table %s isn't a date field", field, pivot.Name())
+	}
+	id, err := pivot.slicerCacheID()
+	if err != nil {
+		return Timeline{}, err
+	}
+	name, err := wb.slicerName(field)
+	if err != nil {
+		return Timeline{}, err
+	}
+
+	cache := &xmlTimelineCacheDefinition{
+		Name:        wb.slicerIdentifier("NativeTimeline_", field),
+		SourceName:  field,
+		PivotTables: &xmlSlicerCachePivots{PivotTable: []*xmlSlicerCachePivot{{TabID: pivot.s._abea.SheetIdAttr, Name: pivot.Name()}}},
+		State: &xmlTimelineState{
+			MinimalRefreshVersion: 6,
+			LastRefreshVersion:    6,
+			PivotCacheID:          id,
+			FilterType:            "unknown",
+			Bounds: &xmlTimelineRange{
+				StartDate: time.Date(min.Year(), 1, 1, 0, 0, 0, 0, time.UTC).Format(timelineDateLayout),
+				EndDate:   time.Date(max.Year()+1, 1, 1, 0, 0, 0, 0, time.UTC).Format(timelineDateLayout),
+			},
+		},
+	}
+	cachePath, n := wb.nextPartPath("xl/timelineCaches/timelineCache%d.xml")
+	cp := &timelineCachePart{path: cachePath, dirty: true, x: cache}
+	if err := wb.writeExtraFile(cp.path, cp.x); err != nil {
+		return Timeline{}, err
+	}
+	st.timelineCaches = append(st.timelineCaches, cp)
+	wbRel := wb._bcg.AddRelationship(fmt.Sprintf("timelineCaches/timelineCache%d.xml", n), timelineCacheType)
+	wb.ContentTypes.AddOverride("/"+cachePath, timelineCacheContentType)
+	addExtRef(&wb._gbadf.ExtLst, extTimelineCaches, xml.Name{Space: x15Namespace, Local: "timelineCacheRefs"},
+		xml.Name{Space: x15Namespace, Local: "timelineCacheRef"}, wbRel.ID())
+	wb.AddDefinedName(cache.Name, "#N/A")
+
+	var part *timelinePart
+	if len(ss.timelines) > 0 {
+		part = ss.timelines[0]
+	} else {
+		partPath, n := wb.nextPartPath("xl/timelines/timeline%d.xml")
+		part = &timelinePart{path: partPath, x: &xmlTimelines{}}
+		rel := rels.AddRelationship(fmt.Sprintf("../timelines/timeline%d.xml", n), timelineType)
+		wb.ContentTypes.AddOverride("/"+partPath, timelineContentType)
+		addExtRef(&s._bbbe.ExtLst, extTimelineList, xml.Name{Space: x15Namespace, Local: "timelineRefs"},
+			xml.Name{Space: x15Namespace, Local: "timelineRef"}, rel.ID())
+		ss.timelines = append(ss.timelines, part)
+	}
+	x := &xmlTimeline{
+		Name:           name,
+		Cache:          cache.Name,
+		Caption:        field,
+		Level:          uint32(TimelineLevelMonths),
+		SelectionLevel: uint32(TimelineLevelMonths),
+		ScrollPosition: cache.State.Bounds.StartDate,
+	}
+	part.x.Timeline = append(part.x.Timeline, x)
+	part.dirty = true
+	if err := wb.writeExtraFile(part.path, part.x); err != nil {
+		return Timeline{}, err
+	}
+
+	el := &unioffice.XSDAny{
+		XMLName: xml.Name{Space: timelineDrawingURI, Local: "timeslicer"},
+		Attrs:   []xml.Attr{{Name: xml.Name{Local: "name"}, Value: name}},
+	}
+	if err := s.addDrawingFrame(anchor, name, timelineDrawingURI, el); err != nil {
+		return Timeline{}, err
+	}
+	return Timeline{wb, part, cp, x}, nil
+}
+
+// Name returns the name of the timeline.
+func (tl Timeline) Name() string { return tl.x.Name }
+
+// CacheName returns the name of the timeline cache, the defined name the
+// timeline is referred to by in formulas.
+func (tl Timeline) CacheName() string { return tl.x.Cache }
+
+// SourceName returns the name of the pivot table field the timeline filters
+// by.
+func (tl Timeline) SourceName() string {
+	if tl.cache == nil {
+		return ""
+	}
+	return tl.cache.x.SourceName
+}
+
+// Caption returns the caption displayed in the header of the timeline.
+func (tl Timeline) Caption() string { return tl.x.Caption }
+
+// SetCaption sets the caption displayed in the header of the timeline.
+func (tl Timeline) SetCaption(caption string) {
+	tl.x.Caption = caption
+	tl.part.dirty = true
+}
+
+// Style returns the name of the timeline style, e.g. TimeSlicerStyleLight1,
+// or an empty string for the default style.
+func (tl Timeline) Style() string { return tl.x.Style }
+
+// SetStyle sets the timeline style, e.g. TimeSlicerStyleDark1.
+func (tl Timeline) SetStyle(style string) {
+	tl.x.Style = style
+	tl.part.dirty = true
+}
+
+// Level returns the time level the timeline displays.
+func (tl Timeline) Level() TimelineLevel { return TimelineLevel(tl.x.Level) }
+
+// SetLevel sets the time level the timeline displays.
+func (tl Timeline) SetLevel(l TimelineLevel) {
+	tl.x.Level = uint32(l)
+	tl.part.dirty = true
+}
+
+// Selection returns the selected date range of the timeline, ok is false if
+// all dates are selected.
+func (tl Timeline) Selection() (start, end time.Time, ok bool) {
+	if tl.cache == nil || tl.cache.x.State == nil || tl.cache.x.State.Selection == nil {
+		return start, end, false
+	}
+	sel := tl.cache.x.State.Selection
+	start, err1 := time.Parse(timelineDateLayout, sel.StartDate)
+	end, err2 := time.Parse(timelineDateLayout, sel.EndDate)
+	return start, end, err1 == nil && err2 == nil
+}
+
+// SetSelection selects the dates from start up to and including end at the
+// level the timeline displays. Pivot tables are filtered by the selection
+// when they are refreshed.
+func (tl Timeline) SetSelection(start, end time.Time) error {
+	if tl.cache == nil || tl.cache.x.State == nil {
+		return errors.New("timeline cache not found")
+	}
+	if end.Before(start) {
+		start, end = end, start
+	}
+	state := tl.cache.x.State
+	state.FilterType = "dateBetween"
+	state.SingleRangeFilterState = unioffice.Bool(true)
+	state.Selection = &xmlTimelineRange{StartDate: start.Format(timelineDateLayout), EndDate: end.Format(timelineDateLayout)}
+	tl.x.SelectionLevel = tl.x.Level
+	tl.cache.dirty = true
+	tl.part.dirty = true
+	return nil
+}
+
+// ClearSelection selects all dates.
+func (tl Timeline) ClearSelection() {
+	if tl.cache == nil || tl.cache.x.State == nil {
+		return
+	}
+	state := tl.cache.x.State
+	state.FilterType = "unknown"
+	state.SingleRangeFilterState = nil
+	state.Selection = nil
+	tl.cache.dirty = true
+}
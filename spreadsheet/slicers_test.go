@@ -0,0 +1,364 @@
+package spreadsheet
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yaklabco/unioffice/v2"
+	sd "github.com/yaklabco/unioffice/v2/schema/soo/dml/spreadsheetDrawing"
+	"github.com/yaklabco/unioffice/v2/schema/soo/sml"
+)
+
+// addTestTable adds a table of regions and sales on A1:B6 to the sheet.
+func addTestTable(wb *Workbook, sheet Sheet) Table {
+	for i, row := range [][]string{{"Region", "Sales"}, {"West", "1"}, {"East", "2"}, {"North", "3"}, {"East", "4"}, {"West", "5"}} {
+		r := sheet.Row(uint32(i + 1))
+		r.Cell("A").SetString(row[0])
+		if i == 0 {
+			r.Cell("B").SetString(row[1])
+		} else {
+			r.Cell("B").SetNumberWithStyle(float64(i), 0)
+		}
+	}
+	t := sml.NewTable()
+	t.IdAttr = 1
+	t.NameAttr = unioffice.String("Sales")
+	t.DisplayNameAttr = "Sales"
+	t.RefAttr = "A1:B6"
+	t.TableColumns = sml.NewCT_TableColumns()
+	t.TableColumns.TableColumn = []*sml.CT_TableColumn{{IdAttr: 1, NameAttr: "Region"}, {IdAttr: 2, NameAttr: "Sales"}}
+	wb._eeegg = append(wb._eeegg, t)
+	rels, _ := sheet.rels()
+	rel := rels.AddAutoRelationship(unioffice.DocTypeSpreadsheet, unioffice.WorksheetType, len(wb._eeegg), unioffice.TableType)
+	wb.ContentTypes.AddOverride(unioffice.AbsoluteFilename(unioffice.DocTypeSpreadsheet, unioffice.TableContentType, len(wb._eeegg)), unioffice.TableContentType)
+	sheet._bbbe.TableParts = sml.NewCT_TableParts()
+	sheet._bbbe.TableParts.TablePart = []*sml.CT_TablePart{{IdAttr: rel.ID()}}
+	return Table{t}
+}
+
+// addTestPivotTable adds a pivot table summarizing regions and dates to the
+// sheet.
+func addTestPivotTable(t *testing.T, wb *Workbook, sheet Sheet) PivotTable {
+	def := sml.NewPivotCacheDefinition()
+	def.CacheFields = sml.NewCT_CacheFields()
+	region := sml.NewCT_CacheField()
+	region.NameAttr = "Region"
+	region.SharedItems = sml.NewCT_SharedItems()
+	for _, v := range []string{"West", "East", "North"} {
+		region.SharedItems.SharedItemsChoice = append(region.SharedItems.SharedItemsChoice, &sml.CT_SharedItemsChoice{S: &sml.CT_String{VAttr: v}})
+	}
+	date := sml.NewCT_CacheField()
+	date.NameAttr = "Date"
+	date.SharedItems = sml.NewCT_SharedItems()
+	date.SharedItems.ContainsDateAttr = unioffice.Bool(true)
+	min, max := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	date.SharedItems.MinDateAttr, date.SharedItems.MaxDateAttr = &min, &max
+	def.CacheFields.CacheField = []*sml.CT_CacheField{region, date}
+	if err := wb.writeExtraFile("xl/pivotCache/pivotCacheDefinition1.xml", def); err != nil {
+		t.Fatalf("error writing pivot cache: %s", err)
+	}
+	r := wb._bcg.AddRelationship("pivotCache/pivotCacheDefinition1.xml", pivotCacheDefinitionType)
+	wb._gbadf.PivotCaches = sml.NewCT_PivotCaches()
+	wb._gbadf.PivotCaches.PivotCache = []*sml.CT_PivotCache{{CacheIdAttr: 5, IdAttr: r.ID()}}
+
+	pt := sml.NewPivotTableDefinition()
+	pt.NameAttr = "PivotTable1"
+	pt.CacheIdAttr = 5
+	if err := wb.writeExtraFile("xl/pivotTables/pivotTable1.xml", pt); err != nil {
+		t.Fatalf("error writing pivot table: %s", err)
+	}
+	rels, _ := sheet.rels()
+	rels.AddRelationship("../pivotTables/pivotTable1.xml", pivotTableType)
+	pts, err := sheet.PivotTables()
+	if err != nil || len(pts) != 1 {
+		t.Fatalf("expected a pivot table, got %d %v", len(pts), err)
+	}
+	return pts[0]
+}
+
+func TestTableSlicer(t *testing.T) {
+	wb := New()
+	sheet := wb.AddSheet()
+	table := addTestTable(wb, sheet)
+
+	if _, err := sheet.AddSlicer(table, "Country", "E2"); err == nil {
+		t.Errorf("expected an error for a missing column")
+	}
+	sl, err := sheet.AddSlicer(table, "Region", "E2")
+	if err != nil {
+		t.Fatalf("error adding slicer: %s", err)
+	}
+	if sl.Name() != "Region" || sl.CacheName() != "Slicer_Region" || !sl.IsTableSlicer() {
+		t.Errorf("unexpected slicer %s %s", sl.Name(), sl.CacheName())
+	}
+	items, err := sl.Items()
+	if err != nil {
+		t.Fatalf("error reading items: %s", err)
+	}
+	if len(items) != 3 || items[0].Value != "East" || items[2].Value != "West" || !items[1].Selected {
+		t.Errorf("unexpected items %v", items)
+	}
+	if err := sl.SetSelectedItems("Central"); err == nil {
+		t.Errorf("expected an error for a missing item")
+	}
+	if err := sl.SetSelectedItems("East"); err != nil {
+		t.Fatalf("error selecting items: %s", err)
+	}
+	if sel, _ := sl.SelectedItems(); len(sel) != 1 || sel[0] != "East" {
+		t.Errorf("expected East to be selected, got %v", sel)
+	}
+	if !sheet.Row(2).IsHidden() || sheet.Row(3).IsHidden() || !sheet.Row(4).IsHidden() {
+		t.Errorf("expected the rows not in the east to be hidden")
+	}
+	if err := sl.SetSelectedItems(); err != nil {
+		t.Fatalf("error selecting items: %s", err)
+	}
+	if sheet.Row(2).IsHidden() {
+		t.Errorf("expected all rows to be visible")
+	}
+	sl.SetStyle("SlicerStyleDark2")
+	sl.SetColumnCount(2)
+
+	second, err := sheet.AddSlicer(table, "Region", "H2:I10")
+	if err != nil {
+		t.Fatalf("error adding slicer: %s", err)
+	}
+	if second.Name() != "Region 1" || second.CacheName() != "Slicer_Region1" {
+		t.Errorf("expected unique names, got %s %s", second.Name(), second.CacheName())
+	}
+	names := []string{}
+	for _, dn := range wb.DefinedNames() {
+		names = append(names, dn.Name()+"="+dn.Content())
+	}
+	if strings.Join(names, ",") != "Slicer_Region=#N/A,Slicer_Region1=#N/A" {
+		t.Errorf("unexpected defined names %v", names)
+	}
+	if refs := extRefs(wb._gbadf.ExtLst, extTableSlicerCaches); len(refs) != 2 {
+		t.Errorf("expected two slicer cache references, got %v", refs)
+	}
+	if refs := extRefs(sheet._bbbe.ExtLst, extTableSlicerList); len(refs) != 1 {
+		t.Errorf("expected one slicer part reference, got %v", refs)
+	}
+	dr, ok := sheet.Drawing()
+	if !ok || len(dr.X().EG_Anchor) != 2 {
+		t.Fatalf("expected two anchors on the drawing")
+	}
+	if gf := dr.X().EG_Anchor[1].AnchorChoice.TwoCellAnchor.ObjectChoicesChoice.GraphicFrame; gf.Graphic.GraphicData.UriAttr != slicerDrawingURI {
+		t.Errorf("unexpected graphic data %s", gf.Graphic.GraphicData.UriAttr)
+	}
+
+	// reread the parts as they are written
+	if err := wb.flushSlicers(); err != nil {
+		t.Fatalf("error writing slicers: %s", err)
+	}
+	wb._slicers = nil
+	slicers, err := sheet.Slicers()
+	if err != nil {
+		t.Fatalf("error reading slicers: %s", err)
+	}
+	if len(slicers) != 2 || slicers[0].Style() != "SlicerStyleDark2" || slicers[0].ColumnCount() != 2 ||
+		slicers[0].SourceName() != "Region" || !slicers[0].IsTableSlicer() {
+		t.Errorf("unexpected slicers after reading %v", slicers)
+	}
+}
+
+func TestTableSlicerHiddenSheet(t *testing.T) {
+	wb := New()
+	hidden := wb.AddSheet()
+	other := addTestTable(wb, hidden)
+	other.X().IdAttr = 2
+	other.X().DisplayNameAttr = "Hidden"
+	sheet := wb.AddSheet()
+	table := addTestTable(wb, sheet)
+	if _, err := hidden.AddSlicer(other, "Region", "E2"); err != nil {
+		t.Fatalf("error adding slicer: %s", err)
+	}
+	if err := hidden.SetState(sml.ST_SheetStateHidden); err != nil {
+		t.Fatalf("error hiding sheet: %s", err)
+	}
+
+	sl, err := sheet.AddSlicer(table, "Region", "E2")
+	if err != nil {
+		t.Fatalf("error adding slicer: %s", err)
+	}
+	if sl.Name() != "Region 1" {
+		t.Errorf("expected a name unique among all sheets, got %s", sl.Name())
+	}
+	if err := sl.SetSelectedItems("East"); err != nil {
+		t.Fatalf("error selecting items: %s", err)
+	}
+	if !sheet.Row(2).IsHidden() || sheet.Row(3).IsHidden() || hidden.Row(2).IsHidden() {
+		t.Errorf("expected the rows of the filtered table to be hidden")
+	}
+}
+
+func TestPivotSlicerAndTimeline(t *testing.T) {
+	wb := New()
+	sheet := wb.AddSheet()
+	pivot := addTestPivotTable(t, wb, sheet)
+
+	sl, err := sheet.AddSlicer(pivot, "Region", "E2")
+	if err != nil {
+		t.Fatalf("error adding slicer: %s", err)
+	}
+	if err := sl.SetSelectedItems("North", "West"); err != nil {
+		t.Fatalf("error selecting items: %s", err)
+	}
+	if _, err := sheet.AddTimeline(pivot, "Region", "H2"); err == nil {
+		t.Errorf("expected an error for a field without dates")
+	}
+	tl, err := sheet.AddTimeline(pivot, "Date", "H2:M8")
+	if err != nil {
+		t.Fatalf("error adding timeline: %s", err)
+	}
+	tl.SetStyle("TimeSlicerStyleDark1")
+	tl.SetLevel(TimelineLevelQuarters)
+	start, end := time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC), time.Date(2023, 9, 30, 0, 0, 0, 0, time.UTC)
+	if err := tl.SetSelection(start, end); err != nil {
+		t.Fatalf("error selecting dates: %s", err)
+	}
+	if refs := extRefs(wb._gbadf.ExtLst, extPivotSlicerCaches); len(refs) != 1 {
+		t.Errorf("expected a pivot slicer cache reference, got %v", refs)
+	}
+	if refs := extRefs(wb._gbadf.ExtLst, extTimelineCaches); len(refs) != 1 {
+		t.Errorf("expected a timeline cache reference, got %v", refs)
+	}
+	if _, def, _ := pivot.cacheDefinition(); findExt(def.ExtLst, extPivotCacheID) == nil {
+		t.Errorf("expected the pivot cache to be given a slicer cache ID")
+	}
+
+	if err := wb.flushSlicers(); err != nil {
+		t.Fatalf("error writing slicers: %s", err)
+	}
+	wb._slicers = nil
+	slicers, err := sheet.Slicers()
+	if err != nil || len(slicers) != 1 {
+		t.Fatalf("expected a slicer, got %d %v", len(slicers), err)
+	}
+	if slicers[0].IsTableSlicer() {
+		t.Errorf("expected a pivot table slicer")
+	}
+	items, err := slicers[0].Items()
+	if err != nil {
+		t.Fatalf("error reading items: %s", err)
+	}
+	if len(items) != 3 || !items[0].Selected || items[1].Selected || !items[2].Selected {
+		t.Errorf("unexpected items %v", items)
+	}
+	timelines, err := sheet.Timelines()
+	if err != nil || len(timelines) != 1 {
+		t.Fatalf("expected a timeline, got %d %v", len(timelines), err)
+	}
+	tl = timelines[0]
+	if tl.Name() != "Date" || tl.CacheName() != "NativeTimeline_Date" || tl.Style() != "TimeSlicerStyleDark1" ||
+		tl.Level() != TimelineLevelQuarters || tl.SourceName() != "Date" {
+		t.Errorf("unexpected timeline %s %s %s", tl.Name(), tl.CacheName(), tl.Style())
+	}
+	if s, e, ok := tl.Selection(); !ok || !s.Equal(start) || !e.Equal(end) {
+		t.Errorf("unexpected selection %s %s", s, e)
+	}
+	tl.ClearSelection()
+	if _, _, ok := tl.Selection(); ok {
+		t.Errorf("expected the selection to be cleared")
+	}
+}
+
+// testSlicerDrawing is a drawing with a slicer as written by Excel, in an
+// alternate content block with a fallback shape.
+const testSlicerDrawing = `<xdr:wsDr xmlns:xdr="http://schemas.openxmlformats.org/drawingml/2006/spreadsheetDrawing" xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main">
+<mc:AlternateContent xmlns:mc="http://schemas.openxmlformats.org/markup-compatibility/2006">
+<mc:Choice xmlns:sle15="http://schemas.microsoft.com/office/drawing/2012/slicer" Requires="sle15">
+<xdr:twoCellAnchor editAs="oneCell"><xdr:from><xdr:col>4</xdr:col><xdr:colOff>0</xdr:colOff><xdr:row>1</xdr:row><xdr:rowOff>0</xdr:rowOff></xdr:from>
+<xdr:to><xdr:col>7</xdr:col><xdr:colOff>0</xdr:colOff><xdr:row>15</xdr:row><xdr:rowOff>0</xdr:rowOff></xdr:to>
+<xdr:graphicFrame macro=""><xdr:nvGraphicFramePr><xdr:cNvPr id="2" name="Region"/><xdr:cNvGraphicFramePr/></xdr:nvGraphicFramePr>
+<xdr:xfrm><a:off x="0" y="0"/><a:ext cx="0" cy="0"/></xdr:xfrm>
+<a:graphic><a:graphicData uri="http://schemas.microsoft.com/office/drawing/2010/slicer"><sle:slicer xmlns:sle="http://schemas.microsoft.com/office/drawing/2010/slicer" name="Region"/></a:graphicData></a:graphic>
+</xdr:graphicFrame><xdr:clientData/></xdr:twoCellAnchor>
+</mc:Choice>
+<mc:Fallback><xdr:twoCellAnchor editAs="oneCell"><xdr:from><xdr:col>4</xdr:col><xdr:colOff>0</xdr:colOff><xdr:row>1</xdr:row><xdr:rowOff>0</xdr:rowOff></xdr:from>
+<xdr:to><xdr:col>7</xdr:col><xdr:colOff>0</xdr:colOff><xdr:row>15</xdr:row><xdr:rowOff>0</xdr:rowOff></xdr:to>
+<xdr:sp macro="" textlink=""><xdr:nvSpPr><xdr:cNvPr id="0" name=""/><xdr:cNvSpPr/></xdr:nvSpPr><xdr:spPr/></xdr:sp><xdr:clientData/></xdr:twoCellAnchor></mc:Fallback>
+</mc:AlternateContent>
+</xdr:wsDr>`
+
+func TestSlicerDrawingRoundTrip(t *testing.T) {
+	src := testSlicerDrawing
+	dr := sd.NewWsDr()
+	if err := xml.Unmarshal([]byte(src), dr); err != nil {
+		t.Fatalf("error decoding drawing: %s", err)
+	}
+	if len(dr.EG_Anchor) != 1 {
+		t.Fatalf("expected the chosen anchor, got %d anchors", len(dr.EG_Anchor))
+	}
+	a := dr.EG_Anchor[0].AnchorChoice.TwoCellAnchor
+	if a == nil || a.ObjectChoicesChoice.GraphicFrame == nil {
+		t.Fatalf("expected the slicer graphic frame to be kept")
+	}
+	buf := bytes.Buffer{}
+	if err := xml.NewEncoder(&buf).Encode(dr); err != nil {
+		t.Fatalf("error encoding drawing: %s", err)
+	}
+	if !strings.Contains(buf.String(), `name="Region"`) || !strings.Contains(buf.String(), slicerDrawingURI) {
+		t.Errorf("expected the slicer to be written, got %s", buf.String())
+	}
+	if !strings.Contains(buf.String(), `Requires="sle15"`) || !strings.Contains(buf.String(), "<mc:Fallback><xdr:sp") {
+		t.Errorf("expected the fallback to be written, got %s", buf.String())
+	}
+
+	// the fallback survives another round trip
+	dr = sd.NewWsDr()
+	if err := xml.Unmarshal(buf.Bytes(), dr); err != nil {
+		t.Fatalf("error decoding drawing: %s", err)
+	}
+	obj := dr.EG_Anchor[0].AnchorChoice.TwoCellAnchor.ObjectChoicesChoice
+	if len(dr.EG_Anchor) != 1 || obj.GraphicFrame == nil || obj.AlternateContent == nil || obj.AlternateContent.Fallback.Sp == nil {
+		t.Errorf("expected the slicer with its fallback after reading it back")
+	}
+}
+
+func TestSlicerSaveRead(t *testing.T) {
+	wb := New()
+	sheet := wb.AddSheet()
+	table := addTestTable(wb, sheet)
+	sl, err := sheet.AddSlicer(table, "Region", "E2")
+	if err != nil {
+		t.Fatalf("error adding slicer: %s", err)
+	}
+	sl.SetCaption("Regions")
+	if err := sl.SetSelectedItems("East"); err != nil {
+		t.Fatalf("error selecting items: %s", err)
+	}
+
+	// replace the anchor with the one Excel writes
+	dr, _ := sheet.Drawing()
+	if err := xml.Unmarshal([]byte(testSlicerDrawing), dr.X()); err != nil {
+		t.Fatalf("error decoding drawing: %s", err)
+	}
+
+	wb = saveAndRead(t, wb)
+	sheet = wb.Sheets()[0]
+	slicers, err := sheet.Slicers()
+	if err != nil {
+		t.Fatalf("error reading slicers: %s", err)
+	}
+	if len(slicers) != 1 || slicers[0].Name() != "Region" || slicers[0].Caption() != "Regions" || !slicers[0].IsTableSlicer() {
+		t.Fatalf("unexpected slicers after reading %v", slicers)
+	}
+	if sel, err := slicers[0].SelectedItems(); err != nil || len(sel) != 1 || sel[0] != "East" {
+		t.Errorf("expected East to be selected, got %v %v", sel, err)
+	}
+	dr, ok := sheet.Drawing()
+	if !ok || len(dr.X().EG_Anchor) != 1 {
+		t.Fatalf("expected the slicer anchor on the drawing")
+	}
+	obj := dr.X().EG_Anchor[0].AnchorChoice.TwoCellAnchor.ObjectChoicesChoice
+	if gf := obj.GraphicFrame; gf == nil || gf.Graphic.GraphicData.UriAttr != slicerDrawingURI {
+		t.Errorf("expected the slicer graphic frame to be read")
+	}
+	if obj.AlternateContent == nil || obj.AlternateContent.Requires != "sle15" || obj.AlternateContent.Fallback.Sp == nil {
+		t.Errorf("expected the fallback shape to be kept")
+	}
+}
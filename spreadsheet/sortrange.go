@@ -0,0 +1,315 @@
+package spreadsheet
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	unioffice "github.com/yaklabco/unioffice/v2"
+	"github.com/yaklabco/unioffice/v2/schema/soo/sml"
+	"github.com/yaklabco/unioffice/v2/spreadsheet/formula"
+	"github.com/yaklabco/unioffice/v2/spreadsheet/reference"
+)
+
+// SortBy is the property of a cell that a sort key compares.
+type SortBy byte
+
+// SortBy constants.
+const (
+	SortByValue SortBy = iota
+	SortByCellColor
+	SortByFontColor
+)
+
+// SortKey is a single key of a multi-key sort.
+type SortKey struct {
+	// Column is the column to sort by, e.g. "B". It must be within the
+	// sorted range.
+	Column string
+	Order  SortOrder
+
+	// CaseSensitive sorts lowercase text before uppercase text that otherwise
+	// compares equal.
+	CaseSensitive bool
+
+	// CustomList orders text matching one of its entries (case insensitive)
+	// by the position in the list, e.g. Jan, Feb, Mar. Other values sort after
+	// the list entries.
+	CustomList []string
+
+	// By selects sorting by value or by color. When sorting by color, cells
+	// whose fill or font color matches Color are placed on top for
+	// SortOrderAscending and at the bottom for SortOrderDescending.
+	By    SortBy
+	Color DifferentialStyle
+}
+
+// SortOptions are the options for SortRangeWithOptions.
+type SortOptions struct {
+	// HasHeader excludes the first row of the range from sorting.
+	HasHeader bool
+
+	// MoveFormulas adjusts the relative references of formulas in the sorted
+	// range by the distance their row is moved, as Excel does.
+	MoveFormulas bool
+}
+
+// SortRange sorts the rows of a range by one or more keys. Only the cells
+// within the range are moved, and the sort is recorded in the sheet so Excel
+// displays the sort indicator.
+func (s *Sheet) SortRange(ref string, keys []SortKey) error {
+	return s.SortRangeWithOptions(ref, keys, SortOptions{})
+}
+
+// sortEntry is a row of the sorted range along with its cells.
+type sortEntry struct {
+	row   uint32
+	cells []*sml.CT_Cell
+	x     *sml.CT_Row
+}
+
+// SortRangeWithOptions sorts the rows of a range by one or more keys,
+// see SortRange.
+func (s *Sheet) SortRangeWithOptions(ref string, keys []SortKey, opts SortOptions) error {
+	if len(keys) == 0 {
+		return errors.New("at least one sort key is required")
+	}
+	from, to, err := reference.ParseRangeReference(strings.ReplaceAll(ref, "$", ""))
+	if err != nil {
+		return fmt.Errorf("invalid sort range: %w", err)
+	}
+	first := from.RowIdx
+	if opts.HasHeader {
+		first++
+	}
+	if first > to.RowIdx {
+		return nil
+	}
+	keyCols := make([]uint32, len(keys))
+	for i, k := range keys {
+		c, err := reference.ParseColumnReference(strings.ReplaceAll(k.Column, "$", ""))
+		if err != nil {
+			return fmt.Errorf("invalid sort key column %q: %w", k.Column, err)
+		}
+		if c.ColumnIdx < from.ColumnIdx || c.ColumnIdx > to.ColumnIdx {
+			return fmt.Errorf("sort key column %s is outside of %s", k.Column, ref)
+		}
+		keyCols[i] = c.ColumnIdx - from.ColumnIdx
+	}
+
+	rows := map[uint32]*sml.CT_Row{}
+	for _, r := range s._bbbe.SheetData.Row {
+		if r.RAttr != nil {
+			rows[*r.RAttr] = r
+		}
+	}
+	width := int(to.ColumnIdx-from.ColumnIdx) + 1
+	entries := []sortEntry{}
+	for r := first; r <= to.RowIdx; r++ {
+		e := sortEntry{row: r, cells: make([]*sml.CT_Cell, width), x: rows[r]}
+		if e.x != nil {
+			kept := e.x.C[:0]
+			for _, c := range e.x.C {
+				cr, err := reference.ParseCellReference(*c.RAttr)
+				if err == nil && cr.ColumnIdx >= from.ColumnIdx && cr.ColumnIdx <= to.ColumnIdx {
+					e.cells[cr.ColumnIdx-from.ColumnIdx] = c
+					continue
+				}
+				kept = append(kept, c)
+			}
+			e.x.C = kept
+		}
+		entries = append(entries, e)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		for k, key := range keys {
+			lhs := s.sortCell(entries[i].x, entries[i].cells[keyCols[k]])
+			rhs := s.sortCell(entries[j].x, entries[j].cells[keyCols[k]])
+			if c := compareSortCells(key, lhs, rhs); c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+
+	for i, e := range entries {
+		target := first + uint32(i)
+		rowX := rows[target]
+		for j, c := range e.cells {
+			if c == nil {
+				continue
+			}
+			c.RAttr = unioffice.String(fmt.Sprintf("%s%d", reference.IndexToColumn(from.ColumnIdx+uint32(j)), target))
+			if opts.MoveFormulas && c.F != nil && c.F.Content != "" && target != e.row {
+				c.F.Content = formula.ShiftReferences(c.F.Content, int(target)-int(e.row), 0)
+			}
+			if rowX == nil {
+				rowX = s.Row(target).X()
+				rows[target] = rowX
+			}
+			rowX.C = append(rowX.C, c)
+		}
+		if rowX != nil {
+			sortRowCells(rowX)
+		}
+	}
+	s.recordSortState(from, to, first, keys)
+	return nil
+}
+
+// sortRowCells orders the cells of a row by column.
+func sortRowCells(r *sml.CT_Row) {
+	sort.SliceStable(r.C, func(i, j int) bool {
+		ci, _ := reference.ParseCellReference(*r.C[i].RAttr)
+		cj, _ := reference.ParseCellReference(*r.C[j].RAttr)
+		return ci.ColumnIdx < cj.ColumnIdx
+	})
+}
+
+func (s *Sheet) recordSortState(from, to reference.CellReference, first uint32, keys []SortKey) {
+	st := sml.NewCT_SortState()
+	st.RefAttr = fmt.Sprintf("%s%d:%s%d", from.Column, first, to.Column, to.RowIdx)
+	for _, k := range keys {
+		col := strings.ToUpper(strings.ReplaceAll(k.Column, "$", ""))
+		sc := sml.NewCT_SortCondition()
+		sc.RefAttr = fmt.Sprintf("%s%d:%s%d", col, first, col, to.RowIdx)
+		if k.Order == SortOrderDescending {
+			sc.DescendingAttr = unioffice.Bool(true)
+		}
+		if len(k.CustomList) > 0 {
+			sc.CustomListAttr = unioffice.String(strings.Join(k.CustomList, ","))
+		}
+		switch k.By {
+		case SortByCellColor:
+			sc.SortByAttr = sml.ST_SortByCellColor
+		case SortByFontColor:
+			sc.SortByAttr = sml.ST_SortByFontColor
+		}
+		if k.By != SortByValue && k.Color.X() != nil {
+			sc.DxfIdAttr = unioffice.Uint32(k.Color.Index())
+		}
+		if k.CaseSensitive {
+			st.CaseSensitiveAttr = unioffice.Bool(true)
+		}
+		st.SortCondition = append(st.SortCondition, sc)
+	}
+	// a sort of the autofilter range is stored with the autofilter
+	if af := s._bbbe.AutoFilter; af != nil && af.RefAttr != nil {
+		if afFrom, afTo, err := reference.ParseRangeReference(*af.RefAttr); err == nil &&
+			afFrom.ColumnIdx <= from.ColumnIdx && afTo.ColumnIdx >= to.ColumnIdx &&
+			afFrom.RowIdx <= from.RowIdx && afTo.RowIdx >= to.RowIdx {
+			af.SortState = st
+			return
+		}
+	}
+	s._bbbe.SortState = st
+}
+
+// sortCellKind is the kind of a value in the order Excel sorts them.
+type sortCellKind byte
+
+const (
+	sortCellNumber sortCellKind = iota
+	sortCellText
+	sortCellBool
+	sortCellError
+	sortCellBlank
+)
+
+type sortCellValue struct {
+	kind sortCellKind
+	num  float64
+	text string
+	cell Cell
+}
+
+func (s *Sheet) sortCell(row *sml.CT_Row, c *sml.CT_Cell) sortCellValue {
+	if c == nil {
+		return sortCellValue{kind: sortCellBlank}
+	}
+	cell := Cell{s._fgeg, s, row, c}
+	v := sortCellValue{cell: cell}
+	raw, isNum := cell.getRawSortValue()
+	switch {
+	case raw == "" && c.Is == nil:
+		v.kind = sortCellBlank
+	case c.TAttr == sml.ST_CellTypeB:
+		v.kind = sortCellBool
+		v.num, _ = strconv.ParseFloat(raw, 64)
+	case c.TAttr == sml.ST_CellTypeE:
+		v.kind = sortCellError
+		v.text = raw
+	case c.TAttr == sml.ST_CellTypeS || c.TAttr == sml.ST_CellTypeInlineStr || !isNum:
+		v.kind = sortCellText
+		v.text = cell.GetString()
+	default:
+		v.kind = sortCellNumber
+		v.num, _ = strconv.ParseFloat(raw, 64)
+	}
+	return v
+}
+
+// compareSortCells returns a negative number if lhs sorts before rhs for the
+// key, zero if they are equal and a positive number otherwise. Blank cells are
+// always sorted last.
+func compareSortCells(key SortKey, lhs, rhs sortCellValue) int {
+	if lhs.kind == sortCellBlank || rhs.kind == sortCellBlank {
+		return boolToInt(lhs.kind == sortCellBlank) - boolToInt(rhs.kind == sortCellBlank)
+	}
+	desc := 1
+	if key.Order == SortOrderDescending {
+		desc = -1
+	}
+	if key.By == SortByCellColor || key.By == SortByFontColor {
+		lm := colorFilterMatch(key.Color, key.By == SortByCellColor, filterCell{cell: lhs.cell})
+		rm := colorFilterMatch(key.Color, key.By == SortByCellColor, filterCell{cell: rhs.cell})
+		return desc * (boolToInt(rm) - boolToInt(lm))
+	}
+	if len(key.CustomList) > 0 {
+		li, ri := customListIndex(key.CustomList, lhs), customListIndex(key.CustomList, rhs)
+		if li != ri {
+			return desc * (li - ri)
+		}
+	}
+	if lhs.kind != rhs.kind {
+		return desc * (int(lhs.kind) - int(rhs.kind))
+	}
+	switch lhs.kind {
+	case sortCellNumber, sortCellBool:
+		switch {
+		case lhs.num < rhs.num:
+			return -desc
+		case lhs.num > rhs.num:
+			return desc
+		}
+		return 0
+	}
+	if c := strings.Compare(strings.ToLower(lhs.text), strings.ToLower(rhs.text)); c != 0 || !key.CaseSensitive {
+		return desc * c
+	}
+	// lowercase sorts before uppercase in a case sensitive sort
+	return desc * -strings.Compare(lhs.text, rhs.text)
+}
+
+// customListIndex returns the position of the value in the custom list, or
+// the length of the list if it isn't an entry.
+func customListIndex(list []string, v sortCellValue) int {
+	if v.kind == sortCellText {
+		for i, e := range list {
+			if strings.EqualFold(e, v.text) {
+				return i
+			}
+		}
+	}
+	return len(list)
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
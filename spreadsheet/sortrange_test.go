@@ -0,0 +1,90 @@
+package spreadsheet
+
+import "testing"
+
+func columnValues(sheet Sheet, col string, from, to uint32) []string {
+	ret := []string{}
+	for r := from; r <= to; r++ {
+		ret = append(ret, sheet.Row(r).Cell(col).GetFormattedValue())
+	}
+	return ret
+}
+
+func TestSortRangeMultiKey(t *testing.T) {
+	wb := New()
+	sheet := wb.AddSheet()
+	data := [][]interface{}{
+		{"Region", "Month", "Sales"},
+		{"west", "Feb", 10.0},
+		{"East", "Mar", 30.0},
+		{"west", "Jan", 20.0},
+		{"east", "Jan", 5.0},
+	}
+	for i, r := range data {
+		row := sheet.Row(uint32(i + 1))
+		for j, v := range r {
+			c := row.Cell(string(rune('A' + j)))
+			switch v := v.(type) {
+			case string:
+				c.SetString(v)
+			case float64:
+				c.SetNumber(v)
+			}
+		}
+		row.Cell("E").SetString("untouched")
+	}
+	sheet.Cell("D2").SetFormulaRaw("C2*2")
+	err := sheet.SortRangeWithOptions("A1:D5", []SortKey{
+		{Column: "A"},
+		{Column: "B", CustomList: []string{"Jan", "Feb", "Mar"}},
+	}, SortOptions{HasHeader: true, MoveFormulas: true})
+	if err != nil {
+		t.Fatalf("sort: %s", err)
+	}
+	exp := []string{"Region", "east", "East", "west", "west"}
+	if got := columnValues(sheet, "A", 1, 5); !equalStrings(got, exp) {
+		t.Errorf("expected %v, got %v", exp, got)
+	}
+	exp = []string{"Month", "Jan", "Mar", "Jan", "Feb"}
+	if got := columnValues(sheet, "B", 1, 5); !equalStrings(got, exp) {
+		t.Errorf("expected %v, got %v", exp, got)
+	}
+	if f := sheet.Cell("D5").GetFormula(); f != "C5*2" {
+		t.Errorf("expected formula to be moved and adjusted, got %q", f)
+	}
+	if v := sheet.Cell("E2").GetString(); v != "untouched" {
+		t.Errorf("expected cells outside of the range to stay, got %q", v)
+	}
+	st := sheet.X().SortState
+	if st == nil || st.RefAttr != "A2:D5" || len(st.SortCondition) != 2 ||
+		st.SortCondition[1].CustomListAttr == nil || *st.SortCondition[1].CustomListAttr != "Jan,Feb,Mar" {
+		t.Errorf("sort state not recorded correctly: %+v", st)
+	}
+}
+
+func TestSortRangeDescendingBlanksLast(t *testing.T) {
+	wb := New()
+	sheet := wb.AddSheet()
+	sheet.Cell("A1").SetNumber(2)
+	sheet.Cell("A3").SetNumber(7)
+	sheet.Cell("A4").SetString("text")
+	if err := sheet.SortRange("A1:A4", []SortKey{{Column: "A", Order: SortOrderDescending}}); err != nil {
+		t.Fatalf("sort: %s", err)
+	}
+	exp := []string{"text", "7", "2", ""}
+	if got := columnValues(sheet, "A", 1, 4); !equalStrings(got, exp) {
+		t.Errorf("expected %v, got %v", exp, got)
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
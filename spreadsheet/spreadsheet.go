@@ -42,7 +42,7 @@ _gaed .SetRow (row +_faffa );};
 // Drawing is a drawing overlay on a sheet.  Only a single drawing is allowed
 // per sheet, so to display multiple charts and images on a single sheet, they
 // must be added to the same drawing.
-type Drawing struct{_bgbe *Workbook ;_gbad *_cdg .WsDr ;};func (_dfd PatternFill )X ()*_ca .CT_PatternFill {return _dfd ._eaecg };type PatternFill struct{_eaecg *_ca .CT_PatternFill ;_bgdf *_ca .CT_Fill ;};
+type Drawing struct{_bgbe *Workbook ;_gbad *_cdg .WsDr ;};func (_dfd PatternFill )X ()*_ca .CT_PatternFill {return _dfd ._eaecg };type PatternFill struct{_eaecg *_ca .CT_PatternFill ;_bgdf *_ca .CT_Fill ;_fdcg *Workbook ;};
 
 // AddView adds a sheet view.
 func (_afeb *Sheet )AddView ()SheetView {if _afeb ._bbbe .SheetViews ==nil {_afeb ._bbbe .SheetViews =_ca .NewCT_SheetViews ();};_fgee :=_ca .NewCT_SheetView ();_afeb ._bbbe .SheetViews .SheetView =append (_afeb ._bbbe .SheetViews .SheetView ,_fgee );return SheetView {_fgee };
@@ -147,7 +147,7 @@ func (_adcee TwoCellAnchor )BottomRight ()CellMarker {return CellMarker {_adcee
 
 // SetTopLeft sets the top left visible cell after the split.
 func (_beca SheetView )SetTopLeft (cellRef string ){_beca .ensurePane ();_beca ._agec .Pane .TopLeftCellAttr =&cellRef ;};func (_edgd Fills )appendFill ()Fill {_geae :=_ca .NewCT_Fill ();_edgd ._bdgf .Fill =append (_edgd ._bdgf .Fill ,_geae );_edgd ._bdgf .CountAttr =_d .Uint32 (uint32 (len (_edgd ._bdgf .Fill )));
-return Fill {_geae ,_edgd ._bdgf };};
+return Fill {_geae ,_edgd ._bdgf ,_edgd ._fgbca };};
 
 // Row returns the row of the cell marker.
 func (_bdcc CellMarker )Row ()int32 {return _bdcc ._cdag .Row };
@@ -161,7 +161,7 @@ func (_fe Cell )SetBool (v bool ){_fe .clearValue ();_fe ._dga .V =_d .String (_
 func (_gfefd RichTextRun )SetText (s string ){_gfefd ._egda .T =s };
 
 // Font allows editing fonts within a spreadsheet stylesheet.
-type Font struct{_fceef *_ca .CT_Font ;_feef *_ca .StyleSheet ;};
+type Font struct{_fceef *_ca .CT_Font ;_feef *_ca .StyleSheet ;_dfcab *Workbook ;};
 
 // SetFormat sets the number format code.
 func (_cfdfg NumberFormat )SetFormat (f string ){_cfdfg ._gef .FormatCodeAttr =f };
@@ -311,7 +311,7 @@ func (_agg Cell )SetInlineString (s string ){_agg .clearValue ();_agg ._dga .Is
 
 // IsNumber returns true if the cell is a number type cell.
 func (_dca Cell )IsNumber ()bool {switch _dca ._dga .TAttr {case _ca .ST_CellTypeN :return true ;case _ca .ST_CellTypeS ,_ca .ST_CellTypeB :return false ;};return _dca ._dga .V !=nil &&_gd .IsNumber (*_dca ._dga .V );};func (_dfbb Fill )SetPatternFill ()PatternFill {if _dfbb ._dcefa .FillChoice ==nil {_dfbb ._dcefa .FillChoice =_ca .NewCT_FillChoice ();
-};_dfbb ._dcefa .FillChoice .GradientFill =nil ;_dfbb ._dcefa .FillChoice .PatternFill =_ca .NewCT_PatternFill ();_dfbb ._dcefa .FillChoice .PatternFill .PatternTypeAttr =_ca .ST_PatternTypeSolid ;return PatternFill {_dfbb ._dcefa .FillChoice .PatternFill ,_dfbb ._dcefa };
+};_dfbb ._dcefa .FillChoice .GradientFill =nil ;_dfbb ._dcefa .FillChoice .PatternFill =_ca .NewCT_PatternFill ();_dfbb ._dcefa .FillChoice .PatternFill .PatternTypeAttr =_ca .ST_PatternTypeSolid ;return PatternFill {_dfbb ._dcefa .FillChoice .PatternFill ,_dfbb ._dcefa ,_dfbb ._ebdd };
 };type ConditionalFormattingRule struct{_fef *_ca .CT_CfRule };
 
 // Cell retrieves or adds a new cell to a row. Col is the column (e.g. 'A', 'B')
@@ -342,7 +342,7 @@ _eca .Cell (_ffg ).X ().F .SiAttr =_d .Uint32 (_abb );};};return nil ;};
 func (_cabb Row )SetHeight (d _ab .Distance ){_cabb ._dgaf .HtAttr =_d .Float64 (float64 (d ));_cabb ._dgaf .CustomHeightAttr =_d .Bool (true );};
 
 // AddFill creates a new empty Fill style.
-func (_cegc Fills )AddFill ()Fill {_agbb :=_ca .NewCT_Fill ();return Fill {_agbb ,_cegc ._bdgf }};
+func (_cegc Fills )AddFill ()Fill {_agbb :=_ca .NewCT_Fill ();return Fill {_agbb ,_cegc ._bdgf ,_cegc ._fgbca }};
 
 // NewSharedStrings constructs a new Shared Strings table.
 func NewSharedStrings ()SharedStrings {return SharedStrings {_bbee :_ca .NewSst (),_bcd :make (map[string ]int )};};func (_aga *evalContext )NamedRange (ref string )_bcc .Reference {for _ ,_bgd :=range _aga ._daa ._fgeg .DefinedNames (){if _bgd .Name ()==ref {return _bcc .MakeRangeReference (_bgd .Content ());
@@ -423,7 +423,7 @@ func (_gfac Comment )CellReference ()string {return _gfac ._adf .RefAttr };
 // in the sheet. This may be required if you modify cells that are used as a
 // formula input to force the formulas to be recomputed the next time the sheet
 // is opened in Excel.
-func (_bbda *Workbook )ClearCachedFormulaResults (){for _ ,_gdgde :=range _bbda .Sheets (){_gdgde .ClearCachedFormulaResults ();};};type Fill struct{_dcefa *_ca .CT_Fill ;_ggb *_ca .CT_Fills ;};
+func (_bbda *Workbook )ClearCachedFormulaResults (){for _ ,_gdgde :=range _bbda .Sheets (){_gdgde .ClearCachedFormulaResults ();};};type Fill struct{_dcefa *_ca .CT_Fill ;_ggb *_ca .CT_Fills ;_ebdd *Workbook ;};
 
 // RowNumber returns the row number (1-N), or zero if it is unset.
 func (_dgad Row )RowNumber ()uint32 {if _dgad ._dgaf .RAttr !=nil {return *_dgad ._dgaf .RAttr ;};return 0;};
@@ -453,7 +453,7 @@ func (_gaeb *Workbook )DefinedNames ()[]DefinedName {if _gaeb ._gbadf .DefinedNa
 func (_dcc ColorScale )AddFormatValue (t _ca .ST_CfvoType ,val string ){_fafg :=_ca .NewCT_Cfvo ();_fafg .TypeAttr =t ;_fafg .ValAttr =_d .String (val );_dcc ._cab .Cfvo =append (_dcc ._cab .Cfvo ,_fafg );};
 
 // GetEpoch returns a workbook's time epoch.
-func (_degd *evalContext )GetEpoch ()_cd .Time {return _degd ._daa ._fgeg .Epoch ()};type Fills struct{_bdgf *_ca .CT_Fills };var _dbac =[...]uint8 {0,18,37};
+func (_degd *evalContext )GetEpoch ()_cd .Time {return _degd ._daa ._fgeg .Epoch ()};type Fills struct{_bdgf *_ca .CT_Fills ;_fgbca *Workbook ;};var _dbac =[...]uint8 {0,18,37};
 
 // PasswordHash returns the hash of the workbook password.
 func (_dfff WorkbookProtection )PasswordHash ()string {if _dfff ._fbbb .WorkbookPasswordAttr ==nil {return "";};return *_dfff ._fbbb .WorkbookPasswordAttr ;};
@@ -468,7 +468,7 @@ _cbce !=nil {_ef .Log .Error ("\u0045R\u0052\u004f\u0052\u003a\u0020\u0025v",_cb
 _aggeg :=false ;for _ ,_eafe :=range _abab {if _eafe .FileHeader .Name =="\u0064\u006f\u0063\u0050ro\u0070\u0073\u002f\u0063\u0075\u0073\u0074\u006f\u006d\u002e\u0078\u006d\u006c"{_aggeg =true ;break ;};};if _aggeg {_cfe .CreateCustomProperties ();};_efc :=_fg .DecodeMap {};
 _efc .SetOnNewRelationshipFunc (_cfe .onNewRelationship );_efc .AddTarget (_d .ContentTypesFilename ,_cfe .ContentTypes .X (),"",0);_efc .AddTarget (_d .BaseRelsFilename ,_cfe .Rels .X (),"",0);if _bddbg :=_efc .Decode (_abab );_bddbg !=nil {return nil ,_bddbg ;
 };for _ ,_ggf :=range _abab {if _ggf ==nil {continue ;};if _adae :=_cfe .AddExtraFileFromZip (_ggf );_adae !=nil {return nil ,_adae ;};};if _aggeg {_bgbef :=false ;for _ ,_eaee :=range _cfe .Rels .X ().Relationship {if _eaee .TargetAttr =="\u0064\u006f\u0063\u0050ro\u0070\u0073\u002f\u0063\u0075\u0073\u0074\u006f\u006d\u002e\u0078\u006d\u006c"{_bgbef =true ;
-break ;};};if !_bgbef {_cfe .AddCustomRelationships ();};};return _cfe ,nil ;};func (_acbf StyleSheet )appendBorder ()Border {_ffbg :=_ca .NewCT_Border ();_acbf ._gccd .Borders .Border =append (_acbf ._gccd .Borders .Border ,_ffbg );_acbf ._gccd .Borders .CountAttr =_d .Uint32 (uint32 (len (_acbf ._gccd .Borders .Border )));
+break ;};};if !_bgbef {_cfe .AddCustomRelationships ();};};if _dbfe :=_cfe .loadChartEx ();_dbfe !=nil {return nil ,_dbfe ;};if _dbfe :=_cfe .loadFormControls ();_dbfe !=nil {return nil ,_dbfe ;};return _cfe ,nil ;};func (_acbf StyleSheet )appendBorder ()Border {_ffbg :=_ca .NewCT_Border ();_acbf ._gccd .Borders .Border =append (_acbf ._gccd .Borders .Border ,_ffbg );_acbf ._gccd .Borders .CountAttr =_d .Uint32 (uint32 (len (_acbf ._gccd .Borders .Border )));
 return Border {_ffbg ,_acbf ._gccd .Borders };};
 
 // SetPassword sets the password hash to a hash of the input password.
@@ -512,7 +512,7 @@ _gcb :=_efea ._bbbe .SheetData .Row [_dcaed ].RAttr ;if _agef ==nil {return true
 // then formatting the value according to the format string.  This should only
 // be used if you care about replicating what Excel would show, otherwise
 // GetValueAsNumber()/GetValueAsTime
-func (_aec Cell )GetFormattedValue ()string {_ccc :=_aec .getFormat ();switch _aec ._dga .TAttr {case _ca .ST_CellTypeB :_aca ,_ :=_aec .GetValueAsBool ();if _aca {return "\u0054\u0052\u0055\u0045";};return "\u0046\u0041\u004cS\u0045";case _ca .ST_CellTypeN :_def ,_ :=_aec .GetValueAsNumber ();
+func (_aec Cell )GetFormattedValue ()string {if _efcg ,_adbe :=_aec .localeFormattedValue ();_adbe {return _efcg ;};_ccc :=_aec .getFormat ();switch _aec ._dga .TAttr {case _ca .ST_CellTypeB :_aca ,_ :=_aec .GetValueAsBool ();if _aca {return "\u0054\u0052\u0055\u0045";};return "\u0046\u0041\u004cS\u0045";case _ca .ST_CellTypeN :_def ,_ :=_aec .GetValueAsNumber ();
 return _gd .Number (_def ,_ccc );case _ca .ST_CellTypeE :if _aec ._dga .V !=nil {return *_aec ._dga .V ;};return "";case _ca .ST_CellTypeS ,_ca .ST_CellTypeInlineStr :return _gd .String (_aec .GetString (),_ccc );case _ca .ST_CellTypeStr :_ffc :=_aec .GetString ();
 if _gd .IsNumber (_ffc ){_cbf ,_ :=_fb .ParseFloat (_ffc ,64);return _gd .Number (_cbf ,_ccc );};return _gd .String (_ffc ,_ccc );case _ca .ST_CellTypeUnset :fallthrough;default:_dgab ,_ :=_aec .GetRawValue ();if len (_dgab )==0{return "";};_egc ,_afc :=_aec .GetValueAsNumber ();
 if _afc ==nil {return _gd .Number (_egc ,_ccc );};return _gd .String (_dgab ,_ccc );};};
@@ -713,12 +713,12 @@ func (_ababf StyleSheet )RemoveFont (f Font )error {for _bgfb ,_fcec :=range _ab
 // supported,  if formula execution fails either due to a parse error or missing
 // function, or erorr in the result (even if expected) the cached value will be
 // left empty allowing Excel to recompute it on load.
-func (_geddd *Sheet )RecalculateFormulas (){_bcbag :=_bcc .NewEvaluator ();_ccca :=_geddd .FormulaContext ();for _ ,_abgc :=range _geddd .Rows (){for _ ,_eegd :=range _abgc .Cells (){if _eegd .X ().F !=nil {_ffgd :=_eegd .X ().F .Content ;if _eegd .X ().F .TAttr ==_ca .ST_CellFormulaTypeShared &&len (_ffgd )==0{continue ;
+func (_geddd *Sheet )RecalculateFormulas (){_bcbag :=_bcc .NewEvaluator ();_ccca :=_geddd .FormulaContext ();for _ ,_abgc :=range _geddd .Rows (){for _ ,_eegd :=range _abgc .Cells (){if _eegd .X ().F !=nil {if _eegd .X ().F .TAttr ==_ca .ST_CellFormulaTypeDataTable {continue ;};_ffgd :=_eegd .X ().F .Content ;if _eegd .X ().F .TAttr ==_ca .ST_CellFormulaTypeShared &&len (_ffgd )==0{continue ;
 };_dcgf :=_bcbag .Eval (_ccca ,_ffgd ).AsString ();if _dcgf .Type ==_bcc .ResultTypeError {_ef .Log .Debug ("\u0065\u0072\u0072o\u0072\u0020\u0065\u0076a\u0075\u006c\u0061\u0074\u0069\u006e\u0067 \u0066\u006f\u0072\u006d\u0075\u006c\u0061\u0020\u0025\u0073\u003a\u0020\u0025\u0073",_ffgd ,_dcgf .ErrorMessage );
-_eegd .X ().V =nil ;}else {if _dcgf .Type ==_bcc .ResultTypeNumber {_eegd .X ().TAttr =_ca .ST_CellTypeN ;}else {_eegd .X ().TAttr =_ca .ST_CellTypeInlineStr ;};_eegd .X ().V =_d .String (_dcgf .Value ());if _eegd .X ().F .TAttr ==_ca .ST_CellFormulaTypeArray {if _dcgf .Type ==_bcc .ResultTypeArray {_geddd .setArray (_eegd .Reference (),_dcgf );
+_eegd .X ().V =nil ;}else {if _dcgf .Type ==_bcc .ResultTypeNumber {_eegd .X ().TAttr =_ca .ST_CellTypeN ;}else {_eegd .X ().TAttr =_ca .ST_CellTypeInlineStr ;};_eegd .X ().V =_d .String (_eegd .displayedPrecision (_dcgf .Value ()));if _eegd .X ().F .TAttr ==_ca .ST_CellFormulaTypeArray {if _dcgf .Type ==_bcc .ResultTypeArray {_geddd .setArray (_eegd .Reference (),_dcgf );
 }else if _dcgf .Type ==_bcc .ResultTypeList {_geddd .setList (_eegd .Reference (),_dcgf );};}else if _eegd .X ().F .TAttr ==_ca .ST_CellFormulaTypeShared &&_eegd .X ().F .RefAttr !=nil {_dddag ,_cfee ,_cadg :=_ed .ParseRangeReference (*_eegd .X ().F .RefAttr );
 if _cadg !=nil {_ef .Log .Debug ("\u0065\u0072r\u006f\u0072\u0020\u0069n\u0020\u0073h\u0061\u0072\u0065\u0064\u0020\u0066\u006f\u0072m\u0075\u006c\u0061\u0020\u0072\u0065\u0066\u0065\u0072\u0065\u006e\u0063e\u003a\u0020\u0025\u0073",_cadg );continue ;};
-_geddd .setShared (_eegd .Reference (),_dddag ,_cfee ,_ffgd );};};};};};};
+_geddd .setShared (_eegd .Reference (),_dddag ,_cfee ,_ffgd );};};};};};_geddd .recalculateDataTables ();};
 
 // MaxColumnIdx returns the max used column of the sheet.
 func (_daee Sheet )MaxColumnIdx ()uint32 {_bae :=uint32 (0);for _ ,_ecbb :=range _daee .Rows (){_bgfe :=_ecbb ._dgaf .C ;if len (_bgfe )> 0{_bdee :=_bgfe [len (_bgfe )-1];_bgcd ,_ :=_ed .ParseCellReference (*_bdee .RAttr );if _bae < _bgcd .ColumnIdx {_bae =_bgcd .ColumnIdx ;
@@ -796,7 +796,7 @@ func (_ddeca RichText )X ()*_ca .CT_Rst {return _ddeca ._fbd };
 func (_aeac *Workbook )Epoch ()_cd .Time {if _aeac .Uses1904Dates (){_cd .Date (1904,1,1,0,0,0,0,_cd .UTC );};return _cd .Date (1899,12,30,0,0,0,0,_cd .UTC );};
 
 // AddNumberFormat adds a new blank number format to the stylesheet.
-func (_acgbb StyleSheet )AddNumberFormat ()NumberFormat {if _acgbb ._gccd .NumFmts ==nil {_acgbb ._gccd .NumFmts =_ca .NewCT_NumFmts ();};_deae :=_ca .NewCT_NumFmt ();_deae .NumFmtIdAttr =uint32 (200+len (_acgbb ._gccd .NumFmts .NumFmt ));_acgbb ._gccd .NumFmts .NumFmt =append (_acgbb ._gccd .NumFmts .NumFmt ,_deae );
+func (_acgbb StyleSheet )AddNumberFormat ()NumberFormat {if _acgbb ._gccd .NumFmts ==nil {_acgbb ._gccd .NumFmts =_ca .NewCT_NumFmts ();};_deae :=_ca .NewCT_NumFmt ();_deae .NumFmtIdAttr =_acgbb .nextNumberFormatID ();_acgbb ._gccd .NumFmts .NumFmt =append (_acgbb ._gccd .NumFmts .NumFmt ,_deae );
 _acgbb ._gccd .NumFmts .CountAttr =_d .Uint32 (uint32 (len (_acgbb ._gccd .NumFmts .NumFmt )));return NumberFormat {_acgbb ._ddee ,_deae };};
 
 // SetDate sets the cell value to a date. It's stored as the number of days past
@@ -822,7 +822,7 @@ func (_cfd Cell )IsError ()bool {return _cfd ._dga .TAttr ==_ca .ST_CellTypeE };
 
 // SetHidden marks the defined name as hidden.
 func (_gface DefinedName )SetHidden (b bool ){_gface ._agac .HiddenAttr =_d .Bool (b )};func NewPatternFill (fills *_ca .CT_Fills )PatternFill {_bdac :=_ca .NewCT_Fill ();_bdac .FillChoice =_ca .NewCT_FillChoice ();_bdac .FillChoice .PatternFill =_ca .NewCT_PatternFill ();
-return PatternFill {_bdac .FillChoice .PatternFill ,_bdac };};
+return PatternFill {_bdac .FillChoice .PatternFill ,_bdac ,nil };};
 
 // ClearSheetViews clears the list of sheet views.  This will clear the results
 // of AddView() or SetFrozen.
@@ -857,7 +857,7 @@ func (_eg Border )Index ()uint32 {for _cac ,_fc :=range _eg ._df .Border {if _fc
 // SheetViews returns the sheet views defined.  This is where splits and frozen
 // rows/cols are configured.  Multiple sheet views are allowed, but I'm not
 // aware of there being a use for more than a single sheet view.
-func (_bcaf *Sheet )SheetViews ()[]SheetView {if _bcaf ._bbbe .SheetViews ==nil {return nil ;};_gaad :=[]SheetView {};for _ ,_gaff :=range _bcaf ._bbbe .SheetViews .SheetView {_gaad =append (_gaad ,SheetView {_gaff });};return _gaad ;};func (_dgg *evalContext )Sheet (name string )_bcc .Context {for _ ,_gbd :=range _dgg ._daa ._fgeg .Sheets (){if _gbd .Name ()==name {return _gbd .FormulaContext ();
+func (_bcaf *Sheet )SheetViews ()[]SheetView {if _bcaf ._bbbe .SheetViews ==nil {return nil ;};_gaad :=[]SheetView {};for _ ,_gaff :=range _bcaf ._bbbe .SheetViews .SheetView {_gaad =append (_gaad ,SheetView {_gaff });};return _gaad ;};func (_dgg *evalContext )Sheet (name string )_bcc .Context {if _ggce ,_bdfa :=_dgg ._daa ._fgeg .externalSheet (name );_bdfa {return _ggce ;};for _ ,_gbd :=range _dgg ._daa ._fgeg .Sheets (){if _gbd .Name ()==name {return _gbd .FormulaContext ();
 };};return _bcc .InvalidReferenceContext ;};
 
 // MoveTo moves the top-left of the anchored object.
@@ -917,7 +917,7 @@ func (_eae CellStyle )HasNumberFormat ()bool {return _eae ._faf .NumFmtIdAttr !=
 type Comments struct{_afb *Workbook ;_dbd *_ca .Comments ;};
 
 // IsEmpty checks if the cell style contains nothing.
-func (_caaca CellStyle )IsEmpty ()bool {return _caaca ._bba ==nil ||_caaca ._faf ==nil ||_caaca ._fbeb ==nil ||_caaca ._fbeb .Xf ==nil ;};func NewFills ()Fills {return Fills {_ca .NewCT_Fills ()}};func (_ddbg Sheet )IsValid ()bool {return _ddbg ._bbbe !=nil };
+func (_caaca CellStyle )IsEmpty ()bool {return _caaca ._bba ==nil ||_caaca ._faf ==nil ||_caaca ._fbeb ==nil ||_caaca ._fbeb .Xf ==nil ;};func NewFills ()Fills {return Fills {_ca .NewCT_Fills (),nil }};func (_ddbg Sheet )IsValid ()bool {return _ddbg ._bbbe !=nil };
 
 
 // Text returns text from the workbook as one string separated with line breaks.
@@ -968,8 +968,8 @@ func (_acf PatternFill )SetPattern (p _ca .ST_PatternType ){_acf ._eaecg .Patter
 // already exists in the saved number formats. If found, the existing number format is reused; otherwise,
 // the new number format is added to the saved number formats collection. The number format is then applied to the cell style,
 // affecting all styles that reference it by index.
-func (_acca CellStyle )SetNumberFormat (s string ){var _bcb NumberFormat ;if _acca ._bba .StyleSheet ._gccd .NumFmts ==nil {_acca ._bba .StyleSheet ._gccd .NumFmts =_ca .NewCT_NumFmts ();};_bdde :=_acca ._bba .StyleSheet ._gccd .NumFmts .NumFmt ;for _ ,_cgf :=range _bdde {if _ga .DeepEqual (_cgf .FormatCodeAttr ,s ){_bcb =NumberFormat {_acca ._bba ,_cgf };
-_acca ._faf .ApplyNumberFormatAttr =_d .Bool (true );_acca ._faf .NumFmtIdAttr =_d .Uint32 (_bcb .ID ());return ;};};_gcg :=_ca .NewCT_NumFmt ();_gcg .NumFmtIdAttr =uint32 (200+len (_acca ._bba .StyleSheet ._gccd .NumFmts .NumFmt ));_acca ._bba .StyleSheet ._gccd .NumFmts .NumFmt =append (_acca ._bba .StyleSheet ._gccd .NumFmts .NumFmt ,_gcg );
+func (_acca CellStyle )SetNumberFormat (s string ){var _bcb NumberFormat ;if _bfgc ,_edbc :=builtinNumberFormatID (s );_edbc {_acca .SetNumberFormatStandard (StandardFormat (_bfgc ));return ;};if _acca ._bba .StyleSheet ._gccd .NumFmts ==nil {_acca ._bba .StyleSheet ._gccd .NumFmts =_ca .NewCT_NumFmts ();};_bdde :=_acca ._bba .StyleSheet ._gccd .NumFmts .NumFmt ;for _ ,_cgf :=range _bdde {if _ga .DeepEqual (_cgf .FormatCodeAttr ,s ){_bcb =NumberFormat {_acca ._bba ,_cgf };
+_acca ._faf .ApplyNumberFormatAttr =_d .Bool (true );_acca ._faf .NumFmtIdAttr =_d .Uint32 (_bcb .ID ());return ;};};_gcg :=_ca .NewCT_NumFmt ();_gcg .NumFmtIdAttr =_acca ._bba .StyleSheet .nextNumberFormatID ();_acca ._bba .StyleSheet ._gccd .NumFmts .NumFmt =append (_acca ._bba .StyleSheet ._gccd .NumFmts .NumFmt ,_gcg );
 _acca ._bba .StyleSheet ._gccd .NumFmts .CountAttr =_d .Uint32 (uint32 (len (_acca ._bba .StyleSheet ._gccd .NumFmts .NumFmt )));_bcb =NumberFormat {_acca ._bba ,_gcg };_bcb ._gef .FormatCodeAttr =s ;_acca ._faf .ApplyNumberFormatAttr =_d .Bool (true );
 _acca ._faf .NumFmtIdAttr =_d .Uint32 (_bcb .ID ());};
 
@@ -1055,7 +1055,7 @@ _aada ._gbadf .DefinedNames .DefinedName =_aada ._gbadf .DefinedNames .DefinedNa
 
 // Workbook is the top level container item for a set of spreadsheets.
 type Workbook struct{_bfe .DocBase ;_gbadf *_ca .Workbook ;StyleSheet StyleSheet ;SharedStrings SharedStrings ;_edca []*_ca .Comments ;_fbef []*_ca .Worksheet ;_aedf []_bfe .Relationships ;_bcg _bfe .Relationships ;_bgbc []*_da .Theme ;_ecgc []*_cdg .WsDr ;
-_fcdfa []_bfe .Relationships ;_adbg []*_ce .Container ;_faebe []*_ge .ChartSpace ;_eeegg []*_ca .Table ;_dgc string ;_eagg map[string ]string ;_ffaff map[string ]*_ge .ChartSpace ;_agde string ;};
+_fcdfa []_bfe .Relationships ;_adbg []*_ce .Container ;_faebe []*_ge .ChartSpace ;_eeegg []*_ca .Table ;_dgc string ;_eagg map[string ]string ;_ffaff map[string ]*_ge .ChartSpace ;_agde string ;_locale *_gd .Locale ;_threaded *threadedState ;_external *externalState ;_formControls *formControlState ;_slicers *slicerState ;_chartEx []*chartExPart ;_refreshChartCaches bool ;};
 
 // AddDataValidation adds a data validation rule to a sheet.
 func (_eecd *Sheet )AddDataValidation ()DataValidation {if _eecd ._bbbe .DataValidations ==nil {_eecd ._bbbe .DataValidations =_ca .NewCT_DataValidations ();};_ggce :=_ca .NewCT_DataValidation ();_ggce .ShowErrorMessageAttr =_d .Bool (true );_eecd ._bbbe .DataValidations .DataValidation =append (_eecd ._bbbe .DataValidations .DataValidation ,_ggce );
@@ -1221,7 +1221,7 @@ func (_faa SheetView )SetState (st _ca .ST_PaneState ){_faa .ensurePane ();_faa
 func (_ecec DefinedName )SetLocalSheetID (id uint32 ){_ecec ._agac .LocalSheetIdAttr =_d .Uint32 (id )};
 
 // AddFont creates a new empty Font style.
-func (_ebe StyleSheet )AddFont ()Font {_ebbe :=_ca .NewCT_Font ();return Font {_ebbe ,_ebe ._gccd }};func _cbbc ()*_cdg .CT_TwoCellAnchor {_bfcc :=_cdg .NewCT_TwoCellAnchor ();_bfcc .EditAsAttr =_cdg .ST_EditAsOneCell ;_bfcc .From .Col =5;_bfcc .From .Row =0;
+func (_ebe StyleSheet )AddFont ()Font {_ebbe :=_ca .NewCT_Font ();return Font {_ebbe ,_ebe ._gccd ,_ebe ._ddee }};func _cbbc ()*_cdg .CT_TwoCellAnchor {_bfcc :=_cdg .NewCT_TwoCellAnchor ();_bfcc .EditAsAttr =_cdg .ST_EditAsOneCell ;_bfcc .From .Col =5;_bfcc .From .Row =0;
 _bfcc .From .ColOff .ST_CoordinateUnqualified =_d .Int64 (0);_bfcc .From .RowOff .ST_CoordinateUnqualified =_d .Int64 (0);_bfcc .To .Col =10;_bfcc .To .Row =20;_bfcc .To .ColOff .ST_CoordinateUnqualified =_d .Int64 (0);_bfcc .To .RowOff .ST_CoordinateUnqualified =_d .Int64 (0);
 return _bfcc ;};
 
@@ -1327,7 +1327,7 @@ for _fcfea ,_bdgd :=range _dac ._fceef .FontChoice {if _bdgd .Sz !=nil {_dac ._f
 };};
 
 // GetFormula returns the formula for a cell.
-func (_ace Cell )GetFormula ()string {if _ace ._dga .F !=nil {return _ace ._dga .F .Content ;};return "";};func (_gdega Cell )setLocked (_gca bool ){_gfa :=_gdega ._dga .SAttr ;if _gfa !=nil {_accd :=_gdega ._bgg .StyleSheet .GetCellStyle (*_gfa );if _accd ._faf .Protection ==nil {_accd ._faf .Protection =_ca .NewCT_CellProtection ();
+func (_ace Cell )GetFormula ()string {if _ace ._dga .F !=nil {return _ace .effectiveFormula ();};return "";};func (_gdega Cell )setLocked (_gca bool ){_gfa :=_gdega ._dga .SAttr ;if _gfa !=nil {_accd :=_gdega ._bgg .StyleSheet .GetCellStyle (*_gfa );if _accd ._faf .Protection ==nil {_accd ._faf .Protection =_ca .NewCT_CellProtection ();
 };_accd ._faf .Protection .LockedAttr =&_gca ;};};func (_agd CellStyle )SetShrinkToFit (b bool ){if _agd ._faf .Alignment ==nil {_agd ._faf .Alignment =_ca .NewCT_CellAlignment ();};_agd ._faf .ApplyAlignmentAttr =_d .Bool (true );if !b {_agd ._faf .Alignment .ShrinkToFitAttr =nil ;
 }else {_agd ._faf .Alignment .ShrinkToFitAttr =_d .Bool (b );};};
 
@@ -1370,7 +1370,7 @@ func (_eda AbsoluteAnchor )SetHeight (h _ab .Distance ){_eda ._be .Ext .CyAttr =
 // will not be changed).  This method only changes the metadata author of the
 // comment.
 func (_edc Comment )SetAuthor (author string ){_edc ._adf .AuthorIdAttr =Comments {_edc ._dgf ,_edc ._eeec }.getOrCreateAuthor (author );};func (_eeae DifferentialStyle )Fill ()Fill {if _eeae ._gace .Fill ==nil {_eeae ._gace .Fill =_ca .NewCT_Fill ();};
-return Fill {_eeae ._gace .Fill ,nil };};func (_dfeff Font )SetName (name string ){_edbbf :=false ;for _dbf ,_ebfdb :=range _dfeff ._fceef .FontChoice {if _ebfdb .Name !=nil {_dfeff ._fceef .FontChoice [_dbf ].Name =&_ca .CT_FontName {ValAttr :name };_edbbf =true ;
+return Fill {_eeae ._gace .Fill ,nil ,_eeae ._bea };};func (_dfeff Font )SetName (name string ){_edbbf :=false ;for _dbf ,_ebfdb :=range _dfeff ._fceef .FontChoice {if _ebfdb .Name !=nil {_dfeff ._fceef .FontChoice [_dbf ].Name =&_ca .CT_FontName {ValAttr :name };_edbbf =true ;
 };};if !_edbbf {_dfeff ._fceef .FontChoice =append (_dfeff ._fceef .FontChoice ,&_ca .CT_FontChoice {Name :&_ca .CT_FontName {ValAttr :name }});};};
 
 // Priority returns the rule priority
@@ -1418,12 +1418,11 @@ _dfbae .SetStyleIndex (cs .Index ());};
 type DataBarScale struct{_ddbe *_ca .CT_DataBar };
 
 // Fonts returns the list of fonts defined in the stylesheet.
-func (_bgdd StyleSheet )Fonts ()[]Font {_ffcd :=[]Font {};for _ ,_gdfg :=range _bgdd ._gccd .Fonts .Font {_ffcd =append (_ffcd ,Font {_gdfg ,_bgdd ._gccd });};return _ffcd ;};
+func (_bgdd StyleSheet )Fonts ()[]Font {_ffcd :=[]Font {};for _ ,_gdfg :=range _bgdd ._gccd .Fonts .Font {_ffcd =append (_ffcd ,Font {_gdfg ,_bgdd ._gccd ,_bgdd ._ddee });};return _ffcd ;};
 
 // NumberFormat returns the number format that the cell style uses, or zero if
 // it is not set.
-func (_efbf CellStyle )NumberFormat ()uint32 {if _efbf ._faf .NumFmtIdAttr ==nil {return 0;};return *_efbf ._faf .NumFmtIdAttr ;};func (_baa Cell )GetRawValue ()(string ,error ){switch _baa ._dga .TAttr {case _ca .ST_CellTypeInlineStr :if _baa ._dga .Is ==nil ||_baa ._dga .Is .T ==nil {return "",nil ;
-};return *_baa ._dga .Is .T ,nil ;case _ca .ST_CellTypeS :if _baa ._dga .V ==nil {return "",nil ;};_fcf ,_aee :=_fb .Atoi (*_baa ._dga .V );if _aee !=nil {return "",_aee ;};return _baa ._bgg .SharedStrings .GetString (_fcf );case _ca .ST_CellTypeStr :if _baa ._dga .F !=nil {return _baa ._dga .F .Content ,nil ;
+func (_efbf CellStyle )NumberFormat ()uint32 {if _efbf ._faf .NumFmtIdAttr ==nil {return 0;};return *_efbf ._faf .NumFmtIdAttr ;};func (_baa Cell )GetRawValue ()(string ,error ){switch _baa ._dga .TAttr {case _ca .ST_CellTypeInlineStr :return rstText (_baa ._dga .Is ),nil ;case _ca .ST_CellTypeS :if _baa ._dga .V ==nil {return "",nil ;};_fcf ,_aee :=_fb .Atoi (*_baa ._dga .V );if _aee !=nil {return "",_aee ;};return _baa ._bgg .SharedStrings .GetString (_fcf );case _ca .ST_CellTypeStr :if _baa ._dga .F !=nil {return _baa ._dga .F .Content ,nil ;
 };};if _baa ._dga .V ==nil {return "",nil ;};return *_baa ._dga .V ,nil ;};
 
 // Tables returns a slice of all defined tables in the workbook.
@@ -1465,7 +1464,7 @@ func (_cfdg CellStyle )SetProtection (protected bool ,hidden bool ){_cfdg ._faf
 
 // GetString returns the string in a cell if it's an inline or string table
 // string. Otherwise it returns an empty string.
-func (_dffa Cell )GetString ()string {switch _dffa ._dga .TAttr {case _ca .ST_CellTypeInlineStr :if _dffa ._dga .Is !=nil &&_dffa ._dga .Is .T !=nil {return *_dffa ._dga .Is .T ;};if _dffa ._dga .V !=nil {return *_dffa ._dga .V ;};case _ca .ST_CellTypeS :if _dffa ._dga .V ==nil {return "";
+func (_dffa Cell )GetString ()string {switch _dffa ._dga .TAttr {case _ca .ST_CellTypeInlineStr :if _dffa ._dga .Is !=nil {return rstText (_dffa ._dga .Is );};if _dffa ._dga .V !=nil {return *_dffa ._dga .V ;};case _ca .ST_CellTypeS :if _dffa ._dga .V ==nil {return "";
 };_fgd ,_aac :=_fb .Atoi (*_dffa ._dga .V );if _aac !=nil {return "";};_agf ,_aac :=_dffa ._bgg .SharedStrings .GetString (_fgd );if _aac !=nil {return "";};return _agf ;};if _dffa ._dga .V ==nil {return "";};return *_dffa ._dga .V ;};
 
 // SetRotation configures the cell to be rotated.
@@ -1511,13 +1510,13 @@ func (_aabf DataValidationCompare )SetValue (v string ){_aabf ._eeea .Formula1 =
 
 // SetWidth sets the width of the anchored object.
 func (_gdef OneCellAnchor )SetWidth (w _ab .Distance ){_gdef ._cedd .Ext .CxAttr =int64 (w /_ab .EMU )};var _dcef *_gfg .Regexp =_gfg .MustCompile ("\u005e(\u005ba\u002d\u007a\u005d\u002b\u0029(\u005b\u0030-\u0039\u005d\u002b\u0029\u0024");func (_dfgg StyleSheet )appendFont ()Font {_efde :=_ca .NewCT_Font ();
-_dfgg ._gccd .Fonts .Font =append (_dfgg ._gccd .Fonts .Font ,_efde );_dfgg ._gccd .Fonts .CountAttr =_d .Uint32 (uint32 (len (_dfgg ._gccd .Fonts .Font )));return Font {_efde ,_dfgg ._gccd };};
+_dfgg ._gccd .Fonts .Font =append (_dfgg ._gccd .Fonts .Font ,_efde );_dfgg ._gccd .Fonts .CountAttr =_d .Uint32 (uint32 (len (_dfgg ._gccd .Fonts .Font )));return Font {_efde ,_dfgg ._gccd ,_dfgg ._ddee };};
 
 // Type returns the type of anchor
 func (_acdd TwoCellAnchor )Type ()AnchorType {return AnchorTypeTwoCell };
 
 // Fills returns a Fills object that can be used to add/create/edit fills.
-func (_egfc StyleSheet )Fills ()Fills {return Fills {_egfc ._gccd .Fills }};
+func (_egfc StyleSheet )Fills ()Fills {return Fills {_egfc ._gccd .Fills ,_egfc ._ddee }};
 
 // Reference returns the table reference (the cells within the table)
 func (_gabd Table )Reference ()string {return _gabd ._cbgb .RefAttr };
@@ -1629,7 +1628,7 @@ func (_gebca RichTextRun )SetSize (m _ab .Distance ){_gebca .ensureRpr ();_efa :
 };func _edb (_cff bool )int {if _cff {return 1;};return 0;};
 
 // Save writes the workbook out to a writer in the zipped xlsx format.
-func (_dafeb *Workbook )Save (w _bf .Writer )error {const _cdbbd ="\u0073\u0070\u0072\u0065ad\u0073\u0068\u0065\u0065\u0074\u003a\u0077\u0062\u002e\u0053\u0061\u0076\u0065";if !_bg .GetLicenseKey ().IsLicensed ()&&!_gfcca {_ag .Println ("\u0055\u006e\u006ci\u0063\u0065\u006e\u0073e\u0064\u0020\u0076\u0065\u0072\u0073\u0069o\u006e\u0020\u006f\u0066\u0020\u0055\u006e\u0069\u004f\u0066\u0066\u0069\u0063\u0065");
+func (_dafeb *Workbook )Save (w _bf .Writer )error {if _dgfe :=_dafeb .flushChartCaches ();_dgfe !=nil {return _dgfe ;};const _cdbbd ="\u0073\u0070\u0072\u0065ad\u0073\u0068\u0065\u0065\u0074\u003a\u0077\u0062\u002e\u0053\u0061\u0076\u0065";if !_bg .GetLicenseKey ().IsLicensed ()&&!_gfcca {_ag .Println ("\u0055\u006e\u006ci\u0063\u0065\u006e\u0073e\u0064\u0020\u0076\u0065\u0072\u0073\u0069o\u006e\u0020\u006f\u0066\u0020\u0055\u006e\u0069\u004f\u0066\u0066\u0069\u0063\u0065");
 _ag .Println ("\u002d\u0020\u0047e\u0074\u0020\u0061\u0020\u0074\u0072\u0069\u0061\u006c\u0020\u006c\u0069\u0063\u0065\u006e\u0073\u0065\u0020\u006f\u006e\u0020\u0068\u0074\u0074\u0070\u0073\u003a\u002f\u002fu\u006e\u0069\u0064\u006f\u0063\u002e\u0069\u006f");
 return _gb .New ("\u0075\u006e\u0069\u006f\u0066\u0066\u0069\u0063\u0065\u0020\u006ci\u0063\u0065\u006e\u0073\u0065\u0020\u0072\u0065\u0071\u0075i\u0072\u0065\u0064");};_daef :="\u0075n\u006b\u006e\u006f\u0077\u006e";if _edgfe ,_adfa :=w .(*_c .File );
 _adfa {_daef =_edgfe .Name ();};if len (_dafeb ._agde )==0{_aafd ,_cfgb :=_bg .GenRefId ("\u0073\u0077");if _cfgb !=nil {_ef .Log .Error ("\u0045R\u0052\u004f\u0052\u003a\u0020\u0025v",_cfgb );return _cfgb ;};_dafeb ._agde =_aafd ;};if _bebd :=_bg .Track (_dafeb ._agde ,_cdbbd ,_daef );
@@ -1644,7 +1643,7 @@ _dfc !=nil {return _dfc ;};};if _dafeb .Thumbnail !=nil {_eeecba :=_d .AbsoluteF
 };for _gbag ,_aceb :=range _dafeb ._ecgc {_bddab :=_d .AbsoluteFilename (_beec ,_d .DrawingType ,_gbag +1);_fg .MarshalXML (_bbdgc ,_bddab ,_aceb );if !_dafeb ._fcdfa [_gbag ].IsEmpty (){_fg .MarshalXML (_bbdgc ,_fg .RelationsPathFor (_bddab ),_dafeb ._fcdfa [_gbag ].X ());
 };};for _bacf ,_gefe :=range _dafeb ._adbg {_fg .MarshalXML (_bbdgc ,_d .AbsoluteFilename (_beec ,_d .VMLDrawingType ,_bacf +1),_gefe );};for _dcdc ,_ccgc :=range _dafeb .Images {if _cdbbc :=_bfe .AddImageToZip (_bbdgc ,_ccgc ,_dcdc +1,_d .DocTypeSpreadsheet );
 _cdbbc !=nil {return _cdbbc ;};};if _cccbf :=_fg .MarshalXML (_bbdgc ,_d .ContentTypesFilename ,_dafeb .ContentTypes .X ());_cccbf !=nil {return _cccbf ;};for _fage ,_dafd :=range _dafeb ._edca {if _dafd ==nil {continue ;};_fg .MarshalXML (_bbdgc ,_d .AbsoluteFilename (_beec ,_d .CommentsType ,_fage +1),_dafd );
-};if _dgfe :=_dafeb .WriteExtraFiles (_bbdgc );_dgfe !=nil {return _dgfe ;};return _bbdgc .Close ();};
+};if _dgfe :=_dafeb .flushThreadedComments ();_dgfe !=nil {return _dgfe ;};if _dgfe :=_dafeb .flushFormControls ();_dgfe !=nil {return _dgfe ;};if _dgfe :=_dafeb .flushSlicers ();_dgfe !=nil {return _dgfe ;};if _dgfe :=_dafeb .flushChartEx ();_dgfe !=nil {return _dgfe ;};if _dgfe :=_dafeb .WriteExtraFiles (_bbdgc );_dgfe !=nil {return _dgfe ;};return _bbdgc .Close ();};
 
 // Row is a row within a spreadsheet.
 type Row struct{_feff *Workbook ;_faff *Sheet ;_dgaf *_ca .CT_Row ;};
@@ -0,0 +1,598 @@
+package spreadsheet
+
+import (
+	"crypto/rand"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"github.com/yaklabco/unioffice/v2/common"
+	"github.com/yaklabco/unioffice/v2/common/tempstorage"
+	"github.com/yaklabco/unioffice/v2/schema/soo/sml"
+	"github.com/yaklabco/unioffice/v2/schema/urn/schemas_microsoft_com/vml"
+	"github.com/yaklabco/unioffice/v2/spreadsheet/reference"
+	"github.com/yaklabco/unioffice/v2/vmldrawing"
+)
+
+const (
+	threadedCommentType        = "http://schemas.microsoft.com/office/2017/10/relationships/threadedComment"
+	threadedCommentContentType = "application/vnd.ms-excel.threadedcomments+xml"
+	personType                 = "http://schemas.microsoft.com/office/2017/10/relationships/person"
+	personContentType          = "application/vnd.ms-excel.person+xml"
+	threadedCommentTimeLayout  = "2006-01-02T15:04:05.00"
+	legacyThreadedCommentNote  = "[Threaded comment]\n\nYour version of Excel allows you to read this threaded comment; however, any edits to it will get removed if the file is opened in a newer version of Excel. Learn more: https://go.microsoft.com/fwlink/?linkid=870924\n\n"
+)
+
+type xmlExtLst struct {
+	Inner string `xml:",innerxml"`
+}
+
+type xmlPersonList struct {
+	XMLName xml.Name     `xml:"http://schemas.microsoft.com/office/spreadsheetml/2018/threadedcomments personList"`
+	Person  []*xmlPerson `xml:"person"`
+	ExtLst  *xmlExtLst   `xml:"extLst,omitempty"`
+}
+
+type xmlPerson struct {
+	DisplayName string     `xml:"displayName,attr"`
+	ID          string     `xml:"id,attr"`
+	UserID      string     `xml:"userId,attr,omitempty"`
+	ProviderID  string     `xml:"providerId,attr,omitempty"`
+	ExtLst      *xmlExtLst `xml:"extLst,omitempty"`
+}
+
+type xmlThreadedComments struct {
+	XMLName         xml.Name              `xml:"http://schemas.microsoft.com/office/spreadsheetml/2018/threadedcomments ThreadedComments"`
+	ThreadedComment []*xmlThreadedComment `xml:"threadedComment"`
+	ExtLst          *xmlExtLst            `xml:"extLst,omitempty"`
+}
+
+type xmlThreadedComment struct {
+	Ref      string       `xml:"ref,attr"`
+	DT       string       `xml:"dT,attr,omitempty"`
+	PersonID string       `xml:"personId,attr"`
+	ID       string       `xml:"id,attr"`
+	ParentID string       `xml:"parentId,attr,omitempty"`
+	Done     string       `xml:"done,attr,omitempty"`
+	Text     string       `xml:"text"`
+	Mentions *xmlMentions `xml:"mentions,omitempty"`
+	ExtLst   *xmlExtLst   `xml:"extLst,omitempty"`
+}
+
+type xmlMentions struct {
+	Mention []*xmlMention `xml:"mention"`
+}
+
+type xmlMention struct {
+	MentionPersonID string `xml:"mentionpersonId,attr"`
+	MentionID       string `xml:"mentionId,attr"`
+	StartIndex      uint32 `xml:"startIndex,attr"`
+	Length          uint32 `xml:"length,attr"`
+}
+
+// threadedState holds the threaded comment and person parts of a workbook
+// that have been accessed, they are written back when the workbook is saved.
+type threadedState struct {
+	persons     *xmlPersonList
+	personsPath string
+	parts       map[*sml.Worksheet]*threadedPart
+}
+
+type threadedPart struct {
+	path string
+	x    *xmlThreadedComments
+}
+
+// newGUID returns a random GUID in the braced upper case form used by Excel.
+func newGUID() string {
+	b := make([]byte, 16)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		panic(err)
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("{%X-%X-%X-%X-%X}", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+func (wb *Workbook) threadedState() *threadedState {
+	if wb._threaded == nil {
+		wb._threaded = &threadedState{parts: map[*sml.Worksheet]*threadedPart{}}
+	}
+	return wb._threaded
+}
+
+// partPath returns the package path of a relationship target relative to the
+// directory dir.
+func partPath(dir, target string) string {
+	if strings.HasPrefix(target, "/") {
+		return strings.TrimPrefix(target, "/")
+	}
+	return path.Join(dir, target)
+}
+
+// readExtraFile decodes a part that was read into the extra files of the
+// workbook, returning false if there is no such part.
+func (wb *Workbook) readExtraFile(zipPath string, v interface{}) (bool, error) {
+	for _, ef := range wb.ExtraFiles {
+		if ef.ZipPath != zipPath {
+			continue
+		}
+		f, err := tempstorage.Open(ef.StoragePath)
+		if err != nil {
+			return false, err
+		}
+		defer f.Close()
+		if err := xml.NewDecoder(f).Decode(v); err != nil {
+			return false, fmt.Errorf("error decoding %s: %s", zipPath, err)
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// writeExtraFile encodes a part into the extra files of the workbook,
+// replacing any previous content.
+func (wb *Workbook) writeExtraFile(zipPath string, v interface{}) error {
+	f, err := tempstorage.TempFile(wb.TmpPath, "xml")
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := io.WriteString(f, xml.Header); err != nil {
+		return err
+	}
+	if err := xml.NewEncoder(f).Encode(v); err != nil {
+		return err
+	}
+	for i, ef := range wb.ExtraFiles {
+		if ef.ZipPath == zipPath {
+			wb.ExtraFiles[i].StoragePath = f.Name()
+			return nil
+		}
+	}
+	wb.ExtraFiles = append(wb.ExtraFiles, common.ExtraFile{ZipPath: zipPath, StoragePath: f.Name()})
+	return nil
+}
+
+// flushThreadedComments writes the accessed threaded comment and person parts
+// to the extra files so they're saved with the workbook.
+func (wb *Workbook) flushThreadedComments() error {
+	st := wb._threaded
+	if st == nil {
+		return nil
+	}
+	if st.persons != nil {
+		if err := wb.writeExtraFile(st.personsPath, st.persons); err != nil {
+			return err
+		}
+	}
+	for _, p := range st.parts {
+		if err := wb.writeExtraFile(p.path, p.x); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// personList returns the person list of the workbook, creating the part if
+// create is set and the workbook has none.
+func (wb *Workbook) personList(create bool) *xmlPersonList {
+	st := wb.threadedState()
+	if st.persons != nil {
+		return st.persons
+	}
+	for _, r := range wb._bcg.Relationships() {
+		if r.Type() != personType {
+			continue
+		}
+		pl := &xmlPersonList{}
+		zipPath := partPath("xl", r.Target())
+		if ok, err := wb.readExtraFile(zipPath, pl); err != nil || !ok {
+			continue
+		}
+		st.persons, st.personsPath = pl, zipPath
+		return pl
+	}
+	if !create {
+		return nil
+	}
+	st.persons = &xmlPersonList{}
+	st.personsPath = "xl/persons/person.xml"
+	wb._bcg.AddRelationship("persons/person.xml", personType)
+	wb.ContentTypes.AddOverride("/"+st.personsPath, personContentType)
+	return st.persons
+}
+
+// Person is an author of threaded comments.
+type Person struct {
+	w *Workbook
+	x *xmlPerson
+}
+
+// ID returns the unique identifier of the person.
+func (p Person) ID() string { return p.x.ID }
+
+// DisplayName returns the name displayed for the person.
+func (p Person) DisplayName() string { return p.x.DisplayName }
+
+// UserID returns the user identifier of the person within its identity
+// provider.
+func (p Person) UserID() string { return p.x.UserID }
+
+// ProviderID returns the identity provider of the person, e.g. AD or None.
+func (p Person) ProviderID() string { return p.x.ProviderID }
+
+// Persons returns the authors of threaded comments in the workbook.
+func (wb *Workbook) Persons() []Person {
+	pl := wb.personList(false)
+	if pl == nil {
+		return nil
+	}
+	ret := []Person{}
+	for _, p := range pl.Person {
+		ret = append(ret, Person{wb, p})
+	}
+	return ret
+}
+
+// AddPerson adds a threaded comment author to the workbook. The provider ID
+// should be "None" for people not tied to an identity provider.
+func (wb *Workbook) AddPerson(displayName, userID, providerID string) Person {
+	pl := wb.personList(true)
+	p := &xmlPerson{DisplayName: displayName, ID: newGUID(), UserID: userID, ProviderID: providerID}
+	pl.Person = append(pl.Person, p)
+	return Person{wb, p}
+}
+
+// personByName returns the person with a display name, adding it if needed.
+func (wb *Workbook) personByName(name string) Person {
+	for _, p := range wb.Persons() {
+		if p.DisplayName() == name {
+			return p
+		}
+	}
+	return wb.AddPerson(name, name, "None")
+}
+
+// personOrUnknown returns the person with an ID, or a person without a name if
+// the workbook doesn't list it.
+func (wb *Workbook) personOrUnknown(id string) Person {
+	for _, p := range wb.Persons() {
+		if p.ID() == id {
+			return p
+		}
+	}
+	return Person{wb, &xmlPerson{ID: id}}
+}
+
+// threadedPart returns the threaded comments part of the sheet, creating it
+// if create is set and the sheet has none.
+func (s *Sheet) threadedPart(create bool) *threadedPart {
+	st := s._fgeg.threadedState()
+	if p, ok := st.parts[s._bbbe]; ok {
+		return p
+	}
+	rels, ok := s.rels()
+	if !ok {
+		return nil
+	}
+	for _, r := range rels.Relationships() {
+		if r.Type() != threadedCommentType {
+			continue
+		}
+		x := &xmlThreadedComments{}
+		zipPath := partPath("xl/worksheets", r.Target())
+		if ok, err := s._fgeg.readExtraFile(zipPath, x); err != nil || !ok {
+			continue
+		}
+		p := &threadedPart{zipPath, x}
+		st.parts[s._bbbe] = p
+		return p
+	}
+	if !create {
+		return nil
+	}
+	used := map[string]bool{}
+	for _, ef := range s._fgeg.ExtraFiles {
+		used[ef.ZipPath] = true
+	}
+	for _, p := range st.parts {
+		used[p.path] = true
+	}
+	n := 1
+	for used[fmt.Sprintf("xl/threadedComments/threadedComment%d.xml", n)] {
+		n++
+	}
+	p := &threadedPart{fmt.Sprintf("xl/threadedComments/threadedComment%d.xml", n), &xmlThreadedComments{}}
+	rels.AddRelationship(fmt.Sprintf("../threadedComments/threadedComment%d.xml", n), threadedCommentType)
+	s._fgeg.ContentTypes.AddOverride("/"+p.path, threadedCommentContentType)
+	st.parts[s._bbbe] = p
+	return p
+}
+
+// ThreadedComment is a comment in a discussion thread attached to a cell.
+type ThreadedComment struct {
+	s    Sheet
+	part *threadedPart
+	x    *xmlThreadedComment
+}
+
+// Mention is a reference to a person within the text of a threaded comment.
+type Mention struct {
+	Person Person
+	// StartIndex and Length locate the mention within the comment text in
+	// UTF-16 code units.
+	StartIndex int
+	Length     int
+}
+
+// ID returns the unique identifier of the comment.
+func (c ThreadedComment) ID() string { return c.x.ID }
+
+// Reference returns the cell the comment is attached to.
+func (c ThreadedComment) Reference() string { return c.x.Ref }
+
+// Text returns the comment text.
+func (c ThreadedComment) Text() string { return c.x.Text }
+
+// Author returns the person that wrote the comment.
+func (c ThreadedComment) Author() Person {
+	return c.s._fgeg.personOrUnknown(c.x.PersonID)
+}
+
+// Time returns the time the comment was written.
+func (c ThreadedComment) Time() time.Time {
+	t, _ := time.Parse(threadedCommentTimeLayout, c.x.DT)
+	return t
+}
+
+// IsReply returns true if the comment is a reply to another comment.
+func (c ThreadedComment) IsReply() bool { return c.x.ParentID != "" }
+
+// root returns the comment that started the thread.
+func (c ThreadedComment) root() ThreadedComment {
+	if c.x.ParentID == "" {
+		return c
+	}
+	for _, x := range c.part.x.ThreadedComment {
+		if x.ID == c.x.ParentID {
+			return ThreadedComment{c.s, c.part, x}
+		}
+	}
+	return c
+}
+
+// IsResolved returns true if the thread of the comment is marked as resolved.
+func (c ThreadedComment) IsResolved() bool {
+	d := c.root().x.Done
+	return d == "1" || d == "true"
+}
+
+// Resolve marks the thread of the comment as resolved or reopens it.
+func (c ThreadedComment) Resolve(b bool) {
+	if b {
+		c.root().x.Done = "1"
+	} else {
+		c.root().x.Done = ""
+	}
+}
+
+// Replies returns the replies to the thread started by the comment.
+func (c ThreadedComment) Replies() []ThreadedComment {
+	ret := []ThreadedComment{}
+	for _, x := range c.part.x.ThreadedComment {
+		if x.ParentID == c.x.ID {
+			ret = append(ret, ThreadedComment{c.s, c.part, x})
+		}
+	}
+	return ret
+}
+
+// Reply adds a reply to the thread of the comment.
+func (c ThreadedComment) Reply(author, text string) ThreadedComment {
+	root := c.root()
+	x := c.s.newThreadedComment(root.x.Ref, author, text)
+	x.ParentID = root.x.ID
+	// replies follow the last comment of their thread
+	idx := len(c.part.x.ThreadedComment)
+	for i, e := range c.part.x.ThreadedComment {
+		if e == root.x || e.ParentID == root.x.ID {
+			idx = i + 1
+		}
+	}
+	tcs := append(c.part.x.ThreadedComment, nil)
+	copy(tcs[idx+1:], tcs[idx:])
+	tcs[idx] = x
+	c.part.x.ThreadedComment = tcs
+	root.updateLegacyComment()
+	return ThreadedComment{c.s, c.part, x}
+}
+
+// Mentions returns the people mentioned in the comment.
+func (c ThreadedComment) Mentions() []Mention {
+	if c.x.Mentions == nil {
+		return nil
+	}
+	ret := []Mention{}
+	for _, m := range c.x.Mentions.Mention {
+		ret = append(ret, Mention{Person: c.s._fgeg.personOrUnknown(m.MentionPersonID), StartIndex: int(m.StartIndex), Length: int(m.Length)})
+	}
+	return ret
+}
+
+// AddMention mentions a person in the comment. The mention refers to the
+// first "@DisplayName" in the text that isn't mentioned yet, which is appended
+// to the text if there is none.
+func (c ThreadedComment) AddMention(p Person) Mention {
+	tag := "@" + p.DisplayName()
+	units := utf16.Encode([]rune(c.x.Text))
+	tagUnits := utf16.Encode([]rune(tag))
+	taken := map[int]bool{}
+	for _, m := range c.Mentions() {
+		taken[m.StartIndex] = true
+	}
+	start := -1
+	for i := 0; i+len(tagUnits) <= len(units); i++ {
+		if !taken[i] && string(utf16.Decode(units[i:i+len(tagUnits)])) == tag {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		if c.x.Text != "" && !strings.HasSuffix(c.x.Text, " ") {
+			c.x.Text += " "
+		}
+		start = len(utf16.Encode([]rune(c.x.Text)))
+		c.x.Text += tag
+		c.root().updateLegacyComment()
+	}
+	if c.x.Mentions == nil {
+		c.x.Mentions = &xmlMentions{}
+	}
+	c.x.Mentions.Mention = append(c.x.Mentions.Mention, &xmlMention{
+		MentionPersonID: p.ID(),
+		MentionID:       newGUID(),
+		StartIndex:      uint32(start),
+		Length:          uint32(len(tagUnits)),
+	})
+	return Mention{Person: p, StartIndex: start, Length: len(tagUnits)}
+}
+
+func (s *Sheet) newThreadedComment(ref, author, text string) *xmlThreadedComment {
+	return &xmlThreadedComment{
+		Ref:      ref,
+		DT:       time.Now().UTC().Format(threadedCommentTimeLayout),
+		PersonID: s._fgeg.personByName(author).ID(),
+		ID:       newGUID(),
+		Text:     text,
+	}
+}
+
+// AddThreadedComment starts a comment thread on a cell. A legacy note with
+// the text of the thread is added as well for applications that don't support
+// threaded comments. A cell has a single thread or note, an error is returned
+// if it already has one; use Reply to add to an existing thread.
+func (s *Sheet) AddThreadedComment(cellRef, author, text string) (ThreadedComment, error) {
+	cref, err := reference.ParseCellReference(cellRef)
+	if err != nil {
+		return ThreadedComment{}, err
+	}
+	ref := fmt.Sprintf("%s%d", cref.Column, cref.RowIdx)
+	for _, tc := range s.ThreadedComments() {
+		if strings.EqualFold(tc.Reference(), ref) {
+			return ThreadedComment{}, fmt.Errorf("cell %s already has a comment thread", ref)
+		}
+	}
+	if s.hasNote(ref) {
+		return ThreadedComment{}, fmt.Errorf("cell %s already has a note", ref)
+	}
+	part := s.threadedPart(true)
+	x := s.newThreadedComment(ref, author, text)
+	part.x.ThreadedComment = append(part.x.ThreadedComment, x)
+
+	comments := s.Comments()
+	comments.AddComment(ref, "tc="+x.ID)
+	if vd := s.commentDrawing(); vd != nil {
+		vd.Shape = append(vd.Shape, vmldrawing.NewCommentShape(int64(cref.ColumnIdx), int64(cref.RowIdx-1)))
+	}
+	tc := ThreadedComment{*s, part, x}
+	tc.updateLegacyComment()
+	return tc, nil
+}
+
+// hasNote returns whether a cell of the sheet has a legacy note.
+func (s *Sheet) hasNote(ref string) bool {
+	for i, ws := range s._fgeg._fbef {
+		if ws != s._bbbe || s._fgeg._edca[i] == nil {
+			continue
+		}
+		for _, lc := range s._fgeg._edca[i].CommentList.Comment {
+			if strings.EqualFold(lc.RefAttr, ref) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// commentDrawing returns the legacy drawing of the sheet holding the shapes
+// of its notes, adding it if the sheet doesn't have one.
+func (s *Sheet) commentDrawing() *vmldrawing.Container {
+	c := s.vmlDrawing(true)
+	if c == nil {
+		return nil
+	}
+	note := vmldrawing.NewCommentDrawing().ShapeType
+	for _, st := range append([]*vml.Shapetype{c.ShapeType}, c.ShapeTypes...) {
+		if st != nil && st.IdAttr != nil && *st.IdAttr == *note.IdAttr {
+			return c
+		}
+	}
+	if c.ShapeType == nil {
+		c.ShapeType = note
+	} else {
+		c.ShapeTypes = append(c.ShapeTypes, note)
+	}
+	return c
+}
+
+// ThreadedComments returns the comments that start a thread on the sheet.
+func (s *Sheet) ThreadedComments() []ThreadedComment {
+	part := s.threadedPart(false)
+	if part == nil {
+		return nil
+	}
+	ret := []ThreadedComment{}
+	for _, x := range part.x.ThreadedComment {
+		if x.ParentID == "" {
+			ret = append(ret, ThreadedComment{*s, part, x})
+		}
+	}
+	return ret
+}
+
+// legacyComment returns the note written for a thread.
+func (c ThreadedComment) legacyComment() *sml.CT_Comment {
+	for i, ws := range c.s._fgeg._fbef {
+		if ws != c.s._bbbe || c.s._fgeg._edca[i] == nil {
+			continue
+		}
+		for _, lc := range c.s._fgeg._edca[i].CommentList.Comment {
+			if lc.GuidAttr != nil && *lc.GuidAttr == c.x.ID {
+				return lc
+			}
+		}
+		authorID := -1
+		for j, a := range c.s._fgeg._edca[i].Authors.Author {
+			if a == "tc="+c.x.ID {
+				authorID = j
+			}
+		}
+		for _, lc := range c.s._fgeg._edca[i].CommentList.Comment {
+			if int(lc.AuthorIdAttr) == authorID {
+				return lc
+			}
+		}
+	}
+	return nil
+}
+
+// updateLegacyComment rewrites the note of a thread with its current text.
+func (c ThreadedComment) updateLegacyComment() {
+	lc := c.legacyComment()
+	if lc == nil {
+		return
+	}
+	id := c.x.ID
+	lc.GuidAttr = &id
+	text := legacyThreadedCommentNote + "Comment:\n    " + c.x.Text
+	for _, r := range c.Replies() {
+		text += "\nReply:\n    " + r.x.Text
+	}
+	lc.Text = sml.NewCT_Rst()
+	lc.Text.T = &text
+}
@@ -0,0 +1,101 @@
+package spreadsheet
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestThreadedComments(t *testing.T) {
+	wb := New()
+	sheet := wb.AddSheet()
+	tc, err := sheet.AddThreadedComment("$B$2", "Alice", "Please check")
+	if err != nil {
+		t.Fatalf("error adding comment: %s", err)
+	}
+	reply := tc.Reply("Bob", "Done")
+	reply.AddMention(wb.personByName("Alice"))
+	reply.Resolve(true)
+
+	if tc.Reference() != "B2" || tc.Author().DisplayName() != "Alice" {
+		t.Errorf("unexpected comment %s by %s", tc.Reference(), tc.Author().DisplayName())
+	}
+	if !tc.IsResolved() {
+		t.Error("expected thread to be resolved")
+	}
+	if len(wb.Persons()) != 2 {
+		t.Errorf("expected 2 persons, got %d", len(wb.Persons()))
+	}
+
+	legacy := sheet.Comments().Comments()
+	if len(legacy) != 1 {
+		t.Fatalf("expected 1 legacy note, got %d", len(legacy))
+	}
+	if lc := tc.legacyComment(); lc == nil || !strings.HasSuffix(*lc.Text.T, "Please check\nReply:\n    Done @Alice") {
+		t.Error("expected legacy note to contain the thread")
+	}
+
+	// reload the parts as written on save
+	if err := wb.flushThreadedComments(); err != nil {
+		t.Fatalf("error writing threaded comments: %s", err)
+	}
+	wb._threaded = nil
+	threads := sheet.ThreadedComments()
+	if len(threads) != 1 {
+		t.Fatalf("expected 1 thread, got %d", len(threads))
+	}
+	replies := threads[0].Replies()
+	if len(replies) != 1 || replies[0].Text() != "Done @Alice" || replies[0].Author().DisplayName() != "Bob" {
+		t.Fatalf("unexpected replies %v", replies)
+	}
+	mentions := replies[0].Mentions()
+	if len(mentions) != 1 || mentions[0].Person.DisplayName() != "Alice" || mentions[0].StartIndex != 5 || mentions[0].Length != 6 {
+		t.Errorf("unexpected mentions %+v", mentions)
+	}
+	if !threads[0].IsResolved() {
+		t.Error("expected resolved state to be kept")
+	}
+	threads[0].Resolve(false)
+	if replies[0].IsResolved() {
+		t.Error("expected thread to be reopened")
+	}
+}
+
+func TestThreadedCommentsSheetDrawing(t *testing.T) {
+	wb := New()
+	first := wb.AddSheet()
+	second := wb.AddSheet()
+	if _, err := first.AddThreadedComment("A1", "Alice", "First"); err != nil {
+		t.Fatalf("error adding comment: %s", err)
+	}
+	if _, err := second.AddThreadedComment("C3", "Alice", "Second"); err != nil {
+		t.Fatalf("error adding comment: %s", err)
+	}
+	a, b := first.vmlDrawing(false), second.vmlDrawing(false)
+	if a == nil || b == nil || a == b {
+		t.Fatal("expected each sheet to have its own legacy drawing")
+	}
+	if len(a.Shape) != 1 || len(b.Shape) != 1 {
+		t.Fatalf("expected one note shape per sheet, got %d and %d", len(a.Shape), len(b.Shape))
+	}
+	if *b.Shape[0].IdAttr != "cs_2_2" || b.ShapeType == nil || *b.ShapeType.IdAttr != "_x0000_t202" {
+		t.Errorf("unexpected note shape on the second sheet")
+	}
+}
+
+func TestThreadedCommentsSameCell(t *testing.T) {
+	wb := New()
+	sheet := wb.AddSheet()
+	if _, err := sheet.AddThreadedComment("B2", "Alice", "First"); err != nil {
+		t.Fatalf("error adding comment: %s", err)
+	}
+	if _, err := sheet.AddThreadedComment("$B$2", "Bob", "Second"); err == nil {
+		t.Error("expected an error adding a second thread to the cell")
+	}
+	sheet.Comments().AddComment("C3", "Bob")
+	if _, err := sheet.AddThreadedComment("C3", "Alice", "Third"); err == nil {
+		t.Error("expected an error adding a thread to a cell with a note")
+	}
+	if n := len(sheet.ThreadedComments()); n != 1 {
+		t.Errorf("expected one thread, got %d", n)
+	}
+}
@@ -0,0 +1,478 @@
+package spreadsheet
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	unioffice "github.com/yaklabco/unioffice/v2"
+	"github.com/yaklabco/unioffice/v2/color"
+	"github.com/yaklabco/unioffice/v2/schema/soo/sml"
+)
+
+// SetView sets the view mode of the sheet, e.g. page break preview or page
+// layout.
+func (s SheetView) SetView(v sml.ST_SheetViewType) {
+	if v == sml.ST_SheetViewTypeNormal {
+		v = sml.ST_SheetViewTypeUnset
+	}
+	s._agec.ViewAttr = v
+}
+
+// View returns the view mode of the sheet.
+func (s SheetView) View() sml.ST_SheetViewType {
+	if s._agec.ViewAttr == sml.ST_SheetViewTypeUnset {
+		return sml.ST_SheetViewTypeNormal
+	}
+	return s._agec.ViewAttr
+}
+
+// State returns the state of the pane (frozen/split/frozen-split), or unset if
+// the view has no pane.
+func (s SheetView) State() sml.ST_PaneState {
+	if s._agec.Pane == nil {
+		return sml.ST_PaneStateUnset
+	}
+	return s._agec.Pane.StateAttr
+}
+
+// SetShowGridLines controls whether grid lines are displayed.
+func (s SheetView) SetShowGridLines(b bool) { setBoolAttr(&s._agec.ShowGridLinesAttr, b, true) }
+
+// ShowGridLines returns whether grid lines are displayed.
+func (s SheetView) ShowGridLines() bool { return boolAttr(s._agec.ShowGridLinesAttr, true) }
+
+// SetShowRowColHeaders controls whether the row and column headings are
+// displayed.
+func (s SheetView) SetShowRowColHeaders(b bool) {
+	setBoolAttr(&s._agec.ShowRowColHeadersAttr, b, true)
+}
+
+// ShowRowColHeaders returns whether the row and column headings are displayed.
+func (s SheetView) ShowRowColHeaders() bool { return boolAttr(s._agec.ShowRowColHeadersAttr, true) }
+
+// SetShowZeros controls whether zero values are displayed, or left blank.
+func (s SheetView) SetShowZeros(b bool) { setBoolAttr(&s._agec.ShowZerosAttr, b, true) }
+
+// SetShowFormulas controls whether cells display their formulas instead of
+// their values.
+func (s SheetView) SetShowFormulas(b bool) { setBoolAttr(&s._agec.ShowFormulasAttr, b, false) }
+
+// SetRightToLeft controls whether the sheet is displayed right-to-left, with
+// column A on the right.
+func (s SheetView) SetRightToLeft(b bool) { setBoolAttr(&s._agec.RightToLeftAttr, b, false) }
+
+// RightToLeft returns whether the sheet is displayed right-to-left.
+func (s SheetView) RightToLeft() bool { return boolAttr(s._agec.RightToLeftAttr, false) }
+
+// SetTabSelected controls whether the sheet tab is selected. Selected sheets
+// are grouped for editing.
+func (s SheetView) SetTabSelected(b bool) { setBoolAttr(&s._agec.TabSelectedAttr, b, false) }
+
+// SetSelection selects the cells of sqref, a space separated list of
+// references, with activeCell as the active cell. The selection applies to the
+// active pane of the view.
+func (s SheetView) SetSelection(activeCell, sqref string) {
+	pane := sml.ST_PaneUnset
+	if s._agec.Pane != nil {
+		pane = s._agec.Pane.ActivePaneAttr
+	}
+	sel := newSelection(pane, activeCell, sqref)
+	for i, x := range s._agec.Selection {
+		if x.PaneAttr == pane {
+			s._agec.Selection[i] = sel
+			return
+		}
+	}
+	s._agec.Selection = append(s._agec.Selection, sel)
+}
+
+// Selection returns the active cell and the selected cells of the active pane.
+func (s SheetView) Selection() (activeCell, sqref string) {
+	pane := sml.ST_PaneUnset
+	if s._agec.Pane != nil {
+		pane = s._agec.Pane.ActivePaneAttr
+	}
+	for _, x := range s._agec.Selection {
+		if x.PaneAttr == pane {
+			return selectionRefs(x)
+		}
+	}
+	return "", ""
+}
+
+func newSelection(pane sml.ST_Pane, activeCell, sqref string) *sml.CT_Selection {
+	sel := sml.NewCT_Selection()
+	sel.PaneAttr = pane
+	sel.ActiveCellAttr = unioffice.String(activeCell)
+	refs := sml.ST_Sqref(strings.Fields(sqref))
+	if len(refs) == 0 {
+		refs = sml.ST_Sqref{activeCell}
+	}
+	sel.SqrefAttr = &refs
+	return sel
+}
+
+func selectionRefs(x *sml.CT_Selection) (activeCell, sqref string) {
+	if x.ActiveCellAttr != nil {
+		activeCell = *x.ActiveCellAttr
+	}
+	if x.SqrefAttr != nil {
+		sqref = strings.Join(*x.SqrefAttr, " ")
+	}
+	return
+}
+
+// SetTabColor sets the color of the sheet tab.
+func (s *Sheet) SetTabColor(c color.Color) {
+	if s._bbbe.SheetPr == nil {
+		s._bbbe.SheetPr = sml.NewCT_SheetPr()
+	}
+	s._bbbe.SheetPr.TabColor = sml.NewCT_Color()
+	s._bbbe.SheetPr.TabColor.RgbAttr = c.AsRGBAString()
+}
+
+// ClearTabColor removes the color of the sheet tab.
+func (s *Sheet) ClearTabColor() {
+	if s._bbbe.SheetPr != nil {
+		s._bbbe.SheetPr.TabColor = nil
+	}
+}
+
+// TabColor returns the color of the sheet tab, or color.Auto if it isn't set.
+func (s *Sheet) TabColor() color.Color {
+	if s._bbbe.SheetPr == nil {
+		return color.Auto
+	}
+	return s._fgeg.ResolveColor(s._bbbe.SheetPr.TabColor)
+}
+
+// State returns whether the sheet is visible, hidden or very hidden.
+func (s *Sheet) State() sml.ST_SheetState {
+	if s._abea.StateAttr == sml.ST_SheetStateUnset {
+		return sml.ST_SheetStateVisible
+	}
+	return s._abea.StateAttr
+}
+
+// SetState shows or hides the sheet. Hidden sheets can be unhidden by users,
+// very hidden sheets only programmatically. The last visible sheet of a
+// workbook can't be hidden. If the active sheet is hidden, the first visible
+// sheet becomes active.
+func (s *Sheet) SetState(st sml.ST_SheetState) error {
+	if st == sml.ST_SheetStateVisible {
+		st = sml.ST_SheetStateUnset
+	}
+	wb := s._fgeg
+	if st != sml.ST_SheetStateUnset {
+		visible := -1
+		for i, x := range wb._gbadf.Sheets.Sheet {
+			if x != s._abea && (x.StateAttr == sml.ST_SheetStateUnset || x.StateAttr == sml.ST_SheetStateVisible) {
+				visible = i
+				break
+			}
+		}
+		if visible < 0 {
+			return errors.New("can't hide the last visible sheet")
+		}
+		if bv := wb._gbadf.BookViews; bv != nil && len(bv.WorkbookView) > 0 {
+			active := bv.WorkbookView[0].ActiveTabAttr
+			if active != nil && int(*active) < len(wb._gbadf.Sheets.Sheet) && wb._gbadf.Sheets.Sheet[*active] == s._abea {
+				wb.SetActiveSheetIndex(uint32(visible))
+			}
+		}
+		for _, v := range s.SheetViews() {
+			v.SetTabSelected(false)
+		}
+	}
+	s._abea.StateAttr = st
+	return nil
+}
+
+// AllSheets returns all sheets of the workbook, including the hidden and very
+// hidden sheets that Sheets omits.
+func (wb *Workbook) AllSheets() []Sheet {
+	ret := []Sheet{}
+	for i, ws := range wb._fbef {
+		ret = append(ret, Sheet{wb, wb._gbadf.Sheets.Sheet[i], ws})
+	}
+	return ret
+}
+
+// sheetByName returns the sheet with a name, comparing names case
+// insensitively as formulas do. Unlike GetSheet it finds hidden sheets too.
+func (wb *Workbook) sheetByName(name string) (Sheet, error) {
+	for _, s := range wb.AllSheets() {
+		if strings.EqualFold(s.Name(), name) {
+			return s, nil
+		}
+	}
+	return Sheet{}, ErrorNotFound
+}
+
+// WorkbookView is the window of a workbook in the application.
+type WorkbookView struct{ x *sml.CT_BookView }
+
+// WorkbookView returns the first workbook view, creating it if necessary.
+func (wb *Workbook) WorkbookView() WorkbookView {
+	if wb._gbadf.BookViews == nil {
+		wb._gbadf.BookViews = sml.NewCT_BookViews()
+	}
+	if len(wb._gbadf.BookViews.WorkbookView) == 0 {
+		wb._gbadf.BookViews.WorkbookView = append(wb._gbadf.BookViews.WorkbookView, sml.NewCT_BookView())
+	}
+	return WorkbookView{wb._gbadf.BookViews.WorkbookView[0]}
+}
+
+// X returns the inner wrapped XML type.
+func (v WorkbookView) X() *sml.CT_BookView { return v.x }
+
+// SetWindow sets the position and size of the window in twips.
+func (v WorkbookView) SetWindow(x, y int32, width, height uint32) {
+	v.x.XWindowAttr = unioffice.Int32(x)
+	v.x.YWindowAttr = unioffice.Int32(y)
+	v.x.WindowWidthAttr = unioffice.Uint32(width)
+	v.x.WindowHeightAttr = unioffice.Uint32(height)
+}
+
+// SetMinimized controls whether the window is minimized.
+func (v WorkbookView) SetMinimized(b bool) { setBoolAttr(&v.x.MinimizedAttr, b, false) }
+
+// SetVisibility sets whether the window is visible or hidden.
+func (v WorkbookView) SetVisibility(vis sml.ST_Visibility) { v.x.VisibilityAttr = vis }
+
+// SetShowSheetTabs controls whether the sheet tabs are displayed.
+func (v WorkbookView) SetShowSheetTabs(b bool) { setBoolAttr(&v.x.ShowSheetTabsAttr, b, true) }
+
+// SetShowScrollBars controls whether the horizontal and vertical scroll bars
+// are displayed.
+func (v WorkbookView) SetShowScrollBars(horizontal, vertical bool) {
+	setBoolAttr(&v.x.ShowHorizontalScrollAttr, horizontal, true)
+	setBoolAttr(&v.x.ShowVerticalScrollAttr, vertical, true)
+}
+
+// SetTabRatio sets the width of the sheet tab area relative to the horizontal
+// scroll bar, in thousandths (0-1000).
+func (v WorkbookView) SetTabRatio(r uint32) { v.x.TabRatioAttr = unioffice.Uint32(r) }
+
+// SetFirstSheet sets the index of the first sheet tab displayed in the tab
+// area.
+func (v WorkbookView) SetFirstSheet(idx uint32) { v.x.FirstSheetAttr = unioffice.Uint32(idx) }
+
+// ActiveSheetIndex returns the index of the active sheet.
+func (v WorkbookView) ActiveSheetIndex() uint32 {
+	if v.x.ActiveTabAttr == nil {
+		return 0
+	}
+	return *v.x.ActiveTabAttr
+}
+
+// CustomView is a named custom view of a workbook, as listed in the Custom
+// Views dialog of Excel. It holds a custom sheet view for every sheet.
+type CustomView struct {
+	wb *Workbook
+	x  *sml.CT_CustomWorkbookView
+}
+
+// X returns the inner wrapped XML type.
+func (v CustomView) X() *sml.CT_CustomWorkbookView { return v.x }
+
+// Name returns the name of the custom view.
+func (v CustomView) Name() string { return v.x.NameAttr }
+
+// SetIncludePrintSettings controls whether the custom view includes the page
+// setup of the sheets.
+func (v CustomView) SetIncludePrintSettings(b bool) {
+	setBoolAttr(&v.x.IncludePrintSettingsAttr, b, true)
+}
+
+// SetIncludeHiddenRowCol controls whether the custom view includes the hidden
+// rows and columns and filter settings of the sheets.
+func (v CustomView) SetIncludeHiddenRowCol(b bool) {
+	setBoolAttr(&v.x.IncludeHiddenRowColAttr, b, true)
+}
+
+// SetActiveSheet sets the sheet that is active when the custom view is shown.
+func (v CustomView) SetActiveSheet(s Sheet) { v.x.ActiveSheetIdAttr = s._abea.SheetIdAttr }
+
+// SetWindow sets the position and size of the window in twips.
+func (v CustomView) SetWindow(x, y int32, width, height uint32) {
+	v.x.XWindowAttr = unioffice.Int32(x)
+	v.x.YWindowAttr = unioffice.Int32(y)
+	v.x.WindowWidthAttr = width
+	v.x.WindowHeightAttr = height
+}
+
+// SheetView returns the custom sheet view of the sheet in the custom view,
+// creating it if necessary.
+func (v CustomView) SheetView(s Sheet) CustomSheetView {
+	if s._bbbe.CustomSheetViews == nil {
+		s._bbbe.CustomSheetViews = sml.NewCT_CustomSheetViews()
+	}
+	for _, x := range s._bbbe.CustomSheetViews.CustomSheetView {
+		if strings.EqualFold(x.GuidAttr, v.x.GuidAttr) {
+			return CustomSheetView{x}
+		}
+	}
+	x := sml.NewCT_CustomSheetView()
+	x.GuidAttr = v.x.GuidAttr
+	x.ScaleAttr = unioffice.Uint32(100)
+	x.TopLeftCellAttr = unioffice.String("A1")
+	s._bbbe.CustomSheetViews.CustomSheetView = append(s._bbbe.CustomSheetViews.CustomSheetView, x)
+	return CustomSheetView{x}
+}
+
+// AddCustomView adds a custom view with a custom sheet view for every sheet,
+// initially showing the sheets as they are.
+func (wb *Workbook) AddCustomView(name string) (CustomView, error) {
+	if _, ok := wb.customView(name); ok {
+		return CustomView{}, fmt.Errorf("custom view %s already exists", name)
+	}
+	if wb._gbadf.CustomWorkbookViews == nil {
+		wb._gbadf.CustomWorkbookViews = sml.NewCT_CustomWorkbookViews()
+	}
+	x := sml.NewCT_CustomWorkbookView()
+	x.NameAttr = name
+	x.GuidAttr = newGUID()
+	x.WindowWidthAttr, x.WindowHeightAttr = 20000, 10000
+	if bv := wb.WorkbookView().x; bv.WindowWidthAttr != nil && bv.WindowHeightAttr != nil {
+		x.WindowWidthAttr, x.WindowHeightAttr = *bv.WindowWidthAttr, *bv.WindowHeightAttr
+	}
+	sheets := wb.AllSheets()
+	if len(sheets) > 0 {
+		active := int(wb.WorkbookView().ActiveSheetIndex())
+		if active >= len(sheets) {
+			active = 0
+		}
+		x.ActiveSheetIdAttr = sheets[active]._abea.SheetIdAttr
+	}
+	wb._gbadf.CustomWorkbookViews.CustomWorkbookView = append(wb._gbadf.CustomWorkbookViews.CustomWorkbookView, x)
+
+	v := CustomView{wb, x}
+	for _, s := range sheets {
+		csv := v.SheetView(s)
+		csv.x.StateAttr = s._abea.StateAttr
+		if views := s.SheetViews(); len(views) > 0 {
+			sv := views[0].X()
+			csv.x.ViewAttr = sv.ViewAttr
+			csv.x.ShowGridLinesAttr = sv.ShowGridLinesAttr
+			csv.x.ShowRowColAttr = sv.ShowRowColHeadersAttr
+			if sv.ZoomScaleAttr != nil {
+				csv.x.ScaleAttr = unioffice.Uint32(*sv.ZoomScaleAttr)
+			}
+			if sv.TopLeftCellAttr != nil {
+				csv.x.TopLeftCellAttr = unioffice.String(*sv.TopLeftCellAttr)
+			}
+		}
+	}
+	return v, nil
+}
+
+func (wb *Workbook) customView(name string) (CustomView, bool) {
+	if wb._gbadf.CustomWorkbookViews != nil {
+		for _, x := range wb._gbadf.CustomWorkbookViews.CustomWorkbookView {
+			if strings.EqualFold(x.NameAttr, name) {
+				return CustomView{wb, x}, true
+			}
+		}
+	}
+	return CustomView{}, false
+}
+
+// CustomViews returns the custom views of the workbook.
+func (wb *Workbook) CustomViews() []CustomView {
+	ret := []CustomView{}
+	if wb._gbadf.CustomWorkbookViews != nil {
+		for _, x := range wb._gbadf.CustomWorkbookViews.CustomWorkbookView {
+			ret = append(ret, CustomView{wb, x})
+		}
+	}
+	return ret
+}
+
+// RemoveCustomView removes the custom view with the given name and its custom
+// sheet views.
+func (wb *Workbook) RemoveCustomView(name string) error {
+	v, ok := wb.customView(name)
+	if !ok {
+		return ErrorNotFound
+	}
+	views := wb._gbadf.CustomWorkbookViews
+	for i, x := range views.CustomWorkbookView {
+		if x == v.x {
+			views.CustomWorkbookView = append(views.CustomWorkbookView[:i], views.CustomWorkbookView[i+1:]...)
+			break
+		}
+	}
+	if len(views.CustomWorkbookView) == 0 {
+		wb._gbadf.CustomWorkbookViews = nil
+	}
+	for _, ws := range wb._fbef {
+		if ws.CustomSheetViews == nil {
+			continue
+		}
+		kept := ws.CustomSheetViews.CustomSheetView[:0]
+		for _, x := range ws.CustomSheetViews.CustomSheetView {
+			if !strings.EqualFold(x.GuidAttr, v.x.GuidAttr) {
+				kept = append(kept, x)
+			}
+		}
+		ws.CustomSheetViews.CustomSheetView = kept
+		if len(kept) == 0 {
+			ws.CustomSheetViews = nil
+		}
+	}
+	return nil
+}
+
+// CustomSheetView is the view of a sheet in a custom view.
+type CustomSheetView struct{ x *sml.CT_CustomSheetView }
+
+// X returns the inner wrapped XML type.
+func (v CustomSheetView) X() *sml.CT_CustomSheetView { return v.x }
+
+// SetView sets the view mode of the sheet.
+func (v CustomSheetView) SetView(t sml.ST_SheetViewType) {
+	if t == sml.ST_SheetViewTypeNormal {
+		t = sml.ST_SheetViewTypeUnset
+	}
+	v.x.ViewAttr = t
+}
+
+// SetZoom sets the zoom level in percent.
+func (v CustomSheetView) SetZoom(pct uint32) { v.x.ScaleAttr = unioffice.Uint32(pct) }
+
+// SetShowGridLines controls whether grid lines are displayed.
+func (v CustomSheetView) SetShowGridLines(b bool) { setBoolAttr(&v.x.ShowGridLinesAttr, b, true) }
+
+// SetShowRowColHeaders controls whether the row and column headings are
+// displayed.
+func (v CustomSheetView) SetShowRowColHeaders(b bool) { setBoolAttr(&v.x.ShowRowColAttr, b, true) }
+
+// SetState shows or hides the sheet in the custom view.
+func (v CustomSheetView) SetState(st sml.ST_SheetState) {
+	if st == sml.ST_SheetStateVisible {
+		st = sml.ST_SheetStateUnset
+	}
+	v.x.StateAttr = st
+}
+
+// SetTopLeft sets the top left visible cell.
+func (v CustomSheetView) SetTopLeft(cellRef string) { v.x.TopLeftCellAttr = unioffice.String(cellRef) }
+
+// SetSelection selects the cells of sqref with activeCell as the active cell.
+func (v CustomSheetView) SetSelection(activeCell, sqref string) {
+	pane := sml.ST_PaneUnset
+	if v.x.Pane != nil {
+		pane = v.x.Pane.ActivePaneAttr
+	}
+	v.x.Selection = newSelection(pane, activeCell, sqref)
+}
+
+// Selection returns the active cell and the selected cells.
+func (v CustomSheetView) Selection() (activeCell, sqref string) {
+	if v.x.Selection == nil {
+		return "", ""
+	}
+	return selectionRefs(v.x.Selection)
+}
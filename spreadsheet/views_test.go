@@ -0,0 +1,118 @@
+package spreadsheet
+
+import (
+	"testing"
+
+	"github.com/yaklabco/unioffice/v2/color"
+	"github.com/yaklabco/unioffice/v2/schema/soo/sml"
+)
+
+func TestSheetViewSettings(t *testing.T) {
+	wb := New()
+	sheet := wb.AddSheet()
+	v := sheet.InitialView()
+	if v.View() != sml.ST_SheetViewTypeNormal || !v.ShowGridLines() || v.RightToLeft() {
+		t.Errorf("unexpected view defaults")
+	}
+	v.SetView(sml.ST_SheetViewTypePageBreakPreview)
+	v.SetShowGridLines(false)
+	v.SetShowRowColHeaders(false)
+	v.SetRightToLeft(true)
+	if v.View() != sml.ST_SheetViewTypePageBreakPreview || v.ShowGridLines() || v.ShowRowColHeaders() || !v.RightToLeft() {
+		t.Errorf("view settings not applied")
+	}
+
+	sheet.SetFrozen(true, true)
+	v = sheet.InitialView()
+	v.SetState(sml.ST_PaneStateFrozenSplit)
+	v.X().Pane.ActivePaneAttr = sml.ST_PaneBottomRight
+	v.SetSelection("C5", "C5:D8 F1")
+	v.SetSelection("C6", "")
+	if active, sqref := v.Selection(); active != "C6" || sqref != "C6" {
+		t.Errorf("unexpected selection %s %s", active, sqref)
+	}
+	if len(v.X().Selection) != 1 || v.X().Selection[0].PaneAttr != sml.ST_PaneBottomRight {
+		t.Errorf("expected a single selection in the active pane")
+	}
+	if v.State() != sml.ST_PaneStateFrozenSplit {
+		t.Errorf("expected frozen split pane")
+	}
+
+	if !sheet.TabColor().IsAuto() {
+		t.Errorf("expected no tab color")
+	}
+	sheet.SetTabColor(color.Red)
+	if got := sheet.TabColor(); *got.AsRGBString() != "ff0000" {
+		t.Errorf("expected red tab, got %s", *got.AsRGBString())
+	}
+}
+
+func TestSheetState(t *testing.T) {
+	wb := New()
+	first := wb.AddSheet()
+	second := wb.AddSheet()
+	wb.SetActiveSheet(first)
+
+	if err := first.SetState(sml.ST_SheetStateVeryHidden); err != nil {
+		t.Fatalf("error hiding sheet: %s", err)
+	}
+	if first.State() != sml.ST_SheetStateVeryHidden {
+		t.Errorf("expected very hidden sheet")
+	}
+	if wb.WorkbookView().ActiveSheetIndex() != 1 {
+		t.Errorf("expected the visible sheet to become active")
+	}
+	if len(wb.Sheets()) != 1 || len(wb.AllSheets()) != 2 {
+		t.Errorf("expected hidden sheet only in AllSheets")
+	}
+	if err := second.SetState(sml.ST_SheetStateHidden); err == nil {
+		t.Errorf("expected error hiding the last visible sheet")
+	}
+	if err := wb.AllSheets()[0].SetState(sml.ST_SheetStateVisible); err != nil || len(wb.Sheets()) != 2 {
+		t.Errorf("expected sheet to be visible again")
+	}
+}
+
+func TestCustomViews(t *testing.T) {
+	wb := New()
+	first := wb.AddSheet()
+	second := wb.AddSheet()
+	first.InitialView().SetZoom(80)
+	wv := wb.WorkbookView()
+	wv.SetWindow(0, 0, 28800, 12000)
+	wv.SetShowSheetTabs(false)
+
+	cv, err := wb.AddCustomView("Print")
+	if err != nil {
+		t.Fatalf("error adding custom view: %s", err)
+	}
+	if _, err := wb.AddCustomView("print"); err == nil {
+		t.Errorf("expected error for duplicate custom view")
+	}
+	if cv.X().WindowWidthAttr != 28800 || cv.X().GuidAttr == "" {
+		t.Errorf("unexpected custom view %+v", cv.X())
+	}
+	csv := cv.SheetView(first)
+	if *csv.X().ScaleAttr != 80 {
+		t.Errorf("expected custom sheet view to copy the zoom")
+	}
+	csv.SetView(sml.ST_SheetViewTypePageLayout)
+	csv.SetSelection("B2", "B2:C3")
+	cv.SheetView(second).SetState(sml.ST_SheetStateHidden)
+	if len(first.X().CustomSheetViews.CustomSheetView) != 1 || second.X().CustomSheetViews.CustomSheetView[0].StateAttr != sml.ST_SheetStateHidden {
+		t.Errorf("expected a custom sheet view per sheet")
+	}
+	if active, sqref := csv.Selection(); active != "B2" || sqref != "B2:C3" {
+		t.Errorf("unexpected selection %s %s", active, sqref)
+	}
+
+	if len(wb.CustomViews()) != 1 || wb.CustomViews()[0].Name() != "Print" {
+		t.Errorf("expected one custom view")
+	}
+	if err := wb.RemoveCustomView("Print"); err != nil {
+		t.Fatalf("error removing custom view: %s", err)
+	}
+	if wb.X().CustomWorkbookViews != nil || first.X().CustomSheetViews != nil {
+		t.Errorf("expected custom views to be removed")
+	}
+}
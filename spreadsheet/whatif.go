@@ -0,0 +1,426 @@
+package spreadsheet
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/yaklabco/unioffice/v2"
+	"github.com/yaklabco/unioffice/v2/common/logger"
+	"github.com/yaklabco/unioffice/v2/schema/soo/sml"
+	"github.com/yaklabco/unioffice/v2/spreadsheet/formula"
+	"github.com/yaklabco/unioffice/v2/spreadsheet/reference"
+)
+
+// ErrGoalSeekNotConverged is returned by GoalSeek if no value of the changing
+// cell was found that brings the set cell within tolerance of the target.
+var ErrGoalSeekNotConverged = errors.New("goal seek did not converge")
+
+// GoalSeekOptions controls the iteration performed by GoalSeek.
+type GoalSeekOptions struct {
+	// MaxIterations is the maximum number of evaluations of the set cell,
+	// defaults to 100.
+	MaxIterations int
+	// Tolerance is the maximum allowed difference between the set cell and the
+	// target value, defaults to 0.001.
+	Tolerance float64
+}
+
+// GoalSeekResult is the outcome of a GoalSeek.
+type GoalSeekResult struct {
+	// Value is the value of the changing cell.
+	Value float64
+	// Result is the value of the set cell with the changing cell set to Value.
+	Result float64
+	// Iterations is the number of times the set cell was evaluated.
+	Iterations int
+}
+
+// goalSeeker evaluates the set cell for values of the changing cell.
+type goalSeeker struct {
+	set      Sheet
+	formula  string
+	changing Cell
+	target   float64
+	tol      float64
+	max      int
+	n        int
+	best     GoalSeekResult
+	haveBest bool
+}
+
+// eval sets the changing cell to x and returns the difference between the set
+// cell and the target value.
+func (g *goalSeeker) eval(x float64) (float64, error) {
+	g.n++
+	g.changing.SetNumber(x)
+	ev := formula.NewEvaluator()
+	res := ev.Eval(g.set.FormulaContext(), g.formula)
+	if res.Type != formula.ResultTypeNumber {
+		return 0, fmt.Errorf("set cell evaluated to %s", res.Value())
+	}
+	fx := res.ValueNumber - g.target
+	if math.IsNaN(fx) || math.IsInf(fx, 0) {
+		return 0, errors.New("set cell evaluated to a non-finite number")
+	}
+	if !g.haveBest || math.Abs(fx) < math.Abs(g.best.Result-g.target) {
+		g.best = GoalSeekResult{Value: x, Result: res.ValueNumber}
+		g.haveBest = true
+	}
+	return fx, nil
+}
+
+func (g *goalSeeker) converged(fx float64) bool { return math.Abs(fx) <= g.tol }
+
+// secant iterates the secant method from x0, switching to Brent's method as
+// soon as the root is bracketed.
+func (g *goalSeeker) secant(x0 float64) (float64, bool) {
+	f0, err := g.eval(x0)
+	if err != nil {
+		return 0, false
+	}
+	if g.converged(f0) {
+		return x0, true
+	}
+	step := math.Abs(x0) * 0.01
+	if step == 0 {
+		step = 0.01
+	}
+	x1 := x0 + step
+	for g.n < g.max {
+		f1, err := g.eval(x1)
+		if err != nil {
+			// back off towards the last good value
+			x1 = x0 + (x1-x0)/2
+			continue
+		}
+		if g.converged(f1) {
+			return x1, true
+		}
+		if math.Signbit(f0) != math.Signbit(f1) {
+			return g.brent(x0, x1, f0, f1)
+		}
+		var x2 float64
+		if f1 == f0 {
+			// flat, widen the search
+			x2 = x1 + 2*(x1-x0)
+		} else {
+			x2 = x1 - f1*(x1-x0)/(f1-f0)
+		}
+		if math.IsNaN(x2) || math.IsInf(x2, 0) {
+			return 0, false
+		}
+		x0, f0, x1 = x1, f1, x2
+	}
+	return 0, false
+}
+
+// brent finds the root bracketed by a and b using Brent's method.
+func (g *goalSeeker) brent(a, b, fa, fb float64) (float64, bool) {
+	if math.Abs(fa) < math.Abs(fb) {
+		a, b, fa, fb = b, a, fb, fa
+	}
+	c, fc, d := a, fa, a
+	bisected := true
+	for g.n < g.max {
+		eps := 1e-12 * math.Max(1, math.Abs(b))
+		if math.Abs(b-a) <= eps {
+			// the bracket collapsed without converging, e.g. on a discontinuity
+			return 0, false
+		}
+		var x float64
+		if fa != fc && fb != fc {
+			// inverse quadratic interpolation
+			x = a*fb*fc/((fa-fb)*(fa-fc)) + b*fa*fc/((fb-fa)*(fb-fc)) + c*fa*fb/((fc-fa)*(fc-fb))
+		} else {
+			x = b - fb*(b-a)/(fb-fa)
+		}
+		lo, hi := (3*a+b)/4, b
+		if lo > hi {
+			lo, hi = hi, lo
+		}
+		if x < lo || x > hi ||
+			(bisected && math.Abs(x-b) >= math.Abs(b-c)/2) ||
+			(!bisected && math.Abs(x-b) >= math.Abs(c-d)/2) ||
+			(bisected && math.Abs(b-c) < eps) ||
+			(!bisected && math.Abs(c-d) < eps) {
+			x = (a + b) / 2
+			bisected = true
+		} else {
+			bisected = false
+		}
+		fx, err := g.eval(x)
+		if err != nil {
+			return 0, false
+		}
+		if g.converged(fx) {
+			return x, true
+		}
+		d, c, fc = c, b, fb
+		if math.Signbit(fa) != math.Signbit(fx) {
+			b, fb = x, fx
+		} else {
+			a, fa = x, fx
+		}
+		if math.Abs(fa) < math.Abs(fb) {
+			a, b, fa, fb = b, a, fb, fa
+		}
+	}
+	return 0, false
+}
+
+// whatIfCell resolves a possibly sheet qualified cell reference such as
+// "'My Sheet'!$B$2", unqualified references refer to the first sheet.
+func (wb *Workbook) whatIfCell(ref string) (Sheet, string, error) {
+	a, ok := parseAuditArea(formula.Reference{Type: formula.ReferenceTypeCell, Value: ref}, "")
+	if !ok {
+		return Sheet{}, "", fmt.Errorf("invalid cell reference %q", ref)
+	}
+	if a.sheet == "" {
+		sheets := wb.AllSheets()
+		if len(sheets) == 0 {
+			return Sheet{}, "", ErrorNotFound
+		}
+		return sheets[0], a.ref, nil
+	}
+	s, err := wb.sheetByName(a.sheet)
+	if err != nil {
+		return Sheet{}, "", fmt.Errorf("sheet %s: %w", a.sheet, err)
+	}
+	return s, a.ref, nil
+}
+
+// GoalSeek searches for the value of changingCell that makes the formula in
+// setCell evaluate to targetValue, using the secant method and falling back
+// to Brent's method once the solution is bracketed. Cell references may be
+// qualified with a sheet name (e.g. "Sheet1!B5"), otherwise they refer to the
+// first sheet. On success the changing cell is left at the value found and
+// the workbook is recalculated. If the search fails the changing cell is
+// restored and ErrGoalSeekNotConverged is returned along with the closest
+// value found.
+func (wb *Workbook) GoalSeek(setCell string, targetValue float64, changingCell string, opts GoalSeekOptions) (GoalSeekResult, error) {
+	setSheet, setRef, err := wb.whatIfCell(setCell)
+	if err != nil {
+		return GoalSeekResult{}, err
+	}
+	changingSheet, changingRef, err := wb.whatIfCell(changingCell)
+	if err != nil {
+		return GoalSeekResult{}, err
+	}
+	f := setSheet.Cell(setRef).GetFormula()
+	if f == "" {
+		return GoalSeekResult{}, errors.New("set cell must contain a formula")
+	}
+	changing := changingSheet.Cell(changingRef)
+	if changing.HasFormula() {
+		return GoalSeekResult{}, errors.New("changing cell must contain a value, not a formula")
+	}
+	g := &goalSeeker{
+		set:      setSheet,
+		formula:  f,
+		changing: changing,
+		target:   targetValue,
+		tol:      opts.Tolerance,
+		max:      opts.MaxIterations,
+	}
+	if g.tol <= 0 {
+		g.tol = 0.001
+	}
+	if g.max <= 0 {
+		g.max = 100
+	}
+
+	orig := *changing.X()
+	x0, _ := changing.GetValueAsNumber()
+	x, ok := g.secant(x0)
+	if !ok {
+		*changing.X() = orig
+		g.best.Iterations = g.n
+		return g.best, ErrGoalSeekNotConverged
+	}
+	changing.SetNumber(x)
+	wb.RecalculateFormulas()
+	res := GoalSeekResult{Value: x, Result: targetValue, Iterations: g.n}
+	if v, err := setSheet.Cell(setRef).GetValueAsNumber(); err == nil {
+		res.Result = v
+	}
+	return res, nil
+}
+
+// AddDataTable adds a what-if data table whose results fill ref. With only
+// colInput set the input values are read from the column left of ref and
+// substituted into colInput to evaluate the formulas in the row above ref.
+// With only rowInput set the input values are read from the row above ref and
+// the formulas from the column left of it. With both set a two-variable table
+// is created, evaluating the formula in the cell above and left of ref. The
+// results are computed by RecalculateFormulas.
+func (s *Sheet) AddDataTable(ref, rowInput, colInput string) error {
+	from, to, err := reference.ParseRangeReference(ref)
+	if err != nil {
+		return fmt.Errorf("invalid data table range: %w", err)
+	}
+	if from.RowIdx <= 1 || from.ColumnIdx == 0 {
+		return errors.New("data table range must have a row above and a column left of it")
+	}
+	f := sml.NewCT_CellFormula()
+	f.TAttr = sml.ST_CellFormulaTypeDataTable
+	f.RefAttr = unioffice.String(from.Column + fmt.Sprint(from.RowIdx) + ":" + to.Column + fmt.Sprint(to.RowIdx))
+	switch {
+	case rowInput != "" && colInput != "":
+		f.Dt2DAttr = unioffice.Bool(true)
+		f.DtrAttr = unioffice.Bool(true)
+		f.R1Attr = unioffice.String(rowInput)
+		f.R2Attr = unioffice.String(colInput)
+	case rowInput != "":
+		f.DtrAttr = unioffice.Bool(true)
+		f.R1Attr = unioffice.String(rowInput)
+	case colInput != "":
+		f.R1Attr = unioffice.String(colInput)
+	default:
+		return errors.New("a data table requires a row or column input cell")
+	}
+	c := s.Cell(from.Column + fmt.Sprint(from.RowIdx))
+	c.Clear()
+	c.X().F = f
+	return nil
+}
+
+// dataTable is a data table formula and the cell it is stored in.
+type dataTable struct {
+	cell string
+	f    *sml.CT_CellFormula
+}
+
+// recalculateDataTables evaluates the data tables on the sheet. Their cells
+// are skipped by the regular evaluation as each result is computed by
+// substituting the table's input values into its input cells.
+func (s *Sheet) recalculateDataTables() {
+	tables := []dataTable{}
+	for _, r := range s.X().SheetData.Row {
+		for _, c := range r.C {
+			if c.F != nil && c.F.TAttr == sml.ST_CellFormulaTypeDataTable && c.RAttr != nil {
+				tables = append(tables, dataTable{*c.RAttr, c.F})
+			}
+		}
+	}
+	for _, t := range tables {
+		if err := s.recalculateDataTable(t); err != nil {
+			logger.Log.Debug("error evaluating data table %s: %s", t.cell, err)
+		}
+	}
+}
+
+func (s *Sheet) recalculateDataTable(t dataTable) error {
+	ref := t.cell
+	if t.f.RefAttr != nil {
+		ref = *t.f.RefAttr
+	}
+	from, to, err := reference.ParseRangeReference(ref)
+	if err != nil {
+		return err
+	}
+	if from.RowIdx <= 1 || from.ColumnIdx == 0 {
+		return errors.New("no room for the table inputs")
+	}
+	if t.f.R1Attr == nil || (t.f.Del1Attr != nil && *t.f.Del1Attr) {
+		return errors.New("missing input cell")
+	}
+	twoD := t.f.Dt2DAttr != nil && *t.f.Dt2DAttr
+	byRow := t.f.DtrAttr != nil && *t.f.DtrAttr
+	rowInput, colInput := *t.f.R1Attr, ""
+	if twoD {
+		if t.f.R2Attr == nil || (t.f.Del2Attr != nil && *t.f.Del2Attr) {
+			return errors.New("missing second input cell")
+		}
+		colInput = *t.f.R2Attr
+		if !byRow {
+			rowInput, colInput = colInput, rowInput
+		}
+	} else if !byRow {
+		rowInput, colInput = "", rowInput
+	}
+	rowInput = strings.ReplaceAll(rowInput, "$", "")
+	colInput = strings.ReplaceAll(colInput, "$", "")
+
+	cellRef := func(col, row uint32) string {
+		return reference.IndexToColumn(col) + fmt.Sprint(row)
+	}
+	inputRow, inputCol := from.RowIdx-1, from.ColumnIdx-1
+	for row := from.RowIdx; row <= to.RowIdx; row++ {
+		for col := from.ColumnIdx; col <= to.ColumnIdx; col++ {
+			var f string
+			switch {
+			case twoD:
+				f = cellRef(inputCol, inputRow)
+			case byRow:
+				f = cellRef(inputCol, row)
+			default:
+				f = cellRef(col, inputRow)
+			}
+			inputs := map[string]string{}
+			if rowInput != "" {
+				inputs[rowInput] = cellRef(col, inputRow)
+			}
+			if colInput != "" {
+				inputs[colInput] = cellRef(inputCol, row)
+			}
+			s.evaluateDataTableCell(s.Cell(cellRef(col, row)), s.Cell(f).GetFormula(), inputs)
+		}
+	}
+	return nil
+}
+
+// evaluateDataTableCell evaluates f with each input cell temporarily set to
+// the value of its source cell and stores the result in c.
+func (s *Sheet) evaluateDataTableCell(c Cell, f string, inputs map[string]string) {
+	saved := map[*sml.CT_Cell]sml.CT_Cell{}
+	for input, source := range inputs {
+		src := *s.Cell(source).X()
+		x := s.Cell(input).X()
+		if _, ok := saved[x]; !ok {
+			saved[x] = *x
+		}
+		x.F = nil
+		x.V, x.Is, x.TAttr = src.V, src.Is, src.TAttr
+	}
+	res := formula.NewEvaluator().Eval(s.FormulaContext(), f)
+	for x, v := range saved {
+		*x = v
+	}
+	if res.Type == formula.ResultTypeList || res.Type == formula.ResultTypeArray {
+		if l := res.ListValues(); len(l) > 0 {
+			res = l[0]
+		} else {
+			res = formula.MakeEmptyResult()
+		}
+	}
+	// the cells keep their data table formula, only the cached value changes
+	x := c.X()
+	x.Is = nil
+	switch res.Type {
+	case formula.ResultTypeError:
+		logger.Log.Debug("error evaluating data table formula %s: %s", f, res.ErrorMessage)
+		e := "#VALUE!"
+		if strings.HasPrefix(res.ValueString, "#") {
+			e = res.ValueString
+		}
+		x.TAttr, x.V = sml.ST_CellTypeE, unioffice.String(e)
+	case formula.ResultTypeNumber:
+		switch {
+		case res.IsBoolean:
+			x.TAttr = sml.ST_CellTypeB
+			x.V = unioffice.String(strconv.Itoa(int(res.ValueNumber)))
+		case math.IsNaN(res.ValueNumber) || math.IsInf(res.ValueNumber, 0):
+			x.TAttr, x.V = sml.ST_CellTypeE, unioffice.String("#NUM!")
+		default:
+			x.TAttr = sml.ST_CellTypeN
+			x.V = unioffice.String(strconv.FormatFloat(res.ValueNumber, 'f', -1, 64))
+		}
+	case formula.ResultTypeEmpty:
+		x.TAttr, x.V = sml.ST_CellTypeN, unioffice.String("0")
+	default:
+		x.TAttr, x.V = sml.ST_CellTypeStr, unioffice.String(res.ValueString)
+	}
+}
@@ -0,0 +1,153 @@
+package spreadsheet
+
+import (
+	"math"
+	"testing"
+
+	"github.com/yaklabco/unioffice/v2/schema/soo/sml"
+)
+
+func TestGoalSeek(t *testing.T) {
+	wb := New()
+	sheet := wb.AddSheet()
+	sheet.Cell("A1").SetNumber(1)
+	sheet.Cell("B1").SetFormulaRaw("A1*A1-2")
+	sheet.Cell("A2").SetNumber(0)
+	sheet.Cell("B2").SetFormulaRaw("A2*3+1")
+
+	res, err := wb.GoalSeek("'Sheet 1'!$B$1", 0, "A1", GoalSeekOptions{Tolerance: 1e-9})
+	if err != nil {
+		t.Fatalf("error seeking goal: %s", err)
+	}
+	if math.Abs(res.Value-math.Sqrt2) > 1e-6 {
+		t.Errorf("expected sqrt(2), got %f", res.Value)
+	}
+	if v, _ := sheet.Cell("A1").GetValueAsNumber(); v != res.Value {
+		t.Errorf("expected changing cell to be set to %f, got %f", res.Value, v)
+	}
+
+	res, err = wb.GoalSeek("B2", 10, "A2", GoalSeekOptions{})
+	if err != nil || math.Abs(res.Value-3) > 0.001 {
+		t.Errorf("expected 3, got %f (%v)", res.Value, err)
+	}
+	if v, _ := sheet.Cell("B2").GetValueAsNumber(); math.Abs(v-10) > 0.001 {
+		t.Errorf("expected set cell to be recalculated, got %f", v)
+	}
+
+	sheet.Cell("B3").SetFormulaRaw("A3*A3+1")
+	sheet.Cell("A3").SetNumber(5)
+	if _, err := wb.GoalSeek("B3", 0, "A3", GoalSeekOptions{MaxIterations: 20}); err != ErrGoalSeekNotConverged {
+		t.Errorf("expected no convergence, got %v", err)
+	}
+	if v, _ := sheet.Cell("A3").GetValueAsNumber(); v != 5 {
+		t.Errorf("expected changing cell to be restored, got %f", v)
+	}
+}
+
+func TestGoalSeekHiddenSheet(t *testing.T) {
+	wb := New()
+	wb.AddSheet()
+	model := wb.AddSheet()
+	model.SetName("Model")
+	model.Cell("A1").SetNumber(0)
+	model.Cell("B1").SetFormulaRaw("A1*2")
+	if err := model.SetState(sml.ST_SheetStateHidden); err != nil {
+		t.Fatalf("error hiding sheet: %s", err)
+	}
+	res, err := wb.GoalSeek("model!B1", 8, "Model!A1", GoalSeekOptions{})
+	if err != nil || math.Abs(res.Value-4) > 0.001 {
+		t.Errorf("expected 4, got %f (%v)", res.Value, err)
+	}
+}
+
+func TestDataTables(t *testing.T) {
+	wb := New()
+	sheet := wb.AddSheet()
+	sheet.Cell("E1").SetNumber(1)
+	sheet.Cell("E2").SetNumber(0)
+
+	// one variable, inputs down the left column
+	for i, v := range []float64{1, 2, 3} {
+		sheet.Cell("A" + string(rune('3'+i))).SetNumber(v)
+	}
+	sheet.Cell("B2").SetFormulaRaw("E1*10")
+	if err := sheet.AddDataTable("B3:B5", "", "$E$1"); err != nil {
+		t.Fatalf("error adding data table: %s", err)
+	}
+
+	// two variables
+	sheet.Cell("A7").SetFormulaRaw("E1+E2*100")
+	sheet.Cell("B7").SetNumber(1)
+	sheet.Cell("C7").SetNumber(2)
+	sheet.Cell("A8").SetNumber(3)
+	sheet.Cell("A9").SetNumber(4)
+	if err := sheet.AddDataTable("B8:C9", "E1", "E2"); err != nil {
+		t.Fatalf("error adding data table: %s", err)
+	}
+
+	// text results
+	sheet.Cell("F3").SetNumber(1)
+	sheet.Cell("F4").SetNumber(2)
+	sheet.Cell("G2").SetFormulaRaw(`IF(E1>1,"big","small")`)
+	if err := sheet.AddDataTable("G3:G4", "", "E1"); err != nil {
+		t.Fatalf("error adding data table: %s", err)
+	}
+
+	sheet.RecalculateFormulas()
+	exp := map[string]float64{"B3": 10, "B4": 20, "B5": 30, "B8": 301, "C8": 302, "B9": 401, "C9": 402, "B2": 10, "E1": 1, "E2": 0}
+	for ref, want := range exp {
+		if got, _ := sheet.Cell(ref).GetValueAsNumber(); got != want {
+			t.Errorf("expected %s = %f, got %f", ref, want, got)
+		}
+	}
+	if !sheet.Cell("B3").HasFormula() {
+		t.Error("expected data table formula to be kept")
+	}
+	for _, ref := range []string{"B3", "B4", "C9"} {
+		if x := sheet.Cell(ref).X(); x.TAttr != sml.ST_CellTypeN || x.Is != nil {
+			t.Errorf("expected %s to be stored as a number, got %s", ref, x.TAttr)
+		}
+	}
+	for ref, want := range map[string]string{"G3": "small", "G4": "big"} {
+		x := sheet.Cell(ref).X()
+		if x.TAttr != sml.ST_CellTypeStr || x.V == nil || *x.V != want {
+			t.Errorf("expected %s to be the string %q, got %s", ref, want, x.TAttr)
+		}
+	}
+}
+
+func TestDataTableErrors(t *testing.T) {
+	wb := New()
+	sheet := wb.AddSheet()
+	sheet.Cell("E1").SetNumber(1)
+
+	// the input makes the formula fail directly
+	sheet.Cell("A3").SetNumber(0)
+	sheet.Cell("A4").SetNumber(2)
+	sheet.Cell("B2").SetFormulaRaw("1/E1")
+	if err := sheet.AddDataTable("B3:B4", "", "E1"); err != nil {
+		t.Fatalf("error adding data table: %s", err)
+	}
+
+	// the input reaches the formula through another cell
+	sheet.Cell("E2").SetFormulaRaw("E1-1")
+	sheet.Cell("C3").SetNumber(1)
+	sheet.Cell("C4").SetNumber(3)
+	sheet.Cell("D2").SetFormulaRaw("4/E2")
+	if err := sheet.AddDataTable("D3:D4", "", "E1"); err != nil {
+		t.Fatalf("error adding data table: %s", err)
+	}
+
+	sheet.RecalculateFormulas()
+	for _, ref := range []string{"B3", "D3"} {
+		x := sheet.Cell(ref).X()
+		if x.TAttr != sml.ST_CellTypeE || x.V == nil || *x.V != "#DIV/0!" {
+			t.Errorf("expected %s to be the error #DIV/0!, got %s", ref, x.TAttr)
+		}
+	}
+	for ref, want := range map[string]float64{"B4": 0.5, "D4": 2} {
+		if got, _ := sheet.Cell(ref).GetValueAsNumber(); got != want {
+			t.Errorf("expected %s = %f, got %f", ref, want, got)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package vmldrawing
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/yaklabco/unioffice/v2"
+	"github.com/yaklabco/unioffice/v2/schema/soo/ofc/sharedTypes"
+	"github.com/yaklabco/unioffice/v2/schema/urn/schemas_microsoft_com/office/excel"
+	"github.com/yaklabco/unioffice/v2/schema/urn/schemas_microsoft_com/vml"
+)
+
+// FormControlShapeTypeID is the id of the shape type form control shapes
+// refer to.
+const FormControlShapeTypeID = "_x0000_t201"
+
+// NewFormControlShapeType constructs the shape type that form control shapes
+// refer to.
+func NewFormControlShapeType() *vml.Shapetype {
+	st := vml.NewShapetype()
+	st.IdAttr = unioffice.String(FormControlShapeTypeID)
+	st.CoordsizeAttr = unioffice.String("21600,21600")
+	st.SptAttr = unioffice.Float32(201)
+	st.PathAttr = unioffice.String("m,l,21600r21600,l21600,xe")
+	p := vml.NewPath()
+	p.ShadowokAttr = sharedTypes.ST_TrueFalseF
+	p.StrokeokAttr = sharedTypes.ST_TrueFalseF
+	p.FillokAttr = sharedTypes.ST_TrueFalseF
+	p.ExtrusionokAttr = sharedTypes.ST_TrueFalseF
+	p.ConnecttypeAttr = vml.OfcST_ConnectTypeRect
+	lock := vml.NewOfcLock()
+	lock.ExtAttr = vml.ST_ExtEdit
+	lock.ShapetypeAttr = sharedTypes.ST_TrueFalseT
+	st.EG_ShapeElements = append(st.EG_ShapeElements,
+		&vml.EG_ShapeElements{ShapeElementsChoice: &vml.EG_ShapeElementsChoice{Path: p}},
+		&vml.EG_ShapeElements{ShapeElementsChoice: &vml.EG_ShapeElementsChoice{Lock: lock}})
+	return st
+}
+
+// NewFormControlShape creates a new form control shape. The anchor is the
+// client anchor of the shape, a list of the zero based left column, left
+// offset, top row, top offset, right column, right offset, bottom row and
+// bottom offset where offsets are in pixels.
+func NewFormControlShape(id int64, objectType excel.ST_ObjectType, anchor string) *vml.Shape {
+	shape := vml.NewShape()
+	shape.IdAttr = unioffice.String(fmt.Sprintf("_x0000_s%d", id))
+	shape.TypeAttr = unioffice.String("#" + FormControlShapeTypeID)
+	shape.StyleAttr = unioffice.String(anchorStyle(anchor))
+	if objectType == excel.ST_ObjectTypeButton {
+		shape.FillcolorAttr = unioffice.String("buttonFace [67]")
+		shape.StrokecolorAttr = unioffice.String("windowText [64]")
+	} else {
+		shape.FilledAttr = sharedTypes.ST_TrueFalseF
+		shape.StrokedAttr = sharedTypes.ST_TrueFalseF
+		shape.StrokecolorAttr = unioffice.String("black [64]")
+	}
+	shape.InsetmodeAttr = vml.OfcST_InsetModeAuto
+
+	p := vml.NewPath()
+	p.ShadowokAttr = sharedTypes.ST_TrueFalseT
+	p.StrokeokAttr = sharedTypes.ST_TrueFalseT
+	p.FillokAttr = sharedTypes.ST_TrueFalseT
+	shape.ShapeChoice = append(shape.ShapeChoice, &vml.CT_ShapeChoice{ShapeElementsChoice: &vml.EG_ShapeElementsChoice{Path: p}})
+	lock := vml.NewOfcLock()
+	lock.ExtAttr = vml.ST_ExtEdit
+	lock.RotationAttr = sharedTypes.ST_TrueFalseT
+	shape.ShapeChoice = append(shape.ShapeChoice, &vml.CT_ShapeChoice{ShapeElementsChoice: &vml.EG_ShapeElementsChoice{Lock: lock}})
+
+	cd := excel.NewClientData()
+	cd.ObjectTypeAttr = objectType
+	cd.ClientDataChoice = []*excel.CT_ClientDataChoice{
+		{Anchor: unioffice.String(anchor)},
+		{PrintObject: sharedTypes.ST_TrueFalseBlankFalse},
+		{AutoFill: sharedTypes.ST_TrueFalseBlankFalse},
+	}
+	shape.ShapeChoice = append(shape.ShapeChoice, &vml.CT_ShapeChoice{ShapeElementsChoice: &vml.EG_ShapeElementsChoice{ClientData: cd}})
+	return shape
+}
+
+// anchorStyle returns an approximate shape style for a client anchor assuming
+// default column widths and row heights, applications position the shape by
+// the anchor.
+func anchorStyle(anchor string) string {
+	v := [8]float64{}
+	for i, f := range strings.Split(anchor, ",") {
+		if i < len(v) {
+			v[i], _ = strconv.ParseFloat(strings.TrimSpace(f), 64)
+		}
+	}
+	left := v[0]*48 + v[1]*0.75
+	top := v[2]*15 + v[3]*0.75
+	width := v[4]*48 + v[5]*0.75 - left
+	height := v[6]*15 + v[7]*0.75 - top
+	return fmt.Sprintf("position:absolute;margin-left:%gpt;margin-top:%gpt;width:%gpt;height:%gpt;z-index:1",
+		left, top, width, height)
+}
+
+// ShapeClientData returns the client data of a shape, or nil if it has none.
+func ShapeClientData(shape *vml.Shape) *excel.ClientData {
+	for _, c := range shape.ShapeChoice {
+		if c.ShapeElementsChoice != nil && c.ShapeElementsChoice.ClientData != nil {
+			return c.ShapeElementsChoice.ClientData
+		}
+	}
+	return nil
+}
+
+func shapeTextbox(shape *vml.Shape) *vml.Textbox {
+	for _, c := range shape.ShapeChoice {
+		if c.ShapeElementsChoice != nil && c.ShapeElementsChoice.Textbox != nil {
+			return c.ShapeElementsChoice.Textbox
+		}
+	}
+	return nil
+}
+
+// ShapeText returns the text of the text box of a shape, e.g. the label of a
+// button.
+func ShapeText(shape *vml.Shape) string {
+	tb := shapeTextbox(shape)
+	if tb == nil {
+		return ""
+	}
+	n, ok := tb.Any.(*unioffice.XSDAny)
+	if !ok {
+		return ""
+	}
+	return anyText(n)
+}
+
+func anyText(n *unioffice.XSDAny) string {
+	s := string(n.Data)
+	for _, c := range n.Nodes {
+		if c.XMLName.Local == "br" {
+			s += "\n"
+			continue
+		}
+		s += anyText(c)
+	}
+	return s
+}
+
+// SetShapeText sets the text of the text box of a shape, adding the text box
+// if needed.
+func SetShapeText(shape *vml.Shape, text string) {
+	tb := shapeTextbox(shape)
+	if tb == nil {
+		tb = vml.NewTextbox()
+		tb.StyleAttr = unioffice.String("mso-direction-alt:auto")
+		tb.SingleclickAttr = sharedTypes.ST_TrueFalseF
+		// the text box precedes the client data
+		choice := &vml.CT_ShapeChoice{ShapeElementsChoice: &vml.EG_ShapeElementsChoice{Textbox: tb}}
+		i := len(shape.ShapeChoice)
+		for j, c := range shape.ShapeChoice {
+			if c.ShapeElementsChoice != nil && c.ShapeElementsChoice.ClientData != nil {
+				i = j
+				break
+			}
+		}
+		shape.ShapeChoice = append(shape.ShapeChoice[:i], append([]*vml.CT_ShapeChoice{choice}, shape.ShapeChoice[i:]...)...)
+	}
+	font := &unioffice.XSDAny{
+		XMLName: xml.Name{Local: "font"},
+		Attrs: []xml.Attr{
+			{Name: xml.Name{Local: "face"}, Value: "Segoe UI"},
+			{Name: xml.Name{Local: "size"}, Value: "160"},
+			{Name: xml.Name{Local: "color"}, Value: "auto"},
+		},
+		Data: []byte(text),
+	}
+	tb.Any = &unioffice.XSDAny{
+		XMLName: xml.Name{Local: "div"},
+		Attrs:   []xml.Attr{{Name: xml.Name{Local: "style"}, Value: "text-align:left"}},
+		Nodes:   []*unioffice.XSDAny{font},
+	}
+}
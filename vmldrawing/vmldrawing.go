@@ -49,7 +49,7 @@ func (_eb *ShapeStyle )Margins ()(float64 ,float64 ,float64 ,float64 ){return _e
 
 // SetItalic sets text to italic.
 func (_fge *TextpathStyle )SetItalic (italic bool ){_fge ._daf =italic };func (_g *Container )UnmarshalXML (d *_fg .Decoder ,start _fg .StartElement )error {_g .Shape =nil ;_eg :for {_fba ,_cdb :=d .Token ();if _cdb !=nil {return _cdb ;};switch _de :=_fba .(type ){case _fg .StartElement :switch _de .Name .Local {case "s\u0068\u0061\u0070\u0065\u006c\u0061\u0079\u006f\u0075\u0074":_g .Layout =_da .NewOfcShapelayout ();
-if _ad :=d .DecodeElement (_g .Layout ,&_de );_ad !=nil {return _ad ;};case "\u0073h\u0061\u0070\u0065\u0074\u0079\u0070e":_g .ShapeType =_da .NewShapetype ();if _fgd :=d .DecodeElement (_g .ShapeType ,&_de );_fgd !=nil {return _fgd ;};case "\u0073\u0068\u0061p\u0065":_ff :=_da .NewShape ();
+if _ad :=d .DecodeElement (_g .Layout ,&_de );_ad !=nil {return _ad ;};case "\u0073h\u0061\u0070\u0065\u0074\u0079\u0070e":_cgg :=_da .NewShapetype ();if _fgd :=d .DecodeElement (_cgg ,&_de );_fgd !=nil {return _fgd ;};if _g .ShapeType ==nil {_g .ShapeType =_cgg ;}else {_g .ShapeTypes =append (_g .ShapeTypes ,_cgg );};case "\u0073\u0068\u0061p\u0065":_ff :=_da .NewShape ();
 if _fe :=d .DecodeElement (_ff ,&_de );_fe !=nil {return _fe ;};_g .Shape =append (_g .Shape ,_ff );};case _fg .EndElement :break _eg ;};};return nil ;};
 
 // MSOPositionHorizontalRelative get `mso-position-horizontal-relative` attribute of shape style.
@@ -113,7 +113,11 @@ func (_cb *TextpathStyle )SetBold (bold bool ){_cb ._cdd =bold };
 func (_cf *ShapeStyle )SetWidth (width float64 ){_cf ._fff =width };
 
 // Width return width of shape.
-func (_age *ShapeStyle )Width ()float64 {return _age ._fff };type Container struct{Layout *_da .OfcShapelayout ;ShapeType *_da .Shapetype ;Shape []*_da .Shape ;};
+func (_age *ShapeStyle )Width ()float64 {return _age ._fff };type Container struct{Layout *_da .OfcShapelayout ;ShapeType *_da .Shapetype ;
+
+// ShapeTypes are the shape types following the first one, e.g. the form
+// control shape type of a drawing that also holds comments.
+ShapeTypes []*_da .Shapetype ;Shape []*_da .Shape ;};
 
 // SetFontSize sets text's fontSize.
 func (_dae *TextpathStyle )SetFontSize (fontSize int64 ){_dae ._ab =fontSize };
@@ -129,7 +133,7 @@ break ;};};return _affd ;};func (_bb *Container )MarshalXML (e *_fg .Encoder ,st
 start .Attr =append (start .Attr ,_fg .Attr {Name :_fg .Name {Local :"\u0078m\u006c\u006e\u0073\u003a\u006f"},Value :"\u0075\u0072\u006e\u003a\u0073\u0063\u0068\u0065\u006d\u0061\u0073\u002d\u006di\u0063\u0072\u006f\u0073\u006f\u0066t\u002d\u0063\u006f\u006d\u003a\u006f\u0066\u0066\u0069\u0063\u0065\u003a\u006ff\u0066\u0069\u0063\u0065"});
 start .Attr =append (start .Attr ,_fg .Attr {Name :_fg .Name {Local :"\u0078m\u006c\u006e\u0073\u003a\u0078"},Value :"\u0075\u0072\u006e\u003a\u0073\u0063\u0068\u0065\u006d\u0061\u0073\u002d\u006d\u0069\u0063\u0072\u006f\u0073\u006f\u0066\u0074\u002d\u0063\u006fm\u003a\u006f\u0066\u0066\u0069c\u0065\u003ae\u0078\u0063\u0065\u006c"});
 start .Name .Local ="\u0078\u006d\u006c";e .EncodeToken (start );if _bb .Layout !=nil {_fbf :=_fg .StartElement {Name :_fg .Name {Local :"\u006f\u003a\u0073\u0068\u0061\u0070\u0065\u006c\u0061\u0079\u006f\u0075\u0074"}};e .EncodeElement (_bb .Layout ,_fbf );
-};if _bb .ShapeType !=nil {_ce :=_fg .StartElement {Name :_fg .Name {Local :"v\u003a\u0073\u0068\u0061\u0070\u0065\u0074\u0079\u0070\u0065"}};e .EncodeElement (_bb .ShapeType ,_ce );};for _ ,_dcc :=range _bb .Shape {_bd :=_fg .StartElement {Name :_fg .Name {Local :"\u0076:\u0073\u0068\u0061\u0070\u0065"}};
+};if _bb .ShapeType !=nil {_ce :=_fg .StartElement {Name :_fg .Name {Local :"v\u003a\u0073\u0068\u0061\u0070\u0065\u0074\u0079\u0070\u0065"}};e .EncodeElement (_bb .ShapeType ,_ce );};for _ ,_bcd :=range _bb .ShapeTypes {_ce :=_fg .StartElement {Name :_fg .Name {Local :"v\u003a\u0073\u0068\u0061\u0070\u0065\u0074\u0079\u0070\u0065"}};e .EncodeElement (_bcd ,_ce );};for _ ,_dcc :=range _bb .Shape {_bd :=_fg .StartElement {Name :_fg .Name {Local :"\u0076:\u0073\u0068\u0061\u0070\u0065"}};
 e .EncodeElement (_dcc ,_bd );};return e .EncodeToken (_fg .EndElement {Name :start .Name });};
 
 // Right get right attribute of shape style.